@@ -0,0 +1,136 @@
+// Package aggregator exposes the price aggregation engine of the price
+// feeder, i.e. the providers and the price calculation of the oracle, for
+// programs embedding it without the voting, the server and the CLI.
+//
+// The module path is price-feeder, which can't be fetched with go get, so
+// the package is only importable by programs inside this repository or by
+// modules replacing price-feeder with a local checkout.
+//
+// A minimal program running the aggregation looks like:
+//
+//	cfg, err := config.ParseConfig("price-feeder.toml")
+//	...
+//	agg, err := aggregator.New(logger, cfg)
+//	...
+//	agg.Events().Subscribe(func(event events.Event) {
+//		prices, ok := event.Data.(map[string]sdk.Dec)
+//		...
+//	}, events.TopicPricesUpdated)
+//
+//	err = agg.Start(ctx, 5*time.Second)
+//
+// Only the config sections of the aggregation are used. The sections of the
+// account, the keyring and the RPC endpoints are validated by
+// config.ParseConfig, but not used.
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+
+	"price-feeder/config"
+	"price-feeder/oracle"
+	"price-feeder/oracle/client"
+	"price-feeder/oracle/types"
+	"price-feeder/pkg/events"
+)
+
+var _ Reader = (*Aggregator)(nil)
+
+type (
+	// Reader defines the read-only view of the aggregated prices. It is
+	// implemented by the Aggregator and by the oracle of the price feeder,
+	// so consumers can be written against either of them.
+	Reader interface {
+		// GetPrices returns the prices of the last calculation by denom.
+		GetPrices() sdk.DecCoins
+		// GetLastPricesTimestamp returns the time of the last calculation.
+		GetLastPricesTimestamp() time.Time
+		// GetProviderStatus returns the health of all running providers.
+		GetProviderStatus() []types.ProviderStatus
+		// Events returns the bus the oracle events are published on, e.g.
+		// events.TopicPricesUpdated after every calculation.
+		Events() *events.Bus
+	}
+
+	// Aggregator runs the configured providers and periodically computes
+	// the prices from their tickers and candles.
+	Aggregator struct {
+		logger zerolog.Logger
+		oracle *oracle.Oracle
+	}
+)
+
+// New returns an aggregator for the currency pairs, providers and price
+// calculation settings of the config. The providers are started with the
+// first price calculation.
+func New(logger zerolog.Logger, cfg config.Config) (*Aggregator, error) {
+	o, _, err := NewOracle(logger, cfg, client.OracleClient{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Aggregator{
+		logger: logger.With().Str("module", "aggregator").Logger(),
+		oracle: o,
+	}, nil
+}
+
+// Start computes the prices every interval until the context is canceled.
+// Failed calculations are logged and retried at the next interval.
+func (a *Aggregator) Start(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval: %s", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.Refresh(ctx); err != nil {
+			a.logger.Err(err).Msg("failed to compute prices")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh computes the prices once from the current provider data.
+func (a *Aggregator) Refresh(ctx context.Context) error {
+	return a.oracle.SetPrices(ctx)
+}
+
+// GetPrices returns the prices of the last calculation by denom.
+func (a *Aggregator) GetPrices() sdk.DecCoins {
+	return a.oracle.GetPrices()
+}
+
+// GetLastPricesTimestamp returns the time of the last calculation.
+func (a *Aggregator) GetLastPricesTimestamp() time.Time {
+	return a.oracle.GetLastPricesTimestamp()
+}
+
+// GetProviderStatus returns the health of all running providers.
+func (a *Aggregator) GetProviderStatus() []types.ProviderStatus {
+	return a.oracle.GetProviderStatus()
+}
+
+// Events returns the bus the oracle events are published on.
+func (a *Aggregator) Events() *events.Bus {
+	return a.oracle.Events()
+}
+
+// Oracle returns the underlying oracle for access beyond the Reader, e.g.
+// the intermediate conversions. The voting methods require an oracle client
+// and must not be used.
+func (a *Aggregator) Oracle() *oracle.Oracle {
+	return a.oracle
+}
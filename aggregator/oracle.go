@@ -0,0 +1,248 @@
+package aggregator
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+
+	"price-feeder/config"
+	"price-feeder/oracle"
+	"price-feeder/oracle/client"
+	"price-feeder/oracle/derivative"
+	"price-feeder/oracle/history"
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+	"price-feeder/oracle/votelog"
+)
+
+// NewOracle returns the oracle configured by the config, together with the
+// price history it records to. The oracle client is only used for voting,
+// so a zero client is sufficient as long as the oracle is not started.
+func NewOracle(
+	logger zerolog.Logger,
+	cfg config.Config,
+	oracleClient client.OracleClient,
+) (*oracle.Oracle, *history.PriceHistory, error) {
+	providerTimeout, err := time.ParseDuration(cfg.ProviderTimeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse provider timeout: %w", err)
+	}
+
+	deviations := make(map[string]sdk.Dec, len(cfg.Deviations))
+	for _, deviation := range cfg.Deviations {
+		threshold, err := sdk.NewDecFromStr(deviation.Threshold)
+		if err != nil {
+			return nil, nil, err
+		}
+		deviations[deviation.Base] = threshold
+	}
+
+	providerMinOverrides := make(map[string]int, len(cfg.ProviderMinOverrides))
+	for _, override := range cfg.ProviderMinOverrides {
+		for _, denom := range override.Denoms {
+			_, found := providerMinOverrides[denom]
+			if found {
+				logger.Warn().
+					Str("denom", denom).
+					Msg("provider_min_overrides already set")
+			}
+			providerMinOverrides[denom] = int(override.Providers)
+		}
+	}
+
+	endpoints := make(map[provider.Name]provider.Endpoint, len(cfg.ProviderEndpoints))
+	for _, e := range cfg.ProviderEndpoints {
+		endpoint, err := e.ToEndpoint(cfg.UrlSets)
+		if err != nil {
+			return nil, nil, err
+		}
+		endpoints[endpoint.Name] = endpoint
+	}
+
+	priceHistory, err := history.NewPriceHistory(cfg.HistoryDb, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to init price history db: %v", err)
+	}
+
+	derivativePairs := map[string][]types.CurrencyPair{}
+	derivativePeriods := map[string]map[string]time.Duration{}
+	derivativeSymbols := map[string]struct{}{}
+	providerPairs := []config.CurrencyPair{}
+	for _, pair := range cfg.CurrencyPairs {
+		if pair.Derivative != "" {
+			period, err := time.ParseDuration(pair.DerivativePeriod)
+			if err != nil {
+				return nil, nil, err
+			}
+			pairs, ok := derivativePairs[pair.Derivative]
+			if !ok {
+				pairs = []types.CurrencyPair{}
+				derivativePeriods[pair.Derivative] = map[string]time.Duration{}
+			}
+			currencyPair := types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}
+			derivativePairs[pair.Derivative] = append(pairs, currencyPair)
+			derivativePeriods[pair.Derivative][currencyPair.String()] = period
+			derivativeSymbols[pair.Base+pair.Quote] = struct{}{}
+		}
+		providerPairs = append(providerPairs, pair)
+	}
+
+	derivatives := map[string]derivative.Derivative{}
+	for name, pairs := range derivativePairs {
+		d, err := derivative.NewDerivative(name, logger, &priceHistory, pairs, derivativePeriods[name])
+		if err != nil {
+			return nil, nil, err
+		}
+		derivatives[name] = d
+	}
+
+	providerWeights := map[string]oracle.ProviderWeight{}
+	for denom, weights := range cfg.ProviderWeights {
+		newWeight := oracle.ProviderWeight{
+			Type:   "simple",
+			Weight: map[string]sdk.Dec{},
+		}
+
+		for providerName, value := range weights {
+			if value < 0 {
+				return nil, nil, fmt.Errorf("override must be >= 0")
+			}
+
+			value, err := sdk.NewDecFromStr(fmt.Sprintf("%f", value))
+			if err != nil {
+				return nil, nil, err
+			}
+			newWeight.Weight[providerName] = value
+		}
+
+		providerWeights[denom] = newWeight
+	}
+
+	blacklistDeviation, err := sdk.NewDecFromStr(cfg.Blacklist.MaxDeviation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blacklistDuration, err := time.ParseDuration(cfg.Blacklist.Duration)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	delistAfter, err := time.ParseDuration(cfg.Blacklist.DelistAfter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blacklistProviders := map[provider.Name][]string{}
+	for name, symbols := range cfg.Blacklist.Providers {
+		for _, symbol := range symbols {
+			blacklistProviders[provider.Name(name)] = append(
+				blacklistProviders[provider.Name(name)],
+				strings.ToUpper(symbol),
+			)
+		}
+	}
+
+	blacklist := oracle.NewBlacklist(
+		blacklistProviders,
+		blacklistDeviation,
+		cfg.Blacklist.Strikes,
+		blacklistDuration,
+	)
+	blacklist.SetDelistAfter(delistAfter)
+
+	var voteLog *votelog.VoteLog
+	if cfg.VoteLog.Dir != "" {
+		voteLog, err = votelog.NewVoteLog(cfg.VoteLog.Dir, cfg.VoteLog.Format)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	volumeDatabase, err := sql.Open("sqlite3", cfg.HistoryDb)
+	if err != nil {
+		logger.Err(err).
+			Str("path", cfg.HistoryDb).
+			Msg("failed to open sqlite db")
+	}
+	volumeDatabase.SetMaxOpenConns(1)
+
+	o := oracle.New(
+		logger,
+		oracleClient,
+		providerPairs,
+		providerTimeout,
+		deviations,
+		providerMinOverrides,
+		endpoints,
+		derivatives,
+		derivativePairs,
+		derivativeSymbols,
+		cfg.Healthchecks,
+		priceHistory,
+		cfg.ContractAdresses,
+		providerWeights,
+		cfg.Decimals,
+		cfg.Periods,
+		volumeDatabase,
+	)
+	o.SetComparison(cfg.Comparison)
+	o.SetBlacklist(blacklist)
+	o.SetVoteLog(voteLog)
+	o.SetPriceExponents(cfg.PriceExponents)
+	o.SetTiming(cfg.Timing)
+	o.SetAutoThresholds(oracle.NewAutoThresholds(logger, cfg.AutoThresholds))
+	o.SetBatchVotes(cfg.BatchVotes)
+	o.SetStateFile(cfg.StateFile)
+	o.SetWhitelist(cfg.Whitelist)
+
+	if err := o.SetLiquidityWeighting(cfg.LiquidityWeighting); err != nil {
+		return nil, nil, err
+	}
+
+	if cfg.PriceBounds != "" {
+		bounds, err := oracle.LoadPriceBounds(cfg.PriceBounds)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := o.SetPriceBounds(bounds); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(cfg.SanityRules) > 0 {
+		rules, err := oracle.ParseSanityRules(cfg.SanityRules)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := o.SetSanityRules(rules); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	chainProfile, err := oracle.GetChainProfile(cfg.ChainProfile)
+	if err != nil {
+		return nil, nil, err
+	}
+	o.SetChainProfile(chainProfile)
+
+	ratesFormat, err := oracle.NewRatesFormat(chainProfile.RatesFormat(), cfg.RatesFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+	o.SetRatesFormat(ratesFormat)
+
+	if cfg.TickerHistory.Enabled {
+		retention, err := time.ParseDuration(cfg.TickerHistory.Retention)
+		if err != nil {
+			return nil, nil, err
+		}
+		o.SetTickerRetention(retention)
+	}
+
+	return o, &priceHistory, nil
+}
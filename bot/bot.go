@@ -0,0 +1,141 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/oracle/types"
+)
+
+// Oracle defines the Oracle interface contract that the bots depend on.
+type Oracle interface {
+	GetLastPriceSyncTimestamp() time.Time
+	GetPrices() sdk.DecCoins
+	GetProviderStatus() []types.ProviderStatus
+	GetMissCounter(ctx context.Context) (uint64, error)
+	GetBalance(ctx context.Context) (sdk.Coins, error)
+}
+
+// Commands answers the status commands sent to the bots, using the same data
+// as the REST API.
+type Commands struct {
+	oracle Oracle
+}
+
+func NewCommands(oracle Oracle) *Commands {
+	return &Commands{oracle: oracle}
+}
+
+// Handle returns the answer to the command in text. It returns false if
+// text is not a command.
+func (c *Commands) Handle(ctx context.Context, text string) (string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", false
+	}
+
+	// telegram appends the bot name in group chats, e.g. /status@feeder_bot
+	command := strings.SplitN(strings.TrimPrefix(fields[0], "/"), "@", 2)[0]
+
+	switch strings.ToLower(command) {
+	case "status":
+		return c.status(), true
+	case "prices":
+		return c.prices(fields[1:]), true
+	case "misses":
+		return c.misses(ctx), true
+	case "balance":
+		return c.balance(ctx), true
+	case "help", "start":
+		return "Commands: /status, /prices [denom...], /misses, /balance", true
+	default:
+		return "", false
+	}
+}
+
+func (c *Commands) status() string {
+	lastSync := c.oracle.GetLastPriceSyncTimestamp()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Last sync: %s (%s ago)\n",
+		lastSync.UTC().Format(time.RFC3339),
+		time.Since(lastSync).Truncate(time.Second),
+	)
+
+	unhealthy := []string{}
+	providers := c.oracle.GetProviderStatus()
+	for _, provider := range providers {
+		if !provider.Healthy {
+			unhealthy = append(unhealthy, provider.Name)
+		}
+	}
+
+	fmt.Fprintf(&sb, "Providers: %d/%d healthy",
+		len(providers)-len(unhealthy), len(providers),
+	)
+	if len(unhealthy) > 0 {
+		fmt.Fprintf(&sb, "\nUnhealthy: %s", strings.Join(unhealthy, ", "))
+	}
+
+	return sb.String()
+}
+
+func (c *Commands) prices(denoms []string) string {
+	filter := make(map[string]struct{}, len(denoms))
+	for _, denom := range denoms {
+		filter[strings.ToUpper(denom)] = struct{}{}
+	}
+
+	prices := c.oracle.GetPrices()
+	prices.Sort()
+
+	lines := []string{}
+	for _, price := range prices {
+		if _, found := filter[price.Denom]; len(filter) > 0 && !found {
+			continue
+		}
+		lines = append(lines, price.Denom+": "+price.Amount.String())
+	}
+
+	if len(lines) == 0 {
+		return "No prices available"
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (c *Commands) misses(ctx context.Context) string {
+	misses, err := c.oracle.GetMissCounter(ctx)
+	if err != nil {
+		return "Failed to get miss counter: " + err.Error()
+	}
+
+	return fmt.Sprintf("Miss counter: %d", misses)
+}
+
+func (c *Commands) balance(ctx context.Context) string {
+	balance, err := c.oracle.GetBalance(ctx)
+	if err != nil {
+		return "Failed to get balance: " + err.Error()
+	}
+
+	if balance.Empty() {
+		return "Balance: 0"
+	}
+
+	return "Balance: " + balance.String()
+}
+
+// truncate limits the message to the maximum length allowed by the chat
+// service.
+func truncate(message string, length int) string {
+	if len(message) <= length {
+		return message
+	}
+
+	return message[:length-3] + "..."
+}
@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/oracle/types"
+)
+
+type mockOracle struct{}
+
+func (mockOracle) GetLastPriceSyncTimestamp() time.Time {
+	return time.Now()
+}
+
+func (mockOracle) GetPrices() sdk.DecCoins {
+	return sdk.NewDecCoins(
+		sdk.NewDecCoinFromDec("ATOM", sdk.MustNewDecFromStr("34.84")),
+		sdk.NewDecCoinFromDec("KUJI", sdk.MustNewDecFromStr("0.75")),
+	)
+}
+
+func (mockOracle) GetProviderStatus() []types.ProviderStatus {
+	return []types.ProviderStatus{
+		{Name: "binance", Healthy: true},
+		{Name: "kraken", Healthy: false},
+	}
+}
+
+func (mockOracle) GetMissCounter(context.Context) (uint64, error) {
+	return 12, nil
+}
+
+func (mockOracle) GetBalance(context.Context) (sdk.Coins, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestCommands(t *testing.T) {
+	commands := NewCommands(mockOracle{})
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		text     string
+		ok       bool
+		contains string
+	}{
+		"no command":   {text: "hello", ok: false},
+		"unknown":      {text: "/foo", ok: false},
+		"status":       {text: "/status", ok: true, contains: "Providers: 1/2 healthy\nUnhealthy: kraken"},
+		"bot name":     {text: "/status@feeder_bot", ok: true, contains: "Providers: 1/2"},
+		"prices":       {text: "/prices", ok: true, contains: "ATOM: 34.840000000000000000\nKUJI: 0.750000000000000000"},
+		"prices denom": {text: "/prices kuji", ok: true, contains: "KUJI: 0.750000000000000000"},
+		"misses":       {text: "/misses", ok: true, contains: "Miss counter: 12"},
+		"balance":      {text: "/balance", ok: true, contains: "Failed to get balance: connection refused"},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			answer, ok := commands.Handle(ctx, tc.text)
+			require.Equal(t, tc.ok, ok)
+			require.Contains(t, answer, tc.contains)
+		})
+	}
+
+	answer, _ := commands.Handle(ctx, "/prices kuji")
+	require.NotContains(t, answer, "ATOM")
+}
+
+func TestTruncate(t *testing.T) {
+	require.Equal(t, "abc", truncate("abc", 5))
+	require.Equal(t, "ab...", truncate("abcdefgh", 5))
+}
+
+func TestTelegramErrorRedactsToken(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	token := "123456:secret-token"
+	bot := NewTelegram(zerolog.Nop(), token, nil, nil)
+	bot.baseURL = server.URL
+
+	_, err := bot.getUpdates(context.Background(), 0)
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), token)
+
+	err = bot.sendMessage(context.Background(), 1, "test")
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), token)
+}
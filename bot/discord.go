@@ -0,0 +1,304 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+)
+
+const (
+	discordBaseURL   = "https://discord.com/api/v10"
+	discordMaxLength = 2000
+
+	discordOpDispatch       = 0
+	discordOpHeartbeat      = 1
+	discordOpIdentify       = 2
+	discordOpReconnect      = 7
+	discordOpInvalidSession = 9
+	discordOpHello          = 10
+
+	// GUILD_MESSAGES | DIRECT_MESSAGES | MESSAGE_CONTENT
+	discordIntents = 1<<9 | 1<<12 | 1<<15
+)
+
+type (
+	// Discord answers commands sent to the configured channels, using the
+	// discord gateway, so the price feeder doesn't need to be reachable from
+	// the internet.
+	Discord struct {
+		logger   zerolog.Logger
+		baseURL  string
+		token    string
+		channels map[string]struct{}
+		commands *Commands
+		client   *http.Client
+	}
+
+	discordPayload struct {
+		Op       int             `json:"op"`
+		Data     json.RawMessage `json:"d,omitempty"`
+		Sequence *int64          `json:"s,omitempty"`
+		Type     string          `json:"t,omitempty"`
+	}
+
+	discordMessage struct {
+		ChannelID string `json:"channel_id"`
+		Content   string `json:"content"`
+		Author    struct {
+			Bot bool `json:"bot"`
+		} `json:"author"`
+	}
+)
+
+// NewDiscord returns a discord bot answering commands of the provided
+// channels only.
+func NewDiscord(
+	logger zerolog.Logger,
+	token string,
+	channelIDs []string,
+	commands *Commands,
+) *Discord {
+	channels := make(map[string]struct{}, len(channelIDs))
+	for _, id := range channelIDs {
+		channels[id] = struct{}{}
+	}
+
+	return &Discord{
+		logger:   logger.With().Str("bot", "discord").Logger(),
+		baseURL:  discordBaseURL,
+		token:    token,
+		channels: channels,
+		commands: commands,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Start connects to the discord gateway and reconnects on errors until the
+// context is cancelled.
+func (d *Discord) Start(ctx context.Context) error {
+	d.logger.Info().Msg("starting discord bot")
+
+	for {
+		err := d.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		d.logger.Warn().Err(err).Msg("discord gateway disconnected, reconnecting")
+		time.Sleep(botRetryDelay)
+	}
+}
+
+func (d *Discord) run(ctx context.Context) error {
+	gateway, err := d.gatewayURL(ctx)
+	if err != nil {
+		return err
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(
+		ctx, gateway+"?v=10&encoding=json", nil,
+	)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// unblock ReadJSON when the context is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	var hello discordPayload
+	if err := conn.ReadJSON(&hello); err != nil {
+		return err
+	}
+	if hello.Op != discordOpHello {
+		return fmt.Errorf("expected hello, got op %d", hello.Op)
+	}
+
+	var helloData struct {
+		HeartbeatInterval int64 `json:"heartbeat_interval"`
+	}
+	if err := json.Unmarshal(hello.Data, &helloData); err != nil {
+		return err
+	}
+
+	var (
+		writeMtx sync.Mutex
+		seqMtx   sync.Mutex
+		sequence *int64
+	)
+
+	write := func(op int, data interface{}) error {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+
+		writeMtx.Lock()
+		defer writeMtx.Unlock()
+		return conn.WriteJSON(discordPayload{Op: op, Data: raw})
+	}
+
+	err = write(discordOpIdentify, map[string]interface{}{
+		"token":   d.token,
+		"intents": discordIntents,
+		"properties": map[string]string{
+			"os":      "linux",
+			"browser": "price-feeder",
+			"device":  "price-feeder",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(helloData.HeartbeatInterval) * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				seqMtx.Lock()
+				seq := sequence
+				seqMtx.Unlock()
+
+				if err := write(discordOpHeartbeat, seq); err != nil {
+					d.logger.Warn().Err(err).Msg("failed to send heartbeat")
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		var payload discordPayload
+		if err := conn.ReadJSON(&payload); err != nil {
+			return err
+		}
+
+		if payload.Sequence != nil {
+			seqMtx.Lock()
+			sequence = payload.Sequence
+			seqMtx.Unlock()
+		}
+
+		switch payload.Op {
+		case discordOpReconnect, discordOpInvalidSession:
+			return fmt.Errorf("gateway requested reconnect (op %d)", payload.Op)
+
+		case discordOpDispatch:
+			if payload.Type != "MESSAGE_CREATE" {
+				continue
+			}
+
+			var message discordMessage
+			if err := json.Unmarshal(payload.Data, &message); err != nil {
+				d.logger.Warn().Err(err).Msg("failed to parse message")
+				continue
+			}
+
+			go d.handle(ctx, message)
+		}
+	}
+}
+
+func (d *Discord) handle(ctx context.Context, message discordMessage) {
+	if message.Author.Bot {
+		return
+	}
+
+	if _, found := d.channels[message.ChannelID]; !found {
+		return
+	}
+
+	answer, ok := d.commands.Handle(ctx, message.Content)
+	if !ok {
+		return
+	}
+
+	err := d.sendMessage(ctx, message.ChannelID, truncate(answer, discordMaxLength))
+	if err != nil {
+		d.logger.Warn().Err(err).Msg("failed to send discord message")
+	}
+}
+
+func (d *Discord) gatewayURL(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, d.baseURL+"/gateway/bot", nil,
+	)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bot "+d.token)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("discord returned status %d", resp.StatusCode)
+	}
+
+	var gateway struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&gateway); err != nil {
+		return "", err
+	}
+
+	return gateway.URL, nil
+}
+
+func (d *Discord) sendMessage(ctx context.Context, channelID, text string) error {
+	body, err := json.Marshal(map[string]string{"content": text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		d.baseURL+"/channels/"+channelID+"/messages",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bot "+d.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("discord returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
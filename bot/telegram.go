@@ -0,0 +1,208 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	telegramBaseURL     = "https://api.telegram.org"
+	telegramPollTimeout = 30 * time.Second
+	telegramMaxLength   = 4096
+	botRetryDelay       = 5 * time.Second
+)
+
+type (
+	// Telegram answers commands sent to a telegram bot, using long polling,
+	// so the price feeder doesn't need to be reachable from the internet.
+	Telegram struct {
+		logger   zerolog.Logger
+		baseURL  string
+		token    string
+		chats    map[int64]struct{}
+		commands *Commands
+		client   *http.Client
+	}
+
+	telegramUpdatesResponse struct {
+		Ok          bool             `json:"ok"`
+		Description string           `json:"description"`
+		Result      []telegramUpdate `json:"result"`
+	}
+
+	telegramUpdate struct {
+		UpdateID int64            `json:"update_id"`
+		Message  *telegramMessage `json:"message"`
+	}
+
+	telegramMessage struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	}
+)
+
+// NewTelegram returns a telegram bot answering commands of the provided chats
+// only.
+func NewTelegram(
+	logger zerolog.Logger,
+	token string,
+	chatIDs []int64,
+	commands *Commands,
+) *Telegram {
+	chats := make(map[int64]struct{}, len(chatIDs))
+	for _, id := range chatIDs {
+		chats[id] = struct{}{}
+	}
+
+	return &Telegram{
+		logger:   logger.With().Str("bot", "telegram").Logger(),
+		baseURL:  telegramBaseURL,
+		token:    token,
+		chats:    chats,
+		commands: commands,
+		client:   &http.Client{Timeout: telegramPollTimeout + 10*time.Second},
+	}
+}
+
+// Start polls for new messages until the context is cancelled.
+func (t *Telegram) Start(ctx context.Context) error {
+	t.logger.Info().Msg("starting telegram bot")
+
+	var offset int64
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		updates, err := t.getUpdates(ctx, offset)
+		if err != nil {
+			t.logger.Warn().Err(err).Msg("failed to get telegram updates")
+			time.Sleep(botRetryDelay)
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			t.handle(ctx, update)
+		}
+	}
+}
+
+func (t *Telegram) handle(ctx context.Context, update telegramUpdate) {
+	if update.Message == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	if _, found := t.chats[chatID]; !found {
+		t.logger.Debug().Int64("chat", chatID).Msg("ignoring message of unknown chat")
+		return
+	}
+
+	answer, ok := t.commands.Handle(ctx, update.Message.Text)
+	if !ok {
+		return
+	}
+
+	err := t.sendMessage(ctx, chatID, truncate(answer, telegramMaxLength))
+	if err != nil {
+		t.logger.Warn().Err(err).Msg("failed to send telegram message")
+	}
+}
+
+func (t *Telegram) getUpdates(
+	ctx context.Context,
+	offset int64,
+) ([]telegramUpdate, error) {
+	query := url.Values{}
+	query.Set("offset", strconv.FormatInt(offset, 10))
+	query.Set("timeout", strconv.Itoa(int(telegramPollTimeout.Seconds())))
+	query.Set("allowed_updates", `["message"]`)
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, t.url("getUpdates")+"?"+query.Encode(), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var updates telegramUpdatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&updates); err != nil {
+		return nil, err
+	}
+
+	if !updates.Ok {
+		return nil, fmt.Errorf("telegram error: %s", updates.Description)
+	}
+
+	return updates.Result, nil
+}
+
+func (t *Telegram) sendMessage(ctx context.Context, chatID int64, text string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"chat_id": chatID,
+		"text":    text,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, t.url("sendMessage"), bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("telegram returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// do sends the request. Errors of the http client contain the request url,
+// which contains the bot token, so it's stripped from the returned error.
+func (t *Telegram) do(req *http.Request) (*http.Response, error) {
+	resp, err := t.client.Do(req)
+	if err == nil {
+		return resp, nil
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = fmt.Errorf("telegram %s: %w", urlErr.Op, urlErr.Err)
+	}
+	return nil, errors.New(strings.ReplaceAll(err.Error(), t.token, "<token>"))
+}
+
+func (t *Telegram) url(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", t.baseURL, t.token, method)
+}
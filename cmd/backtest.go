@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"price-feeder/oracle/derivative"
+	"price-feeder/oracle/provider"
 	"price-feeder/oracle/types"
 
 	"github.com/spf13/cobra"
@@ -16,7 +18,7 @@ import (
 func getBacktestCmd() *cobra.Command {
 	backtestCmd := &cobra.Command{
 		Use:   "backtest",
-		Short: "Backtest TWAP for values in provided JSON file",
+		Short: "Backtest TWAP for values in provided JSON or CSV price path file",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return fmt.Errorf("no file provided")
@@ -32,20 +34,33 @@ func getBacktestCmd() *cobra.Command {
 				return err
 			}
 
-			file, err := os.Open(args[0])
+			symbol, err := cmd.Flags().GetString("symbol")
 			if err != nil {
 				return err
 			}
 
-			defer file.Close()
+			var tickers []types.TickerPrice
 
-			bz, err := io.ReadAll(file)
-			if err != nil {
-				return err
-			}
+			if strings.HasSuffix(strings.ToLower(args[0]), ".csv") {
+				tickers, err = readPricePathTickers(args[0], symbol)
+				if err != nil {
+					return err
+				}
+			} else {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
 
-			var tickers []types.TickerPrice
-			json.Unmarshal(bz, &tickers)
+				defer file.Close()
+
+				bz, err := io.ReadAll(file)
+				if err != nil {
+					return err
+				}
+
+				json.Unmarshal(bz, &tickers)
+			}
 
 			var first, last time.Time
 
@@ -87,6 +102,31 @@ func getBacktestCmd() *cobra.Command {
 
 	backtestCmd.PersistentFlags().Int64("period", 1800, "Time period of the TVWAP calculation in seconds")
 	backtestCmd.PersistentFlags().Int64("interval", 60, "Interval in which new TVWAP prices are calculated in seconds")
+	backtestCmd.PersistentFlags().String("symbol", "", "Symbol of the CSV price path, optional if the file contains a single symbol")
 
 	return backtestCmd
 }
+
+// readPricePathTickers returns the tickers of the symbol in the csv price path
+// file, starting at the unix epoch.
+func readPricePathTickers(file, symbol string) ([]types.TickerPrice, error) {
+	path, err := provider.LoadPricePath(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if symbol == "" {
+		symbols := path.Symbols()
+		if len(symbols) != 1 {
+			return nil, fmt.Errorf("--symbol required, found %s", strings.Join(symbols, ", "))
+		}
+		symbol = symbols[0]
+	}
+
+	tickers := path.Tickers(symbol, time.Unix(0, 0))
+	if len(tickers) == 0 {
+		return nil, fmt.Errorf("no prices found for %s", symbol)
+	}
+
+	return tickers, nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/rs/zerolog"
+
+	"price-feeder/config"
+)
+
+// applyLimits passes the configured memory limit and the maximum number of
+// CPUs to the Go runtime. GOMEMLIMIT and GOMAXPROCS set in the environment
+// take precedence.
+func applyLimits(logger zerolog.Logger, limits config.Limits) {
+	if limits.MemoryLimitMiB > 0 {
+		if os.Getenv("GOMEMLIMIT") != "" {
+			logger.Info().Msg("GOMEMLIMIT is set, ignoring memory_limit_mib")
+		} else {
+			debug.SetMemoryLimit(int64(limits.MemoryLimitMiB) << 20)
+			logger.Info().
+				Int("memory_limit_mib", limits.MemoryLimitMiB).
+				Msg("set soft memory limit")
+		}
+	}
+
+	if limits.MaxProcs > 0 {
+		if os.Getenv("GOMAXPROCS") != "" {
+			logger.Info().Msg("GOMAXPROCS is set, ignoring max_procs")
+		} else {
+			runtime.GOMAXPROCS(limits.MaxProcs)
+			logger.Info().
+				Int("max_procs", limits.MaxProcs).
+				Msg("limited the number of cpus")
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+
+	"price-feeder/config"
+
+	"github.com/spf13/cobra"
+)
+
+func getLintConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint-config [config-file]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Validate the config file and report unknown keys",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.ParseConfig(args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, key := range cfg.UnknownKeys {
+				fmt.Printf("unknown key: %s\n", key)
+			}
+
+			if len(cfg.UnknownKeys) > 0 {
+				return fmt.Errorf("found %d unknown keys", len(cfg.UnknownKeys))
+			}
+
+			fmt.Println("config is valid")
+			return nil
+		},
+	}
+}
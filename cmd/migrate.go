@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"price-feeder/config"
+
+	"github.com/spf13/cobra"
+)
+
+func getMigrateConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate-config [old-config-file] [new-config-file]",
+		Args:  cobra.RangeArgs(1, 2),
+		Short: "Upgrade a config file of an older release to the current format",
+		Long: `Upgrade a config file of an older release to the current format.
+The new config is written to new-config-file, or to stdout if omitted. A
+summary of the changes is printed to stderr. Comments are not preserved.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			migrated, migrations, err := config.MigrateConfig(content)
+			if err != nil {
+				return err
+			}
+
+			for _, migration := range migrations {
+				fmt.Fprintf(os.Stderr, "- %s\n", migration)
+			}
+			fmt.Fprintf(os.Stderr, "%d changes\n", len(migrations))
+
+			if len(args) < 2 {
+				_, err = os.Stdout.Write(migrated)
+				return err
+			}
+
+			return os.WriteFile(args[1], migrated, 0o600)
+		},
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"price-feeder/config"
+	"price-feeder/monitoring"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagOutputDir  = "output-dir"
+	flagStaleAfter = "stale-after"
+	flagMaxMisses  = "max-misses"
+	flagMinBalance = "min-balance"
+
+	monitoringRulesFile     = "price-feeder-alerts.yml"
+	monitoringDashboardFile = "price-feeder-dashboard.json"
+)
+
+func getGenMonitoringCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gen-monitoring [config-file]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Generate Prometheus alert rules and a Grafana dashboard from the config",
+		Long: `Generate Prometheus alert rules and a Grafana dashboard for the
+providers and pairs of the config file. The files are written to the output
+directory as ` + monitoringRulesFile + ` and ` + monitoringDashboardFile + `.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.ParseConfig(args[0])
+			if err != nil {
+				return err
+			}
+
+			outputDir, err := cmd.Flags().GetString(flagOutputDir)
+			if err != nil {
+				return err
+			}
+			staleAfter, err := cmd.Flags().GetDuration(flagStaleAfter)
+			if err != nil {
+				return err
+			}
+			maxMisses, err := cmd.Flags().GetInt(flagMaxMisses)
+			if err != nil {
+				return err
+			}
+			minBalance, err := cmd.Flags().GetInt64(flagMinBalance)
+			if err != nil {
+				return err
+			}
+
+			if staleAfter < time.Second {
+				return fmt.Errorf("stale-after must be at least 1s")
+			}
+
+			generator, err := monitoring.NewGenerator(cfg, monitoring.Thresholds{
+				StaleAfter: staleAfter,
+				MaxMisses:  maxMisses,
+				MinBalance: minBalance,
+			})
+			if err != nil {
+				return err
+			}
+
+			rules, err := generator.Rules()
+			if err != nil {
+				return err
+			}
+			dashboard, err := generator.Dashboard()
+			if err != nil {
+				return err
+			}
+
+			for name, content := range map[string][]byte{
+				monitoringRulesFile:     rules,
+				monitoringDashboardFile: dashboard,
+			} {
+				path := filepath.Join(outputDir, name)
+				if err := os.WriteFile(path, content, 0o644); err != nil {
+					return err
+				}
+				fmt.Printf("wrote %s\n", path)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagOutputDir, ".", "Directory the files are written to")
+	cmd.Flags().Duration(flagStaleAfter, 15*time.Minute, "Alert if a price didn't change for this duration")
+	cmd.Flags().Int(flagMaxMisses, 0, "Alert if more votes are missed per hour")
+	cmd.Flags().Int64(flagMinBalance, 1_000_000, "Alert if the balance of the fee denom is lower")
+
+	return cmd
+}
@@ -3,17 +3,17 @@ package cmd
 import (
 	"bufio"
 	"context"
-	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
 
-	"github.com/Team-Kujira/core/app/params"
 	input "github.com/cosmos/cosmos-sdk/client/input"
 	"github.com/mitchellh/mapstructure"
 
@@ -22,17 +22,24 @@ import (
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
 
+	"price-feeder/aggregator"
+	"price-feeder/bot"
+	"price-feeder/collector"
 	"price-feeder/config"
+	"price-feeder/governance"
+	"price-feeder/grpcapi"
+	"price-feeder/leader"
 	"price-feeder/oracle"
 	"price-feeder/oracle/client"
-	"price-feeder/oracle/derivative"
 	"price-feeder/oracle/history"
+	"price-feeder/oracle/labels"
 	"price-feeder/oracle/provider"
-	"price-feeder/oracle/types"
+	"price-feeder/pkg/secrets"
+	"price-feeder/report"
 	v1 "price-feeder/router/v1"
+	"price-feeder/update"
 
 	"github.com/cosmos/cosmos-sdk/telemetry"
-	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 const (
@@ -64,6 +71,13 @@ func init() {
 
 	rootCmd.AddCommand(getVersionCmd())
 	rootCmd.AddCommand(getBacktestCmd())
+	rootCmd.AddCommand(getThresholdsCmd())
+	rootCmd.AddCommand(getExportStateCmd())
+	rootCmd.AddCommand(getImportStateCmd())
+	rootCmd.AddCommand(getLintConfigCmd())
+	rootCmd.AddCommand(getMigrateConfigCmd())
+	rootCmd.AddCommand(getGenMonitoringCmd())
+	rootCmd.AddCommand(getProvidersCmd())
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -114,7 +128,24 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	params.SetAddressPrefixes()
+	for _, key := range cfg.UnknownKeys {
+		logger.Warn().
+			Str("key", key.Key).
+			Str("suggestion", key.Suggestion).
+			Msg("unknown config key is ignored")
+	}
+
+	resolver := newSecretResolver(cfg.Secrets)
+	if err := expandSecrets(cmd.Context(), &cfg, resolver); err != nil {
+		return err
+	}
+
+	chainProfile, err := oracle.GetChainProfile(cfg.ChainProfile)
+	if err != nil {
+		return err
+	}
+	oracle.SetAddressPrefixes(chainProfile)
+	applyLimits(logger, cfg.Limits)
 
 	ctx, cancel := context.WithCancel(cmd.Context())
 	g, ctx := errgroup.WithContext(ctx)
@@ -127,8 +158,8 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to parse RPC timeout: %w", err)
 	}
 
-	// Gather pass via env variable || std input
-	keyringPass, err := getKeyringPassword()
+	// Gather pass via env variable || secret manager || std input
+	keyringPass, err := getKeyringPassword(ctx, resolver, cfg.Secrets.KeyringPassword)
 	if err != nil {
 		return err
 	}
@@ -145,12 +176,12 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 		cfg.Keyring.Backend,
 		cfg.Keyring.Dir,
 		keyringPass,
-		cfg.RPC.TMRPCEndpoint,
+		cfg.RPC.TMRPC(),
 		rpcTimeout,
 		cfg.Account.Address,
 		cfg.Account.Validator,
 		cfg.Account.FeeGranter,
-		cfg.RPC.GRPCEndpoint,
+		cfg.RPC.GRPC(),
 		cfg.GasAdjustment,
 		cfg.GasPrices,
 		heightPollInterval,
@@ -158,128 +189,42 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-
-	providerTimeout, err := time.ParseDuration(cfg.ProviderTimeout)
-	if err != nil {
-		return fmt.Errorf("failed to parse provider timeout: %w", err)
-	}
-
-	deviations := make(map[string]sdk.Dec, len(cfg.Deviations))
-	for _, deviation := range cfg.Deviations {
-		threshold, err := sdk.NewDecFromStr(deviation.Threshold)
+	oracleClient.Memo = strings.ReplaceAll(cfg.Tx.Memo, "{version}", Version)
+	oracleClient.TimeoutHeight = cfg.Tx.TimeoutHeight
+	if cfg.Tx.AuditLog != "" {
+		oracleClient.AuditLog, err = client.NewAuditLog(cfg.Tx.AuditLog)
 		if err != nil {
 			return err
 		}
-		deviations[deviation.Base] = threshold
-	}
-
-	providerMinOverrides := make(map[string]int, len(cfg.ProviderMinOverrides))
-	for _, override := range cfg.ProviderMinOverrides {
-		for _, denom := range override.Denoms {
-			_, found := providerMinOverrides[denom]
-			if found {
-				logger.Warn().
-					Str("denom", denom).
-					Msg("provider_min_overrides already set")
-			}
-			providerMinOverrides[denom] = int(override.Providers)
-		}
 	}
 
-	endpoints := make(map[provider.Name]provider.Endpoint, len(cfg.ProviderEndpoints))
-	for _, e := range cfg.ProviderEndpoints {
-		endpoint, err := e.ToEndpoint(cfg.UrlSets)
-		if err != nil {
-			return err
-		}
-		endpoints[endpoint.Name] = endpoint
-	}
-
-	history, err := history.NewPriceHistory(cfg.HistoryDb, logger)
+	oracle, history, err := aggregator.NewOracle(logger, cfg, oracleClient)
 	if err != nil {
-		return fmt.Errorf("failed to init price history db: %v", err)
+		return err
 	}
+	oracle.SetResourceLimits(
+		uint64(cfg.Limits.MemoryThresholdMiB)<<20, cfg.Limits.MaxGoroutines,
+	)
 
-	derivativePairs := map[string][]types.CurrencyPair{}
-	derivativePeriods := map[string]map[string]time.Duration{}
-	derivativeSymbols := map[string]struct{}{}
-	providerPairs := []config.CurrencyPair{}
-	for _, pair := range cfg.CurrencyPairs {
-		if pair.Derivative != "" {
-			period, err := time.ParseDuration(pair.DerivativePeriod)
-			if err != nil {
-				return err
-			}
-			pairs, ok := derivativePairs[pair.Derivative]
-			if !ok {
-				pairs = []types.CurrencyPair{}
-				derivativePeriods[pair.Derivative] = map[string]time.Duration{}
-			}
-			currencyPair := types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}
-			derivativePairs[pair.Derivative] = append(pairs, currencyPair)
-			derivativePeriods[pair.Derivative][currencyPair.String()] = period
-			derivativeSymbols[pair.Base+pair.Quote] = struct{}{}
-		}
-		providerPairs = append(providerPairs, pair)
+	if cfg.Voter.CollectorURL != "" {
+		// vote the prices of the collector instead of running the providers
+		oracle.SetPriceSource(collector.NewClient(cfg.Voter))
 	}
 
-	derivatives := map[string]derivative.Derivative{}
-	for name, pairs := range derivativePairs {
-		d, err := derivative.NewDerivative(name, logger, &history, pairs, derivativePeriods[name])
+	var priceMetricsInterval time.Duration
+	if cfg.Telemetry.PriceMetricsInterval != "" {
+		priceMetricsInterval, err = time.ParseDuration(cfg.Telemetry.PriceMetricsInterval)
 		if err != nil {
 			return err
 		}
-		derivatives[name] = d
 	}
-
-	providerWeights := map[string]oracle.ProviderWeight{}
-	for denom, weights := range cfg.ProviderWeights {
-		newWeight := oracle.ProviderWeight{
-			Type:   "simple",
-			Weight: map[string]sdk.Dec{},
-		}
-
-		for providerName, value := range weights {
-			if value < 0 {
-				return fmt.Errorf("override must be >= 0")
-			}
-
-			value, err := sdk.NewDecFromStr(fmt.Sprintf("%f", value))
-			if err != nil {
-				return err
-			}
-			newWeight.Weight[providerName] = value
-		}
-
-		providerWeights[denom] = newWeight
-	}
-
-	volumeDatabase, err := sql.Open("sqlite3", cfg.HistoryDb)
-	if err != nil {
-		logger.Err(err).
-			Str("path", cfg.HistoryDb).
-			Msg("failed to open sqlite db")
-	}
-	volumeDatabase.SetMaxOpenConns(1)
-
-	oracle := oracle.New(
-		logger,
-		oracleClient,
-		providerPairs,
-		providerTimeout,
-		deviations,
-		providerMinOverrides,
-		endpoints,
-		derivatives,
-		derivativePairs,
-		derivativeSymbols,
-		cfg.Healthchecks,
-		history,
-		cfg.ContractAdresses,
-		providerWeights,
-		cfg.Decimals,
-		cfg.Periods,
-		volumeDatabase,
+	provider.ConfigurePriceMetrics(
+		priceMetricsInterval, !cfg.Telemetry.DisablePairMetrics,
+	)
+	labels.Configure(
+		cfg.Telemetry.AllowedProviders,
+		cfg.Telemetry.AllowedPairs,
+		cfg.Telemetry.AggregateOnly,
 	)
 
 	telemetryCfg := telemetry.Config{}
@@ -292,13 +237,86 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	var updates v1.Updates
+	if cfg.UpdateCheck.Enabled {
+		checker := update.NewChecker(
+			logger, cfg.UpdateCheck, cfg.Account.ChainID, Version,
+		)
+		updates = checker
+		g.Go(func() error {
+			return checker.Start(ctx)
+		})
+	}
+
+	var election v1.Election
+	if len(cfg.Leader.Peers) > 0 {
+		elector := leader.NewElector(logger, cfg.Leader, oracle)
+		oracle.SetLeaderElection(elector)
+		election = elector
+		g.Go(func() error {
+			return elector.Start(ctx)
+		})
+	}
+
+	if cfg.Governance.Enabled {
+		watcher := governance.NewWatcher(
+			logger, cfg.Governance, oracleClient.GRPC.Active, oracle,
+		)
+		g.Go(func() error {
+			return watcher.Start(ctx)
+		})
+	}
+
+	if cfg.GRPC.ListenAddr != "" {
+		grpcServer := grpcapi.NewServer(
+			logger, cfg.GRPC, cfg.Server.AuthTokens, oracle,
+		)
+		g.Go(func() error {
+			return grpcServer.Start(ctx)
+		})
+	}
+
 	if cfg.EnableServer {
 		g.Go(func() error {
 			// start the process that observes and publishes exchange prices
-			return startPriceFeeder(ctx, logger, cfg, oracle, metrics)
+			return startPriceFeeder(
+				ctx, logger, cfg, oracle, metrics, history, updates, election,
+			)
 		})
 	}
 
+	commands := bot.NewCommands(oracle)
+	if cfg.Bot.Telegram.Token != "" {
+		telegram := bot.NewTelegram(
+			logger, cfg.Bot.Telegram.Token, cfg.Bot.Telegram.ChatIDs, commands,
+		)
+		g.Go(func() error {
+			return telegram.Start(ctx)
+		})
+	}
+	if cfg.Bot.Discord.Token != "" {
+		discord := bot.NewDiscord(
+			logger, cfg.Bot.Discord.Token, cfg.Bot.Discord.ChannelIDs, commands,
+		)
+		g.Go(func() error {
+			return discord.Start(ctx)
+		})
+	}
+
+	if cfg.Report.URL != "" {
+		var reportMetrics report.Metrics
+		if metrics != nil {
+			reportMetrics = metrics
+		}
+		reporter := report.NewReporter(logger, cfg.Report, oracle, reportMetrics)
+		g.Go(func() error {
+			return reporter.Start(ctx)
+		})
+	}
+
+	// dump the oracle state on SIGUSR1 for debugging
+	trapDumpSignal(ctx, logger, cfg.DebugDumpDir, oracle)
+
 	if cfg.EnableVoter {
 		g.Go(func() error {
 			// start the process that calculates oracle prices and votes
@@ -311,14 +329,21 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 	return g.Wait()
 }
 
-func getKeyringPassword() (string, error) {
+func getKeyringPassword(
+	ctx context.Context,
+	resolver *secrets.Resolver,
+	ref string,
+) (string, error) {
 	reader := bufio.NewReader(os.Stdin)
 
 	pass := os.Getenv(envVariablePass)
-	if pass == "" {
-		return input.GetString("Enter keyring password", reader)
+	if pass != "" {
+		return pass, nil
+	}
+	if ref != "" {
+		return resolver.Resolve(ctx, ref)
 	}
-	return pass, nil
+	return input.GetString("Enter keyring password", reader)
 }
 
 // trapSignal will listen for any OS signal and invoke Done on the main
@@ -336,15 +361,65 @@ func trapSignal(cancel context.CancelFunc, logger zerolog.Logger) {
 	}()
 }
 
+// trapDumpSignal will listen for SIGUSR1 and write a snapshot of the oracle
+// state to the log or, if configured, to a file in the dump directory.
+func trapDumpSignal(
+	ctx context.Context,
+	logger zerolog.Logger,
+	dir string,
+	oracle *oracle.Oracle,
+) {
+	sigCh := make(chan os.Signal, 1)
+
+	signal.Notify(sigCh, syscall.SIGUSR1)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				signal.Stop(sigCh)
+				return
+
+			case <-sigCh:
+				dump := oracle.Dump()
+
+				data, err := json.MarshalIndent(dump, "", "  ")
+				if err != nil {
+					logger.Err(err).Msg("failed to marshal debug dump")
+					continue
+				}
+
+				if dir == "" {
+					logger.Info().RawJSON("dump", data).Msg("debug dump")
+					continue
+				}
+
+				path := filepath.Join(
+					dir, fmt.Sprintf("dump-%d.json", dump.Time.Unix()),
+				)
+				if err := os.WriteFile(path, data, 0o644); err != nil {
+					logger.Err(err).Str("path", path).Msg("failed to write debug dump")
+					continue
+				}
+
+				logger.Info().Str("path", path).Msg("wrote debug dump")
+			}
+		}
+	}()
+}
+
 func startPriceFeeder(
 	ctx context.Context,
 	logger zerolog.Logger,
 	cfg config.Config,
 	oracle *oracle.Oracle,
 	metrics *telemetry.Metrics,
+	history *history.PriceHistory,
+	updates v1.Updates,
+	election v1.Election,
 ) error {
 	rtr := mux.NewRouter()
-	v1Router := v1.New(logger, cfg, oracle, metrics)
+	v1Router := v1.New(logger, cfg, oracle, metrics, history, updates, election)
 	v1Router.RegisterRoutes(rtr, v1.APIPathPrefix)
 
 	writeTimeout, err := time.ParseDuration(cfg.Server.WriteTimeout)
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"price-feeder/oracle/provider"
+
+	"github.com/spf13/cobra"
+)
+
+func getProvidersCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "providers",
+		Args:  cobra.NoArgs,
+		Short: "List the supported providers and their default endpoints",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tURLS\tWEBSOCKET")
+			for _, registration := range provider.Registrations() {
+				websocket := registration.Defaults.Websocket
+				if websocket == "" {
+					websocket = "-"
+				}
+				fmt.Fprintf(
+					w, "%s\t%s\t%s\n",
+					registration.Name,
+					strings.Join(registration.Defaults.Urls, ","),
+					websocket,
+				)
+			}
+			return w.Flush()
+		},
+	}
+}
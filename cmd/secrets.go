@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"price-feeder/config"
+	"price-feeder/pkg/secrets"
+)
+
+const (
+	envVariableVaultAddr  = "VAULT_ADDR"
+	envVariableVaultToken = "VAULT_TOKEN"
+
+	defaultSecretsTimeout = 10 * time.Second
+)
+
+// newSecretResolver returns a resolver for the secret references of the
+// config. Backends only connect once a secret is resolved.
+func newSecretResolver(cfg config.Secrets) *secrets.Resolver {
+	timeout := defaultSecretsTimeout
+	if cfg.Timeout != "" {
+		timeout, _ = time.ParseDuration(cfg.Timeout)
+	}
+
+	address := cfg.Vault.Address
+	if address == "" {
+		address = os.Getenv(envVariableVaultAddr)
+	}
+
+	resolver := secrets.NewResolver(cfg.Refs)
+	resolver.Register(secrets.BackendVault, secrets.NewVault(
+		address, os.Getenv(envVariableVaultToken), cfg.Vault.Namespace, timeout,
+	))
+	resolver.Register(secrets.BackendAWS, secrets.NewAWS(cfg.AWSRegion))
+	resolver.Register(secrets.BackendGCP, secrets.NewGCP(timeout))
+
+	return resolver
+}
+
+// expandSecrets replaces the ${secret:<name>} placeholders of all config
+// values with the referenced secrets.
+func expandSecrets(
+	ctx context.Context,
+	cfg *config.Config,
+	resolver *secrets.Resolver,
+) error {
+	return cfg.ExpandStrings(func(value string) (string, error) {
+		if !secrets.HasPlaceholder(value) {
+			return value, nil
+		}
+		return resolver.Expand(ctx, value)
+	})
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"price-feeder/config"
+	"price-feeder/oracle"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	stateArchiveState   = "state.json"
+	stateArchiveHistory = "history.db"
+)
+
+func getExportStateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export-state [config-file] [archive]",
+		Args:  cobra.ExactArgs(2),
+		Short: "Export the voting state and the history db into an archive",
+		Long: `Export the voting state (last prevote, account sequence and prices)
+and a consistent copy of the history db, including the ticker history and
+volumes, into a tar.gz archive. Stop the feeder before exporting, so the state
+file contains the latest prevote.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.ParseConfig(args[0])
+			if err != nil {
+				return err
+			}
+			if cfg.StateFile == "" {
+				return fmt.Errorf("no state_file configured")
+			}
+
+			state, err := oracle.ReadState(cfg.StateFile)
+			if err != nil {
+				return fmt.Errorf("failed to read state: %w", err)
+			}
+
+			dir, err := os.MkdirTemp("", "price-feeder-state")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+
+			history := filepath.Join(dir, stateArchiveHistory)
+			if err := copySqlite(cfg.HistoryDb, history); err != nil {
+				return fmt.Errorf("failed to copy history db: %w", err)
+			}
+
+			if err := writeStateArchive(args[1], map[string]string{
+				stateArchiveState:   cfg.StateFile,
+				stateArchiveHistory: history,
+			}); err != nil {
+				return err
+			}
+
+			fmt.Printf(
+				"exported state of %s (vote period %.0f, prevote %t, sequence %d)\n",
+				state.Time.Format(time.RFC3339),
+				state.PreviousVotePeriod,
+				state.PreviousPrevote != nil,
+				state.Sequence,
+			)
+
+			return nil
+		},
+	}
+}
+
+func getImportStateCmd() *cobra.Command {
+	importStateCmd := &cobra.Command{
+		Use:   "import-state [config-file] [archive]",
+		Args:  cobra.ExactArgs(2),
+		Short: "Import the voting state and the history db from an archive",
+		Long: `Import an archive created with export-state to the configured
+state_file and history_db. Start the feeder afterwards to reveal the exported
+prevote in the next vote period.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			force, err := cmd.Flags().GetBool("force")
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.ParseConfig(args[0])
+			if err != nil {
+				return err
+			}
+			if cfg.StateFile == "" {
+				return fmt.Errorf("no state_file configured")
+			}
+
+			targets := map[string]string{
+				stateArchiveState:   cfg.StateFile,
+				stateArchiveHistory: cfg.HistoryDb,
+			}
+
+			if !force {
+				for _, target := range targets {
+					if _, err := os.Stat(target); err == nil {
+						return fmt.Errorf("%s already exists, use --force to overwrite", target)
+					}
+				}
+			}
+
+			return readStateArchive(args[1], targets)
+		},
+	}
+
+	importStateCmd.Flags().Bool("force", false, "Overwrite existing state and history db")
+
+	return importStateCmd
+}
+
+// copySqlite writes a consistent copy of the sqlite database, even while it
+// is in use.
+func copySqlite(src, dst string) error {
+	db, err := sql.Open("sqlite3", src)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec("VACUUM INTO ?", dst)
+	return err
+}
+
+// writeStateArchive writes the files to a tar.gz archive using the keys as
+// names.
+func writeStateArchive(path string, files map[string]string) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	for name, file := range files {
+		if err := addArchiveFile(tw, name, file); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+func addArchiveFile(tw *tar.Writer, name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+// readStateArchive extracts the archive entries to the targets by name.
+// Unknown entries are ignored.
+func readStateArchive(path string, targets map[string]string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, found := targets[header.Name]
+		if !found {
+			continue
+		}
+
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+
+		fmt.Printf("imported %s to %s\n", header.Name, target)
+	}
+}
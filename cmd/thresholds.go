@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"price-feeder/config"
+	"price-feeder/oracle"
+	"price-feeder/oracle/history"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+)
+
+func getThresholdsCmd() *cobra.Command {
+	thresholdsCmd := &cobra.Command{
+		Use:   "thresholds [config-file]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Suggest deviation thresholds from the price dispersion stored in the history db",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window, err := cmd.Flags().GetDuration("window")
+			if err != nil {
+				return err
+			}
+
+			multiplierStr, err := cmd.Flags().GetString("multiplier")
+			if err != nil {
+				return err
+			}
+			multiplier, err := sdk.NewDecFromStr(multiplierStr)
+			if err != nil {
+				return err
+			}
+
+			minSamples, err := cmd.Flags().GetInt("min-samples")
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.ParseConfig(args[0])
+			if err != nil {
+				return err
+			}
+
+			h, err := history.NewPriceHistory(cfg.HistoryDb, zerolog.Nop())
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			dispersions, err := h.Dispersions(now.Add(-window), now)
+			if err != nil {
+				return err
+			}
+
+			configured := map[string]string{}
+			for _, deviation := range cfg.Deviations {
+				configured[deviation.Base] = deviation.Threshold
+			}
+
+			suggestions := oracle.SuggestThresholds(dispersions, multiplier, minSamples)
+			for _, suggestion := range oracle.SortedThresholdSuggestions(suggestions) {
+				fmt.Printf(
+					"# samples: %d, mean score: %.3f, std score: %.3f, relative dispersion: %.5f",
+					suggestion.Samples,
+					suggestion.MeanScore,
+					suggestion.StdScore,
+					suggestion.Relative,
+				)
+				if threshold, found := configured[suggestion.Denom]; found {
+					fmt.Printf(", configured: %s", threshold)
+				}
+				fmt.Printf(
+					"\n[[deviation_thresholds]]\nbase = %q\nthreshold = \"%.2f\"\n\n",
+					suggestion.Denom,
+					suggestion.Threshold.MustFloat64(),
+				)
+			}
+
+			return nil
+		},
+	}
+
+	thresholdsCmd.PersistentFlags().Duration("window", 30*24*time.Hour, "Time window of the price dispersion")
+	thresholdsCmd.PersistentFlags().String("multiplier", "3", "Standard deviations of the dispersion added to its mean")
+	thresholdsCmd.PersistentFlags().Int("min-samples", 100, "Minimum number of ticks needed for a suggestion")
+
+	return thresholdsCmd
+}
@@ -0,0 +1,150 @@
+// Package collector lets the provider machinery and the voter run as
+// separate processes. The collector signs its aggregated prices with a
+// shared key and the voter only accepts fresh prices with a valid signature,
+// so the host holding the feeder key never talks to the exchanges.
+package collector
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/config"
+)
+
+const (
+	// PricesPath is the path of the signed prices below the v1 API prefix.
+	PricesPath = "/signed_prices"
+
+	defaultMaxAge  = 30 * time.Second
+	defaultTimeout = 5 * time.Second
+)
+
+type (
+	// SignedPrices defines the prices of a collector with the time they
+	// were computed, signed with the shared key.
+	SignedPrices struct {
+		Time      time.Time          `json:"time"`
+		Prices    map[string]sdk.Dec `json:"prices"`
+		Signature string             `json:"signature"`
+	}
+
+	// Client fetches and verifies the signed prices of a collector. It
+	// implements the price source of a voter.
+	Client struct {
+		url    string
+		token  string
+		key    []byte
+		maxAge time.Duration
+		client *http.Client
+	}
+)
+
+// Sign returns the prices signed with the key.
+func Sign(key []byte, prices map[string]sdk.Dec, t time.Time) SignedPrices {
+	signed := SignedPrices{
+		Time:   t.UTC(),
+		Prices: prices,
+	}
+	signed.Signature = hex.EncodeToString(signed.mac(key))
+	return signed
+}
+
+// Verify returns an error if the signature doesn't match the prices.
+func (s SignedPrices) Verify(key []byte) error {
+	signature, err := hex.DecodeString(s.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if !hmac.Equal(signature, s.mac(key)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// mac returns the HMAC-SHA256 of the time and the sorted prices.
+func (s SignedPrices) mac(key []byte) []byte {
+	denoms := make([]string, 0, len(s.Prices))
+	for denom := range s.Prices {
+		denoms = append(denoms, denom)
+	}
+	sort.Strings(denoms)
+
+	lines := make([]string, 0, len(denoms)+1)
+	lines = append(lines, fmt.Sprintf("%d", s.Time.UnixNano()))
+	for _, denom := range denoms {
+		lines = append(lines, denom+"="+s.Prices[denom].String())
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	return mac.Sum(nil)
+}
+
+// NewClient returns a client of the configured collector.
+func NewClient(cfg config.Voter) *Client {
+	parse := func(value string, fallback time.Duration) time.Duration {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return fallback
+		}
+		return duration
+	}
+
+	return &Client{
+		url:    strings.TrimSuffix(cfg.CollectorURL, "/"),
+		token:  cfg.Token,
+		key:    []byte(cfg.SigningKey),
+		maxAge: parse(cfg.MaxAge, defaultMaxAge),
+		client: &http.Client{Timeout: parse(cfg.Timeout, defaultTimeout)},
+	}
+}
+
+// Prices returns the verified prices of the collector. Prices older than
+// the max age are rejected, so a stalled collector can't make the voter
+// vote stale prices.
+func (c *Client) Prices(ctx context.Context) (map[string]sdk.Dec, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.url+"/api/v1"+PricesPath, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+
+	var signed SignedPrices
+	if err := json.NewDecoder(resp.Body).Decode(&signed); err != nil {
+		return nil, err
+	}
+
+	if err := signed.Verify(c.key); err != nil {
+		return nil, err
+	}
+
+	if age := time.Since(signed.Time); age > c.maxAge {
+		return nil, fmt.Errorf("collector prices are stale: %s old", age.Round(time.Second))
+	}
+
+	return signed.Prices, nil
+}
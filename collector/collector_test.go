@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+)
+
+func TestSignVerify(t *testing.T) {
+	prices := map[string]sdk.Dec{
+		"KUJI": sdk.MustNewDecFromStr("0.75"),
+		"ATOM": sdk.MustNewDecFromStr("9.1"),
+	}
+
+	signed := Sign([]byte("key"), prices, time.Unix(1700000000, 0))
+	require.NoError(t, signed.Verify([]byte("key")))
+	require.Error(t, signed.Verify([]byte("other")))
+
+	// survives the json round trip
+	bz, err := json.Marshal(signed)
+	require.NoError(t, err)
+	var decoded SignedPrices
+	require.NoError(t, json.Unmarshal(bz, &decoded))
+	require.NoError(t, decoded.Verify([]byte("key")))
+
+	decoded.Prices["KUJI"] = sdk.MustNewDecFromStr("0.76")
+	require.Error(t, decoded.Verify([]byte("key")))
+}
+
+func TestClientPrices(t *testing.T) {
+	var computed atomic.Int64
+	computed.Store(time.Now().UnixNano())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.Equal(t, "/api/v1"+PricesPath, r.URL.Path)
+
+		signed := Sign([]byte("key"), map[string]sdk.Dec{
+			"KUJI": sdk.MustNewDecFromStr("0.75"),
+		}, time.Unix(0, computed.Load()))
+		_ = json.NewEncoder(w).Encode(signed)
+	}))
+	defer server.Close()
+
+	cfg := config.Voter{
+		CollectorURL: server.URL + "/",
+		Token:        "token",
+		SigningKey:   "key",
+	}
+
+	prices, err := NewClient(cfg).Prices(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("0.75"), prices["KUJI"])
+
+	cfg.SigningKey = "other"
+	_, err = NewClient(cfg).Prices(context.Background())
+	require.ErrorContains(t, err, "invalid signature")
+
+	cfg.Token = ""
+	_, err = NewClient(cfg).Prices(context.Background())
+	require.ErrorContains(t, err, "status 401")
+
+	cfg = config.Voter{
+		CollectorURL: server.URL,
+		Token:        "token",
+		SigningKey:   "key",
+		MaxAge:       "1s",
+	}
+	computed.Store(time.Now().Add(-time.Minute).UnixNano())
+	_, err = NewClient(cfg).Prices(context.Background())
+	require.ErrorContains(t, err, "stale")
+}
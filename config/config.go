@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +21,11 @@ import (
 const (
 	DenomUSD = "USD"
 
+	HealthcheckSuccess = "success"
+	HealthcheckFail    = "fail"
+	HealthcheckStart   = "start"
+	HealthcheckStop    = "stop"
+
 	defaultListenAddr         = "0.0.0.0:7171"
 	defaultSrvWriteTimeout    = 15 * time.Second
 	defaultSrvReadTimeout     = 15 * time.Second
@@ -25,6 +33,17 @@ const (
 	defaultHeightPollInterval = 1 * time.Second
 	defaultHistoryDb          = "prices.db"
 	defaultDerivativePeriod   = 30 * time.Minute
+	defaultBlacklistDeviation = "0.1"
+	defaultBlacklistDuration  = 1 * time.Hour
+	defaultDelistAfter        = 6 * time.Hour
+	defaultTickerRetention    = 72 * time.Hour
+	defaultTickInterval       = 1 * time.Second
+	minTickInterval           = 100 * time.Millisecond
+	maxTickInterval           = 10 * time.Second
+
+	// maxMemoLength defines the default maximum memo length of the cosmos
+	// sdk auth module.
+	maxMemoLength = 256
 )
 
 var (
@@ -33,65 +52,6 @@ var (
 	// ErrEmptyConfigPath defines a sentinel error for an empty config path.
 	ErrEmptyConfigPath = errors.New("empty configuration file path")
 
-	// SupportedProviders defines a lookup table of all the supported currency API
-	// providers.
-	SupportedProviders = map[provider.Name]struct{}{
-		provider.ProviderAstroportInjective: {},
-		provider.ProviderAstroportNeutron:   {},
-		provider.ProviderAstroportTerra2:    {},
-		provider.ProviderBinance:            {},
-		provider.ProviderBinanceUS:          {},
-		provider.ProviderBingx:              {},
-		provider.ProviderBitfinex:           {},
-		provider.ProviderBitforex:           {},
-		provider.ProviderBitget:             {},
-		provider.ProviderBitmart:            {},
-		provider.ProviderBitstamp:           {},
-		provider.ProviderBybit:              {},
-		provider.ProviderCamelotV2:          {},
-		provider.ProviderCamelotV3:          {},
-		provider.ProviderCoinbase:           {},
-		provider.ProviderCoinex:             {},
-		provider.ProviderCrypto:             {},
-		provider.ProviderCurve:              {},
-		provider.ProviderDexter:             {},
-		provider.ProviderFin:                {},
-		provider.ProviderFinV2:              {},
-		provider.ProviderGate:               {},
-		provider.ProviderHelix:              {},
-		provider.ProviderHitBtc:             {},
-		provider.ProviderHuobi:              {},
-		provider.ProviderIdxOsmosis:         {},
-		provider.ProviderKraken:             {},
-		provider.ProviderKucoin:             {},
-		provider.ProviderLbank:              {},
-		provider.ProviderMaya:               {},
-		provider.ProviderMexc:               {},
-		provider.ProviderMock:               {},
-		provider.ProviderOkx:                {},
-		provider.ProviderOsmosisV2:          {},
-		provider.ProviderPancakeV3Bsc:       {},
-		provider.ProviderPhemex:             {},
-		provider.ProviderPionex:             {},
-		provider.ProviderPoloniex:           {},
-		provider.ProviderPyth:               {},
-		provider.ProviderShade:              {},
-		provider.ProviderStride:             {},
-		provider.ProviderUniswapV3:          {},
-		provider.ProviderUnstake:            {},
-		provider.ProviderVelodromeV2:        {},
-		provider.ProviderWhitewhaleCmdx:     {},
-		provider.ProviderWhitewhaleHuahua:   {},
-		provider.ProviderWhitewhaleInj:      {},
-		provider.ProviderWhitewhaleJuno:     {},
-		provider.ProviderWhitewhaleLuna:     {},
-		provider.ProviderWhitewhaleLunc:     {},
-		provider.ProviderWhitewhaleSei:      {},
-		provider.ProviderWhitewhaleWhale:    {},
-		provider.ProviderXt:                 {},
-		provider.ProviderZero:               {},
-	}
-
 	SupportedDerivatives = map[string]struct{}{
 		derivative.DerivativeTwap: {},
 	}
@@ -109,6 +69,7 @@ type (
 		Deviations           []Deviation                   `toml:"deviation_thresholds"`
 		ProviderMinOverrides []ProviderMinOverrides        `toml:"provider_min_overrides"`
 		ProviderWeights      map[string]map[string]float64 `toml:"provider_weight"`
+		LiquidityWeighting   string                        `toml:"liquidity_weighting"`
 		Account              Account                       `toml:"account" validate:"required,gt=0,dive,required"`
 		Keyring              Keyring                       `toml:"keyring" validate:"required,gt=0,dive,required"`
 		RPC                  RPC                           `toml:"rpc" validate:"required,gt=0,dive,required"`
@@ -119,13 +80,42 @@ type (
 		ProviderEndpoints    []ProviderEndpoints           `toml:"provider_endpoints" validate:"dive"`
 		EnableServer         bool                          `toml:"enable_server"`
 		EnableVoter          bool                          `toml:"enable_voter"`
+		BatchVotes           bool                          `toml:"batch_votes"`
 		Healthchecks         []Healthchecks                `toml:"healthchecks" validate:"dive"`
 		HeightPollInterval   string                        `toml:"height_poll_interval"`
 		HistoryDb            string                        `toml:"history_db"`
+		StateFile            string                        `toml:"state_file"`
 		ContractAdresses     map[string]map[string]string  `toml:"contract_addresses"`
 		Decimals             map[string]map[string]int     `toml:"decimals"`
 		Periods              map[string]map[string]int     `toml:"periods"`
 		UrlSets              map[string]UrlSet             `toml:"url_set"`
+		Comparison           Comparison                    `toml:"comparison"`
+		Blacklist            Blacklist                     `toml:"blacklist"`
+		VoteLog              VoteLog                       `toml:"vote_log"`
+		TickerHistory        TickerHistory                 `toml:"ticker_history"`
+		DebugDumpDir         string                        `toml:"debug_dump_dir"`
+		PriceExponents       map[string]int                `toml:"price_exponents"`
+		RatesFormat          RatesFormat                   `toml:"rates_format"`
+		ChainProfile         string                        `toml:"chain_profile"`
+		PriceBounds          string                        `toml:"price_bounds"`
+		SanityRules          []SanityRule                  `toml:"sanity_rules" validate:"dive"`
+		Timing               Timing                        `toml:"timing"`
+		Bot                  Bot                           `toml:"bot"`
+		Report               Report                        `toml:"report"`
+		AutoThresholds       AutoThresholds                `toml:"auto_thresholds"`
+		Tx                   Tx                            `toml:"tx"`
+		Whitelist            Whitelist                     `toml:"whitelist"`
+		UpdateCheck          UpdateCheck                   `toml:"update_check"`
+		Secrets              Secrets                       `toml:"secrets"`
+		Voter                Voter                         `toml:"voter"`
+		Leader               Leader                        `toml:"leader"`
+		Governance           Governance                    `toml:"governance"`
+		GRPC                 GRPC                          `toml:"grpc"`
+		Limits               Limits                        `toml:"limits"`
+
+		// UnknownKeys contains the keys of the config file, which don't
+		// match any option and are ignored.
+		UnknownKeys []UnknownKey `toml:"-"`
 	}
 
 	// Server defines the API server configuration.
@@ -135,6 +125,8 @@ type (
 		ReadTimeout    string   `toml:"read_timeout"`
 		VerboseCORS    bool     `toml:"verbose_cors"`
 		AllowedOrigins []string `toml:"allowed_origins"`
+		AuthTokens     []string `toml:"auth_tokens"`
+		SigningKey     string   `toml:"signing_key"`
 	}
 
 	// CurrencyPair defines a price quote of the exchange rate for two different
@@ -178,10 +170,14 @@ type (
 	}
 
 	// RPC defines RPC configuration of both the gRPC and Tendermint nodes.
+	// Additional nodes are listed as fallbacks, the feeder fails over to
+	// them while the preferred node is unhealthy.
 	RPC struct {
-		TMRPCEndpoint string `toml:"tmrpc_endpoint" validate:"required"`
-		GRPCEndpoint  string `toml:"grpc_endpoint" validate:"required"`
-		RPCTimeout    string `toml:"rpc_timeout" validate:"required"`
+		TMRPCEndpoint  string   `toml:"tmrpc_endpoint"`
+		TMRPCEndpoints []string `toml:"tmrpc_endpoints"`
+		GRPCEndpoint   string   `toml:"grpc_endpoint"`
+		GRPCEndpoints  []string `toml:"grpc_endpoints"`
+		RPCTimeout     string   `toml:"rpc_timeout" validate:"required"`
 	}
 
 	// Telemetry defines the configuration options for application telemetry.
@@ -213,11 +209,204 @@ type (
 		// PrometheusRetentionTime, when positive, enables a Prometheus metrics sink.
 		// It defines the retention duration in seconds.
 		PrometheusRetentionTime int64 `toml:"prometheus_retention" mapstructure:"prometheus-retention-time"`
+
+		// PriceMetricsInterval limits how often the per pair price, volume
+		// and liquidity gauges are emitted. They are batched and emitted once
+		// per tick by default.
+		PriceMetricsInterval string `toml:"price_metrics_interval" mapstructure:"-"`
+
+		// DisablePairMetrics disables the per provider pair gauges, keeping
+		// only the aggregated prices.
+		DisablePairMetrics bool `toml:"disable_pair_metrics" mapstructure:"-"`
+
+		// AllowedProviders and AllowedPairs restrict the provider and pair
+		// label values of the exported metrics. Empty lists allow all values.
+		AllowedProviders []string `toml:"allowed_providers" mapstructure:"-"`
+		AllowedPairs     []string `toml:"allowed_pairs" mapstructure:"-"`
+
+		// AggregateOnly drops all per provider metrics, except for the
+		// aggregated prices.
+		AggregateOnly bool `toml:"aggregate_only" mapstructure:"-"`
 	}
 
 	Healthchecks struct {
-		URL     string `toml:"url" validate:"required"`
-		Timeout string `toml:"timeout" validate:"required"`
+		URL     string   `toml:"url" validate:"required"`
+		Timeout string   `toml:"timeout" validate:"required"`
+		Events  []string `toml:"events"`
+	}
+
+	// SanityRule defines a custom validation rule of the computed prices,
+	// e.g. "STATOM/ATOM between 1.0 and 2.0". A violated rule blocks the
+	// vote of its denom, if block is set.
+	SanityRule struct {
+		Name  string `toml:"name"`
+		Rule  string `toml:"rule" validate:"required"`
+		Block bool   `toml:"block"`
+	}
+
+	// Timing defines the intervals of the oracle loop. The tick interval is
+	// limited at runtime, so at least two ticks happen in each vote window.
+	// The local clock is checked against the block times and optionally an
+	// NTP server, warning if it drifts more than max_clock_drift.
+	Timing struct {
+		TickInterval        string `toml:"tick_interval"`
+		PriceInterval       string `toml:"price_interval"`
+		HealthcheckInterval string `toml:"healthcheck_interval"`
+		MaxClockDrift       string `toml:"max_clock_drift"`
+		NtpServer           string `toml:"ntp_server"`
+		DeadlineCollection  bool   `toml:"deadline_collection"`
+		WarmupTicks         int    `toml:"warmup_ticks"`
+		WarmupCoverage      string `toml:"warmup_coverage"`
+		WarmupTimeout       string `toml:"warmup_timeout"`
+
+		PrevoteTimeoutBlocks int `toml:"prevote_timeout_blocks"`
+		VoteTimeoutBlocks    int `toml:"vote_timeout_blocks"`
+		StallVotePeriods     int `toml:"stall_vote_periods"`
+	}
+
+	// Bot defines the optional chat bots answering status commands.
+	Bot struct {
+		Telegram TelegramBot `toml:"telegram"`
+		Discord  DiscordBot  `toml:"discord"`
+	}
+
+	// TelegramBot defines the token of the telegram bot and the chats it
+	// answers.
+	TelegramBot struct {
+		Token   string  `toml:"token"`
+		ChatIDs []int64 `toml:"chat_ids"`
+	}
+
+	// DiscordBot defines the token of the discord bot and the channels it
+	// answers.
+	DiscordBot struct {
+		Token      string   `toml:"token"`
+		ChannelIDs []string `toml:"channel_ids"`
+	}
+
+	// Report defines the optional outbound reporting of status and metrics
+	// to a remote collector, for feeders that can't expose the server.
+	Report struct {
+		URL            string            `toml:"url"`
+		Token          string            `toml:"token"`
+		Headers        map[string]string `toml:"headers"`
+		Name           string            `toml:"name"`
+		Interval       string            `toml:"interval"`
+		PushInterval   string            `toml:"push_interval"`
+		Timeout        string            `toml:"timeout"`
+		IncludeMetrics bool              `toml:"include_metrics"`
+	}
+
+	// Leader defines the election of the voting instance among redundant
+	// feeders. It is enabled if peers are configured.
+	Leader struct {
+		Name          string   `toml:"name"`
+		Priority      int      `toml:"priority"`
+		Peers         []string `toml:"peers"`
+		Token         string   `toml:"token"`
+		Interval      string   `toml:"interval"`
+		FailoverAfter string   `toml:"failover_after"`
+	}
+
+	// Governance defines the watcher of governance proposals changing the
+	// oracle params.
+	Governance struct {
+		Enabled  bool   `toml:"enabled"`
+		Interval string `toml:"interval"`
+	}
+
+	// GRPC defines the listen address of the optional gRPC service, serving
+	// the prices and the provider status. It is disabled if empty.
+	GRPC struct {
+		ListenAddr string `toml:"listen_addr"`
+	}
+
+	// Limits defines optional soft limits, protecting co-hosted validator
+	// nodes from a runaway feeder. The memory limit is passed to the Go
+	// runtime, while exceeding the memory threshold trims the caches.
+	Limits struct {
+		MemoryLimitMiB     int `toml:"memory_limit_mib"`
+		MemoryThresholdMiB int `toml:"memory_threshold_mib"`
+		MaxProcs           int `toml:"max_procs"`
+		MaxGoroutines      int `toml:"max_goroutines"`
+	}
+
+	// Voter defines the collector a voter process reads its prices from
+	// instead of running the providers itself. The prices must be signed
+	// with the signing key of the collector.
+	Voter struct {
+		CollectorURL string `toml:"collector_url"`
+		Token        string `toml:"token"`
+		SigningKey   string `toml:"signing_key"`
+		MaxAge       string `toml:"max_age"`
+		Timeout      string `toml:"timeout"`
+	}
+
+	// Secrets defines references to secrets in external secret managers.
+	// Refs can be used in other config values as ${secret:<name>}.
+	Secrets struct {
+		KeyringPassword string            `toml:"keyring_password"`
+		Refs            map[string]string `toml:"refs"`
+		Timeout         string            `toml:"timeout"`
+		Vault           VaultSecrets      `toml:"vault"`
+		AWSRegion       string            `toml:"aws_region"`
+	}
+
+	// VaultSecrets defines the vault server. The token is read from the
+	// VAULT_TOKEN environment variable.
+	VaultSecrets struct {
+		Address   string `toml:"address"`
+		Namespace string `toml:"namespace"`
+	}
+
+	// UpdateCheck defines the optional check of a published release
+	// manifest, warning if the running version is behind the version
+	// recommended for the chain.
+	UpdateCheck struct {
+		Enabled  bool   `toml:"enabled"`
+		URL      string `toml:"url"`
+		Channel  string `toml:"channel"`
+		Interval string `toml:"interval"`
+		Timeout  string `toml:"timeout"`
+	}
+
+	// Whitelist defines how denoms, that are added to the oracle whitelist
+	// by governance without configured pairs, are handled. If the registry
+	// defines pairs for them, they are subscribed automatically, if enabled.
+	Whitelist struct {
+		Registry      string `toml:"registry"`
+		AutoSubscribe bool   `toml:"auto_subscribe"`
+	}
+
+	// RatesFormat defines the format of the exchange rates string, for
+	// forks of the oracle module with other ordering or denom rules. The
+	// options override the ones of the profile.
+	RatesFormat struct {
+		Profile     string `toml:"profile"`
+		Order       string `toml:"order"`
+		Case        string `toml:"case"`
+		Separator   string `toml:"separator"`
+		DenomPrefix string `toml:"denom_prefix"`
+		DenomSuffix string `toml:"denom_suffix"`
+	}
+
+	// Tx defines the metadata added to the oracle transactions. The memo may
+	// contain {version}, which is replaced by the feeder version.
+	Tx struct {
+		Memo          string `toml:"memo"`
+		TimeoutHeight bool   `toml:"timeout_height"`
+		AuditLog      string `toml:"audit_log"`
+	}
+
+	// AutoThresholds defines the deviation thresholds derived from the
+	// historical dispersion of the provider prices, used for all denoms
+	// without a configured deviation threshold.
+	AutoThresholds struct {
+		Enabled    bool   `toml:"enabled"`
+		Window     string `toml:"window"`
+		Interval   string `toml:"interval"`
+		Multiplier string `toml:"multiplier"`
+		MinSamples int    `toml:"min_samples"`
 	}
 
 	ProviderEndpoints struct {
@@ -232,11 +421,71 @@ type (
 		VolumePause  int            `toml:"volume_pause"`
 		Decimals     map[string]int `toml:"decimals"`
 		Periods      map[string]int
+		Signers      []string `toml:"signers"`
+		ChainId      string   `toml:"chain_id"`
+		MaxBlockLag  uint64   `toml:"max_block_lag"`
+		Token        string   `toml:"token"`
+		MaxEventAge  string   `toml:"max_event_age"`
+		// Perp uses the mark prices of perpetual swap markets (bybit, okx)
+		Perp           bool   `toml:"perp"`
+		MaxFundingRate string `toml:"max_funding_rate"`
+		// Candles polls 1m candles to price the pairs with their TVWAP
+		// (binance, kraken, okx)
+		Candles bool `toml:"candles"`
+		// PriceSource "trades" prices the pairs with the VWAP of the
+		// websocket trade stream instead of the tickers (kucoin)
+		PriceSource string `toml:"price_source"`
+		// WebsocketCompression is "permessage-deflate", or "gzip" and
+		// "deflate" for exchanges sending compressed binary frames
+		WebsocketCompression string `toml:"websocket_compression"`
+		// IPVersion restricts the connections to "ipv4" or "ipv6",
+		// otherwise both are dialed happy-eyeballs style
+		IPVersion string `toml:"ip_version"`
+		// ApiKey is sent by providers with keyed api tiers, e.g. coingecko
+		ApiKey string `toml:"api_key"`
+		// Headers are sent with every http request and websocket dial,
+		// e.g. api keys or a custom User-Agent
+		Headers map[string]string `toml:"headers"`
 	}
 
 	UrlSet struct {
 		Urls []string `toml:"urls"`
 	}
+
+	// Comparison defines sources, e.g. other oracle networks, the computed
+	// prices are compared against. These sources are never used for voting.
+	Comparison struct {
+		Providers []provider.Name `toml:"providers"`
+		Threshold string          `toml:"threshold"`
+	}
+
+	// Blacklist defines provider pairs, that are excluded from the price
+	// calculation. Additionally, pairs deviating more than max_deviation
+	// from the computed price for `strikes` consecutive times, are blacklisted
+	// for the configured duration. Runtime blacklisting is disabled, if
+	// strikes is 0. Pairs without ticker for delist_after are dropped until
+	// restart, delisting is disabled, if delist_after is 0.
+	Blacklist struct {
+		Providers    map[string][]string `toml:"providers"`
+		MaxDeviation string              `toml:"max_deviation"`
+		Strikes      int                 `toml:"strikes"`
+		Duration     string              `toml:"duration"`
+		DelistAfter  string              `toml:"delist_after"`
+	}
+
+	// VoteLog defines the directory, successfully broadcasted votes are
+	// written to for accounting. A new file is created every day.
+	VoteLog struct {
+		Dir    string `toml:"dir"`
+		Format string `toml:"format"`
+	}
+
+	// TickerHistory defines whether every ticker collected for a vote is
+	// stored in the history db and how long it is kept.
+	TickerHistory struct {
+		Enabled   bool   `toml:"enabled"`
+		Retention string `toml:"retention"`
+	}
 )
 
 // telemetryValidation is custom validation for the Telemetry struct.
@@ -260,12 +509,37 @@ func endpointValidation(sl validator.StructLevel) {
 		sl.ReportError(endpoint.Name, "urls", "Urls", "urls or url_set empty", "")
 	}
 
-	if _, ok := SupportedProviders[endpoint.Name]; !ok {
+	if !provider.IsRegistered(endpoint.Name) {
 		sl.ReportError(endpoint.Name, "name", "Name", "unsupportedEndpointProvider", "")
 	}
 }
 
 // Validate returns an error if the Config object is invalid.
+// TMRPC returns the Tendermint RPC endpoints in order of preference,
+// tmrpc_endpoint followed by tmrpc_endpoints.
+func (rpc RPC) TMRPC() []string {
+	return joinEndpoints(rpc.TMRPCEndpoint, rpc.TMRPCEndpoints)
+}
+
+// GRPC returns the gRPC endpoints in order of preference, grpc_endpoint
+// followed by grpc_endpoints.
+func (rpc RPC) GRPC() []string {
+	return joinEndpoints(rpc.GRPCEndpoint, rpc.GRPCEndpoints)
+}
+
+func joinEndpoints(endpoint string, endpoints []string) []string {
+	joined := []string{}
+	seen := map[string]struct{}{}
+	for _, e := range append([]string{endpoint}, endpoints...) {
+		if _, found := seen[e]; found || e == "" {
+			continue
+		}
+		seen[e] = struct{}{}
+		joined = append(joined, e)
+	}
+	return joined
+}
+
 func (c Config) Validate() error {
 	validate.RegisterStructValidation(telemetryValidation, Telemetry{})
 	validate.RegisterStructValidation(endpointValidation, ProviderEndpoints{})
@@ -284,6 +558,46 @@ func (p ProviderEndpoints) ToEndpoint(
 		pollInterval = interval
 	}
 
+	var maxEventAge time.Duration
+	if p.MaxEventAge != "" {
+		age, err := time.ParseDuration(p.MaxEventAge)
+		if err != nil {
+			return provider.Endpoint{}, fmt.Errorf("failed to parse max event age: %v", err)
+		}
+		maxEventAge = age
+	}
+
+	var maxFundingRate sdk.Dec
+	if p.MaxFundingRate != "" {
+		rate, err := sdk.NewDecFromStr(p.MaxFundingRate)
+		if err != nil || !rate.IsPositive() {
+			return provider.Endpoint{}, fmt.Errorf("invalid max funding rate: %s", p.MaxFundingRate)
+		}
+		maxFundingRate = rate
+	}
+
+	if p.WebsocketCompression != "" && !provider.IsWebsocketCompression(p.WebsocketCompression) {
+		return provider.Endpoint{}, fmt.Errorf(
+			"invalid websocket compression: %s", p.WebsocketCompression,
+		)
+	}
+
+	if p.IPVersion != "" && !provider.IsIPVersion(p.IPVersion) {
+		return provider.Endpoint{}, fmt.Errorf("invalid ip version: %s", p.IPVersion)
+	}
+
+	switch p.PriceSource {
+	case "", provider.PriceSourceTicker, provider.PriceSourceTrades:
+	default:
+		return provider.Endpoint{}, fmt.Errorf("invalid price source: %s", p.PriceSource)
+	}
+
+	for key := range p.Headers {
+		if strings.TrimSpace(key) == "" {
+			return provider.Endpoint{}, fmt.Errorf("empty header name for '%s'", p.Name)
+		}
+	}
+
 	urls := p.Urls
 	set, found := sets[p.UrlSet]
 	if found {
@@ -296,15 +610,29 @@ func (p ProviderEndpoints) ToEndpoint(
 	}
 
 	e := provider.Endpoint{
-		Name:          p.Name,
-		Urls:          urls,
-		Websocket:     p.Websocket,
-		WebsocketPath: p.WebsocketPath,
-		PollInterval:  pollInterval,
-		VolumeBlocks:  p.VolumeBlocks,
-		VolumePause:   p.VolumePause,
-		Decimals:      p.Decimals,
-		Periods:       p.Periods,
+		Name:           p.Name,
+		Urls:           urls,
+		Websocket:      p.Websocket,
+		WebsocketPath:  p.WebsocketPath,
+		PollInterval:   pollInterval,
+		VolumeBlocks:   p.VolumeBlocks,
+		VolumePause:    p.VolumePause,
+		Decimals:       p.Decimals,
+		Periods:        p.Periods,
+		Signers:        p.Signers,
+		ChainId:        p.ChainId,
+		MaxBlockLag:    p.MaxBlockLag,
+		Token:          p.Token,
+		MaxEventAge:    maxEventAge,
+		Perp:           p.Perp,
+		MaxFundingRate: maxFundingRate,
+		ApiKey:         p.ApiKey,
+		Headers:        p.Headers,
+		Candles:        p.Candles,
+		PriceSource:    p.PriceSource,
+
+		WebsocketCompression: p.WebsocketCompression,
+		IPVersion:            p.IPVersion,
 	}
 	return e, nil
 }
@@ -323,9 +651,11 @@ func ParseConfig(configPath string) (Config, error) {
 		return cfg, fmt.Errorf("failed to read config: %w", err)
 	}
 
-	if _, err := toml.Decode(string(configData), &cfg); err != nil {
+	md, err := toml.Decode(string(configData), &cfg)
+	if err != nil {
 		return cfg, fmt.Errorf("failed to decode config: %w", err)
 	}
+	cfg.UnknownKeys = unknownKeys(md, cfg)
 
 	if cfg.Server.ListenAddr == "" {
 		cfg.Server.ListenAddr = defaultListenAddr
@@ -378,11 +708,14 @@ func ParseConfig(configPath string) (Config, error) {
 				return cfg, fmt.Errorf("cannot combine derivative and nonderivative pairs for %s", cp.Base)
 			}
 		}
-		for _, provider := range cp.Providers {
-			if _, ok := SupportedProviders[provider]; !ok {
-				return cfg, fmt.Errorf("unsupported provider: %s", provider)
+		for _, name := range cp.Providers {
+			if !provider.IsRegistered(name) {
+				return cfg, fmt.Errorf("unsupported provider: %s", name)
 			}
-			pairs[cp.Base][provider] = struct{}{}
+			if provider.IsComparisonOnly(name) {
+				return cfg, fmt.Errorf("provider %s can only be used for comparison", name)
+			}
+			pairs[cp.Base][name] = struct{}{}
 		}
 	}
 
@@ -397,6 +730,183 @@ func ParseConfig(configPath string) (Config, error) {
 		}
 	}
 
+	for _, name := range cfg.Comparison.Providers {
+		if !provider.IsRegistered(name) {
+			return cfg, fmt.Errorf("unsupported comparison provider: %s", name)
+		}
+	}
+
+	if cfg.Comparison.Threshold != "" {
+		threshold, err := sdk.NewDecFromStr(cfg.Comparison.Threshold)
+		if err != nil {
+			return cfg, fmt.Errorf("comparison threshold must be numeric: %w", err)
+		}
+
+		if !threshold.IsPositive() {
+			return cfg, fmt.Errorf("comparison threshold must be greater than 0")
+		}
+	}
+
+	for name := range cfg.Blacklist.Providers {
+		if !provider.IsRegistered(provider.Name(name)) {
+			return cfg, fmt.Errorf("unsupported blacklist provider: %s", name)
+		}
+	}
+
+	if cfg.Blacklist.MaxDeviation == "" {
+		cfg.Blacklist.MaxDeviation = defaultBlacklistDeviation
+	}
+	if _, err := sdk.NewDecFromStr(cfg.Blacklist.MaxDeviation); err != nil {
+		return cfg, fmt.Errorf("blacklist max_deviation must be numeric: %w", err)
+	}
+
+	if cfg.Telemetry.PriceMetricsInterval != "" {
+		if _, err := time.ParseDuration(cfg.Telemetry.PriceMetricsInterval); err != nil {
+			return cfg, fmt.Errorf("failed to parse price metrics interval: %w", err)
+		}
+	}
+
+	if cfg.Blacklist.Duration == "" {
+		cfg.Blacklist.Duration = defaultBlacklistDuration.String()
+	}
+	if _, err := time.ParseDuration(cfg.Blacklist.Duration); err != nil {
+		return cfg, fmt.Errorf("failed to parse blacklist duration: %w", err)
+	}
+
+	if cfg.Blacklist.DelistAfter == "" {
+		cfg.Blacklist.DelistAfter = defaultDelistAfter.String()
+	}
+	if _, err := time.ParseDuration(cfg.Blacklist.DelistAfter); err != nil {
+		return cfg, fmt.Errorf("failed to parse blacklist delist_after: %w", err)
+	}
+
+	if cfg.TickerHistory.Retention == "" {
+		cfg.TickerHistory.Retention = defaultTickerRetention.String()
+	}
+	retention, err := time.ParseDuration(cfg.TickerHistory.Retention)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to parse ticker_history retention: %w", err)
+	}
+	if retention <= 0 {
+		return cfg, fmt.Errorf("ticker_history retention must be greater than 0")
+	}
+
+	if cfg.Blacklist.Strikes < 0 {
+		return cfg, fmt.Errorf("blacklist strikes must not be negative")
+	}
+
+	if cfg.VoteLog.Format == "" {
+		cfg.VoteLog.Format = "csv"
+	}
+	if cfg.VoteLog.Format != "csv" && cfg.VoteLog.Format != "jsonl" {
+		return cfg, fmt.Errorf("unsupported vote log format: %s", cfg.VoteLog.Format)
+	}
+
+	for _, healthcheck := range cfg.Healthchecks {
+		if _, err := time.ParseDuration(healthcheck.Timeout); err != nil {
+			return cfg, fmt.Errorf("failed to parse healthcheck timeout: %w", err)
+		}
+		for _, event := range healthcheck.Events {
+			switch event {
+			case HealthcheckSuccess, HealthcheckFail, HealthcheckStart, HealthcheckStop:
+			default:
+				return cfg, fmt.Errorf("unsupported healthcheck event: %s", event)
+			}
+		}
+	}
+
+	if cfg.Bot.Telegram.Token != "" && len(cfg.Bot.Telegram.ChatIDs) == 0 {
+		return cfg, fmt.Errorf("telegram bot requires at least one chat id")
+	}
+	if cfg.Bot.Discord.Token != "" && len(cfg.Bot.Discord.ChannelIDs) == 0 {
+		return cfg, fmt.Errorf("discord bot requires at least one channel id")
+	}
+
+	if err := validateReport(cfg.Report); err != nil {
+		return cfg, err
+	}
+
+	if err := validateLeader(cfg.Leader); err != nil {
+		return cfg, err
+	}
+
+	if err := validateVoter(cfg.Voter); err != nil {
+		return cfg, err
+	}
+
+	if err := validateGovernance(cfg.Governance); err != nil {
+		return cfg, err
+	}
+
+	if err := validateGRPC(cfg.GRPC); err != nil {
+		return cfg, err
+	}
+
+	if err := validateSecrets(cfg.Secrets); err != nil {
+		return cfg, err
+	}
+
+	if err := validateUpdateCheck(cfg.UpdateCheck); err != nil {
+		return cfg, err
+	}
+
+	if err := validateTiming(cfg.Timing); err != nil {
+		return cfg, err
+	}
+
+	if len(cfg.RPC.TMRPC()) == 0 || len(cfg.RPC.GRPC()) == 0 {
+		return cfg, fmt.Errorf("rpc requires at least one tmrpc and grpc endpoint")
+	}
+
+	if cfg.Limits.MemoryLimitMiB < 0 || cfg.Limits.MemoryThresholdMiB < 0 ||
+		cfg.Limits.MaxProcs < 0 || cfg.Limits.MaxGoroutines < 0 {
+		return cfg, fmt.Errorf("limits must not be negative")
+	}
+
+	if err := validateAutoThresholds(cfg.AutoThresholds); err != nil {
+		return cfg, err
+	}
+
+	if cfg.Whitelist.AutoSubscribe && cfg.Whitelist.Registry == "" {
+		return cfg, fmt.Errorf("whitelist auto_subscribe requires a registry")
+	}
+
+	switch cfg.LiquidityWeighting {
+	case "", "volume", "liquidity", "combined":
+	default:
+		return cfg, fmt.Errorf(
+			"unknown liquidity weighting: %s", cfg.LiquidityWeighting,
+		)
+	}
+
+	switch cfg.RatesFormat.Order {
+	case "", "denom", "whitelist":
+	default:
+		return cfg, fmt.Errorf("unknown rates order: %s", cfg.RatesFormat.Order)
+	}
+
+	switch cfg.RatesFormat.Case {
+	case "", "upper", "lower":
+	default:
+		return cfg, fmt.Errorf("unknown rates case: %s", cfg.RatesFormat.Case)
+	}
+
+	if len(cfg.Tx.Memo) > maxMemoLength {
+		return cfg, fmt.Errorf("tx memo must not exceed %d characters", maxMemoLength)
+	}
+
+	priceExponents := make(map[string]int, len(cfg.PriceExponents))
+	for denom, exponent := range cfg.PriceExponents {
+		if exponent < -sdk.Precision || exponent > sdk.Precision {
+			return cfg, fmt.Errorf(
+				"price exponent of %s must be between %d and %d",
+				denom, -sdk.Precision, sdk.Precision,
+			)
+		}
+		priceExponents[strings.ToUpper(denom)] = exponent
+	}
+	cfg.PriceExponents = priceExponents
+
 	for _, override := range cfg.ProviderMinOverrides {
 		if override.Providers < 1 {
 			return cfg, fmt.Errorf("minimum providers must be greater than 0")
@@ -405,3 +915,261 @@ func ParseConfig(configPath string) (Config, error) {
 
 	return cfg, cfg.Validate()
 }
+
+func validateReport(report Report) error {
+	if report.URL == "" {
+		return nil
+	}
+
+	reportURL, err := url.Parse(report.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse report url: %w", err)
+	}
+	if reportURL.Scheme != "https" {
+		return fmt.Errorf("report url must use https")
+	}
+
+	for name, value := range map[string]string{
+		"interval":      report.Interval,
+		"push_interval": report.PushInterval,
+		"timeout":       report.Timeout,
+	} {
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse report %s: %w", name, err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("report %s must be greater than 0", name)
+		}
+	}
+
+	return nil
+}
+
+func validateLeader(leader Leader) error {
+	for _, peer := range leader.Peers {
+		if _, err := url.Parse(peer); err != nil {
+			return fmt.Errorf("failed to parse leader peer url: %w", err)
+		}
+	}
+
+	for name, value := range map[string]string{
+		"interval":       leader.Interval,
+		"failover_after": leader.FailoverAfter,
+	} {
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse leader %s: %w", name, err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("leader %s must be greater than 0", name)
+		}
+	}
+
+	if leader.Interval != "" && leader.FailoverAfter != "" {
+		interval, _ := time.ParseDuration(leader.Interval)
+		failoverAfter, _ := time.ParseDuration(leader.FailoverAfter)
+		if failoverAfter <= interval {
+			return fmt.Errorf("leader failover_after must be greater than interval")
+		}
+	}
+
+	return nil
+}
+
+func validateGovernance(governance Governance) error {
+	if governance.Interval == "" {
+		return nil
+	}
+
+	duration, err := time.ParseDuration(governance.Interval)
+	if err != nil {
+		return fmt.Errorf("failed to parse governance interval: %w", err)
+	}
+	if duration <= 0 {
+		return fmt.Errorf("governance interval must be greater than 0")
+	}
+
+	return nil
+}
+
+func validateGRPC(grpc GRPC) error {
+	if grpc.ListenAddr == "" {
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(grpc.ListenAddr); err != nil {
+		return fmt.Errorf("invalid grpc listen address: %w", err)
+	}
+
+	return nil
+}
+
+func validateVoter(voter Voter) error {
+	if voter.CollectorURL == "" {
+		return nil
+	}
+
+	if _, err := url.Parse(voter.CollectorURL); err != nil {
+		return fmt.Errorf("failed to parse voter collector url: %w", err)
+	}
+	if voter.SigningKey == "" {
+		return fmt.Errorf("voter requires the signing key of the collector")
+	}
+
+	for name, value := range map[string]string{
+		"max_age": voter.MaxAge,
+		"timeout": voter.Timeout,
+	} {
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse voter %s: %w", name, err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("voter %s must be greater than 0", name)
+		}
+	}
+
+	return nil
+}
+
+func validateUpdateCheck(check UpdateCheck) error {
+	if !check.Enabled {
+		return nil
+	}
+
+	if check.URL == "" {
+		return fmt.Errorf("update check requires a manifest url")
+	}
+	if _, err := url.Parse(check.URL); err != nil {
+		return fmt.Errorf("failed to parse update check url: %w", err)
+	}
+
+	for name, value := range map[string]string{
+		"interval": check.Interval,
+		"timeout":  check.Timeout,
+	} {
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse update check %s: %w", name, err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("update check %s must be greater than 0", name)
+		}
+	}
+
+	return nil
+}
+
+func validateAutoThresholds(thresholds AutoThresholds) error {
+	for name, value := range map[string]string{
+		"window":   thresholds.Window,
+		"interval": thresholds.Interval,
+	} {
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse auto thresholds %s: %w", name, err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("auto thresholds %s must be greater than 0", name)
+		}
+	}
+
+	if thresholds.Multiplier != "" {
+		multiplier, err := sdk.NewDecFromStr(thresholds.Multiplier)
+		if err != nil {
+			return fmt.Errorf("failed to parse auto thresholds multiplier: %w", err)
+		}
+		if !multiplier.IsPositive() {
+			return fmt.Errorf("auto thresholds multiplier must be greater than 0")
+		}
+	}
+
+	if thresholds.MinSamples < 0 {
+		return fmt.Errorf("auto thresholds min_samples must not be negative")
+	}
+
+	return nil
+}
+
+func validateTiming(timing Timing) error {
+	intervals := []struct {
+		name  string
+		value string
+	}{
+		{"tick_interval", timing.TickInterval},
+		{"price_interval", timing.PriceInterval},
+		{"healthcheck_interval", timing.HealthcheckInterval},
+		{"max_clock_drift", timing.MaxClockDrift},
+		{"warmup_timeout", timing.WarmupTimeout},
+	}
+
+	parsed := make(map[string]time.Duration, len(intervals))
+	for _, interval := range intervals {
+		if interval.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(interval.value)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", interval.name, err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("%s must be greater than 0", interval.name)
+		}
+		parsed[interval.name] = duration
+	}
+
+	tick, found := parsed["tick_interval"]
+	if !found {
+		tick = defaultTickInterval
+	}
+
+	if tick < minTickInterval || tick > maxTickInterval {
+		return fmt.Errorf(
+			"tick_interval must be between %s and %s", minTickInterval, maxTickInterval,
+		)
+	}
+
+	if prices, found := parsed["price_interval"]; found && prices < tick {
+		return fmt.Errorf("price_interval must not be shorter than tick_interval")
+	}
+
+	if timing.WarmupTicks < 0 {
+		return fmt.Errorf("warmup_ticks must not be negative")
+	}
+	if timing.PrevoteTimeoutBlocks < 0 {
+		return fmt.Errorf("prevote_timeout_blocks must not be negative")
+	}
+	if timing.VoteTimeoutBlocks < 0 {
+		return fmt.Errorf("vote_timeout_blocks must not be negative")
+	}
+	if timing.StallVotePeriods < 0 {
+		return fmt.Errorf("stall_vote_periods must not be negative")
+	}
+	if timing.WarmupCoverage != "" {
+		coverage, err := strconv.ParseFloat(timing.WarmupCoverage, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse warmup_coverage: %w", err)
+		}
+		if coverage <= 0 || coverage > 1 {
+			return fmt.Errorf("warmup_coverage must be between 0 and 1")
+		}
+	}
+
+	return nil
+}
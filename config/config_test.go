@@ -3,6 +3,7 @@ package config_test
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"price-feeder/config"
@@ -551,3 +552,96 @@ global_labels = [["chain-id", "umee-local-testnet"]]
 	_, err = config.ParseConfig(tmpFile.Name())
 	require.Error(t, err)
 }
+
+func TestParseConfig_UnknownKeys(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	content := []byte(`
+gas_adjustment = 1.5
+gas_prices = "0.00125ukuji"
+enable_votr = true
+
+[server]
+listen_adr = "0.0.0.0:99999"
+
+[[currency_pairs]]
+base = "USDT"
+quote = "USD"
+providers = ["kraken", "binance", "huobi"]
+
+[[deviation_threshold]]
+base = "USDT"
+threshold = "2"
+
+[account]
+address = "kujira15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "kujiravalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "kujira-local-testnet"
+prefix = "kujira"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.kujira"
+pass = "keyringPassword"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+`)
+	_, err = tmpFile.Write(content)
+	require.NoError(t, err)
+
+	cfg, err := config.ParseConfig(tmpFile.Name())
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []config.UnknownKey{
+		{Key: "enable_votr", Suggestion: "enable_voter"},
+		{Key: "server.listen_adr", Suggestion: "server.listen_addr"},
+		{Key: "deviation_threshold", Suggestion: "deviation_thresholds"},
+		{Key: "keyring.pass"},
+	}, cfg.UnknownKeys)
+	require.Equal(t,
+		"deviation_threshold (did you mean deviation_thresholds?)",
+		config.UnknownKey{Key: "deviation_threshold", Suggestion: "deviation_thresholds"}.String(),
+	)
+}
+
+func TestConfig_ExpandStrings(t *testing.T) {
+	cfg := config.Config{
+		Report: config.Report{
+			Token:   "${secret:report}",
+			Headers: map[string]string{"X-Key": "${secret:header}"},
+		},
+		ProviderEndpoints: []config.ProviderEndpoints{
+			{Name: "binance", Urls: []string{"https://example.com/${secret:key}"}},
+		},
+		Secrets: config.Secrets{
+			Refs: map[string]string{"key": "${secret:kept}"},
+		},
+	}
+
+	err := cfg.ExpandStrings(func(value string) (string, error) {
+		return strings.ReplaceAll(value, "${secret:", "resolved:"), nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "resolved:report}", cfg.Report.Token)
+	require.Equal(t, "resolved:header}", cfg.Report.Headers["X-Key"])
+	require.Equal(t, "https://example.com/resolved:key}", cfg.ProviderEndpoints[0].Urls[0])
+	require.Equal(t, "${secret:kept}", cfg.Secrets.Refs["key"])
+}
+
+func TestRPC_Endpoints(t *testing.T) {
+	rpc := config.RPC{
+		TMRPCEndpoint:  "http://node1:26657",
+		TMRPCEndpoints: []string{"http://node2:26657", "http://node1:26657"},
+		GRPCEndpoints:  []string{"node1:9090", "node2:9090"},
+	}
+
+	require.Equal(t, []string{"http://node1:26657", "http://node2:26657"}, rpc.TMRPC())
+	require.Equal(t, []string{"node1:9090", "node2:9090"}, rpc.GRPC())
+	require.Empty(t, config.RPC{}.TMRPC())
+}
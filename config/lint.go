@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// UnknownKey defines a key of the config file, that doesn't match any
+// config option and is ignored.
+type UnknownKey struct {
+	Key        string
+	Suggestion string
+}
+
+// String returns the key with the suggested valid key, if any.
+func (k UnknownKey) String() string {
+	if k.Suggestion == "" {
+		return k.Key
+	}
+	return fmt.Sprintf("%s (did you mean %s?)", k.Key, k.Suggestion)
+}
+
+// unknownKeys returns all keys of the config file, that were not decoded
+// into v, together with the closest valid key at the same level. Keys of
+// unknown tables are only reported once, for the table itself.
+func unknownKeys(md toml.MetaData, v interface{}) []UnknownKey {
+	undecoded := md.Undecoded()
+	reported := map[string]struct{}{}
+	unknown := []UnknownKey{}
+
+	for _, key := range undecoded {
+		parentReported := false
+		for i := 1; i < len(key); i++ {
+			if _, found := reported[key[:i].String()]; found {
+				parentReported = true
+				break
+			}
+		}
+		if parentReported {
+			continue
+		}
+		reported[key.String()] = struct{}{}
+
+		parent := key[:len(key)-1]
+		name := key[len(key)-1]
+
+		suggestion := ""
+		if t, found := typeAt(reflect.TypeOf(v), parent); found {
+			candidate := closestKey(name, tomlKeys(t))
+			if candidate != "" {
+				suggestion = strings.Join(append(append([]string{}, parent...), candidate), ".")
+			}
+		}
+
+		unknown = append(unknown, UnknownKey{
+			Key:        key.String(),
+			Suggestion: suggestion,
+		})
+	}
+
+	return unknown
+}
+
+// typeAt returns the struct type of the table at the path.
+func typeAt(t reflect.Type, path []string) (reflect.Type, bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	if len(path) == 0 {
+		return t, t.Kind() == reflect.Struct
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if strings.EqualFold(tomlName(field), path[0]) {
+				return typeAt(field.Type, path[1:])
+			}
+		}
+	case reflect.Map:
+		return typeAt(t.Elem(), path[1:])
+	}
+
+	return nil, false
+}
+
+// tomlKeys returns the keys of all fields of the struct type.
+func tomlKeys(t reflect.Type) []string {
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := tomlName(t.Field(i))
+		if name == "-" || !t.Field(i).IsExported() {
+			continue
+		}
+		keys = append(keys, name)
+	}
+	return keys
+}
+
+func tomlName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("toml"), ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// closestKey returns the candidate with the smallest edit distance to the
+// key, if it is close enough to be a typo.
+func closestKey(key string, candidates []string) string {
+	key = strings.ToLower(key)
+
+	maxDistance := len(key) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	closest := ""
+	closestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		distance := editDistance(key, strings.ToLower(candidate))
+		if distance < closestDistance {
+			closest = candidate
+			closestDistance = distance
+		}
+	}
+
+	return closest
+}
+
+// editDistance returns the Levenshtein distance of the strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
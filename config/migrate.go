@@ -0,0 +1,104 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+)
+
+// removedOptions maps options of older releases, that are no longer read,
+// to the description of the change.
+var removedOptions = map[string]string{
+	"provider_min_override": "removed, replaced by provider_min_overrides (default 3 providers)",
+}
+
+// Migration describes a single change applied to an old config.
+type Migration struct {
+	Key         string
+	Description string
+}
+
+// String returns the key and the description of the change.
+func (m Migration) String() string {
+	return fmt.Sprintf("%s: %s", m.Key, m.Description)
+}
+
+// MigrateConfig upgrades a config file of an older release to the current
+// format. It returns the new config file and the applied changes. Comments
+// and the order of the keys are not preserved.
+func MigrateConfig(content []byte) ([]byte, []Migration, error) {
+	cfg := map[string]interface{}{}
+	if _, err := toml.Decode(string(content), &cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	m := &migrator{}
+	m.migrate(cfg)
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	return buf.Bytes(), m.migrations, nil
+}
+
+type migrator struct {
+	migrations []Migration
+}
+
+func (m *migrator) add(key, format string, args ...interface{}) {
+	m.migrations = append(m.migrations, Migration{
+		Key:         key,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+func (m *migrator) migrate(cfg map[string]interface{}) {
+	for _, key := range sortedKeys(cfg) {
+		if description, found := removedOptions[key]; found {
+			delete(cfg, key)
+			m.add(key, "%s", description)
+		}
+	}
+
+	for i, endpoint := range tables(cfg["provider_endpoints"]) {
+		key := fmt.Sprintf("provider_endpoints[%d]", i)
+
+		if _, found := endpoint["contracts"]; found {
+			delete(endpoint, "contracts")
+			m.add(
+				key+".contracts",
+				"removed, set the addresses in contract_addresses.%s", endpoint["name"],
+			)
+		}
+	}
+}
+
+// tables returns the tables of an array of tables.
+func tables(value interface{}) []map[string]interface{} {
+	switch v := value.(type) {
+	case []map[string]interface{}:
+		return v
+	case []interface{}:
+		tables := []map[string]interface{}{}
+		for _, item := range v {
+			if table, ok := item.(map[string]interface{}); ok {
+				tables = append(tables, table)
+			}
+		}
+		return tables
+	}
+	return nil
+}
+
+func sortedKeys(table map[string]interface{}) []string {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
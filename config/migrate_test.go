@@ -0,0 +1,73 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"price-feeder/config"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	content := []byte(`
+gas_adjustment = 1.5
+provider_min_override = 1
+
+[[currency_pairs]]
+base = "STATOM"
+quote = "ATOM"
+providers = ["osmosisv2"]
+
+[[provider_endpoints]]
+name = "osmosisv2"
+urls = ["https://lcd.osmosis.zone"]
+
+[[provider_endpoints]]
+name = "finv2"
+urls = ["https://lcd.kaiyo.kujira.setten.io"]
+contracts = ["kujira14hj2tavq8fpesdwxxcu44rty3hh90vhujrvcmstl4zr3txmfvw9sl4e867"]
+`)
+
+	migrated, migrations, err := config.MigrateConfig(content)
+	require.NoError(t, err)
+
+	var cfg config.Config
+	md, err := toml.Decode(string(migrated), &cfg)
+	require.NoError(t, err)
+	require.Empty(t, md.Undecoded())
+
+	require.Equal(t, 1.5, cfg.GasAdjustment)
+	require.Len(t, cfg.CurrencyPairs, 1)
+	require.Equal(t, "osmosisv2", cfg.CurrencyPairs[0].Providers[0].String())
+	require.Len(t, cfg.ProviderEndpoints, 2)
+
+	descriptions := []string{}
+	for _, migration := range migrations {
+		descriptions = append(descriptions, migration.String())
+	}
+	require.Equal(t, []string{
+		"provider_min_override: removed, replaced by provider_min_overrides (default 3 providers)",
+		"provider_endpoints[1].contracts: removed, set the addresses in contract_addresses.finv2",
+	}, descriptions)
+}
+
+func TestMigrateConfigCurrent(t *testing.T) {
+	content, err := os.ReadFile("../config.example.toml")
+	require.NoError(t, err)
+
+	migrated, migrations, err := config.MigrateConfig(content)
+	require.NoError(t, err)
+	require.Empty(t, migrations)
+
+	expected := map[string]interface{}{}
+	_, err = toml.Decode(string(content), &expected)
+	require.NoError(t, err)
+
+	actual := map[string]interface{}{}
+	_, err = toml.Decode(string(migrated), &actual)
+	require.NoError(t, err)
+
+	require.Equal(t, expected, actual)
+}
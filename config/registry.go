@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"price-feeder/oracle/provider"
+
+	"github.com/BurntSushi/toml"
+)
+
+// registryTimeout defines the timeout for fetching a remote pairs registry.
+const registryTimeout = 10 * time.Second
+
+// PairsRegistry defines a list of default currency pairs, in the same format
+// as the currency_pairs of the config, e.g. maintained by the community.
+type PairsRegistry struct {
+	CurrencyPairs []CurrencyPair `toml:"currency_pairs"`
+}
+
+// ReadPairsRegistry reads the registry from a local file or a http(s) url.
+// Pairs with unsupported or comparison only providers are only kept with
+// their supported providers.
+func ReadPairsRegistry(location string) (PairsRegistry, error) {
+	var registry PairsRegistry
+
+	data, err := readRegistry(location)
+	if err != nil {
+		return registry, fmt.Errorf("failed to read pairs registry: %w", err)
+	}
+
+	if _, err := toml.Decode(string(data), &registry); err != nil {
+		return registry, fmt.Errorf("failed to decode pairs registry: %w", err)
+	}
+
+	pairs := make([]CurrencyPair, 0, len(registry.CurrencyPairs))
+	for _, pair := range registry.CurrencyPairs {
+		supported := pair.Providers[:0]
+		for _, name := range pair.Providers {
+			if provider.IsRegistered(name) && !provider.IsComparisonOnly(name) {
+				supported = append(supported, name)
+			}
+		}
+		if pair.Base == "" || pair.Quote == "" || len(supported) == 0 {
+			continue
+		}
+		pair.Base = strings.ToUpper(pair.Base)
+		pair.Quote = strings.ToUpper(pair.Quote)
+		pair.Providers = supported
+		pairs = append(pairs, pair)
+	}
+	registry.CurrencyPairs = pairs
+
+	return registry, nil
+}
+
+// Pairs returns all pairs of the base denom.
+func (r PairsRegistry) Pairs(base string) []CurrencyPair {
+	pairs := []CurrencyPair{}
+	for _, pair := range r.CurrencyPairs {
+		if pair.Base == strings.ToUpper(base) {
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
+
+func readRegistry(location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return os.ReadFile(location)
+	}
+
+	client := http.Client{Timeout: registryTimeout}
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var secretBackends = map[string]struct{}{
+	"vault": {},
+	"aws":   {},
+	"gcp":   {},
+}
+
+func validateSecrets(secrets Secrets) error {
+	refs := map[string]string{}
+	for name, ref := range secrets.Refs {
+		refs["refs."+name] = ref
+	}
+	if secrets.KeyringPassword != "" {
+		refs["keyring_password"] = secrets.KeyringPassword
+	}
+
+	for name, ref := range refs {
+		backend, path, ok := strings.Cut(ref, ":")
+		if !ok || path == "" {
+			return fmt.Errorf("invalid secret reference %s: %s", name, ref)
+		}
+		if _, ok := secretBackends[backend]; !ok {
+			return fmt.Errorf("unsupported secret backend of %s: %s", name, backend)
+		}
+	}
+
+	if secrets.Timeout != "" {
+		timeout, err := time.ParseDuration(secrets.Timeout)
+		if err != nil {
+			return fmt.Errorf("failed to parse secrets timeout: %w", err)
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("secrets timeout must be greater than 0")
+		}
+	}
+
+	return nil
+}
+
+// ExpandStrings replaces all string values of the config, except the
+// secrets section itself, with the result of expand. It is used to resolve
+// secret placeholders after the config is parsed.
+func (c *Config) ExpandStrings(expand func(string) (string, error)) error {
+	return expandValue(reflect.ValueOf(c).Elem(), expand)
+}
+
+func expandValue(value reflect.Value, expand func(string) (string, error)) error {
+	switch value.Kind() {
+	case reflect.String:
+		if !value.CanSet() {
+			return nil
+		}
+		expanded, err := expand(value.String())
+		if err != nil {
+			return err
+		}
+		value.SetString(expanded)
+
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !field.IsExported() || field.Name == "Secrets" {
+				continue
+			}
+			if err := expandValue(value.Field(i), expand); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := expandValue(value.Index(i), expand); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			// map values aren't addressable, expand a copy
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			if err := expandValue(elem, expand); err != nil {
+				return err
+			}
+			value.SetMapIndex(iter.Key(), elem)
+		}
+
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			return expandValue(value.Elem(), expand)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+const (
+	// defaultImage is the kujirad image built from the Dockerfile of this
+	// directory, see `make test-e2e`.
+	defaultImage = "price-feeder-e2e-kujirad"
+
+	votePeriod = 5
+	// periods is the number of vote periods the votes are observed.
+	periods = 4
+)
+
+// mockPrices are served to the mock provider of the feeder.
+var mockPrices = map[string]string{
+	"ATOM": "9.874",
+	"BTC":  "43250.5",
+}
+
+// TestVotes starts a single node chain in docker, runs the feeder with the
+// mock provider against it and asserts that its votes land every period.
+func TestVotes(t *testing.T) {
+	image := os.Getenv("E2E_IMAGE")
+	if image == "" {
+		image = defaultImage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	home := t.TempDir()
+	chainHome := filepath.Join(home, "chain")
+	require.NoError(t, os.Mkdir(chainHome, 0o755))
+
+	container := startChain(t, ctx, image, chainHome)
+	tmrpcEndpoint := "http://" + containerPort(t, container, 26657)
+	grpcEndpoint := containerPort(t, container, 9090)
+
+	address := waitForFile(t, filepath.Join(chainHome, "validator.address"))
+	valoper := waitForFile(t, filepath.Join(chainHome, "validator.valoper"))
+	waitForBlocks(t, ctx, tmrpcEndpoint)
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, "base,quote,price,volume")
+			for denom, price := range mockPrices {
+				fmt.Fprintf(w, "%s,USD,%s,1000\n", denom, price)
+			}
+		},
+	))
+	defer server.Close()
+
+	configPath := filepath.Join(home, "config.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte(fmt.Sprintf(
+		feederConfig,
+		filepath.Join(home, "feeder.db"),
+		grpcEndpoint,
+		tmrpcEndpoint,
+		address,
+		valoper,
+		chainHome,
+		server.URL,
+	)), 0o644))
+
+	startFeeder(t, ctx, home, configPath)
+
+	grpcConn, err := grpc.Dial(grpcEndpoint, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer grpcConn.Close()
+
+	queryClient := oracletypes.NewQueryClient(grpcConn)
+
+	// the periods before the first vote are missed
+	var missCounter uint64
+	rates := map[string]sdk.Dec{}
+	require.Eventually(t, func() bool {
+		for denom := range mockPrices {
+			response, err := queryClient.ExchangeRate(
+				ctx, &oracletypes.QueryExchangeRateRequest{Denom: denom},
+			)
+			if err != nil {
+				return false
+			}
+			rates[denom] = response.ExchangeRate
+		}
+
+		response, err := queryClient.MissCounter(
+			ctx, &oracletypes.QueryMissCounterRequest{ValidatorAddr: valoper},
+		)
+		if err != nil {
+			return false
+		}
+		missCounter = response.MissCounter
+		return true
+	}, 5*time.Minute, time.Second)
+
+	for denom, price := range mockPrices {
+		require.Equal(t, sdk.MustNewDecFromStr(price), rates[denom], denom)
+	}
+
+	// every further period is voted
+	time.Sleep(periods * votePeriod * 1500 * time.Millisecond)
+
+	response, err := queryClient.MissCounter(
+		ctx, &oracletypes.QueryMissCounterRequest{ValidatorAddr: valoper},
+	)
+	require.NoError(t, err)
+	require.Equal(t, missCounter, response.MissCounter)
+}
+
+// feederConfig is the feeder config, the placeholders are the history db,
+// the gRPC and Tendermint RPC endpoints, the feeder and validator
+// addresses, the keyring dir and the url of the mock prices.
+const feederConfig = `
+gas_adjustment = 1.5
+gas_prices = "0.00125ukuji"
+enable_server = false
+enable_voter = true
+history_db = "%s"
+
+[rpc]
+grpc_endpoint = "%s"
+rpc_timeout = "500ms"
+tmrpc_endpoint = "%s"
+
+[account]
+address = "%s"
+chain_id = "e2e-1"
+validator = "%s"
+prefix = "kujira"
+
+[keyring]
+backend = "test"
+dir = "%s"
+
+[[provider_min_overrides]]
+denoms = ["ATOM", "BTC"]
+providers = 1
+
+[[currency_pairs]]
+base = "ATOM"
+quote = "USD"
+providers = ["mock"]
+
+[[currency_pairs]]
+base = "BTC"
+quote = "USD"
+providers = ["mock"]
+
+[[provider_endpoints]]
+name = "mock"
+urls = ["%s"]
+`
+
+// startChain starts the chain container, which is removed at the end of the
+// test. It runs as the current user, so the keyring is readable.
+func startChain(t *testing.T, ctx context.Context, image, chainHome string) string {
+	output, err := exec.CommandContext(
+		ctx,
+		"docker", "run", "-d", "--rm",
+		"--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()),
+		"-e", "VOTE_PERIOD="+strconv.Itoa(votePeriod),
+		"-v", chainHome+":/chain",
+		"-p", "127.0.0.1::26657",
+		"-p", "127.0.0.1::9090",
+		image,
+	).CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	container := strings.TrimSpace(string(output))
+	t.Cleanup(func() {
+		if t.Failed() {
+			logs, _ := exec.Command("docker", "logs", "--tail", "50", container).CombinedOutput()
+			t.Log(string(logs))
+		}
+		_ = exec.Command("docker", "rm", "-f", container).Run()
+	})
+
+	return container
+}
+
+// containerPort returns the host address the port of the container is
+// published on.
+func containerPort(t *testing.T, container string, port int) string {
+	output, err := exec.Command(
+		"docker", "port", container, fmt.Sprintf("%d/tcp", port),
+	).CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	address := strings.Fields(string(output))[0]
+	_, hostPort, err := net.SplitHostPort(address)
+	require.NoError(t, err)
+
+	return net.JoinHostPort("127.0.0.1", hostPort)
+}
+
+func waitForFile(t *testing.T, path string) string {
+	var content string
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+		content = strings.TrimSpace(string(data))
+		return err == nil && content != ""
+	}, 2*time.Minute, time.Second, path)
+	return content
+}
+
+func waitForBlocks(t *testing.T, ctx context.Context, tmrpcEndpoint string) {
+	require.Eventually(t, func() bool {
+		request, err := http.NewRequestWithContext(
+			ctx, http.MethodGet, tmrpcEndpoint+"/block?height=2", nil,
+		)
+		if err != nil {
+			return false
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return false
+		}
+		response.Body.Close()
+		return response.StatusCode == http.StatusOK
+	}, 2*time.Minute, time.Second)
+}
+
+// startFeeder builds and starts the feeder, which is stopped at the end of
+// the test.
+func startFeeder(t *testing.T, ctx context.Context, home, configPath string) {
+	binary := filepath.Join(home, "price-feeder")
+	output, err := exec.CommandContext(ctx, "go", "build", "-o", binary, "..").CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	logPath := filepath.Join(home, "feeder.log")
+	logFile, err := os.Create(logPath)
+	require.NoError(t, err)
+
+	cmd := exec.CommandContext(ctx, binary, configPath)
+	cmd.Env = append(os.Environ(), "PRICE_FEEDER_PASS=test")
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	require.NoError(t, cmd.Start())
+
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		logFile.Close()
+		if t.Failed() {
+			logs, _ := os.ReadFile(logPath)
+			t.Log(string(logs))
+		}
+	})
+}
@@ -0,0 +1,35 @@
+#!/bin/sh
+# Initializes and starts a single node chain with a short vote period. The
+# address and the valoper address of the validator are written to the home
+# directory, which is mounted by the test to read the test keyring.
+set -e
+
+HOME_DIR=/chain
+CHAIN_ID=${CHAIN_ID:-e2e-1}
+VOTE_PERIOD=${VOTE_PERIOD:-5}
+WHITELIST=${WHITELIST:-ATOM,BTC}
+KEYRING="--keyring-backend test --home $HOME_DIR"
+
+kujirad init e2e --chain-id "$CHAIN_ID" --default-denom ukuji --home $HOME_DIR > /dev/null 2>&1
+kujirad keys add validator $KEYRING > /dev/null 2>&1
+kujirad genesis add-genesis-account validator 1000000000000ukuji $KEYRING
+kujirad genesis gentx validator 100000000ukuji --chain-id "$CHAIN_ID" $KEYRING > /dev/null 2>&1
+kujirad genesis collect-gentxs --home $HOME_DIR > /dev/null 2>&1
+
+GENESIS=$HOME_DIR/config/genesis.json
+jq --arg period "$VOTE_PERIOD" --arg whitelist "$WHITELIST" '
+  .app_state.oracle.params.vote_period = $period |
+  .app_state.oracle.params.whitelist = ($whitelist | split(",") | map({name: .}))
+' $GENESIS > $GENESIS.tmp
+mv $GENESIS.tmp $GENESIS
+
+sed -i 's/^timeout_commit = .*/timeout_commit = "1s"/' $HOME_DIR/config/config.toml
+sed -i 's/^minimum-gas-prices = .*/minimum-gas-prices = "0ukuji"/' $HOME_DIR/config/app.toml
+
+kujirad keys show validator -a $KEYRING > $HOME_DIR/validator.address
+kujirad keys show validator -a --bech val $KEYRING > $HOME_DIR/validator.valoper
+
+exec kujirad start \
+  --home $HOME_DIR \
+  --rpc.laddr tcp://0.0.0.0:26657 \
+  --grpc.address 0.0.0.0:9090
@@ -0,0 +1,384 @@
+// Package governance watches governance proposals changing the oracle
+// params, like the whitelist, vote period or slash fraction, so operators
+// aren't caught off guard by parameter changes.
+package governance
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	paramproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+
+	"price-feeder/config"
+	"price-feeder/oracle"
+	"price-feeder/pkg/events"
+)
+
+const (
+	defaultInterval = 5 * time.Minute
+	queryTimeout    = 15 * time.Second
+
+	// activationDelay is waited after the end of a voting period, before
+	// the proposal is checked again, so the chain executed it.
+	activationDelay = 30 * time.Second
+
+	oracleSubspace = "oracle"
+
+	typeURLExecLegacyContent = "/cosmos.gov.v1.MsgExecLegacyContent"
+	typeURLParameterChange   = "/cosmos.params.v1beta1.ParameterChangeProposal"
+	typeURLOraclePrefix      = "/kujira.oracle."
+)
+
+type (
+	// Oracle defines the Oracle interface contract that the watcher
+	// depends on.
+	Oracle interface {
+		Events() *events.Bus
+		RefreshParams()
+	}
+
+	// Querier defines the queries of the gov module.
+	Querier interface {
+		Proposals(ctx context.Context, status govv1.ProposalStatus) ([]*govv1.Proposal, error)
+		Proposal(ctx context.Context, id uint64) (*govv1.Proposal, error)
+	}
+
+	// Proposal defines a proposal changing the oracle params.
+	Proposal struct {
+		ID            uint64    `json:"id"`
+		Title         string    `json:"title"`
+		Status        string    `json:"status"`
+		VotingEndTime time.Time `json:"voting_end_time"`
+		Changes       []Change  `json:"changes"`
+	}
+
+	// Change defines a single change of an oracle param. The value is empty
+	// for messages, that replace all params at once.
+	Change struct {
+		Key   string `json:"key"`
+		Value string `json:"value,omitempty"`
+	}
+
+	// Watcher periodically queries the proposals in voting period and
+	// follows the ones changing the oracle params until they are decided.
+	Watcher struct {
+		logger   zerolog.Logger
+		oracle   Oracle
+		querier  Querier
+		interval time.Duration
+
+		mtx       sync.Mutex
+		proposals map[uint64]Proposal
+	}
+
+	grpcQuerier struct {
+		endpoint func() string
+	}
+)
+
+// NewWatcher returns a watcher querying the proposals from the gRPC
+// endpoint, which is looked up for every query to follow failovers.
+func NewWatcher(
+	logger zerolog.Logger,
+	cfg config.Governance,
+	grpcEndpoint func() string,
+	oracle Oracle,
+) *Watcher {
+	return newWatcher(logger, cfg, &grpcQuerier{endpoint: grpcEndpoint}, oracle)
+}
+
+func newWatcher(
+	logger zerolog.Logger,
+	cfg config.Governance,
+	querier Querier,
+	oracle Oracle,
+) *Watcher {
+	interval, err := time.ParseDuration(cfg.Interval)
+	if err != nil || interval <= 0 {
+		interval = defaultInterval
+	}
+
+	return &Watcher{
+		logger:    logger.With().Str("module", "governance").Logger(),
+		oracle:    oracle,
+		querier:   querier,
+		interval:  interval,
+		proposals: map[uint64]Proposal{},
+	}
+}
+
+// Start watches the proposals until the context is cancelled. Besides the
+// regular interval, proposals are checked again shortly after their voting
+// period ended, so the params are refreshed right after their activation.
+func (w *Watcher) Start(ctx context.Context) error {
+	w.logger.Info().Dur("interval", w.interval).Msg("starting governance watcher")
+
+	for {
+		if err := w.poll(ctx); err != nil {
+			w.logger.Warn().Err(err).Msg("failed to query governance proposals")
+		}
+
+		timer := time.NewTimer(w.next(time.Now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// Proposals returns the proposals changing the oracle params, that are in
+// voting period.
+func (w *Watcher) Proposals() []Proposal {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	proposals := make([]Proposal, 0, len(w.proposals))
+	for _, proposal := range w.proposals {
+		proposals = append(proposals, proposal)
+	}
+	sort.Slice(proposals, func(i, j int) bool {
+		return proposals[i].ID < proposals[j].ID
+	})
+
+	return proposals
+}
+
+// poll picks up new proposals in voting period and checks the outcome of
+// the followed proposals, that left the voting period.
+func (w *Watcher) poll(ctx context.Context) error {
+	voting, err := w.querier.Proposals(ctx, govv1.StatusVotingPeriod)
+	if err != nil {
+		return err
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	current := map[uint64]struct{}{}
+	for _, msg := range voting {
+		proposal, ok := parseProposal(msg)
+		if !ok {
+			continue
+		}
+		current[proposal.ID] = struct{}{}
+
+		if _, known := w.proposals[proposal.ID]; known {
+			continue
+		}
+		w.proposals[proposal.ID] = proposal
+
+		w.logger.Warn().
+			Uint64("id", proposal.ID).
+			Str("title", proposal.Title).
+			Str("changes", proposal.changes()).
+			Time("voting_end_time", proposal.VotingEndTime).
+			Msg("governance proposal changing oracle params entered voting period")
+		w.oracle.Events().Publish(events.Event{
+			Topic: events.TopicProposalVoting,
+			Message: fmt.Sprintf(
+				"proposal %d changing oracle params is in voting period: %s",
+				proposal.ID, proposal.changes(),
+			),
+			Data: proposal,
+		})
+	}
+
+	for id, proposal := range w.proposals {
+		if _, ok := current[id]; ok {
+			continue
+		}
+
+		msg, err := w.querier.Proposal(ctx, id)
+		if err != nil {
+			return err
+		}
+		if msg == nil {
+			return fmt.Errorf("proposal %d not found", id)
+		}
+		proposal.Status = msg.Status.String()
+		delete(w.proposals, id)
+
+		if msg.Status != govv1.StatusPassed {
+			w.logger.Info().
+				Uint64("id", id).
+				Str("status", proposal.Status).
+				Msg("governance proposal changing oracle params was not accepted")
+			continue
+		}
+
+		w.logger.Warn().
+			Uint64("id", id).
+			Str("title", proposal.Title).
+			Str("changes", proposal.changes()).
+			Msg("governance proposal changing oracle params passed, refreshing params")
+		w.oracle.Events().Publish(events.Event{
+			Topic: events.TopicProposalPassed,
+			Message: fmt.Sprintf(
+				"proposal %d changing oracle params passed: %s",
+				id, proposal.changes(),
+			),
+			Data: proposal,
+		})
+		w.oracle.RefreshParams()
+	}
+
+	telemetry.SetGauge(float32(len(w.proposals)), "governance", "proposals")
+
+	return nil
+}
+
+// next returns the time until the next poll, which is the interval or the
+// activation of the earliest followed proposal, if it's sooner.
+func (w *Watcher) next(now time.Time) time.Duration {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	wait := w.interval
+	for _, proposal := range w.proposals {
+		activation := proposal.VotingEndTime.Add(activationDelay).Sub(now)
+		if activation > 0 && activation < wait {
+			wait = activation
+		}
+	}
+
+	return wait
+}
+
+// parseProposal returns the proposal, if any of its messages changes the
+// oracle params. Param changes of the legacy params module are resolved to
+// the single keys.
+func parseProposal(msg *govv1.Proposal) (Proposal, bool) {
+	proposal := Proposal{
+		ID:     msg.Id,
+		Title:  msg.Title,
+		Status: msg.Status.String(),
+	}
+	if msg.VotingEndTime != nil {
+		proposal.VotingEndTime = *msg.VotingEndTime
+	}
+
+	for _, message := range msg.Messages {
+		if message == nil {
+			continue
+		}
+
+		switch {
+		case message.TypeUrl == typeURLExecLegacyContent:
+			var exec govv1.MsgExecLegacyContent
+			if err := exec.Unmarshal(message.Value); err != nil || exec.Content == nil {
+				continue
+			}
+			if exec.Content.TypeUrl != typeURLParameterChange {
+				continue
+			}
+
+			var content paramproposal.ParameterChangeProposal
+			if err := content.Unmarshal(exec.Content.Value); err != nil {
+				continue
+			}
+			if proposal.Title == "" {
+				proposal.Title = content.Title
+			}
+
+			for _, change := range content.Changes {
+				if change.Subspace != oracleSubspace {
+					continue
+				}
+				proposal.Changes = append(proposal.Changes, Change{
+					Key:   change.Key,
+					Value: change.Value,
+				})
+			}
+
+		case strings.HasPrefix(message.TypeUrl, typeURLOraclePrefix):
+			// messages of the oracle module, like a future MsgUpdateParams
+			proposal.Changes = append(proposal.Changes, Change{
+				Key: strings.TrimPrefix(message.TypeUrl, typeURLOraclePrefix),
+			})
+		}
+	}
+
+	return proposal, len(proposal.Changes) > 0
+}
+
+// changes returns the changes formatted for logs and notifications.
+func (p Proposal) changes() string {
+	changes := make([]string, len(p.Changes))
+	for i, change := range p.Changes {
+		if change.Value == "" {
+			changes[i] = change.Key
+			continue
+		}
+		changes[i] = change.Key + "=" + change.Value
+	}
+	return strings.Join(changes, ", ")
+}
+
+func (q *grpcQuerier) dial() (*grpc.ClientConn, error) {
+	conn, err := grpc.Dial(
+		q.endpoint(),
+		// the Cosmos SDK doesn't support any transport security mechanism
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(_ context.Context, addr string) (net.Conn, error) {
+			return oracle.Connect(addr)
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial Cosmos gRPC service: %w", err)
+	}
+	return conn, nil
+}
+
+func (q *grpcQuerier) Proposals(
+	ctx context.Context,
+	status govv1.ProposalStatus,
+) ([]*govv1.Proposal, error) {
+	conn, err := q.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	response, err := govv1.NewQueryClient(conn).Proposals(ctx, &govv1.QueryProposalsRequest{
+		ProposalStatus: status,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proposals: %w", err)
+	}
+
+	return response.Proposals, nil
+}
+
+func (q *grpcQuerier) Proposal(ctx context.Context, id uint64) (*govv1.Proposal, error) {
+	conn, err := q.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	response, err := govv1.NewQueryClient(conn).Proposal(ctx, &govv1.QueryProposalRequest{
+		ProposalId: id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proposal %d: %w", id, err)
+	}
+
+	return response.Proposal, nil
+}
@@ -0,0 +1,147 @@
+package governance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	paramproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+	"price-feeder/pkg/events"
+)
+
+type (
+	mockOracle struct {
+		bus       *events.Bus
+		refreshed int
+	}
+
+	mockQuerier struct {
+		voting    []*govv1.Proposal
+		proposals map[uint64]*govv1.Proposal
+	}
+)
+
+func (m *mockOracle) Events() *events.Bus {
+	return m.bus
+}
+
+func (m *mockOracle) RefreshParams() {
+	m.refreshed++
+}
+
+func (m *mockQuerier) Proposals(context.Context, govv1.ProposalStatus) ([]*govv1.Proposal, error) {
+	return m.voting, nil
+}
+
+func (m *mockQuerier) Proposal(_ context.Context, id uint64) (*govv1.Proposal, error) {
+	return m.proposals[id], nil
+}
+
+func paramChangeProposal(t *testing.T, id uint64, changes ...paramproposal.ParamChange) *govv1.Proposal {
+	content, err := (&paramproposal.ParameterChangeProposal{
+		Title:   "change params",
+		Changes: changes,
+	}).Marshal()
+	require.NoError(t, err)
+
+	exec, err := (&govv1.MsgExecLegacyContent{
+		Content: &codectypes.Any{TypeUrl: typeURLParameterChange, Value: content},
+	}).Marshal()
+	require.NoError(t, err)
+
+	end := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return &govv1.Proposal{
+		Id:            id,
+		Status:        govv1.StatusVotingPeriod,
+		VotingEndTime: &end,
+		Messages: []*codectypes.Any{
+			{TypeUrl: typeURLExecLegacyContent, Value: exec},
+		},
+	}
+}
+
+func TestParseProposal(t *testing.T) {
+	proposal, ok := parseProposal(paramChangeProposal(t, 1,
+		paramproposal.ParamChange{Subspace: "oracle", Key: "VotePeriod", Value: `"20"`},
+		paramproposal.ParamChange{Subspace: "staking", Key: "MaxValidators", Value: `"100"`},
+	))
+	require.True(t, ok)
+	require.Equal(t, "change params", proposal.Title)
+	require.Equal(t, []Change{{Key: "VotePeriod", Value: `"20"`}}, proposal.Changes)
+
+	_, ok = parseProposal(paramChangeProposal(t, 2,
+		paramproposal.ParamChange{Subspace: "staking", Key: "MaxValidators", Value: `"100"`},
+	))
+	require.False(t, ok)
+
+	proposal, ok = parseProposal(&govv1.Proposal{
+		Id: 3,
+		Messages: []*codectypes.Any{
+			{TypeUrl: "/kujira.oracle.MsgUpdateParams"},
+		},
+	})
+	require.True(t, ok)
+	require.Equal(t, []Change{{Key: "MsgUpdateParams"}}, proposal.Changes)
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	oracle := &mockOracle{bus: events.NewBus()}
+	querier := &mockQuerier{proposals: map[uint64]*govv1.Proposal{}}
+	watcher := newWatcher(zerolog.Nop(), config.Governance{}, querier, oracle)
+
+	var published []events.Topic
+	oracle.bus.Subscribe(func(event events.Event) {
+		published = append(published, event.Topic)
+	})
+
+	whitelist := paramChangeProposal(t, 1,
+		paramproposal.ParamChange{Subspace: "oracle", Key: "Whitelist", Value: `[]`},
+	)
+	slashing := paramChangeProposal(t, 2,
+		paramproposal.ParamChange{Subspace: "oracle", Key: "SlashFraction", Value: `"0.01"`},
+	)
+	querier.voting = []*govv1.Proposal{whitelist, slashing}
+
+	// new proposals are reported once
+	require.NoError(t, watcher.poll(context.Background()))
+	require.NoError(t, watcher.poll(context.Background()))
+	require.Len(t, watcher.Proposals(), 2)
+	require.Equal(t, []events.Topic{
+		events.TopicProposalVoting, events.TopicProposalVoting,
+	}, published)
+	require.Zero(t, oracle.refreshed)
+
+	// the whitelist change passed and the slash fraction change was rejected
+	querier.voting = nil
+	querier.proposals[1] = &govv1.Proposal{Id: 1, Status: govv1.StatusPassed}
+	querier.proposals[2] = &govv1.Proposal{Id: 2, Status: govv1.StatusRejected}
+
+	require.NoError(t, watcher.poll(context.Background()))
+	require.Empty(t, watcher.Proposals())
+	require.Equal(t, events.TopicProposalPassed, published[len(published)-1])
+	require.Len(t, published, 3)
+	require.Equal(t, 1, oracle.refreshed)
+}
+
+func TestWatcher_Next(t *testing.T) {
+	watcher := newWatcher(
+		zerolog.Nop(), config.Governance{Interval: "5m"}, &mockQuerier{}, &mockOracle{},
+	)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, 5*time.Minute, watcher.next(now))
+
+	watcher.proposals[1] = Proposal{ID: 1, VotingEndTime: now.Add(time.Minute)}
+	require.Equal(t, time.Minute+activationDelay, watcher.next(now))
+
+	// ended proposals are picked up by the regular poll
+	watcher.proposals[1] = Proposal{ID: 1, VotingEndTime: now.Add(-time.Hour)}
+	require.Equal(t, 5*time.Minute, watcher.next(now))
+}
@@ -0,0 +1,75 @@
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// Client is a typed client of the price feeder gRPC service.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient returns a client using the connection. Bearer tokens are
+// passed as "authorization" metadata of the context.
+func NewClient(conn *grpc.ClientConn) *Client {
+	return &Client{conn: conn}
+}
+
+// GetPrices returns the prices of the denoms, or all prices without denoms.
+func (c *Client) GetPrices(ctx context.Context, denoms ...string) (*PricesResponse, error) {
+	resp := new(PricesResponse)
+	err := c.conn.Invoke(
+		ctx, "/"+ServiceName+"/GetPrices", &PricesRequest{Denoms: denoms}, resp,
+		grpc.CallContentSubtype(CodecName),
+	)
+	return resp, err
+}
+
+// GetProviderStatus returns the health of all running providers.
+func (c *Client) GetProviderStatus(ctx context.Context) (*ProviderStatusResponse, error) {
+	resp := new(ProviderStatusResponse)
+	err := c.conn.Invoke(
+		ctx, "/"+ServiceName+"/GetProviderStatus", &ProviderStatusRequest{}, resp,
+		grpc.CallContentSubtype(CodecName),
+	)
+	return resp, err
+}
+
+// PricesStream receives the price updates of StreamPrices.
+type PricesStream struct {
+	stream grpc.ClientStream
+}
+
+// Recv blocks until the next price update is received.
+func (s *PricesStream) Recv() (*PricesResponse, error) {
+	resp := new(PricesResponse)
+	if err := s.stream.RecvMsg(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// StreamPrices streams the current prices of the denoms, followed by every
+// price update, until the context is canceled.
+func (c *Client) StreamPrices(ctx context.Context, denoms ...string) (*PricesStream, error) {
+	stream, err := c.conn.NewStream(
+		ctx,
+		&grpc.StreamDesc{StreamName: "StreamPrices", ServerStreams: true},
+		"/"+ServiceName+"/StreamPrices",
+		grpc.CallContentSubtype(CodecName),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.SendMsg(&PricesRequest{Denoms: denoms}); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	return &PricesStream{stream: stream}, nil
+}
@@ -0,0 +1,374 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"price-feeder/config"
+	"price-feeder/oracle/types"
+	"price-feeder/pkg/events"
+)
+
+const (
+	// ServiceName is the full name of the price feeder gRPC service.
+	ServiceName = "pricefeeder.v1.PriceFeeder"
+
+	// CodecName is the content subtype of the messages. The messages are
+	// plain Go structs encoded as JSON, so no generated code is required.
+	CodecName = "json"
+
+	// streamBuffer defines how many price updates are buffered per stream,
+	// before updates are dropped for slow consumers.
+	streamBuffer = 16
+)
+
+type (
+	// Oracle defines the oracle interface the gRPC service depends on.
+	Oracle interface {
+		GetPrices() sdk.DecCoins
+		GetLastPricesTimestamp() time.Time
+		GetProviderStatus() []types.ProviderStatus
+		Events() *events.Bus
+	}
+
+	// PricesRequest selects the denoms of the prices. All prices are
+	// returned without denoms.
+	PricesRequest struct {
+		Denoms []string `json:"denoms,omitempty"`
+	}
+
+	// PricesResponse contains the prices of the last calculation.
+	PricesResponse struct {
+		Time   time.Time          `json:"time"`
+		Prices map[string]sdk.Dec `json:"prices"`
+	}
+
+	// ProviderStatusRequest requests the health of all running providers.
+	ProviderStatusRequest struct{}
+
+	// ProviderStatusResponse contains the health of all running providers.
+	ProviderStatusResponse struct {
+		Providers []types.ProviderStatus `json:"providers"`
+	}
+
+	// Server serves the prices and the provider status via gRPC and
+	// streams every price update to its subscribers.
+	Server struct {
+		logger  zerolog.Logger
+		cfg     config.GRPC
+		tokens  []string
+		oracle  Oracle
+		mtx     sync.Mutex
+		streams map[chan PricesResponse]struct{}
+	}
+
+	jsonCodec struct{}
+)
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return CodecName
+}
+
+// NewServer returns a gRPC server, which accepts the bearer tokens if any.
+func NewServer(
+	logger zerolog.Logger,
+	cfg config.GRPC,
+	tokens []string,
+	oracle Oracle,
+) *Server {
+	s := &Server{
+		logger:  logger.With().Str("module", "grpc").Logger(),
+		cfg:     cfg,
+		tokens:  tokens,
+		oracle:  oracle,
+		streams: map[chan PricesResponse]struct{}{},
+	}
+
+	oracle.Events().Subscribe(func(event events.Event) {
+		// the oracle publishes the computed prices keyed by denom
+		prices, ok := event.Data.(map[string]sdk.Dec)
+		if !ok {
+			return
+		}
+		resp := PricesResponse{
+			Time:   event.Time,
+			Prices: make(map[string]sdk.Dec, len(prices)),
+		}
+		for denom, price := range prices {
+			resp.Prices[denom] = price
+		}
+		s.broadcast(resp)
+	}, events.TopicPricesUpdated)
+
+	return s
+}
+
+// Start serves the gRPC service on the listen address until the context
+// is canceled.
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.ListenAddr, err)
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves the gRPC service on the listener until the context is
+// canceled.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(s.authUnary),
+		grpc.StreamInterceptor(s.authStream),
+	)
+	srv.RegisterService(&serviceDesc, s)
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Info().Str("listen_addr", listener.Addr().String()).Msg("starting grpc server")
+		errCh <- srv.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		s.logger.Info().Msg("shutting down grpc server")
+		srv.GracefulStop()
+		return nil
+	case err := <-errCh:
+		return err
+	}
+}
+
+// GetPrices returns the prices of the last calculation.
+func (s *Server) GetPrices(_ context.Context, req *PricesRequest) (*PricesResponse, error) {
+	resp := newPricesResponse(
+		s.oracle.GetLastPricesTimestamp(), s.oracle.GetPrices(), req.Denoms,
+	)
+	return &resp, nil
+}
+
+// GetProviderStatus returns the health of all running providers.
+func (s *Server) GetProviderStatus(
+	_ context.Context,
+	_ *ProviderStatusRequest,
+) (*ProviderStatusResponse, error) {
+	return &ProviderStatusResponse{
+		Providers: s.oracle.GetProviderStatus(),
+	}, nil
+}
+
+// StreamPrices sends the current prices and every following price update
+// until the client disconnects. Updates are dropped for consumers not
+// keeping up.
+func (s *Server) StreamPrices(req *PricesRequest, stream grpc.ServerStream) error {
+	updates := make(chan PricesResponse, streamBuffer)
+
+	s.mtx.Lock()
+	s.streams[updates] = struct{}{}
+	s.mtx.Unlock()
+
+	defer func() {
+		s.mtx.Lock()
+		delete(s.streams, updates)
+		s.mtx.Unlock()
+	}()
+
+	current := newPricesResponse(
+		s.oracle.GetLastPricesTimestamp(), s.oracle.GetPrices(), req.Denoms,
+	)
+	if err := stream.SendMsg(&current); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case update := <-updates:
+			update = update.filter(req.Denoms)
+			if err := stream.SendMsg(&update); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) broadcast(update PricesResponse) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for stream := range s.streams {
+		select {
+		case stream <- update:
+		default:
+			s.logger.Warn().Msg("grpc stream too slow, dropping price update")
+		}
+	}
+}
+
+func (s *Server) authorize(ctx context.Context) error {
+	if len(s.tokens) == 0 {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token := strings.TrimPrefix(value, "Bearer ")
+		for _, allowed := range s.tokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(allowed)) == 1 {
+				return nil
+			}
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "invalid token")
+}
+
+func (s *Server) authUnary(
+	ctx context.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) authStream(
+	srv interface{},
+	stream grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := s.authorize(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+func newPricesResponse(
+	timestamp time.Time,
+	prices sdk.DecCoins,
+	denoms []string,
+) PricesResponse {
+	resp := PricesResponse{
+		Time:   timestamp,
+		Prices: make(map[string]sdk.Dec, len(prices)),
+	}
+	for _, price := range prices {
+		resp.Prices[price.Denom] = price.Amount
+	}
+	return resp.filter(denoms)
+}
+
+// filter returns the response with the prices of the denoms only. All
+// prices are kept without denoms.
+func (r PricesResponse) filter(denoms []string) PricesResponse {
+	if len(denoms) == 0 {
+		return r
+	}
+
+	filtered := PricesResponse{
+		Time:   r.Time,
+		Prices: make(map[string]sdk.Dec, len(denoms)),
+	}
+	for _, denom := range denoms {
+		denom = strings.ToUpper(denom)
+		if price, found := r.Prices[denom]; found {
+			filtered.Prices[denom] = price
+		}
+	}
+	return filtered
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPrices",
+			Handler: func(
+				srv interface{},
+				ctx context.Context,
+				dec func(interface{}) error,
+				interceptor grpc.UnaryServerInterceptor,
+			) (interface{}, error) {
+				req := new(PricesRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(*Server).GetPrices(ctx, req.(*PricesRequest))
+				}
+				info := &grpc.UnaryServerInfo{
+					Server:     srv,
+					FullMethod: "/" + ServiceName + "/GetPrices",
+				}
+				return interceptor(ctx, req, info, handler)
+			},
+		},
+		{
+			MethodName: "GetProviderStatus",
+			Handler: func(
+				srv interface{},
+				ctx context.Context,
+				dec func(interface{}) error,
+				interceptor grpc.UnaryServerInterceptor,
+			) (interface{}, error) {
+				req := new(ProviderStatusRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(*Server).GetProviderStatus(ctx, req.(*ProviderStatusRequest))
+				}
+				info := &grpc.UnaryServerInfo{
+					Server:     srv,
+					FullMethod: "/" + ServiceName + "/GetProviderStatus",
+				}
+				return interceptor(ctx, req, info, handler)
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPrices",
+			ServerStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				req := new(PricesRequest)
+				if err := stream.RecvMsg(req); err != nil {
+					return err
+				}
+				return srv.(*Server).StreamPrices(req, stream)
+			},
+		},
+	},
+}
@@ -0,0 +1,135 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"price-feeder/config"
+	"price-feeder/oracle/types"
+	"price-feeder/pkg/events"
+)
+
+type mockOracle struct {
+	bus    *events.Bus
+	prices sdk.DecCoins
+}
+
+func (m *mockOracle) GetPrices() sdk.DecCoins {
+	return m.prices
+}
+
+func (m *mockOracle) GetLastPricesTimestamp() time.Time {
+	return time.Unix(1700000000, 0).UTC()
+}
+
+func (m *mockOracle) GetProviderStatus() []types.ProviderStatus {
+	return []types.ProviderStatus{{Name: "binance", Healthy: true}}
+}
+
+func (m *mockOracle) Events() *events.Bus {
+	return m.bus
+}
+
+func startServer(t *testing.T, tokens []string) (*mockOracle, *Client) {
+	oracle := &mockOracle{
+		bus: events.NewBus(),
+		prices: sdk.DecCoins{
+			sdk.NewDecCoinFromDec("ATOM", sdk.MustNewDecFromStr("9.1")),
+			sdk.NewDecCoinFromDec("KUJI", sdk.MustNewDecFromStr("0.75")),
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(zerolog.Nop(), config.GRPC{}, tokens, oracle)
+	go func() {
+		_ = server.Serve(ctx, listener)
+	}()
+
+	conn, err := grpc.DialContext(
+		ctx, "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return oracle, NewClient(conn)
+}
+
+func TestServer(t *testing.T) {
+	_, client := startServer(t, nil)
+	ctx := context.Background()
+
+	prices, err := client.GetPrices(ctx)
+	require.NoError(t, err)
+	require.Len(t, prices.Prices, 2)
+	require.Equal(t, "9.100000000000000000", prices.Prices["ATOM"].String())
+
+	prices, err = client.GetPrices(ctx, "kuji")
+	require.NoError(t, err)
+	require.Len(t, prices.Prices, 1)
+	require.Contains(t, prices.Prices, "KUJI")
+
+	providers, err := client.GetProviderStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "binance", providers.Providers[0].Name)
+}
+
+func TestServerStream(t *testing.T) {
+	oracle, client := startServer(t, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamPrices(ctx, "ATOM")
+	require.NoError(t, err)
+
+	// the current prices are sent first
+	prices, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "9.100000000000000000", prices.Prices["ATOM"].String())
+
+	// the stream is registered before the current prices are sent
+	oracle.bus.Publish(events.Event{
+		Topic: events.TopicPricesUpdated,
+		Data: map[string]sdk.Dec{
+			"ATOM": sdk.MustNewDecFromStr("9.2"),
+			"KUJI": sdk.MustNewDecFromStr("0.76"),
+		},
+	})
+
+	prices, err = stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, prices.Prices, 1)
+	require.Equal(t, "9.200000000000000000", prices.Prices["ATOM"].String())
+}
+
+func TestServerAuth(t *testing.T) {
+	_, client := startServer(t, []string{"secret"})
+
+	_, err := client.GetPrices(context.Background())
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(
+		context.Background(), "authorization", "Bearer secret",
+	)
+	_, err = client.GetPrices(ctx)
+	require.NoError(t, err)
+}
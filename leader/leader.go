@@ -0,0 +1,272 @@
+// Package leader elects the voting instance of redundant feeders. Every
+// instance has a priority and gossips its health via the healthz endpoint;
+// the healthy instance with the highest priority votes, while the others
+// stay warm as standby.
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	"github.com/rs/zerolog"
+
+	"price-feeder/config"
+)
+
+const (
+	defaultInterval      = 5 * time.Second
+	defaultFailoverAfter = 30 * time.Second
+)
+
+type (
+	// Oracle defines the Oracle interface contract that the elector
+	// depends on.
+	Oracle interface {
+		GetLastPriceSyncTimestamp() time.Time
+	}
+
+	// Status defines the election state of an instance, as served by the
+	// healthz endpoint.
+	Status struct {
+		Name     string       `json:"name"`
+		Priority int          `json:"priority"`
+		Healthy  bool         `json:"healthy"`
+		Leader   bool         `json:"leader"`
+		Peers    []PeerStatus `json:"peers,omitempty"`
+	}
+
+	// PeerStatus defines the last known state of a peer.
+	PeerStatus struct {
+		URL      string    `json:"url"`
+		Name     string    `json:"name"`
+		Priority int       `json:"priority"`
+		Healthy  bool      `json:"healthy"`
+		Leader   bool      `json:"leader"`
+		LastSeen time.Time `json:"last_seen"`
+		Error    string    `json:"error,omitempty"`
+	}
+
+	// Elector periodically polls the peers and decides whether this
+	// instance votes.
+	Elector struct {
+		logger        zerolog.Logger
+		oracle        Oracle
+		name          string
+		priority      int
+		peers         []string
+		token         string
+		interval      time.Duration
+		failoverAfter time.Duration
+		client        *http.Client
+
+		mtx    sync.RWMutex
+		leader bool
+		status map[string]PeerStatus
+	}
+
+	healthzResponse struct {
+		Oracle struct {
+			LastSync time.Time `json:"last_sync"`
+		} `json:"oracle"`
+		Leader *Status `json:"leader"`
+	}
+)
+
+// NewElector returns an elector for the configured peers. The instance
+// starts as standby until the peers were polled once.
+func NewElector(logger zerolog.Logger, cfg config.Leader, oracle Oracle) *Elector {
+	parse := func(value string, fallback time.Duration) time.Duration {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return fallback
+		}
+		return duration
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+
+	interval := parse(cfg.Interval, defaultInterval)
+
+	return &Elector{
+		logger:        logger.With().Str("module", "leader").Logger(),
+		oracle:        oracle,
+		name:          name,
+		priority:      cfg.Priority,
+		peers:         cfg.Peers,
+		token:         cfg.Token,
+		interval:      interval,
+		failoverAfter: parse(cfg.FailoverAfter, defaultFailoverAfter),
+		client:        &http.Client{Timeout: interval},
+		status:        map[string]PeerStatus{},
+	}
+}
+
+// Start polls the peers until the context is cancelled.
+func (e *Elector) Start(ctx context.Context) error {
+	e.logger.Info().
+		Str("name", e.name).
+		Int("priority", e.priority).
+		Strs("peers", e.peers).
+		Msg("starting leader election")
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.poll(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsLeader returns true if this instance should vote.
+func (e *Elector) IsLeader() bool {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+
+	return e.leader
+}
+
+// Status returns the election state of this instance and its peers.
+func (e *Elector) Status() *Status {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+
+	status := &Status{
+		Name:     e.name,
+		Priority: e.priority,
+		Healthy:  e.healthy(time.Now()),
+		Leader:   e.leader,
+	}
+	for _, peer := range e.peers {
+		status.Peers = append(status.Peers, e.status[peer])
+	}
+
+	return status
+}
+
+func (e *Elector) poll(ctx context.Context, now time.Time) {
+	for _, url := range e.peers {
+		status := e.fetch(ctx, url, now)
+
+		e.mtx.Lock()
+		e.status[url] = status
+		e.mtx.Unlock()
+	}
+
+	e.elect(now)
+}
+
+// fetch returns the state of a peer. On failure, the last known state is
+// kept with the error, so the peer is only considered down after
+// failover_after.
+func (e *Elector) fetch(ctx context.Context, url string, now time.Time) PeerStatus {
+	e.mtx.RLock()
+	status, ok := e.status[url]
+	e.mtx.RUnlock()
+	if !ok {
+		status = PeerStatus{URL: url}
+	}
+
+	response, err := e.get(ctx, url)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	if response.Leader == nil {
+		status.Error = "leader election is not enabled"
+		return status
+	}
+
+	return PeerStatus{
+		URL:      url,
+		Name:     response.Leader.Name,
+		Priority: response.Leader.Priority,
+		Healthy:  now.Sub(response.Oracle.LastSync) < e.failoverAfter,
+		Leader:   response.Leader.Leader,
+		LastSeen: now,
+	}
+}
+
+func (e *Elector) get(ctx context.Context, url string) (healthzResponse, error) {
+	var response healthzResponse
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, strings.TrimSuffix(url, "/")+"/api/v1/healthz", nil,
+	)
+	if err != nil {
+		return response, err
+	}
+	if e.token != "" {
+		req.Header.Set("Authorization", "Bearer "+e.token)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return response, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return response, fmt.Errorf("peer returned status %d", resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+// elect makes this instance the leader, if it is healthy and no healthy
+// peer seen within failover_after has a higher priority. Equal priorities
+// are decided by the lower name.
+func (e *Elector) elect(now time.Time) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	leader := e.healthy(now)
+	if leader {
+		for _, peer := range e.status {
+			if !peer.Healthy || now.Sub(peer.LastSeen) >= e.failoverAfter {
+				continue
+			}
+			if peer.Priority > e.priority ||
+				(peer.Priority == e.priority && peer.Name < e.name) {
+				leader = false
+				break
+			}
+		}
+	}
+
+	if leader != e.leader {
+		if leader {
+			e.logger.Warn().Str("name", e.name).Msg("became leader, voting")
+		} else {
+			e.logger.Warn().Str("name", e.name).Msg("became standby, not voting")
+		}
+	}
+	e.leader = leader
+
+	var value float32
+	if leader {
+		value = 1
+	}
+	telemetry.SetGauge(value, "leader")
+}
+
+// healthy returns true if the oracle of this instance ticked recently.
+func (e *Elector) healthy(now time.Time) bool {
+	return now.Sub(e.oracle.GetLastPriceSyncTimestamp()) < e.failoverAfter
+}
@@ -0,0 +1,90 @@
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+)
+
+type mockOracle struct {
+	lastSync time.Time
+}
+
+func (m mockOracle) GetLastPriceSyncTimestamp() time.Time {
+	return m.lastSync
+}
+
+func TestElector(t *testing.T) {
+	now := time.Now()
+
+	var (
+		down     atomic.Bool
+		peerSync atomic.Int64
+	)
+	peerSync.Store(now.UnixNano())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/healthz", r.URL.Path)
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var response healthzResponse
+		response.Oracle.LastSync = time.Unix(0, peerSync.Load())
+		response.Leader = &Status{Name: "primary", Priority: 10, Healthy: true, Leader: true}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	elector := NewElector(zerolog.Nop(), config.Leader{
+		Name:          "standby",
+		Priority:      5,
+		Peers:         []string{server.URL},
+		Token:         "token",
+		FailoverAfter: "30s",
+	}, mockOracle{lastSync: now})
+
+	ctx := context.Background()
+
+	// healthy primary with higher priority
+	elector.poll(ctx, now)
+	require.False(t, elector.IsLeader())
+	require.Equal(t, "primary", elector.Status().Peers[0].Name)
+
+	// primary unreachable, but seen within failover_after
+	down.Store(true)
+	elector.poll(ctx, now.Add(10*time.Second))
+	require.False(t, elector.IsLeader())
+	require.NotEmpty(t, elector.Status().Peers[0].Error)
+
+	// take over, while this instance is healthy
+	elector.oracle = mockOracle{lastSync: now.Add(25 * time.Second)}
+	elector.poll(ctx, now.Add(31*time.Second))
+	require.True(t, elector.IsLeader())
+
+	// primary is back
+	down.Store(false)
+	now = now.Add(40 * time.Second)
+	peerSync.Store(now.UnixNano())
+	elector.oracle = mockOracle{lastSync: now}
+	elector.poll(ctx, now)
+	require.False(t, elector.IsLeader())
+
+	// unhealthy instances never vote
+	elector.peers = nil
+	elector.status = map[string]PeerStatus{}
+	elector.poll(ctx, now.Add(time.Minute))
+	require.False(t, elector.IsLeader())
+}
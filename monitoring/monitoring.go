@@ -0,0 +1,297 @@
+// Package monitoring generates Prometheus alert rules and a Grafana
+// dashboard for the providers and pairs of a feeder config.
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"gopkg.in/yaml.v3"
+
+	"price-feeder/config"
+	"price-feeder/oracle/provider"
+)
+
+var invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+type (
+	// Thresholds defines when the generated alerts fire.
+	Thresholds struct {
+		// StaleAfter defines how long a price may not change.
+		StaleAfter time.Duration
+		// MaxMisses defines the number of missed votes per hour.
+		MaxMisses int
+		// MinBalance defines the minimum balance of the fee denom.
+		MinBalance int64
+	}
+
+	// Generator generates the monitoring artifacts of a config.
+	Generator struct {
+		prefix     string
+		feeDenom   string
+		providers  []string
+		denoms     []string
+		thresholds Thresholds
+	}
+
+	// RuleGroups defines the Prometheus rule file.
+	RuleGroups struct {
+		Groups []RuleGroup `yaml:"groups"`
+	}
+
+	// RuleGroup defines a group of rules.
+	RuleGroup struct {
+		Name  string `yaml:"name"`
+		Rules []Rule `yaml:"rules"`
+	}
+
+	// Rule defines an alerting rule.
+	Rule struct {
+		Alert       string            `yaml:"alert"`
+		Expr        string            `yaml:"expr"`
+		For         string            `yaml:"for,omitempty"`
+		Labels      map[string]string `yaml:"labels,omitempty"`
+		Annotations map[string]string `yaml:"annotations,omitempty"`
+	}
+)
+
+// NewGenerator returns a generator for the providers and pairs of the
+// config. The metric names follow the telemetry service name.
+func NewGenerator(cfg config.Config, thresholds Thresholds) (*Generator, error) {
+	g := &Generator{thresholds: thresholds}
+
+	if !cfg.Telemetry.EnableServiceLabel && cfg.Telemetry.ServiceName != "" {
+		g.prefix = invalidMetricChars.ReplaceAllString(cfg.Telemetry.ServiceName, "_") + "_"
+	}
+
+	gasPrices, err := sdk.ParseDecCoins(cfg.GasPrices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gas prices: %w", err)
+	}
+	if len(gasPrices) > 0 {
+		g.feeDenom = gasPrices[0].Denom
+	}
+
+	providers := map[string]struct{}{}
+	denoms := map[string]struct{}{}
+	for _, pair := range cfg.CurrencyPairs {
+		denoms[strings.ToUpper(pair.Base)] = struct{}{}
+		for _, name := range pair.Providers {
+			providers[name.String()] = struct{}{}
+		}
+	}
+
+	g.providers = sortedKeys(providers)
+	g.denoms = sortedKeys(denoms)
+
+	return g, nil
+}
+
+// Rules returns the Prometheus alert rules in YAML.
+func (g *Generator) Rules() ([]byte, error) {
+	staleAfter := promDuration(g.thresholds.StaleAfter)
+
+	rules := []Rule{
+		{
+			Alert: "PriceFeederTicksStopped",
+			Expr:  fmt.Sprintf("sum(rate(%s[5m])) == 0 or absent(%s)", g.metric("new_tick"), g.metric("new_tick")),
+			For:   "5m",
+			Labels: map[string]string{
+				"severity": "critical",
+			},
+			Annotations: map[string]string{
+				"summary": "The price feeder stopped computing prices.",
+			},
+		},
+		{
+			Alert: "PriceFeederTickFailures",
+			Expr:  fmt.Sprintf("sum(increase(%s[15m])) > 3", g.metric("failure_tick")),
+			Labels: map[string]string{
+				"severity": "warning",
+			},
+			Annotations: map[string]string{
+				"summary": "Oracle ticks are failing.",
+			},
+		},
+		{
+			Alert: "PriceFeederMissedVotes",
+			Expr: fmt.Sprintf(
+				"sum(increase(%s[1h])) > %d",
+				g.metric("vote_failure_missed"), g.thresholds.MaxMisses,
+			),
+			Labels: map[string]string{
+				"severity": "critical",
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf(
+					"More than %d votes were missed in the last hour.",
+					g.thresholds.MaxMisses,
+				),
+			},
+		},
+	}
+
+	if g.feeDenom != "" {
+		rules = append(rules, Rule{
+			Alert: "PriceFeederLowBalance",
+			Expr: fmt.Sprintf(
+				`%s{denom="%s"} < %d`,
+				g.metric("account_balance"), g.feeDenom, g.thresholds.MinBalance,
+			),
+			For: "10m",
+			Labels: map[string]string{
+				"severity": "warning",
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf(
+					"The feeder balance is below %d%s, votes will fail without fees.",
+					g.thresholds.MinBalance, g.feeDenom,
+				),
+			},
+		})
+	}
+
+	for _, denom := range g.denoms {
+		rules = append(rules, Rule{
+			Alert: "PriceFeederStalePrice",
+			Expr: fmt.Sprintf(
+				`changes(%s{provider="%s",denom="%sUSD"}[%s]) == 0`,
+				g.metric("provider_price"), provider.FinalPriceProvider, denom, staleAfter,
+			),
+			Labels: map[string]string{
+				"severity": "warning",
+				"denom":    denom,
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf(
+					"The %s price did not change for %s.", denom, staleAfter,
+				),
+			},
+		})
+	}
+
+	for _, name := range g.providers {
+		rules = append(rules, Rule{
+			Alert: "PriceFeederStaleProvider",
+			Expr: fmt.Sprintf(
+				`max(changes(%s{provider="%s"}[%s])) == 0`,
+				g.metric("provider_price"), name, staleAfter,
+			),
+			Labels: map[string]string{
+				"severity": "info",
+				"provider": name,
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf(
+					"No %s price changed for %s.", name, staleAfter,
+				),
+			},
+		})
+	}
+
+	return yaml.Marshal(RuleGroups{
+		Groups: []RuleGroup{{Name: "price-feeder", Rules: rules}},
+	})
+}
+
+// Dashboard returns the Grafana dashboard in JSON. The Prometheus
+// datasource is selected when the dashboard is imported.
+func (g *Generator) Dashboard() ([]byte, error) {
+	panels := []map[string]interface{}{}
+
+	add := func(title string, width int, targets ...map[string]interface{}) {
+		id := len(panels) + 1
+		panels = append(panels, map[string]interface{}{
+			"id":         id,
+			"type":       "timeseries",
+			"title":      title,
+			"datasource": map[string]string{"type": "prometheus", "uid": "${datasource}"},
+			"gridPos": map[string]int{
+				"h": 8,
+				"w": width,
+				"x": (len(panels) * width) % 24,
+				"y": (len(panels) * width) / 24 * 8,
+			},
+			"targets": targets,
+		})
+	}
+
+	target := func(expr, legend string) map[string]interface{} {
+		return map[string]interface{}{
+			"expr":         expr,
+			"legendFormat": legend,
+			"refId":        "A",
+		}
+	}
+
+	add("Ticks", 12, target(
+		fmt.Sprintf("sum(rate(%s[5m]))", g.metric("new_tick")), "ticks/s",
+	))
+	add("Missed votes", 12, target(
+		fmt.Sprintf("sum(increase(%s[1h]))", g.metric("vote_failure_missed")), "missed/h",
+	))
+	if g.feeDenom != "" {
+		add("Balance", 12, target(
+			fmt.Sprintf(`%s{denom="%s"}`, g.metric("account_balance"), g.feeDenom), g.feeDenom,
+		))
+	}
+	add("Provider failures", 12, target(
+		fmt.Sprintf(`sum by (provider) (increase(%s[15m]))`, g.metric("failure")), "{{provider}}",
+	))
+
+	for _, denom := range g.denoms {
+		add(denom+" price", 12, target(
+			fmt.Sprintf(
+				`%s{provider="%s",denom="%sUSD"}`,
+				g.metric("provider_price"), provider.FinalPriceProvider, denom,
+			),
+			denom,
+		))
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"title":         "Price Feeder",
+		"uid":           "price-feeder",
+		"schemaVersion": 38,
+		"refresh":       "1m",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"templating": map[string]interface{}{
+			"list": []map[string]interface{}{{
+				"name":  "datasource",
+				"type":  "datasource",
+				"query": "prometheus",
+			}},
+		},
+		"panels": panels,
+	}, "", "  ")
+}
+
+func (g *Generator) metric(name string) string {
+	return g.prefix + name
+}
+
+// promDuration formats a duration in the Prometheus format, e.g. 15m.
+func promDuration(duration time.Duration) string {
+	switch {
+	case duration%time.Hour == 0:
+		return fmt.Sprintf("%dh", duration/time.Hour)
+	case duration%time.Minute == 0:
+		return fmt.Sprintf("%dm", duration/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", duration/time.Second)
+	}
+}
+
+func sortedKeys(values map[string]struct{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"price-feeder/config"
+	"price-feeder/oracle/provider"
+)
+
+func testConfig() config.Config {
+	return config.Config{
+		GasPrices: "0.00125ukuji",
+		Telemetry: config.Telemetry{ServiceName: "price-feeder"},
+		CurrencyPairs: []config.CurrencyPair{
+			{Base: "KUJI", Quote: "USDC", Providers: []provider.Name{"fin", "mexc"}},
+			{Base: "atom", Quote: "USDT", Providers: []provider.Name{"binance"}},
+		},
+	}
+}
+
+func TestRules(t *testing.T) {
+	generator, err := NewGenerator(testConfig(), Thresholds{
+		StaleAfter: 15 * time.Minute,
+		MaxMisses:  2,
+		MinBalance: 1000000,
+	})
+	require.NoError(t, err)
+
+	content, err := generator.Rules()
+	require.NoError(t, err)
+
+	var groups RuleGroups
+	require.NoError(t, yaml.Unmarshal(content, &groups))
+	require.Len(t, groups.Groups, 1)
+
+	expressions := map[string][]string{}
+	for _, rule := range groups.Groups[0].Rules {
+		expressions[rule.Alert] = append(expressions[rule.Alert], rule.Expr)
+	}
+
+	require.Equal(t, []string{
+		"sum(increase(price_feeder_vote_failure_missed[1h])) > 2",
+	}, expressions["PriceFeederMissedVotes"])
+	require.Equal(t, []string{
+		`price_feeder_account_balance{denom="ukuji"} < 1000000`,
+	}, expressions["PriceFeederLowBalance"])
+	require.Equal(t, []string{
+		`changes(price_feeder_provider_price{provider="_final",denom="ATOMUSD"}[15m]) == 0`,
+		`changes(price_feeder_provider_price{provider="_final",denom="KUJIUSD"}[15m]) == 0`,
+	}, expressions["PriceFeederStalePrice"])
+	require.Len(t, expressions["PriceFeederStaleProvider"], 3)
+}
+
+func TestDashboard(t *testing.T) {
+	cfg := testConfig()
+	cfg.Telemetry.EnableServiceLabel = true
+
+	generator, err := NewGenerator(cfg, Thresholds{StaleAfter: time.Hour})
+	require.NoError(t, err)
+
+	content, err := generator.Dashboard()
+	require.NoError(t, err)
+
+	var dashboard struct {
+		Panels []struct {
+			Title   string `json:"title"`
+			Targets []struct {
+				Expr string `json:"expr"`
+			} `json:"targets"`
+		} `json:"panels"`
+	}
+	require.NoError(t, json.Unmarshal(content, &dashboard))
+
+	titles := []string{}
+	for _, panel := range dashboard.Panels {
+		titles = append(titles, panel.Title)
+	}
+	require.Equal(t, []string{
+		"Ticks", "Missed votes", "Balance", "Provider failures",
+		"ATOM price", "KUJI price",
+	}, titles)
+	require.Equal(t,
+		`provider_price{provider="_final",denom="KUJIUSD"}`,
+		dashboard.Panels[5].Targets[0].Expr,
+	)
+}
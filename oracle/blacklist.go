@@ -0,0 +1,269 @@
+package oracle
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	blacklistReasonConfig    = "config"
+	blacklistReasonDeviation = "deviation"
+	blacklistReasonDelisted  = "delisted"
+)
+
+// Blacklist keeps track of provider symbols, that are excluded from the price
+// calculation. Symbols are either blacklisted via config or at runtime after
+// repeatedly deviating from the computed price.
+type Blacklist struct {
+	mtx          sync.RWMutex
+	entries      map[provider.Name]map[string]types.BlacklistEntry
+	strikes      map[provider.Name]map[string]int
+	maxDeviation sdk.Dec
+	maxStrikes   int
+	duration     time.Duration
+	// missing tracks since when the tickers of pairs are missing, to
+	// delist them after delistAfter
+	missing     map[provider.Name]map[string]time.Time
+	delistAfter time.Duration
+}
+
+// NewBlacklist returns a Blacklist with the static entries provided. Runtime
+// blacklisting is disabled, if maxStrikes is 0.
+func NewBlacklist(
+	static map[provider.Name][]string,
+	maxDeviation sdk.Dec,
+	maxStrikes int,
+	duration time.Duration,
+) *Blacklist {
+	b := &Blacklist{
+		entries:      map[provider.Name]map[string]types.BlacklistEntry{},
+		strikes:      map[provider.Name]map[string]int{},
+		missing:      map[provider.Name]map[string]time.Time{},
+		maxDeviation: maxDeviation,
+		maxStrikes:   maxStrikes,
+		duration:     duration,
+	}
+
+	for providerName, symbols := range static {
+		for _, symbol := range symbols {
+			b.add(types.BlacklistEntry{
+				Provider: providerName.String(),
+				Symbol:   symbol,
+				Reason:   blacklistReasonConfig,
+			})
+		}
+	}
+
+	return b
+}
+
+// SetBlacklist sets the blacklist of provider symbols. By default nothing
+// is blacklisted.
+func (o *Oracle) SetBlacklist(blacklist *Blacklist) {
+	o.blacklist = blacklist
+}
+
+func (b *Blacklist) add(entry types.BlacklistEntry) {
+	providerName := provider.Name(entry.Provider)
+	_, found := b.entries[providerName]
+	if !found {
+		b.entries[providerName] = map[string]types.BlacklistEntry{}
+	}
+	b.entries[providerName][entry.Symbol] = entry
+}
+
+// SetDelistAfter sets the duration after which pairs without ticker are
+// delisted. Delisting missing tickers is disabled, if it's 0.
+func (b *Blacklist) SetDelistAfter(delistAfter time.Duration) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.delistAfter = delistAfter
+}
+
+// Delist blacklists the symbol of the provider until the feeder restarts, as
+// the provider doesn't list it anymore. It returns false if the symbol is
+// blacklisted already.
+func (b *Blacklist) Delist(
+	providerName provider.Name,
+	symbol string,
+) (types.BlacklistEntry, bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.delist(providerName, symbol)
+}
+
+func (b *Blacklist) delist(
+	providerName provider.Name,
+	symbol string,
+) (types.BlacklistEntry, bool) {
+	if entry, found := b.entries[providerName][symbol]; found && entry.Until == nil {
+		return entry, false
+	}
+
+	delete(b.missing[providerName], symbol)
+
+	entry := types.BlacklistEntry{
+		Provider: providerName.String(),
+		Symbol:   symbol,
+		Reason:   blacklistReasonDelisted,
+	}
+	b.add(entry)
+	return entry, true
+}
+
+// ObserveTicker tracks whether the provider returned a ticker for the
+// symbol and delists the symbol, once its ticker is missing for
+// delistAfter. It returns the entry, if the symbol was delisted.
+func (b *Blacklist) ObserveTicker(
+	providerName provider.Name,
+	symbol string,
+	found bool,
+	now time.Time,
+) (types.BlacklistEntry, bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if found || b.delistAfter <= 0 {
+		delete(b.missing[providerName], symbol)
+		return types.BlacklistEntry{}, false
+	}
+
+	if _, ok := b.missing[providerName]; !ok {
+		b.missing[providerName] = map[string]time.Time{}
+	}
+
+	since, ok := b.missing[providerName][symbol]
+	if !ok {
+		b.missing[providerName][symbol] = now
+		return types.BlacklistEntry{}, false
+	}
+
+	if now.Sub(since) < b.delistAfter {
+		return types.BlacklistEntry{}, false
+	}
+
+	return b.delist(providerName, symbol)
+}
+
+// IsBlacklisted returns true if the symbol of the given provider is
+// blacklisted at the provided time.
+func (b *Blacklist) IsBlacklisted(
+	providerName provider.Name,
+	symbol string,
+	now time.Time,
+) bool {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+
+	entry, found := b.entries[providerName][symbol]
+	if !found {
+		return false
+	}
+
+	return entry.Until == nil || now.Before(*entry.Until)
+}
+
+// Update compares each provider ticker with the computed USD prices and
+// blacklists a symbol, once it deviated more than maxDeviation for maxStrikes
+// consecutive times. It returns the newly blacklisted entries.
+func (b *Blacklist) Update(
+	providerPrices provider.AggregatedProviderPrices,
+	providerPairs map[provider.Name][]types.CurrencyPair,
+	prices map[string]sdk.Dec,
+	now time.Time,
+) []types.BlacklistEntry {
+	if b.maxStrikes <= 0 {
+		return nil
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	added := []types.BlacklistEntry{}
+
+	for providerName, pairs := range providerPairs {
+		for _, pair := range pairs {
+			symbol := pair.String()
+
+			ticker, found := providerPrices[providerName][symbol]
+			if !found {
+				continue
+			}
+
+			base, found := prices[pair.Base]
+			if !found || base.IsZero() {
+				continue
+			}
+
+			quote := sdk.OneDec()
+			if pair.Quote != "USD" {
+				quote, found = prices[pair.Quote]
+				if !found {
+					continue
+				}
+			}
+
+			deviation := ticker.Price.Mul(quote).Sub(base).Abs().Quo(base)
+
+			_, found = b.strikes[providerName]
+			if !found {
+				b.strikes[providerName] = map[string]int{}
+			}
+
+			if deviation.LTE(b.maxDeviation) {
+				delete(b.strikes[providerName], symbol)
+				continue
+			}
+
+			b.strikes[providerName][symbol]++
+			if b.strikes[providerName][symbol] < b.maxStrikes {
+				continue
+			}
+
+			delete(b.strikes[providerName], symbol)
+
+			until := now.Add(b.duration)
+			entry := types.BlacklistEntry{
+				Provider: providerName.String(),
+				Symbol:   symbol,
+				Reason:   blacklistReasonDeviation,
+				Until:    &until,
+			}
+			b.add(entry)
+			added = append(added, entry)
+		}
+	}
+
+	return added
+}
+
+// Entries returns all active blacklist entries, sorted by provider and symbol.
+func (b *Blacklist) Entries(now time.Time) []types.BlacklistEntry {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+
+	entries := []types.BlacklistEntry{}
+	for _, symbols := range b.entries {
+		for _, entry := range symbols {
+			if entry.Until != nil && !now.Before(*entry.Until) {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Provider != entries[j].Provider {
+			return entries[i].Provider < entries[j].Provider
+		}
+		return entries[i].Symbol < entries[j].Symbol
+	})
+
+	return entries
+}
@@ -0,0 +1,104 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlacklistStatic(t *testing.T) {
+	blacklist := NewBlacklist(
+		map[provider.Name][]string{
+			provider.ProviderHuobi: {"LUNAUSDT"},
+		},
+		sdk.MustNewDecFromStr("0.1"),
+		0,
+		time.Hour,
+	)
+
+	now := time.Now()
+	require.True(t, blacklist.IsBlacklisted(provider.ProviderHuobi, "LUNAUSDT", now))
+	require.False(t, blacklist.IsBlacklisted(provider.ProviderHuobi, "ATOMUSDT", now))
+	require.False(t, blacklist.IsBlacklisted(provider.ProviderBinance, "LUNAUSDT", now))
+	require.Len(t, blacklist.Entries(now), 1)
+}
+
+func TestBlacklistUpdate(t *testing.T) {
+	blacklist := NewBlacklist(nil, sdk.MustNewDecFromStr("0.1"), 2, time.Hour)
+
+	pair := types.CurrencyPair{Base: "LUNA", Quote: "USDT"}
+	providerPairs := map[provider.Name][]types.CurrencyPair{
+		provider.ProviderHuobi: {pair},
+	}
+	prices := map[string]sdk.Dec{
+		"LUNA": sdk.MustNewDecFromStr("1"),
+		"USDT": sdk.MustNewDecFromStr("1"),
+	}
+
+	newPrices := func(price string) provider.AggregatedProviderPrices {
+		return provider.AggregatedProviderPrices{
+			provider.ProviderHuobi: {
+				"LUNAUSDT": {Price: sdk.MustNewDecFromStr(price)},
+			},
+		}
+	}
+
+	now := time.Now()
+
+	// deviating price, first strike
+	added := blacklist.Update(newPrices("2"), providerPairs, prices, now)
+	require.Len(t, added, 0)
+
+	// valid price resets strikes
+	added = blacklist.Update(newPrices("1.05"), providerPairs, prices, now)
+	require.Len(t, added, 0)
+
+	added = blacklist.Update(newPrices("2"), providerPairs, prices, now)
+	require.Len(t, added, 0)
+	require.False(t, blacklist.IsBlacklisted(provider.ProviderHuobi, "LUNAUSDT", now))
+
+	added = blacklist.Update(newPrices("2"), providerPairs, prices, now)
+	require.Len(t, added, 1)
+	require.True(t, blacklist.IsBlacklisted(provider.ProviderHuobi, "LUNAUSDT", now))
+
+	// entry expires
+	later := now.Add(2 * time.Hour)
+	require.False(t, blacklist.IsBlacklisted(provider.ProviderHuobi, "LUNAUSDT", later))
+	require.Len(t, blacklist.Entries(later), 0)
+}
+
+func TestBlacklistDelisting(t *testing.T) {
+	blacklist := NewBlacklist(nil, sdk.MustNewDecFromStr("0.1"), 0, time.Hour)
+	blacklist.SetDelistAfter(time.Hour)
+
+	now := time.Now()
+	_, delisted := blacklist.ObserveTicker(provider.ProviderHuobi, "LUNAUSDT", false, now)
+	require.False(t, delisted)
+
+	// a ticker in between resets the missing time
+	_, delisted = blacklist.ObserveTicker(provider.ProviderHuobi, "LUNAUSDT", true, now.Add(30*time.Minute))
+	require.False(t, delisted)
+	_, delisted = blacklist.ObserveTicker(provider.ProviderHuobi, "LUNAUSDT", false, now.Add(time.Hour))
+	require.False(t, delisted)
+	_, delisted = blacklist.ObserveTicker(provider.ProviderHuobi, "LUNAUSDT", false, now.Add(90*time.Minute))
+	require.False(t, delisted)
+
+	entry, delisted := blacklist.ObserveTicker(provider.ProviderHuobi, "LUNAUSDT", false, now.Add(2*time.Hour))
+	require.True(t, delisted)
+	require.Equal(t, blacklistReasonDelisted, entry.Reason)
+	require.Nil(t, entry.Until)
+	require.True(t, blacklist.IsBlacklisted(provider.ProviderHuobi, "LUNAUSDT", now.Add(24*time.Hour)))
+
+	// delisted pairs are only reported once
+	_, delisted = blacklist.Delist(provider.ProviderHuobi, "LUNAUSDT")
+	require.False(t, delisted)
+
+	_, delisted = blacklist.Delist(provider.ProviderBinance, "LUNAUSDT")
+	require.True(t, delisted)
+	require.Len(t, blacklist.Entries(now), 2)
+}
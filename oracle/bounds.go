@@ -0,0 +1,131 @@
+package oracle
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/pkg/events"
+)
+
+type (
+	// PriceBound defines the plausible USD price range of a denom.
+	PriceBound struct {
+		Min sdk.Dec
+		Max sdk.Dec
+	}
+
+	// PriceOutOfBounds defines a computed price outside the plausible range
+	// of its denom.
+	PriceOutOfBounds struct {
+		Denom string  `json:"denom"`
+		Price sdk.Dec `json:"price"`
+		Min   sdk.Dec `json:"min"`
+		Max   sdk.Dec `json:"max"`
+	}
+
+	// priceBoundFile defines a single denom of the price bounds file.
+	priceBoundFile struct {
+		Min string `toml:"min"`
+		Max string `toml:"max"`
+	}
+)
+
+// LoadPriceBounds reads the plausible price ranges by denom from a TOML
+// file, e.g.:
+//
+//	[BTC]
+//	min = "1000"
+//	max = "10000000"
+func LoadPriceBounds(path string) (map[string]PriceBound, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price bounds: %w", err)
+	}
+
+	var file map[string]priceBoundFile
+	if _, err := toml.Decode(string(data), &file); err != nil {
+		return nil, fmt.Errorf("failed to decode price bounds: %w", err)
+	}
+
+	bounds := make(map[string]PriceBound, len(file))
+	for denom, bound := range file {
+		min, err := sdk.NewDecFromStr(bound.Min)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min price of %s: %w", denom, err)
+		}
+		max, err := sdk.NewDecFromStr(bound.Max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max price of %s: %w", denom, err)
+		}
+		if !min.IsPositive() || !min.LT(max) {
+			return nil, fmt.Errorf(
+				"price bounds of %s must be positive with min below max", denom,
+			)
+		}
+		bounds[strings.ToUpper(denom)] = PriceBound{Min: min, Max: max}
+	}
+
+	return bounds, nil
+}
+
+// PriceBounds returns a middleware for StageValidate, removing all prices
+// outside the plausible range of their denom. It's the last line of
+// defense against decimals or inversion bugs, so every removed price is
+// reported to the callback.
+func PriceBounds(
+	bounds map[string]PriceBound,
+	report func(PriceOutOfBounds),
+) Middleware {
+	return func(next StageFunc) StageFunc {
+		return func(state *PipelineState) error {
+			if err := next(state); err != nil {
+				return err
+			}
+
+			for denom, price := range state.Prices {
+				bound, found := bounds[denom]
+				if !found || (price.GTE(bound.Min) && price.LTE(bound.Max)) {
+					continue
+				}
+
+				state.excludeRates(denom, state.Contributors[denom], "price out of bounds")
+				delete(state.Prices, denom)
+				delete(state.Contributors, denom)
+
+				report(PriceOutOfBounds{
+					Denom: denom,
+					Price: price,
+					Min:   bound.Min,
+					Max:   bound.Max,
+				})
+			}
+
+			return nil
+		}
+	}
+}
+
+// SetPriceBounds registers the price bounds in the pipeline of the oracle.
+// Prices out of bounds are not voted and alerted.
+func (o *Oracle) SetPriceBounds(bounds map[string]PriceBound) error {
+	return o.Pipeline().Use(StageValidate, PriceBounds(bounds, func(out PriceOutOfBounds) {
+		o.logger.Error().
+			Str("denom", out.Denom).
+			Str("price", out.Price.String()).
+			Str("min", out.Min.String()).
+			Str("max", out.Max.String()).
+			Msg("price out of bounds, not voting it")
+
+		telemetry.IncrCounter(1, "price", "out_of_bounds")
+		o.publish(
+			events.TopicPriceOutOfBounds,
+			fmt.Sprintf("price of %s out of bounds", out.Denom),
+			out,
+		)
+	}))
+}
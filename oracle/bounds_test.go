@@ -0,0 +1,73 @@
+package oracle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPriceBounds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bounds.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+[btc]
+min = "1000"
+max = "10000000"
+`), 0o644))
+
+	bounds, err := LoadPriceBounds(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]PriceBound{
+		"BTC": {Min: sdk.NewDec(1000), Max: sdk.NewDec(10000000)},
+	}, bounds)
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+[BTC]
+min = "1000"
+max = "100"
+`), 0o644))
+	_, err = LoadPriceBounds(path)
+	require.Error(t, err)
+}
+
+func TestPriceBounds(t *testing.T) {
+	providerPrices, providerPairs, minOverrides := testPipelineInputs()
+
+	// the price of 1.5 is within the bounds
+	reported := []PriceOutOfBounds{}
+	report := func(out PriceOutOfBounds) {
+		reported = append(reported, out)
+	}
+
+	pipeline := NewPipeline()
+	require.NoError(t, pipeline.Use(StageValidate, PriceBounds(map[string]PriceBound{
+		"KUJI": {Min: sdk.OneDec(), Max: sdk.NewDec(2)},
+	}, report)))
+
+	state, err := runPipeline(
+		pipeline, zerolog.Nop(), providerPrices, providerPairs,
+		nil, minOverrides, nil,
+	)
+	require.NoError(t, err)
+	require.Contains(t, state.Prices, "KUJI")
+	require.Empty(t, reported)
+
+	// an inverted price is removed and reported
+	pipeline = NewPipeline()
+	require.NoError(t, pipeline.Use(StageValidate, PriceBounds(map[string]PriceBound{
+		"KUJI": {Min: sdk.NewDec(10), Max: sdk.NewDec(100)},
+	}, report)))
+
+	state, err = runPipeline(
+		pipeline, zerolog.Nop(), providerPrices, providerPairs,
+		nil, minOverrides, nil,
+	)
+	require.NoError(t, err)
+	require.NotContains(t, state.Prices, "KUJI")
+	require.Len(t, state.Excluded["KUJI"], 2)
+	require.Len(t, reported, 1)
+	require.Equal(t, "KUJI", reported[0].Denom)
+}
@@ -0,0 +1,253 @@
+package oracle
+
+import (
+	"context"
+	"math"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/oracle/client"
+	"price-feeder/oracle/history"
+	"price-feeder/oracle/votelog"
+	"price-feeder/pkg/events"
+)
+
+const (
+	txKindPrevote = "prevote"
+	txKindVote    = "vote"
+)
+
+// SetVoteLog enables appending every vote to the vote log.
+func (o *Oracle) SetVoteLog(voteLog *votelog.VoteLog) {
+	o.voteLog = voteLog
+}
+
+// SetBatchVotes enables broadcasting the prevote of the next period with
+// the vote, if supported by the chain profile.
+func (o *Oracle) SetBatchVotes(batchVotes bool) {
+	o.batchVotes = batchVotes
+}
+
+// pendingTx defines a prevote or vote waiting to be broadcast.
+type pendingTx struct {
+	kind string
+	msgs []sdk.Msg
+	// hash is the vote hash of a prevote.
+	hash string
+	// afterVote is set for a prevote broadcast after the vote of the same
+	// tick, it must be committed within the period of the vote.
+	afterVote bool
+	// batched is set for a vote carrying the prevote of the next period.
+	batched bool
+}
+
+// txQueue holds the pending txs of a tick in separate queues for votes and
+// prevotes. Votes are always broadcast first, as a missing vote counts
+// against the validator, while a late prevote only delays the next vote.
+type txQueue struct {
+	votes    []pendingTx
+	prevotes []pendingTx
+}
+
+func (q *txQueue) push(tx pendingTx) {
+	if tx.kind == txKindVote {
+		q.votes = append(q.votes, tx)
+	} else {
+		q.prevotes = append(q.prevotes, tx)
+	}
+}
+
+func (q *txQueue) pop() (pendingTx, bool) {
+	if len(q.votes) > 0 {
+		tx := q.votes[0]
+		q.votes = q.votes[1:]
+		return tx, true
+	}
+	if len(q.prevotes) > 0 {
+		tx := q.prevotes[0]
+		q.prevotes = q.prevotes[1:]
+		return tx, true
+	}
+	return pendingTx{}, false
+}
+
+// voteTimeoutBlocks returns the number of blocks a vote broadcast for
+// nextBlockHeight is retried: until the end of its vote period, or less if
+// configured.
+func (t timing) voteTimeoutBlocks(nextBlockHeight, votePeriod int64) int64 {
+	timeout := votePeriod - nextBlockHeight%votePeriod
+	if t.voteTimeout > 0 && t.voteTimeout < timeout {
+		timeout = t.voteTimeout
+	}
+	return timeout
+}
+
+// prevoteTimeoutBlocks returns the number of blocks a prevote broadcast for
+// nextBlockHeight is retried. A prevote following a vote is limited to the
+// vote period ending at periodEnd, so it never runs into the next period.
+// A standalone prevote is retried for two vote periods by default.
+func (t timing) prevoteTimeoutBlocks(
+	nextBlockHeight, votePeriod, periodEnd int64,
+	afterVote bool,
+) int64 {
+	timeout := votePeriod * 2
+	if t.prevoteTimeout > 0 {
+		timeout = t.prevoteTimeout
+	}
+	if afterVote && periodEnd-nextBlockHeight < timeout {
+		timeout = periodEnd - nextBlockHeight
+	}
+	return timeout
+}
+
+// broadcastPrevote broadcasts the prevote and remembers its salt and rates,
+// to reveal them in the next vote period.
+func (o *Oracle) broadcastPrevote(
+	ctx context.Context,
+	tx pendingTx,
+	nextBlockHeight, votePeriod int64,
+	prevote PreviousPrevote,
+) error {
+	periodEnd := (nextBlockHeight/votePeriod + 1) * votePeriod
+	if tx.afterVote {
+		// the vote took some blocks, the prevote starts at the current height
+		height, err := o.oracleClient.ChainHeight.GetChainHeight()
+		if err != nil {
+			return err
+		}
+		nextBlockHeight = height + 1
+	}
+
+	timeout := o.timing.prevoteTimeoutBlocks(nextBlockHeight, votePeriod, periodEnd, tx.afterVote)
+	if timeout <= 0 {
+		o.logger.Info().Msg("no blocks left in the vote period, skipping pre-vote")
+		return nil
+	}
+
+	o.logger.Info().
+		Str("hash", tx.hash).
+		Str("validator", o.oracleClient.ValidatorAddrString).
+		Str("feeder", o.oracleClient.OracleAddrString).
+		Int64("timeout_blocks", timeout).
+		Msg("broadcasting pre-vote")
+	result, err := o.oracleClient.BroadcastTx(nextBlockHeight, timeout, tx.msgs...)
+	if err != nil {
+		return err
+	}
+
+	go o.awaitPrevote(ctx, result.Hash, nextBlockHeight, votePeriod)
+	o.publish(events.TopicPrevoteBroadcast, "", tx.msgs[0])
+
+	return o.rememberPrevote(votePeriod, prevote)
+}
+
+// broadcastVote reveals the previous prevote. A batched vote also carries
+// the prevote of the next period, which is remembered once committed.
+func (o *Oracle) broadcastVote(
+	ctx context.Context,
+	tx pendingTx,
+	nextBlockHeight, votePeriod int64,
+	prevote PreviousPrevote,
+) error {
+	exchangeRates := o.previousPrevote.ExchangeRates
+	timeout := o.timing.voteTimeoutBlocks(nextBlockHeight, votePeriod)
+
+	o.logger.Info().
+		Str("exchange_rates", exchangeRates).
+		Str("validator", o.oracleClient.ValidatorAddrString).
+		Str("feeder", o.oracleClient.OracleAddrString).
+		Bool("batched", tx.batched).
+		Int64("timeout_blocks", timeout).
+		Msg("broadcasting vote")
+	result, err := o.oracleClient.BroadcastTx(nextBlockHeight, timeout, tx.msgs...)
+	if err != nil {
+		return err
+	}
+
+	o.watchdog.lastVote = time.Now()
+
+	go o.awaitVote(ctx, result, time.Now(), exchangeRates, nextBlockHeight, votePeriod)
+	o.logVoteDiff(exchangeRates)
+
+	o.setPreviousPrevote(0, nil)
+	o.publish(events.TopicVoteBroadcast, "", tx.msgs[0])
+
+	if tx.batched {
+		return o.rememberPrevote(votePeriod, prevote)
+	}
+	o.saveState()
+	return nil
+}
+
+// recordVote adds the vote broadcasted at the given time to the history and
+// the vote log. The height is the inclusion height of the vote, or 0 if it
+// wasn't observed.
+func (o *Oracle) recordVote(
+	result client.TxResult,
+	broadcastTime time.Time,
+	height int64,
+	exchangeRates string,
+) {
+	err := o.history.AddVote(history.Vote{
+		Time:          broadcastTime,
+		Height:        height,
+		ExchangeRates: exchangeRates,
+		TxHash:        result.Hash,
+	})
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("failed to add vote to history")
+	}
+
+	if o.voteLog == nil {
+		return
+	}
+
+	err = o.voteLog.Append(votelog.Vote{
+		Time:          broadcastTime,
+		Height:        height,
+		ExchangeRates: exchangeRates,
+		TxHash:        result.Hash,
+		Fee:           result.Fee.String(),
+	})
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("failed to append vote to vote log")
+	}
+}
+
+// setPreviousPrevote sets the prevote to be revealed and its vote period.
+// Both are only written by the oracle loop, but read by Dump, so writes are
+// guarded by the lock.
+func (o *Oracle) setPreviousPrevote(votePeriod float64, prevote *PreviousPrevote) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	o.previousVotePeriod = votePeriod
+	o.previousPrevote = prevote
+}
+
+// rememberPrevote stores the committed prevote, to be revealed in the vote
+// period following the current one.
+func (o *Oracle) rememberPrevote(votePeriod int64, prevote PreviousPrevote) error {
+	currentHeight, err := o.oracleClient.ChainHeight.GetChainHeight()
+	if err != nil {
+		return err
+	}
+
+	prevote.SubmitBlockHeight = currentHeight
+	o.setPreviousPrevote(
+		math.Floor(float64(currentHeight)/float64(votePeriod)),
+		&prevote,
+	)
+	o.saveState()
+
+	votedPrices := make(map[string]sdk.Dec, len(prevote.prices))
+	for _, price := range prevote.prices {
+		votedPrices[price.Denom] = price.Amount
+	}
+	o.mtx.Lock()
+	o.votedPrices = votedPrices
+	o.mtx.Unlock()
+
+	return nil
+}
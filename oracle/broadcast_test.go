@@ -0,0 +1,47 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxQueue(t *testing.T) {
+	var q txQueue
+	q.push(pendingTx{kind: txKindPrevote, hash: "next"})
+	q.push(pendingTx{kind: txKindVote})
+
+	// the vote is broadcast first, even if queued after the prevote
+	tx, ok := q.pop()
+	require.True(t, ok)
+	require.Equal(t, txKindVote, tx.kind)
+
+	tx, ok = q.pop()
+	require.True(t, ok)
+	require.Equal(t, txKindPrevote, tx.kind)
+	require.Equal(t, "next", tx.hash)
+
+	_, ok = q.pop()
+	require.False(t, ok)
+}
+
+func TestBroadcastTimeouts(t *testing.T) {
+	var tm timing
+
+	// votes are retried until the end of their vote period
+	require.Equal(t, int64(4), tm.voteTimeoutBlocks(150, 14))
+	require.Equal(t, int64(14), tm.voteTimeoutBlocks(140, 14))
+
+	// standalone prevotes are retried for two periods, prevotes following
+	// a vote only until the end of the period
+	require.Equal(t, int64(28), tm.prevoteTimeoutBlocks(150, 14, 154, false))
+	require.Equal(t, int64(2), tm.prevoteTimeoutBlocks(152, 14, 154, true))
+	require.Equal(t, int64(0), tm.prevoteTimeoutBlocks(154, 14, 154, true))
+
+	tm.voteTimeout = 2
+	tm.prevoteTimeout = 1
+	require.Equal(t, int64(2), tm.voteTimeoutBlocks(150, 14))
+	require.Equal(t, int64(1), tm.voteTimeoutBlocks(153, 14))
+	require.Equal(t, int64(1), tm.prevoteTimeoutBlocks(150, 14, 154, false))
+	require.Equal(t, int64(1), tm.prevoteTimeoutBlocks(150, 14, 154, true))
+}
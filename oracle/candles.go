@@ -0,0 +1,40 @@
+package oracle
+
+import (
+	"time"
+
+	"price-feeder/oracle/types"
+)
+
+// candlePeriod defines the period of candles used for the TVWAP, matching
+// the candles kept by the providers.
+const candlePeriod = 10 * time.Minute
+
+// applyCandles returns the tickers with the last trade price replaced by the
+// TVWAP of the recent candles of the symbol, if the provider reported any.
+// The 24h volume of the ticker is kept for the weighting between providers.
+// The tickers are copied instead of modified in place.
+func applyCandles(
+	tickers map[string]types.TickerPrice,
+	candles map[string][]types.CandlePrice,
+	now time.Time,
+) map[string]types.TickerPrice {
+	applied := make(map[string]types.TickerPrice, len(tickers))
+	for symbol, ticker := range tickers {
+		applied[symbol] = ticker
+
+		symbolCandles, found := candles[symbol]
+		if !found || len(symbolCandles) == 0 {
+			continue
+		}
+
+		tvwap, err := ComputeTVWAP(symbolCandles, now, candlePeriod)
+		if err != nil || !tvwap.IsPositive() {
+			continue
+		}
+
+		ticker.Price = tvwap
+		applied[symbol] = ticker
+	}
+	return applied
+}
@@ -0,0 +1,34 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/oracle/types"
+)
+
+func TestApplyCandles(t *testing.T) {
+	now := time.Now()
+	tickers := map[string]types.TickerPrice{
+		"ATOMUSDT": {Price: sdk.MustNewDecFromStr("10.5"), Volume: sdk.MustNewDecFromStr("1000")},
+		"BTCUSDT":  {Price: sdk.MustNewDecFromStr("30000"), Volume: sdk.MustNewDecFromStr("10")},
+	}
+	candles := map[string][]types.CandlePrice{
+		"ATOMUSDT": {
+			{Price: sdk.MustNewDecFromStr("10"), Volume: sdk.MustNewDecFromStr("5"), TimeStamp: now.UnixMilli()},
+			{Price: sdk.MustNewDecFromStr("10"), Volume: sdk.MustNewDecFromStr("7"), TimeStamp: now.Add(-time.Minute).UnixMilli()},
+		},
+	}
+
+	applied := applyCandles(tickers, candles, now)
+
+	require.Equal(t, "10.000000000000000000", applied["ATOMUSDT"].Price.String())
+	require.Equal(t, "1000.000000000000000000", applied["ATOMUSDT"].Volume.String())
+	require.Equal(t, "30000.000000000000000000", applied["BTCUSDT"].Price.String())
+
+	// the tickers are not modified
+	require.Equal(t, "10.500000000000000000", tickers["ATOMUSDT"].Price.String())
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+type (
+	// SignDoc defines everything signed by the feeder key for a single tx.
+	// The sign bytes are the exact bytes signed, the tx contains the decoded
+	// body, auth info and signature.
+	SignDoc struct {
+		Time          time.Time       `json:"time"`
+		ChainID       string          `json:"chain_id"`
+		AccountNumber uint64          `json:"account_number"`
+		Sequence      uint64          `json:"sequence"`
+		SignMode      string          `json:"sign_mode"`
+		SignBytes     []byte          `json:"sign_bytes"`
+		SignBytesHash string          `json:"sign_bytes_hash"`
+		Tx            json.RawMessage `json:"tx"`
+	}
+
+	// AuditLog appends the sign doc of every signed tx to a JSON lines file,
+	// before the tx is broadcast.
+	AuditLog struct {
+		mtx  sync.Mutex
+		path string
+	}
+)
+
+func NewAuditLog(path string) (*AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	return &AuditLog{path: path}, nil
+}
+
+func (l *AuditLog) Append(doc SignDoc) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	// the tx is only broadcast once its sign doc is persisted
+	return file.Sync()
+}
+
+// newSignDoc recomputes the sign bytes of the signed tx.
+func newSignDoc(
+	clientCtx client.Context,
+	txf tx.Factory,
+	signedTx client.TxBuilder,
+) (SignDoc, error) {
+	sigs, err := signedTx.GetTx().GetSignaturesV2()
+	if err != nil {
+		return SignDoc{}, err
+	}
+	if len(sigs) != 1 {
+		return SignDoc{}, fmt.Errorf("expected one signature, got %d", len(sigs))
+	}
+
+	signMode := txf.SignMode()
+	signerData := authsigning.SignerData{
+		ChainID:       txf.ChainID(),
+		AccountNumber: txf.AccountNumber(),
+		Sequence:      txf.Sequence(),
+		PubKey:        sigs[0].PubKey,
+		Address:       clientCtx.GetFromAddress().String(),
+	}
+
+	signBytes, err := clientCtx.TxConfig.SignModeHandler().GetSignBytes(
+		signMode, signerData, signedTx.GetTx(),
+	)
+	if err != nil {
+		return SignDoc{}, err
+	}
+
+	txJSON, err := clientCtx.TxConfig.TxJSONEncoder()(signedTx.GetTx())
+	if err != nil {
+		return SignDoc{}, err
+	}
+
+	hash := sha256.Sum256(signBytes)
+
+	return SignDoc{
+		Time:          time.Now(),
+		ChainID:       signerData.ChainID,
+		AccountNumber: signerData.AccountNumber,
+		Sequence:      signerData.Sequence,
+		SignMode:      signMode.String(),
+		SignBytes:     signBytes,
+		SignBytesHash: hex.EncodeToString(hash[:]),
+		Tx:            txJSON,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	kujiraapp "github.com/Team-Kujira/core/app"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	encoding := kujiraapp.MakeEncodingConfig()
+	kr := keyring.NewInMemory(encoding.Codec)
+	record, _, err := kr.NewMnemonic(
+		"feeder", keyring.English, sdk.FullFundraiserPath, "", hd.Secp256k1,
+	)
+	require.NoError(t, err)
+	address, err := record.GetAddress()
+	require.NoError(t, err)
+
+	clientCtx := client.Context{}.
+		WithTxConfig(encoding.TxConfig).
+		WithKeyring(kr).
+		WithFromName("feeder").
+		WithFromAddress(address)
+
+	txf := tx.Factory{}.
+		WithTxConfig(encoding.TxConfig).
+		WithKeybase(kr).
+		WithChainID("kaiyo-1").
+		WithAccountNumber(7).
+		WithSequence(42).
+		WithGas(100000).
+		WithSignMode(signing.SignMode_SIGN_MODE_DIRECT)
+
+	unsignedTx, err := txf.BuildUnsignedTx(
+		banktypes.NewMsgSend(address, address, sdk.NewCoins(sdk.NewInt64Coin("ukuji", 1))),
+	)
+	require.NoError(t, err)
+	require.NoError(t, tx.Sign(txf, "feeder", unsignedTx, true))
+
+	signDoc, err := newSignDoc(clientCtx, txf, unsignedTx)
+	require.NoError(t, err)
+	require.Equal(t, "kaiyo-1", signDoc.ChainID)
+	require.Equal(t, uint64(7), signDoc.AccountNumber)
+	require.Equal(t, uint64(42), signDoc.Sequence)
+
+	// the recomputed sign bytes are the ones signed
+	sigs, err := unsignedTx.GetTx().GetSignaturesV2()
+	require.NoError(t, err)
+	signature := sigs[0].Data.(*signing.SingleSignatureData).Signature
+	require.True(t, sigs[0].PubKey.VerifySignature(signDoc.SignBytes, signature))
+
+	path := filepath.Join(t.TempDir(), "audit", "signdocs.jsonl")
+	auditLog, err := NewAuditLog(path)
+	require.NoError(t, err)
+	require.NoError(t, auditLog.Append(signDoc))
+	require.NoError(t, auditLog.Append(signDoc))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var logged SignDoc
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &logged))
+		require.Equal(t, signDoc.SignBytes, logged.SignBytes)
+		require.Equal(t, signDoc.SignBytesHash, logged.SignBytesHash)
+		lines++
+	}
+	require.Equal(t, 2, lines)
+}
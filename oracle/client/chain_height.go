@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/client"
@@ -11,22 +12,29 @@ import (
 type ChainHeight struct {
 	Logger zerolog.Logger
 	ctx context.Context
+	endpoints *EndpointPool
+	newRPC func(url string) (client.TendermintRPC, error)
 	rpc client.TendermintRPC
+	rpcURL string
 	pollInterval time.Duration
+	mtx sync.RWMutex
 	height int64
+	blockTime time.Time
 	err error
 }
 
 func NewChainHeight(
 	ctx context.Context,
-	rpc client.TendermintRPC,
+	endpoints *EndpointPool,
+	newRPC func(url string) (client.TendermintRPC, error),
 	logger zerolog.Logger,
 	pollInterval time.Duration,
 ) (*ChainHeight, error) {
 	c := &ChainHeight{
 		Logger: logger.With().Str("oracle_client", "chain_height").Logger(),
 		ctx: ctx,
-		rpc: rpc,
+		endpoints: endpoints,
+		newRPC: newRPC,
 		height: 0,
 		pollInterval: pollInterval,
 		err: nil,
@@ -44,10 +52,25 @@ func (c *ChainHeight) poll() {
 }
 
 func (c *ChainHeight) update() {
-	status, err := c.rpc.Status(c.ctx)
+	rpc, url, err := c.rpcClient()
+	if err != nil {
+		c.Logger.Warn().Err(err).Msg("failed to create rpc client")
+		c.mtx.Lock()
+		c.err = err
+		c.mtx.Unlock()
+		return
+	}
+
+	// the height isn't locked while waiting for the node
+	status, err := rpc.Status(c.ctx)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
 	if err == nil {
 		if c.height < status.SyncInfo.LatestBlockHeight {
 			c.height = status.SyncInfo.LatestBlockHeight
+			c.blockTime = status.SyncInfo.LatestBlockTime
 			c.Logger.Info().Int64("height", c.height).Msg("got new chain height")
 		} else {
 			c.Logger.Debug().
@@ -57,10 +80,52 @@ func (c *ChainHeight) update() {
 		}
 	} else {
 		c.Logger.Warn().Err(err).Msg("failed to get chain height")
+		c.endpoints.ReportFailure(url, err)
 	}
 	c.err = err
 }
 
+// rpcClient returns the rpc client of the active endpoint, following failovers
+// of the endpoint pool.
+func (c *ChainHeight) rpcClient() (client.TendermintRPC, string, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	url := c.endpoints.Active()
+	if c.rpc == nil || c.rpcURL != url {
+		rpc, err := c.newRPC(url)
+		if err != nil {
+			return nil, url, err
+		}
+		c.rpc = rpc
+		c.rpcURL = url
+	}
+	return c.rpc, url, nil
+}
+
 func (c *ChainHeight) GetChainHeight() (int64, error) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
 	return c.height, c.err
 }
+
+// GetBlockTime returns the header time of the latest block.
+func (c *ChainHeight) GetBlockTime() time.Time {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.blockTime
+}
+
+// Reset re-creates the rpc client and forgets the current height, so a
+// stalled height, e.g. after a node was rolled back or replaced by a node
+// lagging behind, is replaced by the next height received.
+func (c *ChainHeight) Reset() {
+	c.mtx.Lock()
+	c.rpc = nil
+	c.height = 0
+	c.mtx.Unlock()
+
+	c.update()
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -34,7 +35,7 @@ type (
 		KeyringBackend      string
 		KeyringDir          string
 		KeyringPass         string
-		TMRPC               string
+		TMRPC               *EndpointPool
 		RPCTimeout          time.Duration
 		OracleAddr          sdk.AccAddress
 		OracleAddrString    string
@@ -44,9 +45,19 @@ type (
 		Encoding            params.EncodingConfig
 		GasPrices           string
 		GasAdjustment       float64
-		GRPCEndpoint        string
+		GRPC                *EndpointPool
 		KeyringPassphrase   string
 		ChainHeight         *ChainHeight
+		Memo                string
+		TimeoutHeight       bool
+		AuditLog            *AuditLog
+	}
+
+	// TxResult defines the result of a successfully broadcasted transaction.
+	TxResult struct {
+		Hash   string
+		Height int64
+		Fee    sdk.Coins
 	}
 
 	passReader struct {
@@ -62,12 +73,12 @@ func NewOracleClient(
 	keyringBackend string,
 	keyringDir string,
 	keyringPass string,
-	tmRPC string,
+	tmRPCs []string,
 	rpcTimeout time.Duration,
 	oracleAddrString string,
 	validatorAddrString string,
 	feeGranterAddrString string,
-	grpcEndpoint string,
+	grpcEndpoints []string,
 	gasAdjustment float64,
 	gasPrices string,
 	heightPollInterval time.Duration,
@@ -85,7 +96,6 @@ func NewOracleClient(
 		KeyringBackend:      keyringBackend,
 		KeyringDir:          keyringDir,
 		KeyringPass:         keyringPass,
-		TMRPC:               tmRPC,
 		RPCTimeout:          rpcTimeout,
 		OracleAddr:          oracleAddr,
 		OracleAddrString:    oracleAddrString,
@@ -94,18 +104,27 @@ func NewOracleClient(
 		FeeGranterAddr:      feegrantAddrErr,
 		Encoding:            kujiraapp.MakeEncodingConfig(),
 		GasAdjustment:       gasAdjustment,
-		GRPCEndpoint:        grpcEndpoint,
 		GasPrices:           gasPrices,
 	}
+	oracleClient.TMRPC = NewEndpointPool(
+		oracleClient.Logger, "tmrpc", tmRPCs, oracleClient.probeTMRPC,
+	)
+	oracleClient.GRPC = NewEndpointPool(
+		oracleClient.Logger, "grpc", grpcEndpoints, oracleClient.probeGRPC,
+	)
 
-	clientCtx, err := oracleClient.CreateClientContext()
-	if err != nil {
-		return OracleClient{}, err
-	}
+	// the preferred endpoints may be down already at startup
+	oracleClient.TMRPC.Probe(ctx)
+	oracleClient.GRPC.Probe(ctx)
+	go oracleClient.TMRPC.Run(ctx)
+	go oracleClient.GRPC.Run(ctx)
 
 	chainHeight, err := NewChainHeight(
 		ctx,
-		clientCtx.Client,
+		oracleClient.TMRPC,
+		func(url string) (client.TendermintRPC, error) {
+			return oracleClient.newTMRPC(url)
+		},
 		oracleClient.Logger,
 		heightPollInterval,
 	)
@@ -137,25 +156,35 @@ func (r *passReader) Read(p []byte) (n int, err error) {
 // BroadcastTx attempts to broadcast a signed transaction. If it fails, a few re-attempts
 // will be made until the transaction succeeds or ultimately times out or fails.
 // Ref: https://github.com/terra-money/oracle-feeder/blob/baef2a4a02f57a2ffeaa207932b2e03d7fb0fb25/feeder/src/vote.ts#L230
-func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ...sdk.Msg) error {
+func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ...sdk.Msg) (TxResult, error) {
 	maxBlockHeight := nextBlockHeight + timeoutHeight
 	lastCheckHeight := nextBlockHeight - 1
 
 	clientCtx, err := oc.CreateClientContext()
 	if err != nil {
-		return err
+		return TxResult{}, err
 	}
 
 	factory, err := oc.CreateTxFactory()
 	if err != nil {
-		return err
+		return TxResult{}, err
 	}
 
+	// txs delayed beyond the retry window are rejected by the chain, instead
+	// of landing in the next vote period
+	if oc.TimeoutHeight {
+		factory = factory.WithTimeoutHeight(uint64(maxBlockHeight - 1))
+	}
+
+	// the sequence is only resynced once per block, so a node returning
+	// inconsistent sequences can't make us spin
+	var resyncHeight int64
+
 	// re-try voting until timeout
 	for lastCheckHeight < maxBlockHeight {
 		latestBlockHeight, err := oc.ChainHeight.GetChainHeight()
 		if err != nil {
-			return err
+			return TxResult{}, err
 		}
 
 		if latestBlockHeight <= lastCheckHeight {
@@ -165,10 +194,18 @@ func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ..
 		// set last check height to latest block height
 		lastCheckHeight = latestBlockHeight
 
-		resp, err := BroadcastTx(clientCtx, factory, msgs...)
+		// retries go to the endpoint failed over to
+		if clientCtx.NodeURI != oc.TMRPC.Active() {
+			clientCtx, err = oc.CreateClientContext()
+			if err != nil {
+				return TxResult{}, err
+			}
+		}
+
+		resp, fee, err := BroadcastTx(clientCtx, factory, oc.AuditLog, msgs...)
 		if resp != nil && resp.Code != 0 {
 			telemetry.IncrCounter(1, "failure", "tx", "code")
-			err = fmt.Errorf("invalid response code from tx: %d", resp.Code)
+			err = fmt.Errorf("invalid response code from tx: %d: %s", resp.Code, resp.RawLog)
 		}
 
 		if err != nil {
@@ -181,6 +218,37 @@ func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ..
 				hash = resp.TxHash
 			}
 
+			var recovery txRecovery
+			factory, recovery = recoverTx(factory, resp, err)
+
+			switch recovery {
+			case txInMempool:
+				telemetry.IncrCounter(1, "recovered", "tx", "mempool")
+				oc.Logger.Warn().
+					Str("tx_hash", hash).
+					Msg("tx already in mempool, waiting for inclusion")
+
+				return TxResult{
+					Hash:   hash,
+					Height: latestBlockHeight,
+					Fee:    fee,
+				}, nil
+
+			case txResync:
+				telemetry.IncrCounter(1, "recovered", "tx", "sequence")
+				oc.Logger.Warn().
+					Err(err).
+					Uint64("sequence", factory.Sequence()).
+					Msg("account sequence mismatch, resyncing sequence")
+
+				if resyncHeight != latestBlockHeight {
+					resyncHeight = latestBlockHeight
+					// retry within the same block
+					lastCheckHeight--
+					continue
+				}
+			}
+
 			oc.Logger.Debug().
 				Err(err).
 				Int64("max_height", maxBlockHeight).
@@ -199,11 +267,68 @@ func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ..
 			Int64("tx_height", resp.Height).
 			Msg("successfully broadcasted tx")
 
-		return nil
+		// sync broadcasts don't wait for the tx to be included
+		height := resp.Height
+		if height == 0 {
+			height = latestBlockHeight
+		}
+
+		return TxResult{
+			Hash:   resp.TxHash,
+			Height: height,
+			Fee:    fee,
+		}, nil
 	}
 
 	telemetry.IncrCounter(1, "failure", "tx", "timeout")
-	return errors.New("broadcasting tx timed out")
+	return TxResult{}, errors.New("broadcasting tx timed out")
+}
+
+// WaitForTx polls the tx by hash until it is included in a block and returns
+// the inclusion height. Txs are broadcasted in sync mode, which returns
+// before the inclusion, so the height of a TxResult is only the height the
+// tx was broadcasted at.
+func (oc OracleClient) WaitForTx(ctx context.Context, hash string) (int64, error) {
+	bz, err := hex.DecodeString(hash)
+	if err != nil {
+		return 0, fmt.Errorf("invalid tx hash %s: %w", hash, err)
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		// lookups go to the endpoint failed over to
+		tmRPC, err := oc.newTMRPC(oc.TMRPC.Active())
+		if err != nil {
+			return 0, err
+		}
+
+		res, err := tmRPC.Tx(ctx, bz, false)
+		if err == nil {
+			return res.Height, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("tx %s not included: %w", hash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetAccountSequence returns the current account sequence of the oracle
+// address.
+func (oc OracleClient) GetAccountSequence() (uint64, error) {
+	clientCtx, err := oc.CreateClientContext()
+	if err != nil {
+		return 0, err
+	}
+
+	_, sequence, err := clientCtx.AccountRetriever.GetAccountNumberSequence(
+		clientCtx, oc.OracleAddr,
+	)
+	return sequence, err
 }
 
 // CreateClientContext creates an SDK client Context instance used for transaction
@@ -216,14 +341,8 @@ func (oc OracleClient) CreateClientContext() (client.Context, error) {
 		return client.Context{}, err
 	}
 
-	httpClient, err := tmjsonclient.DefaultHTTPClient(oc.TMRPC)
-	if err != nil {
-		return client.Context{}, err
-	}
-
-	httpClient.Timeout = oc.RPCTimeout
-
-	tmRPC, err := rpchttp.NewWithClient(oc.TMRPC, "/websocket", httpClient)
+	nodeURI := oc.TMRPC.Active()
+	tmRPC, err := oc.newTMRPC(nodeURI)
 	if err != nil {
 		return client.Context{}, err
 	}
@@ -262,7 +381,7 @@ func (oc OracleClient) CreateClientContext() (client.Context, error) {
 		Codec:             oc.Encoding.Codec,
 		LegacyAmino:       oc.Encoding.Amino,
 		Input:             os.Stdin,
-		NodeURI:           oc.TMRPC,
+		NodeURI:           nodeURI,
 		Client:            tmRPC,
 		Keyring:           kr,
 		FromAddress:       oc.OracleAddr,
@@ -280,6 +399,18 @@ func (oc OracleClient) CreateClientContext() (client.Context, error) {
 	return clientCtx, nil
 }
 
+// newTMRPC returns a Tendermint RPC client of the endpoint.
+func (oc OracleClient) newTMRPC(url string) (*rpchttp.HTTP, error) {
+	httpClient, err := tmjsonclient.DefaultHTTPClient(url)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient.Timeout = oc.RPCTimeout
+
+	return rpchttp.NewWithClient(url, "/websocket", httpClient)
+}
+
 // CreateTxFactory creates an SDK Factory instance used for transaction
 // generation, signing and broadcasting.
 func (oc OracleClient) CreateTxFactory() (tx.Factory, error) {
@@ -296,7 +427,8 @@ func (oc OracleClient) CreateTxFactory() (tx.Factory, error) {
 		WithGasPrices(oc.GasPrices).
 		WithKeybase(clientCtx.Keyring).
 		WithSignMode(signing.SignMode_SIGN_MODE_DIRECT).
-		WithSimulateAndExecute(true)
+		WithSimulateAndExecute(true).
+		WithMemo(oc.Memo)
 
 	return txFactory, nil
 }
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+)
+
+// endpointProbeInterval defines how often all endpoints of a pool are
+// probed, to fail back to a preferred endpoint once it recovered.
+const endpointProbeInterval = 15 * time.Second
+
+// ProbeFunc checks the health of a single endpoint.
+type ProbeFunc func(ctx context.Context, url string) error
+
+// EndpointPool defines a list of endpoints of the same kind in order of
+// preference. The first healthy endpoint is active, failing endpoints are
+// skipped until a probe sees them recovered.
+type EndpointPool struct {
+	logger zerolog.Logger
+	kind   string
+	probe  ProbeFunc
+
+	mtx    sync.RWMutex
+	urls   []string
+	errs   []error
+	active int
+}
+
+// NewEndpointPool returns a pool of the endpoints, which must not be empty.
+// All endpoints are considered healthy until probed.
+func NewEndpointPool(
+	logger zerolog.Logger,
+	kind string,
+	urls []string,
+	probe ProbeFunc,
+) *EndpointPool {
+	return &EndpointPool{
+		logger: logger.With().Str("endpoint_kind", kind).Logger(),
+		kind:   kind,
+		probe:  probe,
+		urls:   urls,
+		errs:   make([]error, len(urls)),
+	}
+}
+
+// Active returns the endpoint currently in use.
+func (p *EndpointPool) Active() string {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	return p.urls[p.active]
+}
+
+// ReportFailure marks the endpoint unhealthy, e.g. after a failed request,
+// and fails over to the next healthy endpoint.
+func (p *EndpointPool) ReportFailure(url string, err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for i := range p.urls {
+		if p.urls[i] == url {
+			p.errs[i] = err
+		}
+	}
+	p.selectActive()
+}
+
+// Probe checks all endpoints and activates the most preferred healthy one.
+func (p *EndpointPool) Probe(ctx context.Context) {
+	p.mtx.RLock()
+	urls := make([]string, len(p.urls))
+	copy(urls, p.urls)
+	p.mtx.RUnlock()
+
+	errs := make([]error, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			errs[i] = p.probe(ctx, url)
+		}(i, url)
+	}
+	wg.Wait()
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for i, err := range errs {
+		if err != nil && p.errs[i] == nil {
+			p.logger.Warn().Err(err).Str("endpoint", urls[i]).Msg("endpoint unhealthy")
+		}
+		if err == nil && p.errs[i] != nil {
+			p.logger.Info().Str("endpoint", urls[i]).Msg("endpoint recovered")
+		}
+		p.errs[i] = err
+	}
+	p.selectActive()
+}
+
+// selectActive activates the first healthy endpoint. If all of them are
+// unhealthy, the active endpoint is kept. The caller must hold p.mtx.
+func (p *EndpointPool) selectActive() {
+	for i, err := range p.errs {
+		if err != nil {
+			continue
+		}
+		if i != p.active {
+			p.logger.Warn().
+				Str("from", p.urls[p.active]).
+				Str("to", p.urls[i]).
+				Msg("switching endpoint")
+
+			telemetry.IncrCounterWithLabels(
+				[]string{"rpc", "failover"},
+				1,
+				[]metrics.Label{telemetry.NewLabel("kind", p.kind)},
+			)
+			p.active = i
+		}
+		return
+	}
+}
+
+// Run probes the endpoints periodically until the context is done. A pool
+// of a single endpoint has nothing to fail over to and isn't probed.
+func (p *EndpointPool) Run(ctx context.Context) {
+	if len(p.urls) < 2 {
+		return
+	}
+
+	ticker := time.NewTicker(endpointProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Probe(ctx)
+		}
+	}
+}
+
+// probeTMRPC returns an error if the Tendermint RPC endpoint is unreachable
+// or its node is catching up.
+func (oc OracleClient) probeTMRPC(ctx context.Context, url string) error {
+	rpc, err := oc.newTMRPC(url)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, oc.RPCTimeout)
+	defer cancel()
+
+	status, err := rpc.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.SyncInfo.CatchingUp {
+		return fmt.Errorf("node is catching up")
+	}
+	return nil
+}
+
+// probeGRPC returns an error if the gRPC endpoint is unreachable or its node
+// is syncing.
+func (oc OracleClient) probeGRPC(ctx context.Context, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, oc.RPCTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(
+		ctx,
+		url,
+		// the Cosmos SDK doesn't support any transport security mechanism
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(dialEndpoint),
+	)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := tmservice.NewServiceClient(conn).GetSyncing(ctx, &tmservice.GetSyncingRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.Syncing {
+		return fmt.Errorf("node is syncing")
+	}
+	return nil
+}
+
+// dialEndpoint dials an address optionally prefixed with the protocol, e.g.
+// "tcp://127.0.0.1:9090" or "unix:///tmp/grpc.sock".
+func dialEndpoint(ctx context.Context, addr string) (net.Conn, error) {
+	proto, address := "tcp", addr
+	if parts := strings.SplitN(addr, "://", 2); len(parts) == 2 {
+		proto, address = parts[0], parts[1]
+	}
+
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, proto, address)
+}
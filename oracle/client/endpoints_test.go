@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProbe struct {
+	mtx  sync.Mutex
+	errs map[string]error
+}
+
+func (f *fakeProbe) set(url string, err error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.errs[url] = err
+}
+
+func (f *fakeProbe) probe(_ context.Context, url string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.errs[url]
+}
+
+func TestEndpointPool(t *testing.T) {
+	probe := &fakeProbe{errs: map[string]error{}}
+	pool := NewEndpointPool(
+		zerolog.Nop(), "tmrpc", []string{"a", "b", "c"}, probe.probe,
+	)
+	require.Equal(t, "a", pool.Active())
+
+	// failover on a reported failure
+	pool.ReportFailure("a", errors.New("connection refused"))
+	require.Equal(t, "b", pool.Active())
+
+	// the probe finds a recovered, but b failing
+	probe.set("b", errors.New("node is catching up"))
+	pool.Probe(context.Background())
+	require.Equal(t, "a", pool.Active())
+
+	// the most preferred healthy endpoint is used
+	probe.set("a", errors.New("timeout"))
+	pool.Probe(context.Background())
+	require.Equal(t, "c", pool.Active())
+
+	// all unhealthy keeps the active endpoint
+	probe.set("c", errors.New("timeout"))
+	pool.Probe(context.Background())
+	require.Equal(t, "c", pool.Active())
+
+	probe.set("b", nil)
+	pool.Probe(context.Background())
+	require.Equal(t, "b", pool.Active())
+}
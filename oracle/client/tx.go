@@ -1,49 +1,89 @@
 package client
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/tx"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// txRecovery defines how a failed broadcast is retried.
+type txRecovery int
+
+const (
+	// txRetry retries the broadcast with the next block.
+	txRetry txRecovery = iota
+	// txResync retries the broadcast immediately with the resynced account
+	// sequence.
+	txResync
+	// txInMempool stops retrying, because the tx is already pending.
+	txInMempool
 )
 
+// sequenceMismatch matches the error of the ante handler, if the sequence
+// of a tx doesn't match the account.
+var sequenceMismatch = regexp.MustCompile(`account sequence mismatch, expected (\d+)`)
+
 // BroadcastTx attempts to generate, sign and broadcast a transaction with the
 // given set of messages. It will also simulate gas requirements if necessary.
 // It will return an error upon failure.
 //
 // Note, BroadcastTx is copied from the SDK except it removes a few unnecessary
 // things like prompting for confirmation and printing the response. Instead,
-// we return the TxResponse.
-func BroadcastTx(clientCtx client.Context, txf tx.Factory, msgs ...sdk.Msg) (*sdk.TxResponse, error) {
+// we return the TxResponse and the fee paid. If an audit log is given, the
+// sign doc is appended to it before the tx is broadcast.
+func BroadcastTx(
+	clientCtx client.Context,
+	txf tx.Factory,
+	auditLog *AuditLog,
+	msgs ...sdk.Msg,
+) (*sdk.TxResponse, sdk.Coins, error) {
 	txf, err := prepareFactory(clientCtx, txf)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	_, adjusted, err := tx.CalculateGas(clientCtx, txf, msgs...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	txf = txf.WithGas(adjusted)
 
 	unsignedTx, err := txf.BuildUnsignedTx(msgs...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	unsignedTx.SetFeeGranter(clientCtx.GetFeeGranterAddress())
 	// unsignedTx.SetFeePayer(clientCtx.GetFeePayerAddress())
 
 	if err = tx.Sign(txf, clientCtx.GetFromName(), unsignedTx, true); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if auditLog != nil {
+		signDoc, err := newSignDoc(clientCtx, txf, unsignedTx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := auditLog.Append(signDoc); err != nil {
+			return nil, nil, fmt.Errorf("failed to append sign doc to audit log: %w", err)
+		}
 	}
 
 	txBytes, err := clientCtx.TxConfig.TxEncoder()(unsignedTx.GetTx())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return clientCtx.BroadcastTx(txBytes)
+	resp, err := clientCtx.BroadcastTx(txBytes)
+	return resp, unsignedTx.GetTx().GetFee(), err
 }
 
 // prepareFactory ensures the account defined by ctx.GetFromAddress() exists and
@@ -75,3 +115,40 @@ func prepareFactory(clientCtx client.Context, txf tx.Factory) (tx.Factory, error
 
 	return txf, nil
 }
+
+// recoverTx returns how a failed broadcast is retried and the factory to
+// retry with. On a sequence mismatch, the sequence expected by the chain is
+// used, or it's queried again if the error doesn't contain it. A tx already
+// in the mempool was broadcasted before, e.g. by a retry after a timeout.
+func recoverTx(txf tx.Factory, resp *sdk.TxResponse, err error) (tx.Factory, txRecovery) {
+	var (
+		message string
+		code    uint32
+	)
+	if resp != nil {
+		message = resp.RawLog
+		if resp.Codespace == sdkerrors.RootCodespace {
+			code = resp.Code
+		}
+	}
+	if err != nil {
+		message = err.Error() + " " + message
+	}
+
+	switch {
+	case code == sdkerrors.ErrTxInMempoolCache.ABCICode() ||
+		strings.Contains(message, sdkerrors.ErrTxInMempoolCache.Error()):
+		return txf, txInMempool
+
+	case code == sdkerrors.ErrWrongSequence.ABCICode() ||
+		strings.Contains(message, "account sequence mismatch"):
+		// a zero sequence is queried from the chain by prepareFactory
+		var sequence uint64
+		if match := sequenceMismatch.FindStringSubmatch(message); match != nil {
+			sequence, _ = strconv.ParseUint(match[1], 10, 64)
+		}
+		return txf.WithSequence(sequence), txResync
+	}
+
+	return txf, txRetry
+}
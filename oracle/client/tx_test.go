@@ -0,0 +1,89 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverTx(t *testing.T) {
+	testCases := []struct {
+		name     string
+		resp     *sdk.TxResponse
+		err      error
+		recovery txRecovery
+		sequence uint64
+	}{
+		{
+			name: "sequence mismatch in check tx",
+			resp: &sdk.TxResponse{
+				Codespace: sdkerrors.RootCodespace,
+				Code:      sdkerrors.ErrWrongSequence.ABCICode(),
+				RawLog:    "account sequence mismatch, expected 12, got 11: incorrect account sequence",
+			},
+			recovery: txResync,
+			sequence: 12,
+		},
+		{
+			name: "sequence mismatch in simulation",
+			err: errors.New(
+				"rpc error: code = Unknown desc = account sequence mismatch, " +
+					"expected 42, got 40: incorrect account sequence",
+			),
+			recovery: txResync,
+			sequence: 42,
+		},
+		{
+			name: "sequence mismatch without expected sequence",
+			resp: &sdk.TxResponse{
+				Codespace: sdkerrors.RootCodespace,
+				Code:      sdkerrors.ErrWrongSequence.ABCICode(),
+			},
+			recovery: txResync,
+			sequence: 0,
+		},
+		{
+			name: "tx already in mempool",
+			resp: &sdk.TxResponse{
+				Codespace: sdkerrors.RootCodespace,
+				Code:      sdkerrors.ErrTxInMempoolCache.ABCICode(),
+				TxHash:    "ABC",
+			},
+			recovery: txInMempool,
+			sequence: 7,
+		},
+		{
+			name:     "tx already in mempool error",
+			err:      errors.New("broadcast failed: tx already in mempool"),
+			recovery: txInMempool,
+			sequence: 7,
+		},
+		{
+			name: "other module with the same code",
+			resp: &sdk.TxResponse{
+				Codespace: "oracle",
+				Code:      sdkerrors.ErrWrongSequence.ABCICode(),
+			},
+			recovery: txRetry,
+			sequence: 7,
+		},
+		{
+			name:     "insufficient fees",
+			err:      errors.New("insufficient fees"),
+			recovery: txRetry,
+			sequence: 7,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			factory, recovery := recoverTx(tx.Factory{}.WithSequence(7), tc.resp, tc.err)
+			require.Equal(t, tc.recovery, recovery)
+			require.Equal(t, tc.sequence, factory.Sequence())
+		})
+	}
+}
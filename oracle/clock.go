@@ -0,0 +1,204 @@
+package oracle
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	"github.com/rs/zerolog"
+
+	"price-feeder/config"
+)
+
+const (
+	// defaultMaxClockDrift defines the default drift of the local clock, from
+	// which on a warning is logged.
+	defaultMaxClockDrift = 2 * time.Second
+
+	// clockDriftSamples defines the number of blocks the drift is estimated
+	// from.
+	clockDriftSamples = 20
+
+	ntpInterval = 10 * time.Minute
+	ntpTimeout  = 5 * time.Second
+	// seconds between the NTP epoch (1900) and the unix epoch (1970)
+	ntpEpochOffset = 2208988800
+)
+
+// clockMonitor detects drift of the local clock, which breaks the staleness
+// cutoffs and TWAP windows in subtle ways.
+//
+// Block times are set by the proposer and observed after a delay, so the
+// smallest age of the recent blocks is used as estimation, which is close to
+// zero for a correct clock and the drift otherwise. Positive values mean the
+// local clock is ahead.
+type clockMonitor struct {
+	logger    zerolog.Logger
+	maxDrift  time.Duration
+	ntpServer string
+
+	height  int64
+	samples []time.Duration
+}
+
+func newClockMonitor(logger zerolog.Logger, cfg config.Timing) *clockMonitor {
+	c := &clockMonitor{
+		logger:    logger.With().Str("module", "clock").Logger(),
+		maxDrift:  defaultMaxClockDrift,
+		ntpServer: cfg.NtpServer,
+	}
+
+	if cfg.MaxClockDrift != "" {
+		maxDrift, err := time.ParseDuration(cfg.MaxClockDrift)
+		if err == nil && maxDrift > 0 {
+			c.maxDrift = maxDrift
+		}
+	}
+
+	return c
+}
+
+// observeBlock adds the age of a new block and returns the estimated drift,
+// once enough blocks have been observed.
+func (c *clockMonitor) observeBlock(
+	height int64,
+	blockTime time.Time,
+	now time.Time,
+) (time.Duration, bool) {
+	if height <= c.height || blockTime.IsZero() {
+		return 0, false
+	}
+	c.height = height
+
+	c.samples = append(c.samples, now.Sub(blockTime))
+	if len(c.samples) > clockDriftSamples {
+		c.samples = c.samples[1:]
+	}
+
+	if len(c.samples) < clockDriftSamples {
+		return 0, false
+	}
+
+	drift := c.samples[0]
+	for _, sample := range c.samples[1:] {
+		if sample < drift {
+			drift = sample
+		}
+	}
+
+	c.report("chain", drift)
+
+	return drift, true
+}
+
+// runNtp periodically queries the NTP server, if configured.
+func (c *clockMonitor) runNtp(ctx context.Context) {
+	if c.ntpServer == "" {
+		return
+	}
+
+	ticker := time.NewTicker(ntpInterval)
+	defer ticker.Stop()
+
+	for {
+		offset, err := queryNtpOffset(c.ntpServer, ntpTimeout)
+		if err != nil {
+			c.logger.Warn().Err(err).Str("server", c.ntpServer).Msg("ntp query failed")
+		} else {
+			// the ntp offset is the correction of the local clock
+			c.report("ntp", -offset)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *clockMonitor) report(source string, drift time.Duration) {
+	telemetry.SetGaugeWithLabels(
+		[]string{"clock", "drift_ms"},
+		float32(drift.Milliseconds()),
+		[]metrics.Label{telemetry.NewLabel("source", source)},
+	)
+
+	if drift > c.maxDrift || drift < -c.maxDrift {
+		c.logger.Warn().
+			Str("source", source).
+			Dur("drift", drift).
+			Dur("max_drift", c.maxDrift).
+			Msg("local clock drift exceeds threshold")
+	}
+}
+
+// queryNtpOffset returns the offset of the local clock to the NTP server
+// using a single SNTP (RFC 4330) request.
+func queryNtpOffset(server string, timeout time.Duration) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+
+	conn, err := net.DialTimeout("udp", server, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+
+	request := make([]byte, 48)
+	// leap indicator 0, version 4, mode 3 (client)
+	request[0] = 0x23
+
+	sent := time.Now()
+	if _, err := conn.Write(request); err != nil {
+		return 0, err
+	}
+
+	response := make([]byte, 48)
+	n, err := conn.Read(response)
+	if err != nil {
+		return 0, err
+	}
+	received := time.Now()
+
+	return ntpOffset(response[:n], sent, received)
+}
+
+// ntpOffset computes the clock offset from an NTP response, given the local
+// send and receive times: ((t1 - t0) + (t2 - t3)) / 2.
+func ntpOffset(response []byte, sent, received time.Time) (time.Duration, error) {
+	if len(response) < 48 {
+		return 0, fmt.Errorf("invalid ntp response length %d", len(response))
+	}
+
+	mode := response[0] & 0x07
+	if mode != 4 {
+		return 0, fmt.Errorf("invalid ntp response mode %d", mode)
+	}
+
+	if response[1] == 0 {
+		return 0, fmt.Errorf("ntp server is unsynchronized (kiss-o'-death)")
+	}
+
+	receiveTime := ntpTime(response[32:40])
+	transmitTime := ntpTime(response[40:48])
+
+	offset := (receiveTime.Sub(sent) + transmitTime.Sub(received)) / 2
+	return offset, nil
+}
+
+func ntpTime(bz []byte) time.Time {
+	seconds := int64(binary.BigEndian.Uint32(bz[0:4])) - ntpEpochOffset
+	fraction := int64(binary.BigEndian.Uint32(bz[4:8]))
+	nanos := (fraction * int64(time.Second)) >> 32
+	return time.Unix(seconds, nanos)
+}
@@ -0,0 +1,70 @@
+package oracle
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+)
+
+func TestClockMonitorObserveBlock(t *testing.T) {
+	c := newClockMonitor(zerolog.Nop(), config.Timing{})
+	require.Equal(t, defaultMaxClockDrift, c.maxDrift)
+
+	now := time.Now()
+
+	// local clock 3s ahead, blocks are observed 0-900ms after creation
+	for i := int64(1); i <= clockDriftSamples; i++ {
+		blockTime := now.Add(time.Duration(i) * time.Second)
+		observed := blockTime.Add(3 * time.Second).Add(time.Duration(i%10) * 100 * time.Millisecond)
+
+		drift, ok := c.observeBlock(i, blockTime, observed)
+		if i < clockDriftSamples {
+			require.False(t, ok)
+			continue
+		}
+		require.True(t, ok)
+		require.Equal(t, 3*time.Second, drift)
+	}
+
+	// known heights are ignored
+	_, ok := c.observeBlock(clockDriftSamples, now, now)
+	require.False(t, ok)
+
+	c = newClockMonitor(zerolog.Nop(), config.Timing{MaxClockDrift: "500ms"})
+	require.Equal(t, 500*time.Millisecond, c.maxDrift)
+}
+
+func TestNtpOffset(t *testing.T) {
+	putTime := func(bz []byte, ts time.Time) {
+		binary.BigEndian.PutUint32(bz[0:4], uint32(ts.Unix()+ntpEpochOffset))
+		fraction := (int64(ts.Nanosecond()) << 32) / int64(time.Second)
+		binary.BigEndian.PutUint32(bz[4:8], uint32(fraction))
+	}
+
+	sent := time.Unix(1700000000, 0)
+	received := sent.Add(100 * time.Millisecond)
+
+	// server clock is 1s ahead, 50ms one way latency
+	response := make([]byte, 48)
+	response[0] = 0x24 // version 4, mode 4 (server)
+	response[1] = 2    // stratum
+	putTime(response[32:40], sent.Add(50*time.Millisecond).Add(time.Second))
+	putTime(response[40:48], sent.Add(50*time.Millisecond).Add(time.Second))
+
+	offset, err := ntpOffset(response, sent, received)
+	require.NoError(t, err)
+	require.InDelta(t, float64(time.Second), float64(offset), float64(time.Microsecond))
+
+	// kiss-o'-death
+	response[1] = 0
+	_, err = ntpOffset(response, sent, received)
+	require.Error(t, err)
+
+	_, err = ntpOffset(response[:10], sent, received)
+	require.Error(t, err)
+}
@@ -0,0 +1,147 @@
+package oracle
+
+import (
+	"context"
+
+	"price-feeder/config"
+	"price-feeder/oracle/labels"
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// defaultComparisonThreshold defines the relative difference between a
+// computed price and the price of a comparison source, above which the
+// price is reported as diverging.
+var defaultComparisonThreshold = sdk.MustNewDecFromStr("0.05")
+
+// SetComparison sets the sources the computed prices are compared to. An
+// invalid threshold falls back to the default.
+func (o *Oracle) SetComparison(cfg config.Comparison) {
+	o.comparisonProviders = cfg.Providers
+	o.comparisonThreshold = defaultComparisonThreshold
+
+	if cfg.Threshold == "" {
+		return
+	}
+	threshold, err := sdk.NewDecFromStr(cfg.Threshold)
+	if err != nil {
+		o.logger.Warn().
+			Str("threshold", cfg.Threshold).
+			Msg("failed to parse comparison threshold, using default")
+		return
+	}
+	o.comparisonThreshold = threshold
+}
+
+// initComparisonSources creates the configured comparison sources, so they
+// are already polling when the first prices are compared.
+func (o *Oracle) initComparisonSources(ctx context.Context) {
+	for _, providerName := range o.comparisonProviders {
+		endpoint := o.endpoints[providerName]
+		endpoint.ContractAddresses = o.contractAddresses[providerName.String()]
+		endpoint.Decimals = o.decimals[providerName.String()]
+		endpoint.Periods = o.periods[providerName.String()]
+
+		source, err := NewProvider(
+			o.volumeDatabase,
+			ctx,
+			providerName,
+			o.logger,
+			endpoint,
+			o.comparisonPairs...,
+		)
+		if err != nil {
+			o.logger.Err(err).
+				Str("provider", providerName.String()).
+				Msg("failed to create comparison source")
+			continue
+		}
+
+		o.comparisonSources[providerName] = source
+	}
+}
+
+// comparePrices compares the computed prices against the USD prices of the
+// configured comparison sources, e.g. other oracle networks. Comparison
+// sources are never used for voting, divergences are only reported via logs
+// and telemetry.
+func (o *Oracle) comparePrices(prices map[string]sdk.Dec) {
+	for _, providerName := range o.comparisonProviders {
+		source, found := o.comparisonSources[providerName]
+		if !found {
+			continue
+		}
+
+		tickers, err := source.GetTickerPrices(o.comparisonPairs...)
+		if err != nil {
+			o.logger.Warn().
+				Err(err).
+				Str("provider", providerName.String()).
+				Msg("failed to get comparison prices")
+			continue
+		}
+
+		for denom, price := range prices {
+			pair := types.CurrencyPair{Base: denom, Quote: "USD"}
+			ticker, found := tickers[pair.String()]
+			if !found || price.IsZero() {
+				continue
+			}
+
+			deviation := ticker.Price.Sub(price).Abs().Quo(price)
+
+			if labels.Allowed(providerName.String(), denom) {
+				telemetry.SetGaugeWithLabels(
+					[]string{"comparison", "deviation"},
+					float32(deviation.MustFloat64()),
+					[]metrics.Label{
+						labels.Provider(providerName.String()),
+						labels.Pair("denom", denom),
+					},
+				)
+			}
+
+			if deviation.GT(o.comparisonThreshold) {
+				telemetry.IncrCounterWithLabels(
+					[]string{"comparison", "divergence"},
+					1,
+					[]metrics.Label{
+						labels.Provider(providerName.String()),
+						labels.Pair("denom", denom),
+					},
+				)
+				o.logger.Warn().
+					Str("provider", providerName.String()).
+					Str("denom", denom).
+					Str("price", price.String()).
+					Str("comparison", ticker.Price.String()).
+					Str("deviation", deviation.String()).
+					Msg("price diverges from comparison source")
+			}
+		}
+	}
+}
+
+// comparisonPairsFromProviderPairs returns the USD pairs of all denoms
+// that are priced by the oracle.
+func comparisonPairsFromProviderPairs(
+	providerPairs map[provider.Name][]types.CurrencyPair,
+) []types.CurrencyPair {
+	denoms := map[string]struct{}{}
+	pairs := []types.CurrencyPair{}
+	for _, currencyPairs := range providerPairs {
+		for _, pair := range currencyPairs {
+			_, found := denoms[pair.Base]
+			if found {
+				continue
+			}
+			denoms[pair.Base] = struct{}{}
+			pairs = append(pairs, types.CurrencyPair{Base: pair.Base, Quote: "USD"})
+		}
+	}
+	return pairs
+}
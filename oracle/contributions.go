@@ -0,0 +1,73 @@
+package oracle
+
+import (
+	"price-feeder/oracle/history"
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+)
+
+// Reasons of skipped rates, that aren't counted as missing.
+const (
+	skipReasonBlacklisted = "blacklisted"
+	skipReasonStale       = "stale ticker price"
+)
+
+// contributionRanks orders the outcomes of a provider, if it has several
+// pairs of the same denom. The best outcome is counted.
+var contributionRanks = map[string]int{
+	history.ContributionMissing:  0,
+	history.ContributionStale:    1,
+	history.ContributionFiltered: 2,
+	history.ContributionAccepted: 3,
+}
+
+// providerContributions returns the outcome of the configured rates of each
+// provider by denom. Skipped contains the rates by denom, that were already
+// removed before the pipeline ran.
+func providerContributions(
+	providerPairs map[provider.Name][]types.CurrencyPair,
+	state *PipelineState,
+	skipped map[string][]types.ExcludedRate,
+) map[string]map[string]string {
+	outcomes := map[string]map[string]string{}
+	set := func(providerName, denom, outcome string) {
+		if _, found := outcomes[providerName]; !found {
+			outcomes[providerName] = map[string]string{}
+		}
+		current, found := outcomes[providerName][denom]
+		if !found || contributionRanks[outcome] > contributionRanks[current] {
+			outcomes[providerName][denom] = outcome
+		}
+	}
+
+	for providerName, pairs := range providerPairs {
+		for _, pair := range pairs {
+			set(providerName.String(), pair.Base, history.ContributionMissing)
+		}
+	}
+
+	for denom, rates := range skipped {
+		for _, rate := range rates {
+			switch rate.Reason {
+			case skipReasonBlacklisted:
+				set(rate.Provider, denom, history.ContributionFiltered)
+			case skipReasonStale:
+				set(rate.Provider, denom, history.ContributionStale)
+			}
+		}
+	}
+
+	for denom, rates := range state.Excluded {
+		for _, rate := range rates {
+			set(rate.Provider, denom, history.ContributionFiltered)
+		}
+	}
+
+	for denom, contributors := range state.Contributors {
+		for providerName := range contributors {
+			set(providerName.String(), denom, history.ContributionAccepted)
+		}
+	}
+
+	return outcomes
+}
@@ -0,0 +1,62 @@
+package oracle
+
+import (
+	"testing"
+
+	"price-feeder/oracle/history"
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderContributions(t *testing.T) {
+	usd := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	usdt := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+
+	ticker := func(price int64) types.TickerPrice {
+		return types.TickerPrice{Price: sdk.NewDec(price), Volume: sdk.OneDec()}
+	}
+	providerPrices := provider.AggregatedProviderPrices{
+		provider.ProviderBinance: {"ATOMUSD": ticker(10)},
+		provider.ProviderKraken:  {"ATOMUSD": ticker(10)},
+		provider.ProviderKucoin:  {"ATOMUSD": ticker(10)},
+		provider.ProviderOkx:     {"ATOMUSD": ticker(20)},
+	}
+	providerPairs := map[provider.Name][]types.CurrencyPair{
+		// the usdt pair can't be converted, but the usd pair is accepted
+		provider.ProviderBinance: {usd, usdt},
+		provider.ProviderKraken:  {usd},
+		provider.ProviderKucoin:  {usd},
+		provider.ProviderOkx:     {usd},
+		provider.ProviderGate:    {usd},
+		provider.ProviderHuobi:   {usd},
+		provider.ProviderMexc:    {usd},
+	}
+
+	state, err := runPipeline(
+		NewPipeline(), zerolog.Nop(), providerPrices, providerPairs,
+		nil, nil, nil,
+	)
+	require.NoError(t, err)
+
+	skipped := map[string][]types.ExcludedRate{
+		"ATOM": {
+			{Provider: "gate", Symbol: "ATOMUSD", Reason: skipReasonStale},
+			{Provider: "huobi", Symbol: "ATOMUSD", Reason: "provider timed out"},
+			{Provider: "mexc", Symbol: "ATOMUSD", Reason: skipReasonBlacklisted},
+		},
+	}
+
+	require.Equal(t, map[string]map[string]string{
+		"binance": {"ATOM": history.ContributionAccepted},
+		"kraken":  {"ATOM": history.ContributionAccepted},
+		"kucoin":  {"ATOM": history.ContributionAccepted},
+		"okx":     {"ATOM": history.ContributionFiltered},
+		"gate":    {"ATOM": history.ContributionStale},
+		"huobi":   {"ATOM": history.ContributionMissing},
+		"mexc":    {"ATOM": history.ContributionFiltered},
+	}, providerContributions(providerPairs, state, skipped))
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// more than 6 conversions for the USD price is probably not very accurate
+const maxConversions = 6
+
 // convertTickersToUSD converts any tickers which are not quoted in USD to USD,
 // using the conversion rates of other tickers. It will also filter out any tickers
 // not within the deviation threshold set by the config.
@@ -23,65 +26,98 @@ func convertTickersToUSD(
 	providerMinOverrides map[string]int,
 	providerWeights map[string]ProviderWeight,
 ) (map[string]sdk.Dec, error) {
-	if len(providerPrices) == 0 {
-		return nil, nil
-	}
+	rates, _, err := convertTickersToUSDWithConversions(
+		logger,
+		providerPrices,
+		providerPairs,
+		deviationThresholds,
+		providerMinOverrides,
+		providerWeights,
+	)
+	return rates, err
+}
 
-	// group ticker prices by symbol
+// convertTickersToUSDWithConversions works like convertTickersToUSD, but
+// additionally returns the intermediate USD rates per provider and denom.
+func convertTickersToUSDWithConversions(
+	logger zerolog.Logger,
+	providerPrices provider.AggregatedProviderPrices,
+	providerPairs map[provider.Name][]types.CurrencyPair,
+	deviationThresholds map[string]sdk.Dec,
+	providerMinOverrides map[string]int,
+	providerWeights map[string]ProviderWeight,
+) (map[string]sdk.Dec, []types.ConversionRate, error) {
+	state, err := runPipeline(
+		NewPipeline(),
+		logger,
+		providerPrices,
+		providerPairs,
+		deviationThresholds,
+		providerMinOverrides,
+		providerWeights,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	providerPricesBySymbol := map[string]map[provider.Name]types.TickerPrice{}
-	for providerName, tickerPrices := range providerPrices {
-		for symbol, tickerPrice := range tickerPrices {
-			_, found := providerPricesBySymbol[symbol]
-			if !found {
-				providerPricesBySymbol[symbol] = map[provider.Name]types.TickerPrice{}
-			}
+	return state.Prices, state.Conversions, nil
+}
 
-			providerPricesBySymbol[symbol][providerName] = tickerPrice
-		}
+// runPipeline computes the USD prices with the provided pipeline and returns
+// the final pipeline state.
+func runPipeline(
+	pipeline *Pipeline,
+	logger zerolog.Logger,
+	providerPrices provider.AggregatedProviderPrices,
+	providerPairs map[provider.Name][]types.CurrencyPair,
+	deviationThresholds map[string]sdk.Dec,
+	providerMinOverrides map[string]int,
+	providerWeights map[string]ProviderWeight,
+) (*PipelineState, error) {
+	if len(providerPrices) == 0 {
+		return &PipelineState{}, nil
 	}
 
-	symbols := map[string]struct{}{}
-	pairs := []types.CurrencyPair{}
-	for _, currencyPairs := range providerPairs {
-		for _, currencyPair := range currencyPairs {
-			symbol := currencyPair.String()
-			_, found := symbols[symbol]
-			if !found {
-				symbols[symbol] = struct{}{}
-				pairs = append(pairs, currencyPair)
-			}
-		}
+	state := &PipelineState{
+		Logger:               logger,
+		ProviderPrices:       providerPrices,
+		ProviderPairs:        providerPairs,
+		DeviationThresholds:  deviationThresholds,
+		ProviderMinOverrides: providerMinOverrides,
+		ProviderWeights:      providerWeights,
 	}
 
-	// override volume data
-	for _, pair := range pairs {
-		base := pair.Base
-		weight, found := providerWeights[base]
-		if !found {
-			continue
-		}
-
-		symbol := pair.String()
-		tickers := providerPricesBySymbol[symbol]
-
-		tickers, err := SetWeight(tickers, weight)
-		if err != nil {
-			return nil, err
-		}
-
-		providerPricesBySymbol[symbol] = tickers
+	if err := pipeline.Run(state); err != nil {
+		return nil, err
 	}
 
-	// calculate USD values
+	return state, nil
+}
 
-	// more than 6 conversions for the USD price is probably not very accurate
-	maxConversions := 6
+// resolveUSDRates calculates the USD rates of all base denoms per provider.
+// The pairs are converted in rounds: pairs quoted in a denom without enough
+// USD rates yet are retried in the next round, after the other pairs added
+// their rates. The USD rate of a quote is computed once and reused, until
+// new rates of the quote are added.
+func resolveUSDRates(
+	logger zerolog.Logger,
+	providerPricesBySymbol map[string]map[provider.Name]types.TickerPrice,
+	pairs []types.CurrencyPair,
+	deviationThresholds map[string]sdk.Dec,
+	providerMinOverrides map[string]int,
+) (
+	map[string]map[provider.Name]types.TickerPrice,
+	[]types.ConversionRate,
+	error,
+) {
 	usdRates := map[string]map[provider.Name]types.TickerPrice{}
+	quoteRates := map[string]cachedQuoteRate{}
+	conversions := []types.ConversionRate{}
 
-	for i := 0; i < maxConversions; i++ {
-		// reorder pairs
+	// the pairs are reordered, but the caller's slice is kept
+	pairs = append([]types.CurrencyPair{}, pairs...)
 
+	for i := 0; i < maxConversions; i++ {
 		sort.Slice(pairs, func(i, j int) bool {
 			return pairs[i].String() < pairs[j].String()
 		})
@@ -90,7 +126,7 @@ func convertTickersToUSD(
 		// to add more prices for already existing USD rates which are used to calculate
 		// the prices for the remaining denoms
 
-		reordered := []types.CurrencyPair{}
+		reordered := make([]types.CurrencyPair, 0, len(pairs))
 		for _, pair := range pairs {
 			_, found := usdRates[pair.Base]
 			if found {
@@ -104,48 +140,39 @@ func convertTickersToUSD(
 
 		unresolved := []types.CurrencyPair{}
 
-		for _, currencyPair := range pairs {
-			symbol := currencyPair.String()
-			base := currencyPair.Base
-			quote := currencyPair.Quote
-
-			maxDeviation := deviationThresholds[quote]
+		for _, pair := range pairs {
+			symbol := pair.String()
 			tickerPrices := providerPricesBySymbol[symbol]
 
-			newRates := map[provider.Name]types.TickerPrice{}
+			newRates := make(map[provider.Name]types.TickerPrice, len(tickerPrices))
 
-			if quote == "USD" {
+			rate := sdk.OneDec()
+			if pair.Quote == "USD" {
 				for providerName, tickerPrice := range tickerPrices {
 					newRates[providerName] = tickerPrice
 				}
 			} else {
-				minProviders, found := providerMinOverrides[quote]
+				cached, found := quoteRates[pair.Quote]
 				if !found {
-					minProviders = 3
-				}
-
-				// a minimum of 3 usd prices are needed
-				rates, found := usdRates[quote]
-				if !found || len(rates) < minProviders {
-					unresolved = append(unresolved, currencyPair)
-					continue
-				}
-
-				filtered, err := FilterTickerDeviations(
-					logger, symbol, rates, maxDeviation, false,
-				)
-				if err != nil {
-					if len(rates) >= 3 {
-						unresolved = append(unresolved, currencyPair)
-						continue
+					var err error
+					cached.rate, cached.found, err = quoteRate(
+						logger,
+						pair.Quote,
+						usdRates[pair.Quote],
+						deviationThresholds[pair.Quote],
+						providerMinOverrides,
+					)
+					if err != nil {
+						return nil, nil, err
 					}
+					quoteRates[pair.Quote] = cached
 				}
-
-				rate, err := vwapRate(filtered)
-				if err != nil {
-					return nil, err
+				if !cached.found {
+					unresolved = append(unresolved, pair)
+					continue
 				}
 
+				rate = cached.rate
 				for providerName, tickerPrice := range tickerPrices {
 					newRates[providerName] = types.TickerPrice{
 						Price:  tickerPrice.Price.Mul(rate),
@@ -155,18 +182,36 @@ func convertTickersToUSD(
 				}
 			}
 
-			if len(newRates) > 0 {
-				newRates, err := addRates(
-					logger,
-					symbol,
-					usdRates[base],
-					newRates,
-				)
-				if err != nil {
-					return nil, err
+			if len(newRates) == 0 {
+				continue
+			}
+
+			// rates of providers, that are already set, are not replaced
+			for providerName, newRate := range newRates {
+				_, found := usdRates[pair.Base][providerName]
+				if found {
+					continue
 				}
-				usdRates[base] = newRates
+
+				conversions = append(conversions, types.ConversionRate{
+					Denom:     pair.Base,
+					Provider:  providerName.String(),
+					Symbol:    symbol,
+					Price:     tickerPrices[providerName].Price,
+					QuoteRate: rate,
+					Rate:      newRate.Price,
+					Volume:    newRate.Volume,
+				})
 			}
+
+			baseRates, err := addRates(logger, symbol, usdRates[pair.Base], newRates)
+			if err != nil {
+				return nil, nil, err
+			}
+			usdRates[pair.Base] = baseRates
+
+			// the quote rate of the base has to include the new rates
+			delete(quoteRates, pair.Base)
 		}
 
 		// Stop if there are no unresolved symbols left or no symbol could
@@ -177,65 +222,50 @@ func convertTickersToUSD(
 		}
 
 		// Try the next round with all unresolved symbols
-		pairs = []types.CurrencyPair{}
-		pairs = append(pairs, unresolved...)
+		pairs = unresolved
 	}
 
-	ratesDec := map[string]sdk.Dec{}
-	for denom, tickers := range usdRates {
-		for name, ticker := range tickers {
-			provider.TelemetryProviderPrice(
-				provider.Name("_"+name.String()),
-				denom+"USD",
-				float32(ticker.Price.MustFloat64()),
-				float32(ticker.Volume.MustFloat64()),
-			)
-		}
+	return usdRates, conversions, nil
+}
 
-		threshold := deviationThresholds[denom]
-		filtered, err := FilterTickerDeviations(
-			logger, denom, tickers, threshold, true,
-		)
-		if err != nil {
-			minimum, found := providerMinOverrides[denom]
-			if !found {
-				logger.Err(err)
-				continue
-			}
-			if len(filtered) < minimum {
-				logger.Warn().
-					Str("denom", denom).
-					Int("minimum", minimum).
-					Int("available", len(filtered)).
-					Msg("not enough tickers")
-				continue
-			}
-		}
+// cachedQuoteRate is the memoized result of quoteRate.
+type cachedQuoteRate struct {
+	rate  sdk.Dec
+	found bool
+}
 
-		rate, err := vwapRate(filtered)
-		if err != nil {
-			logger.Err(err)
-			continue
-		}
+// quoteRate returns the USD rate of a denom used to convert tickers quoted
+// in that denom. The rate is only returned, if enough providers are available.
+func quoteRate(
+	logger zerolog.Logger,
+	denom string,
+	rates map[provider.Name]types.TickerPrice,
+	maxDeviation sdk.Dec,
+	providerMinOverrides map[string]int,
+) (sdk.Dec, bool, error) {
+	minProviders, found := providerMinOverrides[denom]
+	if !found {
+		minProviders = 3
+	}
 
-		if rate.IsZero() {
-			logger.Error().
-				Str("denom", denom).
-				Msg("rate is zero")
-			continue
-		}
+	// a minimum of 3 usd prices are needed
+	if len(rates) < minProviders {
+		return sdk.Dec{}, false, nil
+	}
 
-		ratesDec[denom] = rate
+	filtered, err := FilterTickerDeviations(
+		logger, denom, rates, maxDeviation, false,
+	)
+	if err != nil && len(rates) >= 3 {
+		return sdk.Dec{}, false, nil
+	}
 
-		provider.TelemetryProviderPrice(
-			"_final",
-			denom+"USD",
-			float32(rate.MustFloat64()),
-			float32(1),
-		)
+	rate, err := vwapRate(filtered)
+	if err != nil {
+		return sdk.Dec{}, false, err
 	}
 
-	return ratesDec, nil
+	return rate, true, nil
 }
 
 func addRates(
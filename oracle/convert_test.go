@@ -1,6 +1,7 @@
 package oracle
 
 import (
+	"fmt"
 	"testing"
 
 	"price-feeder/oracle/provider"
@@ -293,3 +294,139 @@ func TestConvertTickersToUsdEmptyPrices(t *testing.T) {
 
 	require.Equal(t, 0, len(rates))
 }
+
+func TestConvertTickersToUSDWithConversions(t *testing.T) {
+	providerPrices := provider.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			"ATOMUSDT": {
+				Price:  sdk.MustNewDecFromStr("10"),
+				Volume: sdk.MustNewDecFromStr("1"),
+			},
+		},
+		provider.ProviderKraken: {
+			"USDTUSD": {
+				Price:  sdk.MustNewDecFromStr("0.999"),
+				Volume: sdk.MustNewDecFromStr("1"),
+			},
+		},
+	}
+
+	providerPairs := map[provider.Name][]types.CurrencyPair{
+		provider.ProviderBinance: {{Base: "ATOM", Quote: "USDT"}},
+		provider.ProviderKraken:  {{Base: "USDT", Quote: "USD"}},
+	}
+
+	_, conversions, err := convertTickersToUSDWithConversions(
+		zerolog.Nop(),
+		providerPrices,
+		providerPairs,
+		make(map[string]sdk.Dec),
+		map[string]int{"ATOM": 1, "USDT": 1},
+		nil,
+	)
+	require.NoError(t, err)
+	require.Len(t, conversions, 2)
+
+	// USDT is resolved before ATOM
+	require.Equal(t, "USDT", conversions[0].Denom)
+	require.Equal(t, sdk.OneDec(), conversions[0].QuoteRate)
+
+	require.Equal(t, "ATOM", conversions[1].Denom)
+	require.Equal(t, provider.ProviderBinance.String(), conversions[1].Provider)
+	require.Equal(t, "ATOMUSDT", conversions[1].Symbol)
+	require.Equal(t, sdk.MustNewDecFromStr("10"), conversions[1].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("0.999"), conversions[1].QuoteRate)
+	require.Equal(t, sdk.MustNewDecFromStr("9.99"), conversions[1].Rate)
+}
+
+// newBenchmarkTickers returns tickers for n denoms quoted in USDT on three
+// providers, plus USDT/USD and a chain of denoms quoted in each other.
+func newBenchmarkTickers(n int) (
+	provider.AggregatedProviderPrices,
+	map[provider.Name][]types.CurrencyPair,
+) {
+	providerPrices := provider.AggregatedProviderPrices{}
+	providerPairs := map[provider.Name][]types.CurrencyPair{}
+
+	providers := []provider.Name{
+		provider.ProviderBinance,
+		provider.ProviderKraken,
+		provider.ProviderKucoin,
+	}
+
+	ticker := types.TickerPrice{
+		Price:  sdk.MustNewDecFromStr("1.01"),
+		Volume: sdk.MustNewDecFromStr("1000"),
+	}
+
+	for i, providerName := range providers {
+		providerPrices[providerName] = map[string]types.TickerPrice{}
+
+		usdt := types.CurrencyPair{Base: "USDT", Quote: "USD"}
+		providerPrices[providerName][usdt.String()] = types.TickerPrice{
+			Price:  sdk.MustNewDecFromStr(fmt.Sprintf("0.99%d", i)),
+			Volume: ticker.Volume,
+		}
+		providerPairs[providerName] = append(providerPairs[providerName], usdt)
+
+		for j := 0; j < n; j++ {
+			quote := "USDT"
+			if j%4 != 0 {
+				quote = fmt.Sprintf("DENOM%d", j-1)
+			}
+			pair := types.CurrencyPair{
+				Base:  fmt.Sprintf("DENOM%d", j),
+				Quote: quote,
+			}
+			providerPrices[providerName][pair.String()] = ticker
+			providerPairs[providerName] = append(providerPairs[providerName], pair)
+		}
+	}
+
+	return providerPrices, providerPairs
+}
+
+func BenchmarkConvertTickersToUSD(b *testing.B) {
+	providerPrices, providerPairs := newBenchmarkTickers(100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := convertTickersToUSD(
+			zerolog.Nop(),
+			providerPrices,
+			providerPairs,
+			map[string]sdk.Dec{},
+			map[string]int{},
+			nil,
+		)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestConvertTickersToUSDAllocations(t *testing.T) {
+	denoms := 100
+	providerPrices, providerPairs := newBenchmarkTickers(denoms)
+
+	var rates map[string]sdk.Dec
+	allocs := testing.AllocsPerRun(5, func() {
+		rates, _ = convertTickersToUSD(
+			zerolog.Nop(),
+			providerPrices,
+			providerPairs,
+			map[string]sdk.Dec{},
+			map[string]int{},
+			nil,
+		)
+	})
+
+	require.Len(t, rates, denoms+1)
+
+	// generous budget per converted ticker, guarding against regressions
+	// like repeatedly recomputing quote rates
+	budget := float64((denoms + 1) * 3 * 300)
+	require.LessOrEqual(t, allocs, budget)
+}
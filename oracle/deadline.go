@@ -0,0 +1,102 @@
+package oracle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"price-feeder/oracle/labels"
+	"price-feeder/oracle/provider"
+)
+
+const (
+	// latencyWeight defines the weight of the latest response time in the
+	// moving average of the provider latency.
+	latencyWeight = 0.3
+
+	// maxDeadlineSkips defines how often in a row a slow provider is skipped
+	// before it is queried again, so its latency estimate stays current.
+	maxDeadlineSkips = 3
+)
+
+// providerLatencies tracks the response times of the providers, so slow
+// providers can be skipped when the vote deadline is close.
+type providerLatencies struct {
+	mtx     sync.Mutex
+	latency map[provider.Name]time.Duration
+	skips   map[provider.Name]int
+}
+
+// observe updates the average response time of the provider.
+func (l *providerLatencies) observe(name provider.Name, latency time.Duration) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.latency == nil {
+		l.latency = map[provider.Name]time.Duration{}
+	}
+
+	average, found := l.latency[name]
+	if found {
+		latency = time.Duration(
+			latencyWeight*float64(latency) + (1-latencyWeight)*float64(average),
+		)
+	}
+	l.latency[name] = latency
+
+	if labels.AllowedProvider(name.String()) {
+		telemetry.SetGaugeWithLabels(
+			[]string{"provider", "latency_ms"},
+			float32(latency.Milliseconds()),
+			[]metrics.Label{telemetry.NewLabel("provider", name.String())},
+		)
+	}
+}
+
+// skip returns true, if the provider usually doesn't respond within the
+// remaining time and wasn't skipped too often already.
+func (l *providerLatencies) skip(name provider.Name, remaining time.Duration) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.skips == nil {
+		l.skips = map[provider.Name]int{}
+	}
+
+	latency, found := l.latency[name]
+	if !found || latency <= remaining || l.skips[name] >= maxDeadlineSkips {
+		l.skips[name] = 0
+		return false
+	}
+
+	l.skips[name]++
+	return true
+}
+
+// voteDeadline returns the time until which prices can be collected for
+// the vote of the current period, leaving one block to broadcast it. It
+// returns false, if deadline collection is disabled or the block time is
+// not known yet.
+func (o *Oracle) voteDeadline(
+	indexInVotePeriod, votePeriod int64,
+	now time.Time,
+) (time.Time, bool) {
+	blockTime := o.blockTimer.blockTime
+	if !o.timing.deadline || blockTime == 0 {
+		return time.Time{}, false
+	}
+
+	remaining := votePeriod - indexInVotePeriod
+	if indexInVotePeriod == 0 {
+		remaining = votePeriod
+	}
+
+	available := time.Duration(remaining-1) * blockTime
+	if available < blockTime/2 {
+		available = blockTime / 2
+	}
+
+	return now.Add(available), true
+}
@@ -0,0 +1,58 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+	"price-feeder/oracle/provider"
+)
+
+func TestProviderLatencies(t *testing.T) {
+	var l providerLatencies
+
+	// unknown providers are never skipped
+	require.False(t, l.skip(provider.ProviderBinance, time.Second))
+
+	l.observe(provider.ProviderBinance, 100*time.Millisecond)
+	l.observe(provider.ProviderKraken, 2*time.Second)
+	require.False(t, l.skip(provider.ProviderBinance, time.Second))
+
+	// moving average
+	l.observe(provider.ProviderKraken, time.Second)
+	require.Equal(t, 1700*time.Millisecond, l.latency[provider.ProviderKraken])
+
+	// slow providers are queried again after maxDeadlineSkips
+	for i := 0; i < maxDeadlineSkips; i++ {
+		require.True(t, l.skip(provider.ProviderKraken, time.Second))
+	}
+	require.False(t, l.skip(provider.ProviderKraken, time.Second))
+	require.True(t, l.skip(provider.ProviderKraken, time.Second))
+}
+
+func TestVoteDeadline(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	o := Oracle{timing: newTiming(zerolog.Nop(), config.Timing{})}
+	o.blockTimer.blockTime = time.Second
+
+	// disabled
+	_, ok := o.voteDeadline(12, 14, now)
+	require.False(t, ok)
+
+	o.timing = newTiming(zerolog.Nop(), config.Timing{DeadlineCollection: true})
+	deadline, ok := o.voteDeadline(12, 14, now)
+	require.True(t, ok)
+	require.Equal(t, now.Add(time.Second), deadline)
+
+	// last block of the period
+	deadline, _ = o.voteDeadline(13, 14, now)
+	require.Equal(t, now.Add(500*time.Millisecond), deadline)
+
+	// block time unknown
+	o.blockTimer.blockTime = 0
+	_, ok = o.voteDeadline(12, 14, now)
+	require.False(t, ok)
+}
@@ -0,0 +1,164 @@
+package oracle
+
+import (
+	"context"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+	"price-feeder/pkg/events"
+)
+
+// listingCheckInterval defines how often the available pairs of the
+// providers are checked for delisted pairs.
+const listingCheckInterval = 1 * time.Hour
+
+// unlistedRetryInterval defines how often providers with configured pairs
+// missing at startup are checked for new listings.
+const unlistedRetryInterval = 1 * time.Minute
+
+// checkListings periodically compares the configured pairs with the pairs
+// available on each provider and delists pairs, which were available on an
+// earlier check but disappeared since. Pairs never seen as available are
+// left alone, as their naming may just differ from the listing.
+func (o *Oracle) checkListings(ctx context.Context) {
+	listed := map[provider.Name]map[string]struct{}{}
+
+	ticker := time.NewTicker(listingCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		for providerName, pairs := range o.getProviderPairs() {
+			// providers are created by the first tick
+			o.mtx.RLock()
+			priceProvider, found := o.priceProviders[providerName]
+			o.mtx.RUnlock()
+			if !found {
+				continue
+			}
+
+			available, err := priceProvider.GetAvailablePairs()
+			if err != nil || len(available) == 0 {
+				o.logger.Debug().
+					Err(err).
+					Str("provider", providerName.String()).
+					Msg("failed to get available pairs")
+				continue
+			}
+
+			if _, found := listed[providerName]; !found {
+				listed[providerName] = map[string]struct{}{}
+			}
+
+			for _, pair := range pairs {
+				symbol := pair.String()
+				_, isListed := available[priceProvider.CurrencyPairToProviderPair(pair)]
+				if !isListed {
+					_, isListed = available[priceProvider.CurrencyPairToProviderPair(pair.Swap())]
+				}
+
+				if isListed {
+					listed[providerName][symbol] = struct{}{}
+					continue
+				}
+
+				if _, found := listed[providerName][symbol]; !found {
+					continue
+				}
+
+				delete(listed[providerName], symbol)
+				if entry, ok := o.blacklist.Delist(providerName, symbol); ok {
+					o.reportDelisted(entry, "pair not available anymore")
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// retryUnlistedPairs periodically resolves the configured pairs, which
+// weren't available on their provider at startup, e.g. scheduled listings.
+// They are picked up and subscribed as soon as the exchange lists them,
+// without a restart.
+func (o *Oracle) retryUnlistedPairs(ctx context.Context) {
+	ticker := time.NewTicker(unlistedRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		o.mtx.RLock()
+		priceProviders := make([]provider.Provider, 0, len(o.priceProviders))
+		for _, priceProvider := range o.priceProviders {
+			priceProviders = append(priceProviders, priceProvider)
+		}
+		o.mtx.RUnlock()
+
+		for _, priceProvider := range priceProviders {
+			listingProvider, ok := priceProvider.(provider.ListingProvider)
+			if !ok || len(listingProvider.UnlistedPairs()) == 0 {
+				continue
+			}
+
+			available, err := priceProvider.GetAvailablePairs()
+			if err != nil || len(available) == 0 {
+				continue
+			}
+
+			listed, err := listingProvider.ResolveUnlistedPairs(available)
+			if err != nil {
+				o.logger.Warn().
+					Err(err).
+					Str("provider", priceProvider.GetName().String()).
+					Msg("failed to subscribe listed pairs")
+			}
+			if len(listed) > 0 {
+				telemetry.IncrCounter(float32(len(listed)), "provider", "listed")
+			}
+		}
+	}
+}
+
+// observeTicker tracks the missing ticker of a pair and reports the pair,
+// if it got delisted. It returns true if the pair is delisted.
+func (o *Oracle) observeTicker(
+	providerName provider.Name,
+	pair types.CurrencyPair,
+	found bool,
+) bool {
+	entry, delisted := o.blacklist.ObserveTicker(
+		providerName, pair.String(), found, time.Now(),
+	)
+	if delisted {
+		o.reportDelisted(entry, "no ticker price found for too long")
+	}
+	return delisted
+}
+
+// reportDelisted logs a delisted pair once, instead of logging the missing
+// price every tick.
+func (o *Oracle) reportDelisted(entry types.BlacklistEntry, reason string) {
+	o.logger.Warn().
+		Str("provider", entry.Provider).
+		Str("pair", entry.Symbol).
+		Str("reason", reason).
+		Msg("dropped delisted pair until restart")
+
+	telemetry.IncrCounter(1, "provider", "delisted")
+	o.publish(
+		events.TopicProviderQuarantined,
+		"dropped delisted pair "+entry.Symbol+" of "+entry.Provider,
+		entry,
+	)
+}
@@ -62,6 +62,17 @@ func (d *TwapDerivative) GetPrices(symbol string) (map[string]types.TickerPrice,
 		return nil, err
 	}
 
+	// the persisted volume snapshots give a volume consistent over the
+	// period, the volume of the latest ticker is only used as fallback
+	volumes, err := d.history.AverageProviderVolumes(symbol, start, now)
+	if err != nil {
+		d.logger.Warn().
+			Err(err).
+			Str("symbol", symbol).
+			Msg("failed to get historical volumes")
+		volumes = map[string]sdk.Dec{}
+	}
+
 	derivativePrices := map[string]types.TickerPrice{}
 	for providerName, tickerPrices := range tickers {
 
@@ -77,11 +88,14 @@ func (d *TwapDerivative) GetPrices(symbol string) (map[string]types.TickerPrice,
 			continue
 		}
 
-		latestTicker := tickerPrices[len(tickerPrices)-1]
+		volume, found := volumes[providerName]
+		if !found {
+			volume = tickerPrices[len(tickerPrices)-1].Volume
+		}
 
 		derivativePrices[providerName] = types.TickerPrice{
 			Price:  pairPrice,
-			Volume: latestTicker.Volume,
+			Volume: volume,
 			Time:   now,
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"price-feeder/oracle/provider"
 	"price-feeder/oracle/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -108,3 +109,26 @@ func TestTvwapDerivative_tvwap(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, testTvwapPrice6, result6)
 }
+
+// TestTwapPricePathVectors validates the twap against the expected outputs of
+// the deterministic price paths used by the zero provider.
+func TestTwapPricePathVectors(t *testing.T) {
+	path, err := provider.LoadPricePath("../provider/testdata/pricepath.csv")
+	require.NoError(t, err)
+
+	start := time.Unix(0, 0)
+	end := start.Add(10 * time.Minute)
+
+	for _, tc := range []struct {
+		symbol   string
+		expected sdk.Dec
+	}{
+		{"ATOMUSD", sdk.MustNewDecFromStr("10.8")},
+		// the spike at 300s is ignored
+		{"KUJIUSD", sdk.OneDec()},
+	} {
+		price, _, err := Twap(path.Tickers(tc.symbol, start), start, end)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, price, tc.symbol)
+	}
+}
@@ -0,0 +1,101 @@
+package oracle
+
+import (
+	"sort"
+
+	"github.com/rs/zerolog"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+)
+
+// priceDiagnosis defines why no price could be computed for a denom. All
+// rates are formatted as provider:symbol.
+type priceDiagnosis struct {
+	Denom string
+	// Configured contains the configured pairs of the denom.
+	Configured []string
+	// Returned contains the pairs, the providers returned a ticker for.
+	Returned []string
+	// Excluded contains the pairs, that were skipped or filtered, with the
+	// reason, e.g. a provider timeout or a deviating price.
+	Excluded []string
+	// Unconverted contains the pairs, whose quote had no USD rate.
+	Unconverted []string
+}
+
+// diagnoseMissingPrices returns the diagnosis of each missing denom.
+// Skipped contains the rates by denom, that were already removed before the
+// pipeline ran.
+func diagnoseMissingPrices(
+	denoms []string,
+	providerPairs map[provider.Name][]types.CurrencyPair,
+	state *PipelineState,
+	skipped map[string][]types.ExcludedRate,
+) []priceDiagnosis {
+	diagnoses := make([]priceDiagnosis, 0, len(denoms))
+
+	for _, denom := range denoms {
+		diagnosis := priceDiagnosis{
+			Denom:       denom,
+			Configured:  []string{},
+			Returned:    []string{},
+			Excluded:    []string{},
+			Unconverted: []string{},
+		}
+
+		for providerName, pairs := range providerPairs {
+			for _, pair := range pairs {
+				if pair.Base != denom {
+					continue
+				}
+
+				rate := providerName.String() + ":" + pair.String()
+				diagnosis.Configured = append(diagnosis.Configured, rate)
+
+				if _, ok := state.ProviderPrices[providerName][pair.String()]; ok {
+					diagnosis.Returned = append(diagnosis.Returned, rate)
+				}
+
+				if pair.Quote == "USD" {
+					continue
+				}
+				if _, ok := state.Tickers[pair.String()][providerName]; !ok {
+					continue
+				}
+				if _, ok := state.USDRates[denom][providerName]; !ok {
+					diagnosis.Unconverted = append(diagnosis.Unconverted, rate)
+				}
+			}
+		}
+
+		excluded := append([]types.ExcludedRate{}, skipped[denom]...)
+		excluded = append(excluded, state.Excluded[denom]...)
+		for _, rate := range excluded {
+			diagnosis.Excluded = append(
+				diagnosis.Excluded,
+				rate.Provider+":"+rate.Symbol+" ("+rate.Reason+")",
+			)
+		}
+
+		sort.Strings(diagnosis.Configured)
+		sort.Strings(diagnosis.Returned)
+		sort.Strings(diagnosis.Excluded)
+		sort.Strings(diagnosis.Unconverted)
+
+		diagnoses = append(diagnoses, diagnosis)
+	}
+
+	return diagnoses
+}
+
+// log logs the diagnosis as a single line.
+func (d priceDiagnosis) log(logger zerolog.Logger) {
+	logger.Error().
+		Str("denom", d.Denom).
+		Strs("configured", d.Configured).
+		Strs("returned", d.Returned).
+		Strs("excluded", d.Excluded).
+		Strs("unconverted", d.Unconverted).
+		Msg("missing price")
+}
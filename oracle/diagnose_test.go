@@ -0,0 +1,58 @@
+package oracle
+
+import (
+	"testing"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseMissingPrices(t *testing.T) {
+	usdt := types.CurrencyPair{Base: "KUJI", Quote: "USDT"}
+	usd := types.CurrencyPair{Base: "KUJI", Quote: "USD"}
+
+	providerPrices := provider.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			"KUJIUSDT": {Price: sdk.MustNewDecFromStr("1.0"), Volume: sdk.OneDec()},
+		},
+		provider.ProviderKucoin: {
+			"KUJIUSD": {Price: sdk.ZeroDec(), Volume: sdk.OneDec()},
+		},
+	}
+	providerPairs := map[provider.Name][]types.CurrencyPair{
+		provider.ProviderBinance: {usdt},
+		provider.ProviderKraken:  {usd},
+		provider.ProviderKucoin:  {usd},
+	}
+
+	state, err := runPipeline(
+		NewPipeline(), zerolog.Nop(), providerPrices, providerPairs,
+		nil, map[string]int{"KUJI": 1}, nil,
+	)
+	require.NoError(t, err)
+	require.NotContains(t, state.Prices, "KUJI")
+
+	skipped := map[string][]types.ExcludedRate{
+		"KUJI": {{Provider: "kraken", Symbol: "KUJIUSD", Reason: "provider timed out"}},
+	}
+
+	diagnoses := diagnoseMissingPrices(
+		[]string{"KUJI"}, providerPairs, state, skipped,
+	)
+	require.Equal(t, []priceDiagnosis{{
+		Denom: "KUJI",
+		Configured: []string{
+			"binance:KUJIUSDT", "kraken:KUJIUSD", "kucoin:KUJIUSD",
+		},
+		Returned: []string{"binance:KUJIUSDT", "kucoin:KUJIUSD"},
+		Excluded: []string{
+			"kraken:KUJIUSD (provider timed out)",
+			"kucoin:KUJIUSD (invalid price)",
+		},
+		Unconverted: []string{"binance:KUJIUSDT"},
+	}}, diagnoses)
+}
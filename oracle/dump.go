@@ -0,0 +1,84 @@
+package oracle
+
+import (
+	"runtime"
+	"sort"
+	"time"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+)
+
+// DebugDump defines a snapshot of the oracle state, used to diagnose stuck
+// loops at runtime without restarting the price feeder.
+type DebugDump struct {
+	Time               time.Time              `json:"time"`
+	Goroutines         int                    `json:"goroutines"`
+	LastPriceSync      time.Time              `json:"last_price_sync"`
+	Prices             map[string]string      `json:"prices"`
+	PreviousVotePeriod float64                `json:"previous_vote_period"`
+	PreviousPrevote    *PrevoteDump           `json:"previous_prevote,omitempty"`
+	AccountSequence    uint64                 `json:"account_sequence"`
+	AccountError       string                 `json:"account_error,omitempty"`
+	Providers          []provider.Dump        `json:"providers"`
+	Blacklist          []types.BlacklistEntry `json:"blacklist"`
+}
+
+// PrevoteDump defines the prevote to be revealed, without its salt, as the
+// dump is logged or written to a file.
+type PrevoteDump struct {
+	ExchangeRates     string `json:"exchange_rates"`
+	SubmitBlockHeight int64  `json:"submit_block_height"`
+}
+
+// Dump returns a snapshot of the current oracle and provider state. The vote
+// period state is owned by the oracle loop and read on a best effort basis.
+func (o *Oracle) Dump() DebugDump {
+	now := time.Now()
+
+	dump := DebugDump{
+		Time:       now,
+		Goroutines: runtime.NumGoroutine(),
+		Prices:     map[string]string{},
+		Providers:  []provider.Dump{},
+		Blacklist:  o.blacklist.Entries(now),
+	}
+
+	sequence, err := o.oracleClient.GetAccountSequence()
+	if err != nil {
+		dump.AccountError = err.Error()
+	}
+	dump.AccountSequence = sequence
+
+	o.mtx.RLock()
+	dump.PreviousVotePeriod = o.previousVotePeriod
+	if o.previousPrevote != nil {
+		dump.PreviousPrevote = &PrevoteDump{
+			ExchangeRates:     o.previousPrevote.ExchangeRates,
+			SubmitBlockHeight: o.previousPrevote.SubmitBlockHeight,
+		}
+	}
+	dump.LastPriceSync = o.lastPriceSyncTS
+	for denom, price := range o.prices {
+		dump.Prices[denom] = price.String()
+	}
+	priceProviders := make([]provider.Provider, 0, len(o.priceProviders))
+	for _, priceProvider := range o.priceProviders {
+		priceProviders = append(priceProviders, priceProvider)
+	}
+	o.mtx.RUnlock()
+
+	for _, priceProvider := range priceProviders {
+		dumper, ok := priceProvider.(provider.Dumper)
+		if !ok {
+			continue
+		}
+		dump.Providers = append(dump.Providers, dumper.Dump(now))
+	}
+
+	sort.Slice(dump.Providers, func(i, j int) bool {
+		return dump.Providers[i].Name < dump.Providers[j].Name
+	})
+
+	return dump
+}
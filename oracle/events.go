@@ -0,0 +1,59 @@
+package oracle
+
+import (
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"price-feeder/config"
+	"price-feeder/pkg/events"
+)
+
+// healthcheckEvents maps the topics, healthchecks subscribe to, to the
+// healthcheck events they trigger.
+var healthcheckEvents = map[events.Topic]string{
+	events.TopicStart:              config.HealthcheckStart,
+	events.TopicStop:               config.HealthcheckStop,
+	events.TopicVoteBroadcast:      config.HealthcheckSuccess,
+	events.TopicTickFailed:         config.HealthcheckFail,
+	events.TopicQuorumLost:         config.HealthcheckFail,
+	events.TopicVoteMissed:         config.HealthcheckFail,
+	events.TopicHeightStalled:      config.HealthcheckFail,
+	events.TopicVoteMismatch:       config.HealthcheckFail,
+	events.TopicPriceOutOfBounds:   config.HealthcheckFail,
+	events.TopicSanityRuleViolated: config.HealthcheckFail,
+	events.TopicDenomUnconfigured:  config.HealthcheckFail,
+}
+
+// Events returns the event bus of the oracle, so other components, like
+// notifications or the API, can subscribe to its events.
+func (o *Oracle) Events() *events.Bus {
+	return o.events
+}
+
+// publish publishes an event of the topic on the event bus.
+func (o *Oracle) publish(topic events.Topic, message string, data interface{}) {
+	o.events.Publish(events.Event{
+		Topic:   topic,
+		Message: message,
+		Data:    data,
+	})
+}
+
+// subscribeEvents registers the built-in subscribers of the oracle.
+func (o *Oracle) subscribeEvents() {
+	topics := make([]events.Topic, 0, len(healthcheckEvents))
+	for topic := range healthcheckEvents {
+		topics = append(topics, topic)
+	}
+	o.events.Subscribe(func(event events.Event) {
+		o.healthchecksNotify(healthcheckEvents[event.Topic], event.Message)
+	}, topics...)
+
+	o.events.Subscribe(func(event events.Event) {
+		telemetry.IncrCounterWithLabels(
+			[]string{"events"},
+			1,
+			[]metrics.Label{telemetry.NewLabel("topic", string(event.Topic))},
+		)
+	})
+}
@@ -0,0 +1,140 @@
+package oracle
+
+import (
+	"sort"
+	"time"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GetExplanation returns the derivation of the current prices.
+func (o *Oracle) GetExplanation() types.Explanation {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	return o.explanation
+}
+
+// explainPrices returns the derivation of the prices computed by the
+// pipeline. Skipped contains the rates by denom, that were already removed
+// before the pipeline ran, e.g. blacklisted pairs.
+func explainPrices(
+	state *PipelineState,
+	skipped map[string][]types.ExcludedRate,
+	now time.Time,
+) types.Explanation {
+	explanation := types.Explanation{
+		Time:   now,
+		Prices: map[string]types.PriceExplanation{},
+	}
+
+	get := func(denom string) types.PriceExplanation {
+		price, found := explanation.Prices[denom]
+		if !found {
+			price = types.PriceExplanation{
+				Denom:     denom,
+				Providers: []types.ExplainedRate{},
+				Excluded:  []types.ExcludedRate{},
+				Formula:   state.Formulas[denom],
+			}
+		}
+		return price
+	}
+
+	for denom, rates := range skipped {
+		price := get(denom)
+		price.Excluded = append(price.Excluded, rates...)
+		explanation.Prices[denom] = price
+	}
+
+	for denom, rates := range state.Excluded {
+		if denom == "" {
+			continue
+		}
+		price := get(denom)
+		price.Excluded = append(price.Excluded, rates...)
+		explanation.Prices[denom] = price
+	}
+
+	for denom := range state.USDRates {
+		explanation.Prices[denom] = get(denom)
+	}
+
+	for denom, contributors := range state.Contributors {
+		price := get(denom)
+		price.Providers = explainRates(state, denom, contributors)
+		explanation.Prices[denom] = price
+	}
+
+	for denom, rate := range state.Prices {
+		rate := rate
+		price := get(denom)
+		price.Price = &rate
+		explanation.Prices[denom] = price
+	}
+
+	for denom, price := range explanation.Prices {
+		sort.Slice(price.Excluded, func(i, j int) bool {
+			if price.Excluded[i].Provider != price.Excluded[j].Provider {
+				return price.Excluded[i].Provider < price.Excluded[j].Provider
+			}
+			return price.Excluded[i].Symbol < price.Excluded[j].Symbol
+		})
+		explanation.Prices[denom] = price
+	}
+
+	return explanation
+}
+
+// explainRates returns the rates used in the VWAP of the denom, sorted by
+// provider. The weight of each rate is its share of the total volume, or
+// equal for all rates without any volume, like in ComputeVWAP.
+func explainRates(
+	state *PipelineState,
+	denom string,
+	rates map[provider.Name]types.TickerPrice,
+) []types.ExplainedRate {
+	totalVolume := sdk.ZeroDec()
+	for _, rate := range rates {
+		totalVolume = totalVolume.Add(rate.Volume)
+	}
+
+	explained := []types.ExplainedRate{}
+	for providerName, rate := range rates {
+		entry := types.ExplainedRate{
+			Provider:  providerName.String(),
+			Symbol:    denom + "USD",
+			Price:     rate.Price,
+			QuoteRate: sdk.OneDec(),
+			Rate:      rate.Price,
+			Volume:    rate.Volume,
+		}
+
+		for _, conversion := range state.Conversions {
+			if conversion.Denom == denom &&
+				conversion.Provider == providerName.String() {
+				entry.Symbol = conversion.Symbol
+				entry.Price = conversion.Price
+				entry.QuoteRate = conversion.QuoteRate
+				break
+			}
+		}
+
+		if totalVolume.IsZero() {
+			entry.Weight = sdk.OneDec().QuoInt64(int64(len(rates)))
+		} else {
+			entry.Weight = rate.Volume.Quo(totalVolume)
+		}
+
+		explained = append(explained, entry)
+	}
+
+	sort.Slice(explained, func(i, j int) bool {
+		return explained[i].Provider < explained[j].Provider
+	})
+
+	return explained
+}
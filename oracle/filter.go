@@ -1,6 +1,7 @@
 package oracle
 
 import (
+	"price-feeder/oracle/labels"
 	"price-feeder/oracle/provider"
 
 	"price-feeder/oracle/types"
@@ -45,8 +46,8 @@ func FilterTickerDeviations(
 		return tickerPrices, err
 	}
 
-	if stats {
-		labels := []metrics.Label{
+	if stats && labels.AllowedPair(symbol) {
+		symbolLabels := []metrics.Label{
 			telemetry.NewLabel("symbol", symbol),
 		}
 
@@ -55,12 +56,12 @@ func FilterTickerDeviations(
 		telemetry.SetGaugeWithLabels(
 			[]string{"deviation", "high"},
 			float32(mean.Add(margin).MustFloat64()),
-			labels,
+			symbolLabels,
 		)
 		telemetry.SetGaugeWithLabels(
 			[]string{"deviation", "low"},
 			float32(mean.Sub(margin).MustFloat64()),
-			labels,
+			symbolLabels,
 		)
 	}
 
@@ -0,0 +1,134 @@
+package oracle
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// updateGolden rewrites the golden files with the current results, e.g.
+// after an intended change of the aggregation:
+//
+//	go test ./oracle -run TestAggregationGolden -update
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+const (
+	goldenSnapshot = "testdata/aggregation.json"
+	goldenResult   = "testdata/aggregation.golden.json"
+)
+
+type (
+	// goldenInputs defines the recorded inputs of the aggregation.
+	goldenInputs struct {
+		DeviationThresholds  map[string]sdk.Dec `json:"deviation_thresholds"`
+		ProviderMinOverrides map[string]int     `json:"provider_min_overrides"`
+		Providers            map[provider.Name]struct {
+			Pairs   []string                     `json:"pairs"`
+			Tickers map[string]types.TickerPrice `json:"tickers"`
+		} `json:"providers"`
+	}
+
+	// goldenPrice defines the expected price of a denom and the providers
+	// contributing to it.
+	goldenPrice struct {
+		Price     string   `json:"price"`
+		Providers []string `json:"providers"`
+	}
+)
+
+func loadGoldenInputs(t *testing.T) (
+	provider.AggregatedProviderPrices,
+	map[provider.Name][]types.CurrencyPair,
+	map[string]sdk.Dec,
+	map[string]int,
+) {
+	data, err := os.ReadFile(goldenSnapshot)
+	require.NoError(t, err)
+
+	var inputs goldenInputs
+	require.NoError(t, json.Unmarshal(data, &inputs))
+
+	providerPrices := provider.AggregatedProviderPrices{}
+	providerPairs := map[provider.Name][]types.CurrencyPair{}
+	for providerName, snapshot := range inputs.Providers {
+		providerPrices[providerName] = snapshot.Tickers
+		for _, symbol := range snapshot.Pairs {
+			base, quote, found := strings.Cut(symbol, "/")
+			require.True(t, found, symbol)
+			providerPairs[providerName] = append(
+				providerPairs[providerName],
+				types.CurrencyPair{Base: base, Quote: quote},
+			)
+		}
+	}
+
+	return providerPrices, providerPairs,
+		inputs.DeviationThresholds, inputs.ProviderMinOverrides
+}
+
+// TestAggregationGolden runs the whole aggregation pipeline on a recorded
+// snapshot of 30 providers and 40 pairs, including deviating and invalid
+// tickers, and compares the exact prices with the golden file.
+func TestAggregationGolden(t *testing.T) {
+	providerPrices, providerPairs, deviations, minOverrides := loadGoldenInputs(t)
+
+	results := map[string]goldenPrice{}
+
+	// map iteration is random, so every run has to yield the same result
+	for i := 0; i < 10; i++ {
+		state, err := runPipeline(
+			NewPipeline(),
+			zerolog.Nop(),
+			providerPrices,
+			providerPairs,
+			deviations,
+			minOverrides,
+			nil,
+		)
+		require.NoError(t, err)
+
+		result := make(map[string]goldenPrice, len(state.Prices))
+		for denom, price := range state.Prices {
+			providers := []string{}
+			for providerName := range state.Contributors[denom] {
+				providers = append(providers, providerName.String())
+			}
+			sort.Strings(providers)
+
+			result[denom] = goldenPrice{
+				Price:     price.String(),
+				Providers: providers,
+			}
+		}
+
+		if i > 0 {
+			require.Equal(t, results, result)
+		}
+		results = result
+	}
+
+	if *updateGolden {
+		data, err := json.MarshalIndent(results, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(goldenResult, append(data, '\n'), 0o644))
+		return
+	}
+
+	data, err := os.ReadFile(goldenResult)
+	require.NoError(t, err)
+
+	expected := map[string]goldenPrice{}
+	require.NoError(t, json.Unmarshal(data, &expected))
+
+	require.Equal(t, expected, results)
+}
@@ -0,0 +1,141 @@
+package oracle
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"price-feeder/config"
+)
+
+// healthcheckFailInterval defines the minimum time between two fail pings of
+// the same healthcheck, so a failing tick doesn't flood the service.
+const healthcheckFailInterval = 1 * time.Minute
+
+// healthcheck defines an endpoint, that is notified about the configured
+// events. Following the healthchecks.io conventions, success is reported to
+// the plain url, start to <url>/start and failures to <url>/fail, including
+// the error as payload. A graceful shutdown is reported as success with the
+// message as payload, so the service doesn't alert on planned restarts.
+type healthcheck struct {
+	url    string
+	client http.Client
+	events map[string]struct{}
+
+	// events are published from several goroutines
+	mtx      sync.Mutex
+	lastFail time.Time
+}
+
+// allowFail returns true, if the last fail ping is at least
+// healthcheckFailInterval ago, and records the ping.
+func (h *healthcheck) allowFail(now time.Time) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if now.Sub(h.lastFail) < healthcheckFailInterval {
+		return false
+	}
+	h.lastFail = now
+	return true
+}
+
+func newHealthchecks(cfg []config.Healthchecks) ([]*healthcheck, error) {
+	healthchecks := make([]*healthcheck, 0, len(cfg))
+	for _, check := range cfg {
+		timeout, err := time.ParseDuration(check.Timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		events := check.Events
+		if len(events) == 0 {
+			events = []string{config.HealthcheckSuccess}
+		}
+
+		healthcheck := &healthcheck{
+			url:    strings.TrimSuffix(check.URL, "/"),
+			client: http.Client{Timeout: timeout},
+			events: make(map[string]struct{}, len(events)),
+		}
+		for _, event := range events {
+			healthcheck.events[event] = struct{}{}
+		}
+
+		healthchecks = append(healthchecks, healthcheck)
+	}
+
+	return healthchecks, nil
+}
+
+// healthchecksNotify notifies all healthchecks subscribed to the event. The
+// message is sent as payload of fail and stop events.
+func (o *Oracle) healthchecksNotify(event, message string) {
+	if event == config.HealthcheckSuccess && !o.allowHealthcheckPing(time.Now()) {
+		return
+	}
+
+	for _, check := range o.healthchecks {
+		if _, found := check.events[event]; !found {
+			continue
+		}
+
+		var (
+			resp *http.Response
+			err  error
+		)
+
+		switch event {
+		case config.HealthcheckSuccess:
+			resp, err = check.client.Get(check.url)
+		case config.HealthcheckStart:
+			resp, err = check.client.Get(check.url + "/start")
+		case config.HealthcheckFail:
+			if !check.allowFail(time.Now()) {
+				continue
+			}
+			resp, err = check.client.Post(
+				check.url+"/fail",
+				"text/plain",
+				strings.NewReader(message),
+			)
+		case config.HealthcheckStop:
+			resp, err = check.client.Post(
+				check.url,
+				"text/plain",
+				strings.NewReader(message),
+			)
+		default:
+			continue
+		}
+
+		if err != nil {
+			o.logger.Warn().
+				Err(err).
+				Str("event", event).
+				Msg("healthcheck ping failed")
+			continue
+		}
+		resp.Body.Close()
+
+		o.logger.Info().Str("event", event).Msg("updated healthcheck status")
+	}
+}
+
+// allowHealthcheckPing returns true, if the success pings aren't limited by
+// the healthcheck interval, and records the ping.
+func (o *Oracle) allowHealthcheckPing(now time.Time) bool {
+	if o.timing.healthcheck <= 0 {
+		return true
+	}
+
+	o.healthcheckMtx.Lock()
+	defer o.healthcheckMtx.Unlock()
+
+	if now.Sub(o.lastHealthcheckPing) < o.timing.healthcheck {
+		return false
+	}
+	o.lastHealthcheckPing = now
+	return true
+}
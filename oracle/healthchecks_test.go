@@ -0,0 +1,115 @@
+package oracle
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+	"price-feeder/pkg/events"
+)
+
+func TestHealthchecksNotify(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		requests []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mtx.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		mtx.Unlock()
+	}))
+	defer server.Close()
+
+	healthchecks, err := newHealthchecks([]config.Healthchecks{
+		{URL: server.URL + "/all/", Timeout: "1s", Events: []string{"success", "fail", "start"}},
+		{URL: server.URL + "/default", Timeout: "1s"},
+	})
+	require.NoError(t, err)
+
+	o := Oracle{
+		logger:       zerolog.Nop(),
+		healthchecks: healthchecks,
+	}
+
+	o.healthchecksNotify(config.HealthcheckStart, "")
+	o.healthchecksNotify(config.HealthcheckSuccess, "")
+	o.healthchecksNotify(config.HealthcheckFail, "missed vote")
+	// fail pings are rate limited
+	o.healthchecksNotify(config.HealthcheckFail, "missed vote")
+	o.healthchecksNotify(config.HealthcheckStop, "stopped")
+
+	require.Equal(t, []string{
+		"GET /all/start ",
+		"GET /all ",
+		"GET /default ",
+		"POST /all/fail missed vote",
+	}, requests)
+
+	// a graceful shutdown is no failure
+	healthchecks, err = newHealthchecks([]config.Healthchecks{
+		{URL: server.URL + "/stop", Timeout: "1s", Events: []string{"fail", "stop"}},
+	})
+	require.NoError(t, err)
+	o.healthchecks = healthchecks
+	requests = nil
+
+	o.healthchecksNotify(config.HealthcheckStop, "price feeder stopped")
+
+	require.Equal(t, []string{"POST /stop price feeder stopped"}, requests)
+}
+
+func TestHealthchecksEvents(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		requests []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mtx.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		mtx.Unlock()
+	}))
+	defer server.Close()
+
+	healthchecks, err := newHealthchecks([]config.Healthchecks{
+		{URL: server.URL, Timeout: "1s", Events: []string{"success", "fail"}},
+	})
+	require.NoError(t, err)
+
+	o := Oracle{
+		logger:       zerolog.Nop(),
+		healthchecks: healthchecks,
+		events:       events.NewBus(),
+	}
+	o.subscribeEvents()
+
+	topics := []events.Topic{}
+	o.Events().Subscribe(func(event events.Event) {
+		topics = append(topics, event.Topic)
+	})
+
+	o.publish(events.TopicVoteBroadcast, "", nil)
+	o.publish(events.TopicProviderFailed, "provider timed out", "binance")
+	o.publish(events.TopicVoteMissed, "missed vote during voting period", nil)
+
+	require.Equal(t, []string{
+		"GET / ",
+		"POST /fail missed vote during voting period",
+	}, requests)
+	require.Equal(t, []events.Topic{
+		events.TopicVoteBroadcast,
+		events.TopicProviderFailed,
+		events.TopicVoteMissed,
+	}, topics)
+}
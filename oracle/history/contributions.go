@@ -0,0 +1,193 @@
+package history
+
+import (
+	"time"
+)
+
+// contributionRetention defines how long the provider contributions are
+// kept.
+const contributionRetention = 30 * 24 * time.Hour
+
+// Outcomes of the rate of a provider for a denom in a single tick.
+const (
+	// ContributionAccepted defines a rate, that contributed to the price.
+	ContributionAccepted = "accepted"
+	// ContributionFiltered defines a rate, that was removed as outlier,
+	// blacklisted or otherwise not usable.
+	ContributionFiltered = "filtered"
+	// ContributionStale defines a ticker, that was too old to be used.
+	ContributionStale = "stale"
+	// ContributionMissing defines a ticker, that wasn't available, e.g. due
+	// to a failed or timed out provider.
+	ContributionMissing = "missing"
+)
+
+// Contributions defines how often the rates of a provider had each outcome.
+type Contributions struct {
+	Accepted int64 `json:"accepted"`
+	Filtered int64 `json:"filtered"`
+	Stale    int64 `json:"stale"`
+	Missing  int64 `json:"missing"`
+}
+
+func (p *PriceHistory) initContributions() error {
+	_, err := p.db.Exec(`
+		CREATE TABLE IF NOT EXISTS provider_contributions(
+			provider TEXT NOT NULL,
+			denom TEXT NOT NULL,
+			hour INT NOT NULL,
+			outcome TEXT NOT NULL,
+			count INT NOT NULL,
+			CONSTRAINT id PRIMARY KEY (provider, denom, hour, outcome)
+		)
+	`)
+	return err
+}
+
+// AddContributions counts the outcomes of a tick by provider and denom in
+// hourly buckets and removes buckets older than the retention period.
+func (p *PriceHistory) AddContributions(
+	outcomes map[string]map[string]string,
+	now time.Time,
+) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	hour := now.Truncate(time.Hour).Unix()
+	for provider, denoms := range outcomes {
+		for denom, outcome := range denoms {
+			_, err = tx.Exec(`
+				INSERT INTO provider_contributions(provider, denom, hour, outcome, count)
+				VALUES (?, ?, ?, ?, 1)
+				ON CONFLICT(provider, denom, hour, outcome) DO UPDATE SET count = count + 1
+			`, provider, denom, hour, outcome)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = tx.Exec(
+		"DELETE FROM provider_contributions WHERE hour < ?",
+		now.Add(-contributionRetention).Unix(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ProviderContributions returns the outcomes of the rates of the provider
+// by denom between from and to, in hourly resolution.
+func (p *PriceHistory) ProviderContributions(
+	provider string,
+	from, to time.Time,
+) (map[string]Contributions, error) {
+	rows, err := p.db.Query(`
+		SELECT denom, outcome, SUM(count) FROM provider_contributions
+		WHERE provider = ? AND hour BETWEEN ? AND ?
+		GROUP BY denom, outcome
+	`, provider, from.Truncate(time.Hour).Unix(), to.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contributions := map[string]Contributions{}
+	for rows.Next() {
+		var (
+			denom   string
+			outcome string
+			count   int64
+		)
+		if err := rows.Scan(&denom, &outcome, &count); err != nil {
+			return nil, err
+		}
+
+		c := contributions[denom]
+		c.add(outcome, count)
+		contributions[denom] = c
+	}
+
+	return contributions, rows.Err()
+}
+
+// AllContributions returns the outcomes of the rates of all providers by
+// provider and denom between from and to, in hourly resolution.
+func (p *PriceHistory) AllContributions(
+	from, to time.Time,
+) (map[string]map[string]Contributions, error) {
+	rows, err := p.db.Query(`
+		SELECT provider, denom, outcome, SUM(count) FROM provider_contributions
+		WHERE hour BETWEEN ? AND ?
+		GROUP BY provider, denom, outcome
+	`, from.Truncate(time.Hour).Unix(), to.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contributions := map[string]map[string]Contributions{}
+	for rows.Next() {
+		var (
+			provider string
+			denom    string
+			outcome  string
+			count    int64
+		)
+		if err := rows.Scan(&provider, &denom, &outcome, &count); err != nil {
+			return nil, err
+		}
+
+		if _, found := contributions[provider]; !found {
+			contributions[provider] = map[string]Contributions{}
+		}
+		c := contributions[provider][denom]
+		c.add(outcome, count)
+		contributions[provider][denom] = c
+	}
+
+	return contributions, rows.Err()
+}
+
+func (c *Contributions) add(outcome string, count int64) {
+	switch outcome {
+	case ContributionAccepted:
+		c.Accepted += count
+	case ContributionFiltered:
+		c.Filtered += count
+	case ContributionStale:
+		c.Stale += count
+	case ContributionMissing:
+		c.Missing += count
+	}
+}
+
+// Total returns the number of ticks with any outcome.
+func (c Contributions) Total() int64 {
+	return c.Accepted + c.Filtered + c.Stale + c.Missing
+}
+
+// Uptime returns the percentage of ticks, the rate was accepted. It returns
+// false, if there are no ticks.
+func (c Contributions) Uptime() (float64, bool) {
+	total := c.Total()
+	if total == 0 {
+		return 0, false
+	}
+	return float64(c.Accepted) * 100 / float64(total), true
+}
+
+// Add returns the sum of both contributions.
+func (c Contributions) Add(other Contributions) Contributions {
+	return Contributions{
+		Accepted: c.Accepted + other.Accepted,
+		Filtered: c.Filtered + other.Filtered,
+		Stale:    c.Stale + other.Stale,
+		Missing:  c.Missing + other.Missing,
+	}
+}
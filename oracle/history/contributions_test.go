@@ -0,0 +1,63 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceHistory_Contributions(t *testing.T) {
+	h, err := NewPriceHistory(":memory:", zerolog.Nop())
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+
+	// removed due to retention
+	require.NoError(t, h.AddContributions(
+		map[string]map[string]string{"binance": {"ATOM": ContributionMissing}},
+		now.Add(-contributionRetention-2*time.Hour),
+	))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, h.AddContributions(
+			map[string]map[string]string{
+				"binance": {"ATOM": ContributionAccepted, "BTC": ContributionStale},
+				"kraken":  {"ATOM": ContributionFiltered},
+			},
+			now.Add(time.Duration(i)*time.Minute),
+		))
+	}
+	require.NoError(t, h.AddContributions(
+		map[string]map[string]string{"binance": {"ATOM": ContributionFiltered}},
+		now.Add(time.Hour),
+	))
+
+	contributions, err := h.ProviderContributions(
+		"binance", now.Add(-contributionRetention-3*time.Hour), now.Add(time.Hour),
+	)
+	require.NoError(t, err)
+	require.Equal(t, map[string]Contributions{
+		"ATOM": {Accepted: 3, Filtered: 1},
+		"BTC":  {Stale: 3},
+	}, contributions)
+
+	contributions, err = h.ProviderContributions("kraken", now, now)
+	require.NoError(t, err)
+	require.Equal(t, map[string]Contributions{"ATOM": {Filtered: 3}}, contributions)
+
+	all, err := h.AllContributions(now, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]Contributions{
+		"binance": {"ATOM": {Accepted: 3, Filtered: 1}, "BTC": {Stale: 3}},
+		"kraken":  {"ATOM": {Filtered: 3}},
+	}, all)
+
+	uptime, ok := all["binance"]["ATOM"].Uptime()
+	require.True(t, ok)
+	require.Equal(t, 75.0, uptime)
+
+	_, ok = Contributions{}.Uptime()
+	require.False(t, ok)
+}
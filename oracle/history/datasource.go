@@ -0,0 +1,242 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// datasourceRetention defines how long computed prices and votes are
+	// kept for charting.
+	datasourceRetention = 7 * 24 * time.Hour
+
+	TargetPricePrefix    = "price:"
+	TargetProviderPrefix = "provider:"
+)
+
+type (
+	// Point defines a single value of a time series.
+	Point struct {
+		Time  time.Time
+		Value float64
+	}
+
+	// Vote defines a broadcasted vote. The height is the inclusion height,
+	// or 0 if the inclusion wasn't observed.
+	Vote struct {
+		Time          time.Time
+		Height        int64
+		ExchangeRates string
+		TxHash        string
+	}
+)
+
+func (p *PriceHistory) initDatasource() error {
+	_, err := p.db.Exec(`
+		CREATE TABLE IF NOT EXISTS computed_prices(
+			denom TEXT NOT NULL,
+			time INT NOT NULL,
+			price TEXT NOT NULL,
+			CONSTRAINT id PRIMARY KEY (denom, time)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(`
+		CREATE INDEX IF NOT EXISTS computed_prices_time ON computed_prices(time)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// the primary key starts with the time, so pruning needs no index
+	_, err = p.db.Exec(`
+		CREATE TABLE IF NOT EXISTS votes(
+			time INT NOT NULL,
+			height INT NOT NULL,
+			exchange_rates TEXT NOT NULL,
+			tx_hash TEXT NOT NULL,
+			CONSTRAINT id PRIMARY KEY (time, tx_hash)
+		)
+	`)
+	return err
+}
+
+// AddComputedPrices stores the final prices of a tick and periodically
+// removes prices older than the retention period.
+func (p *PriceHistory) AddComputedPrices(prices map[string]sdk.Dec, now time.Time) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for denom, price := range prices {
+		_, err = tx.Exec(
+			"INSERT OR REPLACE INTO computed_prices(denom, time, price) VALUES (?, ?, ?)",
+			denom, now.Unix(), price.String(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return p.prune("computed_prices", now.Add(-datasourceRetention).Unix(), now)
+}
+
+// AddVote stores a broadcasted vote and periodically removes votes older
+// than the retention period.
+func (p *PriceHistory) AddVote(vote Vote) error {
+	_, err := p.db.Exec(
+		"INSERT OR REPLACE INTO votes(time, height, exchange_rates, tx_hash) VALUES (?, ?, ?, ?)",
+		vote.Time.Unix(), vote.Height, vote.ExchangeRates, vote.TxHash,
+	)
+	if err != nil {
+		return err
+	}
+
+	return p.prune("votes", vote.Time.Add(-datasourceRetention).Unix(), vote.Time)
+}
+
+// Targets returns the names of all available time series, computed prices
+// as price:<denom> and provider prices as provider:<provider>:<symbol>.
+func (p *PriceHistory) Targets() ([]string, error) {
+	targets := []string{}
+
+	query := func(prefix, stmt string) error {
+		rows, err := p.db.Query(stmt)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return err
+			}
+			targets = append(targets, prefix+name)
+		}
+		return rows.Err()
+	}
+
+	err := query(TargetPricePrefix, "SELECT DISTINCT denom FROM computed_prices")
+	if err != nil {
+		return nil, err
+	}
+
+	err = query(
+		TargetProviderPrefix,
+		"SELECT DISTINCT provider || ':' || symbol FROM crypto_ticker_prices",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(targets)
+	return targets, nil
+}
+
+// Series returns the time series of the target between from and to.
+func (p *PriceHistory) Series(target string, from, to time.Time) ([]Point, error) {
+	var (
+		stmt string
+		args []interface{}
+	)
+
+	switch {
+	case strings.HasPrefix(target, TargetPricePrefix):
+		stmt = `
+			SELECT time, price FROM computed_prices
+			WHERE denom = ? AND time BETWEEN ? AND ?
+			ORDER BY time ASC
+		`
+		args = []interface{}{strings.TrimPrefix(target, TargetPricePrefix)}
+
+	case strings.HasPrefix(target, TargetProviderPrefix):
+		parts := strings.SplitN(strings.TrimPrefix(target, TargetProviderPrefix), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid target: %s", target)
+		}
+		stmt = `
+			SELECT time, price FROM crypto_ticker_prices
+			WHERE provider = ? AND symbol = ? AND time BETWEEN ? AND ?
+			ORDER BY time ASC
+		`
+		args = []interface{}{parts[0], parts[1]}
+
+	default:
+		return nil, fmt.Errorf("invalid target: %s", target)
+	}
+
+	args = append(args, from.Unix(), to.Unix())
+
+	rows, err := p.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []Point{}
+	for rows.Next() {
+		var (
+			epochTime int64
+			price     string
+		)
+		if err := rows.Scan(&epochTime, &price); err != nil {
+			return nil, err
+		}
+
+		value, err := sdk.NewDecFromStr(price)
+		if err != nil {
+			return nil, err
+		}
+
+		points = append(points, Point{
+			Time:  time.Unix(epochTime, 0),
+			Value: value.MustFloat64(),
+		})
+	}
+
+	return points, rows.Err()
+}
+
+// Votes returns all votes between from and to.
+func (p *PriceHistory) Votes(from, to time.Time) ([]Vote, error) {
+	rows, err := p.db.Query(`
+		SELECT time, height, exchange_rates, tx_hash FROM votes
+		WHERE time BETWEEN ? AND ?
+		ORDER BY time ASC
+	`, from.Unix(), to.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	votes := []Vote{}
+	for rows.Next() {
+		var (
+			vote      Vote
+			epochTime int64
+		)
+		err := rows.Scan(&epochTime, &vote.Height, &vote.ExchangeRates, &vote.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		vote.Time = time.Unix(epochTime, 0)
+		votes = append(votes, vote)
+	}
+
+	return votes, rows.Err()
+}
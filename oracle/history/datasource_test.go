@@ -0,0 +1,50 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceHistory_Datasource(t *testing.T) {
+	h, err := NewPriceHistory(":memory:", zerolog.Nop())
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+
+	// removed due to retention
+	require.NoError(t, h.AddComputedPrices(
+		map[string]sdk.Dec{"ATOM": sdk.NewDec(9)},
+		now.Add(-datasourceRetention-time.Second),
+	))
+	require.NoError(t, h.AddComputedPrices(
+		map[string]sdk.Dec{"ATOM": sdk.NewDec(10), "KUJI": sdk.NewDec(1)},
+		now,
+	))
+	require.NoError(t, h.AddTickerPrice(testPairAtom, "osmosis", testHistoricalTickers1["osmosis"][0]))
+	require.NoError(t, h.AddVote(Vote{Time: now, Height: 100, TxHash: "ABCD"}))
+
+	targets, err := h.Targets()
+	require.NoError(t, err)
+	require.Equal(t, []string{"price:ATOM", "price:KUJI", "provider:osmosis:ATOMUSD"}, targets)
+
+	points, err := h.Series("price:ATOM", now.Add(-30*24*time.Hour), now)
+	require.NoError(t, err)
+	require.Equal(t, []Point{{Time: now, Value: 10}}, points)
+
+	points, err = h.Series("provider:osmosis:ATOMUSD", time.Unix(0, 0), now)
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+	require.Equal(t, float64(5), points[0].Value)
+
+	_, err = h.Series("foo", time.Unix(0, 0), now)
+	require.Error(t, err)
+
+	votes, err := h.Votes(now.Add(-time.Minute), now)
+	require.NoError(t, err)
+	require.Len(t, votes, 1)
+	require.Equal(t, "ABCD", votes[0].TxHash)
+}
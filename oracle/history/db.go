@@ -16,6 +16,7 @@ type (
 		insert  *sql.Stmt
 		query   *sql.Stmt
 		cleanup *sql.Stmt
+		pruner  *pruner
 		logger  zerolog.Logger
 	}
 )
@@ -48,6 +49,38 @@ func (p *PriceHistory) Init() error {
 		return err
 	}
 
+	p.pruner = newPruner()
+
+	err = p.initDatasource()
+	if err != nil {
+		p.logger.Error().Err(err).Msg("failed to create datasource tables")
+		return err
+	}
+
+	err = p.initDispersion()
+	if err != nil {
+		p.logger.Error().Err(err).Msg("failed to create dispersion table")
+		return err
+	}
+
+	err = p.initContributions()
+	if err != nil {
+		p.logger.Error().Err(err).Msg("failed to create contributions table")
+		return err
+	}
+
+	err = p.initVolumes()
+	if err != nil {
+		p.logger.Error().Err(err).Msg("failed to create volumes table")
+		return err
+	}
+
+	err = p.initTickers()
+	if err != nil {
+		p.logger.Error().Err(err).Msg("failed to create tickers table")
+		return err
+	}
+
 	_, err = p.db.Exec("VACUUM")
 	if err != nil {
 		p.logger.Error().Err(err).Msg("failed to vacuum database")
@@ -161,3 +194,45 @@ func (p *PriceHistory) GetTickerPrices(
 	}
 	return tickers, nil
 }
+
+// PruneTickerPrices deletes the ticker prices of all symbols, that are not
+// configured anymore, and returns the number of deleted rows. Tickers of
+// configured symbols are removed once they're older than the period read
+// by GetTickerPrices.
+func (p *PriceHistory) PruneTickerPrices(configured map[string]struct{}) (int64, error) {
+	rows, err := p.db.Query("SELECT DISTINCT symbol FROM crypto_ticker_prices")
+	if err != nil {
+		return 0, err
+	}
+
+	orphans := []string{}
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if _, found := configured[symbol]; !found {
+			orphans = append(orphans, symbol)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, symbol := range orphans {
+		result, err := p.db.Exec("DELETE FROM crypto_ticker_prices WHERE symbol = ?", symbol)
+		if err != nil {
+			return deleted, err
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += count
+	}
+
+	return deleted, nil
+}
@@ -50,3 +50,26 @@ func TestPriceHistory_getPrices(t *testing.T) {
 	require.NoError(t, err2)
 	require.Equal(t, testHistoricalTickers1, res2)
 }
+
+func TestPriceHistory_PruneTickerPrices(t *testing.T) {
+	h, err := NewPriceHistory(":memory:", zerolog.Nop())
+	require.NoError(t, err)
+
+	pairKuji := types.CurrencyPair{Base: "KUJI", Quote: "USD"}
+	for _, ticker := range testHistoricalTickers1["osmosis"] {
+		require.NoError(t, h.AddTickerPrice(testPairAtom, "osmosis", ticker))
+		require.NoError(t, h.AddTickerPrice(pairKuji, "osmosis", ticker))
+	}
+
+	deleted, err := h.PruneTickerPrices(map[string]struct{}{"ATOMUSD": {}})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), deleted)
+
+	tickers, err := h.GetTickerPrices("KUJIUSD", testStartTime1, testEndTime1)
+	require.NoError(t, err)
+	require.Empty(t, tickers)
+
+	tickers, err = h.GetTickerPrices("ATOMUSD", testStartTime1, testEndTime1)
+	require.NoError(t, err)
+	require.Len(t, tickers["osmosis"], 3)
+}
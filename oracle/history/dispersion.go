@@ -0,0 +1,101 @@
+package history
+
+import (
+	"time"
+)
+
+// dispersionRetention defines how long the price dispersion is kept for
+// deriving deviation thresholds.
+const dispersionRetention = 30 * 24 * time.Hour
+
+// Dispersion defines the spread of the provider prices of a denom in a
+// single tick. Relative is the standard deviation divided by the mean and
+// MaxScore the largest distance of a provider price from the mean in
+// standard deviations.
+type Dispersion struct {
+	Time     time.Time
+	Relative float64
+	MaxScore float64
+}
+
+func (p *PriceHistory) initDispersion() error {
+	_, err := p.db.Exec(`
+		CREATE TABLE IF NOT EXISTS price_dispersion(
+			denom TEXT NOT NULL,
+			time INT NOT NULL,
+			relative REAL NOT NULL,
+			max_score REAL NOT NULL,
+			CONSTRAINT id PRIMARY KEY (denom, time)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(`
+		CREATE INDEX IF NOT EXISTS price_dispersion_time ON price_dispersion(time)
+	`)
+	return err
+}
+
+// AddDispersions stores the price dispersion of a tick by denom and
+// periodically removes entries older than the retention period.
+func (p *PriceHistory) AddDispersions(
+	dispersions map[string]Dispersion,
+	now time.Time,
+) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for denom, dispersion := range dispersions {
+		_, err = tx.Exec(
+			"INSERT OR REPLACE INTO price_dispersion(denom, time, relative, max_score) VALUES (?, ?, ?, ?)",
+			denom, now.Unix(), dispersion.Relative, dispersion.MaxScore,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return p.prune("price_dispersion", now.Add(-dispersionRetention).Unix(), now)
+}
+
+// Dispersions returns the price dispersion by denom between from and to.
+func (p *PriceHistory) Dispersions(from, to time.Time) (map[string][]Dispersion, error) {
+	rows, err := p.db.Query(`
+		SELECT denom, time, relative, max_score FROM price_dispersion
+		WHERE time BETWEEN ? AND ?
+		ORDER BY time ASC
+	`, from.Unix(), to.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dispersions := map[string][]Dispersion{}
+	for rows.Next() {
+		var (
+			denom      string
+			epochTime  int64
+			dispersion Dispersion
+		)
+		err := rows.Scan(
+			&denom, &epochTime, &dispersion.Relative, &dispersion.MaxScore,
+		)
+		if err != nil {
+			return nil, err
+		}
+		dispersion.Time = time.Unix(epochTime, 0)
+		dispersions[denom] = append(dispersions[denom], dispersion)
+	}
+
+	return dispersions, rows.Err()
+}
@@ -0,0 +1,72 @@
+package history
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// pruneInterval defines how often rows older than the retention period
+	// are deleted from a table, instead of on every write.
+	pruneInterval = 10 * time.Minute
+	// pruneBatchSize limits the rows deleted per statement, so pruning a
+	// large backlog doesn't block other writers for long.
+	pruneBatchSize = 1000
+)
+
+// pruner tracks when the tables were pruned last. It's shared by all
+// copies of the PriceHistory.
+type pruner struct {
+	mtx  sync.Mutex
+	last map[string]time.Time
+}
+
+func newPruner() *pruner {
+	return &pruner{last: map[string]time.Time{}}
+}
+
+// due returns true and marks the table as pruned, if it wasn't pruned
+// within pruneInterval before now.
+func (p *pruner) due(table string, now time.Time) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	last, found := p.last[table]
+	if found && now.Sub(last) < pruneInterval && now.After(last) {
+		return false
+	}
+
+	p.last[table] = now
+	return true
+}
+
+// prune deletes the rows of the table with a time before the cutoff, at
+// most once per pruneInterval and in batches of pruneBatchSize. The time
+// column of the table needs to be indexed.
+func (p *PriceHistory) prune(table string, cutoff int64, now time.Time) error {
+	if p.pruner == nil || !p.pruner.due(table, now) {
+		return nil
+	}
+
+	stmt := fmt.Sprintf(`
+		DELETE FROM %s WHERE rowid IN (
+			SELECT rowid FROM %s WHERE time < ? LIMIT ?
+		)
+	`, table, table)
+
+	for {
+		result, err := p.db.Exec(stmt, cutoff, pruneBatchSize)
+		if err != nil {
+			return err
+		}
+
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if deleted < pruneBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrune(t *testing.T) {
+	h, err := NewPriceHistory(":memory:", zerolog.Nop())
+	require.NoError(t, err)
+
+	countPrices := func() int {
+		var count int
+		err := h.db.QueryRow("SELECT COUNT(*) FROM computed_prices").Scan(&count)
+		require.NoError(t, err)
+		return count
+	}
+
+	now := time.Unix(1700000000, 0)
+
+	// more rows than a single batch
+	for i := 0; i < pruneBatchSize+10; i++ {
+		_, err := h.db.Exec(
+			"INSERT INTO computed_prices(denom, time, price) VALUES (?, ?, ?)",
+			"ATOM", i, "1",
+		)
+		require.NoError(t, err)
+	}
+
+	prices := map[string]sdk.Dec{"ATOM": sdk.NewDec(10)}
+
+	require.NoError(t, h.AddComputedPrices(prices, now))
+	require.Equal(t, 1, countPrices())
+
+	// outdated prices are kept until the next prune is due
+	old := now.Add(-datasourceRetention - time.Hour)
+	_, err = h.db.Exec(
+		"INSERT INTO computed_prices(denom, time, price) VALUES (?, ?, ?)",
+		"ATOM", old.Unix(), "1",
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, h.AddComputedPrices(prices, now.Add(time.Minute)))
+	require.Equal(t, 3, countPrices())
+
+	// the outdated price is removed, the prices of the three ticks are kept
+	require.NoError(t, h.AddComputedPrices(prices, now.Add(pruneInterval)))
+	require.Equal(t, 3, countPrices())
+}
+
+func TestPruneDispersions(t *testing.T) {
+	h, err := NewPriceHistory(":memory:", zerolog.Nop())
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	dispersions := map[string]Dispersion{"ATOM": {Relative: 0.01, MaxScore: 1}}
+
+	require.NoError(t, h.AddDispersions(dispersions, now.Add(-dispersionRetention-time.Hour)))
+	require.NoError(t, h.AddDispersions(dispersions, now))
+
+	stored, err := h.Dispersions(time.Unix(0, 0), now)
+	require.NoError(t, err)
+	require.Len(t, stored["ATOM"], 1)
+
+	// pruning uses the time index
+	var (
+		id, parent, unused int
+		detail             string
+	)
+	err = h.db.QueryRow(
+		"EXPLAIN QUERY PLAN SELECT rowid FROM price_dispersion WHERE time < ?", 0,
+	).Scan(&id, &parent, &unused, &detail)
+	require.NoError(t, err)
+	require.Contains(t, detail, "price_dispersion_time")
+}
@@ -0,0 +1,113 @@
+package history
+
+import (
+	"time"
+
+	"price-feeder/oracle/types"
+)
+
+// ProviderTicker defines a ticker collected from a provider.
+type ProviderTicker struct {
+	Provider string
+	Ticker   types.TickerPrice
+}
+
+func (p *PriceHistory) initTickers() error {
+	_, err := p.db.Exec(`
+		CREATE TABLE IF NOT EXISTS provider_tickers(
+			provider TEXT NOT NULL,
+			symbol TEXT NOT NULL,
+			time INT NOT NULL,
+			price TEXT NOT NULL,
+			volume TEXT NOT NULL,
+			CONSTRAINT id PRIMARY KEY (provider, symbol, time)
+		)
+	`)
+	return err
+}
+
+// AddProviderTickers stores the tickers collected for a vote by provider and
+// symbol and removes tickers older than the retention period. Tickers are
+// stored with millisecond timestamps, unchanged tickers are only stored once.
+func (p *PriceHistory) AddProviderTickers(
+	tickers map[string]map[string]types.TickerPrice,
+	now time.Time,
+	retention time.Duration,
+) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for provider, symbols := range tickers {
+		for symbol, ticker := range symbols {
+			volume := ""
+			if !ticker.Volume.IsNil() {
+				volume = ticker.Volume.String()
+			}
+
+			_, err = tx.Exec(`
+				INSERT OR IGNORE INTO provider_tickers(provider, symbol, time, price, volume)
+				VALUES (?, ?, ?, ?, ?)
+			`, provider, symbol, ticker.Time.UnixMilli(), ticker.Price.String(), volume)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = tx.Exec(
+		"DELETE FROM provider_tickers WHERE time < ?",
+		now.Add(-retention).UnixMilli(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetProviderTickers returns the stored tickers of the symbol between from
+// and to, sorted by time.
+func (p *PriceHistory) GetProviderTickers(
+	symbol string,
+	from, to time.Time,
+) ([]ProviderTicker, error) {
+	rows, err := p.db.Query(`
+		SELECT provider, time, price, volume FROM provider_tickers
+		WHERE symbol = ? AND time BETWEEN ? AND ?
+		ORDER BY time ASC, provider ASC
+	`, symbol, from.UnixMilli(), to.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tickers := []ProviderTicker{}
+	for rows.Next() {
+		var (
+			provider      string
+			epoch         int64
+			price, volume string
+		)
+		if err := rows.Scan(&provider, &epoch, &price, &volume); err != nil {
+			return nil, err
+		}
+
+		if volume == "" {
+			volume = "0"
+		}
+		ticker, err := types.NewTickerPrice(price, volume, time.UnixMilli(epoch))
+		if err != nil {
+			return nil, err
+		}
+
+		tickers = append(tickers, ProviderTicker{
+			Provider: provider,
+			Ticker:   ticker,
+		})
+	}
+
+	return tickers, rows.Err()
+}
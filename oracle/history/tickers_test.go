@@ -0,0 +1,57 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceHistory_ProviderTickers(t *testing.T) {
+	h, err := NewPriceHistory(":memory:", zerolog.Nop())
+	require.NoError(t, err)
+
+	now := time.UnixMilli(1700000000123)
+	retention := 24 * time.Hour
+
+	ticker := func(price int64, timestamp time.Time) types.TickerPrice {
+		return types.TickerPrice{
+			Price:  sdk.NewDec(price),
+			Volume: sdk.NewDec(1000),
+			Time:   timestamp,
+		}
+	}
+
+	old := now.Add(-retention - time.Minute)
+	require.NoError(t, h.AddProviderTickers(
+		map[string]map[string]types.TickerPrice{
+			"binance": {"ATOMUSDT": ticker(9, old)},
+		},
+		old,
+		retention,
+	))
+
+	tickers := map[string]map[string]types.TickerPrice{
+		"binance": {"ATOMUSDT": ticker(10, now)},
+		"kraken":  {"ATOMUSDT": ticker(11, now.Add(-time.Second)), "BTCUSD": ticker(40000, now)},
+	}
+	require.NoError(t, h.AddProviderTickers(tickers, now, retention))
+	// unchanged tickers are stored once
+	require.NoError(t, h.AddProviderTickers(tickers, now, retention))
+
+	stored, err := h.GetProviderTickers("ATOMUSDT", now.Add(-2*retention), now)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+
+	require.Equal(t, "kraken", stored[0].Provider)
+	require.Equal(t, "11.000000000000000000", stored[0].Ticker.Price.String())
+	require.Equal(t, now.Add(-time.Second).UnixMilli(), stored[0].Ticker.Time.UnixMilli())
+
+	require.Equal(t, "binance", stored[1].Provider)
+	require.Equal(t, "10.000000000000000000", stored[1].Ticker.Price.String())
+	require.Equal(t, "1000.000000000000000000", stored[1].Ticker.Volume.String())
+}
@@ -0,0 +1,130 @@
+package history
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// volumeRetention defines how long the provider volume snapshots are kept.
+const volumeRetention = 30 * 24 * time.Hour
+
+// VolumeSnapshot defines the 24h volume reported by a provider for a symbol
+// at a point in time.
+type VolumeSnapshot struct {
+	Time   time.Time
+	Volume sdk.Dec
+}
+
+func (p *PriceHistory) initVolumes() error {
+	_, err := p.db.Exec(`
+		CREATE TABLE IF NOT EXISTS provider_volumes(
+			provider TEXT NOT NULL,
+			symbol TEXT NOT NULL,
+			time INT NOT NULL,
+			volume TEXT NOT NULL,
+			CONSTRAINT id PRIMARY KEY (provider, symbol, time)
+		)
+	`)
+	return err
+}
+
+// AddProviderVolumes stores the 24h volumes by provider and symbol and
+// removes snapshots older than the retention period.
+func (p *PriceHistory) AddProviderVolumes(
+	volumes map[string]map[string]sdk.Dec,
+	now time.Time,
+) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for provider, symbols := range volumes {
+		for symbol, volume := range symbols {
+			_, err = tx.Exec(
+				"INSERT OR REPLACE INTO provider_volumes(provider, symbol, time, volume) VALUES (?, ?, ?, ?)",
+				provider, symbol, now.Unix(), volume.String(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = tx.Exec(
+		"DELETE FROM provider_volumes WHERE time < ?",
+		now.Add(-volumeRetention).Unix(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetProviderVolumes returns the volume snapshots of the symbol by provider
+// between from and to, sorted by time.
+func (p *PriceHistory) GetProviderVolumes(
+	symbol string,
+	from, to time.Time,
+) (map[string][]VolumeSnapshot, error) {
+	rows, err := p.db.Query(`
+		SELECT provider, time, volume FROM provider_volumes
+		WHERE symbol = ? AND time BETWEEN ? AND ?
+		ORDER BY time ASC
+	`, symbol, from.Unix(), to.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := map[string][]VolumeSnapshot{}
+	for rows.Next() {
+		var (
+			provider string
+			epoch    int64
+			value    string
+		)
+		if err := rows.Scan(&provider, &epoch, &value); err != nil {
+			return nil, err
+		}
+
+		volume, err := sdk.NewDecFromStr(value)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots[provider] = append(snapshots[provider], VolumeSnapshot{
+			Time:   time.Unix(epoch, 0),
+			Volume: volume,
+		})
+	}
+
+	return snapshots, rows.Err()
+}
+
+// AverageProviderVolumes returns the mean of the volume snapshots of the
+// symbol by provider between from and to. Providers without snapshots are
+// left out.
+func (p *PriceHistory) AverageProviderVolumes(
+	symbol string,
+	from, to time.Time,
+) (map[string]sdk.Dec, error) {
+	snapshots, err := p.GetProviderVolumes(symbol, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	averages := make(map[string]sdk.Dec, len(snapshots))
+	for provider, volumes := range snapshots {
+		total := sdk.ZeroDec()
+		for _, snapshot := range volumes {
+			total = total.Add(snapshot.Volume)
+		}
+		averages[provider] = total.QuoInt64(int64(len(volumes)))
+	}
+
+	return averages, nil
+}
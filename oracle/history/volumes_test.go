@@ -0,0 +1,47 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceHistory_ProviderVolumes(t *testing.T) {
+	h, err := NewPriceHistory(":memory:", zerolog.Nop())
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+
+	// removed due to retention
+	require.NoError(t, h.AddProviderVolumes(
+		map[string]map[string]sdk.Dec{"binance": {"ATOMUSDT": sdk.NewDec(1)}},
+		now.Add(-volumeRetention-time.Hour),
+	))
+
+	for i, volume := range []int64{100, 200, 600} {
+		require.NoError(t, h.AddProviderVolumes(
+			map[string]map[string]sdk.Dec{
+				"binance": {"ATOMUSDT": sdk.NewDec(volume)},
+				"kraken":  {"ATOMUSD": sdk.NewDec(volume)},
+			},
+			now.Add(time.Duration(i)*15*time.Minute),
+		))
+	}
+
+	snapshots, err := h.GetProviderVolumes(
+		"ATOMUSDT", now.Add(-volumeRetention-2*time.Hour), now.Add(time.Hour),
+	)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.Len(t, snapshots["binance"], 3)
+	require.Equal(t, now.Unix(), snapshots["binance"][0].Time.Unix())
+	require.Equal(t, "600.000000000000000000", snapshots["binance"][2].Volume.String())
+
+	averages, err := h.AverageProviderVolumes("ATOMUSDT", now, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, averages, 1)
+	require.Equal(t, "300.000000000000000000", averages["binance"].String())
+}
@@ -0,0 +1,187 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"price-feeder/oracle/client"
+	"price-feeder/pkg/events"
+
+	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
+)
+
+// VoteMismatch defines the difference between a submitted vote and the
+// aggregate vote stored on chain.
+type VoteMismatch struct {
+	Height     int64    `json:"height"`
+	Submitted  string   `json:"submitted"`
+	Stored     string   `json:"stored"`
+	Mismatches []string `json:"mismatches"`
+}
+
+// GetAggregateVote returns the aggregate vote of the validator stored at
+// the given height. The vote is only stored until the vote period is
+// tallied, so a missing vote doesn't mean it wasn't accepted.
+func (o *Oracle) GetAggregateVote(
+	ctx context.Context,
+	height int64,
+) (oracletypes.ExchangeRateTuples, error) {
+	grpcConn, err := o.dialGRPC()
+	if err != nil {
+		return nil, err
+	}
+	defer grpcConn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	ctx = metadata.AppendToOutgoingContext(
+		ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(height, 10),
+	)
+
+	exchangeRates, err := o.chainProfile.AggregateVote(
+		ctx, grpcConn, o.oracleClient.ValidatorAddrString,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aggregate vote: %w", err)
+	}
+
+	return exchangeRates, nil
+}
+
+// voteInclusionTimeout defines how long the inclusion of a (pre)vote tx is
+// awaited.
+const voteInclusionTimeout = time.Minute
+
+// waitForInclusion returns the height the (pre)vote tx was included at. It
+// is looked up by the tx hash, as sync broadcasts return before the tx is
+// included.
+func (o *Oracle) waitForInclusion(ctx context.Context, hash string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, voteInclusionTimeout)
+	defer cancel()
+
+	return o.oracleClient.WaitForTx(ctx, hash)
+}
+
+// awaitPrevote observes the commit timing of the prevote once it is
+// included.
+func (o *Oracle) awaitPrevote(ctx context.Context, hash string, nextBlockHeight, votePeriod int64) {
+	height, err := o.waitForInclusion(ctx, hash)
+	if err != nil {
+		o.logger.Warn().Err(err).Str("tx_hash", hash).Msg("failed to observe prevote commit")
+		return
+	}
+
+	o.observeVoteCommit(txKindPrevote, nextBlockHeight, height, votePeriod)
+}
+
+// awaitVote records the vote with its inclusion height, observes its commit
+// timing and verifies the stored vote once it is included. A vote whose
+// inclusion isn't observed is still recorded, without a height.
+func (o *Oracle) awaitVote(
+	ctx context.Context,
+	result client.TxResult,
+	broadcastTime time.Time,
+	exchangeRates string,
+	nextBlockHeight, votePeriod int64,
+) {
+	height, err := o.waitForInclusion(ctx, result.Hash)
+	if err != nil {
+		o.logger.Warn().Err(err).Str("tx_hash", result.Hash).Msg("failed to observe vote commit")
+		o.recordVote(result, broadcastTime, 0, exchangeRates)
+		return
+	}
+
+	o.recordVote(result, broadcastTime, height, exchangeRates)
+	o.observeVoteCommit(txKindVote, nextBlockHeight, height, votePeriod)
+	o.verifyVote(ctx, result.Hash, height, exchangeRates)
+}
+
+// verifyVote compares the aggregate vote stored on chain at the inclusion
+// height with the submitted exchange rates, to catch encoding or
+// truncation bugs right away instead of when misses accumulate.
+func (o *Oracle) verifyVote(ctx context.Context, hash string, height int64, exchangeRates string) {
+	logger := o.logger.With().Str("tx_hash", hash).Int64("height", height).Logger()
+
+	stored, err := o.GetAggregateVote(ctx, height)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to verify vote")
+		return
+	}
+
+	if len(stored) == 0 {
+		// the vote period was tallied in the same block
+		logger.Debug().Msg("vote not stored anymore, verification inconclusive")
+		return
+	}
+
+	// the stored tuples are compared in the default format
+	mismatches := compareVote(
+		strings.ReplaceAll(exchangeRates, o.ratesFormat.Separator, ","), stored,
+	)
+	if len(mismatches) == 0 {
+		logger.Debug().Msg("vote verified")
+		return
+	}
+
+	telemetry.IncrCounter(1, "vote", "mismatch")
+
+	logger.Error().
+		Str("submitted", exchangeRates).
+		Str("stored", stored.String()).
+		Strs("mismatches", mismatches).
+		Msg("stored vote differs from submitted vote")
+
+	o.publish(events.TopicVoteMismatch, "stored vote differs from submitted vote", VoteMismatch{
+		Height:     height,
+		Submitted:  exchangeRates,
+		Stored:     stored.String(),
+		Mismatches: mismatches,
+	})
+}
+
+// compareVote returns a description of every difference between the
+// submitted exchange rates and the stored tuples. The rates have to match
+// exactly, as the chain stores the parsed submission as is.
+func compareVote(submitted string, stored oracletypes.ExchangeRateTuples) []string {
+	tuples, err := oracletypes.ParseExchangeRateTuples(submitted)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to parse submitted rates: %s", err)}
+	}
+
+	storedRates := make(map[string]string, len(stored))
+	for _, tuple := range stored {
+		storedRates[tuple.Denom] = tuple.ExchangeRate.String()
+	}
+
+	mismatches := []string{}
+	for _, tuple := range tuples {
+		rate := tuple.ExchangeRate.String()
+		storedRate, found := storedRates[tuple.Denom]
+		delete(storedRates, tuple.Denom)
+		if !found {
+			mismatches = append(mismatches, fmt.Sprintf("%s: missing on chain", tuple.Denom))
+			continue
+		}
+		if storedRate != rate {
+			mismatches = append(mismatches, fmt.Sprintf(
+				"%s: submitted %s, stored %s", tuple.Denom, rate, storedRate,
+			))
+		}
+	}
+
+	for denom := range storedRates {
+		mismatches = append(mismatches, fmt.Sprintf("%s: not submitted", denom))
+	}
+
+	sort.Strings(mismatches)
+	return mismatches
+}
@@ -0,0 +1,30 @@
+package oracle
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
+)
+
+func TestCompareVote(t *testing.T) {
+	stored := oracletypes.ExchangeRateTuples{
+		{Denom: "ATOM", ExchangeRate: sdk.MustNewDecFromStr("9.874")},
+		{Denom: "KUJI", ExchangeRate: sdk.MustNewDecFromStr("1.0235")},
+	}
+
+	require.Empty(t, compareVote("9.874ATOM,1.023500KUJI", stored))
+
+	require.Equal(t, []string{
+		"KUJI: submitted 1.023000000000000000, stored 1.023500000000000000",
+	}, compareVote("9.874ATOM,1.023KUJI", stored))
+
+	require.Equal(t, []string{
+		"KUJI: not submitted",
+		"OSMO: missing on chain",
+	}, compareVote("9.874ATOM,0.9821OSMO", stored))
+
+	require.Len(t, compareVote("9.874", stored), 1)
+}
@@ -0,0 +1,214 @@
+package oracle
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/config"
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+)
+
+// inverseMinRatio is the factor a price has to be off its reference, while
+// being within the factor of the inverse, to be considered inverted. Prices
+// of pairs trading around 1 can't be told apart from their inverse and are
+// left to the deviation filter.
+const inverseMinRatio = 2.0
+
+// minInverseReferences is the minimum number of other providers of the same
+// symbol used as reference. With a single other provider, it can't be told
+// which of both is inverted.
+const minInverseReferences = 2
+
+// InvertedPrice defines a provider price rejected as inverted.
+type InvertedPrice struct {
+	Provider  provider.Name
+	Symbol    string
+	Price     sdk.Dec
+	Reference sdk.Dec
+}
+
+// isInverted returns true if the price is off the reference by more than
+// inverseMinRatio, but within that ratio of the inverse of the reference.
+func isInverted(price, reference sdk.Dec) bool {
+	if !price.IsPositive() || !reference.IsPositive() {
+		return false
+	}
+
+	p, r := price.MustFloat64(), reference.MustFloat64()
+	direct := math.Abs(math.Log(p / r))
+	inverse := math.Abs(math.Log(p * r))
+
+	limit := math.Log(inverseMinRatio)
+	return direct > limit && inverse < limit
+}
+
+// medianDec returns the median of the values, which must not be empty.
+func medianDec(values []sdk.Dec) sdk.Dec {
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].LT(values[j])
+	})
+
+	middle := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[middle]
+	}
+	return values[middle-1].Add(values[middle]).QuoInt64(2)
+}
+
+// InverseDetection returns a middleware for StageFilter, removing provider
+// prices, which are obviously inverted, e.g. of a newly added DEX pair
+// resolved in the wrong orientation. The orientation is a property of the
+// pair config, so every provider symbol is checked once, on the first tick
+// with a reference, which is usually the first tick after startup. The
+// result is kept until restart: inverted symbols are refused on all later
+// ticks, while checked symbols aren't rejected later, e.g. during a crash.
+//
+// The reference is the median of all prices of the symbol, if a strict
+// majority of them is close to it, or, with too few providers, the price
+// derived from the last computed prices.
+func InverseDetection(
+	lookup func(pair types.CurrencyPair) (sdk.Dec, bool),
+	report func(InvertedPrice),
+) Middleware {
+	var (
+		mtx sync.Mutex
+		// checked holds whether a provider symbol is inverted
+		checked = map[string]bool{}
+	)
+
+	return func(next StageFunc) StageFunc {
+		return func(state *PipelineState) error {
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			pairs := make(map[string]types.CurrencyPair, len(state.Pairs))
+			for _, pair := range state.Pairs {
+				pairs[pair.String()] = pair
+			}
+
+			for symbol, tickers := range state.Tickers {
+				pair, found := pairs[symbol]
+				if !found {
+					continue
+				}
+
+				reference, ok, enough := inverseReference(tickers)
+				if !enough {
+					reference, ok = lookup(pair)
+				}
+
+				inverted := []InvertedPrice{}
+				detected := map[provider.Name]bool{}
+				for providerName, ticker := range tickers {
+					key := providerName.String() + "/" + symbol
+
+					isInverse, found := checked[key]
+					if !found {
+						if !ok || !ticker.Price.IsPositive() {
+							continue
+						}
+						isInverse = isInverted(ticker.Price, reference)
+						checked[key] = isInverse
+						detected[providerName] = isInverse
+					}
+
+					if isInverse {
+						inverted = append(inverted, InvertedPrice{
+							Provider:  providerName,
+							Symbol:    symbol,
+							Price:     ticker.Price,
+							Reference: reference,
+						})
+					}
+				}
+
+				// all prices are compared before any of them is removed
+				for _, price := range inverted {
+					state.Exclude(pair.Base, price.Provider, symbol, price.Price, "price inverted")
+					delete(tickers, price.Provider)
+					if detected[price.Provider] {
+						report(price)
+					}
+				}
+			}
+
+			return next(state)
+		}
+	}
+}
+
+// inverseReference returns the median of the prices, if a strict majority
+// of the prices is within inverseMinRatio of it. It returns false for
+// enough, if there are too few prices to tell which of them are inverted.
+func inverseReference(tickers map[provider.Name]types.TickerPrice) (sdk.Dec, bool, bool) {
+	prices := make([]sdk.Dec, 0, len(tickers))
+	for _, ticker := range tickers {
+		if ticker.Price.IsPositive() {
+			prices = append(prices, ticker.Price)
+		}
+	}
+
+	if len(prices) <= minInverseReferences {
+		return sdk.Dec{}, false, false
+	}
+
+	reference := medianDec(prices)
+
+	limit := math.Log(inverseMinRatio)
+	near := 0
+	for _, price := range prices {
+		if math.Abs(math.Log(price.Quo(reference).MustFloat64())) < limit {
+			near++
+		}
+	}
+
+	return reference, near*2 > len(prices), true
+}
+
+// priceReference returns the price of the pair derived from the last
+// computed USD prices.
+func (o *Oracle) priceReference(pair types.CurrencyPair) (sdk.Dec, bool) {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	base, found := o.prices[pair.Base]
+	if !found || !base.IsPositive() {
+		return sdk.Dec{}, false
+	}
+
+	if pair.Quote == config.DenomUSD {
+		return base, true
+	}
+
+	quote, found := o.prices[pair.Quote]
+	if !found || !quote.IsPositive() {
+		return sdk.Dec{}, false
+	}
+
+	return base.Quo(quote), true
+}
+
+// reportInvertedPrice logs and counts a rejected inverted price.
+func (o *Oracle) reportInvertedPrice(price InvertedPrice) {
+	o.logger.Error().
+		Str("provider", price.Provider.String()).
+		Str("symbol", price.Symbol).
+		Str("price", price.Price.String()).
+		Str("reference", price.Reference.String()).
+		Msg("price is inverted, check the pair orientation")
+
+	telemetry.IncrCounterWithLabels(
+		[]string{"provider", "inverted"},
+		1,
+		[]metrics.Label{
+			{Name: "provider", Value: price.Provider.String()},
+			{Name: "symbol", Value: price.Symbol},
+		},
+	)
+}
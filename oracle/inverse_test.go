@@ -0,0 +1,141 @@
+package oracle
+
+import (
+	"testing"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsInverted(t *testing.T) {
+	dec := sdk.MustNewDecFromStr
+
+	require.True(t, isInverted(dec("0.1"), dec("10")))
+	require.True(t, isInverted(dec("0.09"), dec("10.5")))
+	require.False(t, isInverted(dec("10.2"), dec("10")))
+	// far off, but not the inverse
+	require.False(t, isInverted(dec("1000"), dec("10")))
+	// pairs around 1 can't be told apart from their inverse
+	require.False(t, isInverted(dec("0.98"), dec("1.02")))
+}
+
+func TestInverseDetection(t *testing.T) {
+	pair := types.CurrencyPair{Base: "KUJI", Quote: "ATOM"}
+	ticker := func(price string) types.TickerPrice {
+		return types.TickerPrice{Price: sdk.MustNewDecFromStr(price), Volume: sdk.OneDec()}
+	}
+
+	noReference := func(types.CurrencyPair) (sdk.Dec, bool) {
+		return sdk.Dec{}, false
+	}
+
+	run := func(
+		providerPrices provider.AggregatedProviderPrices,
+		lookup func(types.CurrencyPair) (sdk.Dec, bool),
+	) (*PipelineState, []InvertedPrice) {
+		providerPairs := map[provider.Name][]types.CurrencyPair{}
+		for providerName := range providerPrices {
+			providerPairs[providerName] = []types.CurrencyPair{pair}
+		}
+
+		reported := []InvertedPrice{}
+		pipeline := NewPipeline()
+		require.NoError(t, pipeline.Use(StageFilter, InverseDetection(
+			lookup, func(price InvertedPrice) { reported = append(reported, price) },
+		)))
+
+		state, err := runPipeline(
+			pipeline, zerolog.Nop(), providerPrices, providerPairs,
+			nil, map[string]int{"KUJI": 1, "ATOM": 1}, nil,
+		)
+		require.NoError(t, err)
+		return state, reported
+	}
+
+	// the other providers are the reference
+	state, reported := run(provider.AggregatedProviderPrices{
+		provider.ProviderFinV2:     {"KUJIATOM": ticker("9.8")},
+		provider.ProviderOsmosisV2: {"KUJIATOM": ticker("0.102")},
+		provider.ProviderGate:      {"KUJIATOM": ticker("0.101")},
+	}, noReference)
+	require.Len(t, reported, 1)
+	require.Equal(t, provider.ProviderFinV2, reported[0].Provider)
+	require.NotContains(t, state.Tickers["KUJIATOM"], provider.ProviderFinV2)
+	require.Len(t, state.Excluded["KUJI"], 1)
+
+	// a single other provider isn't enough to tell which one is inverted
+	_, reported = run(provider.AggregatedProviderPrices{
+		provider.ProviderFinV2:     {"KUJIATOM": ticker("9.8")},
+		provider.ProviderOsmosisV2: {"KUJIATOM": ticker("0.102")},
+	}, noReference)
+	require.Empty(t, reported)
+
+	// the last computed prices are the reference of a single provider
+	_, reported = run(provider.AggregatedProviderPrices{
+		provider.ProviderFinV2: {"KUJIATOM": ticker("9.8")},
+	}, func(types.CurrencyPair) (sdk.Dec, bool) {
+		return sdk.MustNewDecFromStr("0.1"), true
+	})
+	require.Len(t, reported, 1)
+}
+
+func TestInverseDetectionChecksOnce(t *testing.T) {
+	pair := types.CurrencyPair{Base: "KUJI", Quote: "ATOM"}
+	ticker := func(price string) types.TickerPrice {
+		return types.TickerPrice{Price: sdk.MustNewDecFromStr(price), Volume: sdk.OneDec()}
+	}
+
+	reported := []InvertedPrice{}
+	pipeline := NewPipeline()
+	require.NoError(t, pipeline.Use(StageFilter, InverseDetection(
+		func(types.CurrencyPair) (sdk.Dec, bool) { return sdk.Dec{}, false },
+		func(price InvertedPrice) { reported = append(reported, price) },
+	)))
+
+	run := func(providerPrices provider.AggregatedProviderPrices) *PipelineState {
+		providerPairs := map[provider.Name][]types.CurrencyPair{}
+		for providerName := range providerPrices {
+			providerPairs[providerName] = []types.CurrencyPair{pair}
+		}
+
+		state, err := runPipeline(
+			pipeline, zerolog.Nop(), providerPrices, providerPairs,
+			nil, map[string]int{"KUJI": 1, "ATOM": 1}, nil,
+		)
+		require.NoError(t, err)
+		return state
+	}
+
+	// without a strict majority, it can't be told which prices are inverted
+	run(provider.AggregatedProviderPrices{
+		provider.ProviderFinV2:     {"KUJIATOM": ticker("9.8")},
+		provider.ProviderKucoin:    {"KUJIATOM": ticker("9.9")},
+		provider.ProviderOsmosisV2: {"KUJIATOM": ticker("0.102")},
+		provider.ProviderGate:      {"KUJIATOM": ticker("0.101")},
+	})
+	require.Empty(t, reported)
+
+	run(provider.AggregatedProviderPrices{
+		provider.ProviderFinV2:     {"KUJIATOM": ticker("9.8")},
+		provider.ProviderOsmosisV2: {"KUJIATOM": ticker("0.102")},
+		provider.ProviderGate:      {"KUJIATOM": ticker("0.101")},
+	})
+	require.Len(t, reported, 1)
+	require.Equal(t, provider.ProviderFinV2, reported[0].Provider)
+
+	// the inverted symbol stays refused without being reported again, while
+	// the checked symbols aren't rejected anymore
+	state := run(provider.AggregatedProviderPrices{
+		provider.ProviderFinV2:     {"KUJIATOM": ticker("9.8")},
+		provider.ProviderOsmosisV2: {"KUJIATOM": ticker("9.7")},
+		provider.ProviderGate:      {"KUJIATOM": ticker("0.101")},
+	})
+	require.Len(t, reported, 1)
+	require.NotContains(t, state.Tickers["KUJIATOM"], provider.ProviderFinV2)
+	require.Contains(t, state.Tickers["KUJIATOM"], provider.ProviderOsmosisV2)
+	require.Contains(t, state.Tickers["KUJIATOM"], provider.ProviderGate)
+}
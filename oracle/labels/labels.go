@@ -0,0 +1,124 @@
+// Package labels restricts the label values of the exported metrics, so
+// large configurations don't blow up the cardinality of the metrics backend.
+package labels
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+)
+
+const (
+	// Other replaces label values, which are not allowed.
+	Other = "other"
+	// All replaces all provider label values in aggregate only mode.
+	All = "all"
+
+	// finalProvider is the provider name of the aggregated prices.
+	finalProvider = "_final"
+)
+
+type filter struct {
+	mtx           sync.RWMutex
+	providers     map[string]struct{}
+	pairs         map[string]struct{}
+	aggregateOnly bool
+}
+
+var labelFilter = &filter{}
+
+// Configure sets the allowed provider and pair label values. Empty lists
+// allow all values. In aggregate only mode, no per provider values are
+// exported, except for the aggregated prices.
+func Configure(providers, pairs []string, aggregateOnly bool) {
+	labelFilter.mtx.Lock()
+	defer labelFilter.mtx.Unlock()
+
+	labelFilter.providers = nil
+	if len(providers) > 0 {
+		labelFilter.providers = map[string]struct{}{}
+		for _, provider := range providers {
+			labelFilter.providers[strings.ToLower(provider)] = struct{}{}
+		}
+	}
+
+	labelFilter.pairs = nil
+	if len(pairs) > 0 {
+		labelFilter.pairs = map[string]struct{}{}
+		for _, pair := range pairs {
+			labelFilter.pairs[strings.ToUpper(pair)] = struct{}{}
+		}
+	}
+
+	labelFilter.aggregateOnly = aggregateOnly
+}
+
+// AllowedProvider returns true, if metrics of the provider are exported.
+// Derived names like `_binance` (USD rates) and `binance_twap` match the
+// provider they are derived from.
+func AllowedProvider(name string) bool {
+	if name == finalProvider {
+		return true
+	}
+
+	labelFilter.mtx.RLock()
+	defer labelFilter.mtx.RUnlock()
+
+	if labelFilter.aggregateOnly {
+		return false
+	}
+	if labelFilter.providers == nil {
+		return true
+	}
+
+	name = strings.TrimPrefix(name, "_")
+	name = strings.TrimSuffix(name, "_twap")
+	_, found := labelFilter.providers[strings.ToLower(name)]
+	return found
+}
+
+// AllowedPair returns true, if metrics of the pair or denom are exported.
+func AllowedPair(pair string) bool {
+	labelFilter.mtx.RLock()
+	defer labelFilter.mtx.RUnlock()
+
+	if labelFilter.pairs == nil {
+		return true
+	}
+	_, found := labelFilter.pairs[strings.ToUpper(pair)]
+	return found
+}
+
+// Allowed returns true, if both the provider and the pair are allowed.
+// Gauges should only be set, if their labels are allowed, as merging the
+// values of several series into one is meaningless.
+func Allowed(provider, pair string) bool {
+	return AllowedProvider(provider) && AllowedPair(pair)
+}
+
+// Provider returns the provider label for counters. Values, which are not
+// allowed, are merged into `other`, or `all` in aggregate only mode.
+func Provider(name string) metrics.Label {
+	if AllowedProvider(name) {
+		return telemetry.NewLabel("provider", name)
+	}
+
+	labelFilter.mtx.RLock()
+	defer labelFilter.mtx.RUnlock()
+
+	if labelFilter.aggregateOnly {
+		return telemetry.NewLabel("provider", All)
+	}
+	return telemetry.NewLabel("provider", Other)
+}
+
+// Pair returns a pair or denom label with the key for counters. Values,
+// which are not allowed, are merged into `other`.
+func Pair(key, pair string) metrics.Label {
+	if AllowedPair(pair) {
+		return telemetry.NewLabel(key, pair)
+	}
+	return telemetry.NewLabel(key, Other)
+}
@@ -0,0 +1,35 @@
+package labels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels(t *testing.T) {
+	defer Configure(nil, nil, false)
+
+	// everything is allowed by default
+	require.True(t, Allowed("binance", "ATOMUSDT"))
+
+	Configure([]string{"Binance"}, []string{"atomusdt", "ATOM"}, false)
+
+	require.True(t, Allowed("binance", "ATOMUSDT"))
+	require.True(t, Allowed("_binance", "ATOM"))
+	require.True(t, Allowed("binance_twap", "ATOM"))
+	require.True(t, Allowed("_final", "ATOM"))
+	require.False(t, Allowed("kraken", "ATOMUSDT"))
+	require.False(t, Allowed("binance", "KUJIUSDT"))
+
+	require.Equal(t, "binance", Provider("binance").Value)
+	require.Equal(t, Other, Provider("kraken").Value)
+	require.Equal(t, "ATOM", Pair("denom", "ATOM").Value)
+	require.Equal(t, Other, Pair("denom", "KUJI").Value)
+
+	Configure(nil, nil, true)
+
+	require.False(t, AllowedProvider("binance"))
+	require.True(t, Allowed("_final", "KUJI"))
+	require.Equal(t, All, Provider("binance").Value)
+	require.Equal(t, "_final", Provider("_final").Value)
+}
@@ -0,0 +1,19 @@
+package oracle
+
+// LeaderElection defines the election of the voting instance among
+// redundant feeders.
+type LeaderElection interface {
+	IsLeader() bool
+}
+
+// SetLeaderElection makes the oracle only broadcast (pre)votes while it is
+// the leader. As standby it keeps computing prices, so it can take over
+// immediately.
+func (o *Oracle) SetLeaderElection(election LeaderElection) {
+	o.leaderElection = election
+}
+
+// isStandby returns true if another instance is voting.
+func (o *Oracle) isStandby() bool {
+	return o.leaderElection != nil && !o.leaderElection.IsLeader()
+}
@@ -0,0 +1,100 @@
+package oracle
+
+import (
+	"context"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"price-feeder/oracle/provider"
+)
+
+// resourceCheckInterval defines how often the memory and goroutine usage is
+// checked against the configured limits.
+const resourceCheckInterval = 30 * time.Second
+
+// resourceLimits defines the soft limits checked by the watchdog.
+type resourceLimits struct {
+	memoryThreshold uint64
+	maxGoroutines   int
+}
+
+// SetResourceLimits sets the heap size in bytes, above which the caches are
+// trimmed, and the number of goroutines, above which a warning is logged.
+// Zero disables the check.
+func (o *Oracle) SetResourceLimits(memoryThreshold uint64, maxGoroutines int) {
+	o.limits = resourceLimits{
+		memoryThreshold: memoryThreshold,
+		maxGoroutines:   maxGoroutines,
+	}
+}
+
+// watchResources periodically exports the heap size and the number of
+// goroutines and checks them against the configured limits.
+func (o *Oracle) watchResources(ctx context.Context) {
+	if o.limits.memoryThreshold == 0 && o.limits.maxGoroutines == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(resourceCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			o.checkResources()
+		}
+	}
+}
+
+func (o *Oracle) checkResources() {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	goroutines := runtime.NumGoroutine()
+
+	telemetry.SetGauge(float32(stats.HeapAlloc), "runtime", "heap_bytes")
+	telemetry.SetGauge(float32(goroutines), "runtime", "goroutines")
+
+	if o.limits.maxGoroutines > 0 && goroutines > o.limits.maxGoroutines {
+		o.logger.Warn().
+			Int("goroutines", goroutines).
+			Int("max_goroutines", o.limits.maxGoroutines).
+			Msg("too many goroutines")
+		telemetry.IncrCounter(1, "limits", "goroutines_exceeded")
+	}
+
+	if o.limits.memoryThreshold == 0 || stats.HeapAlloc <= o.limits.memoryThreshold {
+		return
+	}
+
+	o.logger.Warn().
+		Uint64("heap_bytes", stats.HeapAlloc).
+		Uint64("threshold_bytes", o.limits.memoryThreshold).
+		Msg("memory threshold exceeded, trimming caches")
+	telemetry.IncrCounter(1, "limits", "memory_exceeded")
+
+	o.trimCaches()
+}
+
+// trimCaches drops the cached data of all providers, that support it, and
+// returns the freed memory to the operating system.
+func (o *Oracle) trimCaches() {
+	o.mtx.RLock()
+	priceProviders := make([]provider.Provider, 0, len(o.priceProviders))
+	for _, priceProvider := range o.priceProviders {
+		priceProviders = append(priceProviders, priceProvider)
+	}
+	o.mtx.RUnlock()
+
+	for _, priceProvider := range priceProviders {
+		if trimmer, ok := priceProvider.(provider.CacheTrimmer); ok {
+			trimmer.TrimCaches()
+		}
+	}
+
+	debug.FreeOSMemory()
+}
@@ -0,0 +1,85 @@
+package oracle
+
+import (
+	"fmt"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+)
+
+const (
+	// LiquidityWeightingVolume weights all tickers by their traded volume.
+	LiquidityWeightingVolume = "volume"
+	// LiquidityWeightingLiquidity weights tickers reporting pool liquidity
+	// by that liquidity instead of their traded volume.
+	LiquidityWeightingLiquidity = "liquidity"
+	// LiquidityWeightingCombined weights tickers reporting pool liquidity by
+	// the lower of their traded volume and liquidity, so neither wash
+	// trading nor a large idle pool dominates the VWAP.
+	LiquidityWeightingCombined = "combined"
+)
+
+// LiquidityWeighting returns a middleware for StageNormalize, which replaces
+// the volume of tickers reporting pool liquidity according to the mode.
+// Configured provider weights are applied afterwards and take precedence.
+func LiquidityWeighting(mode string) (Middleware, error) {
+	switch mode {
+	case "", LiquidityWeightingVolume:
+		return func(next StageFunc) StageFunc { return next }, nil
+	case LiquidityWeightingLiquidity, LiquidityWeightingCombined:
+	default:
+		return nil, fmt.Errorf("unknown liquidity weighting: %s", mode)
+	}
+
+	return func(next StageFunc) StageFunc {
+		return func(state *PipelineState) error {
+			for symbol, tickers := range state.Tickers {
+				state.Tickers[symbol] = weightByLiquidity(tickers, mode)
+			}
+			return next(state)
+		}
+	}, nil
+}
+
+// SetLiquidityWeighting registers the liquidity weighting of the mode in the
+// pipeline of the oracle.
+func (o *Oracle) SetLiquidityWeighting(mode string) error {
+	middleware, err := LiquidityWeighting(mode)
+	if err != nil {
+		return err
+	}
+	return o.Pipeline().Use(StageNormalize, middleware)
+}
+
+// weightByLiquidity sets the volume of all tickers with pool liquidity to
+// the weight of the mode. The liquidity is converted to base terms, so it is
+// comparable to the volume of the other tickers of the same symbol.
+func weightByLiquidity(
+	tickers map[provider.Name]types.TickerPrice,
+	mode string,
+) map[provider.Name]types.TickerPrice {
+	for providerName, ticker := range tickers {
+		if ticker.Liquidity.IsNil() || !ticker.Liquidity.IsPositive() ||
+			ticker.Price.IsNil() || !ticker.Price.IsPositive() {
+			continue
+		}
+
+		liquidity := ticker.Liquidity.Quo(ticker.Price)
+
+		switch mode {
+		case LiquidityWeightingLiquidity:
+			ticker.Volume = liquidity
+		case LiquidityWeightingCombined:
+			// AMM providers without volume data only report liquidity
+			if ticker.Volume.IsNil() || !ticker.Volume.IsPositive() {
+				ticker.Volume = liquidity
+			} else if liquidity.LT(ticker.Volume) {
+				ticker.Volume = liquidity
+			}
+		}
+
+		tickers[providerName] = ticker
+	}
+
+	return tickers
+}
@@ -0,0 +1,65 @@
+package oracle
+
+import (
+	"testing"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiquidityWeighting(t *testing.T) {
+	pair := types.CurrencyPair{Base: "KUJI", Quote: "USD"}
+	providerPairs := map[provider.Name][]types.CurrencyPair{
+		provider.ProviderBinance:       {pair},
+		provider.ProviderWhitewhaleInj: {pair},
+	}
+
+	testCases := map[string]sdk.Dec{
+		// 1000 KUJI traded on binance, 9000 KUJI on the pool
+		LiquidityWeightingVolume: sdk.MustNewDecFromStr("1.9"),
+		// 3000 KUJI of liquidity in the pool
+		LiquidityWeightingLiquidity: sdk.MustNewDecFromStr("1.75"),
+		// pool volume is capped at its liquidity
+		LiquidityWeightingCombined: sdk.MustNewDecFromStr("1.75"),
+	}
+
+	for mode, expected := range testCases {
+		t.Run(mode, func(t *testing.T) {
+			providerPrices := provider.AggregatedProviderPrices{
+				provider.ProviderBinance: {
+					"KUJIUSD": {
+						Price:  sdk.NewDec(1),
+						Volume: sdk.NewDec(1000),
+					},
+				},
+				provider.ProviderWhitewhaleInj: {
+					"KUJIUSD": {
+						Price:     sdk.NewDec(2),
+						Volume:    sdk.NewDec(9000),
+						Liquidity: sdk.NewDec(6000),
+					},
+				},
+			}
+
+			middleware, err := LiquidityWeighting(mode)
+			require.NoError(t, err)
+
+			pipeline := NewPipeline()
+			require.NoError(t, pipeline.Use(StageNormalize, middleware))
+
+			state, err := runPipeline(
+				pipeline, zerolog.Nop(), providerPrices, providerPairs,
+				nil, map[string]int{"KUJI": 1}, nil,
+			)
+			require.NoError(t, err)
+			require.Equal(t, expected, state.Prices["KUJI"])
+		})
+	}
+
+	_, err := LiquidityWeighting("unknown")
+	require.Error(t, err)
+}
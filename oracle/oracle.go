@@ -7,12 +7,13 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math"
-	"net/http"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/armon/go-metrics"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
@@ -22,8 +23,12 @@ import (
 	"price-feeder/oracle/client"
 	"price-feeder/oracle/derivative"
 	"price-feeder/oracle/history"
+	"price-feeder/oracle/labels"
 	"price-feeder/oracle/provider"
+	"price-feeder/oracle/provider/volume"
 	"price-feeder/oracle/types"
+	"price-feeder/oracle/votelog"
+	"price-feeder/pkg/events"
 	pfsync "price-feeder/pkg/sync"
 
 	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
@@ -31,12 +36,15 @@ import (
 	"github.com/cosmos/cosmos-sdk/telemetry"
 )
 
-// We define tickerSleep as the minimum timeout between each oracle loop. We
+// We define tickerSleep as the default timeout between each oracle loop. We
 // define this value empirically based on enough time to collect exchange rates,
 // and broadcast pre-vote and vote transactions such that they're committed in
-// at least one block during each voting period.
+// at least one block during each voting period. It can be changed with the
+// tick_interval setting.
 const (
 	tickerSleep = 1000 * time.Millisecond
+
+	volumePruneInterval = 1 * time.Hour
 )
 
 type ProviderWeight struct {
@@ -47,9 +55,13 @@ type ProviderWeight struct {
 // PreviousPrevote defines a structure for defining the previous prevote
 // submitted on-chain.
 type PreviousPrevote struct {
-	ExchangeRates     string
-	Salt              string
-	SubmitBlockHeight int64
+	ExchangeRates     string `json:"exchange_rates"`
+	Salt              string `json:"salt"`
+	SubmitBlockHeight int64  `json:"submit_block_height"`
+
+	// prices are the USD prices of the exchange rates, they are not
+	// persisted.
+	prices sdk.DecCoins
 }
 
 func NewPreviousPrevote() *PreviousPrevote {
@@ -66,6 +78,9 @@ func NewPreviousPrevote() *PreviousPrevote {
 type Oracle struct {
 	logger zerolog.Logger
 	closer *pfsync.Closer
+	// rootLogger is the logger passed to New, the components configured
+	// later add their own module to it.
+	rootLogger zerolog.Logger
 
 	providerTimeout      time.Duration
 	providerPairs        map[provider.Name][]types.CurrencyPair
@@ -85,12 +100,50 @@ type Oracle struct {
 	decimals             map[string]map[string]int
 	periods              map[string]map[string]int
 	volumeDatabase       *sql.DB
+	comparisonProviders  []provider.Name
+	comparisonThreshold  sdk.Dec
+	comparisonPairs      []types.CurrencyPair
+	comparisonSources    map[provider.Name]provider.Provider
+	blacklist            *Blacklist
+	voteLog              *votelog.VoteLog
+	priceExponents       map[string]int
+	ratesFormat          RatesFormat
+	chainProfile         ChainProfile
+	timing               timing
+	blockTimer           blockTimer
+	watchdog             heightWatchdog
+	lastVotedRates       string
+	voteScheduler        voteScheduler
+	pipeline             *Pipeline
+	latencies            providerLatencies
+	batchVotes           bool
+	stateFile            string
+	whitelist            config.Whitelist
+	unconfiguredDenoms   map[string]struct{}
+	autoThresholds       *AutoThresholds
+	clock                *clockMonitor
+	healthcheckMtx       sync.Mutex
+	lastHealthcheckPing  time.Time
+	events               *events.Bus
+	priceSource          PriceSource
+	leaderElection       LeaderElection
+	paramsOutdated       atomic.Bool
+	warmup               *warmup
+	tickerRetention      time.Duration
+	limits               resourceLimits
 
 	mtx             sync.RWMutex
 	lastPriceSyncTS time.Time
 	prices          map[string]sdk.Dec
+	lastPricesTS    time.Time
+	conversions     types.Conversions
+	explanation     types.Explanation
+	volumes         types.Volumes
+	uptime          types.Uptime
+	lastVolumesTS   time.Time
 	paramCache      ParamCache
-	healthchecks    map[string]http.Client
+	healthchecks    []*healthcheck
+	votedPrices     map[string]sdk.Dec
 }
 
 func New(
@@ -121,23 +174,17 @@ func New(
 			})
 		}
 	}
-	healthchecks := make(map[string]http.Client, len(healthchecksConfig))
-	for _, healthcheck := range healthchecksConfig {
-		timeout, err := time.ParseDuration(healthcheck.Timeout)
-		if err != nil {
-			logger.Warn().
-				Str("timeout", healthcheck.Timeout).
-				Msg("failed to parse healthcheck timeout, skipping configuration")
-		} else {
-			healthchecks[healthcheck.URL] = http.Client{
-				Timeout: timeout,
-			}
-		}
+	healthchecks, err := newHealthchecks(healthchecksConfig)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("failed to parse healthcheck timeout, skipping configuration")
 	}
 
-	return &Oracle{
+	o := &Oracle{
 		logger:               logger.With().Str("module", "oracle").Logger(),
 		closer:               pfsync.NewCloser(),
+		rootLogger:           logger,
 		oracleClient:         oc,
 		providerPairs:        providerPairs,
 		priceProviders:       make(map[provider.Name]provider.Provider),
@@ -157,15 +204,48 @@ func New(
 		decimals:             decimals,
 		periods:              periods,
 		volumeDatabase:       volumeDatabase,
+		comparisonThreshold:  defaultComparisonThreshold,
+		comparisonPairs:      comparisonPairsFromProviderPairs(providerPairs),
+		comparisonSources:    make(map[provider.Name]provider.Provider),
+		blacklist:            NewBlacklist(nil, sdk.ZeroDec(), 0, 0),
+		ratesFormat:          DefaultRatesFormat,
+		chainProfile:         kujiraProfile{},
+		voteScheduler:        newVoteScheduler(),
+		pipeline:             NewPipeline(),
+		events:               events.NewBus(),
 	}
+	o.SetTiming(config.Timing{})
+	o.subscribeEvents()
+
+	// the default pipeline is valid, so registering can't fail
+	_ = o.Pipeline().Use(
+		StageFilter, InverseDetection(o.priceReference, o.reportInvertedPrice),
+	)
+
+	return o
 }
 
 // Start starts the oracle process in a blocking fashion.
 func (o *Oracle) Start(ctx context.Context) error {
+	go o.pruneVolumes(ctx)
+	go o.pruneTickerHistory(ctx)
+	go o.checkListings(ctx)
+	go o.retryUnlistedPairs(ctx)
+	go o.watchResources(ctx)
+	go o.updateUptime(ctx)
+	go o.clock.runNtp(ctx)
+
+	o.initComparisonSources(ctx)
+	o.loadState()
+	o.publish(events.TopicStart, "", nil)
+
 	for {
 		select {
 		case <-ctx.Done():
+			o.saveState()
+			o.publish(events.TopicStop, "price feeder stopped", nil)
 			o.closer.Close()
+			return nil
 
 		default:
 			o.logger.Debug().Msg("starting oracle tick")
@@ -175,14 +255,111 @@ func (o *Oracle) Start(ctx context.Context) error {
 			if err := o.tick(ctx); err != nil {
 				telemetry.IncrCounter(1, "failure", "tick")
 				o.logger.Err(err).Msg("oracle tick failed")
+				o.publish(events.TopicTickFailed, "oracle tick failed: "+err.Error(), err)
 			}
 
 			o.lastPriceSyncTS = time.Now()
 
 			telemetry.MeasureSince(startTime, "runtime", "tick")
 			telemetry.IncrCounter(1, "new", "tick")
+			provider.FlushPriceMetrics(time.Now())
+
+			time.Sleep(o.tickInterval())
+		}
+	}
+}
+
+// pruneVolumes periodically removes volumes of providers and symbols, that
+// are not configured anymore, from the shared volume database and reports
+// the remaining rows per provider.
+func (o *Oracle) pruneVolumes(ctx context.Context) {
+	if o.volumeDatabase == nil {
+		return
+	}
+
+	ticker := time.NewTicker(volumePruneInterval)
+	defer ticker.Stop()
+
+	for {
+		// pairs are subscribed at runtime, so the set is rebuilt every time
+		counts, deleted, err := volume.Prune(o.volumeDatabase, o.volumeSymbols())
+		if err != nil {
+			o.logger.Err(err).Msg("failed pruning volumes")
+		} else {
+			for providerName, count := range counts {
+				if labels.AllowedProvider(providerName) {
+					telemetry.SetGaugeWithLabels(
+						[]string{"volume", "rows"},
+						float32(count),
+						[]metrics.Label{telemetry.NewLabel("provider", providerName)},
+					)
+				}
+				o.logger.Debug().
+					Str("provider", providerName).
+					Int64("rows", count).
+					Msg("volume rows")
+			}
+			if deleted > 0 {
+				o.logger.Info().
+					Int64("rows", deleted).
+					Msg("pruned volumes of unconfigured providers")
+			}
+		}
 
-			time.Sleep(tickerSleep)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// volumeSymbols returns the symbols with volume history per provider, both
+// orientations of all provider and comparison pairs.
+func (o *Oracle) volumeSymbols() map[string]map[string]struct{} {
+	configured := map[string]map[string]struct{}{}
+	addPairs := func(providerName provider.Name, pairs []types.CurrencyPair) {
+		symbols, found := configured[providerName.String()]
+		if !found {
+			symbols = map[string]struct{}{}
+			configured[providerName.String()] = symbols
+		}
+		for _, pair := range pairs {
+			symbols[pair.Base+pair.Quote] = struct{}{}
+			symbols[pair.Quote+pair.Base] = struct{}{}
+		}
+	}
+
+	for providerName, pairs := range o.getProviderPairs() {
+		addPairs(providerName, pairs)
+	}
+	for _, providerName := range o.comparisonProviders {
+		addPairs(providerName, o.comparisonPairs)
+	}
+
+	return configured
+}
+
+// pruneTickerHistory periodically removes the stored tickers of symbols,
+// that are not configured as derivatives anymore, from the history.
+func (o *Oracle) pruneTickerHistory(ctx context.Context) {
+	ticker := time.NewTicker(volumePruneInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := o.history.PruneTickerPrices(o.derivativeSymbols)
+		if err != nil {
+			o.logger.Err(err).Msg("failed pruning ticker history")
+		} else if deleted > 0 {
+			o.logger.Info().
+				Int64("rows", deleted).
+				Msg("pruned ticker history of unconfigured symbols")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
 		}
 	}
 }
@@ -202,6 +379,14 @@ func (o *Oracle) GetLastPriceSyncTimestamp() time.Time {
 	return o.lastPriceSyncTS
 }
 
+// GetLastPricesTimestamp returns the time the current prices were computed.
+func (o *Oracle) GetLastPricesTimestamp() time.Time {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	return o.lastPricesTS
+}
+
 // GetPrices returns a copy of the current prices fetched from the oracle's
 // set of exchange rate providers.
 func (o *Oracle) GetPrices() sdk.DecCoins {
@@ -217,16 +402,91 @@ func (o *Oracle) GetPrices() sdk.DecCoins {
 	return prices
 }
 
+// GetConversions returns the intermediate USD rates per provider and denom
+// used to calculate the current prices.
+func (o *Oracle) GetConversions() types.Conversions {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	return o.conversions
+}
+
+// GetBlacklist returns all currently blacklisted provider pairs.
+func (o *Oracle) GetBlacklist() []types.BlacklistEntry {
+	return o.blacklist.Entries(time.Now())
+}
+
+// getProviderPairs returns a copy of the pairs of all providers. Pairs are
+// added at runtime by the tick, so other goroutines have to use the copy.
+func (o *Oracle) getProviderPairs() map[provider.Name][]types.CurrencyPair {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	providerPairs := make(map[provider.Name][]types.CurrencyPair, len(o.providerPairs))
+	for providerName, pairs := range o.providerPairs {
+		providerPairs[providerName] = append([]types.CurrencyPair{}, pairs...)
+	}
+	return providerPairs
+}
+
+// GetProviderStatus returns the health and freshness of all running price
+// providers, sorted by name.
+func (o *Oracle) GetProviderStatus() []types.ProviderStatus {
+	o.mtx.RLock()
+	priceProviders := make([]provider.Provider, 0, len(o.priceProviders))
+	for _, priceProvider := range o.priceProviders {
+		priceProviders = append(priceProviders, priceProvider)
+	}
+	o.mtx.RUnlock()
+
+	status := make([]types.ProviderStatus, 0, len(priceProviders))
+	for _, priceProvider := range priceProviders {
+		status = append(status, types.ProviderStatus{
+			Name:          priceProvider.GetName().String(),
+			Healthy:       priceProvider.Healthy(),
+			LastUpdate:    priceProvider.LastUpdate(),
+			ClockOffsetMs: priceProvider.ClockOffset().Milliseconds(),
+		})
+	}
+
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Name < status[j].Name
+	})
+
+	return status
+}
+
 // SetPrices retrieves all the prices and candles from our set of providers as
 // determined in the config. If candles are available, uses TVWAP in order
 // to determine prices. If candles are not available, uses the most recent prices
 // with VWAP. Warns the the user of any missing prices, and filters out any faulty
 // providers which do not report prices or candles within 2𝜎 of the others.
 func (o *Oracle) SetPrices(ctx context.Context) error {
+	return o.setPrices(ctx, time.Time{})
+}
+
+// setPrices works like SetPrices, but stops waiting for providers at the
+// deadline, if set, and proceeds with the prices available by then.
+// Providers, that usually respond too late, are skipped.
+func (o *Oracle) setPrices(ctx context.Context, deadline time.Time) error {
+	if o.priceSource != nil {
+		return o.setSourcePrices(ctx, deadline)
+	}
+
+	collectCtx := ctx
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		collectCtx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
 	g := new(errgroup.Group)
 	mtx := new(sync.Mutex)
 	requiredRates := make(map[string]struct{})
 	providerPrices := provider.AggregatedProviderPrices{}
+	providerVolumes := map[string]map[string]sdk.Dec{}
+	collectedTickers := map[string]map[string]types.TickerPrice{}
+	skipped := map[string][]types.ExcludedRate{}
 
 	for providerName, currencyPairs := range o.providerPairs {
 		providerName := providerName
@@ -255,7 +515,9 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 			}
 			priceProvider = newProvider
 
+			o.mtx.Lock()
 			o.priceProviders[providerName] = priceProvider
+			o.mtx.Unlock()
 			continue
 		}
 
@@ -267,6 +529,30 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 		}
 
 		g.Go(func() error {
+			skip := func(pair types.CurrencyPair, reason string) {
+				skipped[pair.Base] = append(skipped[pair.Base], types.ExcludedRate{
+					Provider: providerName.String(),
+					Symbol:   pair.String(),
+					Reason:   reason,
+				})
+			}
+			skipAll := func(reason string) {
+				mtx.Lock()
+				defer mtx.Unlock()
+				for _, pair := range currencyPairs {
+					skip(pair, reason)
+				}
+			}
+
+			if !deadline.IsZero() && o.latencies.skip(providerName, time.Until(deadline)) {
+				o.logger.Debug().
+					Str("provider", providerName.String()).
+					Msg("skipping slow provider before vote deadline")
+				skipAll("too slow for vote deadline")
+				return nil
+			}
+
+			start := time.Now()
 			prices := make(map[string]types.TickerPrice, 0)
 			ch := make(chan struct{})
 			errCh := make(chan error, 1)
@@ -283,28 +569,63 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 
 			select {
 			case <-ch:
-				break
+				o.latencies.observe(providerName, time.Since(start))
 			case err := <-errCh:
+				skipAll(err.Error())
+				o.publish(events.TopicProviderFailed, err.Error(), providerName)
 				return err
 			case <-time.After(o.providerTimeout):
+				o.latencies.observe(providerName, time.Since(start))
 				telemetry.IncrCounter(1, "failure", "provider", "type", "timeout")
+				skipAll("provider timed out")
+				o.publish(events.TopicProviderFailed, "provider timed out", providerName)
 				return fmt.Errorf("provider timed out: %s", providerName)
+			case <-collectCtx.Done():
+				o.latencies.observe(providerName, time.Since(start))
+				telemetry.IncrCounter(1, "failure", "provider", "type", "deadline")
+				skipAll("vote deadline reached")
+				return fmt.Errorf("provider missed vote deadline: %s", providerName)
 			}
 
 			// flatten and collect prices based on the base currency per provider
 			//
 			// e.g.: {ProviderKraken: {"ATOM": <price, volume>, ...}}
+			// providers polling candles are priced with their TVWAP
+			candles, err := priceProvider.GetCandlePrices(currencyPairs...)
+			if err == nil && len(candles) > 0 {
+				prices = applyCandles(prices, candles, time.Now())
+			}
+
 			mtx.Lock()
 			defer mtx.Unlock()
 
 			filteredPairs := []types.CurrencyPair{}
 			for _, pair := range currencyPairs {
+				if o.blacklist.IsBlacklisted(providerName, pair.String(), time.Now()) {
+					o.logger.Debug().
+						Str("pair", pair.String()).
+						Str("provider", providerName.String()).
+						Msg("skipping blacklisted pair")
+					skip(pair, skipReasonBlacklisted)
+					continue
+				}
+
 				ticker, ok := prices[pair.String()]
-				if (!ok || ticker == types.TickerPrice{}) {
+				found := ok && ticker != types.TickerPrice{}
+				if o.observeTicker(providerName, pair, found) {
+					skip(pair, skipReasonBlacklisted)
+					continue
+				}
+				if !found {
+					if stale, ok := priceProvider.(provider.StaleTickerProvider); ok && stale.IsStale(pair.String()) {
+						skip(pair, skipReasonStale)
+						continue
+					}
 					o.logger.Warn().
 						Str("pair", pair.String()).
 						Str("provider", providerName.String()).
 						Msg("no ticker price found")
+					skip(pair, "no ticker price found")
 				} else {
 					filteredPairs = append(filteredPairs, pair)
 				}
@@ -312,6 +633,20 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 
 			for _, pair := range filteredPairs {
 				ticker := prices[pair.String()]
+				if !provider.PersistsVolumes(providerName) && !ticker.Volume.IsNil() {
+					_, ok := providerVolumes[providerName.String()]
+					if !ok {
+						providerVolumes[providerName.String()] = map[string]sdk.Dec{}
+					}
+					providerVolumes[providerName.String()][pair.String()] = ticker.Volume
+				}
+				if o.tickerRetention > 0 {
+					_, ok := collectedTickers[providerName.String()]
+					if !ok {
+						collectedTickers[providerName.String()] = map[string]types.TickerPrice{}
+					}
+					collectedTickers[providerName.String()][pair.String()] = ticker
+				}
 				_, isDerivative := o.derivativeSymbols[pair.String()]
 				if isDerivative {
 					err := o.history.AddTickerPrice(pair, providerName.String(), ticker)
@@ -334,6 +669,9 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 		o.logger.Debug().Err(err).Msg("failed to get ticker prices from provider")
 	}
 
+	o.snapshotVolumes(providerVolumes, time.Now())
+	o.persistTickers(collectedTickers, time.Now())
+
 	for name, pairs := range o.derivativePairs {
 		for _, pair := range pairs {
 			symbol := pair.String()
@@ -361,17 +699,29 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 		}
 	}
 
-	computedPrices, err := GetComputedPrices(
+	o.autoThresholds.update(&o.history, time.Now())
+
+	state, err := runPipeline(
+		o.Pipeline(),
 		o.logger,
 		providerPrices,
 		o.providerPairs,
-		o.deviations,
+		o.autoThresholds.apply(o.deviations),
 		o.providerMinOverrides,
 		o.providerWeights,
 	)
 	if err != nil {
 		return err
 	}
+	computedPrices := state.Prices
+
+	priced := 0
+	for base := range requiredRates {
+		if _, ok := computedPrices[base]; ok {
+			priced++
+		}
+	}
+	o.warmup.observe(priced, len(requiredRates))
 
 	if len(computedPrices) != len(requiredRates) {
 		missingPrices := []string{}
@@ -385,9 +735,67 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 		o.logger.Error().Msg(
 			"unable to get prices for: " + strings.Join(missingPrices, ", "),
 		)
+		for _, diagnosis := range diagnoseMissingPrices(
+			missingPrices, o.providerPairs, state, skipped,
+		) {
+			diagnosis.log(o.logger)
+		}
+		o.publish(
+			events.TopicQuorumLost,
+			"provider quorum lost for: "+strings.Join(missingPrices, ", "),
+			missingPrices,
+		)
 	}
 
+	o.comparePrices(computedPrices)
+
+	blacklisted := o.blacklist.Update(
+		providerPrices,
+		o.providerPairs,
+		computedPrices,
+		time.Now(),
+	)
+	for _, entry := range blacklisted {
+		o.logger.Warn().
+			Str("provider", entry.Provider).
+			Str("pair", entry.Symbol).
+			Time("until", *entry.Until).
+			Msg("blacklisted deviating pair")
+		o.publish(
+			events.TopicProviderQuarantined,
+			"blacklisted deviating pair "+entry.Symbol+" of "+entry.Provider,
+			entry,
+		)
+	}
+
+	o.mtx.Lock()
 	o.prices = computedPrices
+	o.lastPricesTS = time.Now()
+	o.conversions = types.Conversions{
+		Time:  time.Now(),
+		Rates: state.Conversions,
+	}
+	o.explanation = explainPrices(state, skipped, time.Now())
+	o.volumes = types.Volumes{
+		Time:    time.Now(),
+		Volumes: computeVolumes(providerPrices, o.providerPairs, computedPrices),
+	}
+	o.mtx.Unlock()
+
+	o.publish(events.TopicPricesUpdated, "", computedPrices)
+
+	if err := o.history.AddComputedPrices(computedPrices, time.Now()); err != nil {
+		o.logger.Warn().Err(err).Msg("failed to add computed prices to history")
+	}
+
+	if err := o.history.AddDispersions(priceDispersions(state), time.Now()); err != nil {
+		o.logger.Warn().Err(err).Msg("failed to add price dispersion to history")
+	}
+
+	contributions := providerContributions(o.providerPairs, state, skipped)
+	if err := o.history.AddContributions(contributions, time.Now()); err != nil {
+		o.logger.Warn().Err(err).Msg("failed to add provider contributions to history")
+	}
 
 	return nil
 }
@@ -422,24 +830,35 @@ func GetComputedPrices(
 // GetParamCache returns the last updated parameters of the x/oracle module
 // if the current ParamCache is outdated, we will query it again.
 func (o *Oracle) GetParamCache(ctx context.Context, currentBlockHeigh int64) (oracletypes.Params, error) {
-	if !o.paramCache.IsOutdated(currentBlockHeigh) {
+	refresh := o.paramsOutdated.Swap(false)
+	if !refresh && !o.paramCache.IsOutdated(currentBlockHeigh) {
 		return *o.paramCache.params, nil
 	}
 
 	params, err := o.GetParams(ctx)
 	if err != nil {
+		if refresh {
+			o.paramsOutdated.Store(true)
+		}
 		return oracletypes.Params{}, err
 	}
 
 	o.checkWhitelist(params)
+	o.reportBalance(ctx)
 	o.paramCache.Update(currentBlockHeigh, params)
 	return params, nil
 }
 
+// RefreshParams makes the next tick query the params again, e.g. after a
+// governance proposal changed them.
+func (o *Oracle) RefreshParams() {
+	o.paramsOutdated.Store(true)
+}
+
 // GetParams returns the current on-chain parameters of the x/oracle module.
 func (o *Oracle) GetParams(ctx context.Context) (oracletypes.Params, error) {
 	grpcConn, err := grpc.Dial(
-		o.oracleClient.GRPCEndpoint,
+		o.oracleClient.GRPC.Active(),
 		// the Cosmos SDK doesn't support any transport security mechanism
 		grpc.WithInsecure(),
 		grpc.WithContextDialer(dialerFunc),
@@ -449,17 +868,16 @@ func (o *Oracle) GetParams(ctx context.Context) (oracletypes.Params, error) {
 	}
 
 	defer grpcConn.Close()
-	queryClient := oracletypes.NewQueryClient(grpcConn)
 
 	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	queryResponse, err := queryClient.Params(ctx, &oracletypes.QueryParamsRequest{})
+	params, err := o.chainProfile.Params(ctx, grpcConn)
 	if err != nil {
 		return oracletypes.Params{}, fmt.Errorf("failed to get x/oracle params: %w", err)
 	}
 
-	return queryResponse.Params, nil
+	return params, nil
 }
 
 func NewProvider(
@@ -470,121 +888,8 @@ func NewProvider(
 	endpoint provider.Endpoint,
 	providerPairs ...types.CurrencyPair,
 ) (provider.Provider, error) {
-	endpoint.Name = providerName
 	providerLogger := logger.With().Str("provider", providerName.String()).Logger()
-	switch providerName {
-
-	case
-		provider.ProviderAstroportNeutron,
-		provider.ProviderAstroportTerra2,
-		provider.ProviderAstroportInjective:
-		return provider.NewAstroportProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case
-		provider.ProviderBinance,
-		provider.ProviderBinanceUS:
-		return provider.NewBinanceProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderBingx:
-		return provider.NewBingxProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderBitfinex:
-		return provider.NewBitfinexProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderBitget:
-		return provider.NewBitgetProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderBitstamp:
-		return provider.NewBitstampProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderBkex:
-		return provider.NewBkexProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderBitmart:
-		return provider.NewBitmartProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderBybit:
-		return provider.NewBybitProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderCamelotV2, provider.ProviderCamelotV3:
-		return provider.NewCamelotProvider(db, ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderCoinbase:
-		return provider.NewCoinbaseProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderCoinex:
-		return provider.NewCoinexProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderCrypto:
-		return provider.NewCryptoProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderCurve:
-		return provider.NewCurveProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderDexter:
-		return provider.NewDexterProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderFin:
-		return provider.NewFinProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderFinV2:
-		return provider.NewFinV2Provider(db, ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderGate:
-		return provider.NewGateProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderHelix:
-		return provider.NewHelixProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderHitBtc:
-		return provider.NewHitBtcProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderHuobi:
-		return provider.NewHuobiProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderIdxOsmosis:
-		return provider.NewIdxProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderKraken:
-		return provider.NewKrakenProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderKucoin:
-		return provider.NewKucoinProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderLbank:
-		return provider.NewLbankProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderMaya:
-		return provider.NewMayaProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderMexc:
-		return provider.NewMexcProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderMock:
-		return provider.NewMockProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderOkx:
-		return provider.NewOkxProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderOsmosis:
-		return provider.NewOsmosisProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderOsmosisV2:
-		return provider.NewOsmosisV2Provider(db, ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderPancakeV3Bsc:
-		return provider.NewPancakeProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderPhemex:
-		return provider.NewPhemexProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderPionex:
-		return provider.NewPionexProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderPoloniex:
-		return provider.NewPoloniexProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderPyth:
-		return provider.NewPythProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderShade:
-		return provider.NewShadeProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderUniswapV3:
-		return provider.NewUniswapV3Provider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderUnstake:
-		return provider.NewUnstakeProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderVelodromeV2:
-		return provider.NewVelodromeV2Provider(ctx, providerLogger, endpoint, providerPairs...)
-	case
-		provider.ProviderWhitewhaleCmdx,
-		provider.ProviderWhitewhaleHuahua,
-		provider.ProviderWhitewhaleInj,
-		provider.ProviderWhitewhaleJuno,
-		provider.ProviderWhitewhaleLunc,
-		provider.ProviderWhitewhaleLuna,
-		provider.ProviderWhitewhaleSei,
-		provider.ProviderWhitewhaleWhale:
-		return provider.NewWhitewhaleProvider(db, ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderXt:
-		return provider.NewXtProvider(ctx, providerLogger, endpoint, providerPairs...)
-	case provider.ProviderZero:
-		return provider.NewZeroProvider(ctx, providerLogger, endpoint, providerPairs...)
-
-	}
-	return nil, fmt.Errorf("provider %s not found", providerName)
-}
-
-func (o *Oracle) checkWhitelist(params oracletypes.Params) {
-	for _, denom := range params.Whitelist {
-		symbol := strings.ToUpper(denom.Name)
-		if _, ok := o.prices[symbol]; !ok {
-			o.logger.Warn().Str("denom", symbol).Msg("price missing for required denom")
-		}
-	}
+	return provider.New(db, ctx, providerName, providerLogger, endpoint, providerPairs...)
 }
 
 func (o *Oracle) tick(ctx context.Context) error {
@@ -603,6 +908,11 @@ func (o *Oracle) tick(ctx context.Context) error {
 		return fmt.Errorf("expected positive block height")
 	}
 
+	o.blockTimer.observe(blockHeight, time.Now())
+	o.clock.observeBlock(
+		blockHeight, o.oracleClient.ChainHeight.GetBlockTime(), time.Now(),
+	)
+
 	oracleParams, err := o.GetParamCache(ctx, blockHeight)
 	if err != nil {
 		return err
@@ -611,6 +921,10 @@ func (o *Oracle) tick(ctx context.Context) error {
 	// Get oracle vote period, next block height, current vote period, and index
 	// in the vote period.
 	oracleVotePeriod := int64(oracleParams.VotePeriod)
+	if o.checkHeightStall(ctx, blockHeight, oracleVotePeriod) {
+		return fmt.Errorf("chain height stalled at %d", blockHeight)
+	}
+
 	nextBlockHeight := blockHeight + 1
 	currentVotePeriod := math.Floor(float64(nextBlockHeight) / float64(oracleVotePeriod))
 	indexInVotePeriod := nextBlockHeight % oracleVotePeriod
@@ -625,27 +939,52 @@ func (o *Oracle) tick(ctx context.Context) error {
 	// Skip until new voting period. Specifically, skip when:
 	// index [0, oracleVotePeriod - 1] > oracleVotePeriod - 2 OR index is 0
 	if (o.previousVotePeriod != 0 && currentVotePeriod == o.previousVotePeriod) ||
-		(indexInVotePeriod > 0 && oracleVotePeriod-indexInVotePeriod > 4) {
+		(indexInVotePeriod > 0 && oracleVotePeriod-indexInVotePeriod > o.voteScheduler.windowBlocks(oracleVotePeriod)) {
 		// oracleVotePeriod-indexInVotePeriod < 2 || (indexInVotePeriod > 0 && indexInVotePeriod < int64(float64(oracleVotePeriod)*0.75)) {
 		o.logger.Info().
 			Msg("skipping until next voting period")
 
+		// keep the prices served by the api up to date between votes and
+		// observe every tick while warming up
+		if !o.warmedUp() ||
+			(o.timing.prices > 0 && time.Since(o.GetLastPricesTimestamp()) >= o.timing.prices) {
+			if err := o.SetPrices(ctx); err != nil {
+				o.logger.Warn().Err(err).Msg("failed to update prices")
+			}
+		}
+
 		return nil
 	}
 
-	if err := o.SetPrices(ctx); err != nil {
+	deadline, _ := o.voteDeadline(indexInVotePeriod, oracleVotePeriod, time.Now())
+	if err := o.setPrices(ctx, deadline); err != nil {
 		return err
 	}
 
+	if o.isStandby() {
+		o.logger.Info().Msg("standby, skipping vote")
+
+		// the leader might fail at any time, start over with a prevote
+		o.setPreviousPrevote(0, nil)
+		return nil
+	}
+
+	// a prevote restored from the state file is still revealed
+	if o.previousPrevote == nil && !o.warmedUp() {
+		o.logger.Info().Msg("warming up, skipping prevote")
+		o.setPreviousPrevote(0, nil)
+		return nil
+	}
+
 	// If we're past the voting period we needed to hit, reset and submit another
 	// prevote.
 	if o.previousVotePeriod != 0 && currentVotePeriod-o.previousVotePeriod != 1 {
 		o.logger.Info().
 			Msg("missing vote during voting period")
 		telemetry.IncrCounter(1, "vote", "failure", "missed")
+		o.publish(events.TopicVoteMissed, "missed vote during voting period", nil)
 
-		o.previousVotePeriod = 0
-		o.previousPrevote = nil
+		o.setPreviousPrevote(0, nil)
 		return nil
 	}
 
@@ -659,78 +998,71 @@ func (o *Oracle) tick(ctx context.Context) error {
 		return err
 	}
 
-	exchangeRatesStr := GenerateExchangeRatesString(o.GetPrices())
-	hash := oracletypes.GetAggregateVoteHash(salt, exchangeRatesStr, valAddr)
-	preVoteMsg := &oracletypes.MsgAggregateExchangeRatePrevote{
-		Hash:      hash.String(), // hash of prices from the oracle
-		Feeder:    o.oracleClient.OracleAddrString,
-		Validator: valAddr.String(),
-	}
-
-	isPrevoteOnlyTx := o.previousPrevote == nil
-	if isPrevoteOnlyTx {
-		// This timeout could be as small as oracleVotePeriod-indexInVotePeriod,
-		// but we give it some extra time just in case.
-		//
-		// Ref : https://github.com/terra-money/oracle-feeder/blob/baef2a4a02f57a2ffeaa207932b2e03d7fb0fb25/feeder/src/vote.ts#L222
-		o.logger.Info().
-			Str("hash", hash.String()).
-			Str("validator", preVoteMsg.Validator).
-			Str("feeder", preVoteMsg.Feeder).
-			Msg("broadcasting pre-vote")
-		if err := o.oracleClient.BroadcastTx(nextBlockHeight, oracleVotePeriod*2, preVoteMsg); err != nil {
-			return err
-		}
+	prices := o.GetPrices()
+	exchangeRatesStr := o.ratesFormat.Format(
+		ScalePrices(prices, o.priceExponents),
+		oracleParams.Whitelist,
+	)
+	hash := o.chainProfile.VoteHash(salt, exchangeRatesStr, valAddr)
+	preVoteMsg := o.chainProfile.PrevoteMsg(hash, o.oracleClient.OracleAddrString, valAddr)
 
-		currentHeight, err := o.oracleClient.ChainHeight.GetChainHeight()
-		if err != nil {
-			return err
-		}
+	prevote := PreviousPrevote{
+		Salt:          salt,
+		ExchangeRates: exchangeRatesStr,
+		prices:        prices,
+	}
 
-		o.previousVotePeriod = math.Floor(float64(currentHeight) / float64(oracleVotePeriod))
-		o.previousPrevote = &PreviousPrevote{
-			Salt:              salt,
-			ExchangeRates:     exchangeRatesStr,
-			SubmitBlockHeight: currentHeight,
-		}
+	var queue txQueue
+	if o.previousPrevote == nil {
+		queue.push(pendingTx{kind: txKindPrevote, msgs: []sdk.Msg{preVoteMsg}, hash: hash})
 	} else {
 		// otherwise, we're in the next voting period and thus we vote
-		voteMsg := &oracletypes.MsgAggregateExchangeRateVote{
-			Salt:          o.previousPrevote.Salt,
-			ExchangeRates: o.previousPrevote.ExchangeRates,
-			Feeder:        o.oracleClient.OracleAddrString,
-			Validator:     valAddr.String(),
-		}
+		voteMsg := o.chainProfile.VoteMsg(
+			o.previousPrevote.Salt,
+			o.previousPrevote.ExchangeRates,
+			o.oracleClient.OracleAddrString,
+			valAddr,
+		)
 
-		o.logger.Info().
-			Str("exchange_rates", voteMsg.ExchangeRates).
-			Str("validator", voteMsg.Validator).
-			Str("feeder", voteMsg.Feeder).
-			Msg("broadcasting vote")
-		if err := o.oracleClient.BroadcastTx(
-			nextBlockHeight,
-			oracleVotePeriod-indexInVotePeriod,
-			voteMsg,
-		); err != nil {
-			return err
+		// the vote and the prevote for the next period can be combined in
+		// a single tx, if the chain processes the vote first, otherwise the
+		// prevote follows in its own tx
+		batchPrevote := o.batchVotes && o.chainProfile.BatchVotes() && o.warmedUp()
+		msgs := []sdk.Msg{voteMsg}
+		if batchPrevote {
+			msgs = append(msgs, preVoteMsg)
+		}
+		queue.push(pendingTx{kind: txKindVote, msgs: msgs, batched: batchPrevote})
+
+		if !batchPrevote && o.warmedUp() {
+			queue.push(pendingTx{
+				kind:      txKindPrevote,
+				msgs:      []sdk.Msg{preVoteMsg},
+				hash:      hash,
+				afterVote: true,
+			})
 		}
-
-		o.previousPrevote = nil
-		o.previousVotePeriod = 0
-		o.healthchecksPing()
 	}
 
-	return nil
-}
-
-func (o *Oracle) healthchecksPing() {
-	for url, client := range o.healthchecks {
-		o.logger.Info().Msg("updating healthcheck status")
-		_, err := client.Get(url)
-		if err != nil {
-			o.logger.Warn().Msg("healthcheck ping failed")
+	// a failed vote doesn't hold back the prevote of the next period, it's
+	// returned once the queue is drained
+	var voteErr error
+	for tx, ok := queue.pop(); ok; tx, ok = queue.pop() {
+		switch tx.kind {
+		case txKindVote:
+			voteErr = o.broadcastVote(ctx, tx, nextBlockHeight, oracleVotePeriod, prevote)
+		case txKindPrevote:
+			if err := o.broadcastPrevote(ctx, tx, nextBlockHeight, oracleVotePeriod, prevote); err != nil {
+				if voteErr != nil {
+					o.logger.Error().Err(err).Msg("failed to broadcast pre-vote")
+					return voteErr
+				}
+				return err
+			}
 		}
 	}
+
+	return voteErr
 }
 
 // GenerateSalt generates a random salt, size length/2,  as a HEX encoded string.
@@ -754,3 +1086,28 @@ func GenerateExchangeRatesString(prices sdk.DecCoins) string {
 	prices.Sort()
 	return prices.String()
 }
+
+// ScalePrices multiplies each price by 10^exponent of its denom, for chains
+// expecting exchange rates scaled by a per denom exponent. Prices of denoms
+// without exponent are returned unchanged.
+func ScalePrices(prices sdk.DecCoins, exponents map[string]int) sdk.DecCoins {
+	if len(exponents) == 0 {
+		return prices
+	}
+
+	scaled := make(sdk.DecCoins, 0, len(prices))
+	for _, price := range prices {
+		amount := price.Amount
+
+		exponent := exponents[price.Denom]
+		if exponent > 0 {
+			amount = amount.Mul(sdk.NewDec(10).Power(uint64(exponent)))
+		} else if exponent < 0 {
+			amount = amount.Quo(sdk.NewDec(10).Power(uint64(-exponent)))
+		}
+
+		scaled = append(scaled, sdk.NewDecCoinFromDec(price.Denom, amount))
+	}
+
+	return scaled
+}
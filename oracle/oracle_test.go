@@ -20,13 +20,18 @@ import (
 )
 
 type mockProvider struct {
-	prices map[string]types.TickerPrice
+	prices  map[string]types.TickerPrice
+	candles map[string][]types.CandlePrice
 }
 
 func (m mockProvider) GetTickerPrices(_ ...types.CurrencyPair) (map[string]types.TickerPrice, error) {
 	return m.prices, nil
 }
 
+func (m mockProvider) GetCandlePrices(_ ...types.CurrencyPair) (map[string][]types.CandlePrice, error) {
+	return m.candles, nil
+}
+
 func (m mockProvider) SubscribeCurrencyPairs(_ ...types.CurrencyPair) error {
 	return nil
 }
@@ -43,6 +48,22 @@ func (m mockProvider) CurrencyPairToProviderPair(pair types.CurrencyPair) string
 	return ""
 }
 
+func (m mockProvider) GetName() provider.Name {
+	return provider.ProviderMock
+}
+
+func (m mockProvider) Healthy() bool {
+	return true
+}
+
+func (m mockProvider) LastUpdate() time.Time {
+	return time.Now()
+}
+
+func (m mockProvider) ClockOffset() time.Duration {
+	return 0
+}
+
 // func (m mockProvider) ProviderPairToCurrencyPair(pair string) types.CurrencyPair {
 // 	return types.CurrencyPair{}
 // }
@@ -372,6 +393,26 @@ func TestGenerateExchangeRatesString(t *testing.T) {
 	}
 }
 
+func TestScalePrices(t *testing.T) {
+	prices := sdk.NewDecCoins(
+		sdk.NewDecCoinFromDec("ATOM", sdk.MustNewDecFromStr("40.13")),
+		sdk.NewDecCoinFromDec("BTC", sdk.MustNewDecFromStr("30000")),
+		sdk.NewDecCoinFromDec("UMEE", sdk.MustNewDecFromStr("3.72")),
+	)
+
+	require.Equal(t, prices, ScalePrices(prices, nil))
+
+	scaled := ScalePrices(prices, map[string]int{
+		"ATOM": 6,
+		"BTC":  -2,
+	})
+	require.Equal(
+		t,
+		"40130000.000000000000000000ATOM,300.000000000000000000BTC,3.720000000000000000UMEE",
+		GenerateExchangeRatesString(scaled),
+	)
+}
+
 func TestSuccessGetComputedPricesTickers(t *testing.T) {
 	providerPrices := make(provider.AggregatedProviderPrices, 1)
 	pair := types.CurrencyPair{
@@ -499,3 +540,123 @@ func TestGetComputedPricesTickersConversion(t *testing.T) {
 		prices[btcEthPair.Base],
 	)
 }
+
+// setPricesBudget defines the maximum duration of a single SetPrices with
+// 50 providers and 100 pairs each. The prices are computed right before the
+// vote is broadcast, so the budget has to stay well below the block time.
+const setPricesBudget = time.Second
+
+// newBenchmarkOracle returns an oracle with mock providers, each providing
+// the tickers of the same pairs. Most pairs are quoted in USDT, every tenth
+// in USD, and the prices vary slightly per provider, so that conversion and
+// deviation filtering are exercised.
+func newBenchmarkOracle(tb testing.TB, providers, pairs int) *Oracle {
+	history, err := history.NewPriceHistory(":memory:", zerolog.Nop())
+	require.NoError(tb, err)
+
+	providerNames := make([]provider.Name, providers)
+	for i := range providerNames {
+		providerNames[i] = provider.Name(fmt.Sprintf("provider%d", i))
+	}
+
+	currencyPairs := []config.CurrencyPair{{
+		Base:      "USDT",
+		Quote:     "USD",
+		Providers: providerNames,
+	}}
+	for i := 0; i < pairs; i++ {
+		quote := "USDT"
+		if i%10 == 0 {
+			quote = "USD"
+		}
+		currencyPairs = append(currencyPairs, config.CurrencyPair{
+			Base:      fmt.Sprintf("DENOM%d", i),
+			Quote:     quote,
+			Providers: providerNames,
+		})
+	}
+
+	oracle := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		currencyPairs,
+		time.Second,
+		make(map[string]sdk.Dec),
+		make(map[string]int),
+		make(map[provider.Name]provider.Endpoint),
+		map[string]derivative.Derivative{},
+		map[string][]types.CurrencyPair{},
+		map[string]struct{}{},
+		nil,
+		history,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	for i, providerName := range providerNames {
+		prices := make(map[string]types.TickerPrice, len(currencyPairs))
+		for _, pair := range currencyPairs {
+			price := sdk.NewDecWithPrec(int64(1000+i%5), 3)
+			prices[pair.Base+pair.Quote] = types.TickerPrice{
+				Price:  price,
+				Volume: sdk.NewDec(int64(1000 + i)),
+			}
+		}
+		oracle.priceProviders[providerName] = mockProvider{prices: prices}
+	}
+
+	return oracle
+}
+
+func BenchmarkSetPrices(b *testing.B) {
+	for _, size := range []struct {
+		providers int
+		pairs     int
+	}{
+		{10, 20},
+		{50, 100},
+	} {
+		b.Run(fmt.Sprintf("%dx%d", size.providers, size.pairs), func(b *testing.B) {
+			oracle := newBenchmarkOracle(b, size.providers, size.pairs)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if err := oracle.SetPrices(context.Background()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSetPricesBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping performance budget in short mode")
+	}
+
+	providers, pairs := 50, 100
+	oracle := newBenchmarkOracle(t, providers, pairs)
+
+	// the first run warms up the price history
+	require.NoError(t, oracle.SetPrices(context.Background()))
+	require.Len(t, oracle.GetPrices(), pairs+1)
+
+	start := time.Now()
+	allocs := testing.AllocsPerRun(3, func() {
+		_ = oracle.SetPrices(context.Background())
+	})
+	// AllocsPerRun runs the function once more to warm up
+	elapsed := time.Since(start) / 4
+
+	require.Less(t, elapsed, setPricesBudget)
+
+	// generous budget per ticker, guarding against regressions like
+	// repeatedly converting or copying all tickers per pair
+	budget := float64(providers * (pairs + 1) * 200)
+	require.LessOrEqual(t, allocs, budget)
+}
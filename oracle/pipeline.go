@@ -0,0 +1,403 @@
+package oracle
+
+import (
+	"fmt"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+)
+
+// Stages of the aggregation pipeline, in order of execution.
+const (
+	// StageCollect groups the provider prices by symbol.
+	StageCollect Stage = "collect"
+	// StageNormalize applies the configured provider weights.
+	StageNormalize Stage = "normalize"
+	// StageFilter removes unusable tickers before the conversion.
+	StageFilter Stage = "filter"
+	// StageConvert converts all tickers to USD.
+	StageConvert Stage = "convert"
+	// StageAggregate removes deviating USD rates and computes the VWAP of
+	// each denom.
+	StageAggregate Stage = "aggregate"
+	// StageValidate removes invalid prices from the result.
+	StageValidate Stage = "validate"
+)
+
+type (
+	// Stage defines the name of a pipeline stage.
+	Stage string
+
+	// StageFunc processes the pipeline state.
+	StageFunc func(state *PipelineState) error
+
+	// Middleware wraps a stage, e.g. to modify the state before or after
+	// the stage runs, or to replace it entirely.
+	Middleware func(next StageFunc) StageFunc
+
+	// PipelineState carries the inputs and the intermediate results through
+	// the pipeline stages.
+	PipelineState struct {
+		Logger               zerolog.Logger
+		ProviderPrices       provider.AggregatedProviderPrices
+		ProviderPairs        map[provider.Name][]types.CurrencyPair
+		DeviationThresholds  map[string]sdk.Dec
+		ProviderMinOverrides map[string]int
+		ProviderWeights      map[string]ProviderWeight
+
+		// Pairs contains the configured pairs, set by StageCollect.
+		Pairs []types.CurrencyPair
+		// Tickers contains the provider tickers by symbol, set by
+		// StageCollect.
+		Tickers map[string]map[provider.Name]types.TickerPrice
+		// USDRates contains the USD rates by denom and provider, set by
+		// StageConvert.
+		USDRates map[string]map[provider.Name]types.TickerPrice
+		// Conversions contains the intermediate conversions, set by
+		// StageConvert.
+		Conversions []types.ConversionRate
+		// Prices contains the computed USD prices, set by StageAggregate.
+		Prices map[string]sdk.Dec
+		// Contributors contains the USD rates used for the price of each
+		// denom, set by StageAggregate.
+		Contributors map[string]map[provider.Name]types.TickerPrice
+		// Formulas contains the inputs of the price computation of each
+		// denom, set by StageAggregate.
+		Formulas map[string]types.PriceFormula
+		// Excluded contains the rates removed by any stage by denom, see
+		// Exclude.
+		Excluded map[string][]types.ExcludedRate
+	}
+
+	// Pipeline computes the USD prices from the provider prices in stages.
+	// Features like circuit breakers or sanity bounds hook into a stage with
+	// a middleware instead of extending the stages themselves.
+	Pipeline struct {
+		stages      []pipelineStage
+		middlewares map[Stage][]Middleware
+	}
+
+	pipelineStage struct {
+		name Stage
+		run  StageFunc
+	}
+)
+
+// Pipeline returns the aggregation pipeline of the oracle, which allows
+// registering middlewares before the oracle is started.
+func (o *Oracle) Pipeline() *Pipeline {
+	if o.pipeline == nil {
+		o.pipeline = NewPipeline()
+	}
+	return o.pipeline
+}
+
+// NewPipeline returns the default aggregation pipeline.
+func NewPipeline() *Pipeline {
+	return &Pipeline{
+		stages: []pipelineStage{
+			{StageCollect, collectStage},
+			{StageNormalize, normalizeStage},
+			{StageFilter, filterStage},
+			{StageConvert, convertStage},
+			{StageAggregate, aggregateStage},
+			{StageValidate, validateStage},
+		},
+		middlewares: map[Stage][]Middleware{},
+	}
+}
+
+// Use registers a middleware for the stage. Middlewares registered first
+// are the outermost.
+func (p *Pipeline) Use(stage Stage, middleware Middleware) error {
+	for _, s := range p.stages {
+		if s.name == stage {
+			p.middlewares[stage] = append(p.middlewares[stage], middleware)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown pipeline stage: %s", stage)
+}
+
+// Run executes all stages in order and stops at the first error.
+func (p *Pipeline) Run(state *PipelineState) error {
+	for _, s := range p.stages {
+		run := s.run
+
+		middlewares := p.middlewares[s.name]
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			run = middlewares[i](run)
+		}
+
+		if err := run(state); err != nil {
+			return fmt.Errorf("pipeline stage %s: %w", s.name, err)
+		}
+	}
+
+	return nil
+}
+
+// Exclude records a rate of the denom, that is removed from the price
+// computation. Middlewares removing rates should record them as well, so
+// they show up in the price explanation.
+func (s *PipelineState) Exclude(
+	denom string,
+	providerName provider.Name,
+	symbol string,
+	price sdk.Dec,
+	reason string,
+) {
+	if s.Excluded == nil {
+		s.Excluded = map[string][]types.ExcludedRate{}
+	}
+	s.Excluded[denom] = append(s.Excluded[denom], types.ExcludedRate{
+		Provider: providerName.String(),
+		Symbol:   symbol,
+		Price:    price,
+		Reason:   reason,
+	})
+}
+
+// excludeRates records all rates of the denom as excluded.
+func (s *PipelineState) excludeRates(
+	denom string,
+	rates map[provider.Name]types.TickerPrice,
+	reason string,
+) {
+	for providerName, rate := range rates {
+		s.Exclude(
+			denom, providerName, s.conversionSymbol(denom, providerName),
+			rate.Price, reason,
+		)
+	}
+}
+
+// conversionSymbol returns the symbol the USD rate of the provider was
+// converted from.
+func (s *PipelineState) conversionSymbol(
+	denom string,
+	providerName provider.Name,
+) string {
+	for _, conversion := range s.Conversions {
+		if conversion.Denom == denom &&
+			conversion.Provider == providerName.String() {
+			return conversion.Symbol
+		}
+	}
+	return denom + "USD"
+}
+
+func collectStage(state *PipelineState) error {
+	state.Tickers = map[string]map[provider.Name]types.TickerPrice{}
+	for providerName, tickerPrices := range state.ProviderPrices {
+		for symbol, tickerPrice := range tickerPrices {
+			_, found := state.Tickers[symbol]
+			if !found {
+				state.Tickers[symbol] = map[provider.Name]types.TickerPrice{}
+			}
+
+			state.Tickers[symbol][providerName] = tickerPrice
+		}
+	}
+
+	symbols := map[string]struct{}{}
+	state.Pairs = []types.CurrencyPair{}
+	for _, currencyPairs := range state.ProviderPairs {
+		for _, currencyPair := range currencyPairs {
+			symbol := currencyPair.String()
+			_, found := symbols[symbol]
+			if !found {
+				symbols[symbol] = struct{}{}
+				state.Pairs = append(state.Pairs, currencyPair)
+			}
+		}
+	}
+
+	return nil
+}
+
+func normalizeStage(state *PipelineState) error {
+	// override volume data
+	for _, pair := range state.Pairs {
+		weight, found := state.ProviderWeights[pair.Base]
+		if !found {
+			continue
+		}
+
+		symbol := pair.String()
+		tickers, err := SetWeight(state.Tickers[symbol], weight)
+		if err != nil {
+			return err
+		}
+
+		state.Tickers[symbol] = tickers
+	}
+
+	return nil
+}
+
+func filterStage(state *PipelineState) error {
+	bases := map[string]string{}
+	for _, pair := range state.Pairs {
+		bases[pair.String()] = pair.Base
+	}
+
+	for symbol, tickers := range state.Tickers {
+		for providerName, ticker := range tickers {
+			if ticker.Price.IsNil() || !ticker.Price.IsPositive() {
+				state.Logger.Debug().
+					Str("symbol", symbol).
+					Str("provider", providerName.String()).
+					Msg("removing ticker without valid price")
+				state.Exclude(
+					bases[symbol], providerName, symbol, ticker.Price,
+					"invalid price",
+				)
+				delete(tickers, providerName)
+			}
+		}
+	}
+
+	return nil
+}
+
+func convertStage(state *PipelineState) error {
+	usdRates, conversions, err := resolveUSDRates(
+		state.Logger,
+		state.Tickers,
+		state.Pairs,
+		state.DeviationThresholds,
+		state.ProviderMinOverrides,
+	)
+	if err != nil {
+		return err
+	}
+
+	state.USDRates = usdRates
+	state.Conversions = conversions
+
+	return nil
+}
+
+func aggregateStage(state *PipelineState) error {
+	logger := state.Logger
+
+	state.Prices = map[string]sdk.Dec{}
+	state.Contributors = map[string]map[provider.Name]types.TickerPrice{}
+	state.Formulas = map[string]types.PriceFormula{}
+	for denom, tickers := range state.USDRates {
+		for name, ticker := range tickers {
+			provider.TelemetryProviderPrice(
+				provider.Name("_"+name.String()),
+				denom+"USD",
+				float32(ticker.Price.MustFloat64()),
+				float32(ticker.Volume.MustFloat64()),
+			)
+		}
+
+		threshold := state.DeviationThresholds[denom]
+		filtered, err := FilterTickerDeviations(
+			logger, denom, tickers, threshold, true,
+		)
+		if err != nil {
+			minimum, found := state.ProviderMinOverrides[denom]
+			if !found {
+				logger.Err(err)
+				state.excludeRates(denom, tickers, err.Error())
+				continue
+			}
+			if len(filtered) < minimum {
+				logger.Warn().
+					Str("denom", denom).
+					Int("minimum", minimum).
+					Int("available", len(filtered)).
+					Msg("not enough tickers")
+				state.excludeRates(denom, tickers, "not enough tickers")
+				continue
+			}
+		}
+
+		for providerName, ticker := range tickers {
+			_, found := filtered[providerName]
+			if !found {
+				state.Exclude(
+					denom, providerName,
+					state.conversionSymbol(denom, providerName),
+					ticker.Price, "deviating price",
+				)
+			}
+		}
+
+		state.Formulas[denom] = priceFormula(tickers, filtered, threshold)
+
+		rate, err := vwapRate(filtered)
+		if err != nil {
+			logger.Err(err)
+			state.excludeRates(denom, filtered, err.Error())
+			continue
+		}
+
+		state.Prices[denom] = rate
+		state.Contributors[denom] = filtered
+	}
+
+	return nil
+}
+
+// priceFormula returns the inputs of the deviation filter and the VWAP.
+func priceFormula(
+	tickers, filtered map[provider.Name]types.TickerPrice,
+	threshold sdk.Dec,
+) types.PriceFormula {
+	if threshold.IsNil() {
+		threshold = defaultDeviationThreshold
+	}
+
+	formula := types.PriceFormula{
+		Method:      "vwap",
+		Threshold:   threshold,
+		TotalVolume: sdk.ZeroDec(),
+	}
+
+	prices := []sdk.Dec{}
+	for _, ticker := range tickers {
+		prices = append(prices, ticker.Price)
+	}
+	deviation, mean, err := StandardDeviation(prices)
+	if err == nil {
+		formula.Mean = &mean
+		formula.Deviation = &deviation
+	}
+
+	for _, ticker := range filtered {
+		formula.TotalVolume = formula.TotalVolume.Add(ticker.Volume)
+	}
+
+	return formula
+}
+
+func validateStage(state *PipelineState) error {
+	for denom, rate := range state.Prices {
+		if rate.IsNil() || rate.IsZero() {
+			state.Logger.Error().
+				Str("denom", denom).
+				Msg("rate is zero")
+			state.excludeRates(denom, state.Contributors[denom], "rate is zero")
+			delete(state.Prices, denom)
+			delete(state.Contributors, denom)
+			continue
+		}
+
+		provider.TelemetryProviderPrice(
+			provider.FinalPriceProvider,
+			denom+"USD",
+			float32(rate.MustFloat64()),
+			float32(1),
+		)
+	}
+
+	return nil
+}
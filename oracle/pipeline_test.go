@@ -0,0 +1,147 @@
+package oracle
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func testPipelineInputs() (
+	provider.AggregatedProviderPrices,
+	map[provider.Name][]types.CurrencyPair,
+	map[string]int,
+) {
+	pair := types.CurrencyPair{Base: "KUJI", Quote: "USD"}
+
+	providerPrices := provider.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			"KUJIUSD": {Price: sdk.MustNewDecFromStr("1.0"), Volume: sdk.OneDec()},
+		},
+		provider.ProviderKraken: {
+			"KUJIUSD": {Price: sdk.MustNewDecFromStr("2.0"), Volume: sdk.OneDec()},
+		},
+	}
+
+	providerPairs := map[provider.Name][]types.CurrencyPair{
+		provider.ProviderBinance: {pair},
+		provider.ProviderKraken:  {pair},
+	}
+
+	return providerPrices, providerPairs, map[string]int{"KUJI": 1}
+}
+
+func TestPipelineDefault(t *testing.T) {
+	providerPrices, providerPairs, minOverrides := testPipelineInputs()
+
+	state, err := runPipeline(
+		NewPipeline(), zerolog.Nop(), providerPrices, providerPairs,
+		nil, minOverrides, nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("1.5"), state.Prices["KUJI"])
+	require.Len(t, state.Conversions, 2)
+}
+
+func TestPipelineMiddlewares(t *testing.T) {
+	providerPrices, providerPairs, minOverrides := testPipelineInputs()
+
+	pipeline := NewPipeline()
+	calls := []string{}
+
+	// custom filter removing a provider before the conversion
+	err := pipeline.Use(StageFilter, func(next StageFunc) StageFunc {
+		return func(state *PipelineState) error {
+			calls = append(calls, "outer")
+			delete(state.Tickers["KUJIUSD"], provider.ProviderKraken)
+			return next(state)
+		}
+	})
+	require.NoError(t, err)
+
+	err = pipeline.Use(StageFilter, func(next StageFunc) StageFunc {
+		return func(state *PipelineState) error {
+			calls = append(calls, "inner")
+			return next(state)
+		}
+	})
+	require.NoError(t, err)
+
+	// sanity bound applied to the aggregated prices
+	err = pipeline.Use(StageValidate, func(next StageFunc) StageFunc {
+		return func(state *PipelineState) error {
+			for denom, price := range state.Prices {
+				if price.LT(sdk.MustNewDecFromStr("1.5")) {
+					delete(state.Prices, denom)
+				}
+			}
+			return next(state)
+		}
+	})
+	require.NoError(t, err)
+
+	state, err := runPipeline(
+		pipeline, zerolog.Nop(), providerPrices, providerPairs,
+		nil, minOverrides, nil,
+	)
+	require.NoError(t, err)
+	require.Empty(t, state.Prices)
+	require.Equal(t, []string{"outer", "inner"}, calls)
+
+	require.Error(t, pipeline.Use(Stage("unknown"), nil))
+}
+
+func TestPipelineStageError(t *testing.T) {
+	providerPrices, providerPairs, minOverrides := testPipelineInputs()
+
+	pipeline := NewPipeline()
+	err := pipeline.Use(StageConvert, func(next StageFunc) StageFunc {
+		return func(state *PipelineState) error {
+			return fmt.Errorf("circuit breaker open")
+		}
+	})
+	require.NoError(t, err)
+
+	_, err = runPipeline(
+		pipeline, zerolog.Nop(), providerPrices, providerPairs,
+		nil, minOverrides, nil,
+	)
+	require.ErrorContains(t, err, "pipeline stage convert: circuit breaker open")
+}
+
+func TestPipelineExplain(t *testing.T) {
+	providerPrices, providerPairs, minOverrides := testPipelineInputs()
+	providerPrices[provider.ProviderKucoin] = map[string]types.TickerPrice{
+		"KUJIUSD": {Price: sdk.ZeroDec(), Volume: sdk.OneDec()},
+	}
+	providerPairs[provider.ProviderKucoin] = providerPairs[provider.ProviderBinance]
+
+	state, err := runPipeline(
+		NewPipeline(), zerolog.Nop(), providerPrices, providerPairs,
+		nil, minOverrides, nil,
+	)
+	require.NoError(t, err)
+
+	skipped := map[string][]types.ExcludedRate{
+		"KUJI": {{Provider: "okx", Symbol: "KUJIUSDT", Reason: "blacklisted"}},
+	}
+	explanation := explainPrices(state, skipped, time.Now())
+
+	kuji := explanation.Prices["KUJI"]
+	require.Equal(t, sdk.MustNewDecFromStr("1.5"), *kuji.Price)
+	require.Len(t, kuji.Providers, 2)
+	require.Equal(t, "binance", kuji.Providers[0].Provider)
+	require.Equal(t, sdk.MustNewDecFromStr("0.5"), kuji.Providers[0].Weight)
+	require.Equal(t, "vwap", kuji.Formula.Method)
+	require.Equal(t, sdk.NewDec(2), kuji.Formula.TotalVolume)
+
+	require.Len(t, kuji.Excluded, 2)
+	require.Equal(t, "invalid price", kuji.Excluded[0].Reason)
+	require.Equal(t, "blacklisted", kuji.Excluded[1].Reason)
+}
@@ -0,0 +1,188 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/grpc"
+)
+
+// DefaultChainProfile is the name of the chain profile used if none is
+// configured.
+const DefaultChainProfile = "kujira"
+
+// ChainProfile encapsulates everything specific to the oracle module of a
+// chain: the address prefix, the messages, the vote hash, the exchange rates
+// format and the queries. The params and votes of other chains are converted to the types
+// of the Kujira oracle module, which most oracle modules are forked from
+// alike.
+type ChainProfile interface {
+	// AddressPrefix returns the bech32 prefix of the account addresses.
+	AddressPrefix() string
+	// RatesFormat returns the default format of the exchange rates string.
+	RatesFormat() RatesFormat
+	// VoteHash returns the hash committed to by the prevote.
+	VoteHash(salt, exchangeRates string, validator sdk.ValAddress) string
+	// PrevoteMsg returns the prevote message of the hash.
+	PrevoteMsg(hash, feeder string, validator sdk.ValAddress) sdk.Msg
+	// VoteMsg returns the vote message revealing the exchange rates.
+	VoteMsg(salt, exchangeRates, feeder string, validator sdk.ValAddress) sdk.Msg
+	// BatchVotes returns true if the vote and the prevote of the next
+	// period can be broadcast in a single tx.
+	BatchVotes() bool
+	// Params queries the params of the oracle module.
+	Params(ctx context.Context, conn *grpc.ClientConn) (oracletypes.Params, error)
+	// MissCounter queries the miss counter of the validator.
+	MissCounter(ctx context.Context, conn *grpc.ClientConn, validator string) (uint64, error)
+	// AggregateVote queries the stored aggregate vote of the validator.
+	AggregateVote(
+		ctx context.Context,
+		conn *grpc.ClientConn,
+		validator string,
+	) (oracletypes.ExchangeRateTuples, error)
+}
+
+// chainProfiles holds all chain profiles by name. Profiles of other forks,
+// e.g. Sei or Terra Classic, can be registered by the programs embedding the
+// feeder.
+var chainProfiles = map[string]ChainProfile{
+	DefaultChainProfile: kujiraProfile{},
+	"umee":              umeeProfile{},
+}
+
+// RegisterChainProfile adds a chain profile, so the feeder can be built for
+// chains with their own oracle module types. It panics if the name is
+// registered already.
+func RegisterChainProfile(name string, profile ChainProfile) {
+	if _, found := chainProfiles[name]; found {
+		panic(fmt.Sprintf("chain profile %s registered twice", name))
+	}
+	chainProfiles[name] = profile
+}
+
+// GetChainProfile returns the chain profile of the name, the default one if
+// the name is empty.
+func GetChainProfile(name string) (ChainProfile, error) {
+	if name == "" {
+		name = DefaultChainProfile
+	}
+	profile, found := chainProfiles[name]
+	if !found {
+		return nil, fmt.Errorf("unknown chain profile: %s", name)
+	}
+	return profile, nil
+}
+
+// ChainProfiles returns the names of all registered chain profiles.
+func ChainProfiles() []string {
+	names := make([]string, 0, len(chainProfiles))
+	for name := range chainProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetAddressPrefixes sets the bech32 prefixes of the addresses to the ones
+// of the chain profile. It has to be called before any address is parsed.
+func SetAddressPrefixes(profile ChainProfile) {
+	prefix := profile.AddressPrefix()
+	validator := prefix + sdk.PrefixValidator + sdk.PrefixOperator
+	consensus := prefix + sdk.PrefixValidator + sdk.PrefixConsensus
+
+	cfg := sdk.GetConfig()
+	cfg.SetBech32PrefixForAccount(prefix, prefix+sdk.PrefixPublic)
+	cfg.SetBech32PrefixForValidator(validator, validator+sdk.PrefixPublic)
+	cfg.SetBech32PrefixForConsensusNode(consensus, consensus+sdk.PrefixPublic)
+}
+
+// SetChainProfile sets the chain profile used to vote and query the oracle
+// module.
+func (o *Oracle) SetChainProfile(profile ChainProfile) {
+	o.chainProfile = profile
+}
+
+// kujiraProfile implements the Kujira oracle module.
+type kujiraProfile struct{}
+
+func (kujiraProfile) AddressPrefix() string {
+	return "kujira"
+}
+
+func (kujiraProfile) RatesFormat() RatesFormat {
+	return DefaultRatesFormat
+}
+
+func (kujiraProfile) VoteHash(salt, exchangeRates string, validator sdk.ValAddress) string {
+	return oracletypes.GetAggregateVoteHash(salt, exchangeRates, validator).String()
+}
+
+func (kujiraProfile) PrevoteMsg(hash, feeder string, validator sdk.ValAddress) sdk.Msg {
+	return &oracletypes.MsgAggregateExchangeRatePrevote{
+		Hash:      hash,
+		Feeder:    feeder,
+		Validator: validator.String(),
+	}
+}
+
+func (kujiraProfile) VoteMsg(
+	salt, exchangeRates, feeder string,
+	validator sdk.ValAddress,
+) sdk.Msg {
+	return &oracletypes.MsgAggregateExchangeRateVote{
+		Salt:          salt,
+		ExchangeRates: exchangeRates,
+		Feeder:        feeder,
+		Validator:     validator.String(),
+	}
+}
+
+// BatchVotes returns true, as the Kujira oracle module processes the vote
+// before the prevote of the same tx.
+func (kujiraProfile) BatchVotes() bool {
+	return true
+}
+
+func (kujiraProfile) Params(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+) (oracletypes.Params, error) {
+	queryResponse, err := oracletypes.NewQueryClient(conn).Params(
+		ctx, &oracletypes.QueryParamsRequest{},
+	)
+	if err != nil {
+		return oracletypes.Params{}, err
+	}
+	return queryResponse.Params, nil
+}
+
+func (kujiraProfile) MissCounter(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	validator string,
+) (uint64, error) {
+	queryResponse, err := oracletypes.NewQueryClient(conn).MissCounter(
+		ctx, &oracletypes.QueryMissCounterRequest{ValidatorAddr: validator},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return queryResponse.MissCounter, nil
+}
+
+func (kujiraProfile) AggregateVote(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	validator string,
+) (oracletypes.ExchangeRateTuples, error) {
+	queryResponse, err := oracletypes.NewQueryClient(conn).AggregateVote(
+		ctx, &oracletypes.QueryAggregateVoteRequest{ValidatorAddr: validator},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return queryResponse.AggregateVote.ExchangeRateTuples, nil
+}
@@ -0,0 +1,225 @@
+package oracle
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestChainProfile(t *testing.T) {
+	profile, err := GetChainProfile("")
+	require.NoError(t, err)
+	require.Equal(t, kujiraProfile{}, profile)
+	require.Contains(t, ChainProfiles(), DefaultChainProfile)
+
+	_, err = GetChainProfile("unknown")
+	require.Error(t, err)
+
+	require.Panics(t, func() {
+		RegisterChainProfile(DefaultChainProfile, kujiraProfile{})
+	})
+
+	validator := sdk.ValAddress([]byte("validator"))
+	hash := profile.VoteHash("salt", "1.0ATOM", validator)
+	require.Equal(
+		t,
+		&oracletypes.MsgAggregateExchangeRatePrevote{
+			Hash:      hash,
+			Feeder:    "feeder",
+			Validator: validator.String(),
+		},
+		profile.PrevoteMsg(hash, "feeder", validator),
+	)
+	require.Equal(
+		t,
+		oracletypes.GetAggregateVoteHash("salt", "1.0ATOM", validator).String(),
+		hash,
+	)
+}
+
+// rawCodec passes the messages through as bytes, to serve hand encoded
+// responses of the Umee oracle module.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	return *v.(*[]byte), nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	*v.(*[]byte) = append([]byte{}, data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+func TestUmeeProfile(t *testing.T) {
+	profile, err := GetChainProfile("umee")
+	require.NoError(t, err)
+	require.Equal(t, "umee", profile.AddressPrefix())
+	require.Equal(t, DefaultRatesFormat, profile.RatesFormat())
+	require.False(t, profile.BatchVotes())
+
+	validator := sdk.ValAddress([]byte("validator"))
+	hash := profile.VoteHash("salt", "1.0ATOM", validator)
+	require.Equal(
+		t,
+		oracletypes.GetAggregateVoteHash("salt", "1.0ATOM", validator).String(),
+		hash,
+	)
+
+	// same fields as the kujira messages, but the type urls of umee
+	prevote, err := codectypes.NewAnyWithValue(profile.PrevoteMsg(hash, "feeder", validator))
+	require.NoError(t, err)
+	require.Equal(t, "/umee.oracle.v1.MsgAggregateExchangeRatePrevote", prevote.TypeUrl)
+	bz, err := (&oracletypes.MsgAggregateExchangeRatePrevote{
+		Hash:      hash,
+		Feeder:    "feeder",
+		Validator: validator.String(),
+	}).Marshal()
+	require.NoError(t, err)
+	require.Equal(t, bz, prevote.Value)
+
+	vote, err := codectypes.NewAnyWithValue(profile.VoteMsg("salt", "ATOM:1.0", "feeder", validator))
+	require.NoError(t, err)
+	require.Equal(t, "/umee.oracle.v1.MsgAggregateExchangeRateVote", vote.TypeUrl)
+	bz, err = (&oracletypes.MsgAggregateExchangeRateVote{
+		Salt:          "salt",
+		ExchangeRates: "ATOM:1.0",
+		Feeder:        "feeder",
+		Validator:     validator.String(),
+	}).Marshal()
+	require.NoError(t, err)
+	require.Equal(t, bz, vote.Value)
+
+	// queries
+	dec := func(s string) []byte {
+		bz, err := sdk.MustNewDecFromStr(s).Marshal()
+		require.NoError(t, err)
+		return bz
+	}
+	denom := func(base, symbol string) []byte {
+		bz := protowire.AppendTag(nil, 1, protowire.BytesType)
+		bz = protowire.AppendString(bz, base)
+		bz = protowire.AppendTag(bz, 2, protowire.BytesType)
+		bz = protowire.AppendString(bz, symbol)
+		bz = protowire.AppendTag(bz, 3, protowire.VarintType)
+		return protowire.AppendVarint(bz, 6)
+	}
+
+	params := protowire.AppendTag(nil, 1, protowire.VarintType)
+	params = protowire.AppendVarint(params, 5)
+	params = protowire.AppendTag(params, 2, protowire.BytesType)
+	params = protowire.AppendBytes(params, dec("0.5"))
+	params = protowire.AppendTag(params, 5, protowire.BytesType)
+	params = protowire.AppendBytes(params, denom("uumee", "UMEE"))
+	params = protowire.AppendTag(params, 5, protowire.BytesType)
+	params = protowire.AppendBytes(params, denom("ibc/ATOM", "ATOM"))
+	params = protowire.AppendTag(params, 7, protowire.VarintType)
+	params = protowire.AppendVarint(params, 100)
+	params = protowire.AppendTag(params, 8, protowire.BytesType)
+	params = protowire.AppendBytes(params, dec("0.05"))
+	// historic stamp period, unknown to the feeder
+	params = protowire.AppendTag(params, 9, protowire.VarintType)
+	params = protowire.AppendVarint(params, 10)
+	paramsResponse := protowire.AppendTag(nil, 1, protowire.BytesType)
+	paramsResponse = protowire.AppendBytes(paramsResponse, params)
+
+	missCounterResponse := protowire.AppendTag(nil, 1, protowire.VarintType)
+	missCounterResponse = protowire.AppendVarint(missCounterResponse, 3)
+
+	tuple := protowire.AppendTag(nil, 1, protowire.BytesType)
+	tuple = protowire.AppendString(tuple, "ATOM")
+	tuple = protowire.AppendTag(tuple, 2, protowire.BytesType)
+	tuple = protowire.AppendBytes(tuple, dec("10.5"))
+	aggregateVote := protowire.AppendTag(nil, 1, protowire.BytesType)
+	aggregateVote = protowire.AppendBytes(aggregateVote, tuple)
+	aggregateVote = protowire.AppendTag(aggregateVote, 2, protowire.BytesType)
+	aggregateVote = protowire.AppendString(aggregateVote, "voter")
+	aggregateVoteResponse := protowire.AppendTag(nil, 1, protowire.BytesType)
+	aggregateVoteResponse = protowire.AppendBytes(aggregateVoteResponse, aggregateVote)
+
+	responses := map[string][]byte{
+		"/umee.oracle.v1.Query/Params":        paramsResponse,
+		"/umee.oracle.v1.Query/MissCounter":   missCounterResponse,
+		"/umee.oracle.v1.Query/AggregateVote": aggregateVoteResponse,
+	}
+
+	var mtx sync.Mutex
+	requests := map[string][]byte{}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(
+		grpc.ForceServerCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+			method, _ := grpc.MethodFromServerStream(stream)
+			var request []byte
+			if err := stream.RecvMsg(&request); err != nil {
+				return err
+			}
+
+			mtx.Lock()
+			requests[method] = request
+			mtx.Unlock()
+
+			response, found := responses[method]
+			if !found {
+				return status.Error(codes.Unimplemented, method)
+			}
+			return stream.SendMsg(&response)
+		}),
+	)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(
+		ctx, "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	p, err := profile.Params(ctx, conn)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), p.VotePeriod)
+	require.Equal(t, sdk.MustNewDecFromStr("0.5"), p.VoteThreshold)
+	require.Equal(t, sdk.ZeroDec(), p.RewardBand)
+	require.Equal(t, uint64(100), p.SlashWindow)
+	require.Equal(t, sdk.MustNewDecFromStr("0.05"), p.MinValidPerWindow)
+	require.Equal(t, oracletypes.DenomList{{Name: "UMEE"}, {Name: "ATOM"}}, p.Whitelist)
+
+	missCounter, err := profile.MissCounter(ctx, conn, "umeevaloper1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), missCounter)
+
+	tuples, err := profile.AggregateVote(ctx, conn, "umeevaloper1")
+	require.NoError(t, err)
+	require.Equal(t, oracletypes.ExchangeRateTuples{
+		{Denom: "ATOM", ExchangeRate: sdk.MustNewDecFromStr("10.5")},
+	}, tuples)
+
+	bz, err = (&oracletypes.QueryAggregateVoteRequest{ValidatorAddr: "umeevaloper1"}).Marshal()
+	require.NoError(t, err)
+	mtx.Lock()
+	require.Equal(t, bz, requests["/umee.oracle.v1.Query/AggregateVote"])
+	mtx.Unlock()
+}
@@ -0,0 +1,196 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+
+	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/grpc"
+)
+
+// The Umee oracle module is forked from the same module as the Kujira one.
+// Its messages and queries have the same fields, so the Kujira types are
+// reused with the type urls and query methods of Umee. Only the params
+// differ, the whitelist is the accept list of the symbol and base denoms.
+const (
+	umeePrevoteMsgName = "umee.oracle.v1.MsgAggregateExchangeRatePrevote"
+	umeeVoteMsgName    = "umee.oracle.v1.MsgAggregateExchangeRateVote"
+
+	umeeQueryParams        = "/umee.oracle.v1.Query/Params"
+	umeeQueryMissCounter   = "/umee.oracle.v1.Query/MissCounter"
+	umeeQueryAggregateVote = "/umee.oracle.v1.Query/AggregateVote"
+)
+
+type (
+	// umeeProfile implements the Umee oracle module.
+	umeeProfile struct{}
+
+	umeePrevoteMsg struct {
+		oracletypes.MsgAggregateExchangeRatePrevote
+	}
+
+	umeeVoteMsg struct {
+		oracletypes.MsgAggregateExchangeRateVote
+	}
+
+	umeeQueryParamsResponse struct {
+		Params *umeeParams `protobuf:"bytes,1,opt,name=params,proto3"`
+	}
+
+	// umeeParams holds the params used by the feeder, the decimals are
+	// encoded as integers scaled by 10^18.
+	umeeParams struct {
+		VotePeriod               uint64       `protobuf:"varint,1,opt,name=vote_period,json=votePeriod,proto3"`
+		VoteThreshold            string       `protobuf:"bytes,2,opt,name=vote_threshold,json=voteThreshold,proto3"`
+		RewardBand               string       `protobuf:"bytes,3,opt,name=reward_band,json=rewardBand,proto3"`
+		RewardDistributionWindow uint64       `protobuf:"varint,4,opt,name=reward_distribution_window,json=rewardDistributionWindow,proto3"`
+		AcceptList               []*umeeDenom `protobuf:"bytes,5,rep,name=accept_list,json=acceptList,proto3"`
+		SlashFraction            string       `protobuf:"bytes,6,opt,name=slash_fraction,json=slashFraction,proto3"`
+		SlashWindow              uint64       `protobuf:"varint,7,opt,name=slash_window,json=slashWindow,proto3"`
+		MinValidPerWindow        string       `protobuf:"bytes,8,opt,name=min_valid_per_window,json=minValidPerWindow,proto3"`
+	}
+
+	umeeDenom struct {
+		BaseDenom   string `protobuf:"bytes,1,opt,name=base_denom,json=baseDenom,proto3"`
+		SymbolDenom string `protobuf:"bytes,2,opt,name=symbol_denom,json=symbolDenom,proto3"`
+		Exponent    uint32 `protobuf:"varint,3,opt,name=exponent,proto3"`
+	}
+)
+
+func (*umeePrevoteMsg) XXX_MessageName() string {
+	return umeePrevoteMsgName
+}
+
+func (*umeeVoteMsg) XXX_MessageName() string {
+	return umeeVoteMsgName
+}
+
+func (m *umeeQueryParamsResponse) Reset()         { *m = umeeQueryParamsResponse{} }
+func (m *umeeQueryParamsResponse) String() string { return fmt.Sprintf("%+v", m.Params) }
+func (*umeeQueryParamsResponse) ProtoMessage()    {}
+
+func (umeeProfile) AddressPrefix() string {
+	return "umee"
+}
+
+func (umeeProfile) RatesFormat() RatesFormat {
+	return RatesFormatProfiles["umee"]
+}
+
+func (umeeProfile) VoteHash(salt, exchangeRates string, validator sdk.ValAddress) string {
+	return oracletypes.GetAggregateVoteHash(salt, exchangeRates, validator).String()
+}
+
+func (umeeProfile) PrevoteMsg(hash, feeder string, validator sdk.ValAddress) sdk.Msg {
+	return &umeePrevoteMsg{oracletypes.MsgAggregateExchangeRatePrevote{
+		Hash:      hash,
+		Feeder:    feeder,
+		Validator: validator.String(),
+	}}
+}
+
+func (umeeProfile) VoteMsg(
+	salt, exchangeRates, feeder string,
+	validator sdk.ValAddress,
+) sdk.Msg {
+	return &umeeVoteMsg{oracletypes.MsgAggregateExchangeRateVote{
+		Salt:          salt,
+		ExchangeRates: exchangeRates,
+		Feeder:        feeder,
+		Validator:     validator.String(),
+	}}
+}
+
+// BatchVotes returns false, the Umee price feeder broadcasts the vote and
+// the prevote in separate txs.
+func (umeeProfile) BatchVotes() bool {
+	return false
+}
+
+func (umeeProfile) Params(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+) (oracletypes.Params, error) {
+	var queryResponse umeeQueryParamsResponse
+	err := conn.Invoke(ctx, umeeQueryParams, &oracletypes.QueryParamsRequest{}, &queryResponse)
+	if err != nil {
+		return oracletypes.Params{}, err
+	}
+	if queryResponse.Params == nil {
+		return oracletypes.Params{}, fmt.Errorf("no params received")
+	}
+	return queryResponse.Params.toParams()
+}
+
+func (umeeProfile) MissCounter(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	validator string,
+) (uint64, error) {
+	var queryResponse oracletypes.QueryMissCounterResponse
+	err := conn.Invoke(
+		ctx,
+		umeeQueryMissCounter,
+		&oracletypes.QueryMissCounterRequest{ValidatorAddr: validator},
+		&queryResponse,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return queryResponse.MissCounter, nil
+}
+
+func (umeeProfile) AggregateVote(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	validator string,
+) (oracletypes.ExchangeRateTuples, error) {
+	var queryResponse oracletypes.QueryAggregateVoteResponse
+	err := conn.Invoke(
+		ctx,
+		umeeQueryAggregateVote,
+		&oracletypes.QueryAggregateVoteRequest{ValidatorAddr: validator},
+		&queryResponse,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return queryResponse.AggregateVote.ExchangeRateTuples, nil
+}
+
+// toParams converts the params to the params of the Kujira oracle module.
+func (p umeeParams) toParams() (oracletypes.Params, error) {
+	params := oracletypes.Params{
+		VotePeriod:               p.VotePeriod,
+		RewardDistributionWindow: p.RewardDistributionWindow,
+		SlashWindow:              p.SlashWindow,
+	}
+
+	decs := []struct {
+		value string
+		dec   *sdk.Dec
+	}{
+		{p.VoteThreshold, &params.VoteThreshold},
+		{p.RewardBand, &params.RewardBand},
+		{p.SlashFraction, &params.SlashFraction},
+		{p.MinValidPerWindow, &params.MinValidPerWindow},
+	}
+	for _, d := range decs {
+		*d.dec = sdk.ZeroDec()
+		if d.value == "" {
+			continue
+		}
+		if err := d.dec.Unmarshal([]byte(d.value)); err != nil {
+			return oracletypes.Params{}, fmt.Errorf("invalid decimal %s: %w", d.value, err)
+		}
+	}
+
+	for _, denom := range p.AcceptList {
+		params.Whitelist = append(params.Whitelist, oracletypes.Denom{
+			Name: denom.SymbolDenom,
+		})
+	}
+
+	return params, nil
+}
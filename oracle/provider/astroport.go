@@ -18,16 +18,19 @@ var (
 	astroportTerra2DefaultEndpoints          = Endpoint{
 		Name:         ProviderAstroportTerra2,
 		Urls:         []string{},
+		ChainId:      "phoenix-1",
 		PollInterval: 6 * time.Second,
 	}
 	astroportNeutronDefaultEndpoints = Endpoint{
 		Name:         ProviderAstroportNeutron,
 		Urls:         []string{},
+		ChainId:      "neutron-1",
 		PollInterval: 6 * time.Second,
 	}
 	astroportInjectiveDefaultEndpoints = Endpoint{
 		Name:         ProviderAstroportInjective,
 		Urls:         []string{},
+		ChainId:      "injective-1",
 		PollInterval: 6 * time.Second,
 	}
 )
@@ -76,6 +79,14 @@ type (
 		Amount string         `json:"amount"`
 	}
 
+	AstroportPoolResponse struct {
+		Data AstroportPoolData `json:"data"`
+	}
+
+	AstroportPoolData struct {
+		Assets []AstroportOfferAsset `json:"assets"`
+	}
+
 	AstroportSimulationResponse struct {
 		Data AstroportSimulationData `json:"data"`
 	}
@@ -87,6 +98,12 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderAstroportNeutron, astroportNeutronDefaultEndpoints, NewAstroportProvider)
+	register(ProviderAstroportTerra2, astroportTerra2DefaultEndpoints, NewAstroportProvider)
+	register(ProviderAstroportInjective, astroportInjectiveDefaultEndpoints, NewAstroportProvider)
+}
+
 func NewAstroportProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -103,8 +120,6 @@ func NewAstroportProvider(
 		nil,
 	)
 
-	provider.contracts = provider.endpoints.ContractAddresses
-
 	availablePairs, _ := provider.GetAvailablePairs()
 	provider.setPairs(pairs, availablePairs, nil)
 
@@ -179,11 +194,20 @@ func (p *AstroportProvider) Poll() error {
 
 		price := strToDec(simulationResponse.Data.Return).Quo(strToDec(amount))
 
+		base, quote := offerAsset, askAsset
 		_, found = p.pairs[pair.String()]
 		if !found {
 			price = floatToDec(1).Quo(price)
+			base, quote = askAsset, offerAsset
 		}
 
+		// the reserves are scaled like the simulated amount
+		liquidity, err := p.getLiquidity(contract, base, quote, price, strToDec(amount))
+		if err != nil {
+			p.logger.Warn().Err(err).Str("symbol", symbol).Msg("failed to get liquidity")
+		}
+		p.setLiquidity(symbol, liquidity)
+
 		p.setTickerPrice(
 			symbol,
 			price,
@@ -199,6 +223,52 @@ func (p *AstroportProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	return p.getAvailablePairsFromContracts()
 }
 
+// getLiquidity returns the value of the base and quote reserves of the pool
+// in terms of the quote asset.
+func (p *AstroportProvider) getLiquidity(
+	contract string,
+	base, quote AstroportAsset,
+	price, scale sdk.Dec,
+) (sdk.Dec, error) {
+	content, err := p.wasmSmartQuery(contract, `{"pool":{}}`)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	var poolResponse AstroportPoolResponse
+	err = json.Unmarshal(content, &poolResponse)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	var baseReserve, quoteReserve sdk.Dec
+	for _, asset := range poolResponse.Data.Assets {
+		switch {
+		case asset.Info.equal(base):
+			baseReserve = strToDec(asset.Amount)
+		case asset.Info.equal(quote):
+			quoteReserve = strToDec(asset.Amount)
+		}
+	}
+
+	if baseReserve.IsNil() || quoteReserve.IsNil() {
+		return sdk.Dec{}, fmt.Errorf("pool reserves not found")
+	}
+
+	return poolLiquidity(baseReserve, quoteReserve, price).Quo(scale), nil
+}
+
+// equal returns true, if both assets refer to the same token.
+func (a AstroportAsset) equal(b AstroportAsset) bool {
+	if a.NativeToken != nil && b.NativeToken != nil {
+		return a.NativeToken.Denom == b.NativeToken.Denom
+	}
+	if a.Token != nil && b.Token != nil {
+		return a.Token.ContractAddress == b.Token.ContractAddress
+	}
+	return false
+}
+
 func (p *AstroportProvider) getDenoms() map[string]AstroportAsset {
 	assets := map[string]AstroportAsset{}
 
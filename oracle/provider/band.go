@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// bandDefaultMaxAge defines how old the band prices of a pair may be,
+	// unless the pair configures its own limit in periods.
+	bandDefaultMaxAge = 10 * time.Minute
+	bandAskCount      = 16
+	bandMinCount      = 10
+)
+
+var (
+	_                    Provider = (*BandProvider)(nil)
+	bandDefaultEndpoints          = Endpoint{
+		Name:         ProviderBand,
+		Urls:         []string{"https://laozi1.bandchain.org/api"},
+		ChainId:      "laozi-mainnet",
+		PollInterval: 10 * time.Second,
+	}
+)
+
+type (
+	// BandProvider defines an oracle provider that queries the reference
+	// rates of the BandChain standard dataset.
+	//
+	// REF: https://docs.bandchain.org/develop/api-endpoints
+	BandProvider struct {
+		provider
+		// maxAge of the prices by symbol, read from the periods of the
+		// endpoint in seconds
+		maxAge map[string]time.Duration
+	}
+
+	BandPricesResponse struct {
+		Results []BandPriceResult `json:"price_results"`
+	}
+
+	BandPriceResult struct {
+		Symbol      string `json:"symbol"`       // Symbol ex.: BTC
+		Multiplier  string `json:"multiplier"`   // Multiplier ex.: 1000000000
+		Price       string `json:"px"`           // Price ex.: 26000000000000
+		RequestID   string `json:"request_id"`   // Request id ex.: 12345678
+		ResolveTime string `json:"resolve_time"` // Unix time ex.: 1690000000
+	}
+)
+
+func init() {
+	register(ProviderBand, bandDefaultEndpoints, NewBandProvider)
+}
+
+func NewBandProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*BandProvider, error) {
+	provider := &BandProvider{}
+	provider.Init(
+		ctx,
+		endpoints,
+		logger,
+		pairs,
+		nil,
+		nil,
+	)
+
+	// band has no use for periods otherwise, so like the unbonding periods
+	// of the unstake provider, they hold the max age of the prices
+	provider.maxAge = map[string]time.Duration{}
+	for symbol, seconds := range endpoints.Periods {
+		provider.maxAge[symbol] = time.Duration(seconds) * time.Second
+	}
+
+	availablePairs, _ := provider.GetAvailablePairs()
+	provider.setPairs(pairs, availablePairs, nil)
+
+	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	return provider, nil
+}
+
+func (p *BandProvider) getPrices(symbols []string) ([]BandPriceResult, error) {
+	stubs := []string{
+		"ask_count=" + strconv.Itoa(bandAskCount),
+		"min_count=" + strconv.Itoa(bandMinCount),
+	}
+	for _, symbol := range symbols {
+		stubs = append(stubs, "symbols="+symbol)
+	}
+
+	path := "/oracle/v1/request_prices?" + strings.Join(stubs, "&")
+	content, err := p.httpGet(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var response BandPricesResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Results, nil
+}
+
+func (p *BandProvider) Poll() error {
+	pairs := p.getAllPairs()
+
+	symbols := []string{}
+	seen := map[string]struct{}{}
+	for _, pair := range pairs {
+		for _, denom := range []string{pair.Base, pair.Quote} {
+			if denom == "USD" {
+				continue
+			}
+			_, found := seen[denom]
+			if found {
+				continue
+			}
+			seen[denom] = struct{}{}
+			symbols = append(symbols, denom)
+		}
+	}
+
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	results, err := p.getPrices(symbols)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	rates := map[string]sdk.Dec{"USD": sdk.OneDec()}
+	resolved := map[string]time.Time{"USD": now}
+	for _, result := range results {
+		price := strToDec(result.Price)
+		multiplier := strToDec(result.Multiplier)
+		if price.IsNil() || multiplier.IsNil() || multiplier.IsZero() {
+			p.logger.Warn().
+				Str("symbol", result.Symbol).
+				Msg("invalid price result")
+			continue
+		}
+
+		seconds, err := strconv.ParseInt(result.ResolveTime, 10, 64)
+		if err != nil {
+			p.logger.Warn().
+				Str("symbol", result.Symbol).
+				Str("resolve_time", result.ResolveTime).
+				Msg("invalid resolve time")
+			continue
+		}
+
+		rates[result.Symbol] = price.Quo(multiplier)
+		resolved[result.Symbol] = time.Unix(seconds, 0)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for symbol, pair := range p.pairs {
+		base, found := rates[pair.Base]
+		if !found {
+			continue
+		}
+
+		quote, found := rates[pair.Quote]
+		if !found || quote.IsZero() {
+			continue
+		}
+
+		maxAge, found := p.maxAge[symbol]
+		if !found {
+			maxAge = bandDefaultMaxAge
+		}
+
+		stale := false
+		for _, denom := range []string{pair.Base, pair.Quote} {
+			age := now.Sub(resolved[denom])
+			if age > maxAge {
+				p.logger.Warn().
+					Str("symbol", denom).
+					Dur("age", age).
+					Dur("max_age", maxAge).
+					Msg("band price is stale")
+				stale = true
+			}
+		}
+
+		if stale {
+			continue
+		}
+
+		p.setTickerPrice(
+			symbol,
+			base.Quo(quote),
+			sdk.NewDec(1),
+			now,
+		)
+	}
+
+	p.logger.Debug().Msg("updated tickers")
+	return nil
+}
+
+func (p *BandProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	// The standard dataset doesn't offer a list of supported symbols,
+	// missing symbols are simply not part of the response.
+	return nil, nil
+}
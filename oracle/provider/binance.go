@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math/rand"
 	"time"
 
@@ -39,9 +40,20 @@ type (
 		Symbol    string `json:"symbol"`    // Symbol ex.: BTCUSDT
 		LastPrice string `json:"lastPrice"` // Last price ex.: 0.0025
 		Volume    string `json:"volume"`    // Total traded base asset volume ex.: 20
+		CloseTime int64  `json:"closeTime"` // Statistics close time in ms ex.: 1690000000000
 	}
+
+	// BinanceKline defines a single kline, ex.:
+	// [1499040000000, "0.01634790", "0.80000000", "0.01575800", "0.01577100",
+	// "148976.11427815", 1499644799999, ...]
+	BinanceKline []json.RawMessage
 )
 
+func init() {
+	register(ProviderBinance, binanceDefaultEndpoints, NewBinanceProvider)
+	register(ProviderBinanceUS, binanceUSDefaultEndpoints, NewBinanceProvider)
+}
+
 func NewBinanceProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -82,6 +94,7 @@ func NewBinanceProvider(
 	provider.setPairs(pairs, availablePairs, currencyPairToBinanceSymbol)
 
 	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	provider.startCandlePolling(provider)
 	return provider, nil
 }
 
@@ -108,7 +121,6 @@ func (p *BinanceProvider) Poll() error {
 
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
-	now := time.Now()
 
 	for _, ticker := range tickers {
 		if !p.isPair(ticker.Symbol) {
@@ -119,7 +131,7 @@ func (p *BinanceProvider) Poll() error {
 			ticker.Symbol,
 			strToDec(ticker.LastPrice),
 			strToDec(ticker.Volume),
-			now,
+			p.eventTime(time.UnixMilli(ticker.CloseTime)),
 		)
 	}
 
@@ -127,6 +139,58 @@ func (p *BinanceProvider) Poll() error {
 	return nil
 }
 
+// PollCandles polls the 1m klines of all pairs.
+func (p *BinanceProvider) PollCandles() error {
+	p.mtx.RLock()
+	pairs := p.getAllPairs()
+	p.mtx.RUnlock()
+
+	for symbol := range pairs {
+		content, err := p.httpGet(fmt.Sprintf(
+			"/api/v3/klines?symbol=%s&interval=1m&limit=%d", symbol, candleLimit,
+		))
+		if err != nil {
+			return err
+		}
+
+		var klines []BinanceKline
+		if err := json.Unmarshal(content, &klines); err != nil {
+			return err
+		}
+
+		candles := make([]types.CandlePrice, 0, len(klines))
+		for _, kline := range klines {
+			if len(kline) < 7 {
+				continue
+			}
+
+			var (
+				closePrice string
+				volume     string
+				closeTime  int64
+			)
+			if json.Unmarshal(kline[4], &closePrice) != nil ||
+				json.Unmarshal(kline[5], &volume) != nil ||
+				json.Unmarshal(kline[6], &closeTime) != nil {
+				continue
+			}
+
+			candles = append(candles, types.CandlePrice{
+				Price:     strToDec(closePrice),
+				Volume:    strToDec(volume),
+				TimeStamp: closeTime,
+			})
+		}
+
+		p.mtx.Lock()
+		p.setCandlePrices(symbol, candles)
+		p.mtx.Unlock()
+	}
+
+	p.logger.Debug().Msg("updated candles")
+	return nil
+}
+
 func (p *BinanceProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	tickers, err := p.getTickers()
 	if err != nil {
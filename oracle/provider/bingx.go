@@ -36,6 +36,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderBingx, bingxDefaultEndpoints, NewBingxProvider)
+}
+
 func NewBingxProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -30,6 +30,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderBitfinex, bitfinexDefaultEndpoints, NewBitfinexProvider)
+}
+
 func NewBitfinexProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
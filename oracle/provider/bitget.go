@@ -43,6 +43,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderBitget, bitgetDefaultEndpoints, NewBitgetProvider)
+}
+
 func NewBitgetProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -106,7 +110,7 @@ func (p *BitgetProvider) Poll() error {
 			ticker.Symbol,
 			strToDec(ticker.Price),
 			strToDec(ticker.Volume),
-			time.UnixMilli(timestamp),
+			p.eventTime(time.UnixMilli(timestamp)),
 		)
 	}
 	p.logger.Debug().Msg("updated tickers")
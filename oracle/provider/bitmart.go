@@ -44,6 +44,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderBitmart, bitmartDefaultEndpoints, NewBitmartProvider)
+}
+
 func NewBitmartProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -35,6 +35,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderBitstamp, bitstampDefaultEndpoints, NewBitstampProvider)
+}
+
 func NewBitstampProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
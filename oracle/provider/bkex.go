@@ -40,6 +40,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderBkex, bkexDefaultEndpoints, NewBkexProvider)
+}
+
 func NewBkexProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
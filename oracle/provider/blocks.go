@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// minBlockCheckInterval defines how often the latest height of a chain
+	// is queried at most.
+	minBlockCheckInterval = time.Second
+
+	// blockLogInterval limits the warnings about failing height queries.
+	blockLogInterval = time.Minute
+)
+
+type (
+	// blockPollingProvider defines a polling provider on a cosmos chain,
+	// whose state only changes with new blocks.
+	blockPollingProvider interface {
+		PollingProvider
+		getCosmosHeight() (uint64, error)
+		chainKey() string
+	}
+
+	// blockTracker decides, whether a block polling provider has to poll
+	// after observing the latest height.
+	blockTracker struct {
+		height uint64
+		polled time.Time
+	}
+)
+
+// observe returns true, if the height advanced since the last poll. If the
+// height can't be queried, it falls back to polling every interval.
+func (b *blockTracker) observe(
+	height uint64,
+	err error,
+	interval time.Duration,
+	now time.Time,
+) bool {
+	if err != nil || height == 0 {
+		if now.Sub(b.polled) < interval {
+			return false
+		}
+	} else if height <= b.height {
+		return false
+	} else {
+		b.height = height
+	}
+
+	b.polled = now
+	return true
+}
+
+// startBlockPolling polls the provider once right after every new block,
+// instead of on a fixed timer. This way every poll returns fresh state and
+// no requests are wasted while the chain produces blocks slowly. The height
+// is checked by a poller shared by all providers of the same chain.
+func startBlockPolling(
+	p blockPollingProvider,
+	interval time.Duration,
+	logger zerolog.Logger,
+) {
+	logger.Debug().Dur("interval", interval).Msg("starting block poll loop")
+
+	var tracker blockTracker
+	for update := range subscribeHeight(p, interval, logger) {
+		if !tracker.observe(update.height, update.err, interval, time.Now()) {
+			continue
+		}
+
+		err := p.Poll()
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to poll")
+		}
+
+		// height updates are dropped while waiting for the request budget
+		if limited, ok := p.(rateLimitedProvider); ok {
+			time.Sleep(limited.pollDelay(0, time.Now()))
+		}
+	}
+}
+
+type (
+	// heightUpdate is the result of a single height query.
+	heightUpdate struct {
+		height uint64
+		err    error
+	}
+
+	// heightPoller queries the latest height of a chain for all block
+	// polling providers on it.
+	heightPoller struct {
+		mtx         sync.Mutex
+		source      blockPollingProvider
+		interval    time.Duration
+		subscribers []chan heightUpdate
+		logger      zerolog.Logger
+		logged      time.Time
+	}
+)
+
+var (
+	heightPollersMtx sync.Mutex
+	// heightPollers contains the running height pollers by chain.
+	heightPollers = map[string]*heightPoller{}
+)
+
+// subscribeHeight returns the height updates of the chain of the provider.
+// The height is checked at the shortest poll interval of the providers on
+// the chain, but not more often than minBlockCheckInterval.
+func subscribeHeight(
+	p blockPollingProvider,
+	interval time.Duration,
+	logger zerolog.Logger,
+) <-chan heightUpdate {
+	if interval < minBlockCheckInterval {
+		interval = minBlockCheckInterval
+	}
+
+	heightPollersMtx.Lock()
+	defer heightPollersMtx.Unlock()
+
+	chain := p.chainKey()
+	poller, found := heightPollers[chain]
+	if !found {
+		poller = &heightPoller{
+			source:   p,
+			interval: interval,
+			logger:   logger,
+		}
+		heightPollers[chain] = poller
+		go poller.run()
+	}
+
+	return poller.subscribe(interval)
+}
+
+// subscribe adds a subscriber and lowers the check interval, if needed.
+func (h *heightPoller) subscribe(interval time.Duration) <-chan heightUpdate {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if interval < h.interval {
+		h.interval = interval
+	}
+
+	// a single pending update is enough, the subscribers only need the
+	// latest height
+	updates := make(chan heightUpdate, 1)
+	h.subscribers = append(h.subscribers, updates)
+	return updates
+}
+
+func (h *heightPoller) run() {
+	for {
+		h.check(time.Now())
+
+		h.mtx.Lock()
+		delay := h.interval
+		h.mtx.Unlock()
+
+		if limited, ok := h.source.(rateLimitedProvider); ok {
+			delay = limited.pollDelay(delay, time.Now())
+		}
+		time.Sleep(delay)
+	}
+}
+
+// check queries the latest height once and sends it to all subscribers,
+// that aren't busy polling. Failures are logged at most every
+// blockLogInterval.
+func (h *heightPoller) check(now time.Time) {
+	height, err := h.source.getCosmosHeight()
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if err != nil && now.Sub(h.logged) >= blockLogInterval {
+		h.logger.Warn().Err(err).Msg("failed to get height, polling by interval")
+		h.logged = now
+	}
+
+	update := heightUpdate{height: height, err: err}
+	for _, updates := range h.subscribers {
+		select {
+		case updates <- update:
+		default:
+		}
+	}
+}
+
+// chainKey identifies the chain of the provider, so providers on the same
+// chain share the height queries.
+func (p *provider) chainKey() string {
+	if p.endpoints.ChainId != "" {
+		return p.endpoints.ChainId
+	}
+	return strings.Join(p.endpoints.Urls, ",")
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockTracker(t *testing.T) {
+	var b blockTracker
+	now := time.Now()
+	interval := 6 * time.Second
+
+	// every new block is polled exactly once
+	require.True(t, b.observe(100, nil, interval, now))
+	require.False(t, b.observe(100, nil, interval, now.Add(time.Second)))
+	require.True(t, b.observe(101, nil, interval, now.Add(2*time.Second)))
+
+	// no polls while the chain is halted
+	require.False(t, b.observe(101, nil, interval, now.Add(time.Minute)))
+
+	// lagging endpoints don't trigger polls
+	require.False(t, b.observe(99, nil, interval, now.Add(time.Minute)))
+
+	// failing height queries fall back to the poll interval
+	err := fmt.Errorf("connection refused")
+	require.True(t, b.observe(0, err, interval, now.Add(time.Minute)))
+	require.False(t, b.observe(0, err, interval, now.Add(time.Minute+time.Second)))
+	require.True(t, b.observe(0, err, interval, now.Add(time.Minute+interval)))
+}
+
+type mockHeightSource struct {
+	blockPollingProvider
+	height  uint64
+	err     error
+	queries int
+}
+
+func (m *mockHeightSource) getCosmosHeight() (uint64, error) {
+	m.queries++
+	return m.height, m.err
+}
+
+func TestHeightPoller(t *testing.T) {
+	source := &mockHeightSource{height: 100}
+	h := &heightPoller{
+		source:   source,
+		interval: 6 * time.Second,
+		logger:   zerolog.Nop(),
+	}
+
+	first := h.subscribe(10 * time.Second)
+	second := h.subscribe(2 * time.Second)
+	require.Equal(t, 2*time.Second, h.interval)
+
+	// a single query serves all subscribers
+	now := time.Now()
+	h.check(now)
+	require.Equal(t, 1, source.queries)
+	require.Equal(t, heightUpdate{height: 100}, <-first)
+	require.Equal(t, heightUpdate{height: 100}, <-second)
+
+	// busy subscribers only get the latest pending update
+	source.height = 101
+	h.check(now.Add(time.Second))
+	h.check(now.Add(2 * time.Second))
+	require.Equal(t, heightUpdate{height: 101}, <-first)
+	require.Len(t, first, 0)
+
+	// failures are logged once per interval
+	source.err = fmt.Errorf("connection refused")
+	h.check(now.Add(3 * time.Second))
+	require.Equal(t, now.Add(3*time.Second), h.logged)
+	h.check(now.Add(4 * time.Second))
+	require.Equal(t, now.Add(3*time.Second), h.logged)
+	h.check(now.Add(3*time.Second + blockLogInterval))
+	require.Equal(t, now.Add(3*time.Second+blockLogInterval), h.logged)
+}
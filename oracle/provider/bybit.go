@@ -30,6 +30,7 @@ type (
 
 	BybitTickersResponse struct {
 		Result BybitTickersResult `json:"result"`
+		Time   int64              `json:"time"` // Server time in ms ex.: 1690000000000
 	}
 
 	BybitTickersResult struct {
@@ -37,12 +38,18 @@ type (
 	}
 
 	BybitTicker struct {
-		Symbol string `json:"symbol"`    // ex.: "LUNAUSDT"
-		Price  string `json:"lastPrice"` // ex.: "21127.86"
-		Volume string `json:"volume24h"` // ex.: "211.378621"
+		Symbol      string `json:"symbol"`      // ex.: "LUNAUSDT"
+		Price       string `json:"lastPrice"`   // ex.: "21127.86"
+		Volume      string `json:"volume24h"`   // ex.: "211.378621"
+		MarkPrice   string `json:"markPrice"`   // linear only, ex.: "21128.01"
+		FundingRate string `json:"fundingRate"` // linear only, ex.: "0.0001"
 	}
 )
 
+func init() {
+	register(ProviderBybit, bybitDefaultEndpoints, NewBybitProvider)
+}
+
 func NewBybitProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -67,7 +74,12 @@ func NewBybitProvider(
 }
 
 func (p *BybitProvider) getTickers() (BybitTickersResponse, error) {
-	content, err := p.httpGet("/v5/market/tickers?category=spot")
+	category := "spot"
+	if p.endpoints.Perp {
+		category = "linear"
+	}
+
+	content, err := p.httpGet("/v5/market/tickers?category=" + category)
 	if err != nil {
 		return BybitTickersResponse{}, err
 	}
@@ -82,24 +94,35 @@ func (p *BybitProvider) getTickers() (BybitTickersResponse, error) {
 }
 
 func (p *BybitProvider) Poll() error {
+	sent := time.Now()
 	tickersResponse, err := p.getTickers()
 	if err != nil {
 		return err
 	}
-
-	timestamp := time.Now()
+	received := time.Now()
 
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
 
+	p.observeServerTime(time.UnixMilli(tickersResponse.Time), sent, received)
+	timestamp := p.eventTime(time.UnixMilli(tickersResponse.Time))
+
 	for _, ticker := range tickersResponse.Result.List {
 		if !p.isPair(ticker.Symbol) {
 			continue
 		}
 
+		price := ticker.Price
+		if p.endpoints.Perp {
+			if p.extremeFunding(ticker.Symbol, strToDec(ticker.FundingRate)) {
+				continue
+			}
+			price = ticker.MarkPrice
+		}
+
 		p.setTickerPrice(
 			ticker.Symbol,
-			strToDec(ticker.Price),
+			strToDec(price),
 			strToDec(ticker.Volume),
 			timestamp,
 		)
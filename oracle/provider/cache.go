@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"time"
+
+	"price-feeder/oracle/types"
+)
+
+// CacheTrimmer defines a provider, which drops its cached data on memory
+// pressure. The caches refill with the next updates.
+type CacheTrimmer interface {
+	TrimCaches()
+}
+
+// TrimCaches drops the candles, the trade windows and all tickers too stale
+// to be served anyway.
+func (p *provider) TrimCaches() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for symbol, ticker := range p.tickers {
+		if time.Since(ticker.Time) > staleTickersCutoff {
+			delete(p.tickers, symbol)
+			delete(p.eventTimes, symbol)
+		}
+	}
+
+	p.candles = map[string][]types.CandlePrice{}
+	p.trades = map[string][]trade{}
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimCaches(t *testing.T) {
+	p := provider{
+		tickers: map[string]types.TickerPrice{
+			"ATOMUSDT": {Price: sdk.NewDec(10), Time: time.Now()},
+			"BTCUSDT":  {Price: sdk.NewDec(40000), Time: time.Now().Add(-2 * staleTickersCutoff)},
+		},
+		eventTimes: map[string]time.Time{
+			"BTCUSDT": time.Now().Add(-2 * staleTickersCutoff),
+		},
+		candles: map[string][]types.CandlePrice{
+			"ATOMUSDT": {{Price: sdk.NewDec(10)}},
+		},
+		trades: map[string][]trade{
+			"ATOMUSDT": {{price: sdk.NewDec(10), amount: sdk.NewDec(1)}},
+		},
+	}
+
+	p.TrimCaches()
+
+	require.Len(t, p.tickers, 1)
+	require.Contains(t, p.tickers, "ATOMUSDT")
+	require.Empty(t, p.eventTimes)
+	require.Empty(t, p.candles)
+	require.Empty(t, p.trades)
+}
@@ -23,6 +23,8 @@ var (
 		PollInterval: 15 * time.Second,
 		VolumeBlocks: 1,
 		VolumePause:  0,
+		ChainId:      "42161",
+		MaxBlockLag:  40,
 	}
 
 	camelotV3DefaultEndpoints = Endpoint{
@@ -31,6 +33,8 @@ var (
 		PollInterval: 15 * time.Second,
 		VolumeBlocks: 1,
 		VolumePause:  0,
+		ChainId:      "42161",
+		MaxBlockLag:  40,
 	}
 )
 
@@ -44,9 +48,15 @@ type (
 		//   ["int256", "int256", "uint160", "uint128", "int24"]
 		topics   map[string][]string
 		decimals map[string]uint64
+		tokens   map[string]string
 	}
 )
 
+func init() {
+	registerWithDB(ProviderCamelotV2, camelotV2DefaultEndpoints, NewCamelotProvider)
+	registerWithDB(ProviderCamelotV3, camelotV3DefaultEndpoints, NewCamelotProvider)
+}
+
 func NewCamelotProvider(
 	db *sql.DB,
 	ctx context.Context,
@@ -108,7 +118,7 @@ func (p *CamelotProvider) Poll() error {
 
 	contracts := []string{}
 	for symbol := range p.getAllPairs() {
-		contract, found := p.contracts[symbol]
+		contract, found := p.contracts.Contract(symbol)
 		if !found {
 			continue
 		}
@@ -151,7 +161,7 @@ func (p *CamelotProvider) Poll() error {
 	defer p.mtx.Unlock()
 
 	for _, contract := range contracts {
-		symbol := p.contracts[contract]
+		symbol, _ := p.contracts.Symbol(contract)
 
 		pair, found := p.getPair(symbol)
 		if !found {
@@ -198,25 +208,16 @@ func (p *CamelotProvider) Poll() error {
 
 		price = price.Mul(factor)
 
-		var volume sdk.Dec
-		// hack to get the proper volume
-		_, found = p.inverse[symbol]
-		if found {
-			volume, _ = p.volumes.Get(pair.Quote + pair.Base)
-
-			if !volume.IsZero() {
-				volume = volume.Quo(price)
-			}
-		} else {
-			volume, _ = p.volumes.Get(pair.String())
+		liquidity, err := p.getEthPoolLiquidity(
+			contract, p.tokens[pair.Base], p.tokens[pair.Quote],
+			decimals1, decimals2, price,
+		)
+		if err != nil {
+			p.logger.Warn().Err(err).Str("symbol", symbol).Msg("failed to get liquidity")
 		}
+		p.setLiquidity(symbol, liquidity)
 
-		p.setTickerPrice(
-			symbol,
-			price,
-			volume,
-			timestamp,
-		)
+		p.setTickerPriceFromVolumes(symbol, price, timestamp)
 	}
 
 	return nil
@@ -224,19 +225,21 @@ func (p *CamelotProvider) Poll() error {
 
 func (p *CamelotProvider) init() error {
 	p.decimals = map[string]uint64{}
+	p.tokens = map[string]string{}
 	types := []string{"address"}
 
 	for symbol, pair := range p.getAllPairs() {
 		logger := p.logger.With().Str("symbol", symbol).Logger()
 		logger.Info().Msg("get decimals")
 
-		contract, found := p.contracts[symbol]
+		contract, found := p.contracts.Contract(symbol)
 		if !found {
 			logger.Warn().Msg("contract not found")
 			continue
 		}
 
 		decimals := make([]uint64, 2)
+		tokens := make([]string, 2)
 
 		for i, method := range []string{"token0()", "token1()"} {
 			response, err := p.evmCall(contract, method, nil)
@@ -255,6 +258,7 @@ func (p *CamelotProvider) init() error {
 				return p.error(err)
 			}
 			token := fmt.Sprintf("%v", decoded[0])
+			tokens[i] = token
 
 			decimals[i], err = p.getEthDecimals(token)
 			if err != nil {
@@ -264,6 +268,8 @@ func (p *CamelotProvider) init() error {
 
 		p.decimals[pair.Base] = decimals[0]
 		p.decimals[pair.Quote] = decimals[1]
+		p.tokens[pair.Base] = tokens[0]
+		p.tokens[pair.Quote] = tokens[1]
 	}
 
 	return nil
@@ -326,7 +332,7 @@ func (p *CamelotProvider) updateVolumes(
 	}
 
 	for _, log := range logs {
-		symbol, found := p.contracts[log.Address]
+		symbol, found := p.contracts.Symbol(log.Address)
 		if !found {
 			p.logger.Warn().Str("contract", log.Address).Msg("symbol not found")
 			continue
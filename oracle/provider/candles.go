@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"sort"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// candleInterval defines the interval of the polled candles and how
+	// often they are polled.
+	candleInterval = time.Minute
+	// candleLimit defines the number of candles requested per poll, enough
+	// to cover providerCandlePeriod.
+	candleLimit = 10
+)
+
+// CandlePollingProvider defines a provider, which polls the OHLCV candles of
+// its pairs. Candles are only polled if enabled in the endpoint config.
+type CandlePollingProvider interface {
+	PollCandles() error
+}
+
+// startCandlePolling polls the candles of the provider every candleInterval
+// until the provider context is done.
+func (p *provider) startCandlePolling(c CandlePollingProvider) {
+	if !p.endpoints.Candles {
+		return
+	}
+
+	go func() {
+		p.logger.Debug().Dur("interval", candleInterval).Msg("starting candle poll loop")
+
+		ticker := time.NewTicker(candleInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := c.PollCandles(); err != nil {
+				p.logger.Error().Err(err).Msg("failed to poll candles")
+			}
+
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// setCandlePrices replaces the candles of the provider symbol. Candles of
+// inverted symbols are inverted like tickers, candles older than
+// providerCandlePeriod are dropped.
+func (p *provider) setCandlePrices(symbol string, candles []types.CandlePrice) {
+	cutoff := PastUnixTime(providerCandlePeriod)
+
+	pair, inverse := p.inverse[symbol]
+	if !inverse {
+		var found bool
+		pair, found = p.pairs[symbol]
+		if !found {
+			p.logger.Debug().
+				Str("symbol", symbol).
+				Msg("symbol not found")
+			return
+		}
+	}
+
+	filtered := make([]types.CandlePrice, 0, len(candles))
+	for _, candle := range candles {
+		if candle.TimeStamp < cutoff || candle.Price.IsNil() || !candle.Price.IsPositive() {
+			continue
+		}
+		if candle.Volume.IsNil() {
+			candle.Volume = sdk.ZeroDec()
+		}
+		if inverse {
+			candle = types.CandlePrice{
+				Price:     invertDec(candle.Price),
+				Volume:    candle.Volume.Mul(candle.Price),
+				TimeStamp: candle.TimeStamp,
+			}
+		}
+		filtered = append(filtered, candle)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].TimeStamp < filtered[j].TimeStamp
+	})
+
+	p.candles[pair.String()] = filtered
+}
+
+// GetCandlePrices returns the candles of the pairs, that aren't older than
+// providerCandlePeriod. Pairs without recent candles are left out.
+func (p *provider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]types.CandlePrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	cutoff := PastUnixTime(providerCandlePeriod)
+
+	candles := make(map[string][]types.CandlePrice, len(pairs))
+	for _, pair := range pairs {
+		symbol := pair.String()
+
+		recent := []types.CandlePrice{}
+		for _, candle := range p.candles[symbol] {
+			if candle.TimeStamp >= cutoff {
+				recent = append(recent, candle)
+			}
+		}
+
+		if len(recent) == 0 {
+			if p.endpoints.Candles {
+				p.logger.Debug().Str("pair", symbol).Msg("no recent candles")
+			}
+			continue
+		}
+
+		candles[symbol] = recent
+	}
+
+	return candles, nil
+}
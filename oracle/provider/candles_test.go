@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCandlePrices(t *testing.T) {
+	p := provider{
+		endpoints: Endpoint{Name: ProviderMock, Candles: true},
+		logger:    zerolog.Nop(),
+		pairs: map[string]types.CurrencyPair{
+			"ATOMUSDT": testAtomUsdtCurrencyPair,
+		},
+		inverse: map[string]types.CurrencyPair{
+			"USDTBTC": testBtcUsdtCurrencyPair,
+		},
+		candles: map[string][]types.CandlePrice{},
+	}
+
+	now := time.Now()
+
+	p.setCandlePrices("ATOMUSDT", []types.CandlePrice{
+		{Price: sdk.NewDec(11), Volume: sdk.NewDec(5), TimeStamp: now.UnixMilli()},
+		{Price: sdk.NewDec(10), Volume: sdk.NewDec(7), TimeStamp: now.Add(-time.Minute).UnixMilli()},
+		// stale
+		{Price: sdk.NewDec(9), Volume: sdk.NewDec(1), TimeStamp: now.Add(-time.Hour).UnixMilli()},
+	})
+
+	// 1 USDT = 0.00002 BTC, 50000 USDT traded
+	p.setCandlePrices("USDTBTC", []types.CandlePrice{
+		{Price: sdk.MustNewDecFromStr("0.00002"), Volume: sdk.NewDec(50000), TimeStamp: now.UnixMilli()},
+	})
+
+	// unknown symbol
+	p.setCandlePrices("OSMOUSDT", []types.CandlePrice{
+		{Price: sdk.NewDec(1), Volume: sdk.NewDec(1), TimeStamp: now.UnixMilli()},
+	})
+
+	candles, err := p.GetCandlePrices(
+		testAtomUsdtCurrencyPair,
+		testBtcUsdtCurrencyPair,
+		types.CurrencyPair{Base: "OSMO", Quote: "USDT"},
+	)
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+
+	atom := candles["ATOMUSDT"]
+	require.Len(t, atom, 2)
+	require.Equal(t, sdk.NewDec(10), atom[0].Price)
+	require.Equal(t, sdk.NewDec(11), atom[1].Price)
+
+	btc := candles["BTCUSDT"]
+	require.Len(t, btc, 1)
+	require.Equal(t, "50000.000000000000000000", btc[0].Price.String())
+	require.Equal(t, "1.000000000000000000", btc[0].Volume.String())
+}
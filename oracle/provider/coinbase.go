@@ -39,6 +39,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderCoinbase, coinbaseDefaultEndpoints, NewCoinbaseProvider)
+}
+
 func NewCoinbaseProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
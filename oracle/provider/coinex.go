@@ -40,6 +40,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderCoinex, coinexDefaultEndpoints, NewCoinexProvider)
+}
+
 func NewCoinexProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -0,0 +1,295 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+)
+
+var (
+	_                         Provider = (*CoingeckoProvider)(nil)
+	coingeckoDefaultEndpoints          = Endpoint{
+		Name: ProviderCoingecko,
+		Urls: []string{
+			"https://api.coingecko.com/api/v3",
+		},
+		// the public api allows about 30 requests per minute
+		PollInterval: 30 * time.Second,
+	}
+
+	// coingeckoDefaultIds maps common denoms to their coingecko coin ids,
+	// other denoms are configured in contract_addresses.coingecko.
+	coingeckoDefaultIds = map[string]string{
+		"ATOM": "cosmos",
+		"BTC":  "bitcoin",
+		"ETH":  "ethereum",
+		"KUJI": "kujira",
+		"OSMO": "osmosis",
+		"USDC": "usd-coin",
+		"USDT": "tether",
+		"USK":  "usk",
+	}
+)
+
+type (
+	// CoingeckoProvider defines an oracle provider that uses the aggregated
+	// USD prices of coingecko. It's meant as fallback for long-tail assets
+	// without a direct CEX listing. The api key is sent as demo key, or as
+	// pro key for the pro api.
+	//
+	// REF: https://docs.coingecko.com/reference/coins-markets
+	// REF: https://docs.coingecko.com/reference/simple-price
+	CoingeckoProvider struct {
+		provider
+	}
+
+	CoingeckoMarket struct {
+		Id           string  `json:"id"`
+		CurrentPrice float64 `json:"current_price"`
+		TotalVolume  float64 `json:"total_volume"` // 24h volume in USD
+		LastUpdated  string  `json:"last_updated"`
+	}
+
+	CoingeckoSimplePrice struct {
+		Usd           float64 `json:"usd"`
+		Usd24hVol     float64 `json:"usd_24h_vol"`
+		LastUpdatedAt int64   `json:"last_updated_at"`
+	}
+
+	// coingeckoRate defines the USD price of a coin with its 24h volume in
+	// coins.
+	coingeckoRate struct {
+		price  sdk.Dec
+		volume sdk.Dec
+		time   time.Time
+	}
+)
+
+func init() {
+	register(ProviderCoingecko, coingeckoDefaultEndpoints, NewCoingeckoProvider)
+}
+
+func NewCoingeckoProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*CoingeckoProvider, error) {
+	provider := &CoingeckoProvider{}
+	provider.Init(
+		ctx,
+		endpoints,
+		logger,
+		pairs,
+		nil,
+		nil,
+	)
+
+	availablePairs, _ := provider.GetAvailablePairs()
+	provider.setPairs(pairs, availablePairs, nil)
+
+	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	return provider, nil
+}
+
+func (p *CoingeckoProvider) Poll() error {
+	ids := map[string]string{}
+	for _, pair := range p.getAllPairs() {
+		for _, denom := range []string{pair.Base, pair.Quote} {
+			if denom != "USD" {
+				ids[denom] = p.getCoinId(denom)
+			}
+		}
+	}
+
+	rates, err := p.getRates(ids)
+	if err != nil {
+		return err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for symbol, pair := range p.pairs {
+		base, found := rates[pair.Base]
+		if !found {
+			continue
+		}
+
+		price := base.price
+		if pair.Quote != "USD" {
+			quote, found := rates[pair.Quote]
+			if !found || !quote.price.IsPositive() {
+				continue
+			}
+			price = price.Quo(quote.price)
+		}
+
+		p.setTickerPrice(symbol, price, base.volume, base.time)
+	}
+
+	p.logger.Debug().Msg("updated tickers")
+	return nil
+}
+
+// getRates returns the USD rates by denom. The markets endpoint is queried
+// first, coins without market data are queried from the simple price
+// endpoint.
+func (p *CoingeckoProvider) getRates(ids map[string]string) (map[string]coingeckoRate, error) {
+	rates := map[string]coingeckoRate{}
+	if len(ids) == 0 {
+		return rates, nil
+	}
+
+	denoms := map[string][]string{}
+	for denom, id := range ids {
+		denoms[id] = append(denoms[id], denom)
+	}
+
+	markets, err := p.getMarkets(sortedKeys(denoms))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, market := range markets {
+		price := floatToDec(market.CurrentPrice)
+		if price.IsNil() || !price.IsPositive() {
+			continue
+		}
+
+		timestamp, err := time.Parse(time.RFC3339, market.LastUpdated)
+		if err != nil {
+			p.logger.Debug().Err(err).Str("id", market.Id).Msg("invalid timestamp")
+			continue
+		}
+
+		for _, denom := range denoms[market.Id] {
+			rates[denom] = coingeckoRate{
+				price:  price,
+				volume: floatToDec(market.TotalVolume).Quo(price),
+				time:   timestamp,
+			}
+		}
+		delete(denoms, market.Id)
+	}
+
+	if len(denoms) == 0 {
+		return rates, nil
+	}
+
+	prices, err := p.getSimplePrices(sortedKeys(denoms))
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("failed to get simple prices")
+		return rates, nil
+	}
+
+	for id, simple := range prices {
+		price := floatToDec(simple.Usd)
+		if price.IsNil() || !price.IsPositive() {
+			continue
+		}
+
+		for _, denom := range denoms[id] {
+			rates[denom] = coingeckoRate{
+				price:  price,
+				volume: floatToDec(simple.Usd24hVol).Quo(price),
+				time:   time.Unix(simple.LastUpdatedAt, 0),
+			}
+		}
+		delete(denoms, id)
+	}
+
+	for id := range denoms {
+		p.logger.Warn().Str("id", id).Msg("no coingecko price")
+	}
+
+	return rates, nil
+}
+
+func (p *CoingeckoProvider) getMarkets(ids []string) ([]CoingeckoMarket, error) {
+	query := url.Values{}
+	query.Set("vs_currency", "usd")
+	query.Set("ids", strings.Join(ids, ","))
+	query.Set("per_page", "250")
+
+	content, err := p.httpRequest("/coins/markets?"+query.Encode(), "GET", nil, p.apiKeyHeader())
+	if err != nil {
+		return nil, err
+	}
+
+	var markets []CoingeckoMarket
+	err = json.Unmarshal(content, &markets)
+	if err != nil {
+		return nil, err
+	}
+
+	return markets, nil
+}
+
+func (p *CoingeckoProvider) getSimplePrices(ids []string) (map[string]CoingeckoSimplePrice, error) {
+	query := url.Values{}
+	query.Set("vs_currencies", "usd")
+	query.Set("ids", strings.Join(ids, ","))
+	query.Set("include_24hr_vol", "true")
+	query.Set("include_last_updated_at", "true")
+
+	content, err := p.httpRequest("/simple/price?"+query.Encode(), "GET", nil, p.apiKeyHeader())
+	if err != nil {
+		return nil, err
+	}
+
+	var prices map[string]CoingeckoSimplePrice
+	err = json.Unmarshal(content, &prices)
+	if err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// apiKeyHeader returns the header of the configured api key. Keys of the
+// pro api are sent as pro keys, all others as demo keys.
+func (p *CoingeckoProvider) apiKeyHeader() map[string]string {
+	if p.endpoints.ApiKey == "" {
+		return nil
+	}
+	if strings.Contains(p.getHttpBase(), "pro-api.coingecko.com") {
+		return map[string]string{"x-cg-pro-api-key": p.endpoints.ApiKey}
+	}
+	return map[string]string{"x-cg-demo-api-key": p.endpoints.ApiKey}
+}
+
+func (p *CoingeckoProvider) getCoinId(denom string) string {
+	id, found := p.contracts.Contract(denom)
+	if found {
+		return id
+	}
+	id, found = coingeckoDefaultIds[denom]
+	if found {
+		return id
+	}
+	return strings.ToLower(denom)
+}
+
+func (p *CoingeckoProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	// Coin ids are configured, unknown ids are simply missing in the
+	// response.
+	return nil, nil
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
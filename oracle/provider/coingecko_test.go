@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoingeckoGetRates(t *testing.T) {
+	var apiKey, marketIds, simpleIds string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("x-cg-demo-api-key")
+
+		switch r.URL.Path {
+		case "/coins/markets":
+			marketIds = r.URL.Query().Get("ids")
+			fmt.Fprint(w, `[
+				{"id":"bitcoin","current_price":30000,"total_volume":3000000,"last_updated":"2023-10-01T12:00:00.000Z"},
+				{"id":"kujira","current_price":0.5,"total_volume":100000,"last_updated":"2023-10-01T12:00:00.000Z"}
+			]`)
+		case "/simple/price":
+			simpleIds = r.URL.Query().Get("ids")
+			fmt.Fprint(w, `{"tiny-coin":{"usd":0.02,"usd_24h_vol":200,"last_updated_at":1696161600}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := CoingeckoProvider{
+		provider: provider{
+			logger:    zerolog.Nop(),
+			http:      newDefaultHTTPClient(),
+			httpBase:  server.URL,
+			endpoints: Endpoint{Urls: []string{server.URL}, ApiKey: "key"},
+		},
+	}
+
+	rates, err := p.getRates(map[string]string{
+		"BTC":  p.getCoinId("BTC"),
+		"KUJI": p.getCoinId("KUJI"),
+		"TINY": "tiny-coin",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "key", apiKey)
+	require.Equal(t, "bitcoin,kujira,tiny-coin", marketIds)
+	require.Equal(t, "tiny-coin", simpleIds)
+
+	require.Len(t, rates, 3)
+	require.True(t, sdk.NewDec(30000).Equal(rates["BTC"].price))
+	require.True(t, sdk.NewDec(100).Equal(rates["BTC"].volume))
+	require.True(t, sdk.NewDec(200000).Equal(rates["KUJI"].volume))
+	require.True(t, sdk.MustNewDecFromStr("0.02").Equal(rates["TINY"].price))
+	require.True(t, sdk.NewDec(10000).Equal(rates["TINY"].volume))
+	require.Equal(t, time.Unix(1696161600, 0), rates["TINY"].time)
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+const (
+	// WebsocketCompressionPerMessage negotiates the permessage-deflate
+	// extension, the frames are decompressed by the websocket library.
+	WebsocketCompressionPerMessage = "permessage-deflate"
+	// WebsocketCompressionGzip decompresses binary frames sent as gzip
+	// streams, e.g. by huobi.
+	WebsocketCompressionGzip = "gzip"
+	// WebsocketCompressionDeflate decompresses binary frames sent as raw
+	// deflate streams, e.g. by the legacy okx streams.
+	WebsocketCompressionDeflate = "deflate"
+
+	// maxDecompressedMessageSize limits the size of a decompressed message.
+	maxDecompressedMessageSize = 16 << 20
+)
+
+// IsWebsocketCompression returns true, if the compression is supported.
+func IsWebsocketCompression(compression string) bool {
+	switch compression {
+	case WebsocketCompressionPerMessage, WebsocketCompressionGzip, WebsocketCompressionDeflate:
+		return true
+	}
+	return false
+}
+
+// decompressMessage decompresses a binary websocket message sent as gzip or
+// raw deflate stream.
+func decompressMessage(compression string, bz []byte) ([]byte, error) {
+	var reader io.ReadCloser
+	switch compression {
+	case WebsocketCompressionGzip:
+		gzipReader, err := gzip.NewReader(bytes.NewReader(bz))
+		if err != nil {
+			return nil, err
+		}
+		reader = gzipReader
+	case WebsocketCompressionDeflate:
+		reader = flate.NewReader(bytes.NewReader(bz))
+	default:
+		return bz, nil
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxDecompressedMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(decompressed) > maxDecompressedMessageSize {
+		return nil, fmt.Errorf("decompressed message exceeds %d bytes", maxDecompressedMessageSize)
+	}
+
+	return decompressed, nil
+}
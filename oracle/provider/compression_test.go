@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecompressMessage(t *testing.T) {
+	message := []byte(`{"ch":"market.atomusdt.ticker","tick":{"close":12.3456}}`)
+
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	_, err := gzipWriter.Write(message)
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	var deflated bytes.Buffer
+	flateWriter, err := flate.NewWriter(&deflated, flate.DefaultCompression)
+	require.NoError(t, err)
+	_, err = flateWriter.Write(message)
+	require.NoError(t, err)
+	require.NoError(t, flateWriter.Close())
+
+	bz, err := decompressMessage(WebsocketCompressionGzip, gzipped.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, message, bz)
+
+	bz, err = decompressMessage(WebsocketCompressionDeflate, deflated.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, message, bz)
+
+	// uncompressed streams are passed through
+	bz, err = decompressMessage(WebsocketCompressionPerMessage, message)
+	require.NoError(t, err)
+	require.Equal(t, message, bz)
+
+	_, err = decompressMessage(WebsocketCompressionGzip, message)
+	require.Error(t, err)
+
+	// binary frames are decompressed before the message handler
+	var received []byte
+	c := &WebsocketController{
+		providerName: ProviderMock,
+		compression:  WebsocketCompressionGzip,
+		messageHandler: func(_ int, bz []byte) {
+			received = bz
+		},
+	}
+	c.readSuccess(websocket.BinaryMessage, gzipped.Bytes())
+	require.Equal(t, message, received)
+}
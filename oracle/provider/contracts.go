@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ContractRegistry maps provider symbols to contract addresses (or pool ids,
+// feed ids, etc.) and back. Both directions are kept in separate maps, so a
+// symbol can never collide with a contract address.
+type ContractRegistry struct {
+	contracts map[string]string // symbol -> contract
+	symbols   map[string]string // contract -> symbol
+}
+
+// NewContractRegistry returns a registry for the provided symbol -> contract
+// mapping. It returns an error if a contract is assigned to more than one
+// symbol, in which case the contract is only mapped to the first symbol in
+// alphabetical order.
+func NewContractRegistry(addresses map[string]string) (ContractRegistry, error) {
+	r := ContractRegistry{
+		contracts: make(map[string]string, len(addresses)),
+		symbols:   make(map[string]string, len(addresses)),
+	}
+
+	symbols := make([]string, 0, len(addresses))
+	for symbol := range addresses {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var err error
+	for _, symbol := range symbols {
+		contract := addresses[symbol]
+
+		existing, found := r.symbols[contract]
+		if found {
+			err = fmt.Errorf(
+				"contract %s used for %s and %s", contract, existing, symbol,
+			)
+			continue
+		}
+
+		r.contracts[symbol] = contract
+		r.symbols[contract] = symbol
+	}
+
+	return r, err
+}
+
+// Contract returns the contract of the provider symbol.
+func (r ContractRegistry) Contract(symbol string) (string, bool) {
+	contract, found := r.contracts[symbol]
+	return contract, found
+}
+
+// Symbol returns the provider symbol of the contract.
+func (r ContractRegistry) Symbol(contract string) (string, bool) {
+	symbol, found := r.symbols[contract]
+	return symbol, found
+}
+
+// Contracts returns a copy of the symbol -> contract mapping.
+func (r ContractRegistry) Contracts() map[string]string {
+	contracts := make(map[string]string, len(r.contracts))
+	for symbol, contract := range r.contracts {
+		contracts[symbol] = contract
+	}
+	return contracts
+}
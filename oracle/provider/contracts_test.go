@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractRegistry(t *testing.T) {
+	registry, err := NewContractRegistry(map[string]string{
+		"ATOMUSDC": "1",
+		"KUJIUSDC": "ATOMUSDC",
+	})
+	require.NoError(t, err)
+
+	contract, found := registry.Contract("ATOMUSDC")
+	require.True(t, found)
+	require.Equal(t, "1", contract)
+
+	symbol, found := registry.Symbol("ATOMUSDC")
+	require.True(t, found)
+	require.Equal(t, "KUJIUSDC", symbol)
+
+	_, found = registry.Symbol("KUJIUSDC")
+	require.False(t, found)
+
+	registry, err = NewContractRegistry(map[string]string{
+		"ATOMUSDC": "1",
+		"USDCATOM": "1",
+	})
+	require.Error(t, err)
+
+	symbol, found = registry.Symbol("1")
+	require.True(t, found)
+	require.Equal(t, "ATOMUSDC", symbol)
+	require.Len(t, registry.Contracts(), 1)
+}
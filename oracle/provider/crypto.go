@@ -45,6 +45,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderCrypto, cryptoDefaultEndpoints, NewCryptoProvider)
+}
+
 func NewCryptoProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
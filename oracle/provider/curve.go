@@ -18,15 +18,29 @@ var (
 		Urls:         []string{"https://api.curve.fi"},
 		PollInterval: 10 * time.Second,
 	}
+
+	// curveRegistries defines the pool registries queried via the api,
+	// including the crypto (non-stable) and NG factories.
+	curveRegistries = []string{
+		"main",
+		"crypto",
+		"factory",
+		"factory-crypto",
+		"factory-tricrypto",
+		"factory-twocrypto",
+		"factory-stable-ng",
+	}
 )
 
 type (
 	// CurveProvider defines an oracle provider implemented by the curve.fi
-	// public API.
+	// public API. If an evm chain id is configured, the configured pools are
+	// queried on chain instead, see curve_onchain.go.
 	//
 	// REF: https://github.com/curvefi/curve-api
 	CurveProvider struct {
 		provider
+		pools map[string]curvePool
 	}
 
 	CurvePoolsResponse struct {
@@ -39,8 +53,9 @@ type (
 	}
 
 	CurvePoolData struct {
-		Address string      `json:"address"`
-		Coins   []CurveCoin `json:"coins"`
+		Address  string      `json:"address"`
+		Coins    []CurveCoin `json:"coins"`
+		UsdTotal float64     `json:"usdTotal"`
 	}
 
 	CurveCoin struct {
@@ -64,6 +79,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderCurve, curveDefaultEndpoints, NewCurveProvider)
+}
+
 func NewCurveProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -83,11 +102,19 @@ func NewCurveProvider(
 	availablePairs, _ := provider.GetAvailablePairs()
 	provider.setPairs(pairs, availablePairs, nil)
 
+	if provider.isOnchain() {
+		provider.initPools()
+	}
+
 	go startPolling(provider, provider.endpoints.PollInterval, logger)
 	return provider, nil
 }
 
 func (p *CurveProvider) Poll() error {
+	if p.isOnchain() {
+		return p.pollOnchain()
+	}
+
 	// get subgraph data, which provides 24h volume data
 	// https://api.curve.fi/api/getSubgraphData/ethereum
 
@@ -114,7 +141,7 @@ func (p *CurveProvider) Poll() error {
 
 	pools := []CurvePoolData{}
 
-	for _, registryID := range []string{"main", "crypto", "factory"} {
+	for _, registryID := range curveRegistries {
 		path := "/api/getPools/ethereum/" + registryID
 		content, err = p.httpGet(path)
 		if err != nil {
@@ -152,6 +179,7 @@ func (p *CurveProvider) Poll() error {
 				continue
 			}
 
+			p.setLiquidity(denom+"USD", floatToDec(pool.UsdTotal))
 			p.setTickerPrice(
 				denom+"USD",
 				floatToDec(coin.Price),
@@ -167,7 +195,14 @@ func (p *CurveProvider) Poll() error {
 func (p *CurveProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	symbols := map[string]struct{}{}
 
-	for _, registryID := range []string{"main", "crypto", "factory"} {
+	if p.isOnchain() {
+		for symbol := range p.contracts.Contracts() {
+			symbols[symbol] = struct{}{}
+		}
+		return symbols, nil
+	}
+
+	for _, registryID := range curveRegistries {
 		path := "/api/getPools/ethereum/" + registryID
 		content, err := p.httpGet(path)
 		if err != nil {
@@ -0,0 +1,310 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const curveMaxCoins = 8
+
+type (
+	// curvePool defines a curve pool queried on chain. Prices returned by
+	// price_oracle() and last_prices() are denominated in coins[0] and
+	// scaled by 1e18, independent of the token decimals.
+	curvePool struct {
+		address string
+		// indexed pools (tricrypto-ng, stableswap-ng) take the coin index
+		// minus one as argument, two coin crypto pools take none
+		indexed bool
+		// method is either price_oracle or last_prices
+		method string
+		base   int
+		quote  int
+		// decimals of the base and quote coin, unknown decimals skip the
+		// liquidity
+		decimals []uint64
+	}
+)
+
+func (p *CurveProvider) isOnchain() bool {
+	return isEvmChainId(p.endpoints.ChainId)
+}
+
+// initPools detects the type and coin indexes of all configured pools.
+func (p *CurveProvider) initPools() {
+	p.pools = map[string]curvePool{}
+
+	for symbol, pair := range p.getAllPairs() {
+		logger := p.logger.With().Str("symbol", symbol).Logger()
+
+		contract, found := p.contracts.Contract(symbol)
+		if !found {
+			logger.Warn().Msg("contract not found")
+			continue
+		}
+
+		pool, err := p.detectPool(contract)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to detect pool type")
+			continue
+		}
+
+		symbols, err := p.getCoinSymbols(contract)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get pool coins")
+			continue
+		}
+
+		pool.base = curveCoinIndex(symbols, pair.Base)
+		pool.quote = curveCoinIndex(symbols, pair.Quote)
+		if pool.base < 0 || pool.quote < 0 {
+			logger.Error().
+				Strs("coins", symbols).
+				Msg("pair not found in pool coins")
+			continue
+		}
+
+		for _, index := range []int{pool.base, pool.quote} {
+			decimals, err := p.coinDecimals(contract, index)
+			if err != nil {
+				logger.Warn().Err(err).Msg("failed to get coin decimals")
+				pool.decimals = nil
+				break
+			}
+			pool.decimals = append(pool.decimals, decimals)
+		}
+
+		logger.Info().
+			Str("method", pool.method).
+			Bool("indexed", pool.indexed).
+			Msg("detected pool type")
+
+		p.pools[symbol] = pool
+	}
+}
+
+// detectPool tries the known price getters, preferring the EMA price
+// oracle over the last traded prices.
+func (p *CurveProvider) detectPool(contract string) (curvePool, error) {
+	for _, method := range []string{"price_oracle", "last_prices"} {
+		// two coin crypto pools
+		_, err := p.curveCall(contract, method+"()", nil)
+		if err == nil {
+			return curvePool{address: contract, method: method}, nil
+		}
+
+		// tricrypto-ng and stableswap-ng pools
+		_, err = p.curveCall(contract, method+"(uint256)", []string{curveArg(0)})
+		if err == nil {
+			return curvePool{address: contract, method: method, indexed: true}, nil
+		}
+	}
+
+	return curvePool{}, fmt.Errorf("no price getter found, stableswap pools are not supported")
+}
+
+// coinDecimals returns the erc20 decimals of the pool coin.
+func (p *CurveProvider) coinDecimals(contract string, index int) (uint64, error) {
+	result, err := p.curveCall(contract, "coins(uint256)", []string{curveArg(index)})
+	if err != nil {
+		return 0, err
+	}
+
+	decoded, err := decodeEthData(result, []string{"address"})
+	if err != nil {
+		return 0, err
+	}
+
+	return p.getEthDecimals(fmt.Sprintf("%v", decoded[0]))
+}
+
+// getCoinSymbols returns the erc20 symbols of all pool coins.
+func (p *CurveProvider) getCoinSymbols(contract string) ([]string, error) {
+	symbols := []string{}
+
+	for i := 0; i < curveMaxCoins; i++ {
+		result, err := p.curveCall(contract, "coins(uint256)", []string{curveArg(i)})
+		if err != nil {
+			// no more coins
+			break
+		}
+
+		decoded, err := decodeEthData(result, []string{"address"})
+		if err != nil {
+			return nil, err
+		}
+		token := fmt.Sprintf("%v", decoded[0])
+
+		result, err = p.curveCall(token, "symbol()", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		decoded, err = decodeEthData(result, []string{"string"})
+		if err != nil {
+			return nil, err
+		}
+
+		symbols = append(symbols, strings.ToUpper(fmt.Sprintf("%v", decoded[0])))
+	}
+
+	if len(symbols) < 2 {
+		return nil, fmt.Errorf("expected at least two coins, got %d", len(symbols))
+	}
+
+	return symbols, nil
+}
+
+func (p *CurveProvider) pollOnchain() error {
+	timestamp := time.Now()
+
+	prices := make(map[string]sdk.Dec, len(p.pools))
+	liquidity := make(map[string]sdk.Dec, len(p.pools))
+	for symbol, pool := range p.pools {
+		base, err := p.coinPrice(pool, pool.base)
+		if err != nil {
+			p.logger.Warn().Err(err).Str("symbol", symbol).Msg("failed to get price")
+			continue
+		}
+
+		quote, err := p.coinPrice(pool, pool.quote)
+		if err != nil || quote.IsZero() {
+			p.logger.Warn().Err(err).Str("symbol", symbol).Msg("failed to get price")
+			continue
+		}
+
+		prices[symbol] = base.Quo(quote)
+
+		liquidity[symbol], err = p.queryLiquidity(pool, prices[symbol])
+		if err != nil {
+			p.logger.Warn().Err(err).Str("symbol", symbol).Msg("failed to get liquidity")
+		}
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for symbol, price := range prices {
+		p.setLiquidity(symbol, liquidity[symbol])
+		p.setTickerPrice(symbol, price, sdk.ZeroDec(), timestamp)
+	}
+
+	return nil
+}
+
+// coinPrice returns the price of the coin in units of coins[0].
+func (p *CurveProvider) coinPrice(pool curvePool, index int) (sdk.Dec, error) {
+	if index == 0 {
+		return sdk.OneDec(), nil
+	}
+
+	var (
+		result string
+		err    error
+	)
+
+	if pool.indexed {
+		result, err = p.curveCall(
+			pool.address, pool.method+"(uint256)", []string{curveArg(index - 1)},
+		)
+	} else {
+		if index != 1 {
+			return sdk.Dec{}, fmt.Errorf("invalid coin index %d", index)
+		}
+		result, err = p.curveCall(pool.address, pool.method+"()", nil)
+	}
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	decoded, err := decodeEthData(result, []string{"uint256"})
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	price := strToDec(fmt.Sprintf("%v", decoded[0]))
+	if price.IsNil() {
+		return sdk.Dec{}, fmt.Errorf("invalid price")
+	}
+
+	return price.Quo(uintToDec(10).Power(18)), nil
+}
+
+// queryLiquidity returns the value of the base and quote coin balances of
+// the pool in terms of the quote coin.
+func (p *CurveProvider) queryLiquidity(pool curvePool, price sdk.Dec) (sdk.Dec, error) {
+	if len(pool.decimals) != 2 {
+		return sdk.Dec{}, fmt.Errorf("unknown coin decimals")
+	}
+
+	reserves := make([]sdk.Dec, 2)
+	for i, index := range []int{pool.base, pool.quote} {
+		result, err := p.curveCall(
+			pool.address, "balances(uint256)", []string{curveArg(index)},
+		)
+		if err != nil {
+			return sdk.Dec{}, err
+		}
+
+		decoded, err := decodeEthData(result, []string{"uint256"})
+		if err != nil {
+			return sdk.Dec{}, err
+		}
+
+		reserve := strToDec(fmt.Sprintf("%v", decoded[0]))
+		if reserve.IsNil() {
+			return sdk.Dec{}, fmt.Errorf("invalid balance")
+		}
+		reserves[i] = reserve.Quo(uintToDec(10).Power(pool.decimals[i]))
+	}
+
+	return poolLiquidity(reserves[0], reserves[1], price), nil
+}
+
+// curveCall calls the method and returns the hex encoded result. Reverted
+// calls return an empty result and are reported as error.
+func (p *CurveProvider) curveCall(
+	contract, method string,
+	args []string,
+) (string, error) {
+	response, err := p.evmCall(contract, method, args)
+	if err != nil {
+		return "", err
+	}
+
+	var data string
+	err = json.Unmarshal(response, &data)
+	if err != nil {
+		return "", err
+	}
+
+	if len(strings.TrimPrefix(data, "0x")) == 0 {
+		return "", fmt.Errorf("%s returned no data", method)
+	}
+
+	return data, nil
+}
+
+func curveArg(i int) string {
+	return fmt.Sprintf("%064x", i)
+}
+
+// curveCoinIndex returns the index of the denom in the pool coins. Wrapped
+// tokens, e.g. WETH, match their native denom.
+func curveCoinIndex(symbols []string, denom string) int {
+	for i, symbol := range symbols {
+		if symbol == denom {
+			return i
+		}
+	}
+	for i, symbol := range symbols {
+		if symbol == "W"+denom {
+			return i
+		}
+	}
+	return -1
+}
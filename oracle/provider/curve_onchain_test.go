@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurveCoinIndex(t *testing.T) {
+	symbols := []string{"USDT", "WBTC", "WETH", "ETH"}
+
+	require.Equal(t, 0, curveCoinIndex(symbols, "USDT"))
+	require.Equal(t, 1, curveCoinIndex(symbols, "BTC"))
+	// exact matches are preferred over wrapped tokens
+	require.Equal(t, 3, curveCoinIndex(symbols, "ETH"))
+	require.Equal(t, -1, curveCoinIndex(symbols, "CRV"))
+
+	require.Equal(
+		t,
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		curveArg(2),
+	)
+}
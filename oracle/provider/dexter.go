@@ -18,6 +18,7 @@ var (
 	dexterDefaultEndpoints          = Endpoint{
 		Name:         ProviderDexter,
 		Urls:         []string{},
+		ChainId:      "core-1",
 		PollInterval: 6 * time.Second,
 	}
 )
@@ -33,6 +34,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderDexter, dexterDefaultEndpoints, NewDexterProvider)
+}
+
 func NewDexterProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -49,14 +54,12 @@ func NewDexterProvider(
 		nil,
 	)
 
-	provider.contracts = provider.endpoints.ContractAddresses
-
 	availablePairs, _ := provider.GetAvailablePairs()
 	provider.setPairs(pairs, availablePairs, nil)
 
 	provider.denoms = provider.getDenoms()
 
-	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	go startBlockPolling(provider, provider.endpoints.PollInterval, logger)
 	return provider, nil
 }
 
@@ -162,10 +165,18 @@ func (p *DexterProvider) Poll() error {
 		price := strToDec(amountOut).Quo(strToDec(amountIn))
 		price = price.Mul(factor)
 
+		base, quote := offer, ask
 		_, found = p.pairs[pair.String()]
 		if !found {
 			price = floatToDec(1).Quo(price)
+			base, quote = ask, offer
+		}
+
+		liquidity, err := p.getLiquidity(contract, base, quote, price)
+		if err != nil {
+			p.logger.Warn().Err(err).Str("symbol", symbol).Msg("failed to get liquidity")
 		}
+		p.setLiquidity(symbol, liquidity)
 
 		p.setTickerPrice(
 			symbol,
@@ -182,6 +193,61 @@ func (p *DexterProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	return p.getAvailablePairsFromContracts()
 }
 
+// getLiquidity returns the value of the base and quote reserves of the pool
+// in terms of the quote denom.
+func (p *DexterProvider) getLiquidity(
+	contract, base, quote string,
+	price sdk.Dec,
+) (sdk.Dec, error) {
+	// {"assets": [{"info": {"native_token": {"denom": "uxprt"}}, "amount": "1000000"}]}
+	type Response struct {
+		Data struct {
+			Assets []struct {
+				Info struct {
+					NativeToken struct {
+						Denom string `json:"denom"`
+					} `json:"native_token"`
+				} `json:"info"`
+				Amount string `json:"amount"`
+			} `json:"assets"`
+		} `json:"data"`
+	}
+
+	content, err := p.wasmSmartQuery(contract, `{"config":{}}`)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	var response Response
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	reserves := map[string]sdk.Dec{}
+	for _, asset := range response.Data.Assets {
+		denom := asset.Info.NativeToken.Denom
+		amount := strToDec(asset.Amount)
+		decimals, found := p.decimals[denom]
+		if !found || amount.IsNil() {
+			continue
+		}
+		reserves[denom] = amount.Quo(uintToDec(10).Power(uint64(decimals)))
+	}
+
+	baseReserve, found := reserves[base]
+	if !found {
+		return sdk.Dec{}, fmt.Errorf("base reserve not found")
+	}
+
+	quoteReserve, found := reserves[quote]
+	if !found {
+		return sdk.Dec{}, fmt.Errorf("quote reserve not found")
+	}
+
+	return poolLiquidity(baseReserve, quoteReserve, price), nil
+}
+
 func (p *DexterProvider) getDenoms() map[string]string {
 	assets := map[string]string{}
 	p.decimals = map[string]int64{}
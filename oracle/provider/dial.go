@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// IPVersion4 and IPVersion6 restrict the connections of an endpoint to
+	// one IP version. By default, both are dialed happy-eyeballs style.
+	IPVersion4 = "ipv4"
+	IPVersion6 = "ipv6"
+
+	dialTimeout   = 10 * time.Second
+	dialKeepAlive = 30 * time.Second
+	// dialFallbackDelay defines how long the preferred address family is
+	// tried, before the other one is dialed in parallel (RFC 6555).
+	dialFallbackDelay = 300 * time.Millisecond
+)
+
+// IsIPVersion returns true, if the ip version is supported.
+func IsIPVersion(ipVersion string) bool {
+	return ipVersion == IPVersion4 || ipVersion == IPVersion6
+}
+
+// dialNetwork restricts the tcp network to the ip version, if set.
+func dialNetwork(network, ipVersion string) string {
+	if network != "tcp" {
+		return network
+	}
+
+	switch ipVersion {
+	case IPVersion4:
+		return "tcp4"
+	case IPVersion6:
+		return "tcp6"
+	default:
+		return network
+	}
+}
+
+// newDialContext returns the dial function of the http clients and
+// websockets of an endpoint. The host is resolved on every dial, so
+// reconnects follow DNS changes instead of retrying dead addresses, and
+// dual-stack hosts are dialed happy-eyeballs style.
+func newDialContext(ipVersion string) func(context.Context, string, string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:       dialTimeout,
+		KeepAlive:     dialKeepAlive,
+		FallbackDelay: dialFallbackDelay,
+	}
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, dialNetwork(network, ipVersion), address)
+	}
+}
+
+// newEndpointHTTPClient returns a http client with its own transport, so
+// idle connections to an endpoint can be dropped after failed requests.
+func newEndpointHTTPClient(timeout time.Duration, ipVersion string) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = newDialContext(ipVersion)
+
+	client := newHTTPClientWithTimeout(timeout)
+	client.Transport = transport
+	return client
+}
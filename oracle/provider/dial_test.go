@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialNetwork(t *testing.T) {
+	require.Equal(t, "tcp", dialNetwork("tcp", ""))
+	require.Equal(t, "tcp4", dialNetwork("tcp", IPVersion4))
+	require.Equal(t, "tcp6", dialNetwork("tcp", IPVersion6))
+	require.Equal(t, "udp", dialNetwork("udp", IPVersion4))
+}
+
+func TestEndpointHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// the test server listens on 127.0.0.1
+	client := newEndpointHTTPClient(defaultTimeout, IPVersion4)
+	res, err := client.Get(server.URL)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	client = newEndpointHTTPClient(defaultTimeout, IPVersion6)
+	_, err = client.Get(server.URL)
+	require.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"sort"
+	"time"
+)
+
+type (
+	// TickerDump defines the state of a single ticker at the time of a dump.
+	TickerDump struct {
+		Price  string    `json:"price"`
+		Volume string    `json:"volume"`
+		Time   time.Time `json:"time"`
+		Age    string    `json:"age"`
+	}
+
+	// Dump defines a snapshot of the internal provider state, used to debug
+	// providers at runtime.
+	Dump struct {
+		Name           string                `json:"name"`
+		Pairs          map[string]string     `json:"pairs"`
+		Inverse        map[string]string     `json:"inverse"`
+		Tickers        map[string]TickerDump `json:"tickers"`
+		Websocket      bool                  `json:"websocket"`
+		Height         uint64                `json:"height,omitempty"`
+		VolumeSymbols  []string              `json:"volume_symbols,omitempty"`
+		VolumeCoverage float64               `json:"volume_coverage,omitempty"`
+	}
+
+	// Dumper is implemented by all providers based on the common provider.
+	Dumper interface {
+		Dump(now time.Time) Dump
+	}
+)
+
+func (p *provider) Dump(now time.Time) Dump {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	dump := Dump{
+		Name:      p.endpoints.Name.String(),
+		Pairs:     make(map[string]string, len(p.pairs)),
+		Inverse:   make(map[string]string, len(p.inverse)),
+		Tickers:   make(map[string]TickerDump, len(p.tickers)),
+		Websocket: p.websocket != nil,
+		Height:    p.height,
+	}
+
+	for symbol, pair := range p.pairs {
+		dump.Pairs[symbol] = pair.String()
+	}
+
+	for symbol, pair := range p.inverse {
+		dump.Inverse[symbol] = pair.String()
+	}
+
+	for symbol, ticker := range p.tickers {
+		dump.Tickers[symbol] = TickerDump{
+			Price:  ticker.Price.String(),
+			Volume: ticker.Volume.String(),
+			Time:   ticker.Time,
+			Age:    now.Sub(ticker.Time).String(),
+		}
+	}
+
+	if p.db != nil {
+		dump.VolumeSymbols = append([]string{}, p.volumes.Symbols()...)
+		sort.Strings(dump.VolumeSymbols)
+		dump.VolumeCoverage = p.volumes.Coverage()
+	}
+
+	return dump
+}
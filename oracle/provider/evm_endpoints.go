@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	evmLagCheckInterval = 30 * time.Second
+	// defaultEvmMaxBlockLag is used if the provider doesn't define a chain
+	// specific maximum lag.
+	defaultEvmMaxBlockLag = 10
+)
+
+// isEvmChainId returns true for numeric chain ids, as used by EVM chains.
+// Cosmos chain ids always contain a non numeric part, e.g. "kaiyo-1".
+func isEvmChainId(chainId string) bool {
+	_, err := strconv.ParseUint(chainId, 10, 64)
+	return err == nil
+}
+
+// verifyEvmChainId returns an error, if the rpc behind the url serves a
+// different chain than configured for the provider.
+func (p *provider) verifyEvmChainId(url string) error {
+	result, err := p.evmRpcQueryUrl(url, "eth_chainId")
+	if err != nil {
+		return err
+	}
+
+	chainId, err := parseEvmQuantity(result)
+	if err != nil {
+		return err
+	}
+
+	if strconv.FormatUint(chainId, 10) != p.endpoints.ChainId {
+		return fmt.Errorf(
+			"expected chain id %s, got %d", p.endpoints.ChainId, chainId,
+		)
+	}
+
+	return nil
+}
+
+// monitorEvmEndpoints periodically compares the latest block of all urls and
+// rotates away from the current url, if it lags behind the others, e.g. if
+// it is served by a stale replica. Lagging urls count as failed probes for
+// the health of the urls, see failover.go.
+func (p *provider) monitorEvmEndpoints() {
+	if len(p.endpoints.Urls) < 2 {
+		return
+	}
+
+	ticker := time.NewTicker(evmLagCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.checkEvmEndpoints()
+		}
+	}
+}
+
+func (p *provider) checkEvmEndpoints() {
+	heights := make(map[string]uint64, len(p.endpoints.Urls))
+
+	var (
+		best       string
+		bestHeight uint64
+	)
+
+	for _, url := range p.endpoints.Urls {
+		if err := p.verifyEvmChainId(url); err != nil {
+			p.logger.Warn().
+				Err(err).
+				Str("endpoint", url).
+				Msg("skipping evm endpoint")
+			p.reportHttpHealth(url, false)
+			continue
+		}
+
+		result, err := p.evmRpcQueryUrl(url, "eth_blockNumber")
+		if err != nil {
+			p.reportHttpHealth(url, false)
+			continue
+		}
+
+		height, err := parseEvmQuantity(result)
+		if err != nil {
+			p.reportHttpHealth(url, false)
+			continue
+		}
+
+		heights[url] = height
+		if height > bestHeight {
+			best = url
+			bestHeight = height
+		}
+	}
+
+	if best == "" {
+		return
+	}
+
+	maxLag := p.endpoints.MaxBlockLag
+	if maxLag == 0 {
+		maxLag = defaultEvmMaxBlockLag
+	}
+
+	for url, height := range heights {
+		p.reportHttpHealth(url, bestHeight-height <= maxLag)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	base := p.getHttpBase()
+	current, found := heights[base]
+	if found && bestHeight-current <= maxLag {
+		p.preferHealthyHttpBase()
+		return
+	}
+
+	p.logger.Warn().
+		Str("endpoint", base).
+		Uint64("height", current).
+		Str("new_endpoint", best).
+		Uint64("new_height", bestHeight).
+		Msg("evm endpoint lagging or wrong chain, rotating")
+
+	p.setHttpBase(best)
+	p.preferHealthyHttpBase()
+}
+
+// evmRpcQueryUrl sends a json rpc request without parameters to the url,
+// without failing over to other urls.
+func (p *provider) evmRpcQueryUrl(url, method string) (json.RawMessage, error) {
+	query := []byte(fmt.Sprintf(
+		`{"jsonrpc":"2.0","method":"%s","params":[],"id":1}`, method,
+	))
+
+	content, err := p.makeHttpRequest(
+		url, "POST", query, map[string]string{"Content-Type": "application/json"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+	}
+
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Result, nil
+}
+
+// parseEvmQuantity parses a hex encoded json rpc quantity, e.g. "0x1a".
+func parseEvmQuantity(result json.RawMessage) (uint64, error) {
+	var quantity string
+	if err := json.Unmarshal(result, &quantity); err != nil {
+		return 0, err
+	}
+
+	if !strings.HasPrefix(quantity, "0x") {
+		return 0, fmt.Errorf("invalid quantity: %s", quantity)
+	}
+
+	return strconv.ParseUint(quantity[2:], 16, 64)
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func newEvmServer(chainId, height uint64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+
+		result := height
+		if request.Method == "eth_chainId" {
+			result = chainId
+		}
+
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"0x%x"}`, result)
+	}))
+}
+
+func TestCheckEvmEndpoints(t *testing.T) {
+	lagging := newEvmServer(1, 100)
+	defer lagging.Close()
+	synced := newEvmServer(1, 110)
+	defer synced.Close()
+	wrongChain := newEvmServer(10, 200)
+	defer wrongChain.Close()
+
+	p := provider{
+		logger:   zerolog.Nop(),
+		http:     newDefaultHTTPClient(),
+		httpBase: lagging.URL,
+		endpoints: Endpoint{
+			Urls:        []string{lagging.URL, wrongChain.URL, synced.URL},
+			ChainId:     "1",
+			MaxBlockLag: 20,
+		},
+	}
+
+	require.NoError(t, p.verifyChainId(synced.URL))
+	require.Error(t, p.verifyChainId(wrongChain.URL))
+
+	// within max lag
+	p.checkEvmEndpoints()
+	require.Equal(t, lagging.URL, p.httpBase)
+
+	p.endpoints.MaxBlockLag = 5
+	p.checkEvmEndpoints()
+	require.Equal(t, synced.URL, p.httpBase)
+
+	require.True(t, isEvmChainId("42161"))
+	require.False(t, isEvmChainId("kaiyo-1"))
+}
@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// httpProbeInterval defines how often all http endpoints of a provider
+	// are probed in the background.
+	httpProbeInterval = 30 * time.Second
+
+	// Every endpoint has a health score between 0 and httpHealthMax. A
+	// success raises the score by one, a failure lowers it by
+	// httpFailurePenalty. Endpoints with a score of at least
+	// httpHealthThreshold are tried first when failing over, but the
+	// provider only returns to a preferred endpoint with the full score,
+	// so it needs several successful probes in a row after failing.
+	httpHealthMax       = 6
+	httpHealthThreshold = 4
+	httpFailurePenalty  = 2
+)
+
+// getHttpBase returns the http endpoint currently in use.
+func (p *provider) getHttpBase() string {
+	p.httpMtx.RLock()
+	defer p.httpMtx.RUnlock()
+
+	return p.httpBase
+}
+
+// setHttpBase selects the http endpoint used by the next requests.
+func (p *provider) setHttpBase(url string) {
+	p.httpMtx.Lock()
+	defer p.httpMtx.Unlock()
+
+	p.httpBase = url
+}
+
+// reportHttpHealth updates the health score of the url.
+func (p *provider) reportHttpHealth(url string, healthy bool) {
+	p.httpMtx.Lock()
+	defer p.httpMtx.Unlock()
+
+	if p.httpHealth == nil {
+		p.httpHealth = map[string]int{}
+	}
+
+	score := p.httpScore(url)
+	if healthy {
+		score++
+	} else {
+		score -= httpFailurePenalty
+	}
+	if score > httpHealthMax {
+		score = httpHealthMax
+	}
+	if score < 0 {
+		score = 0
+	}
+	p.httpHealth[url] = score
+}
+
+// httpScore returns the health score of the url, urls without results are
+// assumed healthy. The caller has to hold httpMtx.
+func (p *provider) httpScore(url string) int {
+	score, found := p.httpHealth[url]
+	if !found {
+		return httpHealthMax
+	}
+	return score
+}
+
+// httpUrls returns the current http base and the urls in the order they are
+// tried: the current base, which sticks until it fails, followed by the
+// healthy and then the unhealthy other urls, each in configured order.
+func (p *provider) httpUrls() (string, []string) {
+	p.httpMtx.RLock()
+	defer p.httpMtx.RUnlock()
+
+	base := p.httpBase
+	urls := []string{base}
+
+	var unhealthy []string
+	for _, url := range p.endpoints.Urls {
+		switch {
+		case url == base:
+		case p.httpScore(url) >= httpHealthThreshold:
+			urls = append(urls, url)
+		default:
+			unhealthy = append(unhealthy, url)
+		}
+	}
+
+	return base, append(urls, unhealthy...)
+}
+
+// httpRequest sends the request to the current http base. If it fails,
+// e.g. on a network error or rate limit, the other urls are tried in turn
+// and the first one succeeding, that serves the expected chain, becomes the
+// new http base. The results count towards the health of the urls.
+func (p *provider) httpRequest(path string, method string, body []byte, headers map[string]string) ([]byte, error) {
+	base, urls := p.httpUrls()
+
+	var (
+		res []byte
+		err error
+	)
+	for _, endpoint := range urls {
+		if endpoint != urls[0] {
+			p.logger.Warn().
+				Str("endpoint", endpoint).
+				Msg("trying alternate http endpoints")
+		}
+
+		res, err = p.makeHttpRequest(endpoint+path, method, body, headers)
+		p.reportHttpHealth(endpoint, err == nil)
+		if err != nil {
+			continue
+		}
+		if endpoint == base {
+			return res, nil
+		}
+
+		err = p.verifyChainId(endpoint)
+		if err != nil {
+			p.reportHttpHealth(endpoint, false)
+			p.logger.Error().
+				Err(err).
+				Str("endpoint", endpoint).
+				Msg("skipping alternate http endpoint")
+			continue
+		}
+
+		p.logger.Info().Str("endpoint", endpoint).Msg("selected alternate http endpoint")
+		p.setHttpBase(endpoint)
+		return res, nil
+	}
+
+	return nil, err
+}
+
+// monitorHttpEndpoints periodically probes all http endpoints, so the
+// provider returns to the preferred endpoint once it recovered.
+func (p *provider) monitorHttpEndpoints() {
+	if len(p.endpoints.Urls) < 2 {
+		return
+	}
+
+	ticker := time.NewTicker(httpProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.probeHttpEndpoints()
+		}
+	}
+}
+
+func (p *provider) probeHttpEndpoints() {
+	for _, url := range p.endpoints.Urls {
+		err := p.probeHttpEndpoint(url)
+		if err != nil {
+			p.logger.Debug().
+				Err(err).
+				Str("endpoint", url).
+				Msg("http endpoint probe failed")
+		}
+		p.reportHttpHealth(url, err == nil)
+	}
+
+	p.preferHealthyHttpBase()
+}
+
+// probeHttpEndpoint verifies the chain id of cosmos nodes and evm rpcs.
+// Other endpoints are healthy, if the server answers without a server error
+// or rate limit, as there is no common path to query.
+func (p *provider) probeHttpEndpoint(url string) error {
+	if p.endpoints.ChainId != "" {
+		return p.verifyChainId(url)
+	}
+
+	req, err := p.newHttpRequest(url, "GET", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 500 ||
+		res.StatusCode == http.StatusTooManyRequests ||
+		res.StatusCode == http.StatusTeapot {
+		return fmt.Errorf("http probe returned status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// preferHealthyHttpBase switches to the first fully recovered url in
+// configured order, if it's preferred over the current http base.
+func (p *provider) preferHealthyHttpBase() {
+	p.httpMtx.Lock()
+	defer p.httpMtx.Unlock()
+
+	for _, url := range p.endpoints.Urls {
+		if url == p.httpBase {
+			return
+		}
+		if p.httpScore(url) < httpHealthMax {
+			continue
+		}
+
+		p.logger.Info().
+			Str("endpoint", url).
+			Str("previous_endpoint", p.httpBase).
+			Msg("returning to preferred http endpoint")
+		p.httpBase = url
+		return
+	}
+}
@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// testEndpoint defines an http endpoint, which answers with the configured
+// status and counts its requests.
+type testEndpoint struct {
+	server   *httptest.Server
+	status   atomic.Int32
+	requests atomic.Int32
+}
+
+func newTestEndpoint(t *testing.T) *testEndpoint {
+	e := &testEndpoint{}
+	e.status.Store(http.StatusOK)
+	e.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e.requests.Add(1)
+		w.WriteHeader(int(e.status.Load()))
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(e.server.Close)
+	return e
+}
+
+func TestHttpUrls(t *testing.T) {
+	p := provider{
+		endpoints: Endpoint{Urls: []string{"a", "b", "c"}},
+		httpBase:  "a",
+	}
+
+	_, urls := p.httpUrls()
+	require.Equal(t, []string{"a", "b", "c"}, urls)
+
+	p.setHttpBase("c")
+	base, urls := p.httpUrls()
+	require.Equal(t, "c", base)
+	require.Equal(t, []string{"c", "a", "b"}, urls)
+
+	// a single failure is tolerated, two make the url unhealthy
+	p.reportHttpHealth("a", false)
+	_, urls = p.httpUrls()
+	require.Equal(t, []string{"c", "a", "b"}, urls)
+	p.reportHttpHealth("a", false)
+	_, urls = p.httpUrls()
+	require.Equal(t, []string{"c", "b", "a"}, urls)
+
+	// a base not configured is tried before all urls
+	p.setHttpBase("x")
+	_, urls = p.httpUrls()
+	require.Equal(t, []string{"x", "b", "c", "a"}, urls)
+}
+
+func TestHttpHealthScore(t *testing.T) {
+	p := provider{
+		endpoints: Endpoint{Urls: []string{"a", "b"}},
+		httpBase:  "b",
+		logger:    zerolog.Nop(),
+	}
+
+	// unknown urls are assumed healthy
+	p.preferHealthyHttpBase()
+	require.Equal(t, "a", p.getHttpBase())
+
+	p.setHttpBase("b")
+	for i := 0; i < 3; i++ {
+		p.reportHttpHealth("a", false)
+	}
+	require.Equal(t, 0, p.httpHealth["a"])
+
+	// the preferred url needs the full score to be used again
+	for i := 0; i < httpHealthMax; i++ {
+		p.preferHealthyHttpBase()
+		require.Equal(t, "b", p.getHttpBase())
+		p.reportHttpHealth("a", true)
+	}
+	require.Equal(t, httpHealthMax, p.httpHealth["a"])
+
+	p.preferHealthyHttpBase()
+	require.Equal(t, "a", p.getHttpBase())
+}
+
+func TestHttpRequestFailover(t *testing.T) {
+	preferred := newTestEndpoint(t)
+	second := newTestEndpoint(t)
+	third := newTestEndpoint(t)
+
+	p := provider{
+		endpoints: Endpoint{
+			Name: ProviderMock,
+			Urls: []string{preferred.server.URL, second.server.URL, third.server.URL},
+		},
+		logger:   zerolog.Nop(),
+		http:     newDefaultHTTPClient(),
+		httpBase: preferred.server.URL,
+	}
+
+	_, err := p.httpGet("/")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), preferred.requests.Load())
+
+	// a failing endpoint rotates to the next one
+	preferred.status.Store(http.StatusInternalServerError)
+	_, err = p.httpGet("/")
+	require.NoError(t, err)
+	require.Equal(t, second.server.URL, p.getHttpBase())
+
+	// the alternate endpoint sticks, even if the preferred one recovered
+	preferred.status.Store(http.StatusOK)
+	_, err = p.httpGet("/")
+	require.NoError(t, err)
+	require.Equal(t, int32(2), preferred.requests.Load())
+	require.Equal(t, int32(2), second.requests.Load())
+
+	// a rate limit rotates as well, past the failing preferred endpoint
+	preferred.status.Store(http.StatusInternalServerError)
+	second.status.Store(http.StatusTooManyRequests)
+	_, err = p.httpGet("/")
+	require.NoError(t, err)
+	require.Equal(t, third.server.URL, p.getHttpBase())
+
+	// the probes return to the preferred endpoint, once it recovered
+	p.probeHttpEndpoints()
+	require.Equal(t, third.server.URL, p.getHttpBase())
+
+	preferred.status.Store(http.StatusNotFound)
+	for i := 0; i < httpHealthMax; i++ {
+		p.probeHttpEndpoints()
+	}
+	require.Equal(t, preferred.server.URL, p.getHttpBase())
+
+	// all endpoints failing return the error and keep the base
+	for _, e := range []*testEndpoint{preferred, second, third} {
+		e.status.Store(http.StatusBadGateway)
+	}
+	_, err = p.httpGet("/")
+	require.Error(t, err)
+	require.Equal(t, preferred.server.URL, p.getHttpBase())
+}
+
+func TestHttpRequestFailoverConcurrent(t *testing.T) {
+	preferred := newTestEndpoint(t)
+	preferred.status.Store(http.StatusServiceUnavailable)
+	alternate := newTestEndpoint(t)
+
+	p := provider{
+		endpoints: Endpoint{
+			Name: ProviderMock,
+			Urls: []string{preferred.server.URL, alternate.server.URL},
+		},
+		logger:   zerolog.Nop(),
+		http:     newDefaultHTTPClient(),
+		httpBase: preferred.server.URL,
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := p.httpGet("/")
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, alternate.server.URL, p.getHttpBase())
+}
+
+func TestSelectHttpBase(t *testing.T) {
+	newNode := func(chainId string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			fmt.Fprintf(w, `{"default_node_info":{"network":"%s"}}`, chainId)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	wrong := newNode("phoenix-1")
+	first := newNode("kaiyo-1")
+	second := newNode("kaiyo-1")
+
+	p := provider{
+		endpoints: Endpoint{
+			ChainId: "kaiyo-1",
+			Urls:    []string{wrong.URL, first.URL, second.URL},
+		},
+		logger:   zerolog.Nop(),
+		http:     newDefaultHTTPClient(),
+		httpBase: wrong.URL,
+	}
+
+	start := time.Now()
+	p.selectHttpBase()
+
+	// the first url serving the chain is selected, all urls are verified
+	// concurrently
+	require.Equal(t, first.URL, p.getHttpBase())
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestInit_selectHttpBase(t *testing.T) {
+	newNode := func(chainId string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			fmt.Fprintf(w, `{"default_node_info":{"network":"%s"}}`, chainId)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	wrong := newNode("phoenix-1")
+	right := newNode("kaiyo-1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the provider is created without waiting for the verification and
+	// uses the first url until then
+	p := provider{}
+	start := time.Now()
+	p.Init(ctx, Endpoint{
+		Name:    ProviderMock,
+		ChainId: "kaiyo-1",
+		Urls:    []string{wrong.URL, right.URL},
+	}, zerolog.Nop(), nil, nil, nil)
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+	require.Equal(t, wrong.URL, p.getHttpBase())
+
+	require.Eventually(t, func() bool {
+		return p.getHttpBase() == right.URL
+	}, 2*time.Second, 10*time.Millisecond)
+}
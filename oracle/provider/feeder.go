@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+)
+
+var (
+	_                      Provider = (*FeederProvider)(nil)
+	feederDefaultEndpoints          = Endpoint{
+		Name:         ProviderFeeder,
+		PollInterval: 5 * time.Second,
+	}
+)
+
+type (
+	// FeederProvider defines an oracle provider that reads the aggregated
+	// prices of other trusted price-feeder instances, e.g. a second
+	// collector in another region. All rates are quoted in USD. The urls
+	// are tried in order, so the provider fails over between peers.
+	//
+	// Peers must not read the prices of this feeder in turn, otherwise
+	// stale prices can circulate between them.
+	FeederProvider struct {
+		provider
+	}
+
+	FeederPricesResponse struct {
+		Prices map[string]sdk.Dec `json:"prices"`
+	}
+
+	FeederHealthzResponse struct {
+		Oracle struct {
+			LastSync time.Time `json:"last_sync"`
+		} `json:"oracle"`
+	}
+)
+
+func init() {
+	register(ProviderFeeder, feederDefaultEndpoints, NewFeederProvider)
+}
+
+func NewFeederProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*FeederProvider, error) {
+	provider := &FeederProvider{}
+	provider.Init(
+		ctx,
+		endpoints,
+		logger,
+		pairs,
+		nil,
+		nil,
+	)
+
+	availablePairs, _ := provider.GetAvailablePairs()
+	provider.setPairs(pairs, availablePairs, nil)
+
+	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	return provider, nil
+}
+
+func (p *FeederProvider) getPrices() (map[string]sdk.Dec, error) {
+	content, err := p.httpGet("/api/v1/prices")
+	if err != nil {
+		return nil, err
+	}
+
+	var response FeederPricesResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Prices, nil
+}
+
+// getLastSync returns the time of the last oracle tick of the peer, so
+// prices of a stalled peer become stale.
+func (p *FeederProvider) getLastSync() (time.Time, error) {
+	content, err := p.httpGet("/api/v1/healthz")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var response FeederHealthzResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return response.Oracle.LastSync, nil
+}
+
+func (p *FeederProvider) Poll() error {
+	lastSync, err := p.getLastSync()
+	if err != nil {
+		return err
+	}
+
+	prices, err := p.getPrices()
+	if err != nil {
+		return err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	timestamp := time.Now()
+	if lastSync.Before(timestamp) {
+		timestamp = lastSync
+	}
+
+	for denom, price := range prices {
+		symbol := strings.ToUpper(denom) + "USD"
+		if !p.isPair(symbol) || price.IsNil() || !price.IsPositive() {
+			continue
+		}
+
+		p.setTickerPrice(
+			symbol,
+			price,
+			sdk.NewDec(1),
+			timestamp,
+		)
+	}
+
+	p.logger.Debug().Msg("updated tickers")
+	return nil
+}
+
+func (p *FeederProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	prices, err := p.getPrices()
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := map[string]struct{}{}
+	for denom := range prices {
+		symbols[strings.ToUpper(denom)+"USD"] = struct{}{}
+	}
+
+	return symbols, nil
+}
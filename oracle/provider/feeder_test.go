@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeederProviderPoll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lastSync := time.Now().Add(-10 * time.Second).UTC().Truncate(time.Second)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v1/healthz":
+			_, _ = w.Write([]byte(`{"status":"available","oracle":{"last_sync":"` +
+				lastSync.Format(time.RFC3339) + `"}}`))
+		case "/api/v1/prices":
+			_, _ = w.Write([]byte(`{"prices":{"KUJI":"0.750000000000000000","ATOM":"9.100000000000000000"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	pair := types.CurrencyPair{Base: "KUJI", Quote: "USD"}
+
+	// no polling routine, polled by the test
+	p := &FeederProvider{}
+	p.Init(
+		ctx,
+		Endpoint{Name: ProviderFeeder, Urls: []string{server.URL}, Token: "token"},
+		zerolog.Nop(),
+		[]types.CurrencyPair{pair},
+		nil,
+		nil,
+	)
+	availablePairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"KUJIUSD": {}, "ATOMUSD": {}}, availablePairs)
+	p.setPairs([]types.CurrencyPair{pair}, availablePairs, nil)
+
+	require.NoError(t, p.Poll())
+
+	tickers, err := p.GetTickerPrices(pair)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("0.75"), tickers[pair.String()].Price)
+	require.True(t, lastSync.Equal(tickers[pair.String()].Time))
+}
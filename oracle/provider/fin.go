@@ -43,6 +43,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderFin, finDefaultEndpoints, NewFinProvider)
+}
+
 func NewFinProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"price-feeder/oracle/provider/volume"
@@ -20,10 +21,14 @@ var (
 	finV2DefaultEndpoints = Endpoint{
 		Name:         ProviderFinV2,
 		Urls:         []string{},
+		ChainId:      "kaiyo-1",
 		PollInterval: 3 * time.Second,
 		VolumeBlocks: 4,
 		VolumePause:  0,
 	}
+
+	// finV2MarketsUrl lists all FIN markets with their contract addresses
+	finV2MarketsUrl = "https://api.kujira.app/api/coingecko/pairs"
 )
 
 type (
@@ -54,8 +59,21 @@ type (
 	FinV2Config struct {
 		Delta int64 `json:"decimal_delta"`
 	}
+
+	FinV2MarketsResponse struct {
+		Markets []FinV2Market `json:"pairs"`
+	}
+
+	FinV2Market struct {
+		Symbol   string `json:"ticker_id"` // ex.: "KUJI_axlUSDC"
+		Contract string `json:"pool_id"`
+	}
 )
 
+func init() {
+	registerWithDB(ProviderFinV2, finV2DefaultEndpoints, NewFinV2Provider)
+}
+
 func NewFinV2Provider(
 	db *sql.DB,
 	ctx context.Context,
@@ -74,12 +92,14 @@ func NewFinV2Provider(
 		nil,
 	)
 
+	provider.discoverMarkets(pairs)
+
 	availablePairs, _ := provider.GetAvailablePairs()
 	provider.setPairs(pairs, availablePairs, nil)
 
 	provider.delta = map[string]int64{}
 
-	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	go startBlockPolling(provider, provider.endpoints.PollInterval, logger)
 
 	return provider, nil
 }
@@ -151,32 +171,100 @@ func (p *FinV2Provider) Poll() error {
 			price = price.Mul(uintToDec(10).Power(uint64(delta)))
 		}
 
-		var volume sdk.Dec
-		// hack to get the proper volume
-		_, found := p.inverse[symbol]
-		if found {
-			volume, _ = p.volumes.Get(pair.Quote + pair.Base)
+		p.setTickerPriceFromVolumes(symbol, price, timestamp)
+	}
+
+	return nil
+}
 
-			if !volume.IsZero() {
-				volume = volume.Quo(price)
+func (p *FinV2Provider) GetAvailablePairs() (map[string]struct{}, error) {
+	return p.getAvailablePairsFromContracts()
+}
+
+// discoverMarkets resolves the contract addresses of all pairs without
+// configured address from the FIN markets list. Configured addresses take
+// precedence, but differing ones are logged.
+func (p *FinV2Provider) discoverMarkets(pairs []types.CurrencyPair) {
+	content, err := p.makeHttpRequest(finV2MarketsUrl, "GET", nil, nil)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("failed to discover fin markets")
+		return
+	}
+
+	var response FinV2MarketsResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("failed to discover fin markets")
+		return
+	}
+
+	discovered := finV2DiscoveredContracts(response.Markets, pairs)
+
+	contracts := map[string]string{}
+	configured := map[string]struct{}{}
+	for symbol, contract := range p.endpoints.ContractAddresses {
+		contracts[symbol] = contract
+		configured[contract] = struct{}{}
+	}
+
+	for symbol, contract := range discovered {
+		existing, found := contracts[symbol]
+		if !found {
+			// already configured for the inverse pair
+			if _, found := configured[contract]; found {
+				continue
 			}
-		} else {
-			volume, _ = p.volumes.Get(pair.String())
+			p.logger.Info().
+				Str("symbol", symbol).
+				Str("contract", contract).
+				Msg("discovered fin market")
+			contracts[symbol] = contract
+			continue
 		}
 
-		p.setTickerPrice(
-			symbol,
-			price,
-			volume,
-			timestamp,
-		)
+		if existing != contract {
+			p.logger.Warn().
+				Str("symbol", symbol).
+				Str("configured", existing).
+				Str("discovered", contract).
+				Msg("configured contract differs from fin market")
+		}
 	}
 
-	return nil
+	p.endpoints.ContractAddresses = contracts
+
+	registry, err := NewContractRegistry(contracts)
+	if err != nil {
+		p.logger.Error().Err(err).Msg("duplicate contract address")
+	}
+	p.contracts = registry
 }
 
-func (p *FinV2Provider) GetAvailablePairs() (map[string]struct{}, error) {
-	return p.getAvailablePairsFromContracts()
+// finV2DiscoveredContracts returns the contract address of each pair, or of
+// its inverse, found in the markets list. Pairs listed with more than one
+// market are ambiguous and have to be configured.
+func finV2DiscoveredContracts(
+	markets []FinV2Market,
+	pairs []types.CurrencyPair,
+) map[string]string {
+	listed := map[string][]string{}
+	for _, market := range markets {
+		symbol := strings.ToUpper(market.Symbol)
+		listed[symbol] = append(listed[symbol], market.Contract)
+	}
+
+	contracts := map[string]string{}
+	for _, pair := range pairs {
+		for _, candidate := range []types.CurrencyPair{pair, pair.Swap()} {
+			found := listed[currencyPairToFinSymbol(candidate)]
+			if len(found) == 1 {
+				contracts[candidate.String()] = found[0]
+				break
+			}
+		}
+	}
+
+	return contracts
 }
 
 func (p *FinV2Provider) getDecimalDelta(contract string) (int64, error) {
@@ -264,7 +352,14 @@ func (p *FinV2Provider) getVolume(height uint64) (volume.Volume, error) {
 		return volume.Volume{}, p.error(err)
 	}
 
+	volume := volume.Volume{
+		Height: height,
+		Time:   timestamp.Unix(),
+		Values: values,
+	}
+
 	for _, tx := range txs {
+		sender := tx.Sender()
 		trades := tx.GetEventsByType("wasm-trade")
 		for _, event := range trades {
 			contract, found := event.Attributes["_contract_address"]
@@ -272,7 +367,7 @@ func (p *FinV2Provider) getVolume(height uint64) (volume.Volume, error) {
 				continue
 			}
 
-			symbol, found := p.contracts[contract]
+			symbol, found := p.contracts.Symbol(contract)
 			if !found {
 				p.logger.Debug().
 					Str("contract", contract).
@@ -317,7 +412,7 @@ func (p *FinV2Provider) getVolume(height uint64) (volume.Volume, error) {
 			}
 
 			for symbol, denom := range denoms {
-				volume, found := values[symbol]
+				value, found := values[symbol]
 				if !found {
 					p.logger.Error().
 						Str("symbol", symbol).
@@ -325,16 +420,11 @@ func (p *FinV2Provider) getVolume(height uint64) (volume.Volume, error) {
 					continue
 				}
 
-				values[symbol] = volume.Add(denom.Amount)
+				values[symbol] = value.Add(denom.Amount)
+				volume.AddTrader(symbol, sender, denom.Amount)
 			}
 		}
 	}
 
-	volume := volume.Volume{
-		Height: height,
-		Time:   timestamp.Unix(),
-		Values: values,
-	}
-
 	return volume, nil
 }
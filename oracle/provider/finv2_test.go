@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/oracle/types"
+)
+
+func TestFinV2DiscoveredContracts(t *testing.T) {
+	markets := []FinV2Market{
+		{Symbol: "KUJI_USDC", Contract: "kujira1kuji"},
+		{Symbol: "USDC_MNTA", Contract: "kujira1mnta"},
+		{Symbol: "ATOM_axlUSDT", Contract: "kujira1atom"},
+		{Symbol: "LUNA_USDC", Contract: "kujira1luna1"},
+		{Symbol: "LUNA_USDC", Contract: "kujira1luna2"},
+	}
+
+	contracts := finV2DiscoveredContracts(markets, []types.CurrencyPair{
+		{Base: "KUJI", Quote: "USDC"},
+		{Base: "MNTA", Quote: "USDC"},
+		{Base: "ATOM", Quote: "USDT"},
+		{Base: "LUNA", Quote: "USDC"},
+		{Base: "DOT", Quote: "USDC"},
+	})
+
+	require.Equal(t, map[string]string{
+		"KUJIUSDC": "kujira1kuji",
+		// inverted markets are registered for the inverse pair
+		"USDCMNTA": "kujira1mnta",
+		"ATOMUSDT": "kujira1atom",
+		// ambiguous and unlisted pairs aren't resolved
+	}, contracts)
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// defaultMaxFundingRate defines the max absolute funding rate per funding
+// interval of perpetual swap markets, if the provider doesn't configure
+// max_funding_rate. The usual baseline is 0.01%.
+var defaultMaxFundingRate = sdk.MustNewDecFromStr("0.001")
+
+// extremeFunding returns true if the absolute funding rate of the pair
+// exceeds the max funding rate of the provider. Perps systematically
+// deviate from spot during such periods, so their prices are excluded.
+func (p *provider) extremeFunding(symbol string, rate sdk.Dec) bool {
+	if rate.IsNil() {
+		return false
+	}
+
+	maxRate := p.endpoints.MaxFundingRate
+	if maxRate.IsNil() || !maxRate.IsPositive() {
+		maxRate = defaultMaxFundingRate
+	}
+
+	if rate.Abs().LTE(maxRate) {
+		return false
+	}
+
+	p.logger.Warn().
+		Str("symbol", symbol).
+		Str("funding_rate", rate.String()).
+		Str("max_funding_rate", maxRate.String()).
+		Msg("extreme funding rate, skipping perp price")
+	telemetryExtremeFunding(p.endpoints.Name, symbol)
+
+	return true
+}
@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderExtremeFunding(t *testing.T) {
+	p := provider{
+		endpoints: Endpoint{Name: ProviderBybit},
+		logger:    zerolog.Nop(),
+	}
+
+	// default max funding rate of 0.1%
+	require.False(t, p.extremeFunding("BTCUSDT", sdk.MustNewDecFromStr("0.0001")))
+	require.False(t, p.extremeFunding("BTCUSDT", sdk.MustNewDecFromStr("-0.001")))
+	require.True(t, p.extremeFunding("BTCUSDT", sdk.MustNewDecFromStr("0.002")))
+	require.True(t, p.extremeFunding("BTCUSDT", sdk.MustNewDecFromStr("-0.002")))
+
+	// unknown funding rates are never extreme
+	require.False(t, p.extremeFunding("BTCUSDT", sdk.Dec{}))
+
+	p.endpoints.MaxFundingRate = sdk.MustNewDecFromStr("0.005")
+	require.False(t, p.extremeFunding("BTCUSDT", sdk.MustNewDecFromStr("0.002")))
+}
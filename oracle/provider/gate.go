@@ -32,9 +32,14 @@ type (
 		Symbol string `json:"currency_pair"` // Symbol ex.: BTC_USDT
 		Price  string `json:"last"`          // Last price ex.: 0.0025
 		Volume string `json:"base_volume"`   // Total traded base asset volume ex.: 1000
+		Quote  string `json:"quote_volume"`  // Total traded quote asset volume ex.: 2.5
 	}
 )
 
+func init() {
+	register(ProviderGate, gateDefaultEndpoints, NewGateProvider)
+}
+
 func NewGateProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -87,6 +92,8 @@ func (p *GateProvider) Poll() error {
 			continue
 		}
 
+		p.setQuoteVolume(ticker.Symbol, strToDec(ticker.Quote))
+
 		p.setTickerPrice(
 			ticker.Symbol,
 			strToDec(ticker.Price),
@@ -42,6 +42,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderHelix, helixDefaultEndpoints, NewHelixProvider)
+}
+
 func NewHelixProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -58,8 +62,6 @@ func NewHelixProvider(
 		nil,
 	)
 
-	provider.contracts = provider.endpoints.ContractAddresses
-
 	availablePairs, _ := provider.GetAvailablePairs()
 	provider.setPairs(pairs, availablePairs, currencyPairToHelixSymbol)
 
@@ -35,6 +35,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderHitBtc, hitbtcDefaultEndpoints, NewHitBtcProvider)
+}
+
 func NewHitBtcProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -31,6 +31,7 @@ type (
 
 	HuobiTickersResponse struct {
 		Data []HuobiTicker `json:"data"`
+		Time int64         `json:"ts"` // Server time in ms ex.: 1690000000000
 	}
 
 	HuobiTicker struct {
@@ -40,6 +41,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderHuobi, huobiDefaultEndpoints, NewHuobiProvider)
+}
+
 func NewHuobiProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -79,14 +84,18 @@ func (p *HuobiProvider) getTickers() (HuobiTickersResponse, error) {
 }
 
 func (p *HuobiProvider) Poll() error {
+	sent := time.Now()
 	tickers, err := p.getTickers()
 	if err != nil {
 		return err
 	}
+	received := time.Now()
 
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
-	now := time.Now()
+
+	p.observeServerTime(time.UnixMilli(tickers.Time), sent, received)
+	now := p.eventTime(time.UnixMilli(tickers.Time))
 
 	for _, ticker := range tickers.Data {
 		if !p.isPair(ticker.Symbol) {
@@ -42,6 +42,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderIdxOsmosis, idxOsmosisDefaultEndpoints, NewIdxProvider)
+}
+
 func NewIdxProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
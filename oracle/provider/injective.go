@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	injectiveFeedPyth = "pyth"
+	injectiveFeedBand = "band"
+)
+
+var (
+	_                               Provider = (*InjectiveOracleProvider)(nil)
+	injectiveOracleDefaultEndpoints          = Endpoint{
+		Name:         ProviderInjectiveOracle,
+		Urls:         []string{},
+		ChainId:      "injective-1",
+		PollInterval: 6 * time.Second,
+	}
+)
+
+type (
+	// InjectiveOracleProvider defines an oracle provider that reads the
+	// pyth and band price feeds of the Injective oracle module, so assets
+	// priced there can be used without the Helix order books. The feed of
+	// a denom is configured in contract_addresses as "pyth:<price id>" or
+	// "band:<symbol>", unconfigured denoms use the band feed of the denom.
+	//
+	// REF: https://docs.injective.network/developers/modules/injective/oracle
+	InjectiveOracleProvider struct {
+		provider
+	}
+
+	InjectivePythPriceStatesResponse struct {
+		PriceStates []InjectivePythPriceState `json:"price_states"`
+	}
+
+	InjectivePythPriceState struct {
+		PriceId    string               `json:"price_id"`
+		PriceState InjectiveOraclePrice `json:"price_state"`
+	}
+
+	InjectiveBandPriceStatesResponse struct {
+		PriceStates []InjectiveBandPriceState `json:"price_states"`
+	}
+
+	InjectiveBandPriceState struct {
+		Symbol     string               `json:"symbol"`
+		PriceState InjectiveOraclePrice `json:"price_state"`
+	}
+
+	InjectiveOraclePrice struct {
+		Price     string `json:"price"`
+		Timestamp string `json:"timestamp"`
+	}
+
+	// injectiveFeed defines the source and id of a price feed.
+	injectiveFeed struct {
+		source string
+		id     string
+	}
+)
+
+func init() {
+	register(ProviderInjectiveOracle, injectiveOracleDefaultEndpoints, NewInjectiveOracleProvider)
+}
+
+func NewInjectiveOracleProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*InjectiveOracleProvider, error) {
+	provider := &InjectiveOracleProvider{}
+	provider.Init(
+		ctx,
+		endpoints,
+		logger,
+		pairs,
+		nil,
+		nil,
+	)
+
+	for _, pair := range pairs {
+		for _, denom := range []string{pair.Base, pair.Quote} {
+			if _, err := provider.getFeed(denom); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	availablePairs, _ := provider.GetAvailablePairs()
+	provider.setPairs(pairs, availablePairs, nil)
+
+	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	return provider, nil
+}
+
+func (p *InjectiveOracleProvider) Poll() error {
+	feeds := map[string]injectiveFeed{}
+	sources := map[string]bool{}
+	for _, pair := range p.getAllPairs() {
+		for _, denom := range []string{pair.Base, pair.Quote} {
+			if denom == "USD" {
+				continue
+			}
+			feed, err := p.getFeed(denom)
+			if err != nil {
+				return err
+			}
+			feeds[denom] = feed
+			sources[feed.source] = true
+		}
+	}
+
+	prices := map[injectiveFeed]InjectiveOraclePrice{}
+	if sources[injectiveFeedPyth] {
+		states, err := p.getPythPriceStates()
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			feed := injectiveFeed{injectiveFeedPyth, normalizePythPriceId(state.PriceId)}
+			prices[feed] = state.PriceState
+		}
+	}
+	if sources[injectiveFeedBand] {
+		states, err := p.getBandPriceStates()
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			prices[injectiveFeed{injectiveFeedBand, state.Symbol}] = state.PriceState
+		}
+	}
+
+	type rate struct {
+		price sdk.Dec
+		time  time.Time
+	}
+	rates := map[string]rate{"USD": {price: sdk.OneDec(), time: time.Now()}}
+	for denom, feed := range feeds {
+		state, found := prices[feed]
+		if !found {
+			p.logger.Warn().
+				Str("denom", denom).
+				Str("feed", feed.source+":"+feed.id).
+				Msg("price feed not found")
+			continue
+		}
+
+		price := strToDec(state.Price)
+		seconds, err := strconv.ParseInt(state.Timestamp, 10, 64)
+		if price.IsNil() || !price.IsPositive() || err != nil {
+			p.logger.Warn().
+				Str("denom", denom).
+				Str("price", state.Price).
+				Str("timestamp", state.Timestamp).
+				Msg("invalid price state")
+			continue
+		}
+
+		rates[denom] = rate{price: price, time: time.Unix(seconds, 0)}
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for symbol, pair := range p.pairs {
+		base, found := rates[pair.Base]
+		if !found {
+			continue
+		}
+
+		quote, found := rates[pair.Quote]
+		if !found {
+			continue
+		}
+
+		// the older update of both feeds is the age of the price
+		timestamp := base.time
+		if quote.time.Before(timestamp) {
+			timestamp = quote.time
+		}
+
+		p.setTickerPrice(
+			symbol,
+			base.price.Quo(quote.price),
+			sdk.NewDec(1),
+			timestamp,
+		)
+	}
+
+	p.logger.Debug().Msg("updated tickers")
+	return nil
+}
+
+func (p *InjectiveOracleProvider) getPythPriceStates() ([]InjectivePythPriceState, error) {
+	content, err := p.httpGet("/injective/oracle/v1beta1/pyth_price_states")
+	if err != nil {
+		return nil, err
+	}
+
+	var response InjectivePythPriceStatesResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.PriceStates, nil
+}
+
+func (p *InjectiveOracleProvider) getBandPriceStates() ([]InjectiveBandPriceState, error) {
+	content, err := p.httpGet("/injective/oracle/v1beta1/band_ibc_price_states")
+	if err != nil {
+		return nil, err
+	}
+
+	var response InjectiveBandPriceStatesResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.PriceStates, nil
+}
+
+// getFeed returns the configured price feed of the denom, or the band feed
+// of the denom if none is configured.
+func (p *InjectiveOracleProvider) getFeed(denom string) (injectiveFeed, error) {
+	contract, found := p.contracts.Contract(denom)
+	if !found {
+		return injectiveFeed{injectiveFeedBand, denom}, nil
+	}
+
+	source, id, found := strings.Cut(contract, ":")
+	switch {
+	case found && source == injectiveFeedPyth && id != "":
+		return injectiveFeed{injectiveFeedPyth, normalizePythPriceId(id)}, nil
+	case found && source == injectiveFeedBand && id != "":
+		return injectiveFeed{injectiveFeedBand, id}, nil
+	}
+
+	return injectiveFeed{}, fmt.Errorf(
+		"invalid injective oracle feed of %s: %s, expected pyth:<price id> or band:<symbol>",
+		denom, contract,
+	)
+}
+
+func (p *InjectiveOracleProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	// Feeds are configured per denom, missing feeds are logged when
+	// polling.
+	return nil, nil
+}
+
+// normalizePythPriceId returns the lower case hex price id without prefix.
+func normalizePythPriceId(id string) string {
+	return strings.TrimPrefix(strings.ToLower(id), "0x")
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectiveOracleProviderPoll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/injective/oracle/v1beta1/pyth_price_states":
+			fmt.Fprint(w, `{"price_states":[
+				{"price_id":"0x09F7C1D7DFBB7DF2B8FE3D3D87EE94A2259D212DA4F30C1F0540D066DFA44723","price_state":{"price":"5.5","timestamp":"1696161600"}}
+			]}`)
+		case "/injective/oracle/v1beta1/band_ibc_price_states":
+			fmt.Fprint(w, `{"price_states":[
+				{"symbol":"ATOM","price_state":{"price":"11.0","timestamp":"1696161590"}},
+				{"symbol":"BAD","price_state":{"price":"0","timestamp":"1696161600"}}
+			]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	pairs := []types.CurrencyPair{
+		{Base: "TIA", Quote: "USD"},
+		{Base: "TIA", Quote: "ATOM"},
+		{Base: "BAD", Quote: "USD"},
+	}
+
+	// no polling routine, the test polls once
+	p := &InjectiveOracleProvider{}
+	p.Init(
+		ctx,
+		Endpoint{
+			Name: ProviderInjectiveOracle,
+			Urls: []string{server.URL},
+			ContractAddresses: map[string]string{
+				"TIA": "pyth:09f7c1d7dfbb7df2b8fe3d3d87ee94a2259d212da4f30c1f0540d066dfa44723",
+			},
+		},
+		zerolog.Nop(),
+		pairs,
+		nil,
+		nil,
+	)
+	p.setPairs(pairs, nil, nil)
+
+	require.NoError(t, p.Poll())
+
+	// the feed timestamps are stale, so the tickers are read directly
+	tickers := p.tickers
+	require.Len(t, tickers, 2)
+	require.True(t, sdk.MustNewDecFromStr("5.5").Equal(tickers["TIAUSD"].Price))
+	require.True(t, sdk.MustNewDecFromStr("0.5").Equal(tickers["TIAATOM"].Price))
+	require.Equal(t, time.Unix(1696161590, 0), tickers["TIAATOM"].Time)
+}
+
+func TestInjectiveOracleProviderGetFeed(t *testing.T) {
+	contracts, err := NewContractRegistry(map[string]string{
+		"TIA":   "pyth:0xABC",
+		"STINJ": "band:stINJ",
+		"FOO":   "chainlink:foo",
+		"BAR":   "pyth:",
+	})
+	require.NoError(t, err)
+
+	p := InjectiveOracleProvider{provider: provider{contracts: contracts}}
+
+	feed, err := p.getFeed("TIA")
+	require.NoError(t, err)
+	require.Equal(t, injectiveFeed{injectiveFeedPyth, "abc"}, feed)
+
+	feed, err = p.getFeed("STINJ")
+	require.NoError(t, err)
+	require.Equal(t, injectiveFeed{injectiveFeedBand, "stINJ"}, feed)
+
+	feed, err = p.getFeed("ATOM")
+	require.NoError(t, err)
+	require.Equal(t, injectiveFeed{injectiveFeedBand, "ATOM"}, feed)
+
+	_, err = p.getFeed("FOO")
+	require.Error(t, err)
+
+	_, err = p.getFeed("BAR")
+	require.Error(t, err)
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"price-feeder/oracle/types"
@@ -44,8 +45,23 @@ type (
 	KrakenPair struct {
 		WsName string `json:"wsname"` // ex.: "XBT/USD"
 	}
+
+	// KrakenOHLCResponse contains the candles by symbol and the id of the
+	// last candle as "last".
+	KrakenOHLCResponse struct {
+		Result map[string]json.RawMessage `json:"result"`
+	}
+
+	// KrakenCandle defines a single candle, ex.:
+	// [1688671200, "30306.1", "30306.2", "30305.7", "30305.7", "30306.1",
+	// "3.39243896", 23]
+	KrakenCandle []json.RawMessage
 )
 
+func init() {
+	register(ProviderKraken, krakenDefaultEndpoints, NewKrakenProvider)
+}
+
 func NewKrakenProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -66,6 +82,7 @@ func NewKrakenProvider(
 	provider.setPairs(pairs, availablePairs, currencyPairToKrakenSymbol)
 
 	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	provider.startCandlePolling(provider)
 	return provider, nil
 }
 
@@ -111,6 +128,70 @@ func (p *KrakenProvider) Poll() error {
 	return nil
 }
 
+// PollCandles polls the 1m candles of all pairs.
+func (p *KrakenProvider) PollCandles() error {
+	p.mtx.RLock()
+	pairs := p.getAllPairs()
+	p.mtx.RUnlock()
+
+	since := time.Now().Add(-candleLimit * candleInterval).Unix()
+
+	for symbol := range pairs {
+		content, err := p.httpGet(fmt.Sprintf(
+			"/0/public/OHLC?pair=%s&interval=1&since=%d", symbol, since,
+		))
+		if err != nil {
+			return err
+		}
+
+		var response KrakenOHLCResponse
+		if err := json.Unmarshal(content, &response); err != nil {
+			return err
+		}
+
+		var krakenCandles []KrakenCandle
+		for key, raw := range response.Result {
+			if key != "last" {
+				if err := json.Unmarshal(raw, &krakenCandles); err != nil {
+					return err
+				}
+			}
+		}
+
+		candles := make([]types.CandlePrice, 0, len(krakenCandles))
+		for _, candle := range krakenCandles {
+			if len(candle) < 7 {
+				continue
+			}
+
+			var (
+				openTime   int64
+				closePrice string
+				volume     string
+			)
+			if json.Unmarshal(candle[0], &openTime) != nil ||
+				json.Unmarshal(candle[4], &closePrice) != nil ||
+				json.Unmarshal(candle[6], &volume) != nil {
+				continue
+			}
+
+			candles = append(candles, types.CandlePrice{
+				Price:  strToDec(closePrice),
+				Volume: strToDec(volume),
+				// candles are dated by their close
+				TimeStamp: SecondsToMilli(openTime) + candleInterval.Milliseconds(),
+			})
+		}
+
+		p.mtx.Lock()
+		p.setCandlePrices(symbol, candles)
+		p.mtx.Unlock()
+	}
+
+	p.logger.Debug().Msg("updated candles")
+	return nil
+}
+
 func (p *KrakenProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	tickers, err := p.getTickers()
 	if err != nil {
@@ -3,25 +3,39 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"price-feeder/oracle/types"
 
+	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog"
 )
 
+const (
+	kucoinMaxSymbolsPerTopic = 100
+)
+
 var (
 	_                      Provider = (*KucoinProvider)(nil)
 	kucoinDefaultEndpoints          = Endpoint{
 		Name:         ProviderKucoin,
 		Urls:         []string{"https://api.kucoin.com"},
 		PollInterval: 2 * time.Second,
+		Websocket:    "ws-api-spot.kucoin.com",
+		PingDuration: 18 * time.Second,
+		PingType:     websocket.TextMessage,
+		PingMessage:  `{"id":"ping","type":"ping"}`,
 	}
 )
 
 type (
 	// KucoinProvider defines an oracle provider implemented by the Kucoin
-	// public API.
+	// public API. Before each websocket (re)connect, a new connect token and
+	// the websocket endpoint are requested, as tokens expire.
 	//
 	// REF: https://docs.kucoin.com/?lang=en_US
 	KucoinProvider struct {
@@ -42,8 +56,65 @@ type (
 		Price  string `json:"last"`   // Last price ex.: 0.0025
 		Volume string `json:"vol"`    // Total traded base asset volume ex.: 1000
 	}
+
+	KucoinBulletResponse struct {
+		Code string           `json:"code"`
+		Data KucoinBulletData `json:"data"`
+	}
+
+	KucoinBulletData struct {
+		Token   string                 `json:"token"`
+		Servers []KucoinInstanceServer `json:"instanceServers"`
+	}
+
+	KucoinInstanceServer struct {
+		Endpoint     string `json:"endpoint"` // Endpoint ex.: wss://ws-api-spot.kucoin.com/
+		Protocol     string `json:"protocol"` // Protocol ex.: websocket
+		PingInterval int64  `json:"pingInterval"`
+	}
+
+	KucoinWsMessage struct {
+		Type    string               `json:"type"`  // Type ex.: message
+		Topic   string               `json:"topic"` // Topic ex.: /market/snapshot:BTC-USDT
+		Subject string               `json:"subject"`
+		Data    KucoinWsSnapshotData `json:"data"`
+	}
+
+	KucoinWsSnapshotData struct {
+		Data KucoinWsSnapshot `json:"data"`
+	}
+
+	KucoinWsSnapshot struct {
+		Symbol string  `json:"symbol"`          // Symbol ex.: BTC-USDT
+		Price  float64 `json:"lastTradedPrice"` // Last price ex.: 0.0025
+		Volume float64 `json:"vol"`             // Total traded base asset volume ex.: 1000
+		Time   int64   `json:"datetime"`        // Time in ms ex.: 1690000000000
+	}
+
+	KucoinWsTradeMessage struct {
+		Topic string        `json:"topic"` // Topic ex.: /market/match:BTC-USDT
+		Data  KucoinWsTrade `json:"data"`
+	}
+
+	KucoinWsTrade struct {
+		Symbol string `json:"symbol"` // Symbol ex.: BTC-USDT
+		Price  string `json:"price"`  // Price ex.: 0.0025
+		Size   string `json:"size"`   // Traded base asset amount ex.: 10
+		Time   string `json:"time"`   // Time in ns ex.: 1690000000000000000
+	}
+
+	KucoinWsSubscriptionMsg struct {
+		ID       string `json:"id"`
+		Type     string `json:"type"`  // Type ex.: subscribe
+		Topic    string `json:"topic"` // Topic ex.: /market/snapshot:BTC-USDT,ETH-USDT
+		Response bool   `json:"response"`
+	}
 )
 
+func init() {
+	register(ProviderKucoin, kucoinDefaultEndpoints, NewKucoinProvider)
+}
+
 func NewKucoinProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -51,22 +122,193 @@ func NewKucoinProvider(
 	pairs ...types.CurrencyPair,
 ) (*KucoinProvider, error) {
 	provider := &KucoinProvider{}
+	provider.wsUrl = provider.getWebsocketUrl
+	provider.wsTrades = provider.getTradeSubscriptionMsgs
+	// the subscriptions are filtered by the available pairs
+	provider.wsDeferStart = true
 	provider.Init(
 		ctx,
 		endpoints,
 		logger,
 		pairs,
-		nil,
-		nil,
+		provider.messageReceived,
+		provider.getSubscriptionMsgs,
 	)
 
 	availablePairs, _ := provider.GetAvailablePairs()
 	provider.setPairs(pairs, availablePairs, currencyPairToKucoinSymbol)
 
-	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	if provider.websocket == nil {
+		go startPolling(provider, provider.endpoints.PollInterval, logger)
+	} else {
+		provider.startWebsocket()
+	}
+
 	return provider, nil
 }
 
+// getWebsocketUrl requests a new public connect token and returns the url
+// of the first available websocket instance server.
+func (p *KucoinProvider) getWebsocketUrl() (url.URL, error) {
+	content, err := p.httpPost("/api/v1/bullet-public", nil)
+	if err != nil {
+		return url.URL{}, err
+	}
+
+	var response KucoinBulletResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return url.URL{}, err
+	}
+
+	if response.Data.Token == "" {
+		return url.URL{}, fmt.Errorf("no connect token received")
+	}
+
+	for _, server := range response.Data.Servers {
+		if server.Protocol != "websocket" {
+			continue
+		}
+
+		endpoint, err := url.Parse(server.Endpoint)
+		if err != nil {
+			p.logger.Warn().
+				Str("endpoint", server.Endpoint).
+				Msg("invalid instance server endpoint")
+			continue
+		}
+
+		query := endpoint.Query()
+		query.Set("token", response.Data.Token)
+		query.Set("connectId", strconv.FormatInt(time.Now().UnixNano(), 10))
+		endpoint.RawQuery = query.Encode()
+
+		return *endpoint, nil
+	}
+
+	return url.URL{}, fmt.Errorf("no instance server found")
+}
+
+func (p *KucoinProvider) getSubscriptionMsgs(pairs ...types.CurrencyPair) []interface{} {
+	return p.getTopicMsgs("/market/snapshot:", pairs...)
+}
+
+func (p *KucoinProvider) getTradeSubscriptionMsgs(pairs ...types.CurrencyPair) []interface{} {
+	return p.getTopicMsgs("/market/match:", pairs...)
+}
+
+// getTopicMsgs returns the subscription messages of the topic for the
+// pairs, each message subscribes kucoinMaxSymbolsPerTopic symbols at most.
+func (p *KucoinProvider) getTopicMsgs(topic string, pairs ...types.CurrencyPair) []interface{} {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	symbols := []string{}
+	for _, pair := range pairs {
+		symbol := currencyPairToKucoinSymbol(pair)
+		if p.isPair(symbol) {
+			symbols = append(symbols, symbol)
+			continue
+		}
+
+		symbol = currencyPairToKucoinSymbol(pair.Swap())
+		if p.isPair(symbol) {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	msgs := []interface{}{}
+	for i := 0; i < len(symbols); i += kucoinMaxSymbolsPerTopic {
+		end := i + kucoinMaxSymbolsPerTopic
+		if end > len(symbols) {
+			end = len(symbols)
+		}
+
+		msgs = append(msgs, KucoinWsSubscriptionMsg{
+			ID:       strconv.Itoa(i),
+			Type:     "subscribe",
+			Topic:    topic + strings.Join(symbols[i:end], ","),
+			Response: true,
+		})
+	}
+
+	return msgs
+}
+
+func (p *KucoinProvider) messageReceived(messageType int, bz []byte) {
+	var message KucoinWsMessage
+	err := json.Unmarshal(bz, &message)
+	if err != nil {
+		p.logger.Error().
+			Err(err).
+			Msg("failed to unmarshal message")
+		return
+	}
+
+	// welcome, ack and pong messages don't carry any data
+	if message.Type != "message" {
+		return
+	}
+
+	if strings.HasPrefix(message.Topic, "/market/match:") {
+		p.tradeReceived(bz)
+		return
+	}
+
+	snapshot := message.Data.Data
+	if !p.isPair(snapshot.Symbol) {
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.setTickerPriceWithEventTime(
+		snapshot.Symbol,
+		floatToDec(snapshot.Price),
+		floatToDec(snapshot.Volume),
+		time.UnixMilli(snapshot.Time),
+	)
+
+	telemetryWebsocketMessage(ProviderKucoin, MessageTypeTicker)
+}
+
+func (p *KucoinProvider) tradeReceived(bz []byte) {
+	var message KucoinWsTradeMessage
+	if err := json.Unmarshal(bz, &message); err != nil {
+		p.logger.Error().
+			Err(err).
+			Msg("failed to unmarshal trade")
+		return
+	}
+
+	trade := message.Data
+	if !p.isPair(trade.Symbol) {
+		return
+	}
+
+	timestamp, err := strconv.ParseInt(trade.Time, 10, 64)
+	if err != nil {
+		p.logger.Error().
+			Err(err).
+			Str("time", trade.Time).
+			Msg("failed to parse trade time")
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.setTrade(
+		trade.Symbol,
+		strToDec(trade.Price),
+		strToDec(trade.Size),
+		time.Unix(0, timestamp),
+	)
+
+	telemetryWebsocketMessage(ProviderKucoin, MessageTypeTrade)
+}
+
 func (p *KucoinProvider) getTickers() (KucoinTickersResponse, error) {
 	content, err := p.httpGet("/api/v1/market/allTickers")
 	if err != nil {
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKucoinProvider_firstSubscription(t *testing.T) {
+	frames := make(chan string, 10)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/bullet-public":
+			endpoint := strings.Replace(server.URL, "http", "ws", 1) + "/ws"
+			fmt.Fprintf(w, `{"code":"200000","data":{"token":"token","instanceServers":[{"endpoint":"%s","protocol":"websocket"}]}}`, endpoint)
+		case "/api/v1/market/allTickers":
+			// slow enough for a websocket started by Init to subscribe
+			// before the pairs are set
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{"code":"200000","data":{"ticker":[{"symbol":"ATOM-USDT","last":"10","vol":"1000"}]}}`))
+		case "/ws":
+			upgrader := websocket.Upgrader{}
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			for {
+				_, bz, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				if !strings.Contains(string(bz), `"ping"`) {
+					frames <- string(bz)
+				}
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := NewKucoinProvider(
+		ctx,
+		zerolog.Nop(),
+		Endpoint{
+			Name:      ProviderKucoin,
+			Urls:      []string{server.URL},
+			Websocket: "unused",
+			PingType:  websocket.TextMessage,
+		},
+		testAtomUsdtCurrencyPair,
+	)
+	require.NoError(t, err)
+
+	select {
+	case frame := <-frames:
+		require.Contains(t, frame, `"type":"subscribe"`)
+		require.Contains(t, frame, "/market/snapshot:ATOM-USDT")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no subscription received")
+	}
+}
@@ -45,6 +45,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderLbank, lbankDefaultEndpoints, NewLbankProvider)
+}
+
 func NewLbankProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
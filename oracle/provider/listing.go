@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"price-feeder/oracle/types"
+)
+
+// ListingProvider defines a provider, which picks up configured pairs that
+// weren't available at startup, once the exchange lists them.
+type ListingProvider interface {
+	// UnlistedPairs returns the configured pairs not listed yet.
+	UnlistedPairs() []types.CurrencyPair
+	// ResolveUnlistedPairs adds the unlisted pairs found in the available
+	// pairs and subscribes them, if the provider uses a websocket. It
+	// returns the newly listed pairs.
+	ResolveUnlistedPairs(availablePairs map[string]struct{}) ([]types.CurrencyPair, error)
+}
+
+func (p *provider) UnlistedPairs() []types.CurrencyPair {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	unlisted := make([]types.CurrencyPair, len(p.unlisted))
+	copy(unlisted, p.unlisted)
+	return unlisted
+}
+
+func (p *provider) ResolveUnlistedPairs(
+	availablePairs map[string]struct{},
+) ([]types.CurrencyPair, error) {
+	p.mtx.Lock()
+
+	listed := []types.CurrencyPair{}
+	unlisted := []types.CurrencyPair{}
+	for _, pair := range p.unlisted {
+		providerSymbol := p.toSymbol(pair.Swap())
+		if _, found := availablePairs[providerSymbol]; found {
+			p.inverse[providerSymbol] = pair
+			listed = append(listed, pair)
+			continue
+		}
+
+		providerSymbol = p.toSymbol(pair)
+		if _, found := availablePairs[providerSymbol]; found {
+			p.pairs[providerSymbol] = pair
+			listed = append(listed, pair)
+			continue
+		}
+
+		unlisted = append(unlisted, pair)
+	}
+	p.unlisted = unlisted
+
+	p.mtx.Unlock()
+
+	for _, pair := range listed {
+		p.logger.Info().
+			Str("pair", pair.String()).
+			Msg("pair got listed")
+	}
+
+	// the websocket controller has its own lock
+	if len(listed) == 0 || p.websocket == nil {
+		return listed, nil
+	}
+	return listed, p.websocket.AddPairs(listed)
+}
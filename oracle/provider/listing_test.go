@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"testing"
+
+	"price-feeder/oracle/types"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveUnlistedPairs(t *testing.T) {
+	p := provider{
+		endpoints: Endpoint{Name: ProviderMock},
+		logger:    zerolog.Nop(),
+	}
+
+	kujiUsdt := types.CurrencyPair{Base: "KUJI", Quote: "USDT"}
+	pairs := []types.CurrencyPair{testAtomUsdtCurrencyPair, kujiUsdt, testBtcUsdtCurrencyPair}
+
+	p.setPairs(pairs, map[string]struct{}{"ATOMUSDT": {}}, nil)
+	require.Equal(t, []types.CurrencyPair{kujiUsdt, testBtcUsdtCurrencyPair}, p.UnlistedPairs())
+
+	// nothing listed yet
+	listed, err := p.ResolveUnlistedPairs(map[string]struct{}{"ATOMUSDT": {}})
+	require.NoError(t, err)
+	require.Empty(t, listed)
+	require.False(t, p.isPair("KUJIUSDT"))
+
+	// inverted listings are resolved like at startup
+	listed, err = p.ResolveUnlistedPairs(map[string]struct{}{
+		"ATOMUSDT": {},
+		"USDTBTC":  {},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{testBtcUsdtCurrencyPair}, listed)
+	require.Equal(t, testBtcUsdtCurrencyPair, p.inverse["USDTBTC"])
+	require.Equal(t, []types.CurrencyPair{kujiUsdt}, p.UnlistedPairs())
+
+	listed, err = p.ResolveUnlistedPairs(map[string]struct{}{"KUJIUSDT": {}})
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{kujiUsdt}, listed)
+	require.True(t, p.isPair("KUJIUSDT"))
+	require.Empty(t, p.UnlistedPairs())
+}
@@ -26,16 +26,20 @@ type (
 	// REF: https://midgard.ninerealms.com/v2/doc
 	MayaProvider struct {
 		provider
-		contracts map[string]string
 	}
 
 	MayaPool struct {
 		Symbol string `json:"asset"`
 		Price  string `json:"assetPrice"`
 		Volume string `json:"volume24h"`
+		Depth  string `json:"runeDepth"`
 	}
 )
 
+func init() {
+	register(ProviderMaya, mayaDefaultEndpoints, NewMayaProvider)
+}
+
 func NewMayaProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -52,8 +56,6 @@ func NewMayaProvider(
 		nil,
 	)
 
-	provider.contracts = provider.endpoints.ContractAddresses
-
 	availablePairs, _ := provider.GetAvailablePairs()
 	provider.setPairs(pairs, availablePairs, nil)
 
@@ -87,15 +89,10 @@ func (p *MayaProvider) Poll() error {
 
 	timestamp := time.Now()
 
-	mapping := map[string]string{}
-	for symbol, contract := range p.contracts {
-		mapping[contract] = symbol
-	}
-
 	precision := int64ToDec(10_000_000_000)
 
 	for _, pool := range pools {
-		symbol, found := mapping[pool.Symbol]
+		symbol, found := p.contracts.Symbol(pool.Symbol)
 		if !found {
 			continue
 		}
@@ -103,6 +100,13 @@ func (p *MayaProvider) Poll() error {
 		price := strToDec(pool.Price)
 		volume := strToDec(pool.Volume).Quo(precision).Quo(price)
 
+		liquidity := strToDec(pool.Depth)
+		if !liquidity.IsNil() {
+			// both sides of the pool have the same value
+			liquidity = liquidity.Quo(precision).MulInt64(2)
+		}
+		p.setLiquidity(symbol, liquidity)
+
 		p.setTickerPrice(
 			symbol,
 			price,
@@ -29,12 +29,18 @@ type (
 	}
 
 	MexcTicker struct {
-		Symbol string `json:"symbol"`    // Symbol ex.: BTC-USDT
-		Price  string `json:"lastPrice"` // Last price ex.: 0.0025
-		Volume string `json:"volume"`    // Total traded base asset volume ex.: 1000
+		Symbol string `json:"symbol"`      // Symbol ex.: BTC-USDT
+		Price  string `json:"lastPrice"`   // Last price ex.: 0.0025
+		Volume string `json:"volume"`      // Total traded base asset volume ex.: 1000
+		Quote  string `json:"quoteVolume"` // Total traded quote asset volume ex.: 2.5
+		Time   int64  `json:"closeTime"`   // Statistics close time in ms ex.: 1690000000000
 	}
 )
 
+func init() {
+	register(ProviderMexc, mexcDefaultEndpoints, NewMexcProvider)
+}
+
 func NewMexcProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -81,17 +87,18 @@ func (p *MexcProvider) Poll() error {
 
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
-	now := time.Now()
 	for _, ticker := range tickers {
 		if !p.isPair(ticker.Symbol) {
 			continue
 		}
 
+		p.setQuoteVolume(ticker.Symbol, strToDec(ticker.Quote))
+
 		p.setTickerPrice(
 			ticker.Symbol,
 			strToDec(ticker.Price),
 			strToDec(ticker.Volume),
-			now,
+			p.eventTime(time.UnixMilli(ticker.Time)),
 		)
 	}
 	p.logger.Debug().Msg("updated tickers")
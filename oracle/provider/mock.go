@@ -24,6 +24,7 @@ const (
 var (
 	_                    Provider = (*MockProvider)(nil)
 	mockDefaultEndpoints          = Endpoint{
+		Name: ProviderMock,
 		Urls: []string{mockBaseURL},
 	}
 )
@@ -36,6 +37,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderMock, mockDefaultEndpoints, NewMockProvider)
+}
+
 func NewMockProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -62,7 +67,7 @@ func NewMockProvider(
 func (p MockProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]types.TickerPrice, error) {
 	tickerPrices := make(map[string]types.TickerPrice, len(pairs))
 
-	resp, err := p.http.Get(p.httpBase)
+	resp, err := p.http.Get(p.getHttpBase())
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +140,7 @@ func (p MockProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][
 
 // GetAvailablePairs return all available pairs symbol to susbscribe.
 func (p MockProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := p.http.Get(p.httpBase)
+	resp, err := p.http.Get(p.getHttpBase())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+)
+
+var (
+	_                   Provider = (*OjoProvider)(nil)
+	ojoDefaultEndpoints          = Endpoint{
+		Name:         ProviderOjo,
+		Urls:         []string{"https://ojo-api.polkachu.com"},
+		ChainId:      "agamotto",
+		PollInterval: 10 * time.Second,
+	}
+)
+
+type (
+	// OjoProvider defines an oracle provider that queries the on-chain
+	// exchange rates of the Ojo oracle network. All rates are quoted in USD.
+	//
+	// REF: https://docs.ojo.network
+	OjoProvider struct {
+		provider
+	}
+
+	OjoExchangeRatesResponse struct {
+		Rates []OjoExchangeRate `json:"exchange_rates"`
+	}
+
+	OjoExchangeRate struct {
+		Denom  string `json:"denom"`  // Denom ex.: ATOM
+		Amount string `json:"amount"` // Rate ex.: 9.870000000000000000
+	}
+)
+
+func init() {
+	registerComparisonOnly(ProviderOjo, ojoDefaultEndpoints, NewOjoProvider)
+}
+
+func NewOjoProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*OjoProvider, error) {
+	provider := &OjoProvider{}
+	provider.Init(
+		ctx,
+		endpoints,
+		logger,
+		pairs,
+		nil,
+		nil,
+	)
+
+	availablePairs, _ := provider.GetAvailablePairs()
+	provider.setPairs(pairs, availablePairs, nil)
+
+	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	return provider, nil
+}
+
+func (p *OjoProvider) getRates() ([]OjoExchangeRate, error) {
+	content, err := p.httpGet("/ojo/oracle/v1/denoms/exchange_rates/")
+	if err != nil {
+		return nil, err
+	}
+
+	var response OjoExchangeRatesResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Rates, nil
+}
+
+func (p *OjoProvider) Poll() error {
+	rates, err := p.getRates()
+	if err != nil {
+		return err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	now := time.Now()
+
+	for _, rate := range rates {
+		symbol := strings.ToUpper(rate.Denom) + "USD"
+		if !p.isPair(symbol) {
+			continue
+		}
+
+		p.setTickerPrice(
+			symbol,
+			strToDec(rate.Amount),
+			sdk.NewDec(1),
+			now,
+		)
+	}
+
+	p.logger.Debug().Msg("updated tickers")
+	return nil
+}
+
+func (p *OjoProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	rates, err := p.getRates()
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := map[string]struct{}{}
+	for _, rate := range rates {
+		symbols[strings.ToUpper(rate.Denom)+"USD"] = struct{}{}
+	}
+
+	return symbols, nil
+}
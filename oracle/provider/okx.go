@@ -3,11 +3,13 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
 	"time"
 
 	"price-feeder/oracle/types"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/rs/zerolog"
 )
 
@@ -18,6 +20,10 @@ var (
 		Urls:         []string{"https://www.okx.com", "https://aws.okx.com"},
 		PollInterval: 2 * time.Second,
 	}
+
+	// okxFundingInterval defines how often the funding rates of swap
+	// markets are refreshed, they only change slowly
+	okxFundingInterval = time.Minute
 )
 
 type (
@@ -27,6 +33,8 @@ type (
 	// REF: https://www.okx.com/docs-v5/en
 	OkxProvider struct {
 		provider
+		fundingRates   map[string]sdk.Dec
+		fundingUpdated time.Time
 	}
 
 	OkxTickersResponse struct {
@@ -40,9 +48,40 @@ type (
 		Price  string `json:"last"`   // Last price ex.: 0.0025
 		Volume string `json:"vol24h"` // Total traded base asset volume ex.: 1000
 		Time   string `json:"ts"`     // Timestamp ex.: 1675246930699
+		// swap markets report vol24h in contracts
+		VolumeCcy string `json:"volCcy24h"` // ex.: 1000
+	}
+
+	OkxMarkPricesResponse struct {
+		Data []OkxMarkPrice `json:"data"`
+	}
+
+	OkxMarkPrice struct {
+		Symbol string `json:"instId"` // ex.: BTC-USDT-SWAP
+		Price  string `json:"markPx"` // ex.: 42310.6
+	}
+
+	OkxFundingRatesResponse struct {
+		Data []OkxFundingRate `json:"data"`
+	}
+
+	OkxFundingRate struct {
+		Symbol string `json:"instId"`      // ex.: BTC-USDT-SWAP
+		Rate   string `json:"fundingRate"` // ex.: 0.0001
+	}
+
+	// OkxCandlesResponse contains the candles of a single market, ex.:
+	// [["1597026383085", "3.721", "3.743", "3.677", "3.708", "8422410",
+	// "22698348.04828491", "12698348.04828491", "1"]]
+	OkxCandlesResponse struct {
+		Data [][]string `json:"data"`
 	}
 )
 
+func init() {
+	register(ProviderOkx, okxDefaultEndpoints, NewOkxProvider)
+}
+
 func NewOkxProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -59,15 +98,28 @@ func NewOkxProvider(
 		nil,
 	)
 
+	toProviderSymbol := currencyPairToOkxSymbol
+	if endpoints.Perp {
+		toProviderSymbol = currencyPairToOkxSwapSymbol
+	}
+
+	provider.fundingRates = map[string]sdk.Dec{}
+
 	availablePairs, _ := provider.GetAvailablePairs()
-	provider.setPairs(pairs, availablePairs, currencyPairToOkxSymbol)
+	provider.setPairs(pairs, availablePairs, toProviderSymbol)
 
 	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	provider.startCandlePolling(provider)
 	return provider, nil
 }
 
 func (p *OkxProvider) getTickers() (OkxTickersResponse, error) {
-	content, err := p.httpGet("/api/v5/market/tickers?instType=SPOT")
+	instType := "SPOT"
+	if p.endpoints.Perp {
+		instType = "SWAP"
+	}
+
+	content, err := p.httpGet("/api/v5/market/tickers?instType=" + instType)
 	if err != nil {
 		return OkxTickersResponse{}, err
 	}
@@ -87,6 +139,15 @@ func (p *OkxProvider) Poll() error {
 		return err
 	}
 
+	var markPrices map[string]string
+	if p.endpoints.Perp {
+		markPrices, err = p.getMarkPrices()
+		if err != nil {
+			return err
+		}
+		p.updateFundingRates()
+	}
+
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
 	for _, ticker := range tickers.Data {
@@ -94,6 +155,19 @@ func (p *OkxProvider) Poll() error {
 			continue
 		}
 
+		if p.endpoints.Perp {
+			if p.extremeFunding(ticker.Symbol, p.fundingRates[ticker.Symbol]) {
+				continue
+			}
+
+			price, found := markPrices[ticker.Symbol]
+			if !found {
+				continue
+			}
+			ticker.Price = price
+			ticker.Volume = ticker.VolumeCcy
+		}
+
 		timestamp, err := strconv.ParseInt(ticker.Time, 0, 64)
 		if err != nil {
 			p.logger.
@@ -106,7 +180,7 @@ func (p *OkxProvider) Poll() error {
 			ticker.Symbol,
 			strToDec(ticker.Price),
 			strToDec(ticker.Volume),
-			time.UnixMilli(timestamp),
+			p.eventTime(time.UnixMilli(timestamp)),
 		)
 	}
 	p.logger.Debug().Msg("updated tickers")
@@ -127,6 +201,116 @@ func (p *OkxProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	return symbols, nil
 }
 
+// PollCandles polls the 1m candles of all pairs. Swap markets report their
+// volume in contracts, so the volume in base currency is used.
+func (p *OkxProvider) PollCandles() error {
+	p.mtx.RLock()
+	pairs := p.getAllPairs()
+	p.mtx.RUnlock()
+
+	volumeIndex := 5
+	if p.endpoints.Perp {
+		volumeIndex = 6
+	}
+
+	for symbol := range pairs {
+		content, err := p.httpGet(fmt.Sprintf(
+			"/api/v5/market/candles?instId=%s&bar=1m&limit=%d", symbol, candleLimit,
+		))
+		if err != nil {
+			return err
+		}
+
+		var response OkxCandlesResponse
+		if err := json.Unmarshal(content, &response); err != nil {
+			return err
+		}
+
+		candles := make([]types.CandlePrice, 0, len(response.Data))
+		for _, candle := range response.Data {
+			if len(candle) <= volumeIndex {
+				continue
+			}
+
+			openTime, err := strconv.ParseInt(candle[0], 10, 64)
+			if err != nil {
+				continue
+			}
+
+			candles = append(candles, types.CandlePrice{
+				Price:  strToDec(candle[4]),
+				Volume: strToDec(candle[volumeIndex]),
+				// candles are dated by their close
+				TimeStamp: openTime + candleInterval.Milliseconds(),
+			})
+		}
+
+		p.mtx.Lock()
+		p.setCandlePrices(symbol, candles)
+		p.mtx.Unlock()
+	}
+
+	p.logger.Debug().Msg("updated candles")
+	return nil
+}
+
+// getMarkPrices returns the mark prices of all swap markets.
+func (p *OkxProvider) getMarkPrices() (map[string]string, error) {
+	content, err := p.httpGet("/api/v5/public/mark-price?instType=SWAP")
+	if err != nil {
+		return nil, err
+	}
+
+	var response OkxMarkPricesResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := map[string]string{}
+	for _, markPrice := range response.Data {
+		prices[markPrice.Symbol] = markPrice.Price
+	}
+
+	return prices, nil
+}
+
+// updateFundingRates refreshes the funding rates of all swap markets, at
+// most once per okxFundingInterval. Failed markets keep their last rate.
+func (p *OkxProvider) updateFundingRates() {
+	if time.Since(p.fundingUpdated) < okxFundingInterval {
+		return
+	}
+	p.fundingUpdated = time.Now()
+
+	p.mtx.RLock()
+	pairs := p.getAllPairs()
+	p.mtx.RUnlock()
+
+	for symbol := range pairs {
+		content, err := p.httpGet("/api/v5/public/funding-rate?instId=" + symbol)
+		if err != nil {
+			p.logger.Warn().Err(err).Str("symbol", symbol).Msg("failed to get funding rate")
+			continue
+		}
+
+		var response OkxFundingRatesResponse
+		err = json.Unmarshal(content, &response)
+		if err != nil || len(response.Data) == 0 {
+			p.logger.Warn().Err(err).Str("symbol", symbol).Msg("failed to get funding rate")
+			continue
+		}
+
+		p.mtx.Lock()
+		p.fundingRates[symbol] = strToDec(response.Data[0].Rate)
+		p.mtx.Unlock()
+	}
+}
+
+func currencyPairToOkxSwapSymbol(pair types.CurrencyPair) string {
+	return currencyPairToOkxSymbol(pair) + "-SWAP"
+}
+
 func currencyPairToOkxSymbol(pair types.CurrencyPair) string {
 	mapping := map[string]string{
 		"MATIC": "POL",
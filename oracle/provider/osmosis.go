@@ -34,12 +34,17 @@ type (
 	}
 
 	OsmosisTicker struct {
-		Symbol string  `json:"symbol"`     // ex.: "ATOM"
-		Price  float64 `json:"price"`      // ex.: 14.8830587017
-		Volume float64 `json:"volume_24h"` // ex.: 6428474.562418117
+		Symbol    string  `json:"symbol"`     // ex.: "ATOM"
+		Price     float64 `json:"price"`      // ex.: 14.8830587017
+		Volume    float64 `json:"volume_24h"` // ex.: 6428474.562418117
+		Liquidity float64 `json:"liquidity"`  // ex.: 58432912.21845301
 	}
 )
 
+func init() {
+	register(ProviderOsmosis, osmosisDefaultEndpoints, NewOsmosisProvider)
+}
+
 func NewOsmosisProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -98,6 +103,7 @@ func (p *OsmosisProvider) Poll() error {
 		price := floatToDec(ticker.Price)
 		volume := floatToDec(ticker.Volume)
 
+		p.setLiquidity(symbol, floatToDec(ticker.Liquidity))
 		p.setTickerPrice(
 			symbol,
 			price,
@@ -20,6 +20,7 @@ var (
 	osmosisv2DefaultEndpoints          = Endpoint{
 		Name:         ProviderOsmosisV2,
 		Urls:         []string{},
+		ChainId:      "osmosis-1",
 		PollInterval: 4 * time.Second,
 		VolumeBlocks: 4,
 		VolumePause:  0,
@@ -35,6 +36,10 @@ type (
 		provider
 		denoms       map[string]string
 		concentrated map[string]struct{}
+		// pools of a symbol, multiple pools are configured comma separated
+		pools    map[string][]string
+		symbols  map[string]string // pool id -> symbol
+		reversed map[string]struct{}
 	}
 
 	OsmosisV2SpotPrice struct {
@@ -61,8 +66,21 @@ type (
 	OsmosisV2Token struct {
 		Denom string `json:"denom"`
 	}
+
+	OsmosisV2LiquidityResponse struct {
+		Liquidity []OsmosisV2Coin `json:"liquidity"`
+	}
+
+	OsmosisV2Coin struct {
+		Denom  string `json:"denom"`
+		Amount string `json:"amount"`
+	}
 )
 
+func init() {
+	registerWithDB(ProviderOsmosisV2, osmosisv2DefaultEndpoints, NewOsmosisV2Provider)
+}
+
 func NewOsmosisV2Provider(
 	db *sql.DB,
 	ctx context.Context,
@@ -89,7 +107,7 @@ func NewOsmosisV2Provider(
 		return nil, err
 	}
 
-	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	go startBlockPolling(provider, provider.endpoints.PollInterval, logger)
 
 	return provider, nil
 }
@@ -103,53 +121,69 @@ func (p *OsmosisV2Provider) Poll() error {
 	defer p.mtx.Unlock()
 
 	for symbol, pair := range p.getAllPairs() {
-
-		poolId, err := p.getContractAddress(pair)
-		if err != nil {
+		pools, found := p.pools[symbol]
+		if !found {
 			p.logger.Warn().
 				Str("symbol", symbol).
 				Msg("no pool id found")
+			continue
 		}
 
-		_, found := p.inverse[symbol]
+		_, found = p.inverse[symbol]
 		if found {
 			pair = pair.Swap()
 		}
 
-		var price sdk.Dec
-
-		_, found = p.concentrated[poolId]
-		if found {
-			price, err = p.queryConcentratedLiquidityPool(poolId)
+		prices := []sdk.Dec{}
+		weights := []sdk.Dec{}
+		// liquidity of all pools in quote units, nil if any is unknown
+		liquidity := sdk.ZeroDec()
+		for _, poolId := range pools {
+			price, err := p.queryPool(pair, poolId)
 			if err != nil {
 				continue
 			}
-		} else {
-			price, err = p.queryLegacyPool(pair, poolId)
+
+			base, quote, err := p.queryReserves(pair, poolId)
 			if err != nil {
-				continue
+				p.logger.Warn().
+					Err(err).
+					Str("pool", poolId).
+					Msg("failed to get pool liquidity")
+				quote = sdk.ZeroDec()
+				liquidity = sdk.Dec{}
+			} else if !liquidity.IsNil() {
+				liquidity = liquidity.Add(poolLiquidity(base, quote, price))
 			}
+
+			prices = append(prices, price)
+			weights = append(weights, quote)
 		}
 
-		volume, _ := p.volumes.Get(pair.String())
-		// hack to get the proper volume
-		_, found = p.inverse[symbol]
-		if found {
-			if !volume.IsZero() {
-				volume = volume.Quo(price)
+		var price sdk.Dec
+		if len(prices) == 1 {
+			price = prices[0]
+		} else {
+			var err error
+			price, err = mergePoolPrices(prices, weights)
+			if err != nil {
+				p.logger.Warn().
+					Err(err).
+					Str("symbol", symbol).
+					Msg("failed to merge pool prices")
+				continue
 			}
 		}
 
-		if volume.IsNil() {
-			volume = sdk.ZeroDec()
+		decimals, found := p.endpoints.Decimals[pair.Quote]
+		if !found || liquidity.IsNil() {
+			liquidity = sdk.Dec{}
+		} else {
+			liquidity = liquidity.Quo(uintToDec(10).Power(uint64(decimals)))
 		}
 
-		p.setTickerPrice(
-			symbol,
-			price,
-			volume,
-			timestamp,
-		)
+		p.setLiquidity(symbol, liquidity)
+		p.setTickerPriceFromVolumes(symbol, price, timestamp)
 	}
 
 	p.logger.Debug().Msg("updated tickers")
@@ -160,6 +194,106 @@ func (p *OsmosisV2Provider) GetAvailablePairs() (map[string]struct{}, error) {
 	return p.getAvailablePairsFromContracts()
 }
 
+// queryPool returns the price of the pair in the given pool, which is
+// expected to hold the base and quote denom of the pair.
+func (p *OsmosisV2Provider) queryPool(
+	pair types.CurrencyPair,
+	poolId string,
+) (sdk.Dec, error) {
+	_, found := p.concentrated[poolId]
+	if !found {
+		return p.queryLegacyPool(pair, poolId)
+	}
+
+	price, err := p.queryConcentratedLiquidityPool(poolId)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	_, found = p.reversed[poolId]
+	if found {
+		if price.IsZero() {
+			return sdk.Dec{}, fmt.Errorf("price is zero")
+		}
+		price = sdk.OneDec().Quo(price)
+	}
+
+	return price, nil
+}
+
+// queryReserves returns the amounts of the base and quote denom in the
+// given pool. The quote amount is used to weight the prices of multiple
+// pools of the same pair.
+func (p *OsmosisV2Provider) queryReserves(
+	pair types.CurrencyPair,
+	poolId string,
+) (sdk.Dec, sdk.Dec, error) {
+	baseDenom, found := p.denoms[pair.Base]
+	if !found {
+		return sdk.Dec{}, sdk.Dec{}, fmt.Errorf("denom not found")
+	}
+
+	quoteDenom, found := p.denoms[pair.Quote]
+	if !found {
+		return sdk.Dec{}, sdk.Dec{}, fmt.Errorf("denom not found")
+	}
+
+	path := "/osmosis/poolmanager/v1beta1/pools/" + poolId + "/total_pool_liquidity"
+
+	content, err := p.httpGet(path)
+	if err != nil {
+		return sdk.Dec{}, sdk.Dec{}, err
+	}
+
+	var response OsmosisV2LiquidityResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return sdk.Dec{}, sdk.Dec{}, err
+	}
+
+	var base, quote sdk.Dec
+	for _, coin := range response.Liquidity {
+		switch coin.Denom {
+		case baseDenom:
+			base = strToDec(coin.Amount)
+		case quoteDenom:
+			quote = strToDec(coin.Amount)
+		}
+	}
+
+	if base.IsNil() || quote.IsNil() {
+		return sdk.Dec{}, sdk.Dec{}, fmt.Errorf("could not parse pool liquidity")
+	}
+
+	return base, quote, nil
+}
+
+// mergePoolPrices returns the average of the pool prices weighted by their
+// liquidity. Without any liquidity, the prices are weighted equally.
+func mergePoolPrices(prices, weights []sdk.Dec) (sdk.Dec, error) {
+	if len(prices) == 0 || len(prices) != len(weights) {
+		return sdk.Dec{}, fmt.Errorf("no pool prices")
+	}
+
+	sum := sdk.ZeroDec()
+	total := sdk.ZeroDec()
+	for i, price := range prices {
+		sum = sum.Add(price.Mul(weights[i]))
+		total = total.Add(weights[i])
+	}
+
+	if total.IsPositive() {
+		return sum.Quo(total), nil
+	}
+
+	sum = sdk.ZeroDec()
+	for _, price := range prices {
+		sum = sum.Add(price)
+	}
+
+	return sum.QuoInt64(int64(len(prices))), nil
+}
+
 func (p *OsmosisV2Provider) queryLegacyPool(
 	pair types.CurrencyPair,
 	poolId string,
@@ -231,13 +365,16 @@ func (p *OsmosisV2Provider) queryConcentratedLiquidityPool(
 func (p *OsmosisV2Provider) init() error {
 	p.denoms = map[string]string{}
 	p.concentrated = map[string]struct{}{}
+	p.pools = map[string][]string{}
+	p.symbols = map[string]string{}
+	p.reversed = map[string]struct{}{}
 
 	for symbol, pair := range p.getAllPairs() {
 		p.logger.Info().
 			Str("symbol", symbol).
 			Msg("set denoms")
 
-		pool, found := p.contracts[symbol]
+		contract, found := p.contracts.Contract(symbol)
 		if !found {
 			continue
 		}
@@ -247,39 +384,86 @@ func (p *OsmosisV2Provider) init() error {
 			pair = pair.Swap()
 		}
 
-		path := "/osmosis/gamm/v1beta1/pools/" + pool
+		for _, pool := range strings.Split(contract, ",") {
+			pool = strings.TrimSpace(pool)
+			if pool == "" {
+				continue
+			}
 
-		content, err := p.httpGet(path)
-		if err != nil {
-			return err
-		}
+			err := p.initPool(symbol, pair, pool)
+			if err != nil {
+				return err
+			}
 
-		var response OsmosisV2PoolResponse
-		err = json.Unmarshal(content, &response)
-		if err != nil {
-			return err
+			p.pools[symbol] = append(p.pools[symbol], pool)
+			p.symbols[pool] = symbol
 		}
+	}
+
+	return nil
+}
+
+// initPool sets the denoms of the pair from the pool. If the pair has
+// multiple pools, the denoms are taken from the first one and concentrated
+// liquidity pools with flipped tokens are marked as reversed.
+func (p *OsmosisV2Provider) initPool(
+	symbol string,
+	pair types.CurrencyPair,
+	pool string,
+) error {
+	existing, found := p.symbols[pool]
+	if found {
+		return fmt.Errorf("pool %s used for %s and %s", pool, existing, symbol)
+	}
 
+	path := "/osmosis/gamm/v1beta1/pools/" + pool
+
+	content, err := p.httpGet(path)
+	if err != nil {
+		return err
+	}
+
+	var response OsmosisV2PoolResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return err
+	}
+
+	_, found = p.pools[symbol]
+	if found {
 		switch response.Pool.Type {
-		case "/osmosis.gamm.v1beta1.Pool":
-			p.denoms[pair.Base] = response.Pool.Assets[0].Token.Denom
-			p.denoms[pair.Quote] = response.Pool.Assets[1].Token.Denom
-			p.denoms[response.Pool.Assets[0].Token.Denom] = pair.Base
-			p.denoms[response.Pool.Assets[1].Token.Denom] = pair.Quote
-		case "/osmosis.gamm.poolmodels.stableswap.v1beta1.Pool":
-			p.denoms[pair.Base] = response.Pool.Liquidity[0].Denom
-			p.denoms[pair.Quote] = response.Pool.Liquidity[1].Denom
-			p.denoms[response.Pool.Liquidity[0].Denom] = pair.Base
-			p.denoms[response.Pool.Liquidity[1].Denom] = pair.Quote
+		case "/osmosis.gamm.v1beta1.Pool",
+			"/osmosis.gamm.poolmodels.stableswap.v1beta1.Pool":
 		case "/osmosis.concentratedliquidity.v1beta1.Pool":
-			p.denoms[pair.Base] = response.Pool.Token0
-			p.denoms[pair.Quote] = response.Pool.Token1
-			p.denoms[response.Pool.Token0] = pair.Base
-			p.denoms[response.Pool.Token1] = pair.Quote
 			p.concentrated[pool] = struct{}{}
+			if response.Pool.Token0 == p.denoms[pair.Quote] {
+				p.reversed[pool] = struct{}{}
+			}
 		default:
 			return fmt.Errorf("pool type not supported")
 		}
+		return nil
+	}
+
+	switch response.Pool.Type {
+	case "/osmosis.gamm.v1beta1.Pool":
+		p.denoms[pair.Base] = response.Pool.Assets[0].Token.Denom
+		p.denoms[pair.Quote] = response.Pool.Assets[1].Token.Denom
+		p.denoms[response.Pool.Assets[0].Token.Denom] = pair.Base
+		p.denoms[response.Pool.Assets[1].Token.Denom] = pair.Quote
+	case "/osmosis.gamm.poolmodels.stableswap.v1beta1.Pool":
+		p.denoms[pair.Base] = response.Pool.Liquidity[0].Denom
+		p.denoms[pair.Quote] = response.Pool.Liquidity[1].Denom
+		p.denoms[response.Pool.Liquidity[0].Denom] = pair.Base
+		p.denoms[response.Pool.Liquidity[1].Denom] = pair.Quote
+	case "/osmosis.concentratedliquidity.v1beta1.Pool":
+		p.denoms[pair.Base] = response.Pool.Token0
+		p.denoms[pair.Quote] = response.Pool.Token1
+		p.denoms[response.Pool.Token0] = pair.Base
+		p.denoms[response.Pool.Token1] = pair.Quote
+		p.concentrated[pool] = struct{}{}
+	default:
+		return fmt.Errorf("pool type not supported")
 	}
 
 	return nil
@@ -342,7 +526,14 @@ func (p *OsmosisV2Provider) getVolume(height uint64) (volume.Volume, error) {
 		values[symbol] = sdk.ZeroDec()
 	}
 
+	volume := volume.Volume{
+		Height: height,
+		Time:   timestamp.Unix(),
+		Values: values,
+	}
+
 	for _, tx := range txs {
+		sender := tx.Sender()
 		swaps := tx.GetEventsByType("token_swapped")
 		if len(swaps) == 0 {
 			continue
@@ -359,7 +550,7 @@ func (p *OsmosisV2Provider) getVolume(height uint64) (volume.Volume, error) {
 				continue
 			}
 
-			symbol, found := p.contracts[pool]
+			symbol, found := p.symbols[pool]
 			if !found {
 				p.logger.Debug().
 					Str("pool_id", pool).
@@ -409,16 +600,11 @@ func (p *OsmosisV2Provider) getVolume(height uint64) (volume.Volume, error) {
 				}
 
 				values[symbol] = value.Add(amount)
+				volume.AddTrader(symbol, sender, amount)
 			}
 		}
 	}
 
-	volume := volume.Volume{
-		Height: height,
-		Time:   timestamp.Unix(),
-		Values: values,
-	}
-
 	return volume, nil
 }
 
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePoolPrices(t *testing.T) {
+	// prices are weighted by liquidity
+	price, err := mergePoolPrices(
+		[]sdk.Dec{sdk.MustNewDecFromStr("1.0"), sdk.MustNewDecFromStr("1.1")},
+		[]sdk.Dec{sdk.NewDec(900), sdk.NewDec(100)},
+	)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("1.01"), price)
+
+	// without liquidity, prices are weighted equally
+	price, err = mergePoolPrices(
+		[]sdk.Dec{sdk.MustNewDecFromStr("1.0"), sdk.MustNewDecFromStr("1.1")},
+		[]sdk.Dec{sdk.ZeroDec(), sdk.ZeroDec()},
+	)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("1.05"), price)
+
+	_, err = mergePoolPrices(nil, nil)
+	require.Error(t, err)
+}
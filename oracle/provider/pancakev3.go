@@ -30,8 +30,7 @@ type (
 	// REF: -
 	PancakeProvider struct {
 		provider
-		contracts map[string]string
-		volumes   map[string][]PancakeVolume
+		volumes map[string][]PancakeVolume
 	}
 
 	PancakeVolume struct {
@@ -53,11 +52,14 @@ type (
 	}
 
 	PancakePool struct {
-		Id        string            `json:"id"`
-		Token0    PancakeToken      `json:"token0"`
-		Token1    PancakeToken      `json:"token1"`
-		SqrtPrice string            `json:"sqrtPrice"`
-		HourData  []PancakeHourData `json:"poolHourData"`
+		Id          string            `json:"id"`
+		Token0      PancakeToken      `json:"token0"`
+		Token1      PancakeToken      `json:"token1"`
+		SqrtPrice   string            `json:"sqrtPrice"`
+		Token1Price string            `json:"token1Price"`
+		Locked0     string            `json:"totalValueLockedToken0"`
+		Locked1     string            `json:"totalValueLockedToken1"`
+		HourData    []PancakeHourData `json:"poolHourData"`
 	}
 
 	PancakeToken struct {
@@ -71,6 +73,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderPancakeV3Bsc, PancakeV3BscDefaultEndpoints, NewPancakeProvider)
+}
+
 func NewPancakeProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -87,8 +93,6 @@ func NewPancakeProvider(
 		nil,
 	)
 
-	provider.contracts = provider.endpoints.ContractAddresses
-
 	availablePairs, _ := provider.GetAvailablePairs()
 	provider.setPairs(pairs, availablePairs, nil)
 
@@ -128,13 +132,15 @@ func (p *PancakeProvider) Poll() error {
 	query, _ := p.getQuery(contracts, offset)
 
 	var (
-		prices  map[string]sdk.Dec
-		volumes map[string][]PancakeVolume
+		prices    map[string]sdk.Dec
+		volumes   map[string][]PancakeVolume
+		liquidity map[string]sdk.Dec
 	)
 
-	prices, volumes, _ = p.query(query)
+	prices, volumes, liquidity, _ = p.query(query)
 
 	p.updateVolumes(volumes)
+	p.pruneVolumes(contracts)
 
 	timestamp := time.Now()
 
@@ -174,6 +180,7 @@ func (p *PancakeProvider) Poll() error {
 			volume = volume.Quo(price)
 		}
 
+		p.setLiquidity(symbol, liquidity[contract])
 		p.setTickerPrice(
 			symbol,
 			price,
@@ -190,6 +197,7 @@ func (p *PancakeProvider) query(
 ) (
 	prices map[string]sdk.Dec,
 	volumes map[string][]PancakeVolume,
+	liquidity map[string]sdk.Dec,
 	err error,
 ) {
 	// version string
@@ -203,6 +211,7 @@ func (p *PancakeProvider) query(
 
 	prices = map[string]sdk.Dec{}
 	volumes = map[string][]PancakeVolume{}
+	liquidity = map[string]sdk.Dec{}
 
 	request, err := json.Marshal(PancakeQuery{Query: query})
 	if err != nil {
@@ -211,7 +220,7 @@ func (p *PancakeProvider) query(
 
 	content, err := p.httpPost(path, request)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	var response PancakeQueryResponse
@@ -220,7 +229,7 @@ func (p *PancakeProvider) query(
 		p.logger.Error().
 			Err(err).
 			Msg("failed unmarshalling response")
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	for _, pool := range response.Data.Pools {
@@ -246,7 +255,7 @@ func (p *PancakeProvider) query(
 			p.logger.Error().
 				Err(err).
 				Msg("failed parsing sqrt price")
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
 		d2 := decimal.NewFromInt(2)
@@ -256,9 +265,17 @@ func (p *PancakeProvider) query(
 		price := strToDec(dec.String())
 
 		prices[pool.Id] = price
+
+		// the locked amounts are in whole tokens, the liquidity is in
+		// terms of token1
+		liquidity[pool.Id] = poolLiquidity(
+			strToDec(pool.Locked0),
+			strToDec(pool.Locked1),
+			strToDec(pool.Token1Price),
+		)
 	}
 
-	return prices, volumes, nil
+	return prices, volumes, liquidity, nil
 }
 
 func (p *PancakeProvider) getQuery(
@@ -281,6 +298,9 @@ func (p *PancakeProvider) getQuery(
 			) {
 			  	id,
 				sqrtPrice,
+				token1Price,
+				totalValueLockedToken0,
+				totalValueLockedToken1,
 			  	poolHourData(
 					where: {
 						periodStartUnix_gte: %d
@@ -336,6 +356,21 @@ func (p *PancakeProvider) updateVolumes(volumes map[string][]PancakeVolume) erro
 	return nil
 }
 
+// pruneVolumes removes the volumes of all pools, that are not configured
+// anymore.
+func (p *PancakeProvider) pruneVolumes(contracts []string) {
+	configured := make(map[string]struct{}, len(contracts))
+	for _, contract := range contracts {
+		configured[contract] = struct{}{}
+	}
+
+	for contract := range p.volumes {
+		if _, found := configured[contract]; !found {
+			delete(p.volumes, contract)
+		}
+	}
+}
+
 func (p *PancakeProvider) getVolume(contract string, tokenId int) (sdk.Dec, error) {
 	value := sdk.NewDec(0)
 	volumes, found := p.volumes[contract]
@@ -368,9 +403,16 @@ func (p *PancakeProvider) getVolume(contract string, tokenId int) (sdk.Dec, erro
 
 func (p *PancakeProvider) init() error {
 	// lowercase contracts, needed for thegraph api calls
-	for symbol, contract := range p.contracts {
-		p.contracts[symbol] = strings.ToLower(contract)
+	contracts := p.contracts.Contracts()
+	for symbol, contract := range contracts {
+		contracts[symbol] = strings.ToLower(contract)
+	}
+
+	registry, err := NewContractRegistry(contracts)
+	if err != nil {
+		return err
 	}
+	p.contracts = registry
 
 	return nil
 }
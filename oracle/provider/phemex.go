@@ -64,6 +64,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderPhemex, phemexDefaultEndpoints, NewPhemexProvider)
+}
+
 func NewPhemexProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -35,6 +35,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderPionex, pionexDefaultEndpoints, NewPionexProvider)
+}
+
 func NewPionexProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
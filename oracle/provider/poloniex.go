@@ -36,6 +36,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderPoloniex, poloniexDefaultEndpoints, NewPoloniexProvider)
+}
+
 func NewPoloniexProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type (
+	// PricePath defines deterministic price paths per symbol, read from a csv
+	// file of the form:
+	//
+	//   offset,symbol,price,volume
+	//   0,ATOMUSD,10.0,1000
+	//   30,ATOMUSD,10.5,1000
+	//
+	// The offset is given in seconds relative to the start of the path, the
+	// volume column is optional and defaults to 1.
+	PricePath struct {
+		points map[string][]pricePoint
+	}
+
+	pricePoint struct {
+		offset time.Duration
+		price  sdk.Dec
+		volume sdk.Dec
+	}
+)
+
+// LoadPricePath reads the price path from the csv file at path.
+func LoadPricePath(path string) (PricePath, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return PricePath{}, err
+	}
+	defer file.Close()
+
+	return ReadPricePath(file)
+}
+
+// ReadPricePath reads a price path in csv format, see PricePath.
+func ReadPricePath(r io.Reader) (PricePath, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return PricePath{}, err
+	}
+
+	path := PricePath{points: map[string][]pricePoint{}}
+
+	for i, record := range records {
+		if len(record) < 3 {
+			return PricePath{}, fmt.Errorf("line %d: expected at least 3 columns", i+1)
+		}
+
+		// skip the header
+		if i == 0 && strings.EqualFold(record[0], "offset") {
+			continue
+		}
+
+		seconds, err := strconv.ParseFloat(record[0], 64)
+		if err != nil || seconds < 0 {
+			return PricePath{}, fmt.Errorf("line %d: invalid offset %s", i+1, record[0])
+		}
+
+		price, err := sdk.NewDecFromStr(record[2])
+		if err != nil {
+			return PricePath{}, fmt.Errorf("line %d: invalid price %s", i+1, record[2])
+		}
+
+		volume := sdk.OneDec()
+		if len(record) > 3 && record[3] != "" {
+			volume, err = sdk.NewDecFromStr(record[3])
+			if err != nil {
+				return PricePath{}, fmt.Errorf("line %d: invalid volume %s", i+1, record[3])
+			}
+		}
+
+		symbol := strings.ToUpper(record[1])
+		path.points[symbol] = append(path.points[symbol], pricePoint{
+			offset: time.Duration(seconds * float64(time.Second)),
+			price:  price,
+			volume: volume,
+		})
+	}
+
+	for _, points := range path.points {
+		sort.SliceStable(points, func(i, j int) bool {
+			return points[i].offset < points[j].offset
+		})
+	}
+
+	return path, nil
+}
+
+// Symbols returns the sorted symbols of all price paths.
+func (p PricePath) Symbols() []string {
+	symbols := make([]string, 0, len(p.points))
+	for symbol := range p.points {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// At returns the latest ticker of each symbol at the given offset, timestamped
+// relative to start. Symbols whose path starts after the offset are omitted.
+func (p PricePath) At(start time.Time, offset time.Duration) map[string]types.TickerPrice {
+	tickers := map[string]types.TickerPrice{}
+
+	for symbol, points := range p.points {
+		index := sort.Search(len(points), func(i int) bool {
+			return points[i].offset > offset
+		}) - 1
+		if index < 0 {
+			continue
+		}
+
+		point := points[index]
+		tickers[symbol] = types.TickerPrice{
+			Price:  point.price,
+			Volume: point.volume,
+			Time:   start.Add(point.offset),
+		}
+	}
+
+	return tickers
+}
+
+// Tickers returns the full price path of the symbol, timestamped relative to
+// start.
+func (p PricePath) Tickers(symbol string, start time.Time) []types.TickerPrice {
+	points := p.points[strings.ToUpper(symbol)]
+
+	tickers := make([]types.TickerPrice, len(points))
+	for i, point := range points {
+		tickers[i] = types.TickerPrice{
+			Price:  point.price,
+			Volume: point.volume,
+			Time:   start.Add(point.offset),
+		}
+	}
+
+	return tickers
+}
@@ -32,15 +32,21 @@ const (
 	defaultTimeout       = 10 * time.Second
 	staleTickersCutoff   = 1 * time.Minute
 	providerCandlePeriod = 10 * time.Minute
+	// httpSelectTimeout bounds the chain id verification of all urls after
+	// the provider is created
+	httpSelectTimeout = 5 * time.Second
+	// maxProviderTickers caps the tickers kept per provider, as pairs can
+	// be subscribed at runtime for the whole lifetime of the process
+	maxProviderTickers = 1000
 
 	ProviderAstroportInjective Name = "astroport_injective"
 	ProviderAstroportNeutron   Name = "astroport_neutron"
 	ProviderAstroportTerra2    Name = "astroport_terra2"
+	ProviderBand               Name = "band"
 	ProviderBinance            Name = "binance"
 	ProviderBinanceUS          Name = "binanceus"
 	ProviderBingx              Name = "bingx"
 	ProviderBitfinex           Name = "bitfinex"
-	ProviderBitforex           Name = "bitforex"
 	ProviderBitget             Name = "bitget"
 	ProviderBitmart            Name = "bitmart"
 	ProviderBitstamp           Name = "bitstamp"
@@ -49,10 +55,12 @@ const (
 	ProviderCamelotV2          Name = "camelotv2"
 	ProviderCamelotV3          Name = "camelotv3"
 	ProviderCoinbase           Name = "coinbase"
+	ProviderCoingecko          Name = "coingecko"
 	ProviderCoinex             Name = "coinex"
 	ProviderCrypto             Name = "crypto"
 	ProviderCurve              Name = "curve"
 	ProviderDexter             Name = "dexter"
+	ProviderFeeder             Name = "feeder"
 	ProviderFin                Name = "fin"
 	ProviderFinV2              Name = "finv2"
 	ProviderGate               Name = "gate"
@@ -60,12 +68,14 @@ const (
 	ProviderHitBtc             Name = "hitbtc"
 	ProviderHuobi              Name = "huobi"
 	ProviderIdxOsmosis         Name = "idxosmosis"
+	ProviderInjectiveOracle    Name = "injectiveoracle"
 	ProviderKraken             Name = "kraken"
 	ProviderKucoin             Name = "kucoin"
 	ProviderLbank              Name = "lbank"
 	ProviderMaya               Name = "maya"
 	ProviderMexc               Name = "mexc"
 	ProviderMock               Name = "mock"
+	ProviderOjo                Name = "ojo"
 	ProviderOkx                Name = "okx"
 	ProviderOsmosis            Name = "osmosis"
 	ProviderOsmosisV2          Name = "osmosisv2"
@@ -73,9 +83,10 @@ const (
 	ProviderPhemex             Name = "phemex"
 	ProviderPionex             Name = "pionex"
 	ProviderPoloniex           Name = "poloniex"
+	ProviderPsm                Name = "psm"
 	ProviderPyth               Name = "pyth"
+	ProviderRedstone           Name = "redstone"
 	ProviderShade              Name = "shade"
-	ProviderStride             Name = "stride"
 	ProviderUniswapV3          Name = "uniswapv3"
 	ProviderUnstake            Name = "unstake"
 	ProviderVelodromeV2        Name = "velodromev2"
@@ -96,6 +107,9 @@ type (
 	Provider interface {
 		// GetTickerPrices returns the tickerPrices based on the provided pairs.
 		GetTickerPrices(...types.CurrencyPair) (map[string]types.TickerPrice, error)
+		// GetCandlePrices returns the recent candles based on the provided
+		// pairs, if the provider polls candles.
+		GetCandlePrices(...types.CurrencyPair) (map[string][]types.CandlePrice, error)
 		// GetAvailablePairs returns the list of all supported pairs.
 		GetAvailablePairs() (map[string]struct{}, error)
 
@@ -104,33 +118,71 @@ type (
 		SubscribeCurrencyPairs(...types.CurrencyPair) error
 		CurrencyPairToProviderPair(types.CurrencyPair) string
 		// ProviderPairToCurrencyPair(string) types.CurrencyPair
+
+		// GetName returns the name of the provider.
+		GetName() Name
+		// Healthy returns true if the provider has received fresh ticker data.
+		Healthy() bool
+		// LastUpdate returns the time of the most recent ticker update.
+		LastUpdate() time.Time
+		// ClockOffset returns the estimated offset of the exchange clock.
+		ClockOffset() time.Duration
 	}
 
 	CurrencyPairToProviderSymbol func(types.CurrencyPair) string
 
 	provider struct {
-		ctx       context.Context
-		name      string
-		endpoints Endpoint
-		httpBase  string
-		http      *http.Client
-		logger    zerolog.Logger
-		mtx       sync.RWMutex
-		pairs     map[string]types.CurrencyPair
-		inverse   map[string]types.CurrencyPair
-		tickers   map[string]types.TickerPrice
-		contracts map[string]string
-		websocket *WebsocketController
-		db        *sql.DB
-		volumes   volume.VolumeHandler
-		height    uint64
-		chain     string
+		ctx        context.Context
+		name       string
+		endpoints  Endpoint
+		httpBase   string
+		http       *http.Client
+		logger     zerolog.Logger
+		mtx        sync.RWMutex
+		pairs      map[string]types.CurrencyPair
+		inverse    map[string]types.CurrencyPair
+		tickers    map[string]types.TickerPrice
+		candles    map[string][]types.CandlePrice
+		trades     map[string][]trade
+		eventTimes map[string]time.Time
+		lastUpdate time.Time
+		quoteVols  map[string]sdk.Dec
+		liquidity  map[string]sdk.Dec
+		contracts  ContractRegistry
+		websocket  *WebsocketController
+		wsUrl      UrlHandler
+		wsTrades   SubscribeHandler
+		// providers whose subscriptions depend on the pairs set after Init
+		// start the websocket themselves, see startWebsocket
+		wsDeferStart bool
+		db           *sql.DB
+		volumes      volume.VolumeHandler
+		height       uint64
+		chain        string
+		// estimated offset of the exchange clock, see timestamps.go
+		clockOffset        time.Duration
+		clockOffsetSamples int
+		// request budget of the provider, see ratelimit.go
+		rateLimit rateLimiter
+		// configured pairs not listed by the exchange yet, see listing.go
+		unlisted []types.CurrencyPair
+		toSymbol CurrencyPairToProviderSymbol
+		// guards httpBase and the health scores of the urls, see
+		// failover.go
+		httpMtx    sync.RWMutex
+		httpHealth map[string]int
 	}
 
 	PollingProvider interface {
 		Poll() error
 	}
 
+	// StaleTickerProvider defines a provider, which tells apart tickers
+	// dropped for being stale from missing tickers.
+	StaleTickerProvider interface {
+		IsStale(symbol string) bool
+	}
+
 	// Name name of an oracle provider. Usually it is an exchange
 	// but this can be any provider name that can give token prices
 	// examples.: "binance", "osmosis", "kraken".
@@ -156,6 +208,22 @@ type (
 		VolumePause       int
 		Decimals          map[string]int
 		Periods           map[string]int
+		Signers           []string
+		ChainId           string            // ex. "osmosis-1" or "1" for evm chains
+		MaxBlockLag       uint64            // evm only, max blocks behind the best url
+		Token             string            // sent as bearer token, e.g. to peer feeders
+		MaxEventAge       time.Duration     // max age of exchange event timestamps
+		Perp              bool              // use perpetual swap markets
+		MaxFundingRate    sdk.Dec           // perps only, max absolute funding rate
+		Headers           map[string]string // sent with every http request and websocket dial
+		ApiKey            string            // keyed api tiers, e.g. coingecko
+		Candles           bool              // poll 1m candles (binance, kraken, okx)
+		PriceSource       string            // "ticker" or "trades", see trades.go
+
+		// "permessage-deflate", "gzip" or "deflate", see compression.go
+		WebsocketCompression string
+		// "ipv4" or "ipv6" to restrict the connections, see dial.go
+		IPVersion string
 	}
 
 	EvmLog struct {
@@ -190,15 +258,27 @@ func (p *provider) Init(
 
 	p.logger = logger.With().Str("provider", p.endpoints.Name.String()).Logger()
 	p.tickers = map[string]types.TickerPrice{}
-	p.http = newDefaultHTTPClient()
+	p.candles = map[string][]types.CandlePrice{}
+	p.trades = map[string][]trade{}
+	p.eventTimes = map[string]time.Time{}
+	p.quoteVols = map[string]sdk.Dec{}
+	p.liquidity = map[string]sdk.Dec{}
+	p.http = newEndpointHTTPClient(defaultTimeout, p.endpoints.IPVersion)
 
 	if len(p.endpoints.Urls) == 0 {
 		p.logger.Error().Msg("no endpoint urls found")
 		return
 	}
+	// the first url is used until the chain ids are verified, so slow
+	// endpoints don't delay the creation of the providers
 	p.httpBase = p.endpoints.Urls[0]
+	go p.selectHttpBase()
 
-	p.contracts = endpoints.ContractAddresses
+	if isEvmChainId(p.endpoints.ChainId) {
+		go p.monitorEvmEndpoints()
+	} else {
+		go p.monitorHttpEndpoints()
+	}
 
 	if p.endpoints.Websocket != "" {
 		websocketUrl := url.URL{
@@ -213,21 +293,27 @@ func (p *provider) Init(
 			pairs,
 			websocketMessageHandler,
 			websocketSubscribeHandler,
+			p.wsUrl,
 			p.endpoints.PingDuration,
 			p.endpoints.PingType,
 			p.endpoints.PingMessage,
+			p.endpoints.Headers,
 			p.logger,
 		)
-		go p.websocket.Start()
+		p.websocket.SetCompression(p.endpoints.WebsocketCompression)
+		p.websocket.SetIPVersion(p.endpoints.IPVersion)
+		if !p.wsDeferStart {
+			p.startWebsocket()
+		}
 	}
 
 	// set contract<>symbol mapping
 
-	p.contracts = endpoints.ContractAddresses
-
-	for symbol, contract := range endpoints.ContractAddresses {
-		p.contracts[contract] = symbol
+	contracts, err := NewContractRegistry(p.endpoints.ContractAddresses)
+	if err != nil {
+		p.logger.Error().Err(err).Msg("duplicate contract address")
 	}
+	p.contracts = contracts
 
 	p.height = 0
 
@@ -260,7 +346,7 @@ func (p *provider) Init(
 	var period int64 = 86400
 	name := endpoints.Name.String()
 
-	volumes, err := volume.NewVolumeHandler(logger, p.db, name, symbols, period)
+	volumes, err := volume.NewVolumeHandler(ctx, logger, p.db, name, symbols, period)
 	if err != nil {
 		panic(err)
 	}
@@ -289,6 +375,12 @@ func (p *provider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]type
 					Str("pair", symbol).
 					Time("time", price.Time).
 					Msg("tickers data is stale")
+			} else if eventTime, stale := p.staleEvent(symbol); stale {
+				p.logger.Warn().
+					Str("pair", symbol).
+					Time("event_time", eventTime).
+					Msg("exchange event time is stale")
+				telemetryStaleEvent(p.endpoints.Name, symbol)
 			} else {
 				tickers[symbol] = price
 			}
@@ -297,6 +389,28 @@ func (p *provider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]type
 	return tickers, nil
 }
 
+func (p *provider) GetName() Name {
+	return p.endpoints.Name
+}
+
+func (p *provider) Healthy() bool {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	if len(p.tickers) == 0 {
+		return false
+	}
+
+	return time.Since(p.lastUpdate) <= staleTickersCutoff
+}
+
+func (p *provider) LastUpdate() time.Time {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	return p.lastUpdate
+}
+
 func (p *provider) SubscribeCurrencyPairs(pairs ...types.CurrencyPair) error {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
@@ -558,56 +672,146 @@ func (p *provider) httpPost(path string, body []byte) ([]byte, error) {
 	return p.httpRequest(path, "POST", body, headers)
 }
 
-func (p *provider) httpRequest(path string, method string, body []byte, headers map[string]string) ([]byte, error) {
-	res, err := p.makeHttpRequest(p.httpBase+path, method, body, headers)
-	if err != nil {
-		index := 0
-		urls := []string{}
+// startWebsocket subscribes the trades, if the provider streams them, and
+// starts the websocket connection.
+func (p *provider) startWebsocket() {
+	if p.websocket == nil {
+		return
+	}
+	if p.wsTrades != nil {
+		p.subscribeTrades(p.wsTrades)
+	}
+	go p.websocket.Start()
+}
 
-		for i, url := range p.endpoints.Urls {
-			if p.httpBase == url {
-				index = i
-				break
-			}
-		}
+// selectHttpBase sets the first url serving the expected chain as http base.
+// All urls are verified concurrently, but the results are checked in
+// configured order, so a preferred url answering within httpSelectTimeout
+// wins over faster ones. If no url can be verified, the http base is left
+// unchanged.
+func (p *provider) selectHttpBase() {
+	if p.endpoints.ChainId == "" {
+		return
+	}
 
-		urls = append(urls, p.endpoints.Urls[index+1:]...)
-		urls = append(urls, p.endpoints.Urls[:index]...)
+	results := make([]chan error, len(p.endpoints.Urls))
+	for i, url := range p.endpoints.Urls {
+		// buffered, so probes finishing after the timeout don't block
+		results[i] = make(chan error, 1)
+		go func(url string, result chan<- error) {
+			result <- p.verifyChainId(url)
+		}(url, results[i])
+	}
 
-		for _, endpoint := range urls {
-			p.logger.Warn().
-				Str("endpoint", endpoint).
-				Msg("trying alternate http endpoints")
-
-			res, err = p.makeHttpRequest(endpoint+path, method, body, headers)
-			if err == nil {
-				p.logger.Info().Str("endpoint", endpoint).Msg("selected alternate http endpoint")
-				p.httpBase = endpoint
-				break
+	timeout := time.NewTimer(httpSelectTimeout)
+	defer timeout.Stop()
+
+	for i, url := range p.endpoints.Urls {
+		select {
+		case err := <-results[i]:
+			if err != nil {
+				p.logger.Error().
+					Err(err).
+					Str("endpoint", url).
+					Msg("failed to verify chain id")
+				continue
 			}
+
+			p.setHttpBase(url)
+			return
+		case <-timeout.C:
+			p.logger.Error().
+				Str("chain_id", p.endpoints.ChainId).
+				Dur("timeout", httpSelectTimeout).
+				Msg("timed out verifying the chain id of the http endpoints")
+			return
 		}
 	}
-	return res, err
+
+	p.logger.Error().
+		Str("chain_id", p.endpoints.ChainId).
+		Msg("no endpoint serving the expected chain found")
 }
 
-func (p *provider) makeHttpRequest(url string, method string, body []byte, headers map[string]string) ([]byte, error) {
+// verifyChainId returns an error, if the cosmos node or evm rpc behind the
+// url serves a different chain than configured for the provider.
+func (p *provider) verifyChainId(url string) error {
+	if p.endpoints.ChainId == "" {
+		return nil
+	}
+
+	if isEvmChainId(p.endpoints.ChainId) {
+		return p.verifyEvmChainId(url)
+	}
+
+	content, err := p.makeHttpRequest(
+		url+"/cosmos/base/tendermint/v1beta1/node_info", "GET", nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		NodeInfo struct {
+			Network string `json:"network"`
+		} `json:"default_node_info"`
+	}
+
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return err
+	}
+
+	if response.NodeInfo.Network != p.endpoints.ChainId {
+		return fmt.Errorf(
+			"expected chain id %s, got %s",
+			p.endpoints.ChainId, response.NodeInfo.Network,
+		)
+	}
+
+	return nil
+}
+
+// newHttpRequest returns a request with the configured headers and token of
+// the provider.
+func (p *provider) newHttpRequest(url string, method string, body []byte, headers map[string]string) (*http.Request, error) {
 	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
 
+	for key, value := range p.endpoints.Headers {
+		req.Header.Set(key, value)
+	}
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
+	if p.endpoints.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.endpoints.Token)
+	}
+
+	return req, nil
+}
+
+func (p *provider) makeHttpRequest(url string, method string, body []byte, headers map[string]string) ([]byte, error) {
+	req, err := p.newHttpRequest(url, method, body, headers)
+	if err != nil {
+		return nil, err
+	}
 
 	res, err := p.http.Do(req)
 	if err != nil {
 		p.logger.Warn().
 			Err(err).
 			Msg("http request failed")
+		// the next request dials a fresh connection and resolves the host
+		// again, in case the endpoint moved
+		p.http.CloseIdleConnections()
 		return nil, err
 	}
 
+	p.rateLimit.observe(p.logger, res.Header, res.StatusCode, time.Now())
+
 	if res.StatusCode != 200 {
 		p.logger.Warn().
 			Int("code", res.StatusCode).
@@ -635,119 +839,12 @@ func (p *provider) makeHttpRequest(url string, method string, body []byte, heade
 }
 
 func (e *Endpoint) SetDefaults() {
-	var defaults Endpoint
-	switch e.Name {
-	case ProviderAstroportInjective:
-		defaults = astroportInjectiveDefaultEndpoints
-	case ProviderAstroportNeutron:
-		defaults = astroportNeutronDefaultEndpoints
-	case ProviderAstroportTerra2:
-		defaults = astroportTerra2DefaultEndpoints
-	case ProviderBinance:
-		defaults = binanceDefaultEndpoints
-	case ProviderBitfinex:
-		defaults = bitfinexDefaultEndpoints
-	case ProviderBinanceUS:
-		defaults = binanceUSDefaultEndpoints
-	case ProviderBingx:
-		defaults = bingxDefaultEndpoints
-	case ProviderBitget:
-		defaults = bitgetDefaultEndpoints
-	case ProviderBitmart:
-		defaults = bitmartDefaultEndpoints
-	case ProviderBitstamp:
-		defaults = bitstampDefaultEndpoints
-	case ProviderBkex:
-		defaults = bkexDefaultEndpoints
-	case ProviderBybit:
-		defaults = bybitDefaultEndpoints
-	case ProviderCamelotV2:
-		defaults = camelotV2DefaultEndpoints
-	case ProviderCamelotV3:
-		defaults = camelotV3DefaultEndpoints
-	case ProviderCoinbase:
-		defaults = coinbaseDefaultEndpoints
-	case ProviderCoinex:
-		defaults = coinexDefaultEndpoints
-	case ProviderCrypto:
-		defaults = cryptoDefaultEndpoints
-	case ProviderCurve:
-		defaults = curveDefaultEndpoints
-	case ProviderDexter:
-		defaults = dexterDefaultEndpoints
-	case ProviderFin:
-		defaults = finDefaultEndpoints
-	case ProviderFinV2:
-		defaults = finV2DefaultEndpoints
-	case ProviderGate:
-		defaults = gateDefaultEndpoints
-	case ProviderHelix:
-		defaults = helixDefaultEndpoints
-	case ProviderHitBtc:
-		defaults = hitbtcDefaultEndpoints
-	case ProviderIdxOsmosis:
-		defaults = idxOsmosisDefaultEndpoints
-	case ProviderHuobi:
-		defaults = huobiDefaultEndpoints
-	case ProviderKraken:
-		defaults = krakenDefaultEndpoints
-	case ProviderKucoin:
-		defaults = kucoinDefaultEndpoints
-	case ProviderLbank:
-		defaults = lbankDefaultEndpoints
-	case ProviderMexc:
-		defaults = mexcDefaultEndpoints
-	case ProviderMaya:
-		defaults = mayaDefaultEndpoints
-	case ProviderMock:
-		defaults = mockDefaultEndpoints
-	case ProviderOkx:
-		defaults = okxDefaultEndpoints
-	case ProviderOsmosis:
-		defaults = osmosisDefaultEndpoints
-	case ProviderOsmosisV2:
-		defaults = osmosisv2DefaultEndpoints
-	case ProviderPancakeV3Bsc:
-		defaults = PancakeV3BscDefaultEndpoints
-	case ProviderPhemex:
-		defaults = phemexDefaultEndpoints
-	case ProviderPionex:
-		defaults = pionexDefaultEndpoints
-	case ProviderPoloniex:
-		defaults = poloniexDefaultEndpoints
-	case ProviderPyth:
-		defaults = pythDefaultEndpoints
-	case ProviderShade:
-		defaults = shadeDefaultEndpoints
-	case ProviderUniswapV3:
-		defaults = uniswapv3DefaultEndpoints
-	case ProviderUnstake:
-		defaults = unstakeDefaultEndpoints
-	case ProviderVelodromeV2:
-		defaults = velodromev2DefaultEndpoints
-	case ProviderWhitewhaleCmdx:
-		defaults = whitewhaleCmdxDefaultEndpoints
-	case ProviderWhitewhaleHuahua:
-		defaults = whitewhaleHuahuaDefaultEndpoints
-	case ProviderWhitewhaleInj:
-		defaults = whitewhaleInjDefaultEndpoints
-	case ProviderWhitewhaleJuno:
-		defaults = whitewhaleJunoDefaultEndpoints
-	case ProviderWhitewhaleLunc:
-		defaults = whitewhaleLuncDefaultEndpoints
-	case ProviderWhitewhaleLuna:
-		defaults = whitewhaleLunaDefaultEndpoints
-	case ProviderWhitewhaleSei:
-		defaults = whitewhaleSeiDefaultEndpoints
-	case ProviderWhitewhaleWhale:
-		defaults = whitewhaleWhaleDefaultEndpoints
-	case ProviderXt:
-		defaults = xtDefaultEndpoints
-	case ProviderZero:
-		defaults = zeroDefaultEndpoints
-	default:
+	registration, found := registry[e.Name]
+	if !found {
 		return
 	}
+	defaults := registration.Defaults
+
 	if e.Urls == nil {
 		urls := defaults.Urls
 		rand.Seed(time.Now().UnixNano())
@@ -763,6 +860,9 @@ func (e *Endpoint) SetDefaults() {
 	if e.WebsocketPath == "" {
 		e.WebsocketPath = defaults.WebsocketPath
 	}
+	if e.WebsocketCompression == "" {
+		e.WebsocketCompression = defaults.WebsocketCompression
+	}
 	if e.PollInterval == time.Duration(0) {
 		e.PollInterval = defaults.PollInterval
 	}
@@ -795,6 +895,18 @@ func (e *Endpoint) SetDefaults() {
 	if e.VolumePause <= 0 {
 		e.VolumePause = defaults.VolumePause
 	}
+
+	if len(e.Signers) == 0 {
+		e.Signers = defaults.Signers
+	}
+
+	if e.ChainId == "" {
+		e.ChainId = defaults.ChainId
+	}
+
+	if e.MaxBlockLag == 0 {
+		e.MaxBlockLag = defaults.MaxBlockLag
+	}
 }
 
 func startPolling(p PollingProvider, interval time.Duration, logger zerolog.Logger) {
@@ -804,7 +916,12 @@ func startPolling(p PollingProvider, interval time.Duration, logger zerolog.Logg
 		if err != nil {
 			logger.Error().Err(err).Msg("failed to poll")
 		}
-		time.Sleep(interval)
+
+		delay := interval
+		if limited, ok := p.(rateLimitedProvider); ok {
+			delay = limited.pollDelay(interval, time.Now())
+		}
+		time.Sleep(delay)
 	}
 }
 
@@ -815,12 +932,14 @@ func (p *provider) setPairs(
 ) error {
 	p.pairs = map[string]types.CurrencyPair{}
 	p.inverse = map[string]types.CurrencyPair{}
+	p.unlisted = nil
 
 	if toProviderSymbol == nil {
 		toProviderSymbol = func(pair types.CurrencyPair) string {
 			return pair.String()
 		}
 	}
+	p.toSymbol = toProviderSymbol
 
 	if availablePairs == nil {
 		p.logger.Warn().Msg("available pairs not provided")
@@ -856,6 +975,7 @@ func (p *provider) setPairs(
 
 		p.logger.Error().
 			Msgf("%s is not supported by this provider", symbol)
+		p.unlisted = append(p.unlisted, pair)
 	}
 
 	return nil
@@ -880,16 +1000,38 @@ func (p *provider) setTickerPrice(
 			Msg("volume is zero")
 	}
 
+	liquidity, hasLiquidity := p.liquidity[symbol]
+
 	// check if price needs to be inverted
 	pair, inverse := p.inverse[symbol]
 	if inverse {
-		volume = volume.Mul(price)
+		// prefer the quote volume reported by the provider over the
+		// approximation based on the last price
+		quoteVolume, found := p.quoteVols[symbol]
+		if found && quoteVolume.IsPositive() {
+			volume = quoteVolume
+		} else {
+			volume = volume.Mul(price)
+		}
+		// liquidity in terms of the new quote
+		if hasLiquidity {
+			liquidity = liquidity.Quo(price)
+		}
 		price = invertDec(price)
 
 		p.tickers[pair.String()] = types.TickerPrice{
-			Price:  price,
-			Volume: volume,
-			Time:   timestamp,
+			Price:     price,
+			Volume:    volume,
+			Time:      timestamp,
+			Liquidity: liquidity,
+		}
+		delete(p.eventTimes, pair.String())
+		p.setLastUpdate(timestamp)
+		p.limitTickers()
+		if hasLiquidity {
+			TelemetryProviderLiquidity(
+				p.endpoints.Name, pair.String(), float32(liquidity.MustFloat64()),
+			)
 		}
 
 		TelemetryProviderPrice(
@@ -911,9 +1053,18 @@ func (p *provider) setTickerPrice(
 	}
 
 	p.tickers[pair.String()] = types.TickerPrice{
-		Price:  price,
-		Volume: volume,
-		Time:   timestamp,
+		Price:     price,
+		Volume:    volume,
+		Time:      timestamp,
+		Liquidity: liquidity,
+	}
+	delete(p.eventTimes, pair.String())
+	p.setLastUpdate(timestamp)
+	p.limitTickers()
+	if hasLiquidity {
+		TelemetryProviderLiquidity(
+			p.endpoints.Name, pair.String(), float32(liquidity.MustFloat64()),
+		)
 	}
 
 	TelemetryProviderPrice(
@@ -924,6 +1075,102 @@ func (p *provider) setTickerPrice(
 	)
 }
 
+func (p *provider) setLastUpdate(timestamp time.Time) {
+	if timestamp.After(p.lastUpdate) {
+		p.lastUpdate = timestamp
+	}
+}
+
+// setTickerPriceFromVolumes sets the ticker price of the provider symbol with
+// the volumes tracked by the volume handler.
+func (p *provider) setTickerPriceFromVolumes(
+	symbol string,
+	price sdk.Dec,
+	timestamp time.Time,
+) {
+	pair, found := p.getPair(symbol)
+	if !found {
+		return
+	}
+
+	baseVolume, _ := p.volumes.Get(pair.Base + pair.Quote)
+	quoteVolume, _ := p.volumes.Get(pair.Quote + pair.Base)
+
+	p.setTickerPriceAndVolumes(symbol, price, baseVolume, quoteVolume, timestamp)
+}
+
+// setTickerPriceAndVolumes sets the ticker price of the provider symbol, with
+// the traded amounts of both the base and the quote denom. If the symbol needs
+// to be inverted, the quote amount is used as volume of the resulting pair.
+func (p *provider) setTickerPriceAndVolumes(
+	symbol string,
+	price sdk.Dec,
+	baseVolume sdk.Dec,
+	quoteVolume sdk.Dec,
+	timestamp time.Time,
+) {
+	if baseVolume.IsNil() {
+		baseVolume = sdk.ZeroDec()
+	}
+
+	p.setQuoteVolume(symbol, quoteVolume)
+	p.setTickerPrice(symbol, price, baseVolume, timestamp)
+}
+
+// limitTickers evicts the least recently updated tickers, with their event
+// times and candles, once the provider keeps more than maxProviderTickers.
+// The caller must hold p.mtx.
+func (p *provider) limitTickers() {
+	for len(p.tickers) > maxProviderTickers {
+		var (
+			oldest     string
+			oldestTime time.Time
+		)
+		for symbol, ticker := range p.tickers {
+			if oldest == "" || ticker.Time.Before(oldestTime) {
+				oldest = symbol
+				oldestTime = ticker.Time
+			}
+		}
+
+		p.logger.Debug().Str("symbol", oldest).Msg("evicting least recently updated ticker")
+		delete(p.tickers, oldest)
+		delete(p.eventTimes, oldest)
+		delete(p.candles, oldest)
+	}
+}
+
+// setQuoteVolume sets the 24h quote volume of the provider symbol, which is
+// used as base volume if the symbol needs to be inverted. Volumes of symbols,
+// that are not configured, are dropped, so providers can pass all tickers of
+// the exchange without growing the map.
+func (p *provider) setQuoteVolume(symbol string, volume sdk.Dec) {
+	if volume.IsNil() || !p.isPair(symbol) {
+		return
+	}
+	p.quoteVols[symbol] = volume
+}
+
+// setLiquidity sets the pool liquidity of the provider symbol in quote
+// terms, which is added to the tickers of the symbol.
+func (p *provider) setLiquidity(symbol string, liquidity sdk.Dec) {
+	if liquidity.IsNil() || !liquidity.IsPositive() || !p.isPair(symbol) {
+		delete(p.liquidity, symbol)
+		return
+	}
+	p.liquidity[symbol] = liquidity
+}
+
+// poolLiquidity returns the value of both pool reserves in terms of the
+// quote, using the price of the base. If any value is missing, the
+// liquidity is nil, which setLiquidity drops.
+func poolLiquidity(baseReserve, quoteReserve, price sdk.Dec) sdk.Dec {
+	if baseReserve.IsNil() || quoteReserve.IsNil() || price.IsNil() {
+		return sdk.Dec{}
+	}
+	return baseReserve.Mul(price).Add(quoteReserve)
+}
+
 func (p *provider) isPair(symbol string) bool {
 	if _, found := p.pairs[symbol]; found {
 		return true
@@ -977,12 +1224,12 @@ func (p *provider) getPair(symbol string) (types.CurrencyPair, bool) {
 }
 
 func (p *provider) getContractAddress(pair types.CurrencyPair) (string, error) {
-	address, found := p.contracts[pair.String()]
+	address, found := p.contracts.Contract(pair.String())
 	if found {
 		return address, nil
 	}
 
-	address, found = p.contracts[pair.Quote+pair.Base]
+	address, found = p.contracts.Contract(pair.Quote + pair.Base)
 	if found {
 		return address, nil
 	}
@@ -1082,6 +1329,61 @@ func (p *provider) getEthDecimals(contract string) (uint64, error) {
 	return decimals, nil
 }
 
+// getEthBalance returns the token balance of the account in whole tokens.
+func (p *provider) getEthBalance(
+	token, account string,
+	decimals uint64,
+) (sdk.Dec, error) {
+	hash, err := keccak256("balanceOf(address)")
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	account = strings.ToLower(strings.TrimPrefix(account, "0x"))
+	if len(account) != 40 {
+		return sdk.Dec{}, fmt.Errorf("invalid address %s", account)
+	}
+
+	data := fmt.Sprintf("%0.8s%024d%s", hash, 0, account)
+
+	result, err := p.doEthCall(token, data)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	decoded, err := decodeEthData(result, []string{"uint256"})
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	balance := strToDec(fmt.Sprintf("%v", decoded[0]))
+	if balance.IsNil() {
+		return sdk.Dec{}, fmt.Errorf("invalid balance")
+	}
+
+	return balance.Quo(uintToDec(10).Power(decimals)), nil
+}
+
+// getEthPoolLiquidity returns the value of the base and quote token
+// balances of the pool contract in terms of the quote token.
+func (p *provider) getEthPoolLiquidity(
+	contract, base, quote string,
+	decimalsBase, decimalsQuote uint64,
+	price sdk.Dec,
+) (sdk.Dec, error) {
+	baseReserve, err := p.getEthBalance(base, contract, decimalsBase)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	quoteReserve, err := p.getEthBalance(quote, contract, decimalsQuote)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	return poolLiquidity(baseReserve, quoteReserve, price), nil
+}
+
 func (p *provider) evmRpcQuery(method, params string) (json.RawMessage, error) {
 	p.logger.Info().Str("method", method).Msg("query evm rpc")
 
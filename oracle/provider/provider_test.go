@@ -1,12 +1,18 @@
 package provider
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"price-feeder/oracle/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 )
 
@@ -85,3 +91,246 @@ func TestStrToDec(t *testing.T) {
 		require.Equal(t, sdk.Dec{}, dec)
 	})
 }
+
+func TestSetTickerPriceAndVolumes(t *testing.T) {
+	p := provider{
+		endpoints: Endpoint{Name: ProviderMock},
+		logger:    zerolog.Nop(),
+		pairs: map[string]types.CurrencyPair{
+			"ATOMUSDT": testAtomUsdtCurrencyPair,
+		},
+		inverse: map[string]types.CurrencyPair{
+			"USDTBTC": testBtcUsdtCurrencyPair,
+		},
+		tickers:   map[string]types.TickerPrice{},
+		quoteVols: map[string]sdk.Dec{},
+	}
+
+	timestamp := time.Now()
+
+	t.Run("direct", func(t *testing.T) {
+		p.setTickerPriceAndVolumes(
+			"ATOMUSDT",
+			sdk.NewDec(10),
+			sdk.NewDec(100),
+			sdk.NewDec(1000),
+			timestamp,
+		)
+
+		ticker := p.tickers["ATOMUSDT"]
+		require.Equal(t, sdk.NewDec(10), ticker.Price)
+		require.Equal(t, sdk.NewDec(100), ticker.Volume)
+		require.Equal(t, timestamp, p.lastUpdate)
+	})
+
+	t.Run("inverse", func(t *testing.T) {
+		// 1 USDT = 0.00002 BTC, 50000 USDT traded for 1 BTC
+		p.setTickerPriceAndVolumes(
+			"USDTBTC",
+			sdk.MustNewDecFromStr("0.00002"),
+			sdk.NewDec(50000),
+			sdk.NewDec(1),
+			timestamp,
+		)
+
+		ticker := p.tickers["BTCUSDT"]
+		require.Equal(t, sdk.NewDec(50000), ticker.Price)
+		require.Equal(t, sdk.NewDec(1), ticker.Volume)
+	})
+
+	t.Run("inverse_without_quote_volume", func(t *testing.T) {
+		p.setTickerPriceAndVolumes(
+			"USDTBTC",
+			sdk.MustNewDecFromStr("0.00002"),
+			sdk.NewDec(50000),
+			sdk.ZeroDec(),
+			timestamp,
+		)
+
+		ticker := p.tickers["BTCUSDT"]
+		require.Equal(t, sdk.NewDec(1), ticker.Volume)
+	})
+
+	t.Run("nil_volume", func(t *testing.T) {
+		p.setTickerPriceAndVolumes(
+			"ATOMUSDT",
+			sdk.NewDec(10),
+			sdk.Dec{},
+			sdk.Dec{},
+			timestamp,
+		)
+
+		ticker := p.tickers["ATOMUSDT"]
+		require.True(t, ticker.Volume.IsZero())
+	})
+}
+
+func TestSetLiquidity(t *testing.T) {
+	p := provider{
+		endpoints: Endpoint{Name: ProviderMock},
+		logger:    zerolog.Nop(),
+		pairs: map[string]types.CurrencyPair{
+			"ATOMUSDT": testAtomUsdtCurrencyPair,
+		},
+		inverse: map[string]types.CurrencyPair{
+			"USDTBTC": testBtcUsdtCurrencyPair,
+		},
+		tickers:   map[string]types.TickerPrice{},
+		quoteVols: map[string]sdk.Dec{},
+		liquidity: map[string]sdk.Dec{},
+	}
+
+	timestamp := time.Now()
+
+	p.setLiquidity("ATOMUSDT", sdk.NewDec(20000))
+	p.setTickerPrice("ATOMUSDT", sdk.NewDec(10), sdk.ZeroDec(), timestamp)
+	require.Equal(t, sdk.NewDec(20000), p.tickers["ATOMUSDT"].Liquidity)
+
+	// 2 BTC of liquidity are 100000 USDT at 50000 USDT per BTC
+	p.setLiquidity("USDTBTC", sdk.NewDec(2))
+	p.setTickerPrice("USDTBTC", sdk.MustNewDecFromStr("0.00002"), sdk.ZeroDec(), timestamp)
+	require.Equal(t, sdk.NewDec(100000), p.tickers["BTCUSDT"].Liquidity)
+
+	// invalid liquidity is removed
+	p.setLiquidity("ATOMUSDT", sdk.ZeroDec())
+	p.setTickerPrice("ATOMUSDT", sdk.NewDec(10), sdk.ZeroDec(), timestamp)
+	require.True(t, p.tickers["ATOMUSDT"].Liquidity.IsNil())
+}
+
+func TestPoolLiquidity(t *testing.T) {
+	// 10 ATOM at 12 USDT and 80 USDT
+	require.Equal(
+		t,
+		sdk.NewDec(200),
+		poolLiquidity(sdk.NewDec(10), sdk.NewDec(80), sdk.NewDec(12)),
+	)
+
+	require.True(t, poolLiquidity(sdk.Dec{}, sdk.NewDec(80), sdk.NewDec(12)).IsNil())
+}
+
+func TestGetEthBalance(t *testing.T) {
+	var calldata string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Params []struct {
+				Data string `json:"data"`
+			} `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		calldata = request.Params[0].Data
+
+		// 1.5 tokens with 6 decimals
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"0x%064x"}`, 1500000)
+	}))
+	defer server.Close()
+
+	p := provider{
+		logger:   zerolog.Nop(),
+		http:     newDefaultHTTPClient(),
+		httpBase: server.URL,
+	}
+
+	account := "0x0B2C639C533813F4AA9D7837CAF62653D097FF85"
+	balance, err := p.getEthBalance(server.URL, account, 6)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("1.5"), balance)
+
+	// balanceOf(address) with the padded account
+	require.Equal(
+		t,
+		"0x70a08231000000000000000000000000"+strings.ToLower(account[2:]),
+		calldata,
+	)
+
+	_, err = p.getEthBalance(server.URL, "0x1234", 6)
+	require.Error(t, err)
+}
+
+func TestUnconfiguredSymbolsAreDropped(t *testing.T) {
+	p := provider{
+		endpoints: Endpoint{Name: ProviderMock},
+		logger:    zerolog.Nop(),
+		pairs: map[string]types.CurrencyPair{
+			"ATOMUSDT": testAtomUsdtCurrencyPair,
+		},
+		inverse:   map[string]types.CurrencyPair{},
+		tickers:   map[string]types.TickerPrice{},
+		quoteVols: map[string]sdk.Dec{},
+		liquidity: map[string]sdk.Dec{},
+	}
+
+	// providers pass the volumes of all exchange symbols
+	p.setQuoteVolume("ATOMUSDT", sdk.NewDec(1000))
+	p.setQuoteVolume("FOOBAR", sdk.NewDec(1000))
+	p.setLiquidity("FOOBAR", sdk.NewDec(1000))
+	p.setTickerPrice("FOOBAR", sdk.NewDec(10), sdk.NewDec(100), time.Now())
+
+	require.Equal(t, map[string]sdk.Dec{"ATOMUSDT": sdk.NewDec(1000)}, p.quoteVols)
+	require.Empty(t, p.liquidity)
+	require.Empty(t, p.tickers)
+}
+
+func TestLimitTickers(t *testing.T) {
+	p := provider{
+		logger:     zerolog.Nop(),
+		tickers:    map[string]types.TickerPrice{},
+		eventTimes: map[string]time.Time{},
+		candles:    map[string][]types.CandlePrice{},
+	}
+
+	now := time.Now()
+	for i := 0; i <= maxProviderTickers; i++ {
+		symbol := fmt.Sprintf("DENOM%dUSD", i)
+		p.tickers[symbol] = types.TickerPrice{
+			Price: sdk.OneDec(),
+			Time:  now.Add(time.Duration(i) * time.Second),
+		}
+		p.eventTimes[symbol] = now
+		p.candles[symbol] = []types.CandlePrice{}
+	}
+
+	// the least recently updated ticker is evicted
+	p.limitTickers()
+	require.Len(t, p.tickers, maxProviderTickers)
+	require.NotContains(t, p.tickers, "DENOM0USD")
+	require.NotContains(t, p.eventTimes, "DENOM0USD")
+	require.NotContains(t, p.candles, "DENOM0USD")
+	require.Contains(t, p.tickers, "DENOM1USD")
+
+	// updated tickers are kept
+	p.tickers["DENOM1USD"] = types.TickerPrice{Price: sdk.OneDec(), Time: now.Add(time.Hour)}
+	p.tickers["NEWUSD"] = types.TickerPrice{Price: sdk.OneDec(), Time: now.Add(time.Hour)}
+	p.limitTickers()
+	require.Contains(t, p.tickers, "DENOM1USD")
+	require.NotContains(t, p.tickers, "DENOM2USD")
+}
+
+func TestHttpRequestHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "price-feeder", r.Header.Get("User-Agent"))
+		require.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	p := provider{
+		endpoints: Endpoint{
+			Name: ProviderMock,
+			Headers: map[string]string{
+				"User-Agent": "price-feeder",
+				"x-api-key":  "secret",
+				// request specific headers take precedence
+				"Content-Type": "text/plain",
+			},
+		},
+		logger:   zerolog.Nop(),
+		http:     newDefaultHTTPClient(),
+		httpBase: server.URL,
+	}
+
+	content, err := p.httpPost("/", []byte("{}"))
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(content))
+}
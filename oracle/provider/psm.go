@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+)
+
+var (
+	_                   Provider = (*PsmProvider)(nil)
+	psmDefaultEndpoints          = Endpoint{
+		Name:         ProviderPsm,
+		Urls:         []string{},
+		PollInterval: 30 * time.Second,
+		ChainId:      "1",
+		MaxBlockLag:  5,
+	}
+)
+
+type (
+	// PsmProvider defines an oracle provider deriving the price of a
+	// stablecoin from the fees of its peg stability module. Supported are
+	// modules with the interface of the maker PSM (tin() and tout()), e.g.
+	// the DAI PSM and LitePSM. The pair base is the stablecoin, the quote is
+	// the collateral (gem) it is swapped 1:1 against.
+	//
+	// REF: https://docs.makerdao.com/smart-contract-modules/core-module/psm-detailed-documentation
+	PsmProvider struct {
+		provider
+	}
+)
+
+func init() {
+	register(ProviderPsm, psmDefaultEndpoints, NewPsmProvider)
+}
+
+func NewPsmProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*PsmProvider, error) {
+	provider := &PsmProvider{}
+	provider.Init(
+		ctx,
+		endpoints,
+		logger,
+		pairs,
+		nil,
+		nil,
+	)
+
+	availablePairs, _ := provider.GetAvailablePairs()
+	provider.setPairs(pairs, availablePairs, nil)
+
+	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	return provider, nil
+}
+
+func (p *PsmProvider) Poll() error {
+	timestamp := time.Now()
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for symbol, pair := range p.getAllPairs() {
+		contract, err := p.getContractAddress(pair)
+		if err != nil {
+			p.logger.Warn().
+				Str("symbol", symbol).
+				Msg("no contract address found")
+			continue
+		}
+
+		tin, err := p.getFee(contract, "tin()")
+		if err != nil {
+			p.logger.Err(err).Str("symbol", symbol).Msg("failed to get tin")
+			continue
+		}
+
+		tout, err := p.getFee(contract, "tout()")
+		if err != nil {
+			p.logger.Err(err).Str("symbol", symbol).Msg("failed to get tout")
+			continue
+		}
+
+		price, err := psmPrice(tin, tout)
+		if err != nil {
+			p.logger.Err(err).Str("symbol", symbol).Msg("")
+			continue
+		}
+
+		p.setTickerPrice(symbol, price, sdk.ZeroDec(), timestamp)
+	}
+
+	p.logger.Debug().Msg("updated tickers")
+	return nil
+}
+
+func (p *PsmProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	return p.getAvailablePairsFromContracts()
+}
+
+// getFee returns the fee of the method, scaled by 1e18 on chain.
+func (p *PsmProvider) getFee(contract, method string) (sdk.Dec, error) {
+	hash, err := keccak256(method)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	response, err := p.doEthCall(contract, hash[:8])
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	decoded, err := decodeEthData(response, []string{"uint256"})
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	fee := strToDec(fmt.Sprintf("%v", decoded[0]))
+	if fee.IsNil() {
+		return sdk.Dec{}, fmt.Errorf("invalid fee")
+	}
+
+	return fee.Quo(uintToDec(10).Power(18)), nil
+}
+
+// psmPrice returns the price of the stablecoin in units of the gem. Minting
+// (selling gem) costs tin, so the stablecoin is worth at most 1/(1-tin) gem.
+// Redeeming (buying gem) costs tout, so it is worth at least 1/(1+tout) gem.
+// The price is the middle of both bounds. A fee of 1 or more halts its
+// direction, in which case only the other bound is used.
+func psmPrice(tin, tout sdk.Dec) (sdk.Dec, error) {
+	if tin.IsNegative() || tout.IsNegative() {
+		return sdk.Dec{}, fmt.Errorf("negative psm fee")
+	}
+
+	mint := tin.LT(sdk.OneDec())
+	redeem := tout.LT(sdk.OneDec())
+
+	switch {
+	case mint && redeem:
+		high := sdk.OneDec().Quo(sdk.OneDec().Sub(tin))
+		low := sdk.OneDec().Quo(sdk.OneDec().Add(tout))
+		return high.Add(low).QuoInt64(2), nil
+	case mint:
+		return sdk.OneDec().Quo(sdk.OneDec().Sub(tin)), nil
+	case redeem:
+		return sdk.OneDec().Quo(sdk.OneDec().Add(tout)), nil
+	}
+
+	return sdk.Dec{}, fmt.Errorf("psm halted")
+}
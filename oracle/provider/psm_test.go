@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPsmPrice(t *testing.T) {
+	// no fees
+	price, err := psmPrice(sdk.ZeroDec(), sdk.ZeroDec())
+	require.NoError(t, err)
+	require.Equal(t, "1.000000000000000000", price.String())
+
+	// 1% redeem fee: the price floor is 1/1.01
+	price, err = psmPrice(sdk.ZeroDec(), sdk.MustNewDecFromStr("0.01"))
+	require.NoError(t, err)
+	require.Equal(t, "0.995049504950495049", price.String())
+
+	// halted redeems only use the mint bound
+	price, err = psmPrice(sdk.MustNewDecFromStr("0.2"), sdk.NewDec(1000))
+	require.NoError(t, err)
+	require.Equal(t, "1.250000000000000000", price.String())
+
+	_, err = psmPrice(sdk.NewDec(1000), sdk.NewDec(1000))
+	require.Error(t, err)
+}
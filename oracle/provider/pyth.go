@@ -41,6 +41,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderPyth, pythDefaultEndpoints, NewPythProvider)
+}
+
 func NewPythProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
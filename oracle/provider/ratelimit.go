@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// rateLimitSlowdown defines the share of the remaining request budget,
+	// below which the poll interval is doubled.
+	rateLimitSlowdown = 0.25
+	// rateLimitPause defines the share of the remaining request budget,
+	// below which polling pauses until the budget is reset.
+	rateLimitPause = 0.05
+
+	// binanceWeightLimit defines the request weight per minute and ip of the
+	// binance REST api.
+	binanceWeightLimit = 6000
+)
+
+type (
+	// rateLimiter tracks the request budget announced by the rate limit
+	// headers of a provider and slows down polling, before the provider
+	// bans the ip.
+	rateLimiter struct {
+		mtx    sync.Mutex
+		factor int
+		until  time.Time
+	}
+
+	// rateLimitedProvider defines a polling provider, whose poll interval
+	// is adjusted to the remaining request budget.
+	rateLimitedProvider interface {
+		pollDelay(interval time.Duration, now time.Time) time.Duration
+	}
+)
+
+// observe updates the request budget with the headers of a response.
+// Supported are Retry-After, X-RateLimit-Remaining with X-RateLimit-Limit
+// and X-RateLimit-Reset, and the used weight of binance.
+func (r *rateLimiter) observe(
+	logger zerolog.Logger,
+	header http.Header,
+	status int,
+	now time.Time,
+) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if status == http.StatusTooManyRequests || status == http.StatusTeapot {
+		wait := time.Minute
+		if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		r.pause(logger, now.Add(wait))
+		return
+	}
+
+	remaining, limit, reset, ok := parseRateLimit(header, now)
+	if !ok {
+		return
+	}
+
+	share := float64(remaining) / float64(limit)
+	switch {
+	case share < rateLimitPause:
+		r.pause(logger, reset)
+	case share < rateLimitSlowdown:
+		if r.factor < 2 {
+			logger.Warn().
+				Int64("remaining", remaining).
+				Int64("limit", limit).
+				Msg("request budget nearly exhausted, slowing down polling")
+		}
+		r.factor = 2
+	default:
+		r.factor = 1
+	}
+}
+
+// pause stops polling until the given time.
+func (r *rateLimiter) pause(logger zerolog.Logger, until time.Time) {
+	if until.After(r.until) {
+		logger.Warn().
+			Time("until", until).
+			Msg("request budget exhausted, pausing polling")
+		r.until = until
+	}
+	r.factor = 2
+}
+
+// pollDelay returns the time until the next poll, which is the poll
+// interval, slowed down according to the remaining request budget.
+func (r *rateLimiter) pollDelay(interval time.Duration, now time.Time) time.Duration {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	delay := interval
+	if r.factor > 1 {
+		delay = interval * time.Duration(r.factor)
+	}
+	if wait := r.until.Sub(now); wait > delay {
+		delay = wait
+	}
+
+	return delay
+}
+
+// pollDelay implements rateLimitedProvider for all providers.
+func (p *provider) pollDelay(interval time.Duration, now time.Time) time.Duration {
+	return p.rateLimit.pollDelay(interval, now)
+}
+
+// parseRateLimit returns the remaining requests, the limit and the time the
+// budget is reset from the response headers.
+func parseRateLimit(header http.Header, now time.Time) (int64, int64, time.Time, bool) {
+	// binance reports the used weight of the current minute
+	if used, err := strconv.ParseInt(header.Get("X-Mbx-Used-Weight-1m"), 10, 64); err == nil {
+		reset := now.Truncate(time.Minute).Add(time.Minute)
+		return binanceWeightLimit - used, binanceWeightLimit, reset, true
+	}
+
+	remaining, err := strconv.ParseInt(header.Get("X-Ratelimit-Remaining"), 10, 64)
+	if err != nil {
+		return 0, 0, time.Time{}, false
+	}
+	limit, err := strconv.ParseInt(header.Get("X-Ratelimit-Limit"), 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, 0, time.Time{}, false
+	}
+
+	reset := now.Add(time.Minute)
+	if value, err := strconv.ParseInt(header.Get("X-Ratelimit-Reset"), 10, 64); err == nil && value > 0 {
+		switch {
+		// unix timestamp in milliseconds
+		case value > 1e12:
+			reset = time.UnixMilli(value)
+		// unix timestamp in seconds
+		case value > 1e9:
+			reset = time.Unix(value, 0)
+		// seconds until the reset
+		default:
+			reset = now.Add(time.Duration(value) * time.Second)
+		}
+	}
+
+	return remaining, limit, reset, true
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+
+	header := http.Header{}
+	header.Set("X-MBX-USED-WEIGHT-1M", "5800")
+	remaining, limit, reset, ok := parseRateLimit(header, now)
+	require.True(t, ok)
+	require.Equal(t, int64(200), remaining)
+	require.Equal(t, int64(binanceWeightLimit), limit)
+	require.Equal(t, time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC), reset)
+
+	header = http.Header{}
+	header.Set("X-RateLimit-Remaining", "10")
+	header.Set("X-RateLimit-Limit", "100")
+	header.Set("X-RateLimit-Reset", "5")
+	remaining, limit, reset, ok = parseRateLimit(header, now)
+	require.True(t, ok)
+	require.Equal(t, int64(10), remaining)
+	require.Equal(t, int64(100), limit)
+	require.Equal(t, now.Add(5*time.Second), reset)
+
+	header.Set("X-RateLimit-Reset", "1704110460")
+	_, _, reset, _ = parseRateLimit(header, now)
+	require.Equal(t, time.Unix(1704110460, 0), reset)
+
+	header.Set("X-RateLimit-Reset", "1704110460000")
+	_, _, reset, _ = parseRateLimit(header, now)
+	require.Equal(t, time.UnixMilli(1704110460000), reset)
+
+	_, _, _, ok = parseRateLimit(http.Header{}, now)
+	require.False(t, ok)
+}
+
+func TestRateLimiter(t *testing.T) {
+	logger := zerolog.Nop()
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	interval := 6 * time.Second
+
+	budget := func(remaining string) http.Header {
+		header := http.Header{}
+		header.Set("X-RateLimit-Remaining", remaining)
+		header.Set("X-RateLimit-Limit", "100")
+		header.Set("X-RateLimit-Reset", "20")
+		return header
+	}
+
+	var r rateLimiter
+	require.Equal(t, interval, r.pollDelay(interval, now))
+
+	// enough budget
+	r.observe(logger, budget("80"), http.StatusOK, now)
+	require.Equal(t, interval, r.pollDelay(interval, now))
+
+	// nearly exhausted budget slows down polling
+	r.observe(logger, budget("20"), http.StatusOK, now)
+	require.Equal(t, 2*interval, r.pollDelay(interval, now))
+
+	// exhausted budget pauses until the reset
+	r.observe(logger, budget("2"), http.StatusOK, now)
+	require.Equal(t, 20*time.Second, r.pollDelay(interval, now))
+	require.Equal(t, 2*interval, r.pollDelay(interval, now.Add(20*time.Second)))
+
+	// recovered budget
+	r.observe(logger, budget("100"), http.StatusOK, now.Add(20*time.Second))
+	require.Equal(t, interval, r.pollDelay(interval, now.Add(20*time.Second)))
+
+	// rate limited responses pause for the retry after duration
+	header := http.Header{}
+	header.Set("Retry-After", "120")
+	r.observe(logger, header, http.StatusTooManyRequests, now)
+	require.Equal(t, 2*time.Minute, r.pollDelay(interval, now))
+}
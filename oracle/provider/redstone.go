@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog"
+)
+
+const (
+	redstoneDataServiceId = "redstone-primary-prod"
+	redstoneMinSigners    = 3
+	redstoneMaxAge        = 3 * time.Minute
+	redstoneDecimals      = 8
+)
+
+var (
+	_                        Provider = (*RedstoneProvider)(nil)
+	redstoneDefaultEndpoints          = Endpoint{
+		Name: ProviderRedstone,
+		Urls: []string{
+			"https://oracle-gateway-1.a.redstone.finance",
+			"https://oracle-gateway-2.a.redstone.finance",
+		},
+		PollInterval: 10 * time.Second,
+		// Authorized signers of the redstone-primary-prod data service
+		Signers: []string{
+			"0x8BB8F32Df04c8b654987DAaeD53D6B6091e3B774",
+			"0xdEB22f54738d54976C4c0fe5ce6d408E40d88499",
+			"0x51Ce04Be4b3E32572C4Ec9135221d0691Ba7d202",
+			"0xDD682daEC5A90dD295d14DA4b0bec9281017b5bE",
+			"0x9c5AE89C4Af6aA32cE58588DBaF90d18a855B6de",
+		},
+	}
+)
+
+type (
+	// RedstoneProvider defines an oracle provider that consumes signed
+	// price packages of the RedStone oracle gateways. Only packages signed
+	// by one of the configured signers are accepted.
+	//
+	// REF: https://docs.redstone.finance/docs/smart-contract-devs/how-it-works
+	RedstoneProvider struct {
+		provider
+		signers map[string]struct{}
+	}
+
+	RedstonePackage struct {
+		Timestamp     int64               `json:"timestampMilliseconds"`
+		Signature     string              `json:"signature"`
+		DataPoints    []RedstoneDataPoint `json:"dataPoints"`
+		DataServiceId string              `json:"dataServiceId"`
+		SignerAddress string              `json:"signerAddress"`
+	}
+
+	RedstoneDataPoint struct {
+		DataFeedId string  `json:"dataFeedId"` // Feed id ex.: BTC
+		Value      float64 `json:"value"`      // Price in USD ex.: 26123.45
+	}
+)
+
+func init() {
+	register(ProviderRedstone, redstoneDefaultEndpoints, NewRedstoneProvider)
+}
+
+func NewRedstoneProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*RedstoneProvider, error) {
+	provider := &RedstoneProvider{}
+	provider.Init(
+		ctx,
+		endpoints,
+		logger,
+		pairs,
+		nil,
+		nil,
+	)
+
+	provider.signers = map[string]struct{}{}
+	for _, signer := range provider.endpoints.Signers {
+		provider.signers[strings.ToLower(signer)] = struct{}{}
+	}
+
+	if len(provider.signers) < redstoneMinSigners {
+		return nil, fmt.Errorf(
+			"redstone requires at least %d signers", redstoneMinSigners,
+		)
+	}
+
+	availablePairs, _ := provider.GetAvailablePairs()
+	provider.setPairs(pairs, availablePairs, nil)
+
+	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	return provider, nil
+}
+
+func (p *RedstoneProvider) getPackages() (map[string][]RedstonePackage, error) {
+	path := "/data-packages/latest/" + redstoneDataServiceId
+	content, err := p.httpGet(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var packages map[string][]RedstonePackage
+	err = json.Unmarshal(content, &packages)
+	if err != nil {
+		return nil, err
+	}
+
+	return packages, nil
+}
+
+func (p *RedstoneProvider) Poll() error {
+	packages, err := p.getPackages()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	rates := map[string]sdk.Dec{"USD": sdk.OneDec()}
+	for _, pair := range p.getAllPairs() {
+		for _, denom := range []string{pair.Base, pair.Quote} {
+			_, found := rates[denom]
+			if found {
+				continue
+			}
+
+			feedId := p.getFeedId(denom)
+			price, err := p.verifiedPrice(feedId, packages[feedId], now)
+			if err != nil {
+				p.logger.Warn().
+					Err(err).
+					Str("feed", feedId).
+					Msg("no verified price")
+				continue
+			}
+
+			rates[denom] = price
+		}
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for symbol, pair := range p.pairs {
+		base, found := rates[pair.Base]
+		if !found {
+			continue
+		}
+
+		quote, found := rates[pair.Quote]
+		if !found || quote.IsZero() {
+			continue
+		}
+
+		p.setTickerPrice(
+			symbol,
+			base.Quo(quote),
+			sdk.NewDec(1),
+			now,
+		)
+	}
+
+	p.logger.Debug().Msg("updated tickers")
+	return nil
+}
+
+// verifiedPrice returns the median of all fresh values for the given feed,
+// that are signed by distinct authorized signers.
+func (p *RedstoneProvider) verifiedPrice(
+	feedId string,
+	packages []RedstonePackage,
+	now time.Time,
+) (sdk.Dec, error) {
+	values := map[string]sdk.Dec{}
+	for _, pkg := range packages {
+		timestamp := time.UnixMilli(pkg.Timestamp)
+		if now.Sub(timestamp) > redstoneMaxAge {
+			continue
+		}
+
+		signer, err := recoverRedstoneSigner(pkg)
+		if err != nil {
+			p.logger.Debug().
+				Err(err).
+				Str("feed", feedId).
+				Msg("failed to recover signer")
+			continue
+		}
+
+		_, found := p.signers[signer]
+		if !found {
+			p.logger.Debug().
+				Str("feed", feedId).
+				Str("signer", signer).
+				Msg("unknown signer")
+			continue
+		}
+
+		for _, dataPoint := range pkg.DataPoints {
+			if dataPoint.DataFeedId != feedId {
+				continue
+			}
+			values[signer] = floatToDec(dataPoint.Value)
+		}
+	}
+
+	if len(values) < redstoneMinSigners {
+		return sdk.Dec{}, fmt.Errorf(
+			"only %d of %d required signers", len(values), redstoneMinSigners,
+		)
+	}
+
+	prices := []sdk.Dec{}
+	for _, value := range values {
+		prices = append(prices, value)
+	}
+
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].LT(prices[j])
+	})
+
+	middle := len(prices) / 2
+	if len(prices)%2 == 0 {
+		return prices[middle-1].Add(prices[middle]).QuoInt64(2), nil
+	}
+
+	return prices[middle], nil
+}
+
+func (p *RedstoneProvider) getFeedId(denom string) string {
+	feedId, found := p.contracts.Contract(denom)
+	if found {
+		return feedId
+	}
+	return denom
+}
+
+func (p *RedstoneProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	// Feeds are only known after fetching the latest packages, unknown
+	// feeds are simply missing in the response.
+	return nil, nil
+}
+
+// recoverRedstoneSigner returns the lower case address of the signer of
+// the given package.
+func recoverRedstoneSigner(pkg RedstonePackage) (string, error) {
+	signature, err := base64.StdEncoding.DecodeString(pkg.Signature)
+	if err != nil {
+		return "", err
+	}
+
+	if len(signature) != 65 {
+		return "", fmt.Errorf("invalid signature length")
+	}
+
+	// go-ethereum expects the recovery id to be 0 or 1
+	if signature[64] >= 27 {
+		signature[64] -= 27
+	}
+
+	message, err := serializeRedstonePackage(pkg)
+	if err != nil {
+		return "", err
+	}
+
+	pubkey, err := crypto.SigToPub(crypto.Keccak256(message), signature)
+	if err != nil {
+		return "", err
+	}
+
+	address := crypto.PubkeyToAddress(*pubkey).Hex()
+
+	return strings.ToLower(address), nil
+}
+
+// serializeRedstonePackage returns the signable bytes of a data package:
+// sorted data points (32 bytes feed id, 32 bytes value), 6 bytes timestamp,
+// 4 bytes data point value size and 3 bytes data point count.
+func serializeRedstonePackage(pkg RedstonePackage) ([]byte, error) {
+	dataPoints := make([]RedstoneDataPoint, len(pkg.DataPoints))
+	copy(dataPoints, pkg.DataPoints)
+
+	sort.Slice(dataPoints, func(i, j int) bool {
+		return dataPoints[i].DataFeedId < dataPoints[j].DataFeedId
+	})
+
+	factor := uintToDec(10).Power(redstoneDecimals)
+
+	message := []byte{}
+	for _, dataPoint := range dataPoints {
+		if len(dataPoint.DataFeedId) > 32 {
+			return nil, fmt.Errorf("feed id too long")
+		}
+
+		feedId := make([]byte, 32)
+		copy(feedId, dataPoint.DataFeedId)
+
+		value := floatToDec(dataPoint.Value).Mul(factor).RoundInt().BigInt()
+		if value.Sign() < 0 || value.BitLen() > 256 {
+			return nil, fmt.Errorf("invalid value")
+		}
+
+		message = append(message, feedId...)
+		message = append(message, value.FillBytes(make([]byte, 32))...)
+	}
+
+	buffer := make([]byte, 8)
+
+	binary.BigEndian.PutUint64(buffer, uint64(pkg.Timestamp))
+	message = append(message, buffer[2:]...)
+
+	binary.BigEndian.PutUint64(buffer, 32)
+	message = append(message, buffer[4:]...)
+
+	binary.BigEndian.PutUint64(buffer, uint64(len(dataPoints)))
+	message = append(message, buffer[5:]...)
+
+	return message, nil
+}
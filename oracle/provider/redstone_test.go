@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedstoneSigner(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+	return key, address
+}
+
+func signRedstonePackage(
+	t *testing.T,
+	key *ecdsa.PrivateKey,
+	timestamp time.Time,
+	value float64,
+) RedstonePackage {
+	pkg := RedstonePackage{
+		Timestamp:  timestamp.UnixMilli(),
+		DataPoints: []RedstoneDataPoint{{DataFeedId: "BTC", Value: value}},
+	}
+
+	message, err := serializeRedstonePackage(pkg)
+	require.NoError(t, err)
+
+	signature, err := crypto.Sign(crypto.Keccak256(message), key)
+	require.NoError(t, err)
+
+	// redstone signatures use the ethereum recovery ids 27 and 28
+	signature[64] += 27
+	pkg.Signature = base64.StdEncoding.EncodeToString(signature)
+
+	return pkg
+}
+
+func TestRecoverRedstoneSigner(t *testing.T) {
+	key, address := newRedstoneSigner(t)
+	pkg := signRedstonePackage(t, key, time.Now(), 26123.45)
+
+	signer, err := recoverRedstoneSigner(pkg)
+	require.NoError(t, err)
+	require.Equal(t, address, signer)
+
+	// a tampered value recovers a different signer
+	pkg.DataPoints[0].Value = 30000
+	signer, err = recoverRedstoneSigner(pkg)
+	if err == nil {
+		require.NotEqual(t, address, signer)
+	}
+
+	pkg.Signature = base64.StdEncoding.EncodeToString([]byte("short"))
+	_, err = recoverRedstoneSigner(pkg)
+	require.Error(t, err)
+}
+
+func TestRedstoneVerifiedPrice(t *testing.T) {
+	now := time.Now()
+
+	p := RedstoneProvider{
+		provider: provider{logger: zerolog.Nop()},
+		signers:  map[string]struct{}{},
+	}
+
+	keys := []*ecdsa.PrivateKey{}
+	for i := 0; i < redstoneMinSigners; i++ {
+		key, address := newRedstoneSigner(t)
+		keys = append(keys, key)
+		p.signers[address] = struct{}{}
+	}
+
+	packages := []RedstonePackage{
+		signRedstonePackage(t, keys[0], now, 100),
+		signRedstonePackage(t, keys[1], now, 101),
+		signRedstonePackage(t, keys[2], now, 105),
+	}
+
+	price, err := p.verifiedPrice("BTC", packages, now)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewDec(101), price)
+
+	// a tampered package doesn't count
+	tampered := append([]RedstonePackage{}, packages...)
+	tampered[2].DataPoints = []RedstoneDataPoint{{DataFeedId: "BTC", Value: 1}}
+	_, err = p.verifiedPrice("BTC", tampered, now)
+	require.EqualError(t, err, "only 2 of 3 required signers")
+
+	// neither does a package of an unknown signer
+	unknown, _ := newRedstoneSigner(t)
+	_, err = p.verifiedPrice("BTC", append(
+		packages[:2:2], signRedstonePackage(t, unknown, now, 102),
+	), now)
+	require.EqualError(t, err, "only 2 of 3 required signers")
+
+	// nor a package signed twice by the same signer
+	_, err = p.verifiedPrice("BTC", append(
+		packages[:2:2], signRedstonePackage(t, keys[0], now, 102),
+	), now)
+	require.EqualError(t, err, "only 2 of 3 required signers")
+
+	// or stale packages
+	_, err = p.verifiedPrice("BTC", packages, now.Add(redstoneMaxAge+time.Second))
+	require.EqualError(t, err, "only 0 of 3 required signers")
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"price-feeder/oracle/types"
+
+	"github.com/rs/zerolog"
+)
+
+type (
+	// Factory creates a provider of the registered name. The db is only
+	// used by providers persisting their volumes.
+	Factory func(
+		db *sql.DB,
+		ctx context.Context,
+		logger zerolog.Logger,
+		endpoints Endpoint,
+		pairs ...types.CurrencyPair,
+	) (Provider, error)
+
+	// Registration defines a provider implementation and its default
+	// endpoints. PersistsVolumes is set for providers storing their own
+	// volume history in the db, ComparisonOnly for sources, e.g. other
+	// oracle networks, that must never be used for voting.
+	Registration struct {
+		Name            Name
+		Factory         Factory
+		Defaults        Endpoint
+		PersistsVolumes bool
+		ComparisonOnly  bool
+	}
+)
+
+// registry holds all implemented providers by name. Providers register
+// themselves in the init function of their file, so the config validation,
+// the defaults and the constructors can't get out of sync.
+var registry = map[Name]Registration{}
+
+// Register adds a provider implementation to the registry. It panics if the
+// name is registered already.
+func Register(name Name, defaults Endpoint, factory Factory) {
+	if _, found := registry[name]; found {
+		panic(fmt.Sprintf("provider %s registered twice", name))
+	}
+	registry[name] = Registration{
+		Name:     name,
+		Factory:  factory,
+		Defaults: defaults,
+	}
+}
+
+// register adds a provider, which doesn't use a database, to the registry.
+func register[P Provider](
+	name Name,
+	defaults Endpoint,
+	constructor func(context.Context, zerolog.Logger, Endpoint, ...types.CurrencyPair) (P, error),
+) {
+	Register(name, defaults, func(
+		_ *sql.DB,
+		ctx context.Context,
+		logger zerolog.Logger,
+		endpoints Endpoint,
+		pairs ...types.CurrencyPair,
+	) (Provider, error) {
+		p, err := constructor(ctx, logger, endpoints, pairs...)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	})
+}
+
+// registerWithDB adds a provider, which uses a database, to the registry.
+func registerWithDB[P Provider](
+	name Name,
+	defaults Endpoint,
+	constructor func(*sql.DB, context.Context, zerolog.Logger, Endpoint, ...types.CurrencyPair) (P, error),
+) {
+	Register(name, defaults, func(
+		db *sql.DB,
+		ctx context.Context,
+		logger zerolog.Logger,
+		endpoints Endpoint,
+		pairs ...types.CurrencyPair,
+	) (Provider, error) {
+		p, err := constructor(db, ctx, logger, endpoints, pairs...)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	})
+
+	registration := registry[name]
+	registration.PersistsVolumes = true
+	registry[name] = registration
+}
+
+// registerComparisonOnly adds a comparison source, which can't be used for
+// voting, to the registry.
+func registerComparisonOnly[P Provider](
+	name Name,
+	defaults Endpoint,
+	constructor func(context.Context, zerolog.Logger, Endpoint, ...types.CurrencyPair) (P, error),
+) {
+	register(name, defaults, constructor)
+
+	registration := registry[name]
+	registration.ComparisonOnly = true
+	registry[name] = registration
+}
+
+// IsRegistered returns true if a provider with the given name is
+// implemented.
+func IsRegistered(name Name) bool {
+	_, found := registry[name]
+	return found
+}
+
+// PersistsVolumes returns true if the provider stores its own volume
+// history, like the DEX providers counting the volume per block.
+func PersistsVolumes(name Name) bool {
+	return registry[name].PersistsVolumes
+}
+
+// IsComparisonOnly returns true if the provider may only be used as
+// comparison source and never for voting.
+func IsComparisonOnly(name Name) bool {
+	return registry[name].ComparisonOnly
+}
+
+// Registrations returns all registered providers sorted by name.
+func Registrations() []Registration {
+	registrations := make([]Registration, 0, len(registry))
+	for _, registration := range registry {
+		registrations = append(registrations, registration)
+	}
+	sort.Slice(registrations, func(i, j int) bool {
+		return registrations[i].Name < registrations[j].Name
+	})
+	return registrations
+}
+
+// New creates a provider by name with the given endpoints.
+func New(
+	db *sql.DB,
+	ctx context.Context,
+	name Name,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (Provider, error) {
+	registration, found := registry[name]
+	if !found {
+		return nil, fmt.Errorf("provider %s not found", name)
+	}
+	endpoints.Name = name
+	return registration.Factory(db, ctx, logger, endpoints, pairs...)
+}
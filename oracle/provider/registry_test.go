@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	registrations := Registrations()
+	require.NotEmpty(t, registrations)
+
+	for i, registration := range registrations {
+		if i > 0 {
+			require.Less(t, registrations[i-1].Name, registration.Name)
+		}
+		require.True(t, IsRegistered(registration.Name))
+		require.NotNil(t, registration.Factory, registration.Name)
+		require.Equal(t, registration.Name, registration.Defaults.Name)
+	}
+
+	require.True(t, IsRegistered(ProviderBitstamp))
+	require.True(t, IsRegistered(ProviderBitfinex))
+	require.False(t, IsRegistered(Name("foo")))
+
+	require.True(t, PersistsVolumes(ProviderFinV2))
+	require.False(t, PersistsVolumes(ProviderBinance))
+
+	require.True(t, IsComparisonOnly(ProviderOjo))
+	require.False(t, IsComparisonOnly(ProviderBinance))
+
+	_, err := New(nil, context.Background(), Name("foo"), zerolog.Nop(), Endpoint{})
+	require.EqualError(t, err, "provider foo not found")
+
+	require.Panics(t, func() {
+		Register(ProviderMock, mockDefaultEndpoints, nil)
+	})
+}
@@ -27,6 +27,7 @@ var (
 	shadeDefaultEndpoints = Endpoint{
 		Name:         ProviderShade,
 		Urls:         []string{},
+		ChainId:      "secret-4",
 		PollInterval: 6 * time.Second,
 	}
 )
@@ -59,7 +60,9 @@ type (
 	}
 
 	ShadePairInfo struct {
-		Pair [2]ShadePair `json:"pair"`
+		Pair    [2]ShadePair `json:"pair"`
+		Amount0 string       `json:"amount_0"`
+		Amount1 string       `json:"amount_1"`
 	}
 
 	ShadePair struct {
@@ -72,6 +75,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderShade, shadeDefaultEndpoints, NewShadeProvider)
+}
+
 func NewShadeProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -88,8 +95,6 @@ func NewShadeProvider(
 		nil,
 	)
 
-	provider.contracts = provider.endpoints.ContractAddresses
-
 	availablePairs, _ := provider.GetAvailablePairs()
 	provider.setPairs(pairs, availablePairs, nil)
 
@@ -166,9 +171,12 @@ func (p *ShadeProvider) Poll() error {
 
 		price := strToDec(response.Simulation.Price)
 
+		// the pool tokens are in the order of the provider symbol
+		token0, token1 := base, quote
 		_, found = p.pairs[pair.String()]
 		if !found {
 			price = uintToDec(1).Quo(price)
+			token0, token1 = quote, base
 		}
 
 		factor, err := computeDecimalsFactor(base.Decimals, quote.Decimals)
@@ -178,6 +186,12 @@ func (p *ShadeProvider) Poll() error {
 
 		price = price.Mul(factor)
 
+		liquidity, err := p.getLiquidity(contract, hash, token0, token1, price)
+		if err != nil {
+			p.logger.Warn().Err(err).Str("symbol", symbol).Msg("failed to get liquidity")
+		}
+		p.setLiquidity(symbol, liquidity)
+
 		p.setTickerPrice(
 			symbol,
 			price,
@@ -412,6 +426,38 @@ func (p *ShadeProvider) getTokenInfo(contract, hash string) (ShadeToken, error)
 	return token, nil
 }
 
+// getLiquidity returns the value of both pool amounts in terms of token1.
+func (p *ShadeProvider) getLiquidity(
+	contract string,
+	hash string,
+	token0, token1 ShadeToken,
+	price sdk.Dec,
+) (sdk.Dec, error) {
+	content, err := p.query(contract, hash, `{"get_pair_info":{}}`)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	var response ShadePairInfoResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	amount0 := strToDec(response.PairInfo.Amount0)
+	amount1 := strToDec(response.PairInfo.Amount1)
+	if amount0.IsNil() || amount1.IsNil() {
+		return sdk.Dec{}, fmt.Errorf("invalid pool amounts")
+	}
+
+	ten := uintToDec(10)
+	return poolLiquidity(
+		amount0.Quo(ten.Power(uint64(token0.Decimals))),
+		amount1.Quo(ten.Power(uint64(token1.Decimals))),
+		price,
+	), nil
+}
+
 func (p *ShadeProvider) getPairInfo(
 	contract string,
 	hash string,
@@ -1,8 +1,14 @@
 package provider
 
 import (
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/armon/go-metrics"
 	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"price-feeder/oracle/labels"
 )
 
 const (
@@ -11,21 +17,45 @@ const (
 	MessageTypeTrade  = MessageType("trade")
 )
 
+// FinalPriceProvider is the provider name of the aggregated prices, which
+// are emitted even if pair metrics are disabled.
+const FinalPriceProvider = Name("_final")
+
 type (
 	MessageType string
+
+	priceGauge struct {
+		name   []string
+		labels []metrics.Label
+		value  float32
+	}
+
+	// priceMetrics buffers the price gauges, which are updated for every
+	// ticker, and emits them in one batch per tick, at most once per
+	// interval.
+	priceMetrics struct {
+		mtx      sync.Mutex
+		interval time.Duration
+		perPair  bool
+		last     time.Time
+		gauges   map[string]priceGauge
+	}
 )
 
+var providerPriceMetrics = &priceMetrics{
+	perPair: true,
+	gauges:  map[string]priceGauge{},
+}
+
 // String cast provider MessageType to string.
 func (mt MessageType) String() string {
 	return string(mt)
 }
 
-// providerLabel returns a label based on the provider name.
+// providerLabel returns a label based on the provider name, restricted by
+// the configured label allow-list.
 func providerLabel(n Name) metrics.Label {
-	return metrics.Label{
-		Name:  "provider",
-		Value: n.String(),
-	}
+	return labels.Provider(n.String())
 }
 
 // messageTypeLabel returns a label based on the message type.
@@ -83,6 +113,38 @@ func telemetryWebsocketMessage(n Name, mt MessageType) {
 	)
 }
 
+// telemetryStaleEvent gives an standard way to add
+// `price_feeder_provider_stale_event{provider="x", pair="x"}` metric.
+func telemetryStaleEvent(n Name, pair string) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"stale_event",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+			labels.Pair("pair", pair),
+		},
+	)
+}
+
+// telemetryExtremeFunding gives an standard way to add
+// `price_feeder_provider_extreme_funding{provider="x", pair="x"}` metric.
+func telemetryExtremeFunding(n Name, pair string) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"extreme_funding",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+			labels.Pair("pair", pair),
+		},
+	)
+}
+
 // TelemetryFailure gives an standard way to add
 // `price_feeder_failure_provider{type="x", provider="x"}` metric.
 func TelemetryFailure(n Name, mt MessageType) {
@@ -98,20 +160,86 @@ func TelemetryFailure(n Name, mt MessageType) {
 	)
 }
 
+// TelemetryProviderPrice buffers the
+// `price_feeder_provider_price{provider="x", denom="x"}` and
+// `price_feeder_provider_volume{provider="x", denom="x"}` metrics until the
+// next FlushPriceMetrics.
 func TelemetryProviderPrice(name Name, denom string, price float32, volume float32) {
-	labels := []metrics.Label{
-		providerLabel(name),
-		telemetry.NewLabel("denom", denom),
+	providerPriceMetrics.set(name, denom, "price", price)
+	providerPriceMetrics.set(name, denom, "volume", volume)
+}
+
+// TelemetryProviderLiquidity buffers the
+// `price_feeder_provider_liquidity{provider="x", denom="x"}` metric until the
+// next FlushPriceMetrics.
+func TelemetryProviderLiquidity(name Name, denom string, liquidity float32) {
+	providerPriceMetrics.set(name, denom, "liquidity", liquidity)
+}
+
+// ConfigurePriceMetrics sets the min interval between two emissions of the
+// buffered price metrics. Without perPair only the aggregated prices are
+// emitted.
+func ConfigurePriceMetrics(interval time.Duration, perPair bool) {
+	providerPriceMetrics.mtx.Lock()
+	defer providerPriceMetrics.mtx.Unlock()
+
+	providerPriceMetrics.interval = interval
+	providerPriceMetrics.perPair = perPair
+}
+
+// FlushPriceMetrics emits the buffered price metrics, if the configured
+// interval passed since the last emission. It is called once per tick.
+func FlushPriceMetrics(now time.Time) {
+	providerPriceMetrics.flush(now)
+}
+
+func (m *priceMetrics) set(name Name, denom, metric string, value float32) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if !m.perPair && name != FinalPriceProvider {
+		return
+	}
+	if !labels.Allowed(name.String(), denom) {
+		return
+	}
+
+	key := strings.Join([]string{metric, name.String(), denom}, "/")
+	m.gauges[key] = priceGauge{
+		name: []string{"provider", metric},
+		labels: []metrics.Label{
+			providerLabel(name),
+			telemetry.NewLabel("denom", denom),
+		},
+		value: value,
+	}
+}
+
+func (m *priceMetrics) flush(now time.Time) []priceGauge {
+	m.mtx.Lock()
+	if now.Sub(m.last) < m.interval {
+		m.mtx.Unlock()
+		return nil
+	}
+	gauges := make([]priceGauge, 0, len(m.gauges))
+	for _, gauge := range m.gauges {
+		gauges = append(gauges, gauge)
+	}
+	m.gauges = map[string]priceGauge{}
+	m.last = now
+	m.mtx.Unlock()
+
+	for _, gauge := range gauges {
+		telemetry.SetGaugeWithLabels(gauge.name, gauge.value, gauge.labels)
 	}
 
-	telemetry.SetGaugeWithLabels([]string{"provider", "price"}, price, labels)
-	telemetry.SetGaugeWithLabels([]string{"provider", "volume"}, volume, labels)
+	return gauges
 }
 
 func TelemetryEvmMethod(chain, provider, method string) {
-	labels := []metrics.Label{
+	evmLabels := []metrics.Label{
 		telemetry.NewLabel("chain", chain),
-		telemetry.NewLabel("provider", provider),
+		labels.Provider(provider),
 		telemetry.NewLabel("method", method),
 	}
 
@@ -120,6 +248,6 @@ func TelemetryEvmMethod(chain, provider, method string) {
 			"evm_calls",
 		},
 		float32(1),
-		labels,
+		evmLabels,
 	)
 }
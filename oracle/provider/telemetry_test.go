@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceMetricsFlush(t *testing.T) {
+	m := &priceMetrics{
+		interval: time.Minute,
+		perPair:  true,
+		gauges:   map[string]priceGauge{},
+	}
+
+	now := time.Now()
+
+	// only the latest value of each gauge is emitted
+	m.set(ProviderBinance, "ATOMUSDT", "price", 1)
+	m.set(ProviderBinance, "ATOMUSDT", "price", 2)
+	m.set(ProviderBinance, "ATOMUSDT", "volume", 10)
+
+	gauges := m.flush(now)
+	require.Len(t, gauges, 2)
+	for _, gauge := range gauges {
+		if gauge.name[1] == "price" {
+			require.Equal(t, float32(2), gauge.value)
+		}
+	}
+
+	// throttled until the interval passed
+	m.set(ProviderBinance, "ATOMUSDT", "price", 3)
+	require.Empty(t, m.flush(now.Add(30*time.Second)))
+	require.Len(t, m.flush(now.Add(time.Minute)), 1)
+
+	// pair metrics disabled, aggregated prices are kept
+	m.perPair = false
+	m.set(ProviderBinance, "ATOMUSDT", "price", 1)
+	m.set(FinalPriceProvider, "ATOMUSD", "price", 1)
+	gauges = m.flush(now.Add(2 * time.Minute))
+	require.Len(t, gauges, 1)
+	require.Equal(t, FinalPriceProvider.String(), gauges[0].labels[0].Value)
+}
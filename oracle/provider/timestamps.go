@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/oracle/labels"
+)
+
+const (
+	// clockOffsetWeight defines the weight of a new sample in the moving
+	// average of the provider clock offset.
+	clockOffsetWeight = 0.2
+	// maxClockOffset defines the offset from which on a warning is logged.
+	maxClockOffset = 2 * time.Second
+	// defaultMaxEventAge defines the max age of exchange event timestamps,
+	// if the provider doesn't configure max_event_age.
+	defaultMaxEventAge = 30 * time.Second
+)
+
+// observeServerTime updates the estimated offset of the provider clock with
+// the server time of a response to a request sent and received at the given
+// local times. Like NTP, the server time is assumed to be taken halfway
+// through the round trip, which compensates the network latency. The caller
+// must hold p.mtx.
+func (p *provider) observeServerTime(server, sent, received time.Time) {
+	if server.IsZero() || received.Before(sent) {
+		return
+	}
+
+	midpoint := sent.Add(received.Sub(sent) / 2)
+	offset := server.Sub(midpoint)
+
+	if p.clockOffsetSamples == 0 {
+		p.clockOffset = offset
+	} else {
+		p.clockOffset = time.Duration(
+			clockOffsetWeight*float64(offset) +
+				(1-clockOffsetWeight)*float64(p.clockOffset),
+		)
+	}
+	p.clockOffsetSamples++
+
+	if p.clockOffset > maxClockOffset || p.clockOffset < -maxClockOffset {
+		p.logger.Warn().
+			Dur("offset", p.clockOffset).
+			Msg("provider clock offset exceeds threshold")
+	}
+
+	if !labels.AllowedProvider(p.endpoints.Name.String()) {
+		return
+	}
+
+	telemetry.SetGaugeWithLabels(
+		[]string{"provider", "clock_offset_ms"},
+		float32(p.clockOffset.Milliseconds()),
+		[]metrics.Label{providerLabel(p.endpoints.Name)},
+	)
+}
+
+// eventTime converts an exchange provided event timestamp into local time,
+// using the estimated clock offset of the provider. Missing timestamps fall
+// back to the current time and timestamps in the future are capped, so a
+// skewed exchange clock can't make tickers look fresher than they are. The
+// caller must hold p.mtx.
+func (p *provider) eventTime(timestamp time.Time) time.Time {
+	now := time.Now()
+
+	if timestamp.IsZero() || timestamp.Unix() <= 0 {
+		return now
+	}
+
+	local := timestamp.Add(-p.clockOffset)
+	if local.After(now) {
+		return now
+	}
+
+	return local
+}
+
+// setTickerPriceWithEventTime sets the ticker price like setTickerPrice and
+// records the exchange event timestamp of the pair, which is checked when
+// the ticker is read. Some exchanges keep pushing tickers with stale prices
+// during their own incidents, which look fresh by the time they are
+// received. The caller must hold p.mtx.
+func (p *provider) setTickerPriceWithEventTime(
+	symbol string,
+	price sdk.Dec,
+	volume sdk.Dec,
+	timestamp time.Time,
+) {
+	p.setTickerPrice(symbol, price, volume, p.eventTime(timestamp))
+
+	if timestamp.IsZero() || timestamp.Unix() <= 0 {
+		return
+	}
+
+	pair, found := p.inverse[symbol]
+	if !found {
+		pair, found = p.pairs[symbol]
+	}
+	if !found {
+		return
+	}
+
+	if p.eventTimes == nil {
+		p.eventTimes = map[string]time.Time{}
+	}
+	p.eventTimes[pair.String()] = timestamp.Add(-p.clockOffset)
+}
+
+// staleEvent returns the exchange event time of the pair and if it's older
+// than the max event age of the provider. Pairs without a recorded event
+// time are never stale. The caller must hold p.mtx.
+func (p *provider) staleEvent(pair string) (time.Time, bool) {
+	eventTime, found := p.eventTimes[pair]
+	if !found {
+		return time.Time{}, false
+	}
+
+	maxAge := p.endpoints.MaxEventAge
+	if maxAge <= 0 {
+		maxAge = defaultMaxEventAge
+	}
+
+	return eventTime, time.Since(eventTime) > maxAge
+}
+
+// IsStale returns true, if the provider has a ticker for the symbol, that
+// is dropped by GetTickerPrices for being stale.
+func (p *provider) IsStale(symbol string) bool {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	ticker, found := p.tickers[symbol]
+	if !found || ticker.Price.IsZero() {
+		return false
+	}
+	if time.Since(ticker.Time) > staleTickersCutoff {
+		return true
+	}
+	_, stale := p.staleEvent(symbol)
+	return stale
+}
+
+// ClockOffset returns the estimated offset of the provider clock to the
+// local clock.
+func (p *provider) ClockOffset() time.Duration {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.clockOffset
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/oracle/types"
+)
+
+func TestProviderClockOffset(t *testing.T) {
+	p := provider{logger: zerolog.Nop()}
+
+	now := time.Now()
+	sent := now.Add(-200 * time.Millisecond)
+	received := now
+
+	// the server time is taken halfway through the round trip, the server
+	// clock is 500ms ahead
+	server := sent.Add(100 * time.Millisecond).Add(500 * time.Millisecond)
+	p.observeServerTime(server, sent, received)
+	require.Equal(t, 500*time.Millisecond, p.clockOffset)
+
+	// following samples are averaged
+	p.observeServerTime(sent.Add(100*time.Millisecond), sent, received)
+	require.Equal(t, 400*time.Millisecond, p.clockOffset)
+
+	// invalid samples are ignored
+	p.observeServerTime(time.Time{}, sent, received)
+	p.observeServerTime(server, received, sent)
+	require.Equal(t, 400*time.Millisecond, p.clockOffset)
+}
+
+func TestProviderEventTime(t *testing.T) {
+	p := provider{clockOffset: time.Second}
+
+	// event times are converted into local time
+	event := time.Now().Add(-time.Second)
+	require.Equal(t, event.Add(-time.Second), p.eventTime(event))
+
+	// future timestamps are capped
+	require.False(t, p.eventTime(time.Now().Add(time.Hour)).After(time.Now()))
+
+	// missing timestamps fall back to the current time
+	require.WithinDuration(t, time.Now(), p.eventTime(time.UnixMilli(0)), time.Second)
+	require.WithinDuration(t, time.Now(), p.eventTime(time.Time{}), time.Second)
+}
+
+func TestProviderStaleEvent(t *testing.T) {
+	p := provider{
+		endpoints: Endpoint{Name: ProviderMock, MaxEventAge: 10 * time.Second},
+		logger:    zerolog.Nop(),
+		pairs: map[string]types.CurrencyPair{
+			"ATOMUSDT": testAtomUsdtCurrencyPair,
+		},
+		tickers:    map[string]types.TickerPrice{},
+		eventTimes: map[string]time.Time{},
+	}
+	pairs := []types.CurrencyPair{testAtomUsdtCurrencyPair}
+
+	// fresh events are returned
+	p.setTickerPriceWithEventTime(
+		"ATOMUSDT", sdk.NewDec(10), sdk.NewDec(100), time.Now().Add(-5*time.Second),
+	)
+	tickers, err := p.GetTickerPrices(pairs...)
+	require.NoError(t, err)
+	require.Contains(t, tickers, "ATOMUSDT")
+
+	// keepalive tickers with an old exchange timestamp are dropped
+	p.setTickerPriceWithEventTime(
+		"ATOMUSDT", sdk.NewDec(10), sdk.NewDec(100), time.Now().Add(-20*time.Second),
+	)
+	tickers, err = p.GetTickerPrices(pairs...)
+	require.NoError(t, err)
+	require.NotContains(t, tickers, "ATOMUSDT")
+
+	// tickers without exchange timestamp are only checked by receive time
+	p.setTickerPrice("ATOMUSDT", sdk.NewDec(10), sdk.NewDec(100), time.Now())
+	tickers, err = p.GetTickerPrices(pairs...)
+	require.NoError(t, err)
+	require.Contains(t, tickers, "ATOMUSDT")
+}
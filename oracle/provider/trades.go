@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// PriceSourceTicker prices the pairs with the tickers of the provider.
+	PriceSourceTicker = "ticker"
+	// PriceSourceTrades prices the pairs with the VWAP of the trades of the
+	// last tradeWindow, received from the websocket trade streams. Some
+	// exchanges report indicative ticker prices, which occasionally spike.
+	PriceSourceTrades = "trades"
+
+	// tradeWindow defines the rolling window of trades kept per pair.
+	tradeWindow = 5 * time.Minute
+)
+
+// trade defines a single trade of a websocket trade stream.
+type trade struct {
+	price  sdk.Dec
+	amount sdk.Dec
+	time   time.Time
+}
+
+// subscribeTrades switches the websocket of the provider to the trade
+// streams, if configured as price source of the endpoint. It returns true,
+// if the trade streams are used.
+func (p *provider) subscribeTrades(handler SubscribeHandler) bool {
+	if p.endpoints.PriceSource != PriceSourceTrades {
+		return false
+	}
+
+	if p.websocket == nil {
+		p.logger.Warn().Msg("trade price source requires a websocket, using tickers")
+		return false
+	}
+
+	p.websocket.SubscribeTrades(handler)
+	return true
+}
+
+// setTrade adds the trade to the rolling window of the symbol and sets the
+// ticker to the VWAP of the window. The window volume is extrapolated to
+// 24h, so it stays comparable to the ticker volumes of other providers. The
+// caller must hold p.mtx.
+func (p *provider) setTrade(
+	symbol string,
+	price sdk.Dec,
+	amount sdk.Dec,
+	timestamp time.Time,
+) {
+	if price.IsNil() || !price.IsPositive() || amount.IsNil() || !amount.IsPositive() {
+		p.logger.Debug().
+			Str("symbol", symbol).
+			Msg("invalid trade")
+		return
+	}
+
+	// trades are pruned relative to the latest trade, independent of the
+	// offset of the exchange clock
+	trades := []trade{}
+	for _, t := range p.trades[symbol] {
+		if timestamp.Sub(t.time) < tradeWindow {
+			trades = append(trades, t)
+		}
+	}
+	trades = append(trades, trade{price: price, amount: amount, time: timestamp})
+	p.trades[symbol] = trades
+
+	vwap, volume := computeTradeVWAP(trades)
+	volume = volume.MulInt64(int64(24 * time.Hour / tradeWindow))
+
+	p.setTickerPrice(symbol, vwap, volume, time.Now())
+}
+
+// computeTradeVWAP returns the volume weighted average price and the total
+// volume of the trades, which must not be empty.
+func computeTradeVWAP(trades []trade) (sdk.Dec, sdk.Dec) {
+	value := sdk.ZeroDec()
+	volume := sdk.ZeroDec()
+	for _, t := range trades {
+		value = value.Add(t.price.Mul(t.amount))
+		volume = volume.Add(t.amount)
+	}
+	return value.Quo(volume), volume
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTrade(t *testing.T) {
+	p := provider{
+		endpoints: Endpoint{Name: ProviderMock},
+		logger:    zerolog.Nop(),
+		pairs: map[string]types.CurrencyPair{
+			"ATOMUSDT": testAtomUsdtCurrencyPair,
+		},
+		inverse: map[string]types.CurrencyPair{
+			"USDTBTC": testBtcUsdtCurrencyPair,
+		},
+		tickers:   map[string]types.TickerPrice{},
+		trades:    map[string][]trade{},
+		quoteVols: map[string]sdk.Dec{},
+		liquidity: map[string]sdk.Dec{},
+	}
+
+	now := time.Now()
+	p.setTrade("ATOMUSDT", sdk.NewDec(10), sdk.NewDec(3), now.Add(-4*time.Minute))
+	p.setTrade("ATOMUSDT", sdk.NewDec(12), sdk.NewDec(1), now.Add(-time.Minute))
+
+	// (10*3 + 12*1) / 4
+	ticker := p.tickers["ATOMUSDT"]
+	require.Equal(t, "10.500000000000000000", ticker.Price.String())
+	// 4 traded in 5 minutes, extrapolated to 24h
+	require.Equal(t, "1152.000000000000000000", ticker.Volume.String())
+
+	// trades outside the window are dropped, relative to the latest trade
+	p.setTrade("ATOMUSDT", sdk.NewDec(11), sdk.NewDec(1), now.Add(time.Minute))
+	require.Len(t, p.trades["ATOMUSDT"], 2)
+	require.Equal(t, "11.500000000000000000", p.tickers["ATOMUSDT"].Price.String())
+
+	// invalid trades are ignored
+	p.setTrade("ATOMUSDT", sdk.NewDec(100), sdk.ZeroDec(), now.Add(time.Minute))
+	p.setTrade("ATOMUSDT", sdk.Dec{}, sdk.NewDec(1), now.Add(time.Minute))
+	require.Len(t, p.trades["ATOMUSDT"], 2)
+
+	// inverted pairs are priced like tickers
+	p.setTrade("USDTBTC", sdk.MustNewDecFromStr("0.00002"), sdk.NewDec(100000), now)
+	require.Equal(t, "50000.000000000000000000", p.tickers["BTCUSDT"].Price.String())
+}
@@ -24,6 +24,8 @@ var (
 			"https://rpc.ankr.com/eth",
 		},
 		PollInterval: 10 * time.Second,
+		ChainId:      "1",
+		MaxBlockLag:  3,
 		// ContractAddresses: map[string]string{
 		// 	"WSTETHWETH": "0x109830a1AAaD605BbF02a9dFA7B0B92EC2FB7dAa",
 		// },
@@ -36,6 +38,7 @@ type (
 	UniswapV3Provider struct {
 		provider
 		decimals map[string]uint64
+		tokens   map[string]string
 	}
 
 	UniswapV3Response struct {
@@ -43,6 +46,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderUniswapV3, uniswapv3DefaultEndpoints, NewUniswapV3Provider)
+}
+
 func NewUniswapV3Provider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -103,7 +110,7 @@ func (p *UniswapV3Provider) Poll() error {
 
 		base := pair.Base
 		quote := pair.Quote
-		_, found := p.contracts[pair.String()]
+		_, found := p.contracts.Contract(pair.String())
 		if !found {
 			base = pair.Quote
 			quote = pair.Base
@@ -133,6 +140,15 @@ func (p *UniswapV3Provider) Poll() error {
 			price = price.Quo(sdk.NewDec(10).Power(diff))
 		}
 
+		liquidity, err := p.getEthPoolLiquidity(
+			contract, p.tokens[base], p.tokens[quote],
+			decimalsBase, decimalsQuote, price,
+		)
+		if err != nil {
+			p.logger.Warn().Err(err).Str("symbol", symbol).Msg("failed to get liquidity")
+		}
+		p.setLiquidity(symbol, liquidity)
+
 		now := time.Now()
 
 		p.setTickerPrice(
@@ -247,6 +263,7 @@ func (p *UniswapV3Provider) getUniswapV3Decimals(contract string) (uint64, error
 
 func (p *UniswapV3Provider) setDecimals() {
 	p.decimals = map[string]uint64{}
+	p.tokens = map[string]string{}
 
 	for _, pair := range p.getAllPairs() {
 		contract, err := p.getContractAddress(pair)
@@ -259,7 +276,7 @@ func (p *UniswapV3Provider) setDecimals() {
 		// the address for the reversed pair
 		base := pair.Base
 		quote := pair.Quote
-		_, found := p.contracts[pair.String()]
+		_, found := p.contracts.Contract(pair.String())
 		if !found {
 			base = pair.Quote
 			quote = pair.Base
@@ -276,6 +293,7 @@ func (p *UniswapV3Provider) setDecimals() {
 			}
 
 			denom := denoms[i]
+			p.tokens[denom] = tokenAddress
 
 			_, found = p.decimals[denom]
 			if !found {
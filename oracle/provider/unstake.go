@@ -31,6 +31,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderUnstake, unstakeDefaultEndpoints, NewUnstakeProvider)
+}
+
 func NewUnstakeProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -47,8 +51,6 @@ func NewUnstakeProvider(
 		nil,
 	)
 
-	provider.contracts = provider.endpoints.ContractAddresses
-
 	availablePairs, _ := provider.GetAvailablePairs()
 	provider.setPairs(pairs, availablePairs, nil)
 
@@ -62,7 +64,7 @@ func NewUnstakeProvider(
 		provider.decimals[symbol] = uint64(decimals)
 	}
 
-	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	go startBlockPolling(provider, provider.endpoints.PollInterval, logger)
 	return provider, nil
 }
 
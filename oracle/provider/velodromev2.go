@@ -3,10 +3,12 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"price-feeder/oracle/types"
 
+	sdkmath "cosmossdk.io/math"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/rs/zerolog"
 )
@@ -17,6 +19,8 @@ var (
 		Name:         ProviderVelodromeV2,
 		Urls:         []string{},
 		PollInterval: 10 * time.Second,
+		ChainId:      "10",
+		MaxBlockLag:  5,
 	}
 )
 
@@ -27,6 +31,7 @@ type (
 		provider
 		decimals map[string]uint64
 		symbols  map[string]string
+		stable   map[string]bool // contract -> stable pool
 	}
 
 	VelodromeV2Response struct {
@@ -34,6 +39,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderVelodromeV2, velodromev2DefaultEndpoints, NewVelodromeV2Provider)
+}
+
 func NewVelodromeV2Provider(
 	ctx context.Context,
 	logger zerolog.Logger,
@@ -80,26 +89,12 @@ func (p *VelodromeV2Provider) Poll() error {
 			continue
 		}
 
-		data := fmt.Sprintf(
-			// symbol has 0x prefix, dropping that
-			"%s%064s%064d%064d", hash[:8], p.symbols[pair.Base][2:], 1, 1,
-		)
-		response, err := p.doEthCall(contract, data)
-		if err != nil {
-			p.logger.Err(err)
-			continue
-		}
-
-		decoded, err := decodeEthData(response, types)
-		if err != nil {
-			p.logger.Err(err)
-			continue
-		}
-
+		// prices are set in terms of the provider symbol, which is the
+		// inverted pair, if the contract is configured for the inverted
+		// pair
 		base := pair.Base
 		quote := pair.Quote
-		_, found := p.contracts[pair.String()]
-		if !found {
+		if _, inverse := p.inverse[symbol]; inverse {
 			base = pair.Quote
 			quote = pair.Base
 		}
@@ -117,6 +112,44 @@ func (p *VelodromeV2Provider) Poll() error {
 				Msg("no decimals found")
 		}
 
+		// the liquidity is in terms of the symbol quote as well
+		liquidity, err := p.getLiquidity(
+			contract, p.symbols[base], p.symbols[quote], decimalsQuote,
+		)
+		if err != nil {
+			p.logger.Warn().Err(err).Str("symbol", symbol).Msg("failed to get liquidity")
+		}
+		p.setLiquidity(symbol, liquidity)
+
+		if p.stable[contract] {
+			price, err := p.getStablePrice(
+				contract, p.symbols[base], decimalsBase, decimalsQuote,
+			)
+			if err != nil {
+				p.logger.Err(err).Str("symbol", symbol).Msg("failed to get stable price")
+				continue
+			}
+
+			p.setTickerPrice(symbol, price, sdk.ZeroDec(), time.Now())
+			continue
+		}
+
+		data := fmt.Sprintf(
+			// symbol has 0x prefix, dropping that
+			"%s%064s%064d%064d", hash[:8], p.symbols[base][2:], 1, 1,
+		)
+		response, err := p.doEthCall(contract, data)
+		if err != nil {
+			p.logger.Err(err)
+			continue
+		}
+
+		decoded, err := decodeEthData(response, types)
+		if err != nil {
+			p.logger.Err(err)
+			continue
+		}
+
 		price := strToDec(fmt.Sprintf("%v", decoded[0]))
 
 		var diff uint64
@@ -141,6 +174,108 @@ func (p *VelodromeV2Provider) Poll() error {
 	return nil
 }
 
+// getStablePrice returns the price of the token in for stable pools. The
+// reserve ratio misprices imbalanced stable pools, as they follow the
+// x3y+y3x invariant, so the pool is asked for the output amount of one whole
+// token in instead, which is small enough to have no relevant price impact.
+func (p *VelodromeV2Provider) getStablePrice(
+	contract, tokenIn string,
+	decimalsIn, decimalsOut uint64,
+) (sdk.Dec, error) {
+	hash, err := keccak256("getAmountOut(uint256,address)")
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	amountIn := sdkmath.NewIntWithDecimal(1, int(decimalsIn))
+
+	data := fmt.Sprintf(
+		// address has 0x prefix, dropping that
+		"%s%064x%064s", hash[:8], amountIn.BigInt(), tokenIn[2:],
+	)
+
+	result, err := p.doEthCall(contract, data)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	decoded, err := decodeEthData(result, []string{"uint256"})
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	amountOut := strToDec(fmt.Sprintf("%v", decoded[0]))
+	if amountOut.IsNil() || !amountOut.IsPositive() {
+		return sdk.Dec{}, fmt.Errorf("invalid amount out")
+	}
+
+	return amountOut.Quo(sdk.NewDec(10).Power(decimalsOut)), nil
+}
+
+// getLiquidity returns the pool liquidity in terms of the quote token,
+// approximated as twice the quote reserve. Pool tokens are sorted by
+// address, which determines the position of the quote reserve.
+func (p *VelodromeV2Provider) getLiquidity(
+	contract, base, quote string,
+	decimalsQuote uint64,
+) (sdk.Dec, error) {
+	hash, err := keccak256("getReserves()")
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	result, err := p.doEthCall(contract, hash[:8])
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	decoded, err := decodeEthData(
+		result, []string{"uint256", "uint256", "uint256"},
+	)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	index := 1
+	if strings.ToLower(quote) < strings.ToLower(base) {
+		index = 0
+	}
+
+	reserve := strToDec(fmt.Sprintf("%v", decoded[index]))
+	if reserve.IsNil() {
+		return sdk.Dec{}, fmt.Errorf("invalid reserve")
+	}
+
+	return reserve.
+		Quo(sdk.NewDec(10).Power(decimalsQuote)).
+		MulInt64(2), nil
+}
+
+// isStable returns true, if the pool uses the stable invariant.
+func (p *VelodromeV2Provider) isStable(contract string) (bool, error) {
+	hash, err := keccak256("stable()")
+	if err != nil {
+		return false, err
+	}
+
+	result, err := p.doEthCall(contract, fmt.Sprintf("%s%064d", hash[:8], 0))
+	if err != nil {
+		return false, err
+	}
+
+	decoded, err := decodeEthData(result, []string{"bool"})
+	if err != nil {
+		return false, err
+	}
+
+	stable, ok := decoded[0].(bool)
+	if !ok {
+		return false, fmt.Errorf("unexpected stable() result")
+	}
+
+	return stable, nil
+}
+
 func (p *VelodromeV2Provider) GetAvailablePairs() (map[string]struct{}, error) {
 	return p.getAvailablePairsFromContracts()
 }
@@ -178,6 +313,7 @@ func (p *VelodromeV2Provider) getTokenAddresses(
 func (p *VelodromeV2Provider) setDecimals() {
 	p.decimals = map[string]uint64{}
 	p.symbols = map[string]string{}
+	p.stable = map[string]bool{}
 
 	for _, pair := range p.getAllPairs() {
 		contract, err := p.getContractAddress(pair)
@@ -190,7 +326,7 @@ func (p *VelodromeV2Provider) setDecimals() {
 		// the address for the reversed pair
 		base := pair.Base
 		quote := pair.Quote
-		_, found := p.contracts[pair.String()]
+		_, found := p.contracts.Contract(pair.String())
 		if !found {
 			base = pair.Quote
 			quote = pair.Base
@@ -203,6 +339,12 @@ func (p *VelodromeV2Provider) setDecimals() {
 			continue
 		}
 
+		stable, err := p.isStable(contract)
+		if err != nil {
+			p.logger.Error().Err(err).Str("contract", contract).Msg("failed to get pool type")
+		}
+		p.stable[contract] = stable
+
 		for i, address := range addresses {
 			denom := denoms[i]
 			p.symbols[denom] = address
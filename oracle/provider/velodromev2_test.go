@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVelodromeV2GetStablePrice(t *testing.T) {
+	var calldata string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Params []struct {
+				Data string `json:"data"`
+			} `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		calldata = request.Params[0].Data
+
+		// 0.999 USDC (6 decimals)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"0x%064x"}`, 999000)
+	}))
+	defer server.Close()
+
+	p := VelodromeV2Provider{
+		provider: provider{
+			logger:   zerolog.Nop(),
+			http:     newDefaultHTTPClient(),
+			httpBase: server.URL,
+		},
+	}
+
+	token := "0xda10009cbd5d07dd0cecc66161fc93d7c9000da1"
+	price, err := p.getStablePrice(server.URL, token, 18, 6)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("0.999"), price)
+
+	// one whole token in (1e18) and the token address as arguments
+	require.True(t, strings.HasSuffix(calldata, fmt.Sprintf("%064x%064s", uint64(1e18), token[2:])))
+}
+
+func TestVelodromeV2PollStableReversedPair(t *testing.T) {
+	usdc := "0x0b2c639c533813f4aa9d7837caf62653d097ff85"
+	dai := "0xda10009cbd5d07dd0cecc66161fc93d7c9000da1"
+
+	getReserves, err := keccak256("getReserves()")
+	require.NoError(t, err)
+	getAmountOut, err := keccak256("getAmountOut(uint256,address)")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Params []json.RawMessage `json:"params"`
+		}
+		var tx struct {
+			Data string `json:"data"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if len(request.Params) > 0 {
+			_ = json.Unmarshal(request.Params[0], &tx)
+		}
+		data := strings.TrimPrefix(tx.Data, "0x")
+
+		switch {
+		case strings.HasPrefix(data, getReserves[:8]):
+			// tokens are sorted by address: 1M USDC, 1.25M DAI
+			fmt.Fprintf(
+				w, `{"jsonrpc":"2.0","id":1,"result":"0x%064x%064x%064x"}`,
+				sdk.NewInt(1_000_000).MulRaw(1e6).BigInt(),
+				sdk.NewInt(1_250_000).Mul(sdk.NewInt(1e18)).BigInt(),
+				0,
+			)
+		case strings.HasPrefix(data, getAmountOut[:8]) && strings.HasSuffix(data, usdc[2:]):
+			// 1 USDC in, 1.25 DAI out
+			fmt.Fprintf(
+				w, `{"jsonrpc":"2.0","id":1,"result":"0x%064x"}`,
+				sdk.NewInt(125).Mul(sdk.NewInt(1e16)).BigInt(),
+			)
+		case strings.HasPrefix(data, getAmountOut[:8]) && strings.HasSuffix(data, dai[2:]):
+			// 1 DAI in, 0.8 USDC out
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"0x%064x"}`, 800000)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	// the contract is configured for the reversed pair
+	pair := types.CurrencyPair{Base: "DAI", Quote: "USDC"}
+	p := &VelodromeV2Provider{
+		decimals: map[string]uint64{"DAI": 18, "USDC": 6},
+		symbols:  map[string]string{"DAI": dai, "USDC": usdc},
+		stable:   map[string]bool{"0xpool": true},
+	}
+	p.Init(
+		context.Background(),
+		Endpoint{
+			Name:              ProviderVelodromeV2,
+			Urls:              []string{server.URL},
+			ContractAddresses: map[string]string{"USDCDAI": "0xpool"},
+		},
+		zerolog.Nop(),
+		[]types.CurrencyPair{pair},
+		nil,
+		nil,
+	)
+	availablePairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	p.setPairs([]types.CurrencyPair{pair}, availablePairs, nil)
+
+	require.NoError(t, p.Poll())
+
+	ticker, found := p.tickers["DAIUSDC"]
+	require.True(t, found)
+	require.Equal(t, sdk.MustNewDecFromStr("0.8"), ticker.Price)
+	// 2.5M DAI liquidity, in terms of USDC
+	require.Equal(t, sdk.NewDec(2_000_000), ticker.Liquidity)
+}
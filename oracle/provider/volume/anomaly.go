@@ -0,0 +1,222 @@
+package volume
+
+import (
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/oracle/labels"
+)
+
+const (
+	// spikeFactor defines how many times the volume of a single block may
+	// exceed the trailing average per block, before the excess is discounted.
+	spikeFactor = 100
+	// minSpikeSamples defines the number of blocks needed, before the
+	// trailing average is used to detect volume spikes.
+	minSpikeSamples = 100
+)
+
+// maxTraderShare defines the max share of the volume of a symbol, that is
+// counted for a single trader address.
+var maxTraderShare = sdk.MustNewDecFromStr("0.5")
+
+type (
+	traderVolume struct {
+		height uint64
+		time   int64
+		symbol string
+		trader string
+		amount sdk.Dec
+	}
+
+	// traders tracks the volume per trader address of the blocks added since
+	// the start, as trader addresses are not persisted.
+	traders struct {
+		blocks  map[uint64]struct{}
+		volumes []traderVolume
+		totals  map[string]map[string]sdk.Dec
+	}
+)
+
+func newTraders() *traders {
+	return &traders{
+		blocks:  map[uint64]struct{}{},
+		volumes: []traderVolume{},
+		totals:  map[string]map[string]sdk.Dec{},
+	}
+}
+
+// AddTrader adds the amount traded by the address to the trader volumes of
+// the symbol. Providers should add all trades, whose sender is known.
+func (v *Volume) AddTrader(symbol, trader string, amount sdk.Dec) {
+	if trader == "" || amount.IsNil() || !amount.IsPositive() {
+		return
+	}
+	if v.Traders == nil {
+		v.Traders = map[string]map[string]sdk.Dec{}
+	}
+	_, found := v.Traders[symbol]
+	if !found {
+		v.Traders[symbol] = map[string]sdk.Dec{}
+	}
+	value, found := v.Traders[symbol][trader]
+	if !found {
+		value = sdk.ZeroDec()
+	}
+	v.Traders[symbol][trader] = value.Add(amount)
+}
+
+// add adds the trader volumes of all new blocks and removes blocks older
+// than startTime.
+func (t *traders) add(volumes []Volume, startTime int64) {
+	for _, volume := range volumes {
+		_, found := t.blocks[volume.Height]
+		if found || len(volume.Traders) == 0 || volume.Time < startTime {
+			continue
+		}
+		t.blocks[volume.Height] = struct{}{}
+
+		for symbol, traders := range volume.Traders {
+			for trader, amount := range traders {
+				t.volumes = append(t.volumes, traderVolume{
+					height: volume.Height,
+					time:   volume.Time,
+					symbol: symbol,
+					trader: trader,
+					amount: amount,
+				})
+				t.update(symbol, trader, amount)
+			}
+		}
+	}
+
+	remaining := t.volumes[:0]
+	for _, volume := range t.volumes {
+		if volume.time >= startTime {
+			remaining = append(remaining, volume)
+			continue
+		}
+		delete(t.blocks, volume.height)
+		t.update(volume.symbol, volume.trader, volume.amount.Neg())
+	}
+	t.volumes = remaining
+}
+
+func (t *traders) update(symbol, trader string, amount sdk.Dec) {
+	_, found := t.totals[symbol]
+	if !found {
+		t.totals[symbol] = map[string]sdk.Dec{}
+	}
+	total, found := t.totals[symbol][trader]
+	if !found {
+		total = sdk.ZeroDec()
+	}
+	total = total.Add(amount)
+	if !total.IsPositive() {
+		delete(t.totals[symbol], trader)
+		return
+	}
+	t.totals[symbol][trader] = total
+}
+
+// dominant returns the trader with the highest volume of the symbol.
+func (t *traders) dominant(symbol string) (string, sdk.Dec) {
+	var (
+		dominant string
+		max      = sdk.ZeroDec()
+	)
+	for trader, total := range t.totals[symbol] {
+		if total.GT(max) || (total.Equal(max) && trader < dominant) {
+			dominant = trader
+			max = total
+		}
+	}
+	return dominant, max
+}
+
+// discountSpikes caps the volume of blocks exceeding the trailing average
+// per block by more than spikeFactor, so single blocks with wash trades
+// don't dominate the 24h volume.
+func (h *VolumeHandler) discountSpikes(volumes []Volume) {
+	for _, volume := range volumes {
+		for symbol, value := range volume.Values {
+			total, found := h.totals[symbol]
+			if !found || total.Values < minSpikeSamples || value.IsNil() {
+				continue
+			}
+
+			average := total.Total.QuoInt64(int64(total.Values))
+			if !average.IsPositive() {
+				continue
+			}
+
+			limit := average.MulInt64(spikeFactor)
+			if value.LTE(limit) {
+				continue
+			}
+
+			h.logger.Warn().
+				Str("symbol", symbol).
+				Uint64("height", volume.Height).
+				Str("volume", value.String()).
+				Str("average", average.String()).
+				Msg("volume spike discounted")
+			h.reportAnomaly(symbol, "spike")
+
+			volume.Values[symbol] = limit
+
+			// scale the trader volumes of the block accordingly
+			ratio := limit.Quo(value)
+			for trader, amount := range volume.Traders[symbol] {
+				volume.Traders[symbol][trader] = amount.Mul(ratio)
+			}
+		}
+	}
+}
+
+// discountDominance limits the volume of the dominant trader of the symbol
+// to maxTraderShare of the total volume, if trader addresses are available.
+func (h *VolumeHandler) discountDominance(symbol string, total sdk.Dec) sdk.Dec {
+	if h.traders == nil || !total.IsPositive() {
+		return total
+	}
+
+	trader, amount := h.traders.dominant(symbol)
+	if trader == "" {
+		return total
+	}
+	if amount.GT(total) {
+		amount = total
+	}
+
+	if amount.Quo(total).LTE(maxTraderShare) {
+		return total
+	}
+
+	// the dominant trader accounts for exactly maxTraderShare afterwards
+	others := total.Sub(amount)
+	capped := others.Mul(maxTraderShare).Quo(sdk.OneDec().Sub(maxTraderShare))
+
+	h.logger.Warn().
+		Str("symbol", symbol).
+		Str("trader", trader).
+		Str("volume", amount.String()).
+		Str("total", total.String()).
+		Msg("single trader volume discounted")
+	h.reportAnomaly(symbol, "dominance")
+
+	return others.Add(capped)
+}
+
+func (h *VolumeHandler) reportAnomaly(symbol, anomaly string) {
+	telemetry.IncrCounterWithLabels(
+		[]string{"volume", "anomaly"},
+		1,
+		[]metrics.Label{
+			labels.Provider(h.provider),
+			labels.Pair("symbol", symbol),
+			telemetry.NewLabel("type", anomaly),
+		},
+	)
+}
@@ -0,0 +1,72 @@
+package volume
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscountSpikes(t *testing.T) {
+	h := VolumeHandler{
+		logger: zerolog.Nop(),
+		totals: map[string]*Total{"KUJIUSK": NewTotal()},
+	}
+
+	volumes := []Volume{{
+		Height: 1,
+		Values: map[string]sdk.Dec{"KUJIUSK": sdk.NewDec(1000)},
+	}}
+
+	// not enough samples for a trailing average
+	h.discountSpikes(volumes)
+	require.Equal(t, sdk.NewDec(1000), volumes[0].Values["KUJIUSK"])
+
+	for i := 0; i < minSpikeSamples; i++ {
+		h.totals["KUJIUSK"].Add(sdk.NewDec(2), uint64(i+1))
+	}
+
+	volumes[0].AddTrader("KUJIUSK", "kujira1abc", sdk.NewDec(1000))
+
+	// 1000 is more than 100x the average of 2
+	h.discountSpikes(volumes)
+	require.Equal(t, sdk.NewDec(200), volumes[0].Values["KUJIUSK"])
+	require.Equal(t, sdk.NewDec(200), volumes[0].Traders["KUJIUSK"]["kujira1abc"])
+
+	// volumes within the limit are kept
+	volumes[0].Values["KUJIUSK"] = sdk.NewDec(150)
+	h.discountSpikes(volumes)
+	require.Equal(t, sdk.NewDec(150), volumes[0].Values["KUJIUSK"])
+}
+
+func TestDiscountDominance(t *testing.T) {
+	h := VolumeHandler{
+		logger:  zerolog.Nop(),
+		traders: newTraders(),
+	}
+
+	volume1 := Volume{Height: 1, Time: 100}
+	volume1.AddTrader("KUJIUSK", "kujira1abc", sdk.NewDec(80))
+	volume1.AddTrader("KUJIUSK", "kujira1def", sdk.NewDec(10))
+
+	volume2 := Volume{Height: 2, Time: 200}
+	volume2.AddTrader("KUJIUSK", "kujira1ghi", sdk.NewDec(10))
+
+	h.traders.add([]Volume{volume1, volume2}, 0)
+
+	// adding the same block again is ignored
+	h.traders.add([]Volume{volume1}, 0)
+
+	// kujira1abc traded 80 of 100, it is limited to the volume of all others
+	require.Equal(t, sdk.NewDec(40), h.discountDominance("KUJIUSK", sdk.NewDec(100)))
+
+	// no trader data available
+	require.Equal(t, sdk.NewDec(100), h.discountDominance("USKKUJI", sdk.NewDec(100)))
+
+	// the first block is outside the period
+	h.traders.add(nil, 150)
+	_, found := h.traders.totals["KUJIUSK"]["kujira1abc"]
+	require.False(t, found)
+	require.Equal(t, sdk.NewDec(100), h.discountDominance("KUJIUSK", sdk.NewDec(100)))
+}
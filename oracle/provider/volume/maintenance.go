@@ -0,0 +1,87 @@
+package volume
+
+import (
+	"database/sql"
+)
+
+// Prune deletes the volumes of all providers and symbols, that are not part
+// of the provided provider -> symbols mapping anymore, e.g. after config
+// changes. It returns the number of remaining rows per provider and the
+// number of deleted rows.
+func Prune(
+	db *sql.DB,
+	configured map[string]map[string]struct{},
+) (map[string]int64, int64, error) {
+	counts := map[string]int64{}
+
+	var name string
+	err := db.QueryRow(`
+		SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name = 'volume_history'
+	`).Scan(&name)
+	if err == sql.ErrNoRows {
+		return counts, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := db.Query(`
+		SELECT provider, symbol, COUNT(*) FROM volume_history
+		GROUP BY provider, symbol
+	`)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	type orphan struct {
+		provider string
+		symbol   string
+	}
+
+	orphans := []orphan{}
+	for rows.Next() {
+		var (
+			provider, symbol string
+			count            int64
+		)
+
+		err = rows.Scan(&provider, &symbol, &count)
+		if err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+
+		_, found := configured[provider][symbol]
+		if !found {
+			orphans = append(orphans, orphan{provider, symbol})
+			continue
+		}
+
+		counts[provider] += count
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var deleted int64
+	for _, o := range orphans {
+		result, err := db.Exec(`
+			DELETE FROM volume_history
+			WHERE provider = ? AND symbol = ?
+		`, o.provider, o.symbol)
+		if err != nil {
+			return nil, deleted, err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, deleted, err
+		}
+		deleted += affected
+	}
+
+	return counts, deleted, nil
+}
@@ -0,0 +1,57 @@
+package volume
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrune(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	// no table yet
+	counts, deleted, err := Prune(db, nil)
+	require.NoError(t, err)
+	require.Len(t, counts, 0)
+	require.Equal(t, int64(0), deleted)
+
+	_, err = db.Exec(`
+		CREATE TABLE volume_history(
+			block INT NOT NULL,
+			symbol TEXT NOT NULL,
+			provider TEXT NOT NULL,
+			time INT NOT NULL,
+			volume TEXT NOT NULL,
+			CONSTRAINT id PRIMARY KEY (symbol, provider, block)
+		)`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`
+		INSERT INTO volume_history(block, symbol, provider, time, volume) VALUES
+		(1, 'KUJIUSK', 'finv2', 1, '1'),
+		(2, 'KUJIUSK', 'finv2', 2, '1'),
+		(1, 'USKKUJI', 'finv2', 1, '1'),
+		(1, 'ATOMOSMO', 'osmosisv2', 1, '1'),
+		(1, 'KUJIUSK', 'oldprovider', 1, '1')
+	`)
+	require.NoError(t, err)
+
+	configured := map[string]map[string]struct{}{
+		"finv2":     {"KUJIUSK": {}},
+		"osmosisv2": {"ATOMOSMO": {}},
+	}
+
+	counts, deleted, err = Prune(db, configured)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+	require.Equal(t, map[string]int64{"finv2": 2, "osmosisv2": 1}, counts)
+
+	var remaining int
+	err = db.QueryRow("SELECT COUNT(*) FROM volume_history").Scan(&remaining)
+	require.NoError(t, err)
+	require.Equal(t, 3, remaining)
+}
@@ -1,11 +1,11 @@
 package volume
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"math"
 	"sort"
-	"strings"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -19,6 +19,9 @@ type Volume struct {
 	Height uint64
 	Time   int64
 	Values map[string]sdk.Dec
+	// Traders contains the volume per symbol and trader address, if the
+	// provider knows the senders of the trades. It is not persisted.
+	Traders map[string]map[string]sdk.Dec
 }
 
 type VolumeHandler struct {
@@ -31,9 +34,12 @@ type VolumeHandler struct {
 	period   int64
 	missing  []uint64
 	cleanup  *sql.Stmt
+	writer   *writer
+	traders  *traders
 }
 
 func NewVolumeHandler(
+	ctx context.Context,
 	logger zerolog.Logger,
 	db *sql.DB,
 	provider string,
@@ -54,9 +60,10 @@ func NewVolumeHandler(
 		volumes:  []Volume{},
 		period:   period,
 		missing:  []uint64{},
+		traders:  newTraders(),
 	}
 
-	err := handler.init()
+	err := handler.init(ctx)
 	if err != nil {
 		return handler, err
 	}
@@ -64,7 +71,7 @@ func NewVolumeHandler(
 	return handler, nil
 }
 
-func (h *VolumeHandler) init() error {
+func (h *VolumeHandler) init(ctx context.Context) error {
 	_, err := h.db.Exec(`
 		CREATE TABLE IF NOT EXISTS volume_history(
 			block INT NOT NULL,
@@ -87,13 +94,15 @@ func (h *VolumeHandler) init() error {
 
 	h.cleanup, err = h.db.Prepare(`
 		DELETE from volume_history
-		WHERE time < ?
+		WHERE provider = ? AND time < ?
 	`)
 	if err != nil {
 		h.logger.Err(err).Msg("failed creating cleanup statement")
 		return err
 	}
 
+	h.writer = newWriter(ctx, h.logger, h.db, h.provider, h.cleanup)
+
 	return h.load()
 }
 
@@ -210,7 +219,7 @@ func (h *VolumeHandler) Get(symbol string) (sdk.Dec, error) {
 		return sdk.ZeroDec(), err
 	}
 
-	return total.Total, nil
+	return h.discountDominance(symbol, total.Total), nil
 }
 
 func (h *VolumeHandler) Add(volumes []Volume) {
@@ -234,8 +243,11 @@ func (h *VolumeHandler) Add(volumes []Volume) {
 		return
 	}
 
+	h.discountSpikes(volumes)
+
 	if len(h.volumes) == 0 {
 		h.update(volumes)
+		h.addTraders(volumes)
 		return
 	}
 
@@ -259,6 +271,8 @@ func (h *VolumeHandler) Add(volumes []Volume) {
 		}
 	}
 
+	h.addTraders(volumes)
+
 	if len(h.volumes) < 2 {
 		return
 	}
@@ -268,18 +282,7 @@ func (h *VolumeHandler) Add(volumes []Volume) {
 	stopTime := h.volumes[len(h.volumes)-1].Time
 	startTime := stopTime - h.period
 
-	go func() {
-		err := h.persist(volumes)
-		if err != nil {
-			h.logger.Error().Msg("error writing volumes to database")
-			return
-		}
-
-		_, err = h.cleanup.Exec(startTime)
-		if err != nil {
-			h.logger.Err(err).Msg("failed removing old volumes")
-		}
-	}()
+	h.writer.enqueue(volumes, startTime)
 
 	first := h.volumes[0]
 	last := h.volumes[len(h.volumes)-1]
@@ -310,6 +313,15 @@ func (h *VolumeHandler) Add(volumes []Volume) {
 	}
 }
 
+// addTraders adds the trader volumes within the period of the known volumes.
+func (h *VolumeHandler) addTraders(volumes []Volume) {
+	if len(h.volumes) == 0 {
+		return
+	}
+	stopTime := h.volumes[len(h.volumes)-1].Time
+	h.traders.add(volumes, stopTime-h.period)
+}
+
 func (h *VolumeHandler) append(volumes []Volume) {
 	t0 := time.Now()
 	h.logger.Debug().Msg("append")
@@ -516,57 +528,6 @@ func (h *VolumeHandler) update(volumes []Volume) {
 	h.logger.Info().Str("duration", time.Since(t0).String()).Msg("update")
 }
 
-func (h *VolumeHandler) persist(volumes []Volume) error {
-	if len(volumes) == 0 {
-		return nil
-	}
-
-	placeholders := []string{}
-	values := []interface{}{}
-
-	for _, volume := range volumes {
-		if len(volume.Values) == 0 {
-			h.logger.Warn().
-				Uint64("height", volume.Height).
-				Msg("no values found")
-			continue
-		}
-
-		for symbol, value := range volume.Values {
-			if value.IsNil() || value.IsNegative() {
-				continue
-			}
-
-			placeholders = append(placeholders, "(?, ?, ?, ?, ?)")
-			values = append(values, []interface{}{
-				symbol,
-				h.provider,
-				volume.Height,
-				volume.Time,
-				value.String(),
-			}...)
-		}
-	}
-
-	if len(placeholders) == 0 {
-		return nil
-	}
-
-	sql := fmt.Sprintf(`
-		INSERT OR IGNORE INTO volume_history(
-			symbol, provider, block, time, volume
-		) VALUES %s
-	`, strings.Join(placeholders, ", "))
-
-	_, err := h.db.Exec(sql, values...)
-	if err != nil {
-		h.logger.Err(err).Msg("failed inserting volumes")
-		return err
-	}
-
-	return nil
-}
-
 func (h *VolumeHandler) GetMissing(amount int) []uint64 {
 	if len(h.missing) >= amount {
 		return h.missing[len(h.missing)-amount:]
@@ -617,3 +578,13 @@ func (h *VolumeHandler) Debug(symbol string) {
 func (h *VolumeHandler) Symbols() []string {
 	return h.symbols
 }
+
+// Coverage returns the share of blocks within the volume period, that are
+// not missing.
+func (h *VolumeHandler) Coverage() float64 {
+	total := len(h.volumes) + len(h.missing)
+	if total == 0 {
+		return 0
+	}
+	return float64(len(h.volumes)) / float64(total)
+}
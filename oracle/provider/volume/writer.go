@@ -0,0 +1,240 @@
+package volume
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	"github.com/mattn/go-sqlite3"
+	"github.com/rs/zerolog"
+
+	"price-feeder/oracle/labels"
+)
+
+const (
+	writerQueueSize = 64
+	// sqlite allows 32766 variables per statement, each row uses 5
+	writerBatchSize  = 1000
+	writerMaxRetries = 5
+	writerRetryDelay = 200 * time.Millisecond
+)
+
+type (
+	writeJob struct {
+		volumes   []Volume
+		startTime int64
+	}
+
+	// writer persists volumes in a dedicated goroutine, so database writes
+	// never block the volume updates of the provider. The goroutine stops
+	// when the context is cancelled, after writing the queued volumes.
+	writer struct {
+		logger   zerolog.Logger
+		db       *sql.DB
+		provider string
+		cleanup  *sql.Stmt
+		queue    chan writeJob
+		done     chan struct{}
+	}
+)
+
+func newWriter(
+	ctx context.Context,
+	logger zerolog.Logger,
+	db *sql.DB,
+	provider string,
+	cleanup *sql.Stmt,
+) *writer {
+	w := &writer{
+		logger:   logger,
+		db:       db,
+		provider: provider,
+		cleanup:  cleanup,
+		queue:    make(chan writeJob, writerQueueSize),
+		done:     make(chan struct{}),
+	}
+
+	go w.run(ctx)
+
+	return w
+}
+
+// enqueue adds the volumes to the write queue. If the queue is full, the
+// volumes are dropped and will be fetched again as missing blocks after a
+// restart.
+func (w *writer) enqueue(volumes []Volume, startTime int64) {
+	select {
+	case w.queue <- writeJob{volumes: volumes, startTime: startTime}:
+	default:
+		w.logger.Warn().
+			Int("volumes", len(volumes)).
+			Msg("volume write queue full, dropping volumes")
+		telemetry.IncrCounterWithLabels(
+			[]string{"volume", "write", "dropped"},
+			1,
+			w.labels(),
+		)
+	}
+
+	w.reportQueueDepth()
+}
+
+func (w *writer) run(ctx context.Context) {
+	defer close(w.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.flush()
+			return
+		case job := <-w.queue:
+			w.write(job)
+		}
+	}
+}
+
+// flush writes the queued volumes without waiting for new ones.
+func (w *writer) flush() {
+	for {
+		select {
+		case job := <-w.queue:
+			w.write(job)
+		default:
+			return
+		}
+	}
+}
+
+func (w *writer) write(job writeJob) {
+	w.reportQueueDepth()
+
+	t0 := time.Now()
+
+	err := w.persist(job.volumes)
+	if err != nil {
+		w.logger.Err(err).Msg("error writing volumes to database")
+		return
+	}
+
+	metrics.MeasureSinceWithLabels(
+		[]string{"volume", "write", "latency"},
+		t0,
+		w.labels(),
+	)
+
+	err = w.retry(func() error {
+		_, err := w.cleanup.Exec(w.provider, job.startTime)
+		return err
+	})
+	if err != nil {
+		w.logger.Err(err).Msg("failed removing old volumes")
+	}
+}
+
+func (w *writer) persist(volumes []Volume) error {
+	placeholders := []string{}
+	values := []interface{}{}
+
+	for _, volume := range volumes {
+		if len(volume.Values) == 0 {
+			w.logger.Warn().
+				Uint64("height", volume.Height).
+				Msg("no values found")
+			continue
+		}
+
+		for symbol, value := range volume.Values {
+			if value.IsNil() || value.IsNegative() {
+				continue
+			}
+
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?)")
+			values = append(values, []interface{}{
+				symbol,
+				w.provider,
+				volume.Height,
+				volume.Time,
+				value.String(),
+			}...)
+		}
+	}
+
+	for len(placeholders) > 0 {
+		size := writerBatchSize
+		if len(placeholders) < size {
+			size = len(placeholders)
+		}
+
+		err := w.insert(placeholders[:size], values[:size*5])
+		if err != nil {
+			return err
+		}
+
+		placeholders = placeholders[size:]
+		values = values[size*5:]
+	}
+
+	return nil
+}
+
+func (w *writer) insert(placeholders []string, values []interface{}) error {
+	query := fmt.Sprintf(`
+		INSERT OR IGNORE INTO volume_history(
+			symbol, provider, block, time, volume
+		) VALUES %s
+	`, strings.Join(placeholders, ", "))
+
+	err := w.retry(func() error {
+		_, err := w.db.Exec(query, values...)
+		return err
+	})
+	if err != nil {
+		w.logger.Err(err).Msg("failed inserting volumes")
+		return err
+	}
+
+	return nil
+}
+
+// retry executes fn until it succeeds, retrying only if the database is
+// busy or locked.
+func (w *writer) retry(fn func() error) error {
+	var err error
+	for i := 0; i < writerMaxRetries; i++ {
+		err = fn()
+		if !isBusy(err) {
+			return err
+		}
+
+		w.logger.Debug().Err(err).Int("attempt", i+1).Msg("database busy")
+		time.Sleep(writerRetryDelay * time.Duration(i+1))
+	}
+	return err
+}
+
+func (w *writer) reportQueueDepth() {
+	telemetry.SetGaugeWithLabels(
+		[]string{"volume", "write", "queue"},
+		float32(len(w.queue)),
+		w.labels(),
+	)
+}
+
+func (w *writer) labels() []metrics.Label {
+	return []metrics.Label{
+		labels.Provider(w.provider),
+	}
+}
+
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
@@ -0,0 +1,133 @@
+package volume
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWriterDB(t *testing.T) (*sql.DB, *sql.Stmt) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE volume_history(
+			block INT NOT NULL,
+			symbol TEXT NOT NULL,
+			provider TEXT NOT NULL,
+			time INT NOT NULL,
+			volume TEXT NOT NULL,
+			CONSTRAINT id PRIMARY KEY (symbol, provider, block)
+		)`)
+	require.NoError(t, err)
+
+	cleanup, err := db.Prepare(`
+		DELETE from volume_history
+		WHERE provider = ? AND time < ?
+	`)
+	require.NoError(t, err)
+
+	return db, cleanup
+}
+
+func countVolumes(t *testing.T, db *sql.DB) int {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM volume_history").Scan(&count)
+	require.NoError(t, err)
+	return count
+}
+
+func TestWriterFlushesOnShutdown(t *testing.T) {
+	db, cleanup := newTestWriterDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := newWriter(ctx, zerolog.Nop(), db, "finv2", cleanup)
+
+	// more rows than a single insert batch
+	volumes := []Volume{}
+	for height := uint64(1); height <= writerBatchSize+10; height++ {
+		volumes = append(volumes, Volume{
+			Height: height,
+			Time:   int64(height),
+			Values: map[string]sdk.Dec{"KUJIUSK": sdk.NewDec(1)},
+		})
+	}
+	w.enqueue(volumes, 0)
+	w.enqueue([]Volume{{
+		Height: 1,
+		Time:   1,
+		Values: map[string]sdk.Dec{"USKKUJI": sdk.NewDec(2)},
+	}}, 0)
+
+	cancel()
+	<-w.done
+
+	require.Equal(t, writerBatchSize+11, countVolumes(t, db))
+}
+
+func TestWriterCleanup(t *testing.T) {
+	db, cleanup := newTestWriterDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := newWriter(ctx, zerolog.Nop(), db, "finv2", cleanup)
+
+	for height := uint64(1); height <= 3; height++ {
+		w.enqueue([]Volume{{
+			Height: height,
+			Time:   int64(height),
+			Values: map[string]sdk.Dec{
+				"KUJIUSK": sdk.NewDec(1),
+				// negative volumes are not persisted
+				"USKKUJI": sdk.NewDec(-1),
+			},
+		}}, int64(height))
+	}
+
+	cancel()
+	<-w.done
+
+	// every write removes the volumes before its start time
+	rows, err := db.Query("SELECT block FROM volume_history ORDER BY block")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	blocks := []int{}
+	for rows.Next() {
+		var block int
+		require.NoError(t, rows.Scan(&block))
+		blocks = append(blocks, block)
+	}
+	require.Equal(t, []int{3}, blocks)
+}
+
+func TestWriterDropsWhenQueueFull(t *testing.T) {
+	db, cleanup := newTestWriterDB(t)
+
+	// the writer goroutine isn't started, so the queue fills up
+	w := &writer{
+		logger:   zerolog.Nop(),
+		db:       db,
+		provider: "finv2",
+		cleanup:  cleanup,
+		queue:    make(chan writeJob, writerQueueSize),
+		done:     make(chan struct{}),
+	}
+
+	for i := 0; i < writerQueueSize+5; i++ {
+		w.enqueue([]Volume{}, 0)
+	}
+	require.Len(t, w.queue, writerQueueSize)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.run(ctx)
+
+	require.Len(t, w.queue, 0)
+	require.Equal(t, 0, countVolumes(t, db))
+}
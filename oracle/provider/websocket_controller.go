@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net/http"
 	"net/url"
 	"sync"
 	"time"
@@ -28,6 +29,10 @@ type (
 
 	SubscribeHandler func(...types.CurrencyPair) []interface{}
 
+	// UrlHandler returns the websocket url to connect to. It is called on
+	// every (re)connect, e.g. to fetch a fresh connect token.
+	UrlHandler func() (url.URL, error)
+
 	// WebsocketController defines a provider agnostic websocket handler
 	// that manages reconnecting, subscribing, and receiving messages
 	WebsocketController struct {
@@ -39,9 +44,14 @@ type (
 		pairs 				[]types.CurrencyPair
 		messageHandler      MessageHandler
 		subscribeHandler	SubscribeHandler
+		tradeSubscribeHandler SubscribeHandler
+		urlHandler          UrlHandler
 		pingDuration        time.Duration
 		pingMessage         string
 		pingMessageType     uint
+		headers             http.Header
+		compression         string
+		ipVersion           string
 		logger              zerolog.Logger
 
 		mtx              sync.Mutex
@@ -59,21 +69,30 @@ func NewWebsocketController(
 	pairs []types.CurrencyPair,
 	messageHandler MessageHandler,
 	subscribeHandler SubscribeHandler,
+	urlHandler UrlHandler,
 	pingDuration time.Duration,
 	pingMessageType uint,
 	pingMessage string,
+	headers map[string]string,
 	logger zerolog.Logger,
 ) *WebsocketController {
+	header := http.Header{}
+	for key, value := range headers {
+		header.Set(key, value)
+	}
+
 	return &WebsocketController{
 		parentCtx: ctx,
 		providerName: providerName,
 		websocketURL: websocketURL,
 		pairs: pairs,
 		subscribeHandler: subscribeHandler,
+		urlHandler: urlHandler,
 		messageHandler: messageHandler,
 		pingDuration: pingDuration,
 		pingMessage: pingMessage,
 		pingMessageType: pingMessageType,
+		headers: header,
 		logger: logger,
 	}
 }
@@ -101,7 +120,7 @@ func (wsc *WebsocketController) Start() {
 		go wsc.readWebSocket()
 		go wsc.pingLoop()
 
-		if err := wsc.subscribe(wsc.subscribeHandler(wsc.pairs...)); err != nil {
+		if err := wsc.subscribe(wsc.subscriptionMsgs(wsc.pairs...)); err != nil {
 			wsc.logger.Err(err).Send()
 			wsc.close()
 			continue
@@ -115,12 +134,29 @@ func (wsc *WebsocketController) connect() error {
 	wsc.mtx.Lock()
 	defer wsc.mtx.Unlock()
 
+	websocketURL := wsc.websocketURL
+	if wsc.urlHandler != nil {
+		newURL, err := wsc.urlHandler()
+		if err != nil {
+			return fmt.Errorf(types.ErrWebsocketDial.Error(), wsc.providerName, err)
+		}
+		websocketURL = newURL
+	}
+
+	// the host is resolved on every (re)connect, see dial.go
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = wsc.compression == WebsocketCompressionPerMessage
+	dialer.NetDialContext = newDialContext(wsc.ipVersion)
+
 	wsc.logger.Debug().Msg("connecting to websocket")
-	conn, resp, err := websocket.DefaultDialer.Dial(wsc.websocketURL.String(), nil)
+	conn, resp, err := dialer.Dial(websocketURL.String(), wsc.headers)
 	if err != nil {
 		return fmt.Errorf(types.ErrWebsocketDial.Error(), wsc.providerName, err)
 	}
 	defer resp.Body.Close()
+	wsc.logger.Debug().
+		Str("remote_addr", conn.RemoteAddr().String()).
+		Msg("connected to websocket")
 	wsc.client = conn
 	wsc.websocketCtx, wsc.websocketCancelFunc = context.WithCancel(wsc.parentCtx)
 	wsc.client.SetPingHandler(wsc.pingHandler)
@@ -154,7 +190,35 @@ func (wsc *WebsocketController) AddSubscriptionMsgs(msgs []interface{}) error {
 }
 
 func (w *WebsocketController) AddPairs(pairs []types.CurrencyPair) error {
-	return w.subscribe(w.subscribeHandler(pairs...))
+	return w.subscribe(w.subscriptionMsgs(pairs...))
+}
+
+// SubscribeTrades switches the subscriptions to the raw trade streams of
+// the pairs, using the given handler instead of the ticker subscriptions.
+// It has to be called before Start.
+func (wsc *WebsocketController) SubscribeTrades(handler SubscribeHandler) {
+	wsc.tradeSubscribeHandler = handler
+}
+
+// SetCompression sets the compression of the websocket messages, see
+// compression.go. It has to be called before Start.
+func (wsc *WebsocketController) SetCompression(compression string) {
+	wsc.compression = compression
+}
+
+// SetIPVersion restricts the connections to "ipv4" or "ipv6", see dial.go.
+// It has to be called before Start.
+func (wsc *WebsocketController) SetIPVersion(ipVersion string) {
+	wsc.ipVersion = ipVersion
+}
+
+// subscriptionMsgs returns the subscription messages of the pairs, for the
+// trade streams if enabled.
+func (wsc *WebsocketController) subscriptionMsgs(pairs ...types.CurrencyPair) []interface{} {
+	if wsc.tradeSubscribeHandler != nil {
+		return wsc.tradeSubscribeHandler(pairs...)
+	}
+	return wsc.subscribeHandler(pairs...)
 }
 
 // SendJSON sends a json message to the websocket connection using the Websocket
@@ -252,6 +316,14 @@ func (wsc *WebsocketController) readSuccess(messageType int, bz []byte) {
 	if len(bz) == 0 {
 		return
 	}
+	if messageType == websocket.BinaryMessage {
+		decompressed, err := decompressMessage(wsc.compression, bz)
+		if err != nil {
+			wsc.logger.Error().Err(err).Msg("failed to decompress message")
+			return
+		}
+		bz = decompressed
+	}
 	// mexc and bitget do not send a valid pong response code so check for it here
 	if string(bz) == "pong" {
 		return
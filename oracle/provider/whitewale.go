@@ -21,41 +21,49 @@ var (
 	whitewhaleCmdxDefaultEndpoints = Endpoint{
 		Name:         ProviderWhitewhaleCmdx,
 		Urls:         []string{},
+		ChainId:      "comdex-1",
 		PollInterval: 6 * time.Second,
 	}
 	whitewhaleHuahuaDefaultEndpoints = Endpoint{
 		Name:         ProviderWhitewhaleHuahua,
 		Urls:         []string{},
+		ChainId:      "chihuahua-1",
 		PollInterval: 6 * time.Second,
 	}
 	whitewhaleInjDefaultEndpoints = Endpoint{
 		Name:         ProviderWhitewhaleInj,
 		Urls:         []string{},
+		ChainId:      "injective-1",
 		PollInterval: 6 * time.Second,
 	}
 	whitewhaleJunoDefaultEndpoints = Endpoint{
 		Name:         ProviderWhitewhaleJuno,
 		Urls:         []string{},
+		ChainId:      "juno-1",
 		PollInterval: 6 * time.Second,
 	}
 	whitewhaleLuncDefaultEndpoints = Endpoint{
 		Name:         ProviderWhitewhaleLunc,
 		Urls:         []string{},
+		ChainId:      "columbus-5",
 		PollInterval: 6 * time.Second,
 	}
 	whitewhaleLunaDefaultEndpoints = Endpoint{
 		Name:         ProviderWhitewhaleLuna,
 		Urls:         []string{},
+		ChainId:      "phoenix-1",
 		PollInterval: 6 * time.Second,
 	}
 	whitewhaleSeiDefaultEndpoints = Endpoint{
 		Name:         ProviderWhitewhaleSei,
 		Urls:         []string{},
+		ChainId:      "pacific-1",
 		PollInterval: 6 * time.Second,
 	}
 	whitewhaleWhaleDefaultEndpoints = Endpoint{
 		Name:         ProviderWhitewhaleWhale,
 		Urls:         []string{},
+		ChainId:      "migaloo-1",
 		PollInterval: 6 * time.Second,
 	}
 )
@@ -103,6 +111,17 @@ type (
 	}
 )
 
+func init() {
+	registerWithDB(ProviderWhitewhaleCmdx, whitewhaleCmdxDefaultEndpoints, NewWhitewhaleProvider)
+	registerWithDB(ProviderWhitewhaleHuahua, whitewhaleHuahuaDefaultEndpoints, NewWhitewhaleProvider)
+	registerWithDB(ProviderWhitewhaleInj, whitewhaleInjDefaultEndpoints, NewWhitewhaleProvider)
+	registerWithDB(ProviderWhitewhaleJuno, whitewhaleJunoDefaultEndpoints, NewWhitewhaleProvider)
+	registerWithDB(ProviderWhitewhaleLunc, whitewhaleLuncDefaultEndpoints, NewWhitewhaleProvider)
+	registerWithDB(ProviderWhitewhaleLuna, whitewhaleLunaDefaultEndpoints, NewWhitewhaleProvider)
+	registerWithDB(ProviderWhitewhaleSei, whitewhaleSeiDefaultEndpoints, NewWhitewhaleProvider)
+	registerWithDB(ProviderWhitewhaleWhale, whitewhaleWhaleDefaultEndpoints, NewWhitewhaleProvider)
+}
+
 func NewWhitewhaleProvider(
 	db *sql.DB,
 	ctx context.Context,
@@ -132,7 +151,7 @@ func NewWhitewhaleProvider(
 		provider.denoms[asset.Denom] = symbol
 	}
 
-	go startPolling(provider, provider.endpoints.PollInterval, logger)
+	go startBlockPolling(provider, provider.endpoints.PollInterval, logger)
 	return provider, nil
 }
 
@@ -229,25 +248,9 @@ func (p *WhitewhaleProvider) Poll() error {
 
 		price := quoteAmount.Quo(baseAmount)
 
-		var volume sdk.Dec
-		// hack to get the proper volume
-		_, found := p.inverse[symbol]
-		if found {
-			volume, _ = p.volumes.Get(pair.Quote + pair.Base)
-
-			if !volume.IsZero() {
-				volume = volume.Quo(price)
-			}
-		} else {
-			volume, _ = p.volumes.Get(pair.String())
-		}
-
-		p.setTickerPrice(
-			symbol,
-			price,
-			volume,
-			timestamp,
-		)
+		// both sides of a constant product pool have the same value
+		p.setLiquidity(symbol, quoteAmount.MulInt64(2))
+		p.setTickerPriceFromVolumes(symbol, price, timestamp)
 	}
 
 	return nil
@@ -379,7 +382,7 @@ func (p *WhitewhaleProvider) getVolume(height uint64) (volume.Volume, error) {
 				continue
 			}
 
-			symbol, found := p.contracts[contract]
+			symbol, found := p.contracts.Symbol(contract)
 			if !found {
 				p.logger.Debug().
 					Str("contract", contract).
@@ -43,6 +43,10 @@ type (
 	}
 )
 
+func init() {
+	register(ProviderXt, xtDefaultEndpoints, NewXtProvider)
+}
+
 func NewXtProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
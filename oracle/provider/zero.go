@@ -21,19 +21,28 @@ var (
 )
 
 type (
-	// ZeroProvider defines an oracle provider that reports 0 for all pairs
+	// ZeroProvider defines an oracle provider that reports 0 for all pairs.
+	// If a csv file is configured as url, it plays the price paths of the
+	// file instead, starting when the provider is created, see PricePath.
 	ZeroProvider struct {
 		provider
+		path  *PricePath
+		start time.Time
+		now   func() time.Time
 	}
 )
 
+func init() {
+	register(ProviderZero, zeroDefaultEndpoints, NewZeroProvider)
+}
+
 func NewZeroProvider(
 	ctx context.Context,
 	logger zerolog.Logger,
 	endpoints Endpoint,
 	pairs ...types.CurrencyPair,
 ) (*ZeroProvider, error) {
-	provider := &ZeroProvider{}
+	provider := &ZeroProvider{now: time.Now}
 	provider.Init(
 		ctx,
 		endpoints,
@@ -42,6 +51,24 @@ func NewZeroProvider(
 		nil,
 		nil,
 	)
+
+	if provider.httpBase != "" {
+		path, err := LoadPricePath(provider.httpBase)
+		if err != nil {
+			return nil, err
+		}
+		provider.path = &path
+		provider.start = provider.now()
+
+		availablePairs, _ := provider.GetAvailablePairs()
+		provider.setPairs(pairs, availablePairs, nil)
+
+		provider.logger.Info().
+			Str("file", provider.httpBase).
+			Strs("symbols", path.Symbols()).
+			Msg("playing price paths")
+	}
+
 	go startPolling(provider, provider.endpoints.PollInterval, logger)
 	return provider, nil
 }
@@ -50,7 +77,22 @@ func (p *ZeroProvider) Poll() error {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
 
-	timestamp := time.Now()
+	timestamp := p.now()
+
+	if p.path != nil {
+		tickers := p.path.At(p.start, timestamp.Sub(p.start))
+		for symbol := range p.pairs {
+			ticker, found := tickers[symbol]
+			if !found {
+				continue
+			}
+			// the path price is the current price
+			ticker.Time = timestamp
+			p.tickers[symbol] = ticker
+		}
+		p.setLastUpdate(timestamp)
+		return nil
+	}
 
 	for symbol := range p.pairs {
 		p.tickers[symbol] = types.TickerPrice{
@@ -59,10 +101,19 @@ func (p *ZeroProvider) Poll() error {
 			Time:   timestamp,
 		}
 	}
+	p.setLastUpdate(timestamp)
 
 	return nil
 }
 
 func (p *ZeroProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	return nil, nil
+	if p.path == nil {
+		return nil, nil
+	}
+
+	symbols := map[string]struct{}{}
+	for _, symbol := range p.path.Symbols() {
+		symbols[symbol] = struct{}{}
+	}
+	return symbols, nil
 }
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPricePath(t *testing.T) {
+	path, err := ReadPricePath(strings.NewReader(
+		"offset,symbol,price,volume\n30,atomusd,11\n0,ATOMUSD,10,5\n",
+	))
+	require.NoError(t, err)
+	require.Equal(t, []string{"ATOMUSD"}, path.Symbols())
+
+	start := time.Unix(1000, 0)
+	tickers := path.Tickers("ATOMUSD", start)
+	require.Len(t, tickers, 2)
+	require.Equal(t, sdk.NewDec(10), tickers[0].Price)
+	require.Equal(t, sdk.NewDec(5), tickers[0].Volume)
+	require.Equal(t, start, tickers[0].Time)
+	require.Equal(t, sdk.NewDec(11), tickers[1].Price)
+	require.Equal(t, sdk.OneDec(), tickers[1].Volume)
+	require.Equal(t, start.Add(30*time.Second), tickers[1].Time)
+
+	_, err = ReadPricePath(strings.NewReader("0,ATOMUSD,abc\n"))
+	require.Error(t, err)
+
+	_, err = ReadPricePath(strings.NewReader("-1,ATOMUSD,1\n"))
+	require.Error(t, err)
+}
+
+func TestZeroProviderPricePath(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pair := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+
+	path, err := LoadPricePath("testdata/pricepath.csv")
+	require.NoError(t, err)
+
+	// no polling routine, the clock is controlled by the test
+	p := &ZeroProvider{path: &path}
+	p.Init(
+		ctx,
+		Endpoint{Name: ProviderZero, Urls: []string{"testdata/pricepath.csv"}},
+		zerolog.Nop(),
+		[]types.CurrencyPair{pair},
+		nil,
+		nil,
+	)
+	availablePairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	p.setPairs([]types.CurrencyPair{pair}, availablePairs, nil)
+
+	start := time.Now()
+	p.start = start
+
+	for _, tc := range []struct {
+		offset time.Duration
+		price  sdk.Dec
+	}{
+		{0, sdk.NewDec(10)},
+		{59 * time.Second, sdk.NewDec(10)},
+		{120 * time.Second, sdk.NewDec(11)},
+		{250 * time.Second, sdk.NewDec(12)},
+		// the last price is kept after the end of the path
+		{time.Hour, sdk.NewDec(10)},
+	} {
+		now := start.Add(tc.offset)
+		p.now = func() time.Time { return now }
+		require.NoError(t, p.Poll())
+
+		p.mtx.RLock()
+		ticker := p.tickers[pair.String()]
+		p.mtx.RUnlock()
+
+		require.Equal(t, tc.price, ticker.Price, tc.offset)
+		require.Equal(t, now, ticker.Time)
+	}
+
+	require.Contains(t, availablePairs, "KUJIUSD")
+}
@@ -0,0 +1,95 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+)
+
+const queryTimeout = 15 * time.Second
+
+// GetMissCounter returns the current miss counter of the validator.
+func (o *Oracle) GetMissCounter(ctx context.Context) (uint64, error) {
+	grpcConn, err := o.dialGRPC()
+	if err != nil {
+		return 0, err
+	}
+	defer grpcConn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	missCounter, err := o.chainProfile.MissCounter(
+		ctx, grpcConn, o.oracleClient.ValidatorAddrString,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get miss counter: %w", err)
+	}
+
+	return missCounter, nil
+}
+
+// GetBalance returns the balance of the feeder account, used to pay the
+// vote fees.
+func (o *Oracle) GetBalance(ctx context.Context) (sdk.Coins, error) {
+	grpcConn, err := o.dialGRPC()
+	if err != nil {
+		return nil, err
+	}
+	defer grpcConn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryClient := banktypes.NewQueryClient(grpcConn)
+	queryResponse, err := queryClient.AllBalances(
+		ctx,
+		&banktypes.QueryAllBalancesRequest{
+			Address: o.oracleClient.OracleAddrString,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	return queryResponse.Balances, nil
+}
+
+// reportBalance exports the balance of the feeder account as
+// `price_feeder_account_balance{denom="x"}`, so operators can alert before
+// the vote fees can't be paid anymore.
+func (o *Oracle) reportBalance(ctx context.Context) {
+	balance, err := o.GetBalance(ctx)
+	if err != nil {
+		o.logger.Debug().Err(err).Msg("failed to report balance")
+		return
+	}
+
+	for _, coin := range balance {
+		telemetry.SetGaugeWithLabels(
+			[]string{"account", "balance"},
+			float32(sdk.NewDecFromInt(coin.Amount).MustFloat64()),
+			[]metrics.Label{telemetry.NewLabel("denom", coin.Denom)},
+		)
+	}
+}
+
+func (o *Oracle) dialGRPC() (*grpc.ClientConn, error) {
+	grpcConn, err := grpc.Dial(
+		o.oracleClient.GRPC.Active(),
+		// the Cosmos SDK doesn't support any transport security mechanism
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(dialerFunc),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial Cosmos gRPC service: %w", err)
+	}
+
+	return grpcConn, nil
+}
@@ -0,0 +1,155 @@
+package oracle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/config"
+)
+
+const (
+	// RatesOrderDenom sorts the exchange rates alphabetically by denom.
+	RatesOrderDenom = "denom"
+	// RatesOrderWhitelist sorts the exchange rates in the order of the
+	// oracle whitelist, followed by all other denoms sorted alphabetically.
+	RatesOrderWhitelist = "whitelist"
+
+	// RatesCaseUpper converts all denoms to upper case.
+	RatesCaseUpper = "upper"
+	// RatesCaseLower converts all denoms to lower case.
+	RatesCaseLower = "lower"
+)
+
+// RatesFormat defines how the exchange rates string of a vote is encoded.
+// Some forks of the oracle module require a specific ordering or denom
+// format, the default matches the Kujira oracle module.
+type RatesFormat struct {
+	Order       string
+	Case        string
+	Separator   string
+	DenomPrefix string
+	DenomSuffix string
+}
+
+// DefaultRatesFormat is the canonical format of the Kujira oracle module.
+var DefaultRatesFormat = RatesFormat{
+	Order:     RatesOrderDenom,
+	Case:      RatesCaseUpper,
+	Separator: ",",
+}
+
+// RatesFormatProfiles holds the rates formats of the supported chains.
+var RatesFormatProfiles = map[string]RatesFormat{
+	"kujira": DefaultRatesFormat,
+	"umee":   DefaultRatesFormat,
+	"sei": {
+		Order:       RatesOrderDenom,
+		Case:        RatesCaseLower,
+		Separator:   ",",
+		DenomPrefix: "u",
+	},
+}
+
+// NewRatesFormat returns the rates format of the configured profile, or
+// the format of the chain profile, with all explicitly configured options
+// overriding the profile.
+func NewRatesFormat(chainFormat RatesFormat, cfg config.RatesFormat) (RatesFormat, error) {
+	format := chainFormat
+	if cfg.Profile != "" {
+		profile, found := RatesFormatProfiles[cfg.Profile]
+		if !found {
+			return format, fmt.Errorf("unknown rates format profile: %s", cfg.Profile)
+		}
+		format = profile
+	}
+
+	if cfg.Order != "" {
+		format.Order = cfg.Order
+	}
+	if cfg.Case != "" {
+		format.Case = cfg.Case
+	}
+	if cfg.Separator != "" {
+		format.Separator = cfg.Separator
+	}
+	if cfg.DenomPrefix != "" {
+		format.DenomPrefix = cfg.DenomPrefix
+	}
+	if cfg.DenomSuffix != "" {
+		format.DenomSuffix = cfg.DenomSuffix
+	}
+
+	switch format.Order {
+	case RatesOrderDenom, RatesOrderWhitelist:
+	default:
+		return format, fmt.Errorf("unknown rates order: %s", format.Order)
+	}
+
+	switch format.Case {
+	case RatesCaseUpper, RatesCaseLower:
+	default:
+		return format, fmt.Errorf("unknown rates case: %s", format.Case)
+	}
+
+	return format, nil
+}
+
+// denom returns the denom as expected by the chain.
+func (f RatesFormat) denom(denom string) string {
+	if f.Case == RatesCaseLower {
+		denom = strings.ToLower(denom)
+	} else {
+		denom = strings.ToUpper(denom)
+	}
+	return f.DenomPrefix + denom + f.DenomSuffix
+}
+
+// Format generates the exchange rates string of the prices. The whitelist
+// is only used to order the rates, if configured.
+func (f RatesFormat) Format(prices sdk.DecCoins, whitelist oracletypes.DenomList) string {
+	rank := make(map[string]int, len(whitelist))
+	if f.Order == RatesOrderWhitelist {
+		for i, denom := range whitelist {
+			symbol := strings.ToUpper(denom.Name)
+			if _, found := rank[symbol]; !found {
+				rank[symbol] = i
+			}
+		}
+	}
+
+	sorted := make(sdk.DecCoins, len(prices))
+	copy(sorted, prices)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		rankI, foundI := rank[strings.ToUpper(sorted[i].Denom)]
+		rankJ, foundJ := rank[strings.ToUpper(sorted[j].Denom)]
+		switch {
+		case foundI && foundJ:
+			return rankI < rankJ
+		case foundI != foundJ:
+			return foundI
+		default:
+			return sorted[i].Denom < sorted[j].Denom
+		}
+	})
+
+	rates := make([]string, len(sorted))
+	for i, price := range sorted {
+		rates[i] = price.Amount.String() + f.denom(price.Denom)
+	}
+
+	return strings.Join(rates, f.Separator)
+}
+
+// SetRatesFormat sets the format of the exchange rates string of the votes.
+func (o *Oracle) SetRatesFormat(format RatesFormat) {
+	o.ratesFormat = format
+}
+
+// SetPriceExponents sets the exponents the voted prices are scaled by.
+func (o *Oracle) SetPriceExponents(exponents map[string]int) {
+	o.priceExponents = exponents
+}
@@ -0,0 +1,49 @@
+package oracle
+
+import (
+	"testing"
+
+	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+)
+
+func TestRatesFormat(t *testing.T) {
+	prices := sdk.DecCoins{
+		sdk.NewDecCoinFromDec("UMEE", sdk.MustNewDecFromStr("3.72")),
+		sdk.NewDecCoinFromDec("ATOM", sdk.MustNewDecFromStr("40.13")),
+		sdk.NewDecCoinFromDec("OSMO", sdk.MustNewDecFromStr("8.69")),
+	}
+	whitelist := oracletypes.DenomList{{Name: "osmo"}, {Name: "umee"}}
+
+	// the default format matches the canonical string
+	require.Equal(
+		t,
+		GenerateExchangeRatesString(sdk.NewDecCoins(prices...)),
+		DefaultRatesFormat.Format(prices, whitelist),
+	)
+
+	format, err := NewRatesFormat(DefaultRatesFormat, config.RatesFormat{Order: RatesOrderWhitelist})
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		"8.690000000000000000OSMO,3.720000000000000000UMEE,40.130000000000000000ATOM",
+		format.Format(prices, whitelist),
+	)
+
+	format, err = NewRatesFormat(DefaultRatesFormat, config.RatesFormat{Profile: "sei", Separator: ";"})
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		"40.130000000000000000uatom;8.690000000000000000uosmo;3.720000000000000000uumee",
+		format.Format(prices, nil),
+	)
+
+	_, err = NewRatesFormat(DefaultRatesFormat, config.RatesFormat{Profile: "unknown"})
+	require.Error(t, err)
+
+	_, err = NewRatesFormat(DefaultRatesFormat, config.RatesFormat{Case: "camel"})
+	require.Error(t, err)
+}
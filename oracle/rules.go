@@ -0,0 +1,273 @@
+package oracle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/config"
+	"price-feeder/pkg/events"
+)
+
+type (
+	// SanityRule defines a parsed custom validation rule of the computed
+	// prices, see ParseSanityRule.
+	SanityRule struct {
+		Name string
+		// Base is the denom of the subject, Quote is empty for its USD
+		// price.
+		Base  string
+		Quote string
+		// Deviation compares the relative change to the last voted price
+		// in percent instead of the price itself.
+		Deviation bool
+		// Block removes the base denom from the vote, if the rule is
+		// violated.
+		Block bool
+
+		op     string
+		limits []sdk.Dec
+	}
+
+	// SanityRuleViolation defines a computed price violating a sanity rule.
+	SanityRuleViolation struct {
+		Rule    string  `json:"rule"`
+		Denom   string  `json:"denom"`
+		Value   sdk.Dec `json:"value"`
+		Blocked bool    `json:"blocked"`
+	}
+)
+
+// ParseSanityRules parses the configured sanity rules.
+func ParseSanityRules(cfgs []config.SanityRule) ([]SanityRule, error) {
+	rules := make([]SanityRule, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		rule, err := ParseSanityRule(cfg.Rule)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Name != "" {
+			rule.Name = cfg.Name
+		}
+		rule.Block = cfg.Block
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ParseSanityRule parses a rule of the form
+//
+//	<subject> [deviation] <condition>
+//
+// The subject is the USD price of a denom, e.g. "BTC", or the ratio of two
+// prices, e.g. "STATOM/ATOM". With "deviation", the condition applies to
+// the change to the last voted price in percent. The condition is either
+// "between <min> and <max>" or a comparison with "<", "<=", ">" or ">=",
+// e.g.:
+//
+//	STATOM/ATOM between 1.0 and 2.0
+//	BTC deviation < 10%
+func ParseSanityRule(expr string) (SanityRule, error) {
+	rule := SanityRule{Name: expr}
+	fields := strings.Fields(expr)
+	if len(fields) < 3 {
+		return rule, fmt.Errorf("invalid sanity rule %q: missing condition", expr)
+	}
+
+	subject := strings.Split(strings.ToUpper(fields[0]), "/")
+	if len(subject) > 2 || subject[0] == "" || (len(subject) == 2 && subject[1] == "") {
+		return rule, fmt.Errorf("invalid sanity rule %q: invalid subject %s", expr, fields[0])
+	}
+	rule.Base = subject[0]
+	if len(subject) == 2 {
+		rule.Quote = subject[1]
+	}
+
+	fields = fields[1:]
+	if strings.ToLower(fields[0]) == "deviation" {
+		rule.Deviation = true
+		fields = fields[1:]
+	}
+
+	var values []string
+	switch {
+	case len(fields) == 4 && strings.ToLower(fields[0]) == "between" &&
+		strings.ToLower(fields[2]) == "and":
+		rule.op = "between"
+		values = []string{fields[1], fields[3]}
+	case len(fields) == 2 && isComparison(fields[0]):
+		rule.op = fields[0]
+		values = []string{fields[1]}
+	default:
+		return rule, fmt.Errorf("invalid sanity rule %q: invalid condition", expr)
+	}
+
+	for _, value := range values {
+		if strings.HasSuffix(value, "%") {
+			if !rule.Deviation {
+				return rule, fmt.Errorf("invalid sanity rule %q: percentages require deviation", expr)
+			}
+			value = strings.TrimSuffix(value, "%")
+		}
+		limit, err := sdk.NewDecFromStr(value)
+		if err != nil {
+			return rule, fmt.Errorf("invalid sanity rule %q: %w", expr, err)
+		}
+		rule.limits = append(rule.limits, limit)
+	}
+
+	if rule.op == "between" && rule.limits[0].GT(rule.limits[1]) {
+		return rule, fmt.Errorf("invalid sanity rule %q: min above max", expr)
+	}
+
+	return rule, nil
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+// holds returns true if the value satisfies the condition of the rule.
+func (r SanityRule) holds(value sdk.Dec) bool {
+	switch r.op {
+	case "<":
+		return value.LT(r.limits[0])
+	case "<=":
+		return value.LTE(r.limits[0])
+	case ">":
+		return value.GT(r.limits[0])
+	case ">=":
+		return value.GTE(r.limits[0])
+	default:
+		return value.GTE(r.limits[0]) && value.LTE(r.limits[1])
+	}
+}
+
+// subject returns the price or ratio the rule applies to. It returns false,
+// if a price is missing.
+func (r SanityRule) subject(price func(denom string) (sdk.Dec, bool)) (sdk.Dec, bool) {
+	base, found := price(r.Base)
+	if !found {
+		return sdk.Dec{}, false
+	}
+	if r.Quote == "" {
+		return base, true
+	}
+
+	quote, found := price(r.Quote)
+	if !found || !quote.IsPositive() {
+		return sdk.Dec{}, false
+	}
+	return base.Quo(quote), true
+}
+
+// evaluate returns the value the condition is checked against and whether
+// the rule is violated. Rules lacking a price, or a voted price to compare
+// the deviation to, are skipped.
+func (r SanityRule) evaluate(
+	prices map[string]sdk.Dec,
+	voted func(denom string) (sdk.Dec, bool),
+) (sdk.Dec, bool) {
+	value, ok := r.subject(func(denom string) (sdk.Dec, bool) {
+		price, found := prices[denom]
+		return price, found
+	})
+	if !ok {
+		return sdk.Dec{}, false
+	}
+
+	if r.Deviation {
+		reference, ok := r.subject(voted)
+		if !ok || !reference.IsPositive() {
+			return sdk.Dec{}, false
+		}
+		value = value.Quo(reference).Sub(sdk.OneDec()).Abs().MulInt64(100)
+	}
+
+	return value, !r.holds(value)
+}
+
+// SanityRules returns a middleware for StageValidate, checking the computed
+// prices against the rules. All rules are evaluated before any price is
+// removed, so a blocked denom doesn't skip the rules of others. Violations
+// are reported to the callback, blocking rules remove their base denom.
+func SanityRules(
+	rules []SanityRule,
+	voted func(denom string) (sdk.Dec, bool),
+	report func(SanityRuleViolation),
+) Middleware {
+	return func(next StageFunc) StageFunc {
+		return func(state *PipelineState) error {
+			if err := next(state); err != nil {
+				return err
+			}
+
+			blocked := map[string]struct{}{}
+			for _, rule := range rules {
+				value, violated := rule.evaluate(state.Prices, voted)
+				if !violated {
+					continue
+				}
+
+				if rule.Block {
+					blocked[rule.Base] = struct{}{}
+				}
+				report(SanityRuleViolation{
+					Rule:    rule.Name,
+					Denom:   rule.Base,
+					Value:   value,
+					Blocked: rule.Block,
+				})
+			}
+
+			for denom := range blocked {
+				state.excludeRates(denom, state.Contributors[denom], "sanity rule violated")
+				delete(state.Prices, denom)
+				delete(state.Contributors, denom)
+			}
+
+			return nil
+		}
+	}
+}
+
+// SetSanityRules registers the sanity rules in the pipeline of the oracle.
+// Deviations are relative to the prices of the last committed prevote.
+func (o *Oracle) SetSanityRules(rules []SanityRule) error {
+	return o.Pipeline().Use(StageValidate, SanityRules(rules, o.votedPrice, func(v SanityRuleViolation) {
+		o.logger.Error().
+			Str("rule", v.Rule).
+			Str("denom", v.Denom).
+			Str("value", v.Value.String()).
+			Bool("blocked", v.Blocked).
+			Msg("sanity rule violated")
+
+		telemetry.IncrCounterWithLabels(
+			[]string{"price", "rule_violation"},
+			1,
+			[]metrics.Label{telemetry.NewLabel("denom", v.Denom)},
+		)
+		o.publish(
+			events.TopicSanityRuleViolated,
+			fmt.Sprintf("sanity rule %q violated by %s", v.Rule, v.Denom),
+			v,
+		)
+	}))
+}
+
+// votedPrice returns the USD price of the denom of the last committed
+// prevote.
+func (o *Oracle) votedPrice(denom string) (sdk.Dec, bool) {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	price, found := o.votedPrices[denom]
+	return price, found
+}
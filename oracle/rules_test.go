@@ -0,0 +1,94 @@
+package oracle
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+)
+
+func TestParseSanityRule(t *testing.T) {
+	rule, err := ParseSanityRule("statom/ATOM between 1.0 and 2.0")
+	require.NoError(t, err)
+	require.Equal(t, "STATOM", rule.Base)
+	require.Equal(t, "ATOM", rule.Quote)
+	require.False(t, rule.Deviation)
+	require.True(t, rule.holds(sdk.MustNewDecFromStr("1.5")))
+	require.False(t, rule.holds(sdk.MustNewDecFromStr("2.1")))
+
+	rule, err = ParseSanityRule("BTC deviation < 10%")
+	require.NoError(t, err)
+	require.Equal(t, "BTC", rule.Base)
+	require.Empty(t, rule.Quote)
+	require.True(t, rule.Deviation)
+	require.True(t, rule.holds(sdk.NewDec(9)))
+	require.False(t, rule.holds(sdk.NewDec(10)))
+
+	for _, expr := range []string{
+		"BTC",
+		"BTC/ < 1",
+		"BTC == 1",
+		"BTC < 10%",
+		"BTC between 2 and 1",
+		"BTC between 1 or 2",
+		"BTC > one",
+	} {
+		_, err := ParseSanityRule(expr)
+		require.Error(t, err, expr)
+	}
+}
+
+func TestSanityRules(t *testing.T) {
+	providerPrices, providerPairs, minOverrides := testPipelineInputs()
+
+	rules, err := ParseSanityRules([]config.SanityRule{
+		{Rule: "KUJI between 1 and 2"},
+		{Name: "kuji peg", Rule: "KUJI > 2"},
+		{Rule: "KUJI deviation < 10%", Block: true},
+	})
+	require.NoError(t, err)
+
+	reported := []SanityRuleViolation{}
+	report := func(v SanityRuleViolation) {
+		reported = append(reported, v)
+	}
+
+	// without a voted price, the deviation rule is skipped
+	voted := map[string]sdk.Dec{}
+	lookup := func(denom string) (sdk.Dec, bool) {
+		price, found := voted[denom]
+		return price, found
+	}
+
+	pipeline := NewPipeline()
+	require.NoError(t, pipeline.Use(StageValidate, SanityRules(rules, lookup, report)))
+
+	state, err := runPipeline(
+		pipeline, zerolog.Nop(), providerPrices, providerPairs,
+		nil, minOverrides, nil,
+	)
+	require.NoError(t, err)
+	require.Contains(t, state.Prices, "KUJI")
+	require.Len(t, reported, 1)
+	require.Equal(t, "kuji peg", reported[0].Rule)
+	require.False(t, reported[0].Blocked)
+
+	// a deviation of 50% blocks the vote of KUJI
+	reported = reported[:0]
+	voted["KUJI"] = sdk.OneDec()
+
+	state, err = runPipeline(
+		pipeline, zerolog.Nop(), providerPrices, providerPairs,
+		nil, minOverrides, nil,
+	)
+	require.NoError(t, err)
+	require.NotContains(t, state.Prices, "KUJI")
+	require.Len(t, state.Excluded["KUJI"], 2)
+	require.Len(t, reported, 2)
+	require.Equal(t, "KUJI deviation < 10%", reported[1].Rule)
+	require.True(t, sdk.NewDec(50).Equal(reported[1].Value))
+	require.True(t, reported[1].Blocked)
+}
@@ -0,0 +1,61 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/pkg/events"
+)
+
+// PriceSource defines a source of already aggregated prices, e.g. a
+// collector process, which replaces the providers of a voter.
+type PriceSource interface {
+	Prices(ctx context.Context) (map[string]sdk.Dec, error)
+}
+
+// SetPriceSource makes the oracle vote the prices of the source instead of
+// running its own providers.
+func (o *Oracle) SetPriceSource(source PriceSource) {
+	o.priceSource = source
+}
+
+// setSourcePrices replaces the current prices with the prices of the price
+// source.
+func (o *Oracle) setSourcePrices(ctx context.Context, deadline time.Time) error {
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	prices, err := o.priceSource.Prices(ctx)
+	if err != nil {
+		o.publish(events.TopicProviderFailed, "price source failed: "+err.Error(), err)
+		return fmt.Errorf("failed to get prices from source: %w", err)
+	}
+
+	computedPrices := make(map[string]sdk.Dec, len(prices))
+	for denom, price := range prices {
+		if price.IsNil() || !price.IsPositive() {
+			continue
+		}
+		computedPrices[strings.ToUpper(denom)] = price
+	}
+
+	o.mtx.Lock()
+	o.prices = computedPrices
+	o.lastPricesTS = time.Now()
+	o.mtx.Unlock()
+
+	o.publish(events.TopicPricesUpdated, "", computedPrices)
+
+	if err := o.history.AddComputedPrices(computedPrices, time.Now()); err != nil {
+		o.logger.Warn().Err(err).Msg("failed to add computed prices to history")
+	}
+
+	return nil
+}
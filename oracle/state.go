@@ -0,0 +1,135 @@
+package oracle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// State defines the voting state of the feeder, which is needed to reveal
+// the last prevote after a restart or on another machine. It contains the
+// salt of the prevote and must be kept private.
+type State struct {
+	Time               time.Time          `json:"time"`
+	PreviousVotePeriod float64            `json:"previous_vote_period"`
+	PreviousPrevote    *PreviousPrevote   `json:"previous_prevote"`
+	Sequence           uint64             `json:"sequence"`
+	Prices             map[string]sdk.Dec `json:"prices"`
+	PricesTime         time.Time          `json:"prices_time"`
+}
+
+// ReadState reads the state from the file.
+func ReadState(path string) (State, error) {
+	var state State
+
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+
+	err = json.Unmarshal(bz, &state)
+	return state, err
+}
+
+// WriteState atomically replaces the file with the state.
+func WriteState(path string, state State) error {
+	bz, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bz); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// SetStateFile enables persisting the voting state to the file.
+func (o *Oracle) SetStateFile(path string) {
+	o.stateFile = path
+}
+
+// saveState writes the current voting state to the state file, if
+// configured.
+func (o *Oracle) saveState() {
+	if o.stateFile == "" {
+		return
+	}
+
+	state := State{
+		Time:               time.Now(),
+		PreviousVotePeriod: o.previousVotePeriod,
+		PreviousPrevote:    o.previousPrevote,
+	}
+
+	o.mtx.RLock()
+	state.Prices = o.prices
+	state.PricesTime = o.lastPricesTS
+	o.mtx.RUnlock()
+
+	sequence, err := o.oracleClient.GetAccountSequence()
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("failed to get account sequence for state")
+	}
+	state.Sequence = sequence
+
+	if err := WriteState(o.stateFile, state); err != nil {
+		o.logger.Error().Err(err).Str("path", o.stateFile).Msg("failed to write state")
+	}
+}
+
+// loadState restores the voting state from the state file, if configured
+// and present. Outdated prevotes are discarded by the next tick.
+func (o *Oracle) loadState() {
+	if o.stateFile == "" {
+		return
+	}
+
+	state, err := ReadState(o.stateFile)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		o.logger.Error().Err(err).Str("path", o.stateFile).Msg("failed to read state")
+		return
+	}
+
+	o.mtx.Lock()
+	o.previousVotePeriod = state.PreviousVotePeriod
+	o.previousPrevote = state.PreviousPrevote
+	if len(state.Prices) > 0 {
+		o.prices = state.Prices
+		o.lastPricesTS = state.PricesTime
+	}
+	o.mtx.Unlock()
+
+	logger := o.logger.With().
+		Time("time", state.Time).
+		Float64("previous_vote_period", state.PreviousVotePeriod).
+		Bool("prevote", state.PreviousPrevote != nil).
+		Logger()
+
+	sequence, err := o.oracleClient.GetAccountSequence()
+	if err == nil && sequence < state.Sequence {
+		logger.Warn().
+			Uint64("sequence", sequence).
+			Uint64("state_sequence", state.Sequence).
+			Msg("transactions of the previous instance are still pending")
+	}
+
+	logger.Info().Msg("restored state")
+}
@@ -0,0 +1,45 @@
+package oracle
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	_, err := ReadState(path)
+	require.Error(t, err)
+
+	state := State{
+		Time:               time.Unix(1700000000, 0).UTC(),
+		PreviousVotePeriod: 1234,
+		PreviousPrevote: &PreviousPrevote{
+			ExchangeRates:     "1.5KUJI",
+			Salt:              "abcd",
+			SubmitBlockHeight: 17276,
+		},
+		Sequence: 42,
+		Prices:   map[string]sdk.Dec{"KUJI": sdk.MustNewDecFromStr("1.5")},
+	}
+	require.NoError(t, WriteState(path, state))
+
+	// replaced atomically
+	state.Sequence = 43
+	require.NoError(t, WriteState(path, state))
+
+	restored, err := ReadState(path)
+	require.NoError(t, err)
+	require.Equal(t, state.PreviousPrevote, restored.PreviousPrevote)
+	require.Equal(t, state.PreviousVotePeriod, restored.PreviousVotePeriod)
+	require.Equal(t, uint64(43), restored.Sequence)
+	require.True(t, state.Prices["KUJI"].Equal(restored.Prices["KUJI"]))
+
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+}
@@ -0,0 +1,244 @@
+package oracle
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"price-feeder/config"
+	"price-feeder/oracle/history"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultAutoThresholdsWindow     = 30 * 24 * time.Hour
+	defaultAutoThresholdsInterval   = time.Hour
+	defaultAutoThresholdsMinSamples = 100
+)
+
+var (
+	defaultAutoThresholdsMultiplier = sdk.NewDec(3)
+
+	// maxAutoThreshold matches the maximum deviation threshold accepted in
+	// the config.
+	maxAutoThreshold = sdk.MustNewDecFromStr("3.0")
+)
+
+type (
+	// ThresholdSuggestion defines a deviation threshold derived from the
+	// historical dispersion of the provider prices of a denom. The threshold
+	// is the mean of the largest provider score per tick plus multiplier
+	// times its standard deviation, limited to [1, 3].
+	ThresholdSuggestion struct {
+		Denom     string  `json:"denom"`
+		Samples   int     `json:"samples"`
+		MeanScore float64 `json:"mean_score"`
+		StdScore  float64 `json:"std_score"`
+		Relative  float64 `json:"relative_dispersion"`
+		Threshold sdk.Dec `json:"threshold"`
+	}
+
+	// AutoThresholds periodically derives the deviation thresholds of all
+	// denoms without a configured threshold from the price history.
+	AutoThresholds struct {
+		logger     zerolog.Logger
+		window     time.Duration
+		interval   time.Duration
+		multiplier sdk.Dec
+		minSamples int
+
+		updated    time.Time
+		thresholds map[string]sdk.Dec
+	}
+)
+
+// NewAutoThresholds returns the runtime thresholds of the config, or nil if
+// they are disabled.
+func NewAutoThresholds(
+	logger zerolog.Logger,
+	cfg config.AutoThresholds,
+) *AutoThresholds {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	a := &AutoThresholds{
+		logger:     logger.With().Str("module", "thresholds").Logger(),
+		window:     defaultAutoThresholdsWindow,
+		interval:   defaultAutoThresholdsInterval,
+		multiplier: defaultAutoThresholdsMultiplier,
+		minSamples: defaultAutoThresholdsMinSamples,
+		thresholds: map[string]sdk.Dec{},
+	}
+
+	// values are validated when parsing the config
+	if window, err := time.ParseDuration(cfg.Window); err == nil {
+		a.window = window
+	}
+	if interval, err := time.ParseDuration(cfg.Interval); err == nil {
+		a.interval = interval
+	}
+	if multiplier, err := sdk.NewDecFromStr(cfg.Multiplier); err == nil {
+		a.multiplier = multiplier
+	}
+	if cfg.MinSamples > 0 {
+		a.minSamples = cfg.MinSamples
+	}
+
+	return a
+}
+
+// SetAutoThresholds enables deriving the deviation thresholds of denoms
+// without configured threshold from the price history, if not nil.
+func (o *Oracle) SetAutoThresholds(autoThresholds *AutoThresholds) {
+	o.autoThresholds = autoThresholds
+}
+
+// update recomputes the thresholds from the price history, at most once per
+// interval.
+func (a *AutoThresholds) update(h *history.PriceHistory, now time.Time) {
+	if a == nil || now.Sub(a.updated) < a.interval {
+		return
+	}
+	a.updated = now
+
+	dispersions, err := h.Dispersions(now.Add(-a.window), now)
+	if err != nil {
+		a.logger.Warn().Err(err).Msg("failed to query price dispersion")
+		return
+	}
+
+	thresholds := map[string]sdk.Dec{}
+	for denom, suggestion := range SuggestThresholds(
+		dispersions, a.multiplier, a.minSamples,
+	) {
+		thresholds[denom] = suggestion.Threshold
+
+		if previous, found := a.thresholds[denom]; !found || !previous.Equal(suggestion.Threshold) {
+			a.logger.Info().
+				Str("denom", denom).
+				Str("threshold", suggestion.Threshold.String()).
+				Int("samples", suggestion.Samples).
+				Msg("updated deviation threshold")
+		}
+	}
+	a.thresholds = thresholds
+}
+
+// apply returns the configured thresholds, completed with the derived
+// thresholds of all other denoms.
+func (a *AutoThresholds) apply(configured map[string]sdk.Dec) map[string]sdk.Dec {
+	if a == nil || len(a.thresholds) == 0 {
+		return configured
+	}
+
+	thresholds := make(map[string]sdk.Dec, len(configured)+len(a.thresholds))
+	for denom, threshold := range a.thresholds {
+		thresholds[denom] = threshold
+	}
+	for denom, threshold := range configured {
+		thresholds[denom] = threshold
+	}
+
+	return thresholds
+}
+
+// SuggestThresholds derives a deviation threshold for every denom with at
+// least minSamples dispersion entries.
+func SuggestThresholds(
+	dispersions map[string][]history.Dispersion,
+	multiplier sdk.Dec,
+	minSamples int,
+) map[string]ThresholdSuggestion {
+	suggestions := map[string]ThresholdSuggestion{}
+
+	for denom, entries := range dispersions {
+		if len(entries) == 0 || len(entries) < minSamples {
+			continue
+		}
+
+		var scoreSum, relativeSum float64
+		for _, entry := range entries {
+			scoreSum += entry.MaxScore
+			relativeSum += entry.Relative
+		}
+		n := float64(len(entries))
+		mean := scoreSum / n
+
+		var variance float64
+		for _, entry := range entries {
+			variance += (entry.MaxScore - mean) * (entry.MaxScore - mean)
+		}
+		std := math.Sqrt(variance / n)
+
+		value := mean + multiplier.MustFloat64()*std
+		// round up to two decimals, ignoring floating point noise
+		threshold := sdk.NewDecWithPrec(int64(math.Ceil(value*100-1e-6)), 2)
+		if threshold.LT(defaultDeviationThreshold) {
+			threshold = defaultDeviationThreshold
+		}
+		if threshold.GT(maxAutoThreshold) {
+			threshold = maxAutoThreshold
+		}
+
+		suggestions[denom] = ThresholdSuggestion{
+			Denom:     denom,
+			Samples:   len(entries),
+			MeanScore: mean,
+			StdScore:  std,
+			Relative:  relativeSum / n,
+			Threshold: threshold,
+		}
+	}
+
+	return suggestions
+}
+
+// SortedThresholdSuggestions returns the suggestions sorted by denom.
+func SortedThresholdSuggestions(
+	suggestions map[string]ThresholdSuggestion,
+) []ThresholdSuggestion {
+	sorted := make([]ThresholdSuggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		sorted = append(sorted, suggestion)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Denom < sorted[j].Denom
+	})
+	return sorted
+}
+
+// priceDispersions returns the dispersion of the USD rates of all denoms
+// with at least three rates.
+func priceDispersions(state *PipelineState) map[string]history.Dispersion {
+	dispersions := map[string]history.Dispersion{}
+
+	for denom, rates := range state.USDRates {
+		prices := make([]sdk.Dec, 0, len(rates))
+		for _, rate := range rates {
+			prices = append(prices, rate.Price)
+		}
+
+		deviation, mean, err := StandardDeviation(prices)
+		if err != nil || !mean.IsPositive() || !deviation.IsPositive() {
+			continue
+		}
+
+		maxScore := sdk.ZeroDec()
+		for _, price := range prices {
+			score := price.Sub(mean).Abs().Quo(deviation)
+			if score.GT(maxScore) {
+				maxScore = score
+			}
+		}
+
+		dispersions[denom] = history.Dispersion{
+			Relative: deviation.Quo(mean).MustFloat64(),
+			MaxScore: maxScore.MustFloat64(),
+		}
+	}
+
+	return dispersions
+}
@@ -0,0 +1,96 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"price-feeder/config"
+	"price-feeder/oracle/history"
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestThresholds(t *testing.T) {
+	dispersions := map[string][]history.Dispersion{
+		"ATOM": {
+			{MaxScore: 1.2, Relative: 0.001},
+			{MaxScore: 1.4, Relative: 0.003},
+		},
+		// clamped to the maximum
+		"KUJI": {
+			{MaxScore: 1},
+			{MaxScore: 2},
+		},
+		// clamped to the minimum
+		"USDC": {
+			{MaxScore: 0.5},
+			{MaxScore: 0.5},
+		},
+		// not enough samples
+		"FOO": {
+			{MaxScore: 1},
+		},
+	}
+
+	suggestions := SuggestThresholds(dispersions, sdk.NewDec(3), 2)
+	require.Len(t, suggestions, 3)
+
+	// 1.3 + 3 * 0.1
+	require.Equal(t, sdk.MustNewDecFromStr("1.6"), suggestions["ATOM"].Threshold)
+	require.InDelta(t, 0.002, suggestions["ATOM"].Relative, 1e-9)
+	require.Equal(t, maxAutoThreshold, suggestions["KUJI"].Threshold)
+	require.Equal(t, defaultDeviationThreshold, suggestions["USDC"].Threshold)
+
+	sorted := SortedThresholdSuggestions(suggestions)
+	require.Equal(t, "ATOM", sorted[0].Denom)
+}
+
+func TestAutoThresholds(t *testing.T) {
+	h, err := history.NewPriceHistory(":memory:", zerolog.Nop())
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+
+	state := &PipelineState{
+		USDRates: map[string]map[provider.Name]types.TickerPrice{
+			"ATOM": {
+				provider.ProviderBinance: {Price: sdk.NewDec(9)},
+				provider.ProviderKraken:  {Price: sdk.NewDec(10)},
+				provider.ProviderOkx:     {Price: sdk.NewDec(11)},
+			},
+			"KUJI": {
+				provider.ProviderBinance: {Price: sdk.NewDec(1)},
+			},
+		},
+	}
+	dispersions := priceDispersions(state)
+	require.Len(t, dispersions, 1)
+	require.InDelta(t, 1.2247, dispersions["ATOM"].MaxScore, 1e-4)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, h.AddDispersions(
+			dispersions, now.Add(time.Duration(i-3)*time.Minute),
+		))
+	}
+
+	require.Nil(t, NewAutoThresholds(zerolog.Nop(), config.AutoThresholds{}))
+
+	a := NewAutoThresholds(zerolog.Nop(), config.AutoThresholds{
+		Enabled:    true,
+		MinSamples: 3,
+	})
+	a.update(&h, now)
+
+	configured := map[string]sdk.Dec{"KUJI": sdk.NewDec(2)}
+	thresholds := a.apply(configured)
+	require.Equal(t, sdk.MustNewDecFromStr("1.23"), thresholds["ATOM"])
+	require.Equal(t, sdk.NewDec(2), thresholds["KUJI"])
+
+	// configured thresholds take precedence
+	thresholds = a.apply(map[string]sdk.Dec{"ATOM": sdk.NewDec(2)})
+	require.Equal(t, sdk.NewDec(2), thresholds["ATOM"])
+}
@@ -0,0 +1,26 @@
+package oracle
+
+import (
+	"time"
+
+	"price-feeder/oracle/types"
+)
+
+// SetTickerRetention enables persisting every ticker collected for a vote
+// in the history db, kept for the retention period. It allows to find out
+// why a vote deviated from the chain median after the fact.
+func (o *Oracle) SetTickerRetention(retention time.Duration) {
+	o.tickerRetention = retention
+}
+
+// persistTickers stores the collected tickers by provider and symbol, if
+// enabled.
+func (o *Oracle) persistTickers(tickers map[string]map[string]types.TickerPrice, now time.Time) {
+	if o.tickerRetention <= 0 || len(tickers) == 0 {
+		return
+	}
+
+	if err := o.history.AddProviderTickers(tickers, now, o.tickerRetention); err != nil {
+		o.logger.Warn().Err(err).Msg("failed to add provider tickers to history")
+	}
+}
@@ -0,0 +1,263 @@
+package oracle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	"github.com/rs/zerolog"
+
+	"price-feeder/config"
+)
+
+const (
+	// voteWindowBlocks defines the initial number of blocks at the end of
+	// each vote period, in which prices are fetched and the votes are
+	// broadcasted. It is tuned at runtime between min and max, based on the
+	// blocks remaining when the transactions are committed.
+	voteWindowBlocks    = 4
+	minVoteWindowBlocks = 2
+	maxVoteWindowBlocks = 10
+
+	// minVoteMargin defines the number of blocks that should remain in the
+	// vote period after a (pre)vote is committed.
+	minVoteMargin = 1
+
+	// voteMarginSamples defines the number of commits with a comfortable
+	// margin, before the vote window is shortened again.
+	voteMarginSamples = 10
+
+	// blockTimeSamples defines the number of blocks that need to be observed,
+	// before the block time estimation is used.
+	blockTimeSamples = 10
+)
+
+// timing defines the intervals of the oracle loop.
+type timing struct {
+	tick        time.Duration
+	prices      time.Duration
+	healthcheck time.Duration
+	deadline    bool
+	clamped     bool
+
+	// prevoteTimeout and voteTimeout limit the blocks a (pre)vote is
+	// retried, if set.
+	prevoteTimeout int64
+	voteTimeout    int64
+}
+
+// SetTiming sets the timing of the oracle loop, the stall watchdog, the
+// clock monitor and the warmup. The warmup starts over.
+func (o *Oracle) SetTiming(cfg config.Timing) {
+	o.timing = newTiming(o.rootLogger, cfg)
+	o.watchdog = newHeightWatchdog(cfg.StallVotePeriods)
+	o.clock = newClockMonitor(o.rootLogger, cfg)
+	o.warmup = newWarmup(o.rootLogger, cfg, time.Now())
+}
+
+func newTiming(logger zerolog.Logger, cfg config.Timing) timing {
+	t := timing{
+		tick:           tickerSleep,
+		deadline:       cfg.DeadlineCollection,
+		prevoteTimeout: int64(cfg.PrevoteTimeoutBlocks),
+		voteTimeout:    int64(cfg.VoteTimeoutBlocks),
+	}
+
+	parse := func(name, value string) time.Duration {
+		if value == "" {
+			return 0
+		}
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			logger.Warn().
+				Str(name, value).
+				Msg("failed to parse interval, using default")
+			return 0
+		}
+		return interval
+	}
+
+	if tick := parse("tick_interval", cfg.TickInterval); tick > 0 {
+		t.tick = tick
+	}
+	t.prices = parse("price_interval", cfg.PriceInterval)
+	t.healthcheck = parse("healthcheck_interval", cfg.HealthcheckInterval)
+
+	return t
+}
+
+// blockTimer estimates the average block time from the observed chain
+// heights.
+type blockTimer struct {
+	height    int64
+	time      time.Time
+	blockTime time.Duration
+}
+
+// observe updates the estimated block time with the current chain height.
+func (b *blockTimer) observe(height int64, now time.Time) {
+	if b.height == 0 || height < b.height {
+		b.height = height
+		b.time = now
+		return
+	}
+
+	blocks := height - b.height
+	if blocks < blockTimeSamples {
+		return
+	}
+
+	b.blockTime = now.Sub(b.time) / time.Duration(blocks)
+	b.height = height
+	b.time = now
+}
+
+// maxTickInterval returns the longest possible tick interval, that still
+// guarantees at least two ticks in each vote window. It returns 0 if the
+// block time is not known yet.
+func (b *blockTimer) maxTickInterval() time.Duration {
+	return b.blockTime * voteWindowBlocks / 2
+}
+
+// tickInterval returns the configured tick interval, limited by the vote
+// window of the chain, so slow ticks can't skip a reveal.
+func (o *Oracle) tickInterval() time.Duration {
+	maxInterval := o.blockTimer.maxTickInterval()
+	if maxInterval == 0 || o.timing.tick <= maxInterval {
+		return o.timing.tick
+	}
+
+	if !o.timing.clamped {
+		o.logger.Warn().
+			Dur("tick_interval", o.timing.tick).
+			Dur("max_interval", maxInterval).
+			Msg("tick interval too long for vote period, using max interval")
+		o.timing.clamped = true
+	}
+
+	return maxInterval
+}
+
+// voteScheduler tunes the vote window based on how late in the vote period
+// the (pre)votes are committed. Commits with less than minVoteMargin blocks
+// left widen the window immediately, while consistently comfortable margins
+// shorten it again, so the votes use prices as fresh as possible.
+type voteScheduler struct {
+	// the commits are observed once the (pre)vote txs are included, so
+	// concurrently to the oracle loop
+	mtx     sync.Mutex
+	window  int64
+	margins []int64
+}
+
+func newVoteScheduler() voteScheduler {
+	return voteScheduler{window: voteWindowBlocks}
+}
+
+// windowBlocks returns the current vote window, at most half the vote period.
+func (s *voteScheduler) windowBlocks(votePeriod int64) int64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	window := s.window
+	if window == 0 {
+		window = voteWindowBlocks
+	}
+	if max := votePeriod / 2; max > 0 && window > max {
+		return max
+	}
+	return window
+}
+
+// observe records the number of blocks left in the vote period after the
+// commit and adjusts the window.
+func (s *voteScheduler) observe(margin int64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.window == 0 {
+		s.window = voteWindowBlocks
+	}
+
+	if margin < minVoteMargin {
+		if s.window < maxVoteWindowBlocks {
+			s.window++
+		}
+		s.margins = nil
+		return
+	}
+
+	s.margins = append(s.margins, margin)
+	if len(s.margins) < voteMarginSamples {
+		return
+	}
+
+	// shorten the window, if all recent commits would still have had enough
+	// margin one block later
+	shorten := s.window > minVoteWindowBlocks
+	for _, m := range s.margins {
+		if m <= minVoteMargin {
+			shorten = false
+		}
+	}
+	if shorten {
+		s.window--
+	}
+	s.margins = nil
+}
+
+// voteMargin returns the number of blocks left after the block at the given
+// height in the vote period targeted by nextBlockHeight. The margin is
+// negative, if the (pre)vote was committed after that vote period ended.
+func voteMargin(nextBlockHeight, height, votePeriod int64) int64 {
+	periodEnd := (nextBlockHeight/votePeriod+1)*votePeriod - 1
+	return periodEnd - height
+}
+
+// observeVoteCommit exports and checks the timing of a committed (pre)vote,
+// broadcasted for nextBlockHeight and committed at height.
+func (o *Oracle) observeVoteCommit(
+	msgType string,
+	nextBlockHeight, height, votePeriod int64,
+) {
+	if height <= 0 || votePeriod <= 0 {
+		return
+	}
+
+	margin := voteMargin(nextBlockHeight, height, votePeriod)
+	latency := height - nextBlockHeight + 1
+
+	labels := []metrics.Label{telemetry.NewLabel("type", msgType)}
+	telemetry.SetGaugeWithLabels(
+		[]string{"vote", "margin_blocks"}, float32(margin), labels,
+	)
+	telemetry.SetGaugeWithLabels(
+		[]string{"vote", "latency_blocks"}, float32(latency), labels,
+	)
+
+	window := o.voteScheduler.windowBlocks(votePeriod)
+	o.voteScheduler.observe(margin)
+
+	logger := o.logger.With().
+		Str("type", msgType).
+		Int64("height", height).
+		Int64("margin_blocks", margin).
+		Int64("latency_blocks", latency).
+		Logger()
+
+	switch {
+	case margin < 0:
+		logger.Warn().Msg("vote committed after the end of the vote period")
+	case margin < minVoteMargin:
+		logger.Warn().Msg("vote committed in the last block of the vote period")
+	default:
+		logger.Debug().Msg("vote committed")
+	}
+
+	if newWindow := o.voteScheduler.windowBlocks(votePeriod); newWindow != window {
+		logger.Info().
+			Int64("window_blocks", newWindow).
+			Msg("adjusted vote window")
+	}
+}
@@ -0,0 +1,82 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+)
+
+func TestBlockTimer(t *testing.T) {
+	var timer blockTimer
+	now := time.Unix(1700000000, 0)
+
+	timer.observe(100, now)
+	require.Equal(t, time.Duration(0), timer.maxTickInterval())
+
+	// not enough blocks observed yet
+	timer.observe(105, now.Add(3*time.Second))
+	require.Equal(t, time.Duration(0), timer.maxTickInterval())
+
+	timer.observe(110, now.Add(6*time.Second))
+	require.Equal(t, 600*time.Millisecond, timer.blockTime)
+	require.Equal(t, 1200*time.Millisecond, timer.maxTickInterval())
+}
+
+func TestTickInterval(t *testing.T) {
+	o := Oracle{
+		logger: zerolog.Nop(),
+		timing: newTiming(zerolog.Nop(), config.Timing{TickInterval: "5s"}),
+	}
+	require.Equal(t, 5*time.Second, o.tickInterval())
+
+	o.blockTimer.blockTime = 600 * time.Millisecond
+	require.Equal(t, 1200*time.Millisecond, o.tickInterval())
+	require.True(t, o.timing.clamped)
+
+	o.timing = newTiming(zerolog.Nop(), config.Timing{})
+	require.Equal(t, tickerSleep, o.tickInterval())
+}
+
+func TestVoteMargin(t *testing.T) {
+	require.Equal(t, int64(13), voteMargin(140, 140, 14))
+	require.Equal(t, int64(2), voteMargin(150, 151, 14))
+	require.Equal(t, int64(0), voteMargin(152, 153, 14))
+
+	// committed in the next vote period
+	require.Equal(t, int64(-1), voteMargin(152, 154, 14))
+	require.Equal(t, int64(-3), voteMargin(153, 156, 14))
+}
+
+func TestVoteScheduler(t *testing.T) {
+	s := newVoteScheduler()
+	require.Equal(t, int64(voteWindowBlocks), s.windowBlocks(14))
+
+	// limited to half the vote period
+	require.Equal(t, int64(3), s.windowBlocks(6))
+
+	// late commits widen the window immediately
+	s.observe(0)
+	require.Equal(t, int64(voteWindowBlocks+1), s.windowBlocks(14))
+
+	for i := 0; i < 2*maxVoteWindowBlocks; i++ {
+		s.observe(0)
+	}
+	require.Equal(t, int64(maxVoteWindowBlocks), s.windowBlocks(100))
+
+	// tight margins keep the window
+	s = newVoteScheduler()
+	for i := 0; i < voteMarginSamples; i++ {
+		s.observe(minVoteMargin)
+	}
+	require.Equal(t, int64(voteWindowBlocks), s.windowBlocks(14))
+
+	// comfortable margins shorten it, down to the minimum
+	for i := 0; i < 10*voteMarginSamples; i++ {
+		s.observe(3)
+	}
+	require.Equal(t, int64(minVoteWindowBlocks), s.windowBlocks(14))
+}
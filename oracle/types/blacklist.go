@@ -0,0 +1,14 @@
+package types
+
+import (
+	"time"
+)
+
+// BlacklistEntry defines a provider symbol, which is excluded from the price
+// calculation. Entries without an expiry are set via config.
+type BlacklistEntry struct {
+	Provider string     `json:"provider"`
+	Symbol   string     `json:"symbol"`
+	Reason   string     `json:"reason"`
+	Until    *time.Time `json:"until,omitempty"`
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type (
+	// ConversionRate defines the USD rate of a denom for a single provider,
+	// including the ticker and the USD rate of its quote used to calculate it.
+	ConversionRate struct {
+		Denom     string  `json:"denom"`
+		Provider  string  `json:"provider"`
+		Symbol    string  `json:"symbol"`
+		Price     sdk.Dec `json:"price"`
+		QuoteRate sdk.Dec `json:"quote_rate"`
+		Rate      sdk.Dec `json:"rate"`
+		Volume    sdk.Dec `json:"volume"`
+	}
+
+	// Conversions defines all conversion rates calculated in a single tick.
+	Conversions struct {
+		Time  time.Time        `json:"time"`
+		Rates []ConversionRate `json:"rates"`
+	}
+)
@@ -21,6 +21,19 @@ func (tx *CosmosTx) GetEventsByType(eventType string) []CosmosTxEvent {
 	return events
 }
 
+// Sender returns the sender of the first message of the tx, or an empty
+// string if it is not part of the events.
+func (tx *CosmosTx) Sender() string {
+	for _, event := range tx.GetEventsByType("message") {
+		sender, found := event.Attributes["sender"]
+		if found && sender != "" {
+			return sender
+		}
+	}
+
+	return ""
+}
+
 type CosmosTxEvent struct {
 	Type       string
 	Attributes map[string]string
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type (
+	// Explanation defines the complete derivation of the prices computed in
+	// a single tick.
+	Explanation struct {
+		Time   time.Time                   `json:"time"`
+		Prices map[string]PriceExplanation `json:"prices"`
+	}
+
+	// PriceExplanation defines the derivation of the price of a single denom.
+	// Price is nil, if no price could be computed.
+	PriceExplanation struct {
+		Denom     string          `json:"denom"`
+		Price     *sdk.Dec        `json:"price"`
+		Providers []ExplainedRate `json:"providers"`
+		Excluded  []ExcludedRate  `json:"excluded"`
+		Formula   PriceFormula    `json:"formula"`
+	}
+
+	// ExplainedRate defines a provider rate contributing to the price. Price
+	// is the raw ticker price, QuoteRate the USD rate of its quote and Rate
+	// the resulting USD rate. Weight is the share of the rate in the VWAP.
+	ExplainedRate struct {
+		Provider  string  `json:"provider"`
+		Symbol    string  `json:"symbol"`
+		Price     sdk.Dec `json:"price"`
+		QuoteRate sdk.Dec `json:"quote_rate"`
+		Rate      sdk.Dec `json:"rate"`
+		Volume    sdk.Dec `json:"volume"`
+		Weight    sdk.Dec `json:"weight"`
+	}
+
+	// ExcludedRate defines a provider rate, that didn't contribute to the
+	// price, and the reason why.
+	ExcludedRate struct {
+		Provider string  `json:"provider"`
+		Symbol   string  `json:"symbol"`
+		Price    sdk.Dec `json:"price"`
+		Reason   string  `json:"reason"`
+	}
+
+	// PriceFormula defines the inputs of the deviation filter and the VWAP.
+	// Mean and deviation are only set, if enough rates were available to
+	// filter deviating rates.
+	PriceFormula struct {
+		Method      string   `json:"method"`
+		Mean        *sdk.Dec `json:"mean,omitempty"`
+		Deviation   *sdk.Dec `json:"deviation,omitempty"`
+		Threshold   sdk.Dec  `json:"threshold"`
+		TotalVolume sdk.Dec  `json:"total_volume"`
+	}
+)
@@ -0,0 +1,13 @@
+package types
+
+import (
+	"time"
+)
+
+// ProviderStatus defines the health and freshness of a price provider.
+type ProviderStatus struct {
+	Name          string    `json:"name"`
+	Healthy       bool      `json:"healthy"`
+	LastUpdate    time.Time `json:"last_update"`
+	ClockOffsetMs int64     `json:"clock_offset_ms"`
+}
@@ -9,9 +9,10 @@ import (
 
 // TickerPrice defines price and volume information for a symbol or ticker exchange rate.
 type TickerPrice struct {
-	Price  sdk.Dec   `json:"price"`  // last trade price
-	Volume sdk.Dec   `json:"volume"` // 24h volume
-	Time   time.Time `json:"time"`
+	Price     sdk.Dec   `json:"price"`  // last trade price
+	Volume    sdk.Dec   `json:"volume"` // 24h volume
+	Time      time.Time `json:"time"`
+	Liquidity sdk.Dec   `json:"liquidity"` // pool liquidity in quote terms, AMM providers only
 }
 
 func NewTickerPrice(price string, volume string, timestamp time.Time) (TickerPrice, error) {
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"time"
+)
+
+type (
+	// ProviderUptime defines the percentage of ticks over the last 24 hours
+	// and 7 days, in which the provider contributed a valid price for the
+	// denom. Windows without ticks are nil.
+	ProviderUptime struct {
+		Provider string   `json:"provider"`
+		Denom    string   `json:"denom"`
+		Day      *float64 `json:"uptime_24h"`
+		Week     *float64 `json:"uptime_7d"`
+	}
+
+	// Uptime defines the uptime of all providers and denoms at a point in
+	// time.
+	Uptime struct {
+		Time      time.Time        `json:"time"`
+		Providers []ProviderUptime `json:"providers"`
+	}
+)
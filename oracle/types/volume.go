@@ -0,0 +1,24 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type (
+	// DenomVolume defines the 24h volume of a denom in USD, in total and
+	// per provider.
+	DenomVolume struct {
+		Denom     string             `json:"denom"`
+		Volume    sdk.Dec            `json:"volume"`
+		Providers map[string]sdk.Dec `json:"providers"`
+	}
+
+	// Volumes defines the USD volumes of all denoms calculated in a single
+	// tick.
+	Volumes struct {
+		Time    time.Time     `json:"time"`
+		Volumes []DenomVolume `json:"volumes"`
+	}
+)
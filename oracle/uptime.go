@@ -0,0 +1,137 @@
+package oracle
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"price-feeder/oracle/history"
+	"price-feeder/oracle/labels"
+	"price-feeder/oracle/types"
+)
+
+// uptimeInterval defines how often the provider uptime is recalculated. The
+// contributions are stored in hourly buckets, so it doesn't need to be
+// more frequent.
+const uptimeInterval = 5 * time.Minute
+
+// updateUptime periodically calculates the uptime of each provider and
+// denom from the stored contributions and exports it as gauges.
+func (o *Oracle) updateUptime(ctx context.Context) {
+	ticker := time.NewTicker(uptimeInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := o.calculateUptime(time.Now()); err != nil {
+			o.logger.Err(err).Msg("failed to calculate provider uptime")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (o *Oracle) calculateUptime(now time.Time) error {
+	day, err := o.history.AllContributions(now.Add(-24*time.Hour), now)
+	if err != nil {
+		return err
+	}
+	week, err := o.history.AllContributions(now.Add(-7*24*time.Hour), now)
+	if err != nil {
+		return err
+	}
+
+	uptime := providerUptime(day, week)
+
+	for _, entry := range uptime {
+		if !labels.AllowedProvider(entry.Provider) {
+			continue
+		}
+		for window, value := range map[string]*float64{
+			"24h": entry.Day,
+			"7d":  entry.Week,
+		} {
+			if value == nil {
+				continue
+			}
+			telemetry.SetGaugeWithLabels(
+				[]string{"provider", "uptime"},
+				float32(*value),
+				[]metrics.Label{
+					telemetry.NewLabel("provider", entry.Provider),
+					telemetry.NewLabel("denom", entry.Denom),
+					telemetry.NewLabel("window", window),
+				},
+			)
+		}
+	}
+
+	o.mtx.Lock()
+	o.uptime = types.Uptime{Time: now, Providers: uptime}
+	o.mtx.Unlock()
+
+	return nil
+}
+
+// providerUptime merges the contributions of both windows into the uptime
+// by provider and denom, sorted by provider and denom.
+func providerUptime(
+	day, week map[string]map[string]history.Contributions,
+) []types.ProviderUptime {
+	entries := map[string]map[string]*types.ProviderUptime{}
+	get := func(providerName, denom string) *types.ProviderUptime {
+		if _, found := entries[providerName]; !found {
+			entries[providerName] = map[string]*types.ProviderUptime{}
+		}
+		entry, found := entries[providerName][denom]
+		if !found {
+			entry = &types.ProviderUptime{Provider: providerName, Denom: denom}
+			entries[providerName][denom] = entry
+		}
+		return entry
+	}
+
+	for providerName, denoms := range week {
+		for denom, contributions := range denoms {
+			if uptime, ok := contributions.Uptime(); ok {
+				get(providerName, denom).Week = &uptime
+			}
+		}
+	}
+	for providerName, denoms := range day {
+		for denom, contributions := range denoms {
+			if uptime, ok := contributions.Uptime(); ok {
+				get(providerName, denom).Day = &uptime
+			}
+		}
+	}
+
+	uptime := []types.ProviderUptime{}
+	for _, denoms := range entries {
+		for _, entry := range denoms {
+			uptime = append(uptime, *entry)
+		}
+	}
+	sort.Slice(uptime, func(i, j int) bool {
+		if uptime[i].Provider != uptime[j].Provider {
+			return uptime[i].Provider < uptime[j].Provider
+		}
+		return uptime[i].Denom < uptime[j].Denom
+	})
+
+	return uptime
+}
+
+// GetUptime returns the last calculated uptime of all providers and denoms.
+func (o *Oracle) GetUptime() types.Uptime {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	return o.uptime
+}
@@ -0,0 +1,32 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/oracle/history"
+)
+
+func TestProviderUptime(t *testing.T) {
+	day := map[string]map[string]history.Contributions{
+		"binance": {"ATOM": {Accepted: 3, Missing: 1}},
+	}
+	week := map[string]map[string]history.Contributions{
+		"binance": {"ATOM": {Accepted: 9, Missing: 1}},
+		"kraken":  {"ATOM": {Filtered: 5}},
+	}
+
+	uptime := providerUptime(day, week)
+	require.Len(t, uptime, 2)
+
+	require.Equal(t, "binance", uptime[0].Provider)
+	require.Equal(t, "ATOM", uptime[0].Denom)
+	require.Equal(t, 75.0, *uptime[0].Day)
+	require.Equal(t, 90.0, *uptime[0].Week)
+
+	// no ticks in the last 24 hours
+	require.Equal(t, "kraken", uptime[1].Provider)
+	require.Nil(t, uptime[1].Day)
+	require.Equal(t, 0.0, *uptime[1].Week)
+}
@@ -2,6 +2,7 @@ package oracle
 
 import (
 	"fmt"
+	"time"
 
 	"price-feeder/oracle/provider"
 	"price-feeder/oracle/types"
@@ -43,6 +44,47 @@ func ComputeVWAP(tickers []types.TickerPrice) (sdk.Dec, error) {
 	return weightedPrice.Quo(volumeSum), nil
 }
 
+// minCandleWeight defines the time weight of the oldest candles, relative
+// to the most recent one.
+var minCandleWeight = sdk.MustNewDecFromStr("0.2")
+
+// ComputeTVWAP computes the time and volume weighted average price of the
+// candles. The volume of each candle is weighted linearly by its age within
+// period, from 1 for the most recent candle down to minCandleWeight, so the
+// price follows the market without being dominated by a single trade.
+func ComputeTVWAP(candles []types.CandlePrice, now time.Time, period time.Duration) (sdk.Dec, error) {
+	if len(candles) == 0 {
+		return sdk.Dec{}, fmt.Errorf("no candles supplied")
+	}
+
+	periodMs := sdk.NewDec(period.Milliseconds())
+	tickers := make([]types.TickerPrice, len(candles))
+	for i, candle := range candles {
+		age := sdk.NewDec(now.UnixMilli() - candle.TimeStamp)
+		if age.IsNegative() {
+			age = sdk.ZeroDec()
+		}
+
+		// weight = 1 - (1 - minCandleWeight) * age / period
+		weight := sdk.OneDec().Sub(sdk.OneDec().Sub(minCandleWeight).Mul(age).Quo(periodMs))
+		if weight.LT(minCandleWeight) {
+			weight = minCandleWeight
+		}
+
+		volume := candle.Volume
+		if volume.IsNil() {
+			volume = sdk.ZeroDec()
+		}
+
+		tickers[i] = types.TickerPrice{
+			Price:  candle.Price,
+			Volume: volume.Mul(weight),
+		}
+	}
+
+	return ComputeVWAP(tickers)
+}
+
 // StandardDeviation returns standard deviation and mean of assets.
 // Will skip calculating for an asset if there are less than 3 prices.
 func StandardDeviation(prices []sdk.Dec) (sdk.Dec, sdk.Dec, error) {
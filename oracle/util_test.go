@@ -2,6 +2,7 @@ package oracle_test
 
 import (
 	"testing"
+	"time"
 
 	"price-feeder/oracle"
 	"price-feeder/oracle/types"
@@ -73,6 +74,35 @@ func TestComputeVWAP(t *testing.T) {
 	})
 }
 
+func TestComputeTVWAP(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	period := 10 * time.Minute
+
+	candles := []types.CandlePrice{{
+		Price:     sdk.MustNewDecFromStr("10"),
+		Volume:    sdk.MustNewDecFromStr("100"),
+		TimeStamp: now.UnixMilli(),
+	}, {
+		// weighted with minCandleWeight
+		Price:     sdk.MustNewDecFromStr("20"),
+		Volume:    sdk.MustNewDecFromStr("100"),
+		TimeStamp: now.Add(-period).UnixMilli(),
+	}}
+
+	tvwap, err := oracle.ComputeTVWAP(candles, now, period)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("11.666666666666666667"), tvwap)
+
+	// half way through the period
+	candles[1].TimeStamp = now.Add(-period / 2).UnixMilli()
+	tvwap, err = oracle.ComputeTVWAP(candles, now, period)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("13.75"), tvwap)
+
+	_, err = oracle.ComputeTVWAP([]types.CandlePrice{}, now, period)
+	require.Error(t, err)
+}
+
 func TestStandardDeviation(t *testing.T) {
 	type result struct {
 		mean      sdk.Dec
@@ -0,0 +1,98 @@
+package oracle
+
+import (
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+)
+
+// volumeSnapshotInterval defines how often the 24h volumes of the providers
+// are persisted to the history.
+const volumeSnapshotInterval = 15 * time.Minute
+
+// snapshotVolumes persists the 24h volumes by provider and symbol, if the
+// last snapshot is older than volumeSnapshotInterval. Providers storing
+// their own volume history are not included.
+func (o *Oracle) snapshotVolumes(volumes map[string]map[string]sdk.Dec, now time.Time) {
+	if len(volumes) == 0 {
+		return
+	}
+
+	o.mtx.Lock()
+	if now.Sub(o.lastVolumesTS) < volumeSnapshotInterval {
+		o.mtx.Unlock()
+		return
+	}
+	o.lastVolumesTS = now
+	o.mtx.Unlock()
+
+	if err := o.history.AddProviderVolumes(volumes, now); err != nil {
+		o.logger.Warn().Err(err).Msg("failed to add provider volumes to history")
+	}
+}
+
+// computeVolumes sums up the 24h base volumes of all provider tickers by
+// denom, converted to USD with the computed price of the denom. Denoms
+// without price are left out.
+func computeVolumes(
+	providerPrices provider.AggregatedProviderPrices,
+	providerPairs map[provider.Name][]types.CurrencyPair,
+	prices map[string]sdk.Dec,
+) []types.DenomVolume {
+	volumes := map[string]*types.DenomVolume{}
+
+	for providerName, pairs := range providerPairs {
+		for _, pair := range pairs {
+			ticker, found := providerPrices[providerName][pair.String()]
+			if !found || ticker.Volume.IsNil() || !ticker.Volume.IsPositive() {
+				continue
+			}
+
+			price, found := prices[pair.Base]
+			if !found {
+				continue
+			}
+
+			volume, found := volumes[pair.Base]
+			if !found {
+				volume = &types.DenomVolume{
+					Denom:     pair.Base,
+					Volume:    sdk.ZeroDec(),
+					Providers: map[string]sdk.Dec{},
+				}
+				volumes[pair.Base] = volume
+			}
+
+			usd := ticker.Volume.Mul(price)
+			volume.Volume = volume.Volume.Add(usd)
+
+			providerVolume, found := volume.Providers[providerName.String()]
+			if !found {
+				providerVolume = sdk.ZeroDec()
+			}
+			volume.Providers[providerName.String()] = providerVolume.Add(usd)
+		}
+	}
+
+	sorted := make([]types.DenomVolume, 0, len(volumes))
+	for _, volume := range volumes {
+		sorted = append(sorted, *volume)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Denom < sorted[j].Denom
+	})
+
+	return sorted
+}
+
+// GetVolumes returns the 24h USD volumes per denom of the last tick.
+func (o *Oracle) GetVolumes() types.Volumes {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	return o.volumes
+}
@@ -0,0 +1,69 @@
+package oracle
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+)
+
+func TestComputeVolumes(t *testing.T) {
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	atomUSD := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	lunaUSDT := types.CurrencyPair{Base: "LUNA", Quote: "USDT"}
+
+	providerPairs := map[provider.Name][]types.CurrencyPair{
+		provider.ProviderBinance: {atomUSDT, lunaUSDT},
+		provider.ProviderKraken:  {atomUSD},
+	}
+	providerPrices := provider.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			"ATOMUSDT": {Price: sdk.MustNewDecFromStr("10.1"), Volume: sdk.MustNewDecFromStr("100")},
+			"LUNAUSDT": {Price: sdk.MustNewDecFromStr("1"), Volume: sdk.MustNewDecFromStr("5000")},
+		},
+		provider.ProviderKraken: {
+			"ATOMUSD": {Price: sdk.MustNewDecFromStr("9.9"), Volume: sdk.MustNewDecFromStr("50")},
+		},
+	}
+	prices := map[string]sdk.Dec{
+		"ATOM": sdk.MustNewDecFromStr("10"),
+	}
+
+	volumes := computeVolumes(providerPrices, providerPairs, prices)
+	require.Len(t, volumes, 1)
+	require.Equal(t, "ATOM", volumes[0].Denom)
+	require.Equal(t, "1500.000000000000000000", volumes[0].Volume.String())
+	require.Len(t, volumes[0].Providers, 2)
+	require.Equal(t, "1000.000000000000000000", volumes[0].Providers["binance"].String())
+	require.Equal(t, "500.000000000000000000", volumes[0].Providers["kraken"].String())
+}
+
+func TestVolumeSymbols(t *testing.T) {
+	o := Oracle{
+		logger: zerolog.Nop(),
+		providerPairs: map[provider.Name][]types.CurrencyPair{
+			provider.ProviderFinV2: {{Base: "KUJI", Quote: "USK"}},
+		},
+		priceProviders:      map[provider.Name]provider.Provider{},
+		comparisonProviders: []provider.Name{provider.ProviderOjo},
+		comparisonPairs:     []types.CurrencyPair{{Base: "ATOM", Quote: "USD"}},
+	}
+
+	require.Equal(t, map[string]map[string]struct{}{
+		"finv2": {"KUJIUSK": {}, "USKKUJI": {}},
+		"ojo":   {"ATOMUSD": {}, "USDATOM": {}},
+	}, o.volumeSymbols())
+
+	// pairs subscribed at runtime keep their volume history
+	o.subscribePairs([]config.CurrencyPair{{
+		Base:      "MNTA",
+		Quote:     "USK",
+		Providers: []provider.Name{provider.ProviderFinV2},
+	}})
+	require.Contains(t, o.volumeSymbols()["finv2"], "MNTAUSK")
+}
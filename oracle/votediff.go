@@ -0,0 +1,93 @@
+package oracle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
+)
+
+// diffVotes returns the change of every rate of the current vote to the
+// previous vote in percent, sorted by denom, e.g. "BTC:+1.25%". Denoms not
+// in both votes are marked as "new" or "removed". The largest absolute
+// change is returned separately, to find a jumping asset at a glance.
+func diffVotes(previous, current string) ([]string, string, error) {
+	previousTuples, err := oracletypes.ParseExchangeRateTuples(previous)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse previous rates: %w", err)
+	}
+	currentTuples, err := oracletypes.ParseExchangeRateTuples(current)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse current rates: %w", err)
+	}
+
+	previousRates := make(map[string]sdk.Dec, len(previousTuples))
+	for _, tuple := range previousTuples {
+		previousRates[tuple.Denom] = tuple.ExchangeRate
+	}
+
+	var (
+		diff      []string
+		maxChange string
+		maxAbs    sdk.Dec
+	)
+	for _, tuple := range currentTuples {
+		rate, found := previousRates[tuple.Denom]
+		delete(previousRates, tuple.Denom)
+		if !found || !rate.IsPositive() {
+			diff = append(diff, tuple.Denom+":new")
+			continue
+		}
+
+		change := tuple.ExchangeRate.Quo(rate).Sub(sdk.OneDec()).MulInt64(100)
+		entry := fmt.Sprintf("%s:%s%%", tuple.Denom, formatChange(change))
+		diff = append(diff, entry)
+
+		if maxAbs.IsNil() || change.Abs().GT(maxAbs) {
+			maxAbs = change.Abs()
+			maxChange = entry
+		}
+	}
+	for denom := range previousRates {
+		diff = append(diff, denom+":removed")
+	}
+
+	sort.Strings(diff)
+	return diff, maxChange, nil
+}
+
+// formatChange formats a change in percent with sign and two decimals.
+func formatChange(change sdk.Dec) string {
+	sign := "+"
+	if change.IsNegative() {
+		sign = "-"
+	}
+	// round half away from zero, RoundInt rounds half to even
+	rounded := change.Abs().MulInt64(100).Add(sdk.NewDecWithPrec(5, 1)).TruncateInt()
+	return fmt.Sprintf(
+		"%s%s.%02d", sign, rounded.QuoRaw(100), rounded.ModRaw(100).Int64(),
+	)
+}
+
+// logVoteDiff logs the change of the voted rates to the previous vote.
+func (o *Oracle) logVoteDiff(exchangeRates string) {
+	previous := o.lastVotedRates
+	o.lastVotedRates = exchangeRates
+	if previous == "" {
+		return
+	}
+
+	diff, maxChange, err := diffVotes(previous, exchangeRates)
+	if err != nil {
+		o.logger.Debug().Err(err).Msg("failed to diff vote")
+		return
+	}
+
+	o.logger.Info().
+		Str("max_change", maxChange).
+		Str("diff", strings.Join(diff, " ")).
+		Msg("vote diff to previous period")
+}
@@ -0,0 +1,34 @@
+package oracle
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffVotes(t *testing.T) {
+	diff, maxChange, err := diffVotes(
+		"10.0ATOM,30000.0BTC,1.0KUJI",
+		"10.05ATOM,27000.0BTC,1.0USK",
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"ATOM:+0.50%",
+		"BTC:-10.00%",
+		"KUJI:removed",
+		"USK:new",
+	}, diff)
+	require.Equal(t, "BTC:-10.00%", maxChange)
+
+	_, _, err = diffVotes("10.0ATOM", "10.0")
+	require.Error(t, err)
+}
+
+func TestFormatChange(t *testing.T) {
+	require.Equal(t, "+0.00", formatChange(sdk.ZeroDec()))
+	require.Equal(t, "+1.23", formatChange(sdk.MustNewDecFromStr("1.2345")))
+	require.Equal(t, "-0.01", formatChange(sdk.MustNewDecFromStr("-0.005")))
+	require.Equal(t, "+0.03", formatChange(sdk.MustNewDecFromStr("0.025")))
+	require.Equal(t, "-120.50", formatChange(sdk.MustNewDecFromStr("-120.5")))
+}
@@ -0,0 +1,106 @@
+package votelog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+var csvHeader = []string{"time", "height", "exchange_rates", "tx_hash", "fee"}
+
+type (
+	// Vote defines a successfully broadcasted vote. The height is the
+	// inclusion height, or 0 if the inclusion wasn't observed.
+	Vote struct {
+		Time          time.Time `json:"time"`
+		Height        int64     `json:"height"`
+		ExchangeRates string    `json:"exchange_rates"`
+		TxHash        string    `json:"tx_hash"`
+		Fee           string    `json:"fee"`
+	}
+
+	// VoteLog appends votes to one file per UTC day in the configured
+	// directory, e.g. votes-2006-01-02.csv.
+	VoteLog struct {
+		mtx    sync.Mutex
+		dir    string
+		format string
+	}
+)
+
+func NewVoteLog(dir, format string) (*VoteLog, error) {
+	if format != FormatCSV && format != FormatJSONL {
+		return nil, fmt.Errorf("unsupported vote log format: %s", format)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &VoteLog{
+		dir:    dir,
+		format: format,
+	}, nil
+}
+
+// Path returns the file the vote is appended to.
+func (l *VoteLog) Path(vote Vote) string {
+	name := fmt.Sprintf("votes-%s.%s", vote.Time.UTC().Format("2006-01-02"), l.format)
+	return filepath.Join(l.dir, name)
+}
+
+func (l *VoteLog) Append(vote Vote) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	file, err := os.OpenFile(l.Path(vote), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if l.format == FormatJSONL {
+		data, err := json.Marshal(vote)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(append(data, '\n'))
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(file)
+	if info.Size() == 0 {
+		if err := writer.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	err = writer.Write([]string{
+		vote.Time.UTC().Format(time.RFC3339),
+		strconv.FormatInt(vote.Height, 10),
+		vote.ExchangeRates,
+		vote.TxHash,
+		vote.Fee,
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
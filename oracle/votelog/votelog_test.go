@@ -0,0 +1,60 @@
+package votelog
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteLogCSV(t *testing.T) {
+	voteLog, err := NewVoteLog(t.TempDir(), FormatCSV)
+	require.NoError(t, err)
+
+	vote := Vote{
+		Time:          time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		Height:        100,
+		ExchangeRates: "1.000000000000000000ATOM,2.000000000000000000KUJI",
+		TxHash:        "ABCD",
+		Fee:           "250ukuji",
+	}
+
+	require.NoError(t, voteLog.Append(vote))
+	require.NoError(t, voteLog.Append(vote))
+	require.True(t, strings.HasSuffix(voteLog.Path(vote), "votes-2023-01-02.csv"))
+
+	data, err := os.ReadFile(voteLog.Path(vote))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, "time,height,exchange_rates,tx_hash,fee", lines[0])
+	require.Equal(t, `2023-01-02T03:04:05Z,100,"1.000000000000000000ATOM,2.000000000000000000KUJI",ABCD,250ukuji`, lines[1])
+
+	// next day is written to a new file
+	vote.Time = vote.Time.Add(24 * time.Hour)
+	require.NoError(t, voteLog.Append(vote))
+	require.True(t, strings.HasSuffix(voteLog.Path(vote), "votes-2023-01-03.csv"))
+}
+
+func TestVoteLogJSONL(t *testing.T) {
+	voteLog, err := NewVoteLog(t.TempDir(), FormatJSONL)
+	require.NoError(t, err)
+
+	vote := Vote{
+		Time:   time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		Height: 100,
+		TxHash: "ABCD",
+	}
+
+	require.NoError(t, voteLog.Append(vote))
+
+	data, err := os.ReadFile(voteLog.Path(vote))
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"tx_hash":"ABCD"`)
+
+	_, err = NewVoteLog(t.TempDir(), "xml")
+	require.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package oracle
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"price-feeder/config"
+)
+
+const (
+	defaultWarmupCoverage = 0.8
+	defaultWarmupTimeout  = 5 * time.Minute
+)
+
+// warmup delays the first prevote after a start, until enough of the
+// required denoms were priced in consecutive ticks, so the vote isn't built
+// from a partially connected provider set.
+type warmup struct {
+	logger   zerolog.Logger
+	ticks    int
+	coverage float64
+	timeout  time.Duration
+	started  time.Time
+	streak   int
+	done     bool
+}
+
+func newWarmup(logger zerolog.Logger, cfg config.Timing, now time.Time) *warmup {
+	w := &warmup{
+		logger:   logger.With().Str("module", "warmup").Logger(),
+		ticks:    cfg.WarmupTicks,
+		coverage: defaultWarmupCoverage,
+		timeout:  defaultWarmupTimeout,
+		started:  now,
+		done:     cfg.WarmupTicks <= 0,
+	}
+
+	if coverage, err := strconv.ParseFloat(cfg.WarmupCoverage, 64); err == nil &&
+		coverage > 0 && coverage <= 1 {
+		w.coverage = coverage
+	}
+	if timeout, err := time.ParseDuration(cfg.WarmupTimeout); err == nil && timeout > 0 {
+		w.timeout = timeout
+	}
+
+	return w
+}
+
+// observe records the number of priced and required denoms of a tick.
+// Ticks without any required denoms, like the first one starting the
+// providers, are ignored.
+func (w *warmup) observe(priced, required int) {
+	if w.done || required == 0 {
+		return
+	}
+
+	if float64(priced)/float64(required) < w.coverage {
+		w.streak = 0
+		return
+	}
+
+	w.streak++
+	if w.streak >= w.ticks {
+		w.done = true
+		w.logger.Info().
+			Int("ticks", w.streak).
+			Dur("duration", time.Since(w.started)).
+			Msg("warm-up completed")
+	}
+}
+
+// ready returns true if the warm-up completed or timed out.
+func (w *warmup) ready(now time.Time) bool {
+	if w.done {
+		return true
+	}
+
+	if now.Sub(w.started) >= w.timeout {
+		w.done = true
+		w.logger.Warn().
+			Int("ticks", w.streak).
+			Dur("timeout", w.timeout).
+			Msg("warm-up timed out, voting with the available prices")
+		return true
+	}
+
+	return false
+}
+
+// warmedUp returns true if the oracle may prevote. Prices of a price source
+// are already aggregated by a warmed up collector.
+func (o *Oracle) warmedUp() bool {
+	return o.priceSource != nil || o.warmup.ready(time.Now())
+}
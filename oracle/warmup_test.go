@@ -0,0 +1,58 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+)
+
+func TestWarmup(t *testing.T) {
+	now := time.Now()
+	w := newWarmup(zerolog.Nop(), config.Timing{
+		WarmupTicks:    3,
+		WarmupCoverage: "0.8",
+		WarmupTimeout:  "1m",
+	}, now)
+
+	// the first tick only starts the providers
+	w.observe(0, 0)
+	require.False(t, w.ready(now))
+
+	w.observe(8, 10)
+	w.observe(9, 10)
+	require.False(t, w.ready(now))
+
+	// a tick below the coverage starts over
+	w.observe(5, 10)
+	w.observe(10, 10)
+	w.observe(8, 10)
+	require.False(t, w.ready(now))
+
+	w.observe(10, 10)
+	require.True(t, w.ready(now))
+
+	// the warm-up completes only once
+	w.observe(0, 10)
+	require.True(t, w.ready(now))
+}
+
+func TestWarmupTimeout(t *testing.T) {
+	now := time.Now()
+	w := newWarmup(zerolog.Nop(), config.Timing{
+		WarmupTicks:   3,
+		WarmupTimeout: "1m",
+	}, now)
+
+	w.observe(1, 10)
+	require.False(t, w.ready(now.Add(59*time.Second)))
+	require.True(t, w.ready(now.Add(time.Minute)))
+}
+
+func TestWarmupDisabled(t *testing.T) {
+	w := newWarmup(zerolog.Nop(), config.Timing{}, time.Now())
+	require.True(t, w.ready(time.Now()))
+}
@@ -0,0 +1,98 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"price-feeder/pkg/events"
+)
+
+const (
+	// defaultStallVotePeriods defines the number of vote periods without a
+	// new chain height, after which the height poller is restarted.
+	defaultStallVotePeriods = 3
+
+	// defaultStallBlockTime is assumed until the block time is observed.
+	defaultStallBlockTime = 6 * time.Second
+)
+
+// heightWatchdog tracks the last new chain height, to detect a stalled
+// height, which would skip all ticks until the next vote period forever.
+type heightWatchdog struct {
+	votePeriods int64
+
+	height   int64
+	seen     time.Time
+	lastVote time.Time
+}
+
+func newHeightWatchdog(votePeriods int) heightWatchdog {
+	if votePeriods <= 0 {
+		votePeriods = defaultStallVotePeriods
+	}
+	return heightWatchdog{votePeriods: int64(votePeriods)}
+}
+
+// observe records the chain height and returns for how long it hasn't
+// changed.
+func (w *heightWatchdog) observe(height int64, now time.Time) time.Duration {
+	if height != w.height || w.seen.IsZero() {
+		w.height = height
+		w.seen = now
+		return 0
+	}
+	return now.Sub(w.seen)
+}
+
+// reset starts waiting for a new height again.
+func (w *heightWatchdog) reset() {
+	w.height = 0
+	w.seen = time.Time{}
+}
+
+// stallTimeout returns the duration of the configured number of vote
+// periods.
+func (w *heightWatchdog) stallTimeout(votePeriod int64, blockTime time.Duration) time.Duration {
+	if blockTime <= 0 {
+		blockTime = defaultStallBlockTime
+	}
+	return time.Duration(w.votePeriods*votePeriod) * blockTime
+}
+
+// checkHeightStall restarts the chain height poller and starts over with a
+// new prevote, if the chain height hasn't changed for the configured number
+// of vote periods. It returns true, if the height was stalled.
+func (o *Oracle) checkHeightStall(ctx context.Context, height, votePeriod int64) bool {
+	now := time.Now()
+	stalled := o.watchdog.observe(height, now)
+	if o.watchdog.votePeriods == 0 ||
+		stalled < o.watchdog.stallTimeout(votePeriod, o.blockTimer.blockTime) {
+		return false
+	}
+
+	logger := o.logger.Error().
+		Int64("height", height).
+		Dur("stalled", stalled)
+	if !o.watchdog.lastVote.IsZero() {
+		logger = logger.Time("last_vote", o.watchdog.lastVote)
+	}
+	logger.Msg("chain height stalled, restarting chain height poller")
+
+	telemetry.IncrCounter(1, "watchdog", "height_stalled")
+	o.publish(
+		events.TopicHeightStalled,
+		fmt.Sprintf("chain height stalled at %d for %s", height, stalled.Round(time.Second)),
+		height,
+	)
+
+	o.oracleClient.TMRPC.Probe(ctx)
+	o.oracleClient.ChainHeight.Reset()
+
+	// the vote period of the stalled height would be skipped forever
+	o.setPreviousPrevote(0, nil)
+	o.watchdog.reset()
+	return true
+}
@@ -0,0 +1,28 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeightWatchdog(t *testing.T) {
+	w := newHeightWatchdog(0)
+	require.Equal(t, int64(defaultStallVotePeriods), w.votePeriods)
+
+	// 3 vote periods of 14 blocks
+	require.Equal(t, 42*defaultStallBlockTime, w.stallTimeout(14, 0))
+	require.Equal(t, 42*time.Second, w.stallTimeout(14, time.Second))
+
+	now := time.Now()
+	require.Zero(t, w.observe(100, now))
+	require.Equal(t, time.Minute, w.observe(100, now.Add(time.Minute)))
+
+	// a new height, even a lower one, resets the stall
+	require.Zero(t, w.observe(99, now.Add(2*time.Minute)))
+	require.Equal(t, time.Minute, w.observe(99, now.Add(3*time.Minute)))
+
+	w.reset()
+	require.Zero(t, w.observe(99, now.Add(4*time.Minute)))
+}
@@ -0,0 +1,172 @@
+package oracle
+
+import (
+	"strings"
+
+	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"price-feeder/config"
+	"price-feeder/oracle/types"
+	"price-feeder/pkg/events"
+)
+
+// SetWhitelist configures how denoms added to the whitelist without
+// configured pairs are handled.
+func (o *Oracle) SetWhitelist(whitelist config.Whitelist) {
+	o.whitelist = whitelist
+}
+
+// checkWhitelist warns about whitelisted denoms without price and alerts
+// once about denoms, that were added to the whitelist without configured
+// pairs, as votes will miss their rates. Pairs of these denoms are
+// subscribed from the registry, if enabled.
+func (o *Oracle) checkWhitelist(params oracletypes.Params) {
+	if o.unconfiguredDenoms == nil {
+		o.unconfiguredDenoms = map[string]struct{}{}
+	}
+
+	configured := o.configuredDenoms()
+	unconfigured := []string{}
+
+	for _, denom := range params.Whitelist {
+		symbol := strings.ToUpper(denom.Name)
+		if _, ok := o.prices[symbol]; !ok {
+			o.logger.Warn().Str("denom", symbol).Msg("price missing for required denom")
+		}
+
+		if _, ok := configured[symbol]; !ok {
+			unconfigured = append(unconfigured, symbol)
+		}
+	}
+
+	if len(unconfigured) > 0 && o.whitelist.AutoSubscribe {
+		unconfigured = o.subscribeDenoms(unconfigured)
+	}
+
+	current := map[string]struct{}{}
+	for _, denom := range unconfigured {
+		current[denom] = struct{}{}
+		if _, alerted := o.unconfiguredDenoms[denom]; alerted {
+			continue
+		}
+
+		o.logger.Error().
+			Str("denom", denom).
+			Msg("denom whitelisted without configured pairs, votes will miss its rate")
+		o.publish(
+			events.TopicDenomUnconfigured,
+			"denom whitelisted without configured pairs: "+denom,
+			denom,
+		)
+	}
+	o.unconfiguredDenoms = current
+
+	telemetry.SetGauge(float32(len(unconfigured)), "whitelist", "unconfigured")
+}
+
+// configuredDenoms returns the base denoms of all configured pairs.
+func (o *Oracle) configuredDenoms() map[string]struct{} {
+	denoms := map[string]struct{}{}
+	for _, pairs := range o.providerPairs {
+		for _, pair := range pairs {
+			denoms[pair.Base] = struct{}{}
+		}
+	}
+	for _, pairs := range o.derivativePairs {
+		for _, pair := range pairs {
+			denoms[pair.Base] = struct{}{}
+		}
+	}
+	return denoms
+}
+
+// subscribeDenoms adds the registry pairs of the denoms, and of their quotes
+// if not configured yet, to the providers. It returns the denoms without
+// registry pairs.
+func (o *Oracle) subscribeDenoms(denoms []string) []string {
+	registry, err := config.ReadPairsRegistry(o.whitelist.Registry)
+	if err != nil {
+		o.logger.Error().Err(err).Msg("failed to read pairs registry")
+		return denoms
+	}
+
+	missing := []string{}
+	for _, denom := range denoms {
+		pairs := registry.Pairs(denom)
+		if len(pairs) == 0 {
+			missing = append(missing, denom)
+			continue
+		}
+
+		// quotes need a USD rate for the conversion
+		configured := o.configuredDenoms()
+		for _, pair := range pairs {
+			if _, ok := configured[pair.Quote]; ok || pair.Quote == "USD" {
+				continue
+			}
+			pairs = append(pairs, registry.Pairs(pair.Quote)...)
+		}
+
+		if o.subscribePairs(pairs) == 0 {
+			missing = append(missing, denom)
+		}
+	}
+
+	return missing
+}
+
+// subscribePairs adds the pairs to their providers and returns the number
+// of added provider pairs. Running providers subscribe the pairs, all others
+// use them once they are started.
+func (o *Oracle) subscribePairs(pairs []config.CurrencyPair) int {
+	added := 0
+	for _, pair := range pairs {
+		if pair.Derivative != "" {
+			continue
+		}
+
+		currencyPair := types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}
+		for _, providerName := range pair.Providers {
+			subscribed := false
+			for _, existing := range o.providerPairs[providerName] {
+				if existing == currencyPair {
+					subscribed = true
+					break
+				}
+			}
+			if subscribed {
+				continue
+			}
+
+			o.mtx.RLock()
+			priceProvider, found := o.priceProviders[providerName]
+			o.mtx.RUnlock()
+
+			if found {
+				if err := priceProvider.SubscribeCurrencyPairs(currencyPair); err != nil {
+					o.logger.Warn().
+						Err(err).
+						Str("provider", providerName.String()).
+						Str("pair", currencyPair.String()).
+						Msg("failed to subscribe registry pair")
+					continue
+				}
+			}
+
+			// the tick is the only writer, but other goroutines read the
+			// pairs
+			o.mtx.Lock()
+			o.providerPairs[providerName] = append(o.providerPairs[providerName], currencyPair)
+			o.mtx.Unlock()
+			added++
+
+			o.logger.Info().
+				Str("provider", providerName.String()).
+				Str("pair", currencyPair.String()).
+				Msg("subscribed registry pair for whitelisted denom")
+		}
+	}
+
+	return added
+}
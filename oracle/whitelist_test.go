@@ -0,0 +1,67 @@
+package oracle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	oracletypes "github.com/Team-Kujira/core/x/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+	"price-feeder/oracle/provider"
+	"price-feeder/oracle/types"
+)
+
+func TestCheckWhitelist(t *testing.T) {
+	registry := filepath.Join(t.TempDir(), "pairs.toml")
+	require.NoError(t, os.WriteFile(registry, []byte(`
+[[currency_pairs]]
+base = "foo"
+quote = "KUJI"
+providers = ["fin", "unknown"]
+
+[[currency_pairs]]
+base = "KUJI"
+quote = "USDT"
+providers = ["binance"]
+`), 0o600))
+
+	o := Oracle{
+		logger: zerolog.Nop(),
+		providerPairs: map[provider.Name][]types.CurrencyPair{
+			provider.ProviderBinance: {{Base: "ATOM", Quote: "USDT"}},
+		},
+		priceProviders: map[provider.Name]provider.Provider{},
+		whitelist:      config.Whitelist{Registry: registry},
+	}
+
+	params := oracletypes.Params{
+		Whitelist: oracletypes.DenomList{
+			{Name: "atom"}, {Name: "foo"}, {Name: "bar"},
+		},
+	}
+
+	// alert only
+	o.checkWhitelist(params)
+	require.Equal(t, map[string]struct{}{"FOO": {}, "BAR": {}}, o.unconfiguredDenoms)
+	require.Len(t, o.providerPairs[provider.ProviderBinance], 1)
+
+	// subscribe registry pairs, including the quote
+	o.whitelist.AutoSubscribe = true
+	o.checkWhitelist(params)
+	require.Equal(t, map[string]struct{}{"BAR": {}}, o.unconfiguredDenoms)
+	require.Equal(t,
+		[]types.CurrencyPair{{Base: "FOO", Quote: "KUJI"}},
+		o.providerPairs[provider.ProviderFin],
+	)
+	require.Contains(t,
+		o.providerPairs[provider.ProviderBinance],
+		types.CurrencyPair{Base: "KUJI", Quote: "USDT"},
+	)
+
+	// already subscribed
+	o.checkWhitelist(params)
+	require.Len(t, o.providerPairs[provider.ProviderFin], 1)
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Topic defines the kind of an event.
+type Topic string
+
+// Topics published by the oracle.
+const (
+	// TopicStart is published when the oracle starts.
+	TopicStart Topic = "start"
+	// TopicStop is published when the oracle shuts down.
+	TopicStop Topic = "stop"
+	// TopicTickFailed is published when an oracle tick fails.
+	TopicTickFailed Topic = "tick_failed"
+	// TopicPricesUpdated is published after the prices are computed, with
+	// the prices as data.
+	TopicPricesUpdated Topic = "prices_updated"
+	// TopicQuorumLost is published when prices of configured denoms are
+	// missing, with the denoms as data.
+	TopicQuorumLost Topic = "quorum_lost"
+	// TopicPrevoteBroadcast is published after a prevote is committed.
+	TopicPrevoteBroadcast Topic = "prevote_broadcast"
+	// TopicVoteBroadcast is published after a vote is committed.
+	TopicVoteBroadcast Topic = "vote_broadcast"
+	// TopicVoteMismatch is published when the aggregate vote stored on
+	// chain differs from the submitted vote, with the differences as data.
+	TopicVoteMismatch Topic = "vote_mismatch"
+	// TopicVoteMissed is published when a vote period was missed.
+	TopicVoteMissed Topic = "vote_missed"
+	// TopicHeightStalled is published when the chain height didn't change
+	// for several vote periods, with the height as data.
+	TopicHeightStalled Topic = "height_stalled"
+	// TopicPriceOutOfBounds is published when a computed price is outside
+	// the plausible range of its denom, with the price and range as data.
+	TopicPriceOutOfBounds Topic = "price_out_of_bounds"
+	// TopicSanityRuleViolated is published when a computed price violates
+	// a configured sanity rule, with the violation as data.
+	TopicSanityRuleViolated Topic = "sanity_rule_violated"
+	// TopicProviderFailed is published when a provider returns an error or
+	// doesn't respond in time, with the provider name as data.
+	TopicProviderFailed Topic = "provider_failed"
+	// TopicProviderQuarantined is published when a pair of a provider is
+	// blacklisted, with the blacklist entry as data.
+	TopicProviderQuarantined Topic = "provider_quarantined"
+	// TopicDenomUnconfigured is published when a whitelisted denom has no
+	// configured pairs, with the denom as data.
+	TopicDenomUnconfigured Topic = "denom_unconfigured"
+	// TopicProposalVoting is published when a governance proposal changing
+	// the oracle params enters the voting period, with the proposal as data.
+	TopicProposalVoting Topic = "proposal_voting"
+	// TopicProposalPassed is published when a governance proposal changing
+	// the oracle params passed, with the proposal as data.
+	TopicProposalPassed Topic = "proposal_passed"
+)
+
+type (
+	// Event defines a single event published on the bus.
+	Event struct {
+		Topic   Topic
+		Time    time.Time
+		Message string
+		Data    interface{}
+	}
+
+	// Handler processes events. Handlers are called synchronously by the
+	// publisher, so slow handlers should hand the event off to a goroutine.
+	Handler func(event Event)
+
+	// Bus dispatches published events to the handlers subscribed to their
+	// topic. It decouples the components producing events, like the oracle
+	// and the providers, from the ones reacting to them, like healthchecks,
+	// notifications and telemetry.
+	Bus struct {
+		mtx      sync.RWMutex
+		handlers map[Topic][]Handler
+		all      []Handler
+	}
+)
+
+// NewBus returns a new event bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{handlers: map[Topic][]Handler{}}
+}
+
+// Subscribe registers the handler for the topics. Without topics, the
+// handler receives all events.
+func (b *Bus) Subscribe(handler Handler, topics ...Topic) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if len(topics) == 0 {
+		b.all = append(b.all, handler)
+		return
+	}
+
+	for _, topic := range topics {
+		b.handlers[topic] = append(b.handlers[topic], handler)
+	}
+}
+
+// Publish dispatches the event to all handlers subscribed to its topic, in
+// order of subscription. The event time is set, if missing.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mtx.RLock()
+	handlers := make([]Handler, 0, len(b.handlers[event.Topic])+len(b.all))
+	handlers = append(handlers, b.handlers[event.Topic]...)
+	handlers = append(handlers, b.all...)
+	b.mtx.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
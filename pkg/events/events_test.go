@@ -0,0 +1,36 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+
+	received := []string{}
+	bus.Subscribe(func(event Event) {
+		received = append(received, "vote:"+event.Message)
+	}, TopicVoteBroadcast, TopicVoteMissed)
+	bus.Subscribe(func(event Event) {
+		require.False(t, event.Time.IsZero())
+		received = append(received, "all:"+string(event.Topic))
+	})
+
+	bus.Publish(Event{Topic: TopicVoteBroadcast, Message: "ok"})
+	bus.Publish(Event{Topic: TopicStart})
+	bus.Publish(Event{Topic: TopicVoteMissed, Message: "missed"})
+
+	require.Equal(t, []string{
+		"vote:ok",
+		"all:vote_broadcast",
+		"all:start",
+		"vote:missed",
+		"all:vote_missed",
+	}, received)
+
+	// publishing without a bus is a no-op
+	var nilBus *Bus
+	nilBus.Publish(Event{Topic: TopicStart})
+}
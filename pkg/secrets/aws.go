@@ -0,0 +1,66 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+var _ Backend = (*AWS)(nil)
+
+// AWS reads secrets from the AWS Secrets Manager. Credentials are taken from
+// the default credential chain, e.g. the instance role.
+type AWS struct {
+	region string
+
+	once   sync.Once
+	client *secretsmanager.SecretsManager
+	err    error
+}
+
+// NewAWS returns an AWS Secrets Manager backend. If region is empty, the
+// region is taken from the environment or shared config.
+func NewAWS(region string) *AWS {
+	return &AWS{region: region}
+}
+
+func (a *AWS) Get(ctx context.Context, path string) (string, error) {
+	a.once.Do(func() {
+		cfg := aws.NewConfig()
+		if a.region != "" {
+			cfg = cfg.WithRegion(a.region)
+		}
+
+		var sess *session.Session
+		sess, a.err = session.NewSessionWithOptions(session.Options{
+			Config:            *cfg,
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if a.err == nil {
+			a.client = secretsmanager.New(sess)
+		}
+	})
+	if a.err != nil {
+		return "", a.err
+	}
+
+	output, err := a.client.GetSecretValueWithContext(
+		ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(path)},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case output.SecretString != nil:
+		return *output.SecretString, nil
+	case output.SecretBinary != nil:
+		return string(output.SecretBinary), nil
+	default:
+		return "", fmt.Errorf("secret is empty")
+	}
+}
@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcpSecretManagerURL = "https://secretmanager.googleapis.com/v1/"
+	gcpScope            = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+var _ Backend = (*GCP)(nil)
+
+// GCP reads secrets from the GCP Secret Manager. Credentials are taken from
+// the application default credentials, e.g. the service account of the
+// instance. The path is the full version name, e.g.
+// projects/<project>/secrets/<secret>/versions/latest.
+type GCP struct {
+	client *http.Client
+
+	once   sync.Once
+	tokens oauth2.TokenSource
+	err    error
+}
+
+// NewGCP returns a GCP Secret Manager backend.
+func NewGCP(timeout time.Duration) *GCP {
+	return &GCP{client: &http.Client{Timeout: timeout}}
+}
+
+func (g *GCP) Get(ctx context.Context, path string) (string, error) {
+	g.once.Do(func() {
+		// the token source outlives the request context
+		g.tokens, g.err = google.DefaultTokenSource(context.Background(), gcpScope)
+	})
+	if g.err != nil {
+		return "", g.err
+	}
+
+	token, err := g.tokens.Token()
+	if err != nil {
+		return "", err
+	}
+
+	url := gcpSecretManagerURL + strings.TrimPrefix(path, "/") + ":access"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	token.SetAuthHeader(req)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secret manager returned status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(response.Payload.Data)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,146 @@
+// Package secrets resolves references to secrets stored in an external
+// secret manager, so they never have to be written to config or env files.
+//
+// A reference has the form "<backend>:<path>[#<key>]". If a key is given, the
+// secret is expected to be a JSON object and the value of the key is
+// returned.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	BackendVault = "vault"
+	BackendAWS   = "aws"
+	BackendGCP   = "gcp"
+)
+
+// placeholder matches ${secret:<name>} in config values.
+var placeholder = regexp.MustCompile(`\$\{secret:([A-Za-z0-9_.-]+)\}`)
+
+type (
+	// Backend defines a secret manager returning the secret at a path.
+	Backend interface {
+		Get(ctx context.Context, path string) (string, error)
+	}
+
+	// Resolver resolves references using the registered backends. Resolved
+	// references are cached, so every secret is fetched only once.
+	Resolver struct {
+		backends map[string]Backend
+		refs     map[string]string
+
+		mtx   sync.Mutex
+		cache map[string]string
+	}
+)
+
+// NewResolver returns a resolver for the named references.
+func NewResolver(refs map[string]string) *Resolver {
+	return &Resolver{
+		backends: map[string]Backend{},
+		refs:     refs,
+		cache:    map[string]string{},
+	}
+}
+
+// Register adds a backend for the given reference prefix.
+func (r *Resolver) Register(name string, backend Backend) {
+	r.backends[name] = backend
+}
+
+// Resolve returns the secret of a reference.
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if value, ok := r.cache[ref]; ok {
+		return value, nil
+	}
+
+	name, rest, ok := strings.Cut(ref, ":")
+	if !ok {
+		return "", fmt.Errorf("invalid secret reference: %s", ref)
+	}
+	path, key, _ := strings.Cut(rest, "#")
+
+	backend, ok := r.backends[name]
+	if !ok {
+		return "", fmt.Errorf("unsupported secret backend: %s", name)
+	}
+
+	value, err := backend.Get(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s: %w", path, err)
+	}
+
+	if key != "" {
+		value, err = lookup(value, key)
+		if err != nil {
+			return "", fmt.Errorf("failed to get secret %s: %w", path, err)
+		}
+	}
+
+	r.cache[ref] = value
+	return value, nil
+}
+
+// ResolveName returns the secret of a named reference.
+func (r *Resolver) ResolveName(ctx context.Context, name string) (string, error) {
+	ref, ok := r.refs[name]
+	if !ok {
+		return "", fmt.Errorf("unknown secret: %s", name)
+	}
+	return r.Resolve(ctx, ref)
+}
+
+// Expand replaces all ${secret:<name>} placeholders with the secrets of the
+// named references.
+func (r *Resolver) Expand(ctx context.Context, value string) (string, error) {
+	var err error
+
+	expanded := placeholder.ReplaceAllStringFunc(value, func(match string) string {
+		if err != nil {
+			return match
+		}
+
+		name := placeholder.FindStringSubmatch(match)[1]
+
+		var secret string
+		secret, err = r.ResolveName(ctx, name)
+		return secret
+	})
+
+	return expanded, err
+}
+
+// HasPlaceholder returns true if the value contains a secret placeholder.
+func HasPlaceholder(value string) bool {
+	return placeholder.MatchString(value)
+}
+
+func lookup(secret, key string) (string, error) {
+	values := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(secret), &values); err != nil {
+		return "", fmt.Errorf("secret is not a json object: %w", err)
+	}
+
+	value, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found", key)
+	}
+
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	default:
+		bz, err := json.Marshal(value)
+		return string(bz), err
+	}
+}
@@ -0,0 +1,105 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockBackend struct {
+	secrets map[string]string
+	calls   int
+}
+
+func (m *mockBackend) Get(_ context.Context, path string) (string, error) {
+	m.calls++
+	secret, ok := m.secrets[path]
+	if !ok {
+		return "", fmt.Errorf("not found")
+	}
+	return secret, nil
+}
+
+func TestResolver(t *testing.T) {
+	backend := &mockBackend{secrets: map[string]string{
+		"feeder/password": "hunter2",
+		"feeder/keys":     `{"alchemy":"abc","retries":3}`,
+	}}
+
+	resolver := NewResolver(map[string]string{
+		"alchemy": "mock:feeder/keys#alchemy",
+		"missing": "mock:feeder/keys#missing",
+	})
+	resolver.Register("mock", backend)
+
+	ctx := context.Background()
+
+	password, err := resolver.Resolve(ctx, "mock:feeder/password")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", password)
+
+	retries, err := resolver.Resolve(ctx, "mock:feeder/keys#retries")
+	require.NoError(t, err)
+	require.Equal(t, "3", retries)
+
+	url, err := resolver.Expand(ctx, "https://eth.example.com/v2/${secret:alchemy}")
+	require.NoError(t, err)
+	require.Equal(t, "https://eth.example.com/v2/abc", url)
+
+	// cached
+	_, err = resolver.ResolveName(ctx, "alchemy")
+	require.NoError(t, err)
+	require.Equal(t, 3, backend.calls)
+
+	_, err = resolver.Expand(ctx, "${secret:missing}")
+	require.ErrorContains(t, err, "key missing not found")
+
+	_, err = resolver.Expand(ctx, "${secret:unknown}")
+	require.ErrorContains(t, err, "unknown secret")
+
+	_, err = resolver.Resolve(ctx, "ssm:feeder/password")
+	require.ErrorContains(t, err, "unsupported secret backend")
+
+	require.True(t, HasPlaceholder("${secret:alchemy}"))
+	require.False(t, HasPlaceholder("${alchemy}"))
+}
+
+func TestVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/feeder":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"hunter2"},"metadata":{"version":1}}}`))
+		case "/v1/kv/feeder":
+			_, _ = w.Write([]byte(`{"data":{"password":"hunter3"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewResolver(nil)
+	resolver.Register(BackendVault, NewVault(server.URL+"/", "token", "", time.Second))
+
+	ctx := context.Background()
+
+	password, err := resolver.Resolve(ctx, "vault:secret/data/feeder#password")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", password)
+
+	password, err = resolver.Resolve(ctx, "vault:kv/feeder#password")
+	require.NoError(t, err)
+	require.Equal(t, "hunter3", password)
+
+	_, err = resolver.Resolve(ctx, "vault:secret/data/unknown#password")
+	require.ErrorContains(t, err, "status 404")
+}
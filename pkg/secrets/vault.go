@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var _ Backend = (*Vault)(nil)
+
+// Vault reads secrets from the HashiCorp Vault HTTP API. Both KV version 1
+// and 2 are supported, the path has to include "data/" for version 2.
+type Vault struct {
+	address   string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+// NewVault returns a vault backend. The token is never read from the config,
+// but from the VAULT_TOKEN environment variable.
+func NewVault(address, token, namespace string, timeout time.Duration) *Vault {
+	return &Vault{
+		address:   strings.TrimSuffix(address, "/"),
+		token:     token,
+		namespace: namespace,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (v *Vault) Get(ctx context.Context, path string) (string, error) {
+	url := v.address + "/v1/" + strings.TrimPrefix(path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data     json.RawMessage `json:"data"`
+		Metadata json.RawMessage `json:"metadata"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", err
+	}
+
+	// KV version 2 nests the secret in data.data
+	var nested struct {
+		Data     json.RawMessage `json:"data"`
+		Metadata json.RawMessage `json:"metadata"`
+	}
+	if err := json.Unmarshal(response.Data, &nested); err == nil &&
+		nested.Data != nil && nested.Metadata != nil {
+		return string(nested.Data), nil
+	}
+
+	return string(response.Data), nil
+}
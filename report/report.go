@@ -0,0 +1,251 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+
+	"price-feeder/config"
+	"price-feeder/oracle/types"
+)
+
+const (
+	defaultInterval     = 30 * time.Second
+	defaultPushInterval = 5 * time.Minute
+	defaultTimeout      = 10 * time.Second
+
+	// maxSnapshots defines the number of snapshots kept while the collector
+	// is unreachable, older snapshots are dropped.
+	maxSnapshots = 1000
+)
+
+type (
+	// Oracle defines the Oracle interface contract that the reporter depends
+	// on.
+	Oracle interface {
+		GetLastPriceSyncTimestamp() time.Time
+		GetPrices() sdk.DecCoins
+		GetProviderStatus() []types.ProviderStatus
+		GetUptime() types.Uptime
+	}
+
+	// Metrics defines the Metrics interface contract that the reporter
+	// depends on.
+	Metrics interface {
+		Gather(format string) (telemetry.GatherResponse, error)
+	}
+
+	// Snapshot defines the status of the feeder at a point in time.
+	Snapshot struct {
+		Time      time.Time              `json:"time"`
+		LastSync  time.Time              `json:"last_sync"`
+		Prices    sdk.DecCoins           `json:"prices"`
+		Providers []types.ProviderStatus `json:"providers"`
+		Uptime    []types.ProviderUptime `json:"uptime,omitempty"`
+		Metrics   json.RawMessage        `json:"metrics,omitempty"`
+	}
+
+	// Batch defines the body pushed to the collector.
+	Batch struct {
+		Name      string     `json:"name"`
+		Snapshots []Snapshot `json:"snapshots"`
+	}
+
+	// Reporter periodically collects snapshots of the feeder status and
+	// pushes them in batches to a remote collector over HTTPS. The
+	// connection is outbound only, so the feeder can run behind NAT without
+	// exposing the server.
+	Reporter struct {
+		logger       zerolog.Logger
+		oracle       Oracle
+		metrics      Metrics
+		url          string
+		token        string
+		headers      map[string]string
+		name         string
+		interval     time.Duration
+		pushInterval time.Duration
+		client       *http.Client
+
+		mtx       sync.Mutex
+		snapshots []Snapshot
+	}
+)
+
+// NewReporter returns a reporter pushing to the configured url. Metrics are
+// only included if metrics is not nil and include_metrics is set.
+func NewReporter(
+	logger zerolog.Logger,
+	cfg config.Report,
+	oracle Oracle,
+	metrics Metrics,
+) *Reporter {
+	parse := func(value string, fallback time.Duration) time.Duration {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return fallback
+		}
+		return duration
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+
+	if !cfg.IncludeMetrics {
+		metrics = nil
+	}
+
+	return &Reporter{
+		logger:       logger.With().Str("module", "report").Logger(),
+		oracle:       oracle,
+		metrics:      metrics,
+		url:          cfg.URL,
+		token:        cfg.Token,
+		headers:      cfg.Headers,
+		name:         name,
+		interval:     parse(cfg.Interval, defaultInterval),
+		pushInterval: parse(cfg.PushInterval, defaultPushInterval),
+		client:       &http.Client{Timeout: parse(cfg.Timeout, defaultTimeout)},
+	}
+}
+
+// Start collects and pushes snapshots until the context is cancelled. Pending
+// snapshots are pushed one last time on shutdown.
+func (r *Reporter) Start(ctx context.Context) error {
+	r.logger.Info().
+		Str("url", r.url).
+		Dur("interval", r.interval).
+		Dur("push_interval", r.pushInterval).
+		Msg("starting reporter")
+
+	collectTicker := time.NewTicker(r.interval)
+	defer collectTicker.Stop()
+
+	pushTicker := time.NewTicker(r.pushInterval)
+	defer pushTicker.Stop()
+
+	r.collect()
+
+	for {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(
+				context.Background(), r.client.Timeout,
+			)
+			defer cancel()
+
+			if err := r.push(shutdownCtx); err != nil {
+				r.logger.Warn().Err(err).Msg("failed to push final report")
+			}
+			return nil
+
+		case <-collectTicker.C:
+			r.collect()
+
+		case <-pushTicker.C:
+			if err := r.push(ctx); err != nil {
+				r.logger.Warn().Err(err).Msg("failed to push report")
+			}
+		}
+	}
+}
+
+// collect adds a snapshot of the current status.
+func (r *Reporter) collect() {
+	snapshot := Snapshot{
+		Time:      time.Now().UTC(),
+		LastSync:  r.oracle.GetLastPriceSyncTimestamp().UTC(),
+		Prices:    r.oracle.GetPrices(),
+		Providers: r.oracle.GetProviderStatus(),
+		Uptime:    r.oracle.GetUptime().Providers,
+	}
+
+	if r.metrics != nil {
+		response, err := r.metrics.Gather(telemetry.FormatDefault)
+		if err != nil {
+			r.logger.Debug().Err(err).Msg("failed to gather metrics")
+		} else if json.Valid(response.Metrics) {
+			snapshot.Metrics = response.Metrics
+		}
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.snapshots = append(r.snapshots, snapshot)
+	if len(r.snapshots) > maxSnapshots {
+		r.snapshots = r.snapshots[len(r.snapshots)-maxSnapshots:]
+	}
+}
+
+// push sends all pending snapshots in one batch. Snapshots are kept for the
+// next push if the collector is not reachable.
+func (r *Reporter) push(ctx context.Context) error {
+	r.mtx.Lock()
+	snapshots := r.snapshots
+	r.snapshots = nil
+	r.mtx.Unlock()
+
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	err := r.send(ctx, Batch{Name: r.name, Snapshots: snapshots})
+	if err != nil {
+		r.mtx.Lock()
+		r.snapshots = append(snapshots, r.snapshots...)
+		if len(r.snapshots) > maxSnapshots {
+			r.snapshots = r.snapshots[len(r.snapshots)-maxSnapshots:]
+		}
+		r.mtx.Unlock()
+		return err
+	}
+
+	r.logger.Debug().Int("snapshots", len(snapshots)).Msg("pushed report")
+	return nil
+}
+
+func (r *Reporter) send(ctx context.Context, batch Batch) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, r.url, bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range r.headers {
+		req.Header.Set(key, value)
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+	"price-feeder/oracle/types"
+)
+
+type mockOracle struct{}
+
+func (mockOracle) GetLastPriceSyncTimestamp() time.Time {
+	return time.Unix(1700000000, 0)
+}
+
+func (mockOracle) GetPrices() sdk.DecCoins {
+	return sdk.NewDecCoins(
+		sdk.NewDecCoinFromDec("KUJI", sdk.MustNewDecFromStr("0.75")),
+	)
+}
+
+func (mockOracle) GetProviderStatus() []types.ProviderStatus {
+	return []types.ProviderStatus{{Name: "binance", Healthy: true}}
+}
+
+func (mockOracle) GetUptime() types.Uptime {
+	uptime := 99.5
+	return types.Uptime{
+		Providers: []types.ProviderUptime{
+			{Provider: "binance", Denom: "KUJI", Day: &uptime, Week: &uptime},
+		},
+	}
+}
+
+type mockMetrics struct{}
+
+func (mockMetrics) Gather(string) (telemetry.GatherResponse, error) {
+	return telemetry.GatherResponse{Metrics: []byte(`{"Gauges":[]}`)}, nil
+}
+
+func TestReporterPush(t *testing.T) {
+	var (
+		fail    atomic.Bool
+		batches = make(chan Batch, 10)
+	)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.Equal(t, "validator-1", r.Header.Get("X-Feeder"))
+
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var batch Batch
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		batches <- batch
+	}))
+	defer server.Close()
+
+	reporter := NewReporter(zerolog.Nop(), config.Report{
+		URL:            server.URL,
+		Token:          "secret",
+		Headers:        map[string]string{"X-Feeder": "validator-1"},
+		Name:           "feeder",
+		IncludeMetrics: true,
+	}, mockOracle{}, mockMetrics{})
+	reporter.client = server.Client()
+
+	ctx := context.Background()
+
+	// nothing to push
+	require.NoError(t, reporter.push(ctx))
+	require.Empty(t, batches)
+
+	// snapshots are kept while the collector is unreachable
+	fail.Store(true)
+	reporter.collect()
+	require.Error(t, reporter.push(ctx))
+
+	fail.Store(false)
+	reporter.collect()
+	require.NoError(t, reporter.push(ctx))
+
+	batch := <-batches
+	require.Equal(t, "feeder", batch.Name)
+	require.Len(t, batch.Snapshots, 2)
+	require.Equal(t, "KUJI", batch.Snapshots[0].Prices[0].Denom)
+	require.Equal(t, "binance", batch.Snapshots[0].Providers[0].Name)
+	require.Equal(t, "KUJI", batch.Snapshots[0].Uptime[0].Denom)
+	require.JSONEq(t, `{"Gauges":[]}`, string(batch.Snapshots[0].Metrics))
+
+	require.Empty(t, reporter.snapshots)
+}
+
+func TestReporterMaxSnapshots(t *testing.T) {
+	reporter := NewReporter(
+		zerolog.Nop(), config.Report{URL: "https://localhost"}, mockOracle{}, mockMetrics{},
+	)
+	require.Nil(t, reporter.metrics)
+	require.Equal(t, defaultInterval, reporter.interval)
+	require.Equal(t, defaultPushInterval, reporter.pushInterval)
+
+	for i := 0; i < maxSnapshots+10; i++ {
+		reporter.collect()
+	}
+	require.Len(t, reporter.snapshots, maxSnapshots)
+}
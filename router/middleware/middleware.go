@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/justinas/alice"
@@ -16,6 +18,9 @@ func Build(logger zerolog.Logger, cfg config.Config) alice.Chain {
 	mChain := alice.New()
 	mChain = AddRequestLoggingMiddleware(mChain, logger)
 	mChain = AddCORSMiddleware(mChain, logger, cfg)
+	if len(cfg.Server.AuthTokens) > 0 {
+		mChain = AddAuthMiddleware(mChain, cfg.Server.AuthTokens)
+	}
 
 	return mChain
 }
@@ -70,3 +75,23 @@ func AddCORSMiddleware(mChain alice.Chain, logger zerolog.Logger, cfg config.Con
 
 	return mChain
 }
+
+// AddAuthMiddleware appends middleware to a provided middleware chain, which
+// rejects requests without one of the bearer tokens.
+func AddAuthMiddleware(mChain alice.Chain, tokens []string) alice.Chain {
+	return mChain.Append(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+			for _, allowed := range tokens {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(allowed)) == 1 {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		})
+	})
+}
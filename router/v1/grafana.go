@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"price-feeder/oracle/history"
+	"price-feeder/pkg/httputil"
+)
+
+// Datasource defines the history interface the Grafana and the provider
+// stats endpoints depend on.
+type Datasource interface {
+	Targets() ([]string, error)
+	Series(target string, from, to time.Time) ([]history.Point, error)
+	Votes(from, to time.Time) ([]history.Vote, error)
+	ProviderContributions(provider string, from, to time.Time) (map[string]history.Contributions, error)
+}
+
+type (
+	// GrafanaRange defines the time range of a Grafana request.
+	GrafanaRange struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	}
+
+	// GrafanaSearchRequest defines the request of the /search endpoint.
+	GrafanaSearchRequest struct {
+		Target string `json:"target"`
+	}
+
+	// GrafanaQueryRequest defines the request of the /query endpoint.
+	GrafanaQueryRequest struct {
+		Range   GrafanaRange `json:"range"`
+		Targets []struct {
+			Target string `json:"target"`
+		} `json:"targets"`
+		MaxDataPoints int `json:"maxDataPoints"`
+	}
+
+	// GrafanaSeries defines a single time series of the /query response.
+	// Datapoints are [value, unix time in ms].
+	GrafanaSeries struct {
+		Target     string       `json:"target"`
+		Datapoints [][2]float64 `json:"datapoints"`
+	}
+
+	// GrafanaAnnotationRequest defines the request of the /annotations
+	// endpoint.
+	GrafanaAnnotationRequest struct {
+		Range      GrafanaRange    `json:"range"`
+		Annotation json.RawMessage `json:"annotation"`
+	}
+
+	// GrafanaAnnotation defines a single vote event of the /annotations
+	// response.
+	GrafanaAnnotation struct {
+		Annotation json.RawMessage `json:"annotation"`
+		Time       int64           `json:"time"`
+		Title      string          `json:"title"`
+		Text       string          `json:"text"`
+		Tags       []string        `json:"tags"`
+	}
+)
+
+func (r *Router) grafanaSearchHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var searchReq GrafanaSearchRequest
+		// the body is optional
+		_ = json.NewDecoder(req.Body).Decode(&searchReq)
+
+		targets, err := r.datasource.Targets()
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, targets)
+	}
+}
+
+func (r *Router) grafanaQueryHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var queryReq GrafanaQueryRequest
+		if err := json.NewDecoder(req.Body).Decode(&queryReq); err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		resp := make([]GrafanaSeries, 0, len(queryReq.Targets))
+		for _, target := range queryReq.Targets {
+			points, err := r.datasource.Series(
+				target.Target, queryReq.Range.From, queryReq.Range.To,
+			)
+			if err != nil {
+				httputil.RespondWithError(w, http.StatusBadRequest, err)
+				return
+			}
+
+			points = downsample(points, queryReq.MaxDataPoints)
+
+			series := GrafanaSeries{
+				Target:     target.Target,
+				Datapoints: make([][2]float64, len(points)),
+			}
+			for i, point := range points {
+				series.Datapoints[i] = [2]float64{
+					point.Value,
+					float64(point.Time.UnixMilli()),
+				}
+			}
+
+			resp = append(resp, series)
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) grafanaAnnotationsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var annotationReq GrafanaAnnotationRequest
+		if err := json.NewDecoder(req.Body).Decode(&annotationReq); err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		votes, err := r.datasource.Votes(
+			annotationReq.Range.From, annotationReq.Range.To,
+		)
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := make([]GrafanaAnnotation, len(votes))
+		for i, vote := range votes {
+			resp[i] = GrafanaAnnotation{
+				Annotation: annotationReq.Annotation,
+				Time:       vote.Time.UnixMilli(),
+				Title:      "vote",
+				Text:       vote.TxHash,
+				Tags:       []string{"vote"},
+			}
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+// downsample reduces the points to at most max points by skipping points in
+// regular intervals.
+func downsample(points []history.Point, max int) []history.Point {
+	if max <= 0 || len(points) <= max {
+		return points
+	}
+
+	sampled := make([]history.Point, 0, max)
+	step := float64(len(points)) / float64(max)
+	for i := 0; i < max; i++ {
+		sampled = append(sampled, points[int(float64(i)*step)])
+	}
+
+	return sampled
+}
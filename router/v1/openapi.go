@@ -0,0 +1,213 @@
+package v1
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/pkg/httputil"
+)
+
+type (
+	// route defines a v1 API route and its documentation. Request and
+	// response are zero values of the json types, their schemas are
+	// derived by reflection.
+	route struct {
+		path     string
+		method   string
+		handler  http.HandlerFunc
+		summary  string
+		params   []routeParam
+		request  interface{}
+		response interface{}
+	}
+
+	// routeParam defines an optional query parameter of a route, or a path
+	// parameter, if the path contains it in braces.
+	routeParam struct {
+		name        string
+		description string
+	}
+
+	// schema defines a (subset of an) OpenAPI schema object.
+	schema map[string]interface{}
+)
+
+var (
+	decType       = reflect.TypeOf(sdk.Dec{})
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	rawType       = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textType      = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (r *Router) openapiHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		httputil.RespondWithJSON(w, http.StatusOK, openapiDocument(r.routes()))
+	}
+}
+
+// openapiDocument returns the OpenAPI 3 document of the routes.
+func openapiDocument(routes []route) schema {
+	components := schema{}
+	paths := schema{}
+
+	for _, route := range routes {
+		operation := schema{
+			"summary": route.summary,
+		}
+
+		if len(route.params) > 0 {
+			params := []schema{}
+			for _, param := range route.params {
+				p := schema{
+					"name":        param.name,
+					"in":          "query",
+					"description": param.description,
+					"schema":      schema{"type": "string"},
+				}
+				if strings.Contains(route.path, "{"+param.name+"}") {
+					p["in"] = "path"
+					p["required"] = true
+				}
+				params = append(params, p)
+			}
+			operation["parameters"] = params
+		}
+
+		if route.request != nil {
+			operation["requestBody"] = schema{
+				"content": schema{
+					"application/json": schema{
+						"schema": typeSchema(reflect.TypeOf(route.request), components),
+					},
+				},
+			}
+		}
+
+		response := schema{"description": "OK"}
+		if route.response != nil {
+			response["content"] = schema{
+				"application/json": schema{
+					"schema": typeSchema(reflect.TypeOf(route.response), components),
+				},
+			}
+		}
+		operation["responses"] = schema{"200": response}
+
+		path := APIPathPrefix + route.path
+		item, found := paths[path].(schema)
+		if !found {
+			item = schema{}
+			paths[path] = item
+		}
+		item[strings.ToLower(route.method)] = operation
+	}
+
+	return schema{
+		"openapi": "3.0.3",
+		"info": schema{
+			"title":   "price-feeder",
+			"version": "v1",
+		},
+		"paths": paths,
+		"components": schema{
+			"schemas": components,
+		},
+	}
+}
+
+// typeSchema returns the schema of the json encoding of the type. Named
+// structs are added to the components and referenced.
+func typeSchema(t reflect.Type, components schema) schema {
+	switch t {
+	case decType:
+		return schema{"type": "string", "format": "decimal"}
+	case timeType:
+		return schema{"type": "string", "format": "date-time"}
+	case durationType:
+		return schema{"type": "integer", "description": "nanoseconds"}
+	case rawType:
+		return schema{}
+	}
+
+	if t.Kind() != reflect.Pointer &&
+		(t.Implements(marshalerType) || t.Implements(textType) ||
+			reflect.PointerTo(t).Implements(textType)) {
+		return schema{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem(), components)
+	case reflect.Bool:
+		return schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return schema{"type": "number"}
+	case reflect.String:
+		return schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return schema{"type": "array", "items": typeSchema(t.Elem(), components)}
+	case reflect.Map:
+		return schema{
+			"type":                 "object",
+			"additionalProperties": typeSchema(t.Elem(), components),
+		}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, components)
+		}
+
+		name := strings.ReplaceAll(t.String(), ".", "_")
+		if _, found := components[name]; !found {
+			// reserve the name first, types may reference themselves
+			components[name] = schema{}
+			components[name] = structSchema(t, components)
+		}
+		return schema{"$ref": "#/components/schemas/" + name}
+	}
+
+	return schema{}
+}
+
+// structSchema returns the object schema of the exported, json encoded
+// fields of the struct.
+func structSchema(t reflect.Type, components schema) schema {
+	properties := schema{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = typeSchema(field.Type, components)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	s := schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
@@ -4,10 +4,33 @@ import (
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/leader"
+	"price-feeder/oracle/types"
+	"price-feeder/update"
 )
 
 // Oracle defines the Oracle interface contract that the v1 router depends on.
 type Oracle interface {
 	GetLastPriceSyncTimestamp() time.Time
+	GetLastPricesTimestamp() time.Time
 	GetPrices() sdk.DecCoins
+	GetBlacklist() []types.BlacklistEntry
+	GetProviderStatus() []types.ProviderStatus
+	GetConversions() types.Conversions
+	GetExplanation() types.Explanation
+	GetVolumes() types.Volumes
+	GetUptime() types.Uptime
+}
+
+// Updates defines the update checker interface the healthz endpoint depends
+// on.
+type Updates interface {
+	Status() *update.Status
+}
+
+// Election defines the leader election interface the healthz endpoint
+// depends on. Peers read the election state from it.
+type Election interface {
+	Status() *leader.Status
 }
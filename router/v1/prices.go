@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	pricesFormatJSON = "json"
+	pricesFormatCSV  = "csv"
+)
+
+// pricesQuery defines the query parameters of the prices endpoint.
+type pricesQuery struct {
+	denoms   map[string]struct{}
+	format   string
+	metadata bool
+	explain  bool
+	offset   int
+	limit    int
+}
+
+// parsePricesQuery parses the query parameters of the prices endpoint:
+// denoms (comma separated), format (json or csv), metadata, explain, and
+// offset and limit for pagination over the denoms sorted by name.
+func parsePricesQuery(values url.Values) (pricesQuery, error) {
+	q := pricesQuery{
+		format:   pricesFormatJSON,
+		metadata: values.Get("metadata") == "true",
+		explain:  values.Get("explain") == "true",
+	}
+
+	for _, denom := range strings.Split(values.Get("denoms"), ",") {
+		denom = strings.ToUpper(strings.TrimSpace(denom))
+		if denom == "" {
+			continue
+		}
+		if q.denoms == nil {
+			q.denoms = map[string]struct{}{}
+		}
+		q.denoms[denom] = struct{}{}
+	}
+
+	if format := values.Get("format"); format != "" {
+		if format != pricesFormatJSON && format != pricesFormatCSV {
+			return q, fmt.Errorf("unsupported format: %s", format)
+		}
+		q.format = format
+	}
+
+	var err error
+	if offset := values.Get("offset"); offset != "" {
+		q.offset, err = strconv.Atoi(offset)
+		if err != nil || q.offset < 0 {
+			return q, fmt.Errorf("invalid offset: %s", offset)
+		}
+	}
+	if limit := values.Get("limit"); limit != "" {
+		q.limit, err = strconv.Atoi(limit)
+		if err != nil || q.limit < 0 {
+			return q, fmt.Errorf("invalid limit: %s", limit)
+		}
+	}
+
+	return q, nil
+}
+
+// selective returns true if the query selects a subset of the prices.
+func (q pricesQuery) selective() bool {
+	return q.denoms != nil || q.offset > 0 || q.limit > 0
+}
+
+// filter returns the selected prices sorted by denom, the page selected by
+// offset and limit and the total number of selected prices.
+func (q pricesQuery) filter(prices sdk.DecCoins) (sdk.DecCoins, int) {
+	selected := sdk.DecCoins{}
+	for _, price := range prices {
+		if q.denoms != nil {
+			if _, found := q.denoms[price.Denom]; !found {
+				continue
+			}
+		}
+		selected = append(selected, price)
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Denom < selected[j].Denom
+	})
+
+	total := len(selected)
+	if q.offset >= total {
+		return sdk.DecCoins{}, total
+	}
+	selected = selected[q.offset:]
+	if q.limit > 0 && q.limit < len(selected) {
+		selected = selected[:q.limit]
+	}
+
+	return selected, total
+}
+
+// writePricesCSV writes the prices as csv with one line per denom.
+func writePricesCSV(w http.ResponseWriter, resp PricesResponse) {
+	denoms := make([]string, 0, len(resp.Prices))
+	for denom := range resp.Prices {
+		denoms = append(denoms, denom)
+	}
+	sort.Strings(denoms)
+
+	header := []string{"denom", "price"}
+	if resp.Metadata != nil {
+		header = append(header, "age", "providers")
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write(header)
+	for _, denom := range denoms {
+		record := []string{denom, resp.Prices[denom].String()}
+		if resp.Metadata != nil {
+			metadata := resp.Metadata[denom]
+			record = append(
+				record,
+				strconv.FormatFloat(metadata.Age, 'f', 3, 64),
+				strconv.Itoa(metadata.Providers),
+			)
+		}
+		_ = writer.Write(record)
+	}
+	writer.Flush()
+}
+
+// priceAge returns the seconds since the prices were calculated.
+func priceAge(timestamp time.Time) float64 {
+	if timestamp.IsZero() {
+		return 0
+	}
+	return time.Since(timestamp).Seconds()
+}
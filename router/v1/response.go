@@ -3,8 +3,14 @@ package v1
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"price-feeder/leader"
+	"price-feeder/oracle/history"
+	"price-feeder/oracle/types"
+	"price-feeder/update"
 )
 
 // Response constants
@@ -19,12 +25,70 @@ type (
 		Oracle struct {
 			LastSync string `json:"last_sync"`
 		} `json:"oracle"`
+		Update *update.Status `json:"update,omitempty"`
+		Leader *leader.Status `json:"leader,omitempty"`
 	}
 
 	// PricesResponse defines the response type for getting the latest exchange
-	// rates from the oracle.
+	// rates from the oracle. Explain and Metadata are only set, if requested
+	// with ?explain=true and ?metadata=true. Total is the number of prices
+	// matching the denoms filter, before pagination.
 	PricesResponse struct {
-		Prices map[string]sdk.Dec `json:"prices"`
+		Prices   map[string]sdk.Dec                `json:"prices"`
+		Total    int                               `json:"total"`
+		Explain  map[string]types.PriceExplanation `json:"explain,omitempty"`
+		Metadata map[string]PriceMetadata          `json:"metadata,omitempty"`
+	}
+
+	// PriceMetadata defines the age of a price in seconds and the number of
+	// providers it was calculated from.
+	PriceMetadata struct {
+		Age       float64 `json:"age"`
+		Providers int     `json:"providers"`
+	}
+
+	// BlacklistResponse defines the response type for getting the currently
+	// blacklisted provider pairs.
+	BlacklistResponse struct {
+		Entries []types.BlacklistEntry `json:"entries"`
+	}
+
+	// ProvidersResponse defines the response type for getting the health of
+	// all running price providers.
+	ProvidersResponse struct {
+		Providers []types.ProviderStatus `json:"providers"`
+	}
+
+	// ConversionsResponse defines the response type for getting the USD rates
+	// per provider and denom used during the last price calculation.
+	ConversionsResponse struct {
+		Time  time.Time              `json:"time"`
+		Rates []types.ConversionRate `json:"rates"`
+	}
+
+	// VolumesResponse defines the response type for getting the 24h volumes
+	// per denom in USD across all providers.
+	VolumesResponse struct {
+		Time    time.Time           `json:"time"`
+		Volumes []types.DenomVolume `json:"volumes"`
+	}
+
+	// UptimeResponse defines the response type for getting the percentage of
+	// ticks each provider contributed a valid price per denom.
+	UptimeResponse struct {
+		Time      time.Time              `json:"time"`
+		Providers []types.ProviderUptime `json:"providers"`
+	}
+
+	// ProviderStatsResponse defines the response type for getting how often
+	// the rates of a provider made it into the final prices. Denoms contains
+	// the outcomes by denom and Total their sum.
+	ProviderStatsResponse struct {
+		Provider string                           `json:"provider"`
+		From     time.Time                        `json:"from"`
+		To       time.Time                        `json:"to"`
+		Total    history.Contributions            `json:"total"`
+		Denoms   map[string]history.Contributions `json:"denoms"`
 	}
 )
 
@@ -10,29 +10,49 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog"
 
+	"price-feeder/collector"
 	"price-feeder/config"
+	"price-feeder/oracle/types"
 	"price-feeder/pkg/httputil"
 	"price-feeder/router/middleware"
 )
 
 const (
 	APIPathPrefix = "/api/v1"
+
+	// defaultStatsPeriod defines the period reported by the provider stats
+	// endpoint, if none is requested.
+	defaultStatsPeriod = 24 * time.Hour
 )
 
 // Router defines a router wrapper used for registering v1 API routes.
 type Router struct {
-	logger  zerolog.Logger
-	cfg     config.Config
-	oracle  Oracle
-	metrics Metrics
+	logger     zerolog.Logger
+	cfg        config.Config
+	oracle     Oracle
+	metrics    Metrics
+	datasource Datasource
+	updates    Updates
+	election   Election
 }
 
-func New(logger zerolog.Logger, cfg config.Config, oracle Oracle, metrics Metrics) *Router {
+func New(
+	logger zerolog.Logger,
+	cfg config.Config,
+	oracle Oracle,
+	metrics Metrics,
+	datasource Datasource,
+	updates Updates,
+	election Election,
+) *Router {
 	return &Router{
-		logger:  logger.With().Str("module", "router").Logger(),
-		cfg:     cfg,
-		oracle:  oracle,
-		metrics: metrics,
+		logger:     logger.With().Str("module", "router").Logger(),
+		cfg:        cfg,
+		oracle:     oracle,
+		metrics:    metrics,
+		datasource: datasource,
+		updates:    updates,
+		election:   election,
 	}
 }
 
@@ -51,7 +71,7 @@ func (r *Router) RegisterRoutes(rtr *mux.Router, prefix string) {
 			}
 		}
 
-		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
 		w.Header().Set(
 			"Access-Control-Allow-Headers",
 			"Content-Type, Access-Control-Allow-Headers, Authorization, X-Requested-With",
@@ -60,22 +80,171 @@ func (r *Router) RegisterRoutes(rtr *mux.Router, prefix string) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	v1Router.Handle(
-		"/healthz",
-		mChain.ThenFunc(r.healthzHandler()),
-	).Methods(httputil.MethodGET)
+	for _, route := range r.routes() {
+		v1Router.Handle(
+			route.path,
+			mChain.ThenFunc(route.handler),
+		).Methods(route.method)
+	}
+}
+
+// routes returns all v1 API routes with their documentation. The OpenAPI
+// document is generated from the same list, so it can't miss a route.
+func (r *Router) routes() []route {
+	routes := []route{
+		{
+			path:     "/healthz",
+			method:   httputil.MethodGET,
+			handler:  r.healthzHandler(),
+			summary:  "Health of the price feeder",
+			response: HealthZResponse{},
+		},
+		{
+			path:    "/prices",
+			method:  httputil.MethodGET,
+			handler: r.pricesHandler(),
+			summary: "Latest prices",
+			params: []routeParam{
+				{"denoms", "comma separated denoms to select"},
+				{"offset", "number of denoms, sorted by name, to skip"},
+				{"limit", "max number of denoms to return"},
+				{"format", "json (default) or csv"},
+				{"metadata", "true adds the age and provider count per denom"},
+				{"explain", "true adds the derivation of each price"},
+			},
+			response: PricesResponse{},
+		},
+	}
+
+	if r.cfg.Server.SigningKey != "" {
+		routes = append(routes, route{
+			path:     collector.PricesPath,
+			method:   httputil.MethodGET,
+			handler:  r.signedPricesHandler(),
+			summary:  "Latest prices signed with the signing key",
+			response: collector.SignedPrices{},
+		})
+	}
+
+	routes = append(routes, []route{
+		{
+			path:     "/blacklist",
+			method:   httputil.MethodGET,
+			handler:  r.blacklistHandler(),
+			summary:  "Blacklisted provider pairs",
+			response: BlacklistResponse{},
+		},
+		{
+			path:     "/providers",
+			method:   httputil.MethodGET,
+			handler:  r.providersHandler(),
+			summary:  "Health of all running providers",
+			response: ProvidersResponse{},
+		},
+		{
+			path:    "/conversions",
+			method:  httputil.MethodGET,
+			handler: r.conversionsHandler(),
+			summary: "USD rates used during the last price calculation",
+			params: []routeParam{
+				{"denom", "denom to select"},
+			},
+			response: ConversionsResponse{},
+		},
+		{
+			path:    "/volumes",
+			method:  httputil.MethodGET,
+			handler: r.volumesHandler(),
+			summary: "24h volumes per denom in USD across all providers",
+			params: []routeParam{
+				{"denom", "denom to select"},
+			},
+			response: VolumesResponse{},
+		},
+		{
+			path:    "/uptime",
+			method:  httputil.MethodGET,
+			handler: r.uptimeHandler(),
+			summary: "Uptime of the providers per denom over the last 24h and 7d",
+			params: []routeParam{
+				{"provider", "provider to select"},
+				{"denom", "denom to select"},
+			},
+			response: UptimeResponse{},
+		},
+		{
+			path:    "/openapi.json",
+			method:  httputil.MethodGET,
+			handler: r.openapiHandler(),
+			summary: "OpenAPI document of this api",
+		},
+	}...)
 
-	v1Router.Handle(
-		"/prices",
-		mChain.ThenFunc(r.pricesHandler()),
-	).Methods(httputil.MethodGET)
+	if r.datasource != nil {
+		routes = append(routes, route{
+			path:    "/providers/{name}/stats",
+			method:  httputil.MethodGET,
+			handler: r.providerStatsHandler(),
+			summary: "Outcomes of the rates of a provider over time",
+			params: []routeParam{
+				{"name", "name of the provider"},
+				{"period", "duration to report, e.g. 168h (default 24h)"},
+			},
+			response: ProviderStatsResponse{},
+		})
+	}
+
+	// simple-json-datasource protocol, e.g. for Grafana
+	if r.datasource != nil {
+		routes = append(routes, []route{
+			{
+				path:   "/grafana/",
+				method: httputil.MethodGET,
+				handler: func(w http.ResponseWriter, req *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				},
+				summary: "Grafana datasource test",
+			},
+			{
+				path:     "/grafana/search",
+				method:   httputil.MethodPOST,
+				handler:  r.grafanaSearchHandler(),
+				summary:  "Grafana datasource targets",
+				request:  GrafanaSearchRequest{},
+				response: []string{},
+			},
+			{
+				path:     "/grafana/query",
+				method:   httputil.MethodPOST,
+				handler:  r.grafanaQueryHandler(),
+				summary:  "Grafana datasource time series",
+				request:  GrafanaQueryRequest{},
+				response: []GrafanaSeries{},
+			},
+			{
+				path:     "/grafana/annotations",
+				method:   httputil.MethodPOST,
+				handler:  r.grafanaAnnotationsHandler(),
+				summary:  "Grafana datasource vote annotations",
+				request:  GrafanaAnnotationRequest{},
+				response: []GrafanaAnnotation{},
+			},
+		}...)
+	}
 
 	if r.cfg.Telemetry.Enabled {
-		v1Router.Handle(
-			"/metrics",
-			mChain.ThenFunc(r.metricsHandler()),
-		).Methods(httputil.MethodGET)
+		routes = append(routes, route{
+			path:    "/metrics",
+			method:  httputil.MethodGET,
+			handler: r.metricsHandler(),
+			summary: "Telemetry metrics",
+			params: []routeParam{
+				{"format", "prometheus or text (default)"},
+			},
+		})
 	}
+
+	return routes
 }
 
 func (r *Router) healthzHandler() http.HandlerFunc {
@@ -85,6 +254,12 @@ func (r *Router) healthzHandler() http.HandlerFunc {
 		}
 
 		resp.Oracle.LastSync = r.oracle.GetLastPriceSyncTimestamp().Format(time.RFC3339)
+		if r.updates != nil {
+			resp.Update = r.updates.Status()
+		}
+		if r.election != nil {
+			resp.Leader = r.election.Status()
+		}
 
 		httputil.RespondWithJSON(w, http.StatusOK, resp)
 	}
@@ -92,12 +267,188 @@ func (r *Router) healthzHandler() http.HandlerFunc {
 
 func (r *Router) pricesHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		prices := make(map[string]sdk.Dec, len(r.oracle.GetPrices()))
-		for _, price := range r.oracle.GetPrices() {
+		query, err := parsePricesQuery(req.URL.Query())
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		selected, total := query.filter(r.oracle.GetPrices())
+
+		prices := make(map[string]sdk.Dec, len(selected))
+		for _, price := range selected {
 			prices[price.Denom] = price.Amount
 		}
 		resp := PricesResponse{
 			Prices: prices,
+			Total:  total,
+		}
+
+		if query.explain || query.metadata {
+			explanations := r.oracle.GetExplanation().Prices
+
+			// without selection, missing prices are explained as well
+			if query.explain && !query.selective() {
+				resp.Explain = explanations
+			} else if query.explain {
+				resp.Explain = map[string]types.PriceExplanation{}
+				for denom := range prices {
+					if explanation, found := explanations[denom]; found {
+						resp.Explain[denom] = explanation
+					}
+				}
+			}
+
+			if query.metadata {
+				age := priceAge(r.oracle.GetLastPricesTimestamp())
+				resp.Metadata = make(map[string]PriceMetadata, len(prices))
+				for denom := range prices {
+					resp.Metadata[denom] = PriceMetadata{
+						Age:       age,
+						Providers: len(explanations[denom].Providers),
+					}
+				}
+			}
+		}
+
+		if query.format == pricesFormatCSV {
+			writePricesCSV(w, resp)
+			return
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) signedPricesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		prices := make(map[string]sdk.Dec, len(r.oracle.GetPrices()))
+		for _, price := range r.oracle.GetPrices() {
+			prices[price.Denom] = price.Amount
+		}
+
+		resp := collector.Sign(
+			[]byte(r.cfg.Server.SigningKey),
+			prices,
+			r.oracle.GetLastPricesTimestamp(),
+		)
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) blacklistHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := BlacklistResponse{
+			Entries: r.oracle.GetBlacklist(),
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) providersHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := ProvidersResponse{
+			Providers: r.oracle.GetProviderStatus(),
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) providerStatsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		name := mux.Vars(req)["name"]
+
+		period := defaultStatsPeriod
+		if value := strings.TrimSpace(req.FormValue("period")); value != "" {
+			var err error
+			period, err = time.ParseDuration(value)
+			if err != nil || period <= 0 {
+				writeErrorResponse(w, http.StatusBadRequest, "invalid period: "+value)
+				return
+			}
+		}
+
+		to := time.Now()
+		from := to.Add(-period)
+		denoms, err := r.datasource.ProviderContributions(name, from, to)
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := ProviderStatsResponse{
+			Provider: name,
+			From:     from,
+			To:       to,
+			Denoms:   denoms,
+		}
+		for _, contributions := range denoms {
+			resp.Total = resp.Total.Add(contributions)
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) conversionsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		denom := strings.ToUpper(strings.TrimSpace(req.FormValue("denom")))
+
+		conversions := r.oracle.GetConversions()
+
+		resp := ConversionsResponse{
+			Time:  conversions.Time,
+			Rates: []types.ConversionRate{},
+		}
+		for _, rate := range conversions.Rates {
+			if denom == "" || rate.Denom == denom {
+				resp.Rates = append(resp.Rates, rate)
+			}
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) volumesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		denom := strings.ToUpper(strings.TrimSpace(req.FormValue("denom")))
+
+		volumes := r.oracle.GetVolumes()
+
+		resp := VolumesResponse{
+			Time:    volumes.Time,
+			Volumes: []types.DenomVolume{},
+		}
+		for _, volume := range volumes.Volumes {
+			if denom == "" || volume.Denom == denom {
+				resp.Volumes = append(resp.Volumes, volume)
+			}
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) uptimeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		providerName := strings.ToLower(strings.TrimSpace(req.FormValue("provider")))
+		denom := strings.ToUpper(strings.TrimSpace(req.FormValue("denom")))
+
+		uptime := r.oracle.GetUptime()
+
+		resp := UptimeResponse{
+			Time:      uptime.Time,
+			Providers: []types.ProviderUptime{},
+		}
+		for _, entry := range uptime.Providers {
+			if (providerName == "" || entry.Provider == providerName) &&
+				(denom == "" || entry.Denom == denom) {
+				resp.Providers = append(resp.Providers, entry)
+			}
 		}
 
 		httputil.RespondWithJSON(w, http.StatusOK, resp)
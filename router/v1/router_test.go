@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,8 +13,12 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/suite"
 
+	"price-feeder/collector"
 	"price-feeder/config"
+	"price-feeder/oracle/history"
+	"price-feeder/oracle/types"
 	v1 "price-feeder/router/v1"
+	"price-feeder/update"
 
 	"github.com/cosmos/cosmos-sdk/telemetry"
 )
@@ -25,6 +30,83 @@ var (
 		sdk.NewDecCoinFromDec("ATOM", sdk.MustNewDecFromStr("34.84")),
 		sdk.NewDecCoinFromDec("UMEE", sdk.MustNewDecFromStr("4.21")),
 	}
+
+	mockBlacklist = []types.BlacklistEntry{
+		{Provider: "huobi", Symbol: "LUNAUSDT", Reason: "config"},
+	}
+
+	mockProviderStatus = []types.ProviderStatus{
+		{Name: "binance", Healthy: true, LastUpdate: time.Unix(1700000000, 0).UTC()},
+	}
+
+	mockConversions = types.Conversions{
+		Time: time.Unix(1700000000, 0).UTC(),
+		Rates: []types.ConversionRate{
+			{
+				Denom:     "USDT",
+				Provider:  "kraken",
+				Symbol:    "USDTUSD",
+				Price:     sdk.MustNewDecFromStr("0.999"),
+				QuoteRate: sdk.OneDec(),
+				Rate:      sdk.MustNewDecFromStr("0.999"),
+				Volume:    sdk.MustNewDecFromStr("1000"),
+			},
+			{
+				Denom:     "BTC",
+				Provider:  "binance",
+				Symbol:    "BTCUSDT",
+				Price:     sdk.MustNewDecFromStr("30000"),
+				QuoteRate: sdk.MustNewDecFromStr("0.999"),
+				Rate:      sdk.MustNewDecFromStr("29970"),
+				Volume:    sdk.MustNewDecFromStr("10"),
+			},
+		},
+	}
+
+	mockVolumes = types.Volumes{
+		Time: time.Unix(1700000000, 0).UTC(),
+		Volumes: []types.DenomVolume{
+			{
+				Denom:  "ATOM",
+				Volume: sdk.MustNewDecFromStr("3484"),
+				Providers: map[string]sdk.Dec{
+					"binance": sdk.MustNewDecFromStr("3484"),
+				},
+			},
+			{
+				Denom:  "BTC",
+				Volume: sdk.MustNewDecFromStr("299700"),
+				Providers: map[string]sdk.Dec{
+					"binance": sdk.MustNewDecFromStr("299700"),
+				},
+			},
+		},
+	}
+
+	mockUptime = types.Uptime{
+		Time: time.Unix(1700000000, 0).UTC(),
+		Providers: []types.ProviderUptime{
+			{Provider: "binance", Denom: "ATOM", Day: &mockUptimeDay, Week: &mockUptimeWeek},
+			{Provider: "kraken", Denom: "ATOM", Week: &mockUptimeWeek},
+		},
+	}
+	mockUptimeDay  = 99.5
+	mockUptimeWeek = 97.25
+
+	mockExplanation = types.Explanation{
+		Time: time.Unix(1700000000, 0).UTC(),
+		Prices: map[string]types.PriceExplanation{
+			"ATOM": {
+				Denom: "ATOM",
+				Providers: []types.ExplainedRate{
+					{Provider: "binance", Symbol: "ATOMUSDT", Weight: sdk.OneDec()},
+				},
+				Excluded: []types.ExcludedRate{
+					{Provider: "kraken", Symbol: "ATOMUSD", Reason: "deviating price"},
+				},
+			},
+		},
+	}
 )
 
 type mockOracle struct{}
@@ -33,16 +115,87 @@ func (m mockOracle) GetLastPriceSyncTimestamp() time.Time {
 	return time.Now()
 }
 
+func (m mockOracle) GetLastPricesTimestamp() time.Time {
+	return time.Now()
+}
+
 func (m mockOracle) GetPrices() sdk.DecCoins {
 	return mockPrices
 }
 
+func (m mockOracle) GetBlacklist() []types.BlacklistEntry {
+	return mockBlacklist
+}
+
+func (m mockOracle) GetProviderStatus() []types.ProviderStatus {
+	return mockProviderStatus
+}
+
+func (m mockOracle) GetConversions() types.Conversions {
+	return mockConversions
+}
+
+func (m mockOracle) GetExplanation() types.Explanation {
+	return mockExplanation
+}
+
+func (m mockOracle) GetVolumes() types.Volumes {
+	return mockVolumes
+}
+
+func (m mockOracle) GetUptime() types.Uptime {
+	return mockUptime
+}
+
+type mockDatasource struct{}
+
+func (mockDatasource) Targets() ([]string, error) {
+	return []string{"price:ATOM", "provider:binance:ATOMUSDT"}, nil
+}
+
+func (mockDatasource) Series(target string, from, to time.Time) ([]history.Point, error) {
+	return []history.Point{
+		{Time: time.UnixMilli(1700000000000), Value: 34.84},
+		{Time: time.UnixMilli(1700000030000), Value: 34.9},
+	}, nil
+}
+
+func (mockDatasource) Votes(from, to time.Time) ([]history.Vote, error) {
+	return []history.Vote{
+		{Time: time.UnixMilli(1700000000000), Height: 100, TxHash: "ABCD"},
+	}, nil
+}
+
+func (mockDatasource) ProviderContributions(
+	provider string,
+	from, to time.Time,
+) (map[string]history.Contributions, error) {
+	if provider != "binance" {
+		return map[string]history.Contributions{}, nil
+	}
+	return map[string]history.Contributions{
+		"ATOM": {Accepted: 100, Filtered: 5, Missing: 2},
+		"BTC":  {Accepted: 90, Stale: 10},
+	}, nil
+}
+
 type mockMetrics struct{}
 
 func (mockMetrics) Gather(format string) (telemetry.GatherResponse, error) {
 	return telemetry.GatherResponse{}, nil
 }
 
+type mockUpdates struct{}
+
+func (mockUpdates) Status() *update.Status {
+	return &update.Status{
+		Channel:     update.DefaultChannel,
+		Version:     "v0.9.0",
+		Recommended: "v0.9.1",
+		Outdated:    true,
+	}
+}
+
 type RouterTestSuite struct {
 	suite.Suite
 
@@ -57,10 +210,14 @@ func (rts *RouterTestSuite) SetupSuite() {
 		Server: config.Server{
 			AllowedOrigins: []string{},
 			VerboseCORS:    false,
+			SigningKey:     "secret",
 		},
 	}
 
-	r := v1.New(zerolog.Nop(), cfg, mockOracle{}, mockMetrics{})
+	r := v1.New(
+		zerolog.Nop(), cfg, mockOracle{}, mockMetrics{}, mockDatasource{},
+		mockUpdates{}, nil,
+	)
 	r.RegisterRoutes(mux, v1.APIPathPrefix)
 
 	rts.mux = mux
@@ -88,6 +245,12 @@ func (rts *RouterTestSuite) TestHealthz() {
 	var respBody map[string]interface{}
 	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
 	rts.Require().Equal(respBody["status"], v1.StatusAvailable)
+
+	var healthz v1.HealthZResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &healthz))
+	rts.Require().NotNil(healthz.Update)
+	rts.Require().True(healthz.Update.Outdated)
+	rts.Require().Equal("v0.9.1", healthz.Update.Recommended)
 }
 
 func (rts *RouterTestSuite) TestPrices() {
@@ -102,4 +265,277 @@ func (rts *RouterTestSuite) TestPrices() {
 	rts.Require().Equal(respBody.Prices["ATOM"], mockPrices.AmountOf("ATOM"))
 	rts.Require().Equal(respBody.Prices["UMEE"], mockPrices.AmountOf("UMEE"))
 	rts.Require().Equal(respBody.Prices["FOO"], sdk.Dec{})
+	rts.Require().Nil(respBody.Explain)
+
+	req, err = http.NewRequest("GET", "/api/v1/prices?explain=true", nil)
+	rts.Require().NoError(err)
+
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	respBody = v1.PricesResponse{}
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Len(respBody.Explain["ATOM"].Providers, 1)
+	rts.Require().Equal("binance", respBody.Explain["ATOM"].Providers[0].Provider)
+	rts.Require().Equal("deviating price", respBody.Explain["ATOM"].Excluded[0].Reason)
+}
+
+func (rts *RouterTestSuite) TestPricesQuery() {
+	req, err := http.NewRequest("GET", "/api/v1/prices?denoms=atom,FOO&metadata=true", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.PricesResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Len(respBody.Prices, 1)
+	rts.Require().Equal(mockPrices.AmountOf("ATOM"), respBody.Prices["ATOM"])
+	rts.Require().Equal(1, respBody.Total)
+	rts.Require().Equal(1, respBody.Metadata["ATOM"].Providers)
+
+	// pagination over the denoms sorted by name
+	req, err = http.NewRequest("GET", "/api/v1/prices?offset=1&limit=1", nil)
+	rts.Require().NoError(err)
+
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	respBody = v1.PricesResponse{}
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Len(respBody.Prices, 1)
+	rts.Require().Contains(respBody.Prices, "UMEE")
+	rts.Require().Equal(2, respBody.Total)
+
+	req, err = http.NewRequest("GET", "/api/v1/prices?format=csv&metadata=true", nil)
+	rts.Require().NoError(err)
+
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+	rts.Require().Equal("text/csv", response.Header().Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSpace(response.Body.String()), "\n")
+	rts.Require().Len(lines, 3)
+	rts.Require().Equal("denom,price,age,providers", lines[0])
+	rts.Require().True(strings.HasPrefix(lines[1], "ATOM,34.840000000000000000,"))
+	rts.Require().True(strings.HasSuffix(lines[2], ",0"))
+
+	req, err = http.NewRequest("GET", "/api/v1/prices?format=xml", nil)
+	rts.Require().NoError(err)
+
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusBadRequest, response.Code)
+}
+
+func (rts *RouterTestSuite) TestProviderStats() {
+	req, err := http.NewRequest("GET", "/api/v1/providers/binance/stats?period=168h", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.ProviderStatsResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal("binance", respBody.Provider)
+	rts.Require().Equal(7*24*time.Hour, respBody.To.Sub(respBody.From))
+	rts.Require().Equal(
+		history.Contributions{Accepted: 190, Filtered: 5, Stale: 10, Missing: 2},
+		respBody.Total,
+	)
+	rts.Require().Equal(int64(10), respBody.Denoms["BTC"].Stale)
+
+	req, err = http.NewRequest("GET", "/api/v1/providers/binance/stats?period=foo", nil)
+	rts.Require().NoError(err)
+
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusBadRequest, response.Code)
+}
+
+func (rts *RouterTestSuite) TestOpenAPI() {
+	req, err := http.NewRequest("GET", "/api/v1/openapi.json", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var document struct {
+		Paths      map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &document))
+
+	// every registered route is documented
+	err = rts.mux.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil || methods[0] == "OPTIONS" {
+			return nil
+		}
+		for _, method := range methods {
+			rts.Require().Contains(document.Paths[path], strings.ToLower(method), path)
+		}
+		return nil
+	})
+	rts.Require().NoError(err)
+
+	rts.Require().Contains(document.Components.Schemas, "v1_PricesResponse")
+	rts.Require().Contains(document.Components.Schemas, "types_ProviderStatus")
+}
+
+func (rts *RouterTestSuite) TestSignedPrices() {
+	req, err := http.NewRequest("GET", "/api/v1/signed_prices", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody collector.SignedPrices
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(respBody.Prices["ATOM"], mockPrices.AmountOf("ATOM"))
+	rts.Require().NoError(respBody.Verify([]byte("secret")))
+	rts.Require().Error(respBody.Verify([]byte("other")))
+}
+
+func (rts *RouterTestSuite) TestBlacklist() {
+	req, err := http.NewRequest("GET", "/api/v1/blacklist", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.BlacklistResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(mockBlacklist, respBody.Entries)
+}
+
+func (rts *RouterTestSuite) TestProviders() {
+	req, err := http.NewRequest("GET", "/api/v1/providers", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.ProvidersResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(mockProviderStatus, respBody.Providers)
+}
+
+func (rts *RouterTestSuite) TestConversions() {
+	req, err := http.NewRequest("GET", "/api/v1/conversions", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.ConversionsResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(mockConversions.Time, respBody.Time)
+	rts.Require().Len(respBody.Rates, 2)
+
+	req, err = http.NewRequest("GET", "/api/v1/conversions?denom=btc", nil)
+	rts.Require().NoError(err)
+
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	respBody = v1.ConversionsResponse{}
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Len(respBody.Rates, 1)
+	rts.Require().Equal("BTCUSDT", respBody.Rates[0].Symbol)
+	rts.Require().Equal(mockConversions.Rates[1].QuoteRate, respBody.Rates[0].QuoteRate)
+}
+
+func (rts *RouterTestSuite) TestVolumes() {
+	req, err := http.NewRequest("GET", "/api/v1/volumes", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.VolumesResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(mockVolumes.Time, respBody.Time)
+	rts.Require().Len(respBody.Volumes, 2)
+
+	req, err = http.NewRequest("GET", "/api/v1/volumes?denom=btc", nil)
+	rts.Require().NoError(err)
+
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	respBody = v1.VolumesResponse{}
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Len(respBody.Volumes, 1)
+	rts.Require().Equal(mockVolumes.Volumes[1].Volume, respBody.Volumes[0].Volume)
+}
+
+func (rts *RouterTestSuite) TestUptime() {
+	req, err := http.NewRequest("GET", "/api/v1/uptime", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.UptimeResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(mockUptime, types.Uptime(respBody))
+
+	req, err = http.NewRequest("GET", "/api/v1/uptime?provider=kraken", nil)
+	rts.Require().NoError(err)
+
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	respBody = v1.UptimeResponse{}
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Len(respBody.Providers, 1)
+	rts.Require().Nil(respBody.Providers[0].Day)
+	rts.Require().Equal(mockUptimeWeek, *respBody.Providers[0].Week)
+}
+
+func (rts *RouterTestSuite) TestGrafana() {
+	req, err := http.NewRequest("POST", "/api/v1/grafana/search", strings.NewReader(`{"target":""}`))
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var targets []string
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &targets))
+	rts.Require().Equal([]string{"price:ATOM", "provider:binance:ATOMUSDT"}, targets)
+
+	req, err = http.NewRequest("POST", "/api/v1/grafana/query", strings.NewReader(`{
+		"range": {"from": "2023-11-14T00:00:00Z", "to": "2023-11-15T00:00:00Z"},
+		"targets": [{"target": "price:ATOM"}],
+		"maxDataPoints": 1
+	}`))
+	rts.Require().NoError(err)
+
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var series []v1.GrafanaSeries
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &series))
+	rts.Require().Len(series, 1)
+	rts.Require().Equal([][2]float64{{34.84, 1700000000000}}, series[0].Datapoints)
+
+	req, err = http.NewRequest("POST", "/api/v1/grafana/annotations", strings.NewReader(`{
+		"range": {"from": "2023-11-14T00:00:00Z", "to": "2023-11-15T00:00:00Z"},
+		"annotation": {"name": "votes"}
+	}`))
+	rts.Require().NoError(err)
+
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var annotations []v1.GrafanaAnnotation
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &annotations))
+	rts.Require().Len(annotations, 1)
+	rts.Require().Equal("ABCD", annotations[0].Text)
+	rts.Require().Equal(int64(1700000000000), annotations[0].Time)
 }
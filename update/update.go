@@ -0,0 +1,324 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	"github.com/rs/zerolog"
+
+	"price-feeder/config"
+)
+
+const (
+	DefaultChannel = "stable"
+
+	defaultInterval = 24 * time.Hour
+	defaultTimeout  = 10 * time.Second
+)
+
+type (
+	// Manifest defines the published release manifest. Every release channel
+	// defines the recommended and minimum versions, which can be overridden
+	// per chain id.
+	Manifest struct {
+		Channels map[string]Channel `json:"channels"`
+	}
+
+	// Channel defines the versions of a release channel.
+	Channel struct {
+		Release
+		Chains map[string]Release `json:"chains"`
+	}
+
+	// Release defines the recommended and minimum versions.
+	Release struct {
+		Recommended string `json:"recommended"`
+		Minimum     string `json:"minimum"`
+		Notes       string `json:"notes"`
+	}
+
+	// Status defines the result of the last update check.
+	Status struct {
+		Time        time.Time `json:"time"`
+		Channel     string    `json:"channel"`
+		Version     string    `json:"version"`
+		Recommended string    `json:"recommended,omitempty"`
+		Minimum     string    `json:"minimum,omitempty"`
+		Notes       string    `json:"notes,omitempty"`
+		Outdated    bool      `json:"outdated"`
+		Unsupported bool      `json:"unsupported"`
+		Error       string    `json:"error,omitempty"`
+	}
+
+	// Checker periodically fetches the release manifest and warns if the
+	// running version is behind the version recommended for the chain.
+	Checker struct {
+		logger   zerolog.Logger
+		url      string
+		channel  string
+		chainID  string
+		version  string
+		interval time.Duration
+		client   *http.Client
+
+		mtx    sync.RWMutex
+		status *Status
+	}
+)
+
+// NewChecker returns a checker for the running version on the given chain.
+func NewChecker(
+	logger zerolog.Logger,
+	cfg config.UpdateCheck,
+	chainID string,
+	version string,
+) *Checker {
+	parse := func(value string, fallback time.Duration) time.Duration {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return fallback
+		}
+		return duration
+	}
+
+	channel := cfg.Channel
+	if channel == "" {
+		channel = DefaultChannel
+	}
+
+	return &Checker{
+		logger:   logger.With().Str("module", "update").Logger(),
+		url:      cfg.URL,
+		channel:  channel,
+		chainID:  chainID,
+		version:  version,
+		interval: parse(cfg.Interval, defaultInterval),
+		client:   &http.Client{Timeout: parse(cfg.Timeout, defaultTimeout)},
+	}
+}
+
+// Start checks the manifest on startup and then once per interval, until
+// the context is cancelled.
+func (c *Checker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.check(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-ticker.C:
+			c.check(ctx)
+		}
+	}
+}
+
+// Status returns the result of the last check or nil, if there was none
+// yet.
+func (c *Checker) Status() *Status {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if c.status == nil {
+		return nil
+	}
+
+	status := *c.status
+	return &status
+}
+
+func (c *Checker) check(ctx context.Context) {
+	status := Status{
+		Time:    time.Now().UTC(),
+		Channel: c.channel,
+		Version: c.version,
+	}
+
+	manifest, err := c.fetch(ctx)
+	if err == nil {
+		err = status.apply(manifest, c.chainID)
+	}
+
+	if err != nil {
+		status.Error = err.Error()
+		c.logger.Debug().Err(err).Msg("failed to check for updates")
+	} else {
+		c.report(status)
+	}
+
+	c.mtx.Lock()
+	c.status = &status
+	c.mtx.Unlock()
+}
+
+func (c *Checker) report(status Status) {
+	var behind float32
+	if status.Outdated {
+		behind = 1
+	}
+	telemetry.SetGauge(behind, "update", "outdated")
+
+	switch {
+	case status.Unsupported:
+		c.logger.Error().
+			Str("version", status.Version).
+			Str("minimum", status.Minimum).
+			Str("recommended", status.Recommended).
+			Str("notes", status.Notes).
+			Msg("running version is below the minimum version, upgrade now")
+
+	case status.Outdated:
+		c.logger.Warn().
+			Str("version", status.Version).
+			Str("recommended", status.Recommended).
+			Str("notes", status.Notes).
+			Msg("running version is behind the recommended version")
+
+	default:
+		c.logger.Debug().
+			Str("version", status.Version).
+			Msg("running version is up to date")
+	}
+}
+
+func (c *Checker) fetch(ctx context.Context) (Manifest, error) {
+	var manifest Manifest
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return manifest, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return manifest, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return manifest, fmt.Errorf("manifest returned status %d", resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&manifest)
+	return manifest, err
+}
+
+// apply sets the versions of the channel and chain and compares them with
+// the running version.
+func (s *Status) apply(manifest Manifest, chainID string) error {
+	channel, ok := manifest.Channels[s.Channel]
+	if !ok {
+		return fmt.Errorf("release channel %s not found", s.Channel)
+	}
+
+	release := channel.Release
+	if override, ok := channel.Chains[chainID]; ok {
+		if override.Recommended != "" {
+			release.Recommended = override.Recommended
+		}
+		if override.Minimum != "" {
+			release.Minimum = override.Minimum
+		}
+		if override.Notes != "" {
+			release.Notes = override.Notes
+		}
+	}
+
+	s.Recommended = release.Recommended
+	s.Minimum = release.Minimum
+	s.Notes = release.Notes
+
+	if s.Version == "" {
+		// development builds have no version to compare
+		return nil
+	}
+
+	if s.Recommended != "" {
+		cmp, err := Compare(s.Version, s.Recommended)
+		if err != nil {
+			return err
+		}
+		s.Outdated = cmp < 0
+	}
+
+	if s.Minimum != "" {
+		cmp, err := Compare(s.Version, s.Minimum)
+		if err != nil {
+			return err
+		}
+		s.Unsupported = cmp < 0
+		s.Outdated = s.Outdated || s.Unsupported
+	}
+
+	return nil
+}
+
+// Compare compares two semantic versions and returns -1, 0 or 1. The
+// leading "v" is optional, pre-releases are lower than their release.
+func Compare(a, b string) (int, error) {
+	coreA, preA, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	coreB, preB, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range coreA {
+		if coreA[i] < coreB[i] {
+			return -1, nil
+		}
+		if coreA[i] > coreB[i] {
+			return 1, nil
+		}
+	}
+
+	switch {
+	case preA == preB:
+		return 0, nil
+	case preA == "":
+		return 1, nil
+	case preB == "":
+		return -1, nil
+	case preA < preB:
+		return -1, nil
+	default:
+		return 1, nil
+	}
+}
+
+func parseVersion(version string) ([3]int, string, error) {
+	var core [3]int
+
+	value := strings.TrimPrefix(strings.TrimSpace(version), "v")
+
+	// build metadata is ignored
+	value, _, _ = strings.Cut(value, "+")
+	value, pre, _ := strings.Cut(value, "-")
+
+	parts := strings.Split(value, ".")
+	if len(parts) > 3 {
+		return core, "", fmt.Errorf("invalid version: %s", version)
+	}
+
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return core, "", fmt.Errorf("invalid version: %s", version)
+		}
+		core[i] = number
+	}
+
+	return core, pre, nil
+}
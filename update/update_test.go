@@ -0,0 +1,109 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"price-feeder/config"
+)
+
+const testManifest = `{
+  "channels": {
+    "stable": {
+      "recommended": "v0.9.2",
+      "minimum": "v0.8.0",
+      "chains": {
+        "kaiyo-1": {"minimum": "v0.9.0", "notes": "required for the v0.9 upgrade"}
+      }
+    },
+    "beta": {"recommended": "v1.0.0-rc.1"}
+  }
+}`
+
+func TestCompare(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     string
+		expected int
+	}{
+		{"v0.9.1", "v0.9.1", 0},
+		{"0.9.1", "v0.9.1", 0},
+		{"v0.9.1", "v0.9.2", -1},
+		{"v0.10.0", "v0.9.2", 1},
+		{"v1", "v0.99.99", 1},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"v1.0.0-rc.2", "v1.0.0-rc.1", 1},
+		{"v1.0.0+abc", "v1.0.0", 0},
+	} {
+		cmp, err := Compare(tc.a, tc.b)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, cmp, "%s <> %s", tc.a, tc.b)
+	}
+
+	_, err := Compare("v1.x", "v1.0.0")
+	require.Error(t, err)
+}
+
+func TestCheckerCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testManifest))
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		name        string
+		channel     string
+		chainID     string
+		version     string
+		recommended string
+		minimum     string
+		outdated    bool
+		unsupported bool
+	}{
+		{"up to date", "", "harpoon-4", "v0.9.2", "v0.9.2", "v0.8.0", false, false},
+		{"outdated", "stable", "harpoon-4", "v0.8.5", "v0.9.2", "v0.8.0", true, false},
+		{"chain minimum", "stable", "kaiyo-1", "v0.8.5", "v0.9.2", "v0.9.0", true, true},
+		{"development build", "stable", "kaiyo-1", "", "v0.9.2", "v0.9.0", false, false},
+		{"beta", "beta", "kaiyo-1", "v0.9.2", "v1.0.0-rc.1", "", true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := NewChecker(zerolog.Nop(), config.UpdateCheck{
+				Enabled: true,
+				URL:     server.URL,
+				Channel: tc.channel,
+			}, tc.chainID, tc.version)
+			require.Nil(t, checker.Status())
+
+			checker.check(context.Background())
+
+			status := checker.Status()
+			require.NotNil(t, status)
+			require.Empty(t, status.Error)
+			require.Equal(t, tc.recommended, status.Recommended)
+			require.Equal(t, tc.minimum, status.Minimum)
+			require.Equal(t, tc.outdated, status.Outdated)
+			require.Equal(t, tc.unsupported, status.Unsupported)
+		})
+	}
+}
+
+func TestCheckerCheckError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	checker := NewChecker(zerolog.Nop(), config.UpdateCheck{
+		Enabled: true,
+		URL:     server.URL,
+		Channel: "nightly",
+	}, "kaiyo-1", "v0.9.0")
+
+	checker.check(context.Background())
+	require.Contains(t, checker.Status().Error, "status 404")
+	require.False(t, checker.Status().Outdated)
+}